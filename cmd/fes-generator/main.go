@@ -2,17 +2,24 @@
 package main
 
 import (
-	"encoding/csv"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"strconv"
+	"runtime"
 	"strings"
+	"sync"
+	"syscall"
 
 	"github.com/fhs/go-netcdf/netcdf"
+
+	csvstore "go.ngs.io/tides-api/internal/adapter/store/csv"
 )
 
 // ConstituentData holds amplitude and phase for a constituent.
@@ -31,6 +38,49 @@ type RegionalGrid struct {
 	Resolution float64 // Degrees.
 }
 
+// Output layouts.
+const (
+	layoutSplit    = "split"    // {name}_amplitude.nc and {name}_phase.nc on the region's axes.
+	layoutCombined = "combined" // ocean_tide/{name}.nc with hRe/hIm in cm on a 0–360° axis, like FES.
+)
+
+// dims returns the number of latitude and longitude grid points.
+func (g RegionalGrid) dims() (nLat, nLon int) {
+	return int((g.LatMax-g.LatMin)/g.Resolution) + 1, int((g.LonMax-g.LonMin)/g.Resolution) + 1
+}
+
+// lonAxis returns the longitudes of a layout. The combined layout uses a
+// 0–360° axis; global grids cover [0, 360) and regional grids must not
+// cross the prime meridian.
+func (g RegionalGrid) lonAxis(layout string) ([]float64, error) {
+	_, nLon := g.dims()
+	start := g.LonMin
+	if layout == layoutCombined {
+		if g.LonMax-g.LonMin >= 360 {
+			start, nLon = 0, int(360/g.Resolution)
+		} else {
+			start = math.Mod(g.LonMin, 360)
+			if start < 0 {
+				start += 360
+			}
+			if end := start + g.LonMax - g.LonMin; end > 360 {
+				return nil, fmt.Errorf("region %.1f°-%.1f°E crosses 0° and cannot use a 0–360° axis", g.LonMin, g.LonMax)
+			}
+		}
+	}
+	lon := make([]float64, nLon)
+	for i := range lon {
+		lon[i] = start + float64(i)*g.Resolution
+	}
+	return lon, nil
+}
+
+// writeMu serializes NetCDF writes: netCDF-C is not thread-safe, so grids
+// are computed in parallel but written one file at a time.
+//
+//nolint:gochecknoglobals // Intentional: guards the process-wide netCDF-C library.
+var writeMu sync.Mutex
+
 func main() {
 	// Command line flags.
 	csvPath := flag.String("csv", "./data/mock_tokyo_constituents.csv", "Path to CSV file with constituent data")
@@ -43,6 +93,10 @@ func main() {
 	resolution := flag.Float64("resolution", 0.1, "Grid resolution in degrees")
 	tokyoLat := flag.Float64("tokyo-lat", 35.6762, "Tokyo latitude (reference point)")
 	tokyoLon := flag.Float64("tokyo-lon", 139.6503, "Tokyo longitude (reference point)")
+	only := flag.String("constituents", "", "Comma-separated constituents to generate (default all in the CSV)")
+	workers := flag.Int("workers", runtime.NumCPU(), "Number of constituents generated in parallel")
+	showProgress := flag.Bool("progress", true, "Show a progress bar when stderr is a terminal")
+	layout := flag.String("layout", layoutSplit, "Output layout: split ({name}_amplitude.nc/{name}_phase.nc) or combined (ocean_tide/{name}.nc with hRe/hIm, as distributed by FES)")
 
 	flag.Parse()
 
@@ -82,6 +136,21 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to read CSV: %v", err)
 	}
+	if *only != "" {
+		constituents, err = selectConstituents(constituents, *only)
+		if err != nil {
+			log.Fatalf("Invalid -constituents: %v", err)
+		}
+	}
+	if *layout != layoutSplit && *layout != layoutCombined {
+		log.Fatalf("Unknown layout: %s (use %s or %s)", *layout, layoutSplit, layoutCombined)
+	}
+	if _, err := grid.lonAxis(*layout); err != nil {
+		log.Fatalf("Invalid region for %s layout: %v", *layout, err)
+	}
+	if *workers < 1 {
+		log.Fatalf("Invalid -workers: %d (must be at least 1)", *workers)
+	}
 
 	log.Printf("Loaded %d constituents from %s", len(constituents), *csvPath)
 	log.Printf("Generating FES NetCDF files for region: %s", *region)
@@ -94,32 +163,106 @@ func main() {
 		log.Fatalf("Failed to create output directory: %v", err)
 	}
 
-	// Generate NetCDF files for each constituent.
-	for _, constituent := range constituents {
-		if err := generateNetCDF(constituent, grid, *tokyoLat, *tokyoLon, *outDir); err != nil {
-			log.Printf("Warning: Failed to generate NetCDF for %s: %v", constituent.Name, err)
-			continue
-		}
-		log.Printf("✓ Generated %s_amplitude.nc and %s_phase.nc",
-			strings.ToLower(constituent.Name), strings.ToLower(constituent.Name))
+	// Cancel on SIGINT/SIGTERM; unfinished files are removed.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := generateAll(ctx, constituents, grid, *tokyoLat, *tokyoLon, *outDir, *layout, *workers, *showProgress && isTerminal(os.Stderr)); err != nil {
+		stop()
+		log.Fatalf("Generation canceled: %v", err)
 	}
 
 	// Print summary.
 	log.Printf("\n=== Generation Complete ===")
 	log.Printf("Files created in: %s", *outDir)
-	log.Printf("Grid size: %d × %d points",
-		int((grid.LatMax-grid.LatMin)/grid.Resolution)+1,
-		int((grid.LonMax-grid.LonMin)/grid.Resolution)+1)
+	nLat, nLon := grid.dims()
+	log.Printf("Grid size: %d × %d points", nLat, nLon)
 
 	// Estimate file sizes.
-	nLat := int((grid.LatMax-grid.LatMin)/grid.Resolution) + 1
-	nLon := int((grid.LonMax-grid.LonMin)/grid.Resolution) + 1
 	bytesPerFile := nLat * nLon * 8 // 8 bytes per float64.
 	totalMB := float64(bytesPerFile*len(constituents)*2) / 1024 / 1024
-	log.Printf("Total size: ~%.1f MB (%d constituents × 2 files)", totalMB, len(constituents))
+	log.Printf("Total size: ~%.1f MB (%d constituents × 2 grids)", totalMB, len(constituents))
+}
+
+// generateAll generates constituents on a pool of workers. Per-constituent
+// failures are logged and skipped; it returns an error only when ctx is
+// canceled.
+func generateAll(ctx context.Context, constituents []ConstituentData, grid RegionalGrid, tokyoLat, tokyoLon float64, outDir, layout string, workers int, showProgress bool) error {
+	var out io.Writer
+	if showProgress {
+		out = os.Stderr
+	}
+	nLat, _ := grid.dims()
+	bar := newProgress(out, len(constituents)*nLat)
+	jobs := make(chan ConstituentData)
+	var wg sync.WaitGroup
+	for range min(workers, len(constituents)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for constituent := range jobs {
+				err := generateNetCDF(ctx, constituent, grid, tokyoLat, tokyoLon, outDir, layout, bar)
+				switch {
+				case ctx.Err() != nil:
+				case err != nil:
+					log.Printf("Warning: Failed to generate NetCDF for %s: %v", constituent.Name, err)
+				case out == nil:
+					log.Printf("✓ Generated %s", strings.Join(outputNames(constituent.Name, layout), " and "))
+				}
+			}
+		}()
+	}
+feed:
+	for _, constituent := range constituents {
+		select {
+		case jobs <- constituent:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	bar.finish()
+	return ctx.Err()
+}
+
+// selectConstituents keeps the constituents named in a comma-separated list
+// (case-insensitive), failing on names missing from the CSV.
+func selectConstituents(constituents []ConstituentData, list string) ([]ConstituentData, error) {
+	want := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+			want[name] = true
+		}
+	}
+	selected := make([]ConstituentData, 0, len(want))
+	for _, c := range constituents {
+		if want[strings.ToUpper(c.Name)] {
+			selected = append(selected, c)
+			delete(want, strings.ToUpper(c.Name))
+		}
+	}
+	if len(want) > 0 {
+		missing := make([]string, 0, len(want))
+		for name := range want {
+			missing = append(missing, name)
+		}
+		return nil, fmt.Errorf("not in CSV: %s", strings.Join(missing, ", "))
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no constituents selected")
+	}
+	return selected, nil
+}
+
+// isTerminal reports whether f is a character device, so the progress bar
+// is not written into redirected logs.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
 
-// readConstituentCSV reads constituent data from CSV file.
+// readConstituentCSV reads constituent data from a station CSV file.
 func readConstituentCSV(path string) ([]ConstituentData, error) {
 	//nolint:gosec // G304: File path from command-line argument, user-controlled.
 	file, err := os.Open(path)
@@ -128,74 +271,59 @@ func readConstituentCSV(path string) ([]ConstituentData, error) {
 	}
 	defer func() { _ = file.Close() }()
 
-	reader := csv.NewReader(file)
-	reader.TrimLeadingSpace = true
-
-	// Read header.
-	header, err := reader.Read()
+	_, params, err := csvstore.ReadStation(file)
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate header.
-	if len(header) != 3 || header[0] != "constituent" ||
-		header[1] != "amplitude_m" || header[2] != "phase_deg" {
-		return nil, fmt.Errorf("invalid CSV header: %v", header)
-	}
-
-	// Read data.
-	constituents := make([]ConstituentData, 0, 10)
-	for {
-		record, err := reader.Read()
-		if err != nil {
-			break // EOF.
-		}
-
-		if len(record) != 3 {
-			continue
-		}
-
-		amplitude, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid amplitude for %s: %v", record[0], err)
-		}
-
-		phase, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
-		if err != nil {
-			return nil, fmt.Errorf("invalid phase for %s: %v", record[0], err)
-		}
-
+	constituents := make([]ConstituentData, 0, len(params))
+	for _, p := range params {
 		constituents = append(constituents, ConstituentData{
-			Name:      strings.TrimSpace(record[0]),
-			Amplitude: amplitude,
-			Phase:     phase,
+			Name:      p.Name,
+			Amplitude: p.AmplitudeM,
+			Phase:     p.PhaseDeg,
 		})
 	}
 
 	return constituents, nil
 }
 
-// generateNetCDF creates amplitude and phase NetCDF files for a constituent.
-func generateNetCDF(constituent ConstituentData, grid RegionalGrid, tokyoLat, tokyoLon float64, outDir string) error {
+// outputNames returns the files written for a constituent, relative to the
+// output directory.
+func outputNames(name, layout string) []string {
+	name = strings.ToLower(name)
+	if layout == layoutCombined {
+		return []string{filepath.Join("ocean_tide", name+".nc")}
+	}
+	return []string{name + "_amplitude.nc", name + "_phase.nc"}
+}
+
+// generateNetCDF creates the NetCDF files of a constituent in the given
+// layout. Files are written under a .partial suffix and renamed only when
+// all are complete, so a failed or canceled constituent leaves nothing behind.
+func generateNetCDF(ctx context.Context, constituent ConstituentData, grid RegionalGrid, tokyoLat, tokyoLon float64, outDir, layout string, bar *progress) error {
 	// Create lat/lon arrays.
-	nLat := int((grid.LatMax-grid.LatMin)/grid.Resolution) + 1
-	nLon := int((grid.LonMax-grid.LonMin)/grid.Resolution) + 1
+	nLat, _ := grid.dims()
 
 	lat := make([]float64, nLat)
 	for i := 0; i < nLat; i++ {
 		lat[i] = grid.LatMin + float64(i)*grid.Resolution
 	}
 
-	lon := make([]float64, nLon)
-	for i := 0; i < nLon; i++ {
-		lon[i] = grid.LonMin + float64(i)*grid.Resolution
+	lon, err := grid.lonAxis(layout)
+	if err != nil {
+		return err
 	}
+	nLon := len(lon)
 
 	// Generate amplitude and phase grids with spatial variation.
 	amplitude := make([]float64, nLat*nLon)
 	phase := make([]float64, nLat*nLon)
 
 	for i := 0; i < nLat; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		for j := 0; j < nLon; j++ {
 			idx := i*nLon + j
 
@@ -230,25 +358,71 @@ func generateNetCDF(constituent ConstituentData, grid RegionalGrid, tokyoLat, to
 				phase[idx] += 360.0
 			}
 		}
+		bar.add(1)
 	}
 
-	// Write amplitude file.
-	ampPath := filepath.Join(outDir, fmt.Sprintf("%s_amplitude.nc", strings.ToLower(constituent.Name)))
-	if err := writeNetCDF(ampPath, lat, lon, amplitude, nLat, nLon, "amplitude"); err != nil {
-		return err
+	// Variables of each output file, in outputNames order.
+	var files [][]gridVar
+	if layout == layoutCombined {
+		// Complex amplitude in centimeters, as in the FES distribution.
+		re := make([]float64, len(amplitude))
+		im := make([]float64, len(amplitude))
+		for idx := range amplitude {
+			g := phase[idx] * math.Pi / 180
+			re[idx] = amplitude[idx] * 100 * math.Cos(g)
+			im[idx] = amplitude[idx] * 100 * math.Sin(g)
+		}
+		files = [][]gridVar{{{"hRe", re}, {"hIm", im}}}
+	} else {
+		files = [][]gridVar{{{"amplitude", amplitude}}, {{"phase", phase}}}
 	}
 
-	// Write phase file.
-	phaPath := filepath.Join(outDir, fmt.Sprintf("%s_phase.nc", strings.ToLower(constituent.Name)))
-	if err := writeNetCDF(phaPath, lat, lon, phase, nLat, nLon, "phase"); err != nil {
-		return err
+	paths := outputNames(constituent.Name, layout)
+	for i, name := range paths {
+		paths[i] = filepath.Join(outDir, name)
 	}
+	defer func() {
+		for _, path := range paths {
+			_ = os.Remove(path + ".partial")
+		}
+	}()
 
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	for i, vars := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		//nolint:gosec // G301: Standard directory permissions for data output.
+		if err := os.MkdirAll(filepath.Dir(paths[i]), 0o755); err != nil {
+			return err
+		}
+		if err := writeNetCDF(paths[i]+".partial", lat, lon, vars...); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Rename(path+".partial", path); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// writeNetCDF writes a NetCDF file with the given data.
-func writeNetCDF(path string, lat, lon, data []float64, nLat, nLon int, varName string) error {
+// gridVar is a 2D (lat, lon) variable of an output file.
+type gridVar struct {
+	name string
+	data []float64
+}
+
+// writeNetCDF writes a NetCDF file with lat/lon coordinates and the given
+// variables.
+func writeNetCDF(path string, lat, lon []float64, vars ...gridVar) error {
+	nLat, nLon := len(lat), len(lon)
+
 	// Create NetCDF file.
 	ds, err := netcdf.CreateFile(path, netcdf.CLOBBER|netcdf.NETCDF4)
 	if err != nil {
@@ -287,13 +461,15 @@ func writeNetCDF(path string, lat, lon, data []float64, nLat, nLon int, varName
 		return fmt.Errorf("failed to write lon data: %w", err)
 	}
 
-	// Create data variable.
-	dataVar, err := ds.AddVar(varName, netcdf.DOUBLE, []netcdf.Dim{latDim, lonDim})
-	if err != nil {
-		return err
-	}
-	if err := dataVar.WriteFloat64s(data); err != nil {
-		return fmt.Errorf("failed to write data: %w", err)
+	// Create data variables.
+	for _, v := range vars {
+		dataVar, err := ds.AddVar(v.name, netcdf.DOUBLE, []netcdf.Dim{latDim, lonDim})
+		if err != nil {
+			return err
+		}
+		if err := dataVar.WriteFloat64s(v.data); err != nil {
+			return fmt.Errorf("failed to write %s data: %w", v.name, err)
+		}
 	}
 
 	// Note: Attributes are optional for basic functionality.
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const progressWidth = 30
+
+// progress renders a single-line progress bar of generated grid rows.
+type progress struct {
+	out   io.Writer
+	total int64
+	done  atomic.Int64
+	start time.Time
+	stop  chan struct{}
+	idle  chan struct{}
+}
+
+// newProgress starts redrawing the bar on out until finish is called. A nil
+// writer disables rendering but still counts.
+func newProgress(out io.Writer, total int) *progress {
+	p := &progress{
+		out:   out,
+		total: int64(total),
+		start: time.Now(),
+		stop:  make(chan struct{}),
+		idle:  make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *progress) add(n int) {
+	p.done.Add(int64(n))
+}
+
+func (p *progress) run() {
+	defer close(p.idle)
+	if p.out == nil {
+		<-p.stop
+		return
+	}
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.draw()
+		case <-p.stop:
+			p.draw()
+			_, _ = fmt.Fprintln(p.out)
+			return
+		}
+	}
+}
+
+func (p *progress) draw() {
+	done := min(p.done.Load(), p.total)
+	frac := 1.0
+	if p.total > 0 {
+		frac = float64(done) / float64(p.total)
+	}
+	filled := int(frac * progressWidth)
+	_, _ = fmt.Fprintf(p.out, "\r[%s%s] %5.1f%% %s",
+		strings.Repeat("=", filled), strings.Repeat(" ", progressWidth-filled),
+		frac*100, time.Since(p.start).Truncate(time.Second))
+}
+
+// finish draws the final state and stops rendering.
+func (p *progress) finish() {
+	close(p.stop)
+	<-p.idle
+}
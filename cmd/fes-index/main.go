@@ -0,0 +1,57 @@
+// Command fes-index scans a FES directory once and writes an index of its
+// constituents (files, variable names, axes, units and bounds). The server
+// loads the index at startup instead of scanning the directory and guessing
+// variable names.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"go.ngs.io/tides-api/internal/adapter/store/fes"
+)
+
+func main() {
+	var (
+		dir    string
+		output string
+	)
+	flag.StringVar(&dir, "dir", "./data/fes", "FES data directory")
+	flag.StringVar(&output, "o", "", "Index file (default: <dir>/"+fes.IndexFileName+")")
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: fes-index [-dir path] [-o path]")
+		os.Exit(2)
+	}
+	if output == "" {
+		output = filepath.Join(dir, fes.IndexFileName)
+	}
+
+	idx, err := fes.BuildIndex(dir)
+	if err != nil {
+		log.Fatalf("Failed to index %s: %v", dir, err)
+	}
+	for _, name := range idx.Names() {
+		e := idx.Constituents[name]
+		amp, pha := e.Amplitude, e.Phase
+		fmt.Printf("%-5s %s (%s) %s, %s (%s); lat %g..%g, lon %g..%g, %dx%d\n",
+			name, amp.Path, variable(amp.Variables), amp.Units, pha.Path, variable(pha.Variables),
+			amp.Lat.Min, amp.Lat.Max, amp.Lon.Min, amp.Lon.Max, amp.Lat.Size, amp.Lon.Size)
+	}
+	if err := idx.Write(output); err != nil {
+		log.Fatalf("Failed to write index: %v", err)
+	}
+	fmt.Printf("Indexed %d constituents in %s\n", len(idx.Constituents), output)
+}
+
+// variable names the data variable, or the real/imaginary pair.
+func variable(v fes.Variables) string {
+	if v.Data != "" {
+		return v.Data
+	}
+	return v.Real + "/" + v.Imag
+}
@@ -0,0 +1,215 @@
+// Command harmonics-convert converts the harmonic constants of a tide
+// station, as downloaded from NOAA CO-OPS (harcon.json) or listed in a CSV
+// file (e.g., IHO constituent tables), into a station override entry. The
+// entry is printed, or merged into an overrides file with -overrides.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.ngs.io/tides-api/internal/adapter/harcon"
+)
+
+type overrideEntry struct {
+	Name         string                       `json:"name"`
+	Station      string                       `json:"station,omitempty"`
+	Lat          float64                      `json:"lat"`
+	Lon          float64                      `json:"lon"`
+	RadiusKm     float64                      `json:"radius_km"`
+	DatumOffset  *float64                     `json:"datum_offset_m,omitempty"`
+	Constituents []harcon.OverrideConstituent `json:"constituents"`
+	Source       string                       `json:"source,omitempty"`
+}
+
+func main() {
+	var (
+		inPath        string
+		format        string
+		units         string
+		station       string
+		stationName   string
+		lat           float64
+		lon           float64
+		radiusKm      float64
+		datumOffset   float64
+		source        string
+		overridesPath string
+	)
+	flag.StringVar(&inPath, "in", "", "Path to the NOAA harcon.json or constituent CSV file")
+	flag.StringVar(&format, "format", "", "Input format: noaa or csv (default: from the file extension)")
+	flag.StringVar(&units, "units", "m", "CSV amplitude units: m, cm, mm or ft (NOAA files carry their own)")
+	flag.StringVar(&station, "station", "", "Station code")
+	flag.StringVar(&stationName, "name", "", "Station name (default: station code)")
+	flag.Float64Var(&lat, "lat", 0, "Station latitude")
+	flag.Float64Var(&lon, "lon", 0, "Station longitude")
+	flag.Float64Var(&radiusKm, "radius_km", 40, "Radius within which the override applies")
+	flag.Float64Var(&datumOffset, "datum_offset_m", 0, "Optional datum offset of the station (meters)")
+	flag.StringVar(&source, "source", "", "Source label (default: noaa-coops or iho)")
+	flag.StringVar(&overridesPath, "overrides", "", "Station overrides JSON to merge the entry into (default: print it)")
+	flag.Parse()
+
+	if inPath == "" || (station == "" && stationName == "") {
+		fmt.Fprintln(os.Stderr, "Usage: harmonics-convert -in harcon.json -station ID -lat LAT -lon LON [-format noaa|csv] [-units cm] [-overrides data/jma_station_overrides.json]")
+		os.Exit(2)
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 360 {
+		exitErr(fmt.Errorf("invalid station position %v, %v", lat, lon))
+	}
+	if radiusKm <= 0 {
+		exitErr(errors.New("radius_km must be positive"))
+	}
+	if format == "" {
+		format = "csv"
+		if strings.EqualFold(filepath.Ext(inPath), ".json") {
+			format = "noaa"
+		}
+	}
+
+	constants, err := readConstants(inPath, format, units)
+	if err != nil {
+		exitErr(err)
+	}
+	converted, skipped := harcon.Convert(constants, lon)
+	for _, name := range skipped {
+		fmt.Fprintf(os.Stderr, "warning: skipped constituent %s (unsupported, duplicate or zero amplitude)\n", name)
+	}
+	if len(converted) == 0 {
+		exitErr(fmt.Errorf("no supported constituents in %s", inPath))
+	}
+
+	entry := overrideEntry{
+		Name:         stationName,
+		Station:      station,
+		Lat:          lat,
+		Lon:          lon,
+		RadiusKm:     radiusKm,
+		Constituents: converted,
+		Source:       source,
+	}
+	if entry.Name == "" {
+		entry.Name = station
+	}
+	if entry.Source == "" {
+		entry.Source = map[string]string{"noaa": "noaa-coops", "csv": "iho"}[format]
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "datum_offset_m" {
+			entry.DatumOffset = &datumOffset
+		}
+	})
+
+	if overridesPath == "" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(entry); err != nil {
+			exitErr(err)
+		}
+		return
+	}
+	if err := mergeOverride(overridesPath, entry); err != nil {
+		exitErr(err)
+	}
+	fmt.Printf("Saved %d constituents for station %s in %s\n", len(converted), stationKey(entry), overridesPath)
+}
+
+func readConstants(path, format, units string) ([]harcon.Constant, error) {
+	//nolint:gosec // G304: Input path provided by operator (CLI flag).
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	switch format {
+	case "noaa":
+		return harcon.ReadNOAA(f)
+	case "csv":
+		return harcon.ReadCSV(f, units)
+	default:
+		return nil, fmt.Errorf("unsupported format %q (expected noaa or csv)", format)
+	}
+}
+
+// mergeOverride replaces the entry of the same station in the overrides
+// file, or adds it, keeping entries sorted by station key. Entries are kept
+// as raw JSON, so fields this command does not know are preserved.
+func mergeOverride(path string, entry overrideEntry) error {
+	var entries []json.RawMessage
+	//nolint:gosec // G304: Overrides path provided by operator (CLI flag).
+	b, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	keys := make(map[int]string, len(entries)+1)
+	replaced := false
+	for i, e := range entries {
+		var existing overrideEntry
+		if err := json.Unmarshal(e, &existing); err != nil {
+			return fmt.Errorf("invalid %s: %w", path, err)
+		}
+		keys[i] = stationKey(existing)
+		if keys[i] == stationKey(entry) {
+			entries[i] = raw
+			replaced = true
+		}
+	}
+	if !replaced {
+		keys[len(entries)] = stationKey(entry)
+		entries = append(entries, raw)
+	}
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+	sorted := make([]json.RawMessage, len(entries))
+	for i, idx := range order {
+		sorted[i] = entries[idx]
+	}
+	return writeJSON(path, sorted)
+}
+
+func stationKey(o overrideEntry) string {
+	if o.Station != "" {
+		return o.Station
+	}
+	return o.Name
+}
+
+func writeJSON(path string, data any) error {
+	//nolint:gosec // G301: Standard directory permissions for data output.
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	//nolint:gosec // G304: File path from function parameter, controlled by caller.
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+func exitErr(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,66 @@
+// Command jma-archive ingests JMA fixed-width hourly tide text into the
+// observation archive so past windows can be queried without re-downloading.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.ngs.io/tides-api/internal/adapter/store/archive"
+	"go.ngs.io/tides-api/internal/jma"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func main() {
+	var (
+		jmaPath    string
+		station    string
+		archiveDir string
+	)
+	flag.StringVar(&jmaPath, "jma_file", "", "Path or URL to JMA TXT (fixed-width)")
+	flag.StringVar(&station, "station", "", "JMA station code (e.g., KZ)")
+	flag.StringVar(&archiveDir, "archive_dir", "./data/archive", "Observation archive directory")
+	flag.Parse()
+
+	if jmaPath == "" || station == "" {
+		fmt.Fprintln(os.Stderr, "Usage: jma-archive -jma_file <path|url> -station KZ [-archive_dir ./data/archive]")
+		os.Exit(2)
+	}
+
+	records, err := jma.LoadStationRecordsFromPath(jmaPath, station)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load JMA: %v\n", err)
+		os.Exit(1)
+	}
+
+	obs := jma.Observations(records)
+	levels := make([]domain.TideLevel, len(obs))
+	for i, o := range obs {
+		levels[i] = domain.TideLevel{Time: o.Time, HeightM: o.HeightM}
+	}
+
+	store := archive.NewStore(archiveDir)
+	added, err := store.Append(station, levels)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to archive: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Parsed observations: %d\n", len(levels))
+	fmt.Printf("Newly archived: %d\n", added)
+
+	// Report hours missing from the parsed span (JMA marks them 999).
+	if len(levels) > 0 {
+		gaps := domain.FindGaps(levels, levels[0].Time, levels[len(levels)-1].Time, time.Hour)
+		missing := 0
+		for _, g := range gaps {
+			missing += g.Missing
+		}
+		fmt.Printf("Missing hours: %d in %d gaps\n", missing, len(gaps))
+		for _, g := range gaps {
+			fmt.Printf("  %s .. %s (%d h)\n", g.Start.Format(time.RFC3339), g.End.Format(time.RFC3339), g.Missing)
+		}
+	}
+}
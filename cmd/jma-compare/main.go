@@ -11,6 +11,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -50,15 +51,57 @@ func fetch(url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-// findTargetRecord extracts hourly heights for the specified JST date.
+// parseUTCOffset parses a station offset such as "+09:00" or "-05:30".
+func parseUTCOffset(s string) (*time.Location, error) {
+	t, err := time.Parse("-07:00", s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid utc_offset %q (expected ±HH:MM): %v", s, err)
+	}
+	_, offset := t.Zone()
+	if offset == 9*60*60 {
+		return jma.JSTLocation, nil
+	}
+	return time.FixedZone(s, offset), nil
+}
+
+// dayWindowUTC returns the UTC instants of local 00:00 and 24:00 for dateStr at loc.
+func dayWindowUTC(dateStr string, loc *time.Location) (start, end time.Time, err error) {
+	day, err := time.ParseInLocation("2006-01-02", dateStr, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date format: %v", err)
+	}
+	return day.UTC(), day.Add(24 * time.Hour).UTC(), nil
+}
+
+// withWindow adds start/end (and a 1h interval) to apiURL unless already present.
+func withWindow(apiURL string, start, end time.Time) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid api_url: %v", err)
+	}
+	q := u.Query()
+	if q.Get("start") == "" && q.Get("end") == "" {
+		q.Set("start", start.Format(time.RFC3339))
+		q.Set("end", end.Format(time.RFC3339))
+	}
+	if q.Get("interval") == "" {
+		q.Set("interval", "1h")
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// findTargetRecord extracts hourly heights for the specified local date.
+// Records are matched by calendar date, since the hourly values are local
+// station hours regardless of the offset.
 func findTargetRecord(records []jma.HourlyRecord, dateStr string) ([]float64, error) {
 	target, err := time.Parse("2006-01-02", dateStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid date format: %v", err)
 	}
-	locDate := time.Date(target.Year(), target.Month(), target.Day(), 0, 0, 0, 0, jma.JSTLocation)
+	ty, tm, td := target.Date()
 	for _, rec := range records {
-		if rec.Time.Equal(locDate) {
+		if y, m, d := rec.Time.Date(); y == ty && m == tm && d == td {
 			hours := make([]float64, 24)
 			for i := 0; i < 24; i++ {
 				if rec.Valid[i] {
@@ -71,8 +114,9 @@ func findTargetRecord(records []jma.HourlyRecord, dateStr string) ([]float64, er
 	return nil, fmt.Errorf("JMA record not found for date %s", dateStr)
 }
 
-// fetchAPIData fetches and parses API data into a map.
-func fetchAPIData(apiURL string) (map[string]float64, error) {
+// fetchAPIData fetches and parses API data into a map keyed by Unix time,
+// so responses in any timezone (utc or jst) pair with the same instants.
+func fetchAPIData(apiURL string) (map[int64]float64, error) {
 	body, err := fetch(apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch API: %v", err)
@@ -82,26 +126,25 @@ func fetchAPIData(apiURL string) (map[string]float64, error) {
 		return nil, fmt.Errorf("invalid API JSON: %v", err)
 	}
 
-	apiMap := make(map[string]float64)
+	apiMap := make(map[int64]float64)
 	for _, p := range api.Predictions {
-		apiMap[p.Time] = p.HeightM
+		t, err := time.Parse(time.RFC3339, p.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API time %q: %v", p.Time, err)
+		}
+		apiMap[t.Unix()] = p.HeightM
 	}
 	return apiMap, nil
 }
 
-// compareData compares hourly JMA data with API predictions.
-func compareData(hourly []float64, apiMap map[string]float64, startUTC string) ([]float64, error) {
-	start, err := time.Parse(time.RFC3339, startUTC)
-	if err != nil {
-		return nil, fmt.Errorf("invalid start_utc: %v", err)
-	}
-
+// compareData compares hourly JMA data with API predictions starting at start.
+func compareData(hourly []float64, apiMap map[int64]float64, start time.Time) ([]float64, error) {
 	diffs := make([]float64, 0, 24)
 	for i := 0; i < 24; i++ {
-		t := start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
-		apiH, ok := apiMap[t]
+		t := start.Add(time.Duration(i) * time.Hour)
+		apiH, ok := apiMap[t.Unix()]
 		if !ok {
-			return nil, fmt.Errorf("API missing time: %s", t)
+			return nil, fmt.Errorf("API missing time: %s", t.Format(time.RFC3339))
 		}
 		diffs = append(diffs, hourly[i]-apiH)
 	}
@@ -127,25 +170,63 @@ func calculateStats(diffs []float64) (mean, rmse float64) {
 	return mean, rmse
 }
 
+// overrideTime returns the parsed RFC3339 value, or def when value is empty.
+func overrideTime(def time.Time, value, name string) (time.Time, error) {
+	if value == "" {
+		return def, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return t.UTC(), nil
+}
+
 func main() {
 	var (
-		jmaPath  string
-		station  string
-		dateStr  string
-		apiURL   string
-		startUTC string
-		endUTC   string
+		jmaPath   string
+		station   string
+		dateStr   string
+		apiURL    string
+		startUTC  string
+		endUTC    string
+		utcOffset string
 	)
 	flag.StringVar(&jmaPath, "jma_file", "", "Path or URL to JMA TXT (fixed-width)")
 	flag.StringVar(&station, "station", "KZ", "JMA station code (e.g., KZ)")
-	flag.StringVar(&dateStr, "date", "2025-10-27", "Target date in JST (YYYY-MM-DD)")
-	flag.StringVar(&apiURL, "api_url", "", "Full API URL to fetch predictions (must span the JST day; include params)")
-	flag.StringVar(&startUTC, "start_utc", "2025-10-26T15:00:00Z", "Start time in UTC matching JST 00:00")
-	flag.StringVar(&endUTC, "end_utc", "2025-10-27T15:00:00Z", "End time in UTC matching JST 24:00")
+	flag.StringVar(&dateStr, "date", "2025-10-27", "Target date in station local time (YYYY-MM-DD)")
+	flag.StringVar(&apiURL, "api_url", "", "API predictions URL (lat/lon or station params; start/end are added from -date when omitted)")
+	flag.StringVar(&utcOffset, "utc_offset", "+09:00", "Station time offset from UTC used for the JMA day (±HH:MM)")
+	flag.StringVar(&startUTC, "start_utc", "", "Override start time in UTC (default: local 00:00 of -date)")
+	flag.StringVar(&endUTC, "end_utc", "", "Override end time in UTC (default: local 24:00 of -date)")
 	flag.Parse()
 
 	if jmaPath == "" || apiURL == "" {
-		fmt.Fprintln(os.Stderr, "Usage: jma-compare -jma_file <path|url> -station KZ -date 2025-10-27 -api_url <url> [-start_utc ... -end_utc ...]")
+		fmt.Fprintln(os.Stderr, "Usage: jma-compare -jma_file <path|url> -station KZ -date 2025-10-27 -api_url <url> [-utc_offset +09:00]")
+		os.Exit(2)
+	}
+
+	// Resolve the UTC window of the local station day.
+	loc, err := parseUTCOffset(utcOffset)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	start, end, err := dayWindowUTC(dateStr, loc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	if start, err = overrideTime(start, startUTC, "start_utc"); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	if end, err = overrideTime(end, endUTC, "end_utc"); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	if apiURL, err = withWindow(apiURL, start, end); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(2)
 	}
 
@@ -171,7 +252,7 @@ func main() {
 	}
 
 	// Compare.
-	diffs, err := compareData(hourly, apiMap, startUTC)
+	diffs, err := compareData(hourly, apiMap, start)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -180,9 +261,9 @@ func main() {
 	// Calculate statistics.
 	mean, rmse := calculateStats(diffs)
 
+	fmt.Printf("Window (UTC): %s .. %s\n", start.Format(time.RFC3339), end.Format(time.RFC3339))
 	fmt.Printf("Paired points: %d\n", len(diffs))
 	fmt.Printf("Mean(JMA-API) [m]: %.3f\n", mean)
 	fmt.Printf("RMSE around mean [m]: %.3f\n", rmse)
 	fmt.Printf("\nRecommended datum_offset_m: %.3f\n", mean)
 }
-
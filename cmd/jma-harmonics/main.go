@@ -11,8 +11,8 @@ import (
 	"strings"
 	"time"
 
-	"go.ngs.io/tides-api/internal/domain"
 	"go.ngs.io/tides-api/internal/jma"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
 type sample struct {
@@ -37,6 +37,15 @@ type stationOverride struct {
 	Source       string                `json:"source"`
 }
 
+// fitStats describes how well the fitted constituents reproduce the samples.
+type fitStats struct {
+	Samples         int     `json:"samples"`
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	RMSEM           float64 `json:"rmse_m"`
+	MaxAbsResidualM float64 `json:"max_abs_residual_m"`
+}
+
 func main() {
 	var (
 		jmaPath     string
@@ -48,6 +57,7 @@ func main() {
 		minDateStr  string
 		maxDateStr  string
 		constCSV    string
+		statsPath   string
 	)
 
 	flag.StringVar(&jmaPath, "jma_file", "", "Path or URL to JMA TXT file")
@@ -58,7 +68,8 @@ func main() {
 	flag.Float64Var(&radiusKm, "radius_km", 40, "Radius in km within which to apply these overrides")
 	flag.StringVar(&minDateStr, "start_date", "", "Optional start date (YYYY-MM-DD, JST)")
 	flag.StringVar(&maxDateStr, "end_date", "", "Optional end date (YYYY-MM-DD, JST)")
-	flag.StringVar(&constCSV, "constituents", "M2,S2,N2,K2,K1,O1,P1,Q1,M4,MS4,MN4,M6,S4,Mf,Mm,Ssa,Sa", "Comma-separated constituent list")
+	flag.StringVar(&constCSV, "constituents", strings.Join(domain.FitConstituents, ","), "Comma-separated constituent list")
+	flag.StringVar(&statsPath, "fit_stats", "", "Optional path to write fit quality JSON (samples, RMSE)")
 	flag.Parse()
 
 	if jmaPath == "" || station == "" {
@@ -90,12 +101,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	intercept, overrides, err := fitHarmonics(samples, lon, constituents)
+	intercept, overrides, stats, err := fitHarmonics(samples, lon, constituents)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "fit failed: %v\n", err)
 		os.Exit(1)
 	}
 
+	if statsPath != "" {
+		if err := writeFitStats(statsPath, stats); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write fit stats: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	if stationName == "" {
 		stationName = station
 	}
@@ -171,137 +189,49 @@ func extractSamples(records []jma.HourlyRecord, minTime, maxTime time.Time) []sa
 }
 
 func parseConstituents(csv string) []string {
-	parts := strings.Split(csv, ",")
-	out := make([]string, 0, len(parts))
-	for _, p := range parts {
-		trimmed := strings.ToUpper(strings.TrimSpace(p))
-		if trimmed == "" {
-			continue
-		}
-		if _, ok := domain.GetConstituentSpeed(trimmed); ok {
-			out = append(out, trimmed)
-		}
+	known, unknown := domain.CanonicalizeConstituents(strings.Split(csv, ","))
+	if len(unknown) > 0 {
+		fmt.Fprintf(os.Stderr, "warning: skipping unknown constituents: %s\n", strings.Join(unknown, ","))
 	}
-	return out
+	return known
 }
 
-func fitHarmonics(samples []sample, lon float64, names []string) (float64, []overrideConstituent, error) {
-	speeds := make([]float64, len(names))
-	for i, name := range names {
-		speed, ok := domain.GetConstituentSpeed(name)
-		if !ok {
-			return 0, nil, fmt.Errorf("unknown constituent: %s", name)
-		}
-		speeds[i] = speed
-	}
-
-	nodal := domain.NewAstronomicalNodalCorrection()
-	ref := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
-	paramCount := 1 + len(names)*2
-
-	normal := make([][]float64, paramCount)
-	for i := range normal {
-		normal[i] = make([]float64, paramCount)
-	}
-	rhs := make([]float64, paramCount)
-
-	for _, s := range samples {
-		deltaHours := s.Time.Sub(ref).Hours()
-		features := make([]float64, paramCount)
-		features[0] = 1
-		idx := 1
-		for i, name := range names {
-			f, u := nodal.GetFactors(name, deltaHours)
-			thetaDeg := speeds[i]*deltaHours + lon + u
-			thetaRad := domain.Deg2Rad(thetaDeg)
-			cosTerm := f * math.Cos(thetaRad)
-			sinTerm := f * math.Sin(thetaRad)
-			features[idx] = cosTerm
-			features[idx+1] = sinTerm
-			idx += 2
-		}
-		for i := 0; i < paramCount; i++ {
-			rhs[i] += features[i] * s.Height
-			for j := 0; j <= i; j++ {
-				normal[i][j] += features[i] * features[j]
-			}
-		}
+func fitHarmonics(samples []sample, lon float64, names []string) (float64, []overrideConstituent, fitStats, error) {
+	var stats fitStats
+	levels := make([]domain.TideLevel, len(samples))
+	for i, s := range samples {
+		levels[i] = domain.TideLevel{Time: s.Time, HeightM: s.Height}
 	}
-
-	for i := 0; i < paramCount; i++ {
-		for j := 0; j < i; j++ {
-			normal[j][i] = normal[i][j]
-		}
-	}
-
-	coeffs, err := solveSPD(normal, rhs)
+	fit, err := domain.FitHarmonics(levels, lon, names)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, stats, err
 	}
 
-	intercept := coeffs[0]
-	overrides := make([]overrideConstituent, 0, len(names))
-	idx := 1
-	for _, name := range names {
-		c := coeffs[idx]
-		s := coeffs[idx+1]
-		amp := math.Hypot(c, s)
-		phase := math.Mod(domain.Rad2Deg(math.Atan2(s, c))+360, 360)
+	stats.Samples = fit.Samples
+	stats.Start = samples[0].Time.Format(time.RFC3339)
+	stats.End = samples[len(samples)-1].Time.Format(time.RFC3339)
+	stats.RMSEM = round(fit.RMSEM, 4)
+	stats.MaxAbsResidualM = round(fit.MaxAbsResidualM, 4)
+
+	overrides := make([]overrideConstituent, 0, len(fit.Constituents))
+	for _, c := range fit.Constituents {
 		overrides = append(overrides, overrideConstituent{
-			Name:       name,
-			AmplitudeM: round(amp, 6),
-			PhaseDeg:   round(phase, 6),
+			Name:       c.Name,
+			AmplitudeM: round(c.AmplitudeM, 6),
+			PhaseDeg:   round(c.PhaseDeg, 6),
 		})
-		idx += 2
 	}
 
-	return round(intercept, 6), overrides, nil
+	return round(fit.InterceptM, 6), overrides, stats, nil
 }
 
-// solveSPD solves a linear system Ax = b where A is a symmetric positive-definite matrix,
-// using Cholesky decomposition. The input matrix 'mat' must be square, symmetric, and positive-definite.
-// Returns the solution vector x, or an error if the matrix is not positive-definite.
-func solveSPD(mat [][]float64, rhs []float64) ([]float64, error) {
-	n := len(rhs)
-	L := make([][]float64, n)
-	for i := range L {
-		L[i] = make([]float64, n)
-	}
-	for i := 0; i < n; i++ {
-		for j := 0; j <= i; j++ {
-			sum := mat[i][j]
-			for k := 0; k < j; k++ {
-				sum -= L[i][k] * L[j][k]
-			}
-			if i == j {
-				if sum <= 0 {
-					return nil, fmt.Errorf("matrix not positive definite")
-				}
-				L[i][j] = math.Sqrt(sum)
-			} else {
-				L[i][j] = sum / L[j][j]
-			}
-		}
-	}
-
-	y := make([]float64, n)
-	for i := 0; i < n; i++ {
-		sum := rhs[i]
-		for k := 0; k < i; k++ {
-			sum -= L[i][k] * y[k]
-		}
-		y[i] = sum / L[i][i]
-	}
-
-	x := make([]float64, n)
-	for i := n - 1; i >= 0; i-- {
-		sum := y[i]
-		for k := i + 1; k < n; k++ {
-			sum -= L[k][i] * x[k]
-		}
-		x[i] = sum / L[i][i]
+func writeFitStats(path string, stats fitStats) error {
+	b, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
 	}
-	return x, nil
+	//nolint:gosec // G306: Fit stats are not sensitive.
+	return os.WriteFile(path, append(b, '\n'), 0o644)
 }
 
 func round(v float64, places int) float64 {
@@ -43,82 +43,154 @@ func main() {
 	stationsPath := flag.String("stations", "tmp/jma-stations.json", "Path to station metadata JSON (code/lat/lng)")
 	txtDir := flag.String("txt_dir", "tmp/jma_txt", "Directory containing {CODE}.txt files")
 	harmonicsBin := flag.String("harmonics_bin", "tmp/bin/jma-harmonics", "Path to jma-harmonics binary (built automatically if missing)")
-	overridesOut := flag.String("overrides_out", "data/jma_station_overrides.json", "Output JSON for station overrides")
-	datumOut := flag.String("datum_out", "data/jma_datum_offsets.json", "Output JSON for datum offsets")
+	overridesOut := flag.String("overrides_out", "data/jma_station_overrides.json", "Output JSON for station overrides (merged with existing entries)")
+	datumOut := flag.String("datum_out", "data/jma_datum_offsets.json", "Output JSON for datum offsets (merged with existing entries)")
 	radiusKm := flag.Float64("radius_km", 40, "Default radius_km when jma-harmonics output omits it")
+	reportPath := flag.String("report", "", "Optional path to write a JSON run report (per-station status, reasons, fit quality)")
+	statePath := flag.String("state", "tmp/jma-overrides-state.json", "Input digests of fitted stations, used to skip unchanged stations")
+	only := flag.String("only", "", "Comma-separated station codes to process (default all)")
+	exclude := flag.String("exclude", "", "Comma-separated station codes to leave untouched")
+	force := flag.Bool("force", false, "Refit stations even when their input is unchanged")
 	flag.Parse()
 
+	report := newRunReport(*reportPath)
+
 	stations, err := loadStations(*stationsPath)
 	if err != nil {
-		exitErr(err)
+		report.fail(err)
+	}
+	filter := newStationFilter(*only, *exclude)
+	selected := stations[:0]
+	for _, st := range stations {
+		if filter.allows(strings.TrimSpace(st.Code)) {
+			selected = append(selected, st)
+		}
 	}
+	stations = selected
 	if len(stations) == 0 {
-		exitErr(fmt.Errorf("no stations found in %s", *stationsPath))
+		report.fail(fmt.Errorf("no stations selected from %s", *stationsPath))
+	}
+	report.Total = len(stations)
+
+	state, err := loadState(*statePath)
+	if err != nil {
+		report.fail(err)
+	}
+	existingOverrides, err := loadExisting[overrideResult](*overridesOut)
+	if err != nil {
+		report.fail(err)
+	}
+	existingDatums, err := loadExisting[datumEntry](*datumOut)
+	if err != nil {
+		report.fail(err)
+	}
+	overrides := make(map[string]overrideResult, len(existingOverrides))
+	for _, o := range existingOverrides {
+		overrides[stationKey(o)] = o
+	}
+	datumOffsets := make(map[string]datumEntry, len(existingDatums))
+	for _, d := range existingDatums {
+		datumOffsets[d.Name] = d
 	}
 
 	if err := ensureHarmonicsBinary(*harmonicsBin); err != nil {
-		exitErr(fmt.Errorf("build jma-harmonics: %w", err))
+		report.fail(fmt.Errorf("build jma-harmonics: %w", err))
+	}
+
+	withFit := supportsFitStats(*harmonicsBin)
+	if !withFit {
+		fmt.Fprintf(os.Stderr, "warning: %s predates -fit_stats; rebuild it to report fit quality\n", *harmonicsBin)
 	}
 
 	txtDirAbs, err := filepath.Abs(*txtDir)
 	if err != nil {
-		exitErr(err)
+		report.fail(err)
 	}
 
-	overrides := make([]overrideResult, 0, len(stations))
-	datumOffsets := make([]datumEntry, 0, len(stations))
-
 	for idx, st := range stations {
 		code := strings.TrimSpace(st.Code)
 		if code == "" {
+			report.add(idx+1, len(stations), code, statusSkipped, "empty station code", nil)
 			continue
 		}
 		lat, err := parseCoordinate(st.Lat)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "[%d/%d] skip %s: invalid latitude (%v)\n", idx+1, len(stations), code, err)
+			report.add(idx+1, len(stations), code, statusSkipped, fmt.Sprintf("invalid latitude (%v)", err), nil)
 			continue
 		}
 		lon, err := parseCoordinate(st.Lng)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "[%d/%d] skip %s: invalid longitude (%v)\n", idx+1, len(stations), code, err)
+			report.add(idx+1, len(stations), code, statusSkipped, fmt.Sprintf("invalid longitude (%v)", err), nil)
 			continue
 		}
 		txtPath := filepath.Join(txtDirAbs, fmt.Sprintf("%s.txt", code))
-		if _, err := os.Stat(txtPath); err != nil {
-			fmt.Fprintf(os.Stderr, "[%d/%d] skip %s: %v\n", idx+1, len(stations), code, err)
+		digest, err := inputDigest(txtPath, lat, lon, *radiusKm)
+		if err != nil {
+			report.add(idx+1, len(stations), code, statusSkipped, err.Error(), nil)
 			continue
 		}
-		result, err := runHarmonics(*harmonicsBin, txtPath, code, lat, lon, *radiusKm)
+		if _, present := overrides[code]; present && !*force && state.Stations[code].InputDigest == digest {
+			report.add(idx+1, len(stations), code, statusUpToDate, "", nil)
+			continue
+		}
+		result, fit, err := runHarmonics(*harmonicsBin, txtPath, code, lat, lon, *radiusKm, withFit)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "[%d/%d] harmonics failed for %s: %v\n", idx+1, len(stations), code, err)
+			report.add(idx+1, len(stations), code, statusFailed, err.Error(), nil)
 			continue
 		}
-		overrides = append(overrides, result)
-		datumOffsets = append(datumOffsets, datumEntry{
+		overrides[code] = result
+		datumOffsets[result.Name] = datumEntry{
 			Name:    result.Name,
 			Lat:     result.Lat,
 			Lon:     result.Lon,
 			OffsetM: result.DatumOffset,
-		})
-		fmt.Printf("[%d/%d] processed %s\n", idx+1, len(stations), code)
+		}
+		state.record(code, digest)
+		report.add(idx+1, len(stations), code, statusProcessed, "", fit)
 	}
 
 	if len(overrides) == 0 {
-		exitErr(fmt.Errorf("no overrides produced"))
+		report.fail(fmt.Errorf("no overrides produced"))
+	}
+	if report.Processed == 0 {
+		fmt.Printf("No stations changed; %s is up to date\n", *overridesOut)
+		if err := report.write(); err != nil {
+			exitErr(err)
+		}
+		return
 	}
 
-	sort.Slice(overrides, func(i, j int) bool { return stationKey(overrides[i]) < stationKey(overrides[j]) })
-	sort.Slice(datumOffsets, func(i, j int) bool { return datumOffsets[i].Name < datumOffsets[j].Name })
+	mergedOverrides := make([]overrideResult, 0, len(overrides))
+	for _, o := range overrides {
+		mergedOverrides = append(mergedOverrides, o)
+	}
+	mergedDatums := make([]datumEntry, 0, len(datumOffsets))
+	for _, d := range datumOffsets {
+		mergedDatums = append(mergedDatums, d)
+	}
+	sort.Slice(mergedOverrides, func(i, j int) bool { return stationKey(mergedOverrides[i]) < stationKey(mergedOverrides[j]) })
+	sort.Slice(mergedDatums, func(i, j int) bool { return mergedDatums[i].Name < mergedDatums[j].Name })
 
-	if err := writeJSON(*overridesOut, overrides); err != nil {
-		exitErr(err)
+	if err := writeJSON(*overridesOut, mergedOverrides); err != nil {
+		report.fail(err)
 	}
-	if err := writeJSON(*datumOut, datumOffsets); err != nil {
-		exitErr(err)
+	if err := writeJSON(*datumOut, mergedDatums); err != nil {
+		report.fail(err)
 	}
+	if err := writeJSON(*statePath, state); err != nil {
+		report.fail(err)
+	}
+	report.OverridesOut, report.DatumOut = *overridesOut, *datumOut
 
-	fmt.Printf("Saved %d overrides -> %s\n", len(overrides), *overridesOut)
+	fmt.Printf("Saved %d overrides (%d refitted) -> %s\n", len(mergedOverrides), report.Processed, *overridesOut)
 	fmt.Printf("Saved datum offsets -> %s\n", *datumOut)
+
+	if err := report.write(); err != nil {
+		exitErr(err)
+	}
+	if *reportPath != "" {
+		fmt.Printf("Saved run report -> %s\n", *reportPath)
+	}
 }
 
 func loadStations(path string) ([]stationEntry, error) {
@@ -151,7 +223,16 @@ func ensureHarmonicsBinary(binPath string) error {
 	return cmd.Run()
 }
 
-func runHarmonics(binPath, txtPath, code string, lat, lon, radius float64) (overrideResult, error) {
+// supportsFitStats reports whether the jma-harmonics binary accepts -fit_stats.
+func supportsFitStats(binPath string) bool {
+	//nolint:gosec // G204: Known binary path.
+	out, _ := exec.CommandContext(context.Background(), binPath, "-h").CombinedOutput()
+	return strings.Contains(string(out), "-fit_stats")
+}
+
+// runHarmonics fits one station and returns its override and, when withFit
+// is set, its fit quality.
+func runHarmonics(binPath, txtPath, code string, lat, lon, radius float64, withFit bool) (overrideResult, *fitStats, error) {
 	args := []string{
 		binPath,
 		fmt.Sprintf("-jma_file=%s", txtPath),
@@ -161,19 +242,43 @@ func runHarmonics(binPath, txtPath, code string, lat, lon, radius float64) (over
 		fmt.Sprintf("-lon=%f", lon),
 		fmt.Sprintf("-radius_km=%f", radius),
 	}
+	var statsPath string
+	if withFit {
+		f, err := os.CreateTemp("", "jma-fit-*.json")
+		if err != nil {
+			return overrideResult{}, nil, err
+		}
+		_ = f.Close()
+		statsPath = f.Name()
+		defer func() { _ = os.Remove(statsPath) }()
+		args = append(args, fmt.Sprintf("-fit_stats=%s", statsPath))
+	}
 	//nolint:gosec // G204: args[0] is known binary path, args from controlled source.
 	cmd := exec.CommandContext(context.Background(), args[0], args[1:]...)
 	var stdout, stderr strings.Builder
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
-		return overrideResult{}, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		return overrideResult{}, nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
 	}
 	var result overrideResult
 	if err := json.Unmarshal([]byte(stdout.String()), &result); err != nil {
-		return overrideResult{}, err
+		return overrideResult{}, nil, err
+	}
+
+	if !withFit {
+		return result, nil, nil
+	}
+	//nolint:gosec // G304: Temporary file created above.
+	b, err := os.ReadFile(statsPath)
+	if err != nil {
+		return overrideResult{}, nil, fmt.Errorf("read fit stats: %w", err)
+	}
+	var fit fitStats
+	if err := json.Unmarshal(b, &fit); err != nil {
+		return overrideResult{}, nil, fmt.Errorf("invalid fit stats: %w", err)
 	}
-	return result, nil
+	return result, &fit, nil
 }
 
 func parseCoordinate(raw string) (float64, error) {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Station outcomes in the run report.
+const (
+	statusProcessed = "processed"
+	statusUpToDate  = "up_to_date"
+	statusSkipped   = "skipped"
+	statusFailed    = "failed"
+)
+
+// fitStats is the fit quality written by jma-harmonics -fit_stats.
+type fitStats struct {
+	Samples         int     `json:"samples"`
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	RMSEM           float64 `json:"rmse_m"`
+	MaxAbsResidualM float64 `json:"max_abs_residual_m"`
+}
+
+// stationReport is the outcome for one station.
+type stationReport struct {
+	Code   string    `json:"code"`
+	Status string    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+	Fit    *fitStats `json:"fit,omitempty"`
+}
+
+// runReport is the machine-readable run summary written with -report.
+type runReport struct {
+	StartedAt    string          `json:"started_at"`
+	FinishedAt   string          `json:"finished_at"`
+	Total        int             `json:"total"`
+	Processed    int             `json:"processed"`
+	UpToDate     int             `json:"up_to_date"`
+	Skipped      int             `json:"skipped"`
+	Failed       int             `json:"failed"`
+	OverridesOut string          `json:"overrides_out,omitempty"`
+	DatumOut     string          `json:"datum_out,omitempty"`
+	Error        string          `json:"error,omitempty"` // Run-level failure.
+	Stations     []stationReport `json:"stations"`
+
+	path string
+}
+
+func newRunReport(path string) *runReport {
+	return &runReport{
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+		Stations:  []stationReport{},
+		path:      path,
+	}
+}
+
+// add records a station outcome and prints it for humans: progress to
+// stdout, skips and failures to stderr.
+func (r *runReport) add(idx, total int, code, status, reason string, fit *fitStats) {
+	r.Stations = append(r.Stations, stationReport{Code: code, Status: status, Reason: reason, Fit: fit})
+	switch status {
+	case statusProcessed:
+		r.Processed++
+		fmt.Printf("[%d/%d] processed %s\n", idx, total, code)
+	case statusUpToDate:
+		r.UpToDate++
+		fmt.Printf("[%d/%d] up to date %s\n", idx, total, code)
+	case statusSkipped:
+		r.Skipped++
+		fmt.Fprintf(os.Stderr, "[%d/%d] skip %s: %s\n", idx, total, code, reason)
+	case statusFailed:
+		r.Failed++
+		fmt.Fprintf(os.Stderr, "[%d/%d] harmonics failed for %s: %s\n", idx, total, code, reason)
+	}
+}
+
+// write saves the report when -report is set.
+func (r *runReport) write() error {
+	if r.path == "" {
+		return nil
+	}
+	r.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := writeJSON(r.path, r); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+// fail records a run-level error, saves the report and exits.
+func (r *runReport) fail(err error) {
+	r.Error = err.Error()
+	if werr := r.write(); werr != nil {
+		fmt.Fprintln(os.Stderr, "error:", werr)
+	}
+	exitErr(err)
+}
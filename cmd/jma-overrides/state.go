@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// runState remembers the inputs each station was last fitted from, so
+// unchanged stations are skipped on the next run.
+type runState struct {
+	Stations map[string]stationState `json:"stations"`
+}
+
+type stationState struct {
+	InputDigest string `json:"input_digest"` // TXT contents and fit parameters.
+	FittedAt    string `json:"fitted_at"`
+}
+
+func loadState(path string) (*runState, error) {
+	s := &runState{Stations: map[string]stationState{}}
+	//nolint:gosec // G304: File path from command-line argument.
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", path, err)
+	}
+	if s.Stations == nil {
+		s.Stations = map[string]stationState{}
+	}
+	return s, nil
+}
+
+func (s *runState) record(code, digest string) {
+	s.Stations[code] = stationState{InputDigest: digest, FittedAt: time.Now().UTC().Format(time.RFC3339)}
+}
+
+// inputDigest hashes a station's TXT file with the parameters it is fitted with.
+func inputDigest(txtPath string, lat, lon, radius float64) (string, error) {
+	//nolint:gosec // G304: Station file under the -txt_dir directory.
+	f, err := os.Open(txtPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	_, _ = fmt.Fprintf(h, "|%f|%f|%f", lat, lon, radius)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// stationFilter selects stations by the -only and -exclude lists.
+type stationFilter struct {
+	only    map[string]bool
+	exclude map[string]bool
+}
+
+func newStationFilter(only, exclude string) stationFilter {
+	return stationFilter{only: codeSet(only), exclude: codeSet(exclude)}
+}
+
+func (f stationFilter) allows(code string) bool {
+	if len(f.only) > 0 && !f.only[code] {
+		return false
+	}
+	return !f.exclude[code]
+}
+
+func codeSet(list string) map[string]bool {
+	set := map[string]bool{}
+	for _, code := range strings.Split(list, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			set[code] = true
+		}
+	}
+	return set
+}
+
+// loadExisting reads a previous output file; a missing file is empty.
+func loadExisting[T any](path string) ([]T, error) {
+	//nolint:gosec // G304: File path from command-line argument.
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []T
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return out, nil
+}
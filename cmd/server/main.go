@@ -2,21 +2,56 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"go.ngs.io/tides-api/internal/adapter/geoid"
+	"go.ngs.io/tides-api/internal/adapter/interp"
+	"go.ngs.io/tides-api/internal/adapter/notify"
+	"go.ngs.io/tides-api/internal/adapter/observation"
+	"go.ngs.io/tides-api/internal/adapter/onnx"
+	"go.ngs.io/tides-api/internal/adapter/retry"
+	"go.ngs.io/tides-api/internal/adapter/shadow"
 	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/store/archive"
 	"go.ngs.io/tides-api/internal/adapter/store/bathymetry"
 	"go.ngs.io/tides-api/internal/adapter/store/csv"
 	"go.ngs.io/tides-api/internal/adapter/store/fes"
+	"go.ngs.io/tides-api/internal/adapter/store/geocache"
+	"go.ngs.io/tides-api/internal/adapter/store/sqlitecache"
+	"go.ngs.io/tides-api/internal/adapter/store/tpxo"
+	"go.ngs.io/tides-api/internal/adapter/vlm"
 	httpHandler "go.ngs.io/tides-api/internal/http"
+	"go.ngs.io/tides-api/internal/licensing"
+	"go.ngs.io/tides-api/internal/schema"
 	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
-const version = "0.1.0"
+// Build metadata, injected at build time with
+// -ldflags "-X main.version=... -X main.commit=... -X main.buildDate=...".
+//
+//nolint:gochecknoglobals // Intentional: set by the linker.
+var (
+	version   = "0.1.0"
+	commit    = "unknown"
+	buildDate = "unknown"
+)
 
 func main() {
 	// Parse command-line flags.
@@ -30,7 +65,7 @@ func main() {
 	}
 
 	if *showVersion {
-		fmt.Printf("tides-api version %s\n", version)
+		fmt.Printf("tides-api version %s (commit %s, built %s)\n", version, commit, buildDate)
 		return
 	}
 
@@ -38,22 +73,166 @@ func main() {
 	port := getEnv("PORT", "8080")
 	dataDir := getEnv("DATA_DIR", "./data")
 	fesDir := getEnv("FES_DIR", "./data/fes")
+	fesIndexPath := getEnv("FES_INDEX_PATH", "")
+	tpxoDir := getEnv("TPXO_DIR", "")
+	acceptedLicenses := getEnv(licensing.AcceptanceEnv, "")
+	fesCurrentsDir := getEnv("FES_CURRENTS_DIR", "")
+	defaultSource := getEnv("SOURCE", "fes")
 	gebcoPath := getEnv("BATHYMETRY_GEBCO_PATH", "")
 	mssPath := getEnv("BATHYMETRY_MSS_PATH", "")
 	geoidPath := getEnv("GEOID_EGM2008_PATH", "")
+	vlmPath := getEnv("VLM_PATH", "")
+	vlmEpoch := getEnv("VLM_REFERENCE_EPOCH", "2020-01-01")
+	slrScenariosPath := getEnv("SLR_SCENARIOS_PATH", "")
+	seismicEventsPath := getEnv("SEISMIC_EVENTS_PATH", "")
+	datumCachePath := getEnv("DATUM_CACHE_PATH", "")
+	datumOffsetsPath := getEnv("DATUM_OFFSETS_PATH", usecase.DefaultDatumOffsetsPath)
+	stationOverridesPath := getEnv("STATION_OVERRIDES_PATH", usecase.DefaultStationOverridesPath)
+	bathyTimeIndex, err := strconv.Atoi(getEnv("BATHYMETRY_TIME_INDEX", "0"))
+	if err != nil {
+		log.Fatalf("Invalid BATHYMETRY_TIME_INDEX: %v", err)
+	}
+	bathyVertical, err := bathymetry.ParseVerticalConvention(getEnv("BATHYMETRY_VERTICAL_CONVENTION", string(bathymetry.PositiveUp)))
+	if err != nil {
+		log.Fatalf("Invalid BATHYMETRY_VERTICAL_CONVENTION: %v", err)
+	}
+	minCells, maxCells := getEnv("GRID_SUBSET_MIN_CELLS", "4"), getEnv("GRID_SUBSET_MAX_CELLS", "480")
+	gebcoMargin, err := parseSubsetMargin(getEnv("BATHYMETRY_GEBCO_MARGIN_DEG", "2"), minCells, maxCells)
+	if err != nil {
+		log.Fatalf("Invalid BATHYMETRY_GEBCO_MARGIN_DEG or GRID_SUBSET_*_CELLS: %v", err)
+	}
+	mssMargin, err := parseSubsetMargin(getEnv("BATHYMETRY_MSS_MARGIN_DEG", "2"), minCells, maxCells)
+	if err != nil {
+		log.Fatalf("Invalid BATHYMETRY_MSS_MARGIN_DEG or GRID_SUBSET_*_CELLS: %v", err)
+	}
+	geoidMargin, err := parseSubsetMargin(getEnv("GEOID_EGM2008_MARGIN_DEG", "2"), minCells, maxCells)
+	if err != nil {
+		log.Fatalf("Invalid GEOID_EGM2008_MARGIN_DEG or GRID_SUBSET_*_CELLS: %v", err)
+	}
+	monitorStationsPath := getEnv("MONITOR_STATIONS_PATH", "")
+	observationTemplate := getEnv("OBSERVATION_URL_TEMPLATE", "")
+	alertWebhookURL := getEnv("ALERT_WEBHOOK_URL", "")
+	errorReportURL := getEnv("ERROR_REPORT_URL", "")
+	monitorInterval := getEnv("MONITOR_INTERVAL", "5m")
+	archiveDir := getEnv("ARCHIVE_DIR", "")
+	archiveInterval := getEnv("ARCHIVE_INTERVAL", "1h")
+	recalibrationInterval := getEnv("RECALIBRATION_INTERVAL", "")
+	recalibrationWindow := getEnv("RECALIBRATION_WINDOW", "8760h")
+	recalibrationStations := getEnv("RECALIBRATION_STATIONS", "")
+	shadowURL := getEnv("SHADOW_URL", "")
+	fixedNow := getEnv("FIXED_NOW", "")
+	shadowSampleRate := getEnv("SHADOW_SAMPLE_RATE", "0.01")
+	shadowToleranceM := getEnv("SHADOW_TOLERANCE_M", "0.001")
+	slowRequestThreshold := getEnv("SLOW_REQUEST_THRESHOLD", "")
+	requestLogSampleRate := getEnv("REQUEST_LOG_SAMPLE_RATE", "")
+	batchAPIKeys := getEnv("BATCH_API_KEYS", "")
+	batchMaxConcurrent := getEnv("BATCH_MAX_CONCURRENT", "2")
+	batchMaxQueued := getEnv("BATCH_MAX_QUEUED", "32")
+	batchQueueTimeout := getEnv("BATCH_QUEUE_TIMEOUT", "30s")
+	snapshotPath := getEnv("SNAPSHOT_PATH", "")
+	tenantsPath := getEnv("TENANTS_PATH", "")
+	apiDocs := getEnv("API_DOCS", "false") == "true"
+	metricsEnabled := getEnv("METRICS_ENABLED", "false") == "true"
+	analyticsExportPath := getEnv("ANALYTICS_EXPORT_PATH", "")
+	analyticsExportInterval := getEnv("ANALYTICS_EXPORT_INTERVAL", "1h")
+	fillPolicy, err := fes.ParseFillPolicy(getEnv("FES_FILL_POLICY", string(fes.FillNaN)))
+	if err != nil {
+		log.Fatalf("Invalid FES_FILL_POLICY: %v", err)
+	}
+	fillRadius, err := strconv.Atoi(getEnv("FES_FILL_RADIUS_CELLS", "4"))
+	if err != nil || fillRadius < 0 {
+		log.Fatalf("Invalid FES_FILL_RADIUS_CELLS: %q", getEnv("FES_FILL_RADIUS_CELLS", "4"))
+	}
+	if residualModelPath := getEnv("RESIDUAL_MODEL_PATH", ""); residualModelPath != "" {
+		residual, err := loadResidualModel(residualModelPath)
+		if err != nil {
+			log.Fatalf("Invalid RESIDUAL_MODEL_PATH: %v", err)
+		}
+		domain.RegisterPredictionModel(onnx.ResidualModelKey, residual)
+		log.Printf("Residual model: %s (%s)", residualModelPath, residual.Name())
+	}
+	predictionModelKey := getEnv("PREDICTION_MODEL", domain.HarmonicModelKey)
+	predictionModel, err := domain.LookupPredictionModel(predictionModelKey)
+	if err != nil {
+		log.Fatalf("Invalid PREDICTION_MODEL: %v", err)
+	}
+	fesConstituentsSetting := getEnv("FES_CONSTITUENTS", "default")
+	ensembleSetting := getEnv("FES_ENSEMBLE", "")
+	fesConstituents, err := fes.ParseConstituents(fesConstituentsSetting)
+	if err != nil {
+		log.Fatalf("Invalid FES_CONSTITUENTS: %v", err)
+	}
+	constituentCacheSize, err := strconv.Atoi(getEnv("CONSTITUENT_CACHE_SIZE", "10000"))
+	if err != nil {
+		log.Fatalf("Invalid CONSTITUENT_CACHE_SIZE: %v", err)
+	}
+	gridCellCacheMB, err := strconv.Atoi(getEnv("GRID_CELL_CACHE_MB", "64"))
+	if err != nil || gridCellCacheMB < 0 {
+		log.Fatalf("Invalid GRID_CELL_CACHE_MB: %q", getEnv("GRID_CELL_CACHE_MB", "64"))
+	}
+	cellCache := constituentCache{
+		size:           constituentCacheSize,
+		path:           getEnv("CONSTITUENT_CACHE_PATH", ""),
+		gridCellsBytes: int64(gridCellCacheMB) << 20,
+	}
+	retryPolicy, err := parseRetryPolicy(
+		getEnv("FILE_RETRY_ATTEMPTS", "3"),
+		getEnv("FILE_RETRY_DELAY", "100ms"),
+		getEnv("FILE_RETRY_JITTER", "0.5"),
+	)
+	if err != nil {
+		log.Fatalf("Invalid FILE_RETRY_* setting: %v", err)
+	}
+	logger, err := newLogger(getEnv("LOG_FORMAT", "text"))
+	if err != nil {
+		log.Fatalf("Invalid LOG_FORMAT: %v", err)
+	}
+	licenses, err := licensing.ParseAcceptance(acceptedLicenses)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", licensing.AcceptanceEnv, err)
+	}
 
 	log.Printf("Starting Tide API server...")
 	log.Printf("Port: %s", port)
 	log.Printf("Data directory: %s", dataDir)
 	log.Printf("FES directory: %s", fesDir)
+	if fillPolicy == fes.FillNearest {
+		log.Printf("FES fill policy: %s (within %d cells)", fillPolicy, fillRadius)
+	} else {
+		log.Printf("FES fill policy: %s", fillPolicy)
+	}
+	log.Printf("Prediction model: %s", predictionModel.Name())
+	if fesConstituents == nil {
+		log.Printf("FES constituents: all available")
+	} else {
+		log.Printf("FES constituents: %s", strings.Join(fesConstituents, ","))
+	}
+	log.Printf("File read retries: %d attempts, %s base delay, %.0f%% jitter", retryPolicy.Attempts, retryPolicy.BaseDelay, retryPolicy.Jitter*100)
+	retry.Default().SetPolicy(retryPolicy)
 
 	// Initialize stores.
 	csvStore := csv.NewConstituentStore(dataDir)
 	fesStore := fes.NewStore(fesDir)
+	fesStore.SetFillPolicy(fillPolicy)
+	fesStore.SetNearestWetRadius(fillRadius)
+	fesStore.SetConstituents(fesConstituents)
+	indexed, err := loadFESIndex(fesStore, fesDir, fesIndexPath)
+	if err != nil {
+		log.Fatalf("Invalid FES index: %v", err)
+	}
+	if indexed {
+		log.Printf("FES index: loaded (directory scan skipped)")
+	}
 
 	// Cast to interface.
 	var csvLoader store.ConstituentLoader = csvStore
-	var fesLoader store.ConstituentLoader = fesStore
+	fesLoader := cellCache.wrap(fesStore)
+	if constituentCacheSize > 0 {
+		log.Printf("Constituent cache: %d geohash-%d cells", constituentCacheSize, geocache.Precision)
+	}
+	if gridCellCacheMB > 0 {
+		log.Printf("Grid cell cache: %d MB", gridCellCacheMB)
+	}
 
 	// Initialize geoid store (optional, for MSL correction).
 	var geoidStore *geoid.Store
@@ -61,6 +240,7 @@ func main() {
 		log.Printf("Initializing EGM2008 geoid store")
 		log.Printf("  Geoid path: %s", geoidPath)
 		geoidStore = geoid.NewStore(geoidPath)
+		geoidStore.SetSubsetMargin(geoidMargin)
 		log.Printf("Geoid store initialized (will apply MSL correction)")
 	}
 
@@ -70,7 +250,7 @@ func main() {
 	if gebcoPath != "" || mssPath != "" {
 		log.Printf("Initializing bathymetry store")
 		if gebcoPath != "" {
-			log.Printf("  GEBCO path: %s", gebcoPath)
+			log.Printf("  GEBCO path: %s (%s)", gebcoPath, bathyVertical)
 		}
 		if mssPath != "" {
 			log.Printf("  MSS path: %s", mssPath)
@@ -78,17 +258,329 @@ func main() {
 		if geoidStore == nil && mssPath != "" {
 			log.Printf("  Warning: MSS data without geoid correction (results will be ellipsoidal)")
 		}
-		bathyStore = bathymetry.NewLocalStore(gebcoPath, mssPath, geoidStore)
+		localStore := bathymetry.NewLocalStore(gebcoPath, mssPath, geoidStore)
+		localStore.SetTimeIndex(bathyTimeIndex)
+		localStore.SetVerticalConvention(bathyVertical)
+		localStore.SetSubsetMargins(gebcoMargin, mssMargin)
+		log.Printf("  Grid subsets: ±%g° GEBCO, ±%g° MSS, %d-%d cells", gebcoMargin.Degrees, mssMargin.Degrees, gebcoMargin.MinCells, gebcoMargin.MaxCells)
+		bathyStore = localStore
 		log.Printf("Bathymetry store initialized")
 	} else {
 		log.Printf("Bathymetry store disabled (no data paths configured)")
 	}
 
+	// Validate station tables and nodal coefficients against their schemas.
+	if err := validateDataFiles(map[string]string{
+		schema.DatumOffsets:     datumOffsetsPath,
+		schema.StationOverrides: stationOverridesPath,
+		schema.AstroCoeffs:      domain.NodalCoeffsPath(),
+	}); err != nil {
+		log.Fatalf("Invalid data file: %v", err)
+	}
+
 	// Initialize use case.
 	predictionUC := usecase.NewPredictionUseCase(csvLoader, fesLoader, bathyStore)
+	predictionUC.SetCodeVersion(version)
+	predictionUC.SetPredictionModel(predictionModel)
+	predictionUC.SetStationTables(datumOffsetsPath, stationOverridesPath)
+	if tpxoDir != "" {
+		if err := licenses.Check(licensing.TPXO); err != nil {
+			log.Fatalf("Refusing to load TPXO_DIR: %v", err)
+		}
+		tpxoStore := tpxo.NewStore(tpxoDir)
+		names, err := tpxoStore.Constituents()
+		if err != nil {
+			log.Fatalf("Invalid TPXO_DIR: %v", err)
+		}
+		predictionUC.SetTPXOStore(tpxoStore)
+		log.Printf("TPXO9-atlas store: %s (%d constituents)", tpxoDir, len(names))
+	}
+	if fesCurrentsDir != "" {
+		currentStore := fes.NewCurrentStore(fesCurrentsDir)
+		currentStore.SetFillPolicy(fillPolicy)
+		currentStore.SetNearestWetRadius(fillRadius)
+		names, err := currentStore.Constituents()
+		if err != nil {
+			log.Fatalf("Invalid FES_CURRENTS_DIR: %v", err)
+		}
+		predictionUC.SetCurrentStore(currentStore)
+		log.Printf("FES current store: %s (%d constituents)", fesCurrentsDir, len(names))
+	}
+	if err := predictionUC.SetDefaultSource(defaultSource); err != nil {
+		log.Fatalf("Invalid SOURCE: %v", err)
+	}
+	if fixedNow != "" {
+		t, err := time.Parse(time.RFC3339, fixedNow)
+		if err != nil {
+			log.Fatalf("Invalid FIXED_NOW %q (expected RFC3339): %v", fixedNow, err)
+		}
+		log.Printf("Clock frozen at %s", t.UTC().Format(time.RFC3339))
+		predictionUC.SetClock(domain.FixedClock{Time: t})
+	}
+	if ensembleSetting != "" {
+		members, err := parseEnsemble(ensembleSetting, func(dir string) (store.ConstituentLoader, error) {
+			s := fes.NewStore(dir)
+			s.SetFillPolicy(fillPolicy)
+			s.SetNearestWetRadius(fillRadius)
+			s.SetConstituents(fesConstituents)
+			if _, err := loadFESIndex(s, dir, ""); err != nil {
+				return nil, err
+			}
+			return cellCache.wrap(s), nil
+		})
+		if err != nil {
+			log.Fatalf("Invalid FES_ENSEMBLE: %v", err)
+		}
+		predictionUC.SetEnsemble(members)
+		log.Printf("Ensemble datasets: %s", ensembleSetting)
+	}
+
+	// Load the vertical land motion grid (optional).
+	if vlmPath != "" {
+		epoch, err := time.Parse(time.DateOnly, vlmEpoch)
+		if err != nil {
+			log.Fatalf("Invalid VLM_REFERENCE_EPOCH %q (expected YYYY-MM-DD): %v", vlmEpoch, err)
+		}
+		grid, err := vlm.Open(vlmPath)
+		if err != nil {
+			log.Fatalf("Failed to load land motion grid: %v", err)
+		}
+		predictionUC.SetLandMotion(grid, epoch)
+		log.Printf("Vertical land motion: %s (reference epoch %s)", grid.Name(), vlmEpoch)
+	}
+
+	// Load named sea level rise scenarios (optional).
+	if slrScenariosPath != "" {
+		scenarios, err := usecase.LoadSLRScenarios(slrScenariosPath)
+		if err != nil {
+			log.Fatalf("Failed to load sea level rise scenarios: %v", err)
+		}
+		predictionUC.SetSLRScenarios(scenarios)
+		log.Printf("Sea level rise scenarios: %d (%s)", len(scenarios), slrScenariosPath)
+	}
+
+	// Load the seismic event registry (optional).
+	if seismicEventsPath != "" {
+		events, err := usecase.LoadSeismicEvents(seismicEventsPath)
+		if err != nil {
+			log.Fatalf("Failed to load seismic events: %v", err)
+		}
+		predictionUC.SetSeismicEvents(events)
+		log.Printf("Seismic events: %d (%s)", len(events), seismicEventsPath)
+	}
+
+	// Persist computed datum tables (optional).
+	if datumCachePath != "" {
+		tables, err := sqlitecache.OpenTables(datumCachePath)
+		if err != nil {
+			log.Printf("Warning: datum tables not persisted: %v", err)
+		} else {
+			predictionUC.SetDatumCache(tables)
+			log.Printf("Persisted datum tables: %s", datumCachePath)
+		}
+	}
+
+	// Restore state exported by a previous revision (optional).
+	if snapshotPath != "" {
+		if err := restoreSnapshot(predictionUC, snapshotPath); err != nil {
+			log.Fatalf("Failed to restore snapshot: %v", err)
+		}
+	}
+
+	// Initialize live observation source (optional).
+	var observations usecase.ObservationSource
+	var history usecase.ObservationHistory
+	if observationTemplate != "" {
+		log.Printf("Observation source: %s", observationTemplate)
+		source := observation.NewJMASource(observationTemplate)
+		observations, history = source, source
+		predictionUC.SetObservationHistory(history)
+	}
+
+	// SIGINT or SIGTERM (as sent by Cloud Run before stopping an instance)
+	// stops the background jobs and drains the server.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	var background sync.WaitGroup
+
+	// Initialize station monitor (optional).
+	var monitorUC *usecase.MonitorUseCase
+	if monitorStationsPath != "" {
+		interval, err := time.ParseDuration(monitorInterval)
+		if err != nil {
+			log.Fatalf("Invalid MONITOR_INTERVAL %q: %v", monitorInterval, err)
+		}
+		stations, err := usecase.LoadMonitoredStations(monitorStationsPath)
+		if err != nil {
+			log.Fatalf("Failed to load monitored stations: %v", err)
+		}
+		log.Printf("Initializing station monitor")
+		log.Printf("  Stations: %d (%s)", len(stations), monitorStationsPath)
+		if observations == nil {
+			log.Printf("  Observations disabled (OBSERVATION_URL_TEMPLATE not set)")
+		}
+
+		monitorUC = usecase.NewMonitorUseCase(predictionUC, observations, stations)
+		if observations != nil {
+			predictionUC.SetNowcaster(monitorUC)
+		}
+		if alertWebhookURL != "" {
+			monitorUC.AddNotifier(notify.NewWebhookNotifier(alertWebhookURL))
+			log.Printf("  Alert webhook enabled")
+		}
+		if observations != nil {
+			log.Printf("  Surge evaluation interval: %s", interval)
+			go monitorUC.Run(ctx, interval)
+		}
+	} else {
+		log.Printf("Station monitor disabled (MONITOR_STATIONS_PATH not set)")
+	}
+
+	// Initialize observation archive (optional).
+	var archiveUC *usecase.ArchiveUseCase
+	if archiveDir != "" {
+		log.Printf("Initializing observation archive")
+		log.Printf("  Archive directory: %s", archiveDir)
+		archiveUC = usecase.NewArchiveUseCase(predictionUC, archive.NewStore(archiveDir), observations)
+		if observations != nil && monitorUC != nil {
+			interval, err := time.ParseDuration(archiveInterval)
+			if err != nil {
+				log.Fatalf("Invalid ARCHIVE_INTERVAL %q: %v", archiveInterval, err)
+			}
+			stations := make([]string, 0, len(monitorUC.Stations()))
+			for _, st := range monitorUC.Stations() {
+				stations = append(stations, st.Station)
+			}
+			log.Printf("  Scheduled ingestion every %s for %d monitored stations", interval, len(stations))
+			go archiveUC.Run(ctx, interval, 48*time.Hour, stations)
+		}
+	}
+
+	// Initialize station table recalibration (optional).
+	var recalibrationUC *usecase.RecalibrationUseCase
+	if history != nil {
+		window, err := time.ParseDuration(recalibrationWindow)
+		if err != nil || window <= 0 {
+			log.Fatalf("Invalid RECALIBRATION_WINDOW %q", recalibrationWindow)
+		}
+		var stations []string
+		if recalibrationStations != "" {
+			stations = strings.Split(recalibrationStations, ",")
+		}
+		recalibrationUC = usecase.NewRecalibrationUseCase(predictionUC, history, window, stations)
+		if recalibrationInterval != "" {
+			interval, err := time.ParseDuration(recalibrationInterval)
+			if err != nil {
+				log.Fatalf("Invalid RECALIBRATION_INTERVAL %q: %v", recalibrationInterval, err)
+			}
+			log.Printf("Station table recalibration every %s over the last %s, staged for approval", interval, window)
+			go recalibrationUC.Run(ctx, interval)
+		}
+	}
+
+	// Initialize tenants (optional).
+	var tenants *httpHandler.TenantResolver
+	if tenantsPath != "" {
+		log.Printf("Initializing tenants from %s", tenantsPath)
+		defaults := tenantConfig{
+			DataDir:              dataDir,
+			FESDir:               fesDir,
+			FillPolicy:           string(fillPolicy),
+			FillRadius:           &fillRadius,
+			FESConstituents:      fesConstituentsSetting,
+			PredictionModel:      predictionModelKey,
+			DatumOffsetsPath:     datumOffsetsPath,
+			StationOverridesPath: stationOverridesPath,
+		}
+		tenants, err = loadTenants(tenantsPath, predictionUC, defaults, bathyStore, history, cellCache)
+		if err != nil {
+			log.Fatalf("Failed to load tenants: %v", err)
+		}
+	}
+
+	// Initialize usage analytics.
+	analytics := usecase.NewUsageAnalytics()
+	if analyticsExportPath != "" {
+		interval, err := time.ParseDuration(analyticsExportInterval)
+		if err != nil {
+			log.Fatalf("Invalid ANALYTICS_EXPORT_INTERVAL %q: %v", analyticsExportInterval, err)
+		}
+		log.Printf("Usage analytics export: %s every %s", analyticsExportPath, interval)
+		// Waited for on shutdown, as it exports once more when stopped.
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			analytics.RunExport(ctx, interval, analyticsExportPath)
+		}()
+	}
+
+	// Initialize shadow evaluation against another instance (optional).
+	var shadowEvaluator *usecase.ShadowEvaluator
+	if shadowURL != "" {
+		rate, err := strconv.ParseFloat(shadowSampleRate, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Fatalf("Invalid SHADOW_SAMPLE_RATE %q (expected 0-1)", shadowSampleRate)
+		}
+		tolerance, err := strconv.ParseFloat(shadowToleranceM, 64)
+		if err != nil || tolerance < 0 {
+			log.Fatalf("Invalid SHADOW_TOLERANCE_M %q", shadowToleranceM)
+		}
+		log.Printf("Shadow evaluation: %.2f%% of /v1/tides requests replayed to %s (tolerance %g m)", rate*100, shadowURL, tolerance)
+		shadowEvaluator = usecase.NewShadowEvaluator(shadow.NewClient(shadowURL), rate, tolerance)
+	}
+
+	// Initialize structured request logging: every request, or the slow
+	// ones and a sample of the others.
+	slowThreshold := time.Duration(0)
+	if slowRequestThreshold != "" {
+		if slowThreshold, err = time.ParseDuration(slowRequestThreshold); err != nil || slowThreshold <= 0 {
+			log.Fatalf("Invalid SLOW_REQUEST_THRESHOLD %q (expected a positive duration)", slowRequestThreshold)
+		}
+	}
+	sampleRate := 1.0
+	if slowThreshold > 0 {
+		sampleRate = 0.01
+	}
+	if requestLogSampleRate != "" {
+		if sampleRate, err = strconv.ParseFloat(requestLogSampleRate, 64); err != nil || sampleRate < 0 || sampleRate > 1 {
+			log.Fatalf("Invalid REQUEST_LOG_SAMPLE_RATE %q (expected 0-1)", requestLogSampleRate)
+		}
+	}
+	slowLabel := "off"
+	if slowThreshold > 0 {
+		slowLabel = slowThreshold.String()
+	}
+	log.Printf("Request log: slow threshold %s, %.2f%% of other requests logged", slowLabel, sampleRate*100)
+	requestLog := httpHandler.NewRequestLog(logger, slowThreshold, sampleRate)
+
+	// Initialize priority scheduling of batch requests.
+	scheduler, err := newScheduler(batchAPIKeys, batchMaxConcurrent, batchMaxQueued, batchQueueTimeout)
+	if err != nil {
+		log.Fatalf("Invalid BATCH_* setting: %v", err)
+	}
+
+	// Initialize incident reporting of panicking requests (optional).
+	var errorReporter httpHandler.ErrorReporter
+	if errorReportURL != "" {
+		log.Printf("Incident reports: %s", errorReportURL)
+		errorReporter = notify.NewWebhookNotifier(errorReportURL)
+	}
 
 	// Setup router.
-	router := httpHandler.SetupRouter(predictionUC)
+	router := httpHandler.SetupRouter(httpHandler.Services{
+		Prediction:    predictionUC,
+		Monitor:       monitorUC,
+		Archive:       archiveUC,
+		Tenants:       tenants,
+		Analytics:     analytics,
+		Recalibration: recalibrationUC,
+		Shadow:        shadowEvaluator,
+		RequestLog:    requestLog,
+		Scheduler:     scheduler,
+		Build:         httpHandler.BuildInfo{Version: version, Commit: commit, BuildDate: buildDate},
+		ErrorReporter: errorReporter,
+		Docs:          apiDocs,
+		Metrics:       metricsEnabled,
+	})
 
 	// Start server.
 	addr := fmt.Sprintf(":%s", port)
@@ -96,14 +588,229 @@ func main() {
 	log.Printf("Health check: http://localhost:%s/health", port)
 	log.Printf("API endpoints:")
 	log.Printf("  - GET /v1/tides/predictions")
+	log.Printf("  - POST /v1/tides/predictions:batch")
+	log.Printf("  - GET /v1/tides/stream")
 	log.Printf("  - GET /v1/constituents")
+	log.Printf("  - GET /v1/constituents/:name/coverage")
+	log.Printf("  - GET /v1/constituents/:name/grid")
+	log.Printf("  - GET /v1/stations")
+	log.Printf("  - GET /v1/version")
+	log.Printf("  - GET /v1/examples")
 	if bathyStore != nil {
 		log.Printf("  - GET /v1/bathymetry")
 	}
+	if monitorUC != nil {
+		log.Printf("  - GET /v1/monitor/alerts")
+		log.Printf("  - GET /v1/monitor/dashboard")
+	}
+	if archiveUC != nil {
+		log.Printf("  - GET /v1/observations/archive")
+		log.Printf("  - POST /v1/observations/archive/fill")
+	}
 
-	if err := router.Run(addr); err != nil {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+	select {
+	case err := <-serveErr:
 		log.Fatalf("Failed to start server: %v", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Printf("Shutting down: draining requests for up to %s", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// Long-lived streams are still open.
+		log.Printf("Warning: closing remaining connections: %v", err)
+		_ = server.Close()
+	}
+	background.Wait()
+	log.Printf("Server stopped")
+}
+
+// shutdownTimeout bounds the draining of requests on shutdown, within the
+// 10 s Cloud Run allows after SIGTERM.
+const shutdownTimeout = 8 * time.Second
+
+// restoreSnapshot restores server state from a snapshot file.
+func restoreSnapshot(predictionUC *usecase.PredictionUseCase, path string) error {
+	//nolint:gosec // G304: File path from env var.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snapshot usecase.Snapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return fmt.Errorf("invalid snapshot JSON: %w", err)
+	}
+	result, err := predictionUC.RestoreSnapshot(&snapshot)
+	if err != nil {
+		return err
+	}
+	log.Printf("Restored snapshot from %s (created %s by %s)", path, snapshot.CreatedAt, snapshot.CodeVersion)
+	log.Printf("  Station tables: %s", result.StationTables)
+	log.Printf("  Warming %d locations in background", result.WarmupLocations)
+	return nil
+}
+
+// validateDataFiles checks each existing data file (kind -> path) against its
+// schema; missing files are skipped since the tables are optional.
+func validateDataFiles(files map[string]string) error {
+	for _, kind := range schema.Kinds() {
+		path, ok := files[kind]
+		if !ok {
+			continue
+		}
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := schema.ValidateFile(kind, path); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// newLogger creates the structured logger in the given format. With json,
+// it also becomes the default logger, so that all log lines are JSON.
+func newLogger(format string) (*slog.Logger, error) {
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, nil)), nil
+	case "json":
+		logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+		slog.SetDefault(logger)
+		return logger, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (expected text or json)", format)
+	}
+}
+
+// parseRetryPolicy builds the file read retry policy from its settings.
+func parseRetryPolicy(attempts, delay, jitter string) (retry.Policy, error) {
+	p := retry.DefaultPolicy()
+	var err error
+	if p.Attempts, err = strconv.Atoi(attempts); err != nil {
+		return p, fmt.Errorf("attempts: %w", err)
 	}
+	if p.BaseDelay, err = time.ParseDuration(delay); err != nil {
+		return p, fmt.Errorf("delay: %w", err)
+	}
+	if p.Jitter, err = strconv.ParseFloat(jitter, 64); err != nil {
+		return p, fmt.Errorf("jitter: %w", err)
+	}
+	return p, p.Validate()
+}
+
+// parseSubsetMargin builds the grid subset margin of a dataset from its
+// setting in degrees and the GRID_SUBSET_*_CELLS bounds.
+func parseSubsetMargin(degrees, minCells, maxCells string) (interp.SubsetMargin, error) {
+	var m interp.SubsetMargin
+	var err error
+	if m.Degrees, err = strconv.ParseFloat(degrees, 64); err != nil || m.Degrees < 0 {
+		return m, fmt.Errorf("margin %q: want degrees >= 0", degrees)
+	}
+	if m.MinCells, err = strconv.Atoi(minCells); err != nil || m.MinCells < 0 {
+		return m, fmt.Errorf("min cells %q: want an integer >= 0", minCells)
+	}
+	if m.MaxCells, err = strconv.Atoi(maxCells); err != nil || m.MaxCells < 0 {
+		return m, fmt.Errorf("max cells %q: want an integer >= 0", maxCells)
+	}
+	if m.MaxCells > 0 && m.MinCells > m.MaxCells {
+		return m, fmt.Errorf("min cells %d exceed max cells %d", m.MinCells, m.MaxCells)
+	}
+	return m, nil
+}
+
+// newScheduler creates the request scheduler from the BATCH_* settings.
+func newScheduler(keys, concurrency, queue, timeout string) (*httpHandler.Scheduler, error) {
+	cfg := httpHandler.DefaultSchedulerConfig()
+	var err error
+	if cfg.BatchConcurrency, err = strconv.Atoi(concurrency); err != nil {
+		return nil, fmt.Errorf("max concurrent: %w", err)
+	}
+	if cfg.BatchQueue, err = strconv.Atoi(queue); err != nil {
+		return nil, fmt.Errorf("max queued: %w", err)
+	}
+	if cfg.BatchQueueTimeout, err = time.ParseDuration(timeout); err != nil {
+		return nil, fmt.Errorf("queue timeout: %w", err)
+	}
+	for _, k := range strings.Split(keys, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			cfg.BatchKeys = append(cfg.BatchKeys, k)
+		}
+	}
+	s, err := httpHandler.NewScheduler(cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Batch priority: %d concurrent, %d queued for up to %s, %d batch API keys", cfg.BatchConcurrency, cfg.BatchQueue, cfg.BatchQueueTimeout, len(cfg.BatchKeys))
+	return s, nil
+}
+
+// parseEnsemble parses "name=dir,..." ensemble datasets, creating each
+// member's loader with newLoader.
+func parseEnsemble(setting string, newLoader func(dir string) (store.ConstituentLoader, error)) ([]usecase.EnsembleMember, error) {
+	var members []usecase.EnsembleMember
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(setting, ",") {
+		name, dir, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		name, dir = strings.TrimSpace(name), strings.TrimSpace(dir)
+		if !ok || name == "" || dir == "" {
+			return nil, fmt.Errorf("invalid member %q (expected name=dir)", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate member %q", name)
+		}
+		seen[name] = true
+		loader, err := newLoader(dir)
+		if err != nil {
+			return nil, fmt.Errorf("member %q: %w", name, err)
+		}
+		members = append(members, usecase.EnsembleMember{Name: name, Loader: loader})
+	}
+	if len(members) < 2 {
+		return nil, errors.New("an ensemble needs at least two datasets")
+	}
+	return members, nil
+}
+
+// loadFESIndex sets the fes-index file at path on s or, if path is empty,
+// the one in the FES directory when present. It reports whether the store
+// uses an index; without one the directory is scanned once now. A failed
+// scan is logged and the store scans on every read, so data files added
+// later are still found.
+func loadFESIndex(s *fes.Store, dir, path string) (bool, error) {
+	if path == "" {
+		path = filepath.Join(dir, fes.IndexFileName)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			if n, err := s.ScanFiles(); err != nil {
+				log.Printf("Warning: FES directory %s not scanned: %v", dir, err)
+			} else {
+				log.Printf("FES directory %s: %d constituents scanned", dir, n)
+			}
+			return false, nil
+		}
+	}
+	if err := s.LoadIndex(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// loadResidualModel loads an ONNX residual correction model.
+func loadResidualModel(path string) (*onnx.ResidualModel, error) {
+	m, err := onnx.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return onnx.NewResidualModel(m, filepath.Base(path))
 }
 
 // getEnv retrieves an environment variable or returns a default value.
@@ -114,6 +821,49 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// constituentCache configures the geohash cell cache of FES stores and
+// their grid cell caches.
+type constituentCache struct {
+	size           int    // Cells held in memory; <= 0 disables caching.
+	path           string // SQLite file persisting cells across restarts; empty disables.
+	gridCellsBytes int64  // Memory of grid cells read around points; 0 disables.
+}
+
+// wrap sets the grid cell cache of a FES store and wraps the store in a
+// geohash cell cache. Persisted cells are keyed by the
+// store's dataset version and the code version, so replaced data files or
+// a new interpolation never serve stale cells. If the cache file cannot be
+// opened, the store is cached in memory only.
+func (c constituentCache) wrap(s *fes.Store) store.ConstituentLoader {
+	s.SetCellCache(c.gridCellsBytes)
+	if c.size <= 0 {
+		return s
+	}
+	loader := geocache.NewLoader(s, c.size)
+	if c.path == "" {
+		return loader
+	}
+	datasetVersion, err := s.DatasetVersion()
+	if err != nil {
+		log.Printf("Warning: constituent cache not persisted: %v", err)
+		return loader
+	}
+	persisted, err := sqlitecache.Open(c.path, datasetVersion+";"+version)
+	if err != nil {
+		log.Printf("Warning: constituent cache not persisted: %v", err)
+		return loader
+	}
+	cells, err := persisted.Len()
+	if err != nil {
+		log.Printf("Warning: constituent cache not persisted: %v", err)
+		_ = persisted.Close()
+		return loader
+	}
+	log.Printf("Persisted constituent cache: %s (%s, %d cells)", c.path, datasetVersion, cells)
+	loader.SetBacking(persisted)
+	return loader
+}
+
 // printUsage prints usage information.
 func printUsage() {
 	fmt.Printf("Tides API Server v%s\n\n", version)
@@ -128,10 +878,67 @@ func printUsage() {
 	fmt.Println("  PORT                    Server port (default: 8080)")
 	fmt.Println("  DATA_DIR                CSV data directory (default: ./data)")
 	fmt.Println("  FES_DIR                 FES NetCDF data directory (default: ./data/fes)")
+	fmt.Println("  FES_INDEX_PATH          FES index written by fes-index (default: fes-index.json in FES_DIR, if present)")
+	fmt.Println("  TPXO_DIR                TPXO9-atlas NetCDF directory (h_*.nc and grid_*.nc), enabling source=tpxo (optional)")
+	fmt.Println("  DATASET_LICENSES_ACCEPTED  Datasets whose license terms are accepted, e.g. tpxo (required to load TPXO_DIR)")
+	fmt.Println("  FES_CURRENTS_DIR        FES current directory (eastward_velocity/, northward_velocity/), enabling /v1/tides/currents (optional)")
+	fmt.Println("  SOURCE                  Dataset of lat/lon queries without a source parameter: fes or tpxo (default: fes)")
 	fmt.Println("  CORS_ALLOWED_ORIGINS    Comma-separated list of allowed origins (default: all origins)")
 	fmt.Println("  BATHYMETRY_GEBCO_PATH   Path to GEBCO NetCDF file (optional, can be GCS FUSE mount)")
 	fmt.Println("  BATHYMETRY_MSS_PATH     Path to MSS NetCDF file (optional, can be GCS FUSE mount)")
 	fmt.Println("  GEOID_EGM2008_PATH      Path to EGM2008 geoid NetCDF file (optional, for MSL correction)")
+	fmt.Println("  VLM_PATH                Vertical land motion NetCDF grid in mm/yr, for include_vlm requests (optional)")
+	fmt.Println("  VLM_REFERENCE_EPOCH     Date from which land motion is applied, YYYY-MM-DD (default: 2020-01-01)")
+	fmt.Println("  SLR_SCENARIOS_PATH      JSON list of named sea level rise scenarios for slr_scenario (optional)")
+	fmt.Println("  SEISMIC_EVENTS_PATH     JSON list of co-seismic land displacements applied after each event (optional)")
+	fmt.Println("  MONITOR_STATIONS_PATH   JSON list of stations to monitor (optional)")
+	fmt.Println("  OBSERVATION_URL_TEMPLATE  JMA hourly text path/URL with {station} and {year} placeholders")
+	fmt.Println("  ALERT_WEBHOOK_URL       Webhook receiving surge alert level changes (optional)")
+	fmt.Println("  ERROR_REPORT_URL        Webhook receiving incident reports of panicking requests (optional)")
+	fmt.Println("  MONITOR_INTERVAL        Surge evaluation interval (default: 5m)")
+	fmt.Println("  ARCHIVE_DIR             Observation archive directory (optional)")
+	fmt.Println("  ARCHIVE_INTERVAL        Scheduled archive ingestion interval (default: 1h)")
+	fmt.Println("  RECALIBRATION_INTERVAL  Interval of staged station table refits (optional, e.g. 168h)")
+	fmt.Println("  RECALIBRATION_WINDOW    Observations fitted per recalibration (default: 8760h)")
+	fmt.Println("  RECALIBRATION_STATIONS  Comma-separated stations to recalibrate (default: all in the tables)")
+	fmt.Println("  ADMIN_TOKEN             Bearer token enabling /admin endpoints (optional)")
+	fmt.Println("  API_DOCS                Serve the OpenAPI spec and the /docs explorer: true or false (default: false)")
+	fmt.Println("  METRICS_ENABLED         Serve Prometheus metrics at /metrics: true or false (default: false)")
+	fmt.Println("  FIXED_NOW               Freeze the server's current time, RFC3339 (optional, for tests and reproducible runs)")
+	fmt.Println("  SHADOW_URL              Instance /v1/tides requests are replayed to for comparison (optional)")
+	fmt.Println("  SHADOW_SAMPLE_RATE      Fraction of requests replayed, 0-1 (default: 0.01)")
+	fmt.Println("  SHADOW_TOLERANCE_M      Height difference logged as a divergence (default: 0.001)")
+	fmt.Println("  SLOW_REQUEST_THRESHOLD  Log requests slower than this with parameters and timing (optional, e.g. 1s)")
+	fmt.Println("  REQUEST_LOG_SAMPLE_RATE  Fraction of other requests logged, 0-1 (default: 1, or 0.01 with SLOW_REQUEST_THRESHOLD)")
+	fmt.Println("  LOG_FORMAT              Structured log format: text or json (default: text)")
+	fmt.Println("  BATCH_API_KEYS          Comma-separated API keys whose requests are always batch priority (optional)")
+	fmt.Println("  BATCH_MAX_CONCURRENT    Batch-priority requests served at once (default: 2)")
+	fmt.Println("  BATCH_MAX_QUEUED        Batch-priority requests waiting for a slot, more get 429 (default: 32)")
+	fmt.Println("  BATCH_QUEUE_TIMEOUT     Longest wait of a batch-priority request (default: 30s)")
+	fmt.Println("  SNAPSHOT_PATH           Snapshot file to restore at startup (optional)")
+	fmt.Println("  TENANTS_PATH            JSON tenant config selecting datasets by API key/host (optional)")
+	fmt.Println("  ANALYTICS_EXPORT_PATH   File the usage report is periodically written to (optional)")
+	fmt.Println("  ANALYTICS_EXPORT_INTERVAL  Usage report export interval (default: 1h)")
+	fmt.Println("  BATHYMETRY_VERTICAL_CONVENTION  positive_up (elevation, GEBCO) or positive_down (depth) (default: positive_up)")
+	fmt.Println("  BATHYMETRY_TIME_INDEX   Index along time dimensions of 3D bathymetry/MSS variables (default: 0)")
+	fmt.Println("  BATHYMETRY_GEBCO_MARGIN_DEG  Degrees of GEBCO grid loaded around a location; 0 for all (default: 2)")
+	fmt.Println("  BATHYMETRY_MSS_MARGIN_DEG    Degrees of MSS grid loaded around a location; 0 for all (default: 2)")
+	fmt.Println("  GEOID_EGM2008_MARGIN_DEG     Degrees of geoid grid loaded around a location; 0 for all (default: 2)")
+	fmt.Println("  GRID_SUBSET_MIN_CELLS   Grid cells a subset margin spans at least (default: 4)")
+	fmt.Println("  GRID_SUBSET_MAX_CELLS   Grid cells a subset margin spans at most; 0 for no limit (default: 480)")
+	fmt.Println("  FES_FILL_POLICY         Land/no-data cells: nan, nearest or zero (default: nan)")
+	fmt.Println("  FES_FILL_RADIUS_CELLS   Grid cells searched for a wet point with FES_FILL_POLICY=nearest (default: 4)")
+	fmt.Println("  PREDICTION_MODEL        Height model (default: harmonic)")
+	fmt.Println("  RESIDUAL_MODEL_PATH     ONNX residual correction model, selectable as onnx_residual (optional)")
+	fmt.Println("  FES_ENSEMBLE            Datasets of ensemble=true requests, e.g. fes2014=/data/fes2014,fes2022=/data/fes2022 (optional)")
+	fmt.Println("  FES_CONSTITUENTS        Constituents per location: default, all or a list like M2,S2,K1 (default: default)")
+	fmt.Println("  CONSTITUENT_CACHE_SIZE  Geohash cells of cached constituent sets, 0 disables (default: 10000)")
+	fmt.Println("  CONSTITUENT_CACHE_PATH  SQLite file persisting cached constituent sets across restarts (optional)")
+	fmt.Println("  GRID_CELL_CACHE_MB      Memory of cached FES grid cells read around points, 0 disables (default: 64)")
+	fmt.Println("  DATUM_CACHE_PATH        SQLite file persisting computed tidal datum tables, may be CONSTITUENT_CACHE_PATH (optional)")
+	fmt.Println("  FILE_RETRY_ATTEMPTS     NetCDF read attempts on transient I/O errors (default: 3)")
+	fmt.Println("  FILE_RETRY_DELAY        Wait before the first retry, doubled per retry (default: 100ms)")
+	fmt.Println("  FILE_RETRY_JITTER       Randomized fraction of each wait, 0-1 (default: 0.5)")
 	fmt.Println()
 	fmt.Println("EXAMPLES:")
 	fmt.Println("  # Start server with default settings")
@@ -141,9 +948,33 @@ func printUsage() {
 	fmt.Println("  PORT=3000 tides-api")
 	fmt.Println()
 	fmt.Println("API ENDPOINTS:")
-	fmt.Println("  GET /health                    Health check")
-	fmt.Println("  GET /v1/constituents           List tidal constituents")
+	fmt.Println("  GET /health                    Health check (alias /healthz)")
+	fmt.Println("  GET /v1/version                Build, enabled features and dataset versions")
+	fmt.Println("  GET /v1/examples               Request/response examples for contract tests")
+	fmt.Println("  GET /v1/openapi.json           OpenAPI 3 spec of the public endpoints")
+	fmt.Println("  GET /docs                      Interactive API explorer of /openapi.json (with API_DOCS=true)")
+	fmt.Println("  GET /metrics                   Prometheus metrics (with METRICS_ENABLED=true)")
+	fmt.Println("  GET /v1/constituents           List tidal constituents (filter by type, frequency)")
+	fmt.Println("  GET /v1/constituents/:name/coverage  Grid extent of a constituent in the FES dataset")
+	fmt.Println("  GET /v1/constituents/:name/grid  Decimated amplitude/phase grid of a constituent")
+	fmt.Println("  GET /v1/stations               List station IDs and overrides (nearest first with lat/lon)")
 	fmt.Println("  GET /v1/tides/predictions      Get tide predictions")
+	fmt.Println("  POST /v1/tides/predictions:batch  Predictions for up to 100 locations or stations")
+	fmt.Println("  GET /v1/tides/stream           Live predicted height as Server-Sent Events")
+	fmt.Println("  GET /v1/tides/currents         Tidal current speed and direction, slack water and max flood/ebb (if configured)")
+	fmt.Println("  GET /v1/tides/datums           Tidal datums (MSL, MHHW, ..., LAT) of a location or station")
 	fmt.Println("  GET /v1/bathymetry             Get bathymetry and MSL data (if configured)")
+	fmt.Println("  GET /v1/monitor/alerts         Surge rate-of-rise alerts (if configured)")
+	fmt.Println("  GET /v1/monitor/dashboard      Multi-station status snapshot (if configured)")
+	fmt.Println("  GET /v1/observations/archive   Archived observations with predictions (if configured)")
+	fmt.Println("  POST /v1/observations/archive/fill  Fill archive gaps with synthetic predictions")
+	fmt.Println("  GET/POST /admin/snapshot       Export/restore server state (requires ADMIN_TOKEN)")
+	fmt.Println("  POST /v1/admin/warm            Preload constituents and bathymetry of locations (requires ADMIN_TOKEN)")
+	fmt.Println("  GET /admin/analytics           Usage per API key/origin (requires ADMIN_TOKEN)")
+	fmt.Println("  GET /admin/metrics             Cache statistics (requires ADMIN_TOKEN)")
+	fmt.Println("  POST /v1/admin/estimate-datum  Estimate a station datum offset from observations (requires ADMIN_TOKEN)")
+	fmt.Println("  POST /admin/tune-radii         Tune station override radii against nearby stations (requires ADMIN_TOKEN)")
+	fmt.Println("  GET /admin/feed                ZIP of a year of highs and lows per station (requires ADMIN_TOKEN)")
+	fmt.Println("  GET/POST /admin/recalibrations Staged station table refits and approval (requires ADMIN_TOKEN)")
 	fmt.Println()
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"go.ngs.io/tides-api/internal/adapter/store/bathymetry"
+	"go.ngs.io/tides-api/internal/adapter/store/csv"
+	"go.ngs.io/tides-api/internal/adapter/store/fes"
+	httpHandler "go.ngs.io/tides-api/internal/http"
+	"go.ngs.io/tides-api/internal/schema"
+	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// tenantConfig describes one tenant in the TENANTS_PATH JSON file.
+// Empty paths fall back to the server-wide configuration.
+type tenantConfig struct {
+	Name                 string   `json:"name"`
+	APIKeys              []string `json:"api_keys"`
+	Hosts                []string `json:"hosts"`
+	DataDir              string   `json:"data_dir"`
+	FESDir               string   `json:"fes_dir"`
+	FillPolicy           string   `json:"fill_policy"`
+	FillRadius           *int     `json:"fill_radius"` // Cells searched with fill_policy nearest.
+	FESConstituents      string   `json:"fes_constituents"`
+	PredictionModel      string   `json:"prediction_model"`
+	DatumOffsetsPath     string   `json:"datum_offsets_path"`
+	StationOverridesPath string   `json:"station_overrides_path"`
+}
+
+// loadTenants builds a tenant resolver from a JSON config file. Requests
+// matching no tenant use defaultUC. Tenants share the observation source
+// and the nowcasts of the station monitor.
+func loadTenants(path string, defaultUC *usecase.PredictionUseCase, defaults tenantConfig, bathyStore bathymetry.Store, history usecase.ObservationHistory, cellCache constituentCache) (*httpHandler.TenantResolver, error) {
+	//nolint:gosec // G304: File path from env var.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants config: %w", err)
+	}
+	var configs []tenantConfig
+	if err := json.Unmarshal(b, &configs); err != nil {
+		return nil, fmt.Errorf("invalid tenants JSON: %w", err)
+	}
+
+	resolver := httpHandler.NewTenantResolver(&httpHandler.Tenant{Name: "default", Prediction: defaultUC})
+	for _, cfg := range configs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("tenant without name in %s", path)
+		}
+		if cfg.DataDir == "" {
+			cfg.DataDir = defaults.DataDir
+		}
+		if cfg.FESDir == "" {
+			cfg.FESDir = defaults.FESDir
+		}
+		if cfg.FillPolicy == "" {
+			cfg.FillPolicy = defaults.FillPolicy
+		}
+		if cfg.FillRadius == nil {
+			cfg.FillRadius = defaults.FillRadius
+		}
+		if cfg.FESConstituents == "" {
+			cfg.FESConstituents = defaults.FESConstituents
+		}
+		if cfg.PredictionModel == "" {
+			cfg.PredictionModel = defaults.PredictionModel
+		}
+		if cfg.DatumOffsetsPath == "" {
+			cfg.DatumOffsetsPath = defaults.DatumOffsetsPath
+		}
+		if cfg.StationOverridesPath == "" {
+			cfg.StationOverridesPath = defaults.StationOverridesPath
+		}
+
+		if err := validateDataFiles(map[string]string{
+			schema.DatumOffsets:     cfg.DatumOffsetsPath,
+			schema.StationOverrides: cfg.StationOverridesPath,
+		}); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+
+		fillPolicy, err := fes.ParseFillPolicy(cfg.FillPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+		if *cfg.FillRadius < 0 {
+			return nil, fmt.Errorf("tenant %s: fill_radius must not be negative", cfg.Name)
+		}
+		constituents, err := fes.ParseConstituents(cfg.FESConstituents)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+		fesStore := fes.NewStore(cfg.FESDir)
+		fesStore.SetFillPolicy(fillPolicy)
+		fesStore.SetNearestWetRadius(*cfg.FillRadius)
+		fesStore.SetConstituents(constituents)
+		if _, err := loadFESIndex(fesStore, cfg.FESDir, ""); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+
+		uc := usecase.NewPredictionUseCase(csv.NewConstituentStore(cfg.DataDir), cellCache.wrap(fesStore), bathyStore)
+		uc.SetCodeVersion(version)
+		model, err := domain.LookupPredictionModel(cfg.PredictionModel)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+		uc.SetPredictionModel(model)
+		if tpxo := defaultUC.TPXOStore(); tpxo != nil {
+			uc.SetTPXOStore(tpxo)
+		}
+		if err := uc.SetDefaultSource(defaultUC.DefaultSource()); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", cfg.Name, err)
+		}
+		if currents := defaultUC.CurrentStore(); currents != nil {
+			uc.SetCurrentStore(currents)
+		}
+		uc.SetStationTables(cfg.DatumOffsetsPath, cfg.StationOverridesPath)
+		uc.SetClock(defaultUC.Clock())
+		uc.SetLandMotion(defaultUC.LandMotion())
+		uc.SetSLRScenarios(defaultUC.SLRScenarios())
+		uc.SetSeismicEvents(defaultUC.SeismicEvents())
+		uc.SetEnsemble(defaultUC.Ensemble())
+		uc.SetDatumCache(defaultUC.DatumCache())
+		if history != nil {
+			uc.SetObservationHistory(history)
+		}
+		if nowcaster := defaultUC.Nowcaster(); nowcaster != nil {
+			uc.SetNowcaster(nowcaster)
+		}
+
+		resolver.Add(&httpHandler.Tenant{Name: cfg.Name, Prediction: uc}, cfg.APIKeys, cfg.Hosts)
+		log.Printf("  Tenant %s: data=%s fes=%s keys=%d hosts=%v", cfg.Name, cfg.DataDir, cfg.FESDir, len(cfg.APIKeys), cfg.Hosts)
+	}
+	return resolver, nil
+}
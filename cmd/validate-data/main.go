@@ -0,0 +1,58 @@
+// Command validate-data checks the station override, datum offset and nodal
+// coefficient files against their JSON Schemas and reports every violation
+// with its line, column and JSON pointer.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"go.ngs.io/tides-api/internal/schema"
+)
+
+func main() {
+	var (
+		overridesPath string
+		datumPath     string
+		astroPath     string
+	)
+	flag.StringVar(&overridesPath, "overrides", "data/jma_station_overrides.json", "Station overrides JSON (empty to skip)")
+	flag.StringVar(&datumPath, "datum", "data/jma_datum_offsets.json", "Datum offsets JSON (empty to skip)")
+	flag.StringVar(&astroPath, "astro", "data/astro_coeffs.json", "Nodal coefficients JSON (empty to skip)")
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Usage: validate-data [-overrides path] [-datum path] [-astro path]")
+		os.Exit(2)
+	}
+
+	files := []struct{ kind, path string }{
+		{schema.StationOverrides, overridesPath},
+		{schema.DatumOffsets, datumPath},
+		{schema.AstroCoeffs, astroPath},
+	}
+	failed := false
+	for _, f := range files {
+		if f.path == "" {
+			continue
+		}
+		if err := schema.ValidateFile(f.kind, f.path); err != nil {
+			failed = true
+			var verr *schema.ValidationError
+			if errors.As(err, &verr) {
+				for _, e := range verr.Errors {
+					fmt.Printf("%s:%s\n", f.path, e)
+				}
+			} else {
+				fmt.Printf("%s: %v\n", f.path, err)
+			}
+			continue
+		}
+		fmt.Printf("ok %s\n", f.path)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+// Command xlsx-import converts harmonic constants distributed as Excel
+// (.xlsx) sheets into station CSV files. By default it prints a preview of
+// the mapped rows; -commit writes the station file into the data directory.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	csvstore "go.ngs.io/tides-api/internal/adapter/store/csv"
+	"go.ngs.io/tides-api/internal/adapter/xlsx"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func main() {
+	var (
+		inPath      string
+		layoutPath  string
+		station     string
+		dataDir     string
+		stationName string
+		lat         float64
+		lon         float64
+		datumOffset float64
+		commit      bool
+	)
+	layout := xlsx.DefaultLayout()
+	flag.StringVar(&inPath, "in", "", "Path to the .xlsx workbook")
+	flag.StringVar(&layoutPath, "layout", "", "Optional JSON layout file (sheet, header_row, constituent, amplitude, phase, speed, units)")
+	flag.StringVar(&station, "station", "", "Station ID (written as mock_{station}_constituents.csv)")
+	flag.StringVar(&dataDir, "data_dir", "./data", "Station CSV directory")
+	flag.StringVar(&layout.Sheet, "sheet", "", "Sheet name (default: first sheet)")
+	flag.IntVar(&layout.HeaderRow, "header_row", layout.HeaderRow, "1-based header row")
+	flag.StringVar(&layout.Constituent, "constituent", layout.Constituent, "Constituent column (header text or letter)")
+	flag.StringVar(&layout.Amplitude, "amplitude", layout.Amplitude, "Amplitude column (header text or letter)")
+	flag.StringVar(&layout.Phase, "phase", layout.Phase, "Phase column (header text or letter)")
+	flag.StringVar(&layout.Speed, "speed", "", "Optional speed column (header text or letter)")
+	flag.StringVar(&layout.Units, "units", layout.Units, "Amplitude units: m, cm, mm or ft")
+	flag.StringVar(&stationName, "station_name", "", "Station name metadata")
+	flag.Float64Var(&lat, "lat", 0, "Station latitude metadata")
+	flag.Float64Var(&lon, "lon", 0, "Station longitude metadata")
+	flag.Float64Var(&datumOffset, "datum_offset_m", 0, "Station datum offset metadata (meters)")
+	flag.BoolVar(&commit, "commit", false, "Write the station file (default: preview only)")
+	flag.Parse()
+
+	if inPath == "" || station == "" {
+		fmt.Fprintln(os.Stderr, "Usage: xlsx-import -in constants.xlsx -station ID [-layout layout.json] [-units cm] [-commit]")
+		os.Exit(2)
+	}
+
+	// Layout file first; explicit flags override it.
+	if layoutPath != "" {
+		flagLayout := layout
+		//nolint:gosec // G304: Layout path provided by operator (CLI flag).
+		b, err := os.ReadFile(layoutPath)
+		if err != nil {
+			exitErr(fmt.Errorf("read layout: %w", err))
+		}
+		layout = xlsx.DefaultLayout()
+		if err := json.Unmarshal(b, &layout); err != nil {
+			exitErr(fmt.Errorf("parse layout: %w", err))
+		}
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "sheet":
+				layout.Sheet = flagLayout.Sheet
+			case "header_row":
+				layout.HeaderRow = flagLayout.HeaderRow
+			case "constituent":
+				layout.Constituent = flagLayout.Constituent
+			case "amplitude":
+				layout.Amplitude = flagLayout.Amplitude
+			case "phase":
+				layout.Phase = flagLayout.Phase
+			case "speed":
+				layout.Speed = flagLayout.Speed
+			case "units":
+				layout.Units = flagLayout.Units
+			}
+		})
+	}
+
+	//nolint:gosec // G304: Workbook path provided by operator (CLI flag).
+	f, err := os.Open(inPath)
+	if err != nil {
+		exitErr(err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		exitErr(err)
+	}
+	sheet, err := xlsx.ReadSheet(f, info.Size(), layout.Sheet)
+	if err != nil {
+		exitErr(err)
+	}
+
+	preview := xlsx.Import(sheet, layout)
+	printPreview(preview)
+	if !preview.Valid {
+		os.Exit(1)
+	}
+	if !commit {
+		fmt.Println("Preview only; re-run with -commit to write the station file.")
+		return
+	}
+
+	meta := &domain.StationMetadata{Name: stationName}
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "lat":
+			meta.Lat = &lat
+		case "lon":
+			meta.Lon = &lon
+		case "datum_offset_m":
+			meta.DatumOffsetM = &datumOffset
+		}
+	})
+	if err := csvstore.NewConstituentStore(dataDir).SaveStation(station, meta, preview.Constituents); err != nil {
+		exitErr(err)
+	}
+	fmt.Printf("Saved %d constituents for station %s in %s\n", len(preview.Constituents), station, dataDir)
+}
+
+func printPreview(p *xlsx.Preview) {
+	fmt.Printf("Sheet: %s (header row %d, units %s)\n", p.Sheet, p.Layout.HeaderRow, p.Layout.Units)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROW\tCONSTITUENT\tAMPLITUDE_M\tPHASE_DEG\tSPEED_DEG_PER_HR\tERROR")
+	for _, r := range p.Rows {
+		fmt.Fprintf(tw, "%d\t%s\t%.4f\t%.2f\t%.7f\t%s\n", r.Row, r.Constituent, r.AmplitudeM, r.PhaseDeg, r.SpeedDegPerHr, r.Error)
+	}
+	_ = tw.Flush()
+	for _, e := range p.Errors {
+		fmt.Fprintln(os.Stderr, "error:", e)
+	}
+}
+
+func exitErr(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
@@ -8,6 +8,7 @@ require (
 	github.com/fhs/go-netcdf v1.2.1
 	github.com/gin-contrib/cors v1.7.6
 	github.com/gin-gonic/gin v1.11.0
+	github.com/mattn/go-sqlite3 v1.14.33
 )
 
 require (
@@ -0,0 +1,197 @@
+// Package circuit implements circuit breakers for datasets that keep
+// failing, so requests skip them quickly instead of paying for a failing
+// read every time, while a periodic trial call detects recovery.
+package circuit
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// State is the state of a circuit.
+type State string
+
+// Circuit states.
+const (
+	Closed   State = "closed"    // Calls pass through.
+	Open     State = "open"      // Calls are skipped until the cooldown ends.
+	HalfOpen State = "half_open" // One trial call is in flight.
+)
+
+// Config configures a breaker.
+type Config struct {
+	Threshold   int           // Consecutive failures that open the circuit (minimum 1).
+	MinCooldown time.Duration // First open period; doubled after each failed trial.
+	MaxCooldown time.Duration // Cap on the open period.
+	// Now returns the current time (default time.Now).
+	Now func() time.Time
+}
+
+// Status reports a breaker.
+type Status struct {
+	Name     string    `json:"name"`
+	State    State     `json:"state"`
+	Failures int       `json:"consecutive_failures,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Since    time.Time `json:"since,omitzero"`    // First failure of the current outage.
+	RetryAt  time.Time `json:"retry_at,omitzero"` // End of the open period.
+	Skipped  int64     `json:"skipped,omitempty"` // Calls skipped while open.
+}
+
+// Breaker tracks consecutive failures of one dataset.
+type Breaker struct {
+	name string
+	cfg  Config
+
+	mu       sync.Mutex
+	failures int
+	lastErr  error
+	since    time.Time
+	retryAt  time.Time
+	trial    bool // A half-open trial call is in flight.
+	skipped  int64
+}
+
+// New creates a closed breaker.
+func New(name string, cfg Config) *Breaker {
+	cfg.Threshold = max(cfg.Threshold, 1)
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Breaker{name: name, cfg: cfg}
+}
+
+// Allow reports whether a call may proceed. Once the open period ends, a
+// single trial call is allowed; its Record closes or reopens the circuit.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state() {
+	case Closed:
+		return true
+	case Open:
+		if !b.cfg.Now().Before(b.retryAt) {
+			b.trial = true
+			return true
+		}
+	case HalfOpen:
+	}
+	b.skipped++
+	return false
+}
+
+// Record reports the outcome of an allowed call; a nil err is a success.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+
+	if err == nil {
+		if b.failures >= b.cfg.Threshold {
+			fmt.Fprintf(os.Stderr, "Info: %s recovered after %d failed attempts\n", b.name, b.failures)
+		}
+		b.failures, b.lastErr = 0, nil
+		b.since, b.retryAt = time.Time{}, time.Time{}
+		return
+	}
+
+	now := b.cfg.Now()
+	if b.failures == 0 {
+		b.since = now
+	}
+	b.failures++
+	b.lastErr = err
+	if b.failures < b.cfg.Threshold {
+		return
+	}
+	cooldown := b.cfg.MaxCooldown
+	if shift := b.failures - b.cfg.Threshold; shift < 16 {
+		cooldown = min(b.cfg.MinCooldown<<shift, b.cfg.MaxCooldown)
+	}
+	b.retryAt = now.Add(cooldown)
+	if b.failures == b.cfg.Threshold {
+		fmt.Fprintf(os.Stderr, "Warning: %s circuit open after %d failures: %v\n", b.name, b.failures, err)
+	}
+}
+
+// Reason describes the current failure ("" when the last call succeeded).
+func (b *Breaker) Reason() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s unavailable: %v", b.name, b.lastErr)
+}
+
+// Status returns a snapshot of the breaker.
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s := Status{Name: b.name, State: b.state(), Failures: b.failures, Skipped: b.skipped}
+	if b.failures > 0 {
+		s.Error = b.lastErr.Error()
+		s.Since = b.since.UTC()
+	}
+	if s.State != Closed {
+		s.RetryAt = b.retryAt.UTC()
+	}
+	return s
+}
+
+func (b *Breaker) state() State {
+	switch {
+	case b.failures < b.cfg.Threshold:
+		return Closed
+	case b.trial:
+		return HalfOpen
+	default:
+		return Open
+	}
+}
+
+// Set holds one breaker per name (e.g., per constituent), created on first use.
+type Set struct {
+	cfg      Config
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// NewSet creates an empty set whose breakers share cfg.
+func NewSet(cfg Config) *Set {
+	return &Set{cfg: cfg, breakers: make(map[string]*Breaker)}
+}
+
+// Get returns the breaker for name.
+func (s *Set) Get(name string) *Breaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.breakers[name]
+	if b == nil {
+		b = New(name, s.cfg)
+		s.breakers[name] = b
+	}
+	return b
+}
+
+// Failing returns the status of breakers whose last call failed, by name.
+func (s *Set) Failing() []Status {
+	s.mu.Lock()
+	breakers := make([]*Breaker, 0, len(s.breakers))
+	for _, b := range s.breakers {
+		breakers = append(breakers, b)
+	}
+	s.mu.Unlock()
+
+	out := make([]Status, 0)
+	for _, b := range breakers {
+		if st := b.Status(); st.Failures > 0 {
+			out = append(out, st)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,68 @@
+package circuit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New("M2", Config{Threshold: 2, MinCooldown: time.Minute, MaxCooldown: 3 * time.Minute, Now: func() time.Time { return now }})
+	errRead := errors.New("read failed")
+
+	b.Record(errRead)
+	if !b.Allow() || b.Status().State != Closed {
+		t.Fatalf("expected closed below threshold, got %+v", b.Status())
+	}
+	b.Record(errRead)
+	if st := b.Status(); st.State != Open || !st.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected open until +1m, got %+v", st)
+	}
+	if b.Allow() {
+		t.Fatal("expected calls skipped while open")
+	}
+
+	// One trial after the cooldown; concurrent calls are still skipped.
+	now = now.Add(time.Minute)
+	if !b.Allow() || b.Allow() {
+		t.Fatal("expected exactly one trial call")
+	}
+	if st := b.Status(); st.State != HalfOpen || st.Skipped != 2 {
+		t.Fatalf("expected half-open with 2 skipped, got %+v", st)
+	}
+
+	// A failed trial doubles the cooldown, capped.
+	b.Record(errRead)
+	if st := b.Status(); !st.RetryAt.Equal(now.Add(2 * time.Minute)) {
+		t.Errorf("expected retry at +2m, got %+v", st)
+	}
+	now = now.Add(2 * time.Minute)
+	b.Allow()
+	b.Record(errRead)
+	if st := b.Status(); !st.RetryAt.Equal(now.Add(3 * time.Minute)) {
+		t.Errorf("expected capped retry at +3m, got %+v", st)
+	}
+
+	now = now.Add(3 * time.Minute)
+	b.Allow()
+	b.Record(nil)
+	if st := b.Status(); st.State != Closed || st.Failures != 0 || b.Reason() != "" {
+		t.Errorf("expected closed after a successful trial, got %+v", st)
+	}
+}
+
+func TestSetReportsFailingBreakers(t *testing.T) {
+	s := NewSet(Config{Threshold: 1, MinCooldown: time.Minute, MaxCooldown: time.Minute})
+	s.Get("S2").Record(errors.New("eio"))
+	s.Get("M2").Record(nil)
+	s.Get("K1").Record(errors.New("eio"))
+
+	failing := s.Failing()
+	if len(failing) != 2 || failing[0].Name != "K1" || failing[1].Name != "S2" {
+		t.Fatalf("expected K1 and S2 failing, got %+v", failing)
+	}
+	if s.Get("S2") != s.Get("S2") {
+		t.Error("expected the same breaker per name")
+	}
+}
@@ -2,6 +2,7 @@
 package geoid
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"sync"
@@ -9,11 +10,18 @@ import (
 	"github.com/fhs/go-netcdf/netcdf"
 
 	"go.ngs.io/tides-api/internal/adapter/interp"
+	"go.ngs.io/tides-api/internal/adapter/ncfill"
+	"go.ngs.io/tides-api/internal/adapter/retry"
 )
 
+// ErrUnavailable reports that the geoid grid could not be loaded, as opposed
+// to a location outside the loaded grid.
+var ErrUnavailable = errors.New("geoid grid unavailable")
+
 // Store provides geoid height lookups for coordinate transformations.
 type Store struct {
 	geoidPath string // Path to EGM2008 NetCDF file.
+	margin    interp.SubsetMargin
 	grid      *interp.Grid2D
 	mu        sync.RWMutex
 }
@@ -22,9 +30,20 @@ type Store struct {
 func NewStore(geoidPath string) *Store {
 	return &Store{
 		geoidPath: geoidPath,
+		margin:    interp.DefaultSubsetMargin,
 	}
 }
 
+// SetSubsetMargin sets the region of the grid loaded around the first
+// location looked up (default interp.DefaultSubsetMargin). A loaded grid is
+// dropped.
+func (s *Store) SetSubsetMargin(m interp.SubsetMargin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.margin = m
+	s.grid = nil
+}
+
 // GetGeoidHeight returns the EGM2008 geoid height (N) at a given location.
 // This is the separation between the WGS84 ellipsoid and the geoid (mean sea level).
 // Positive values mean the geoid is above the ellipsoid.
@@ -38,8 +57,8 @@ func (s *Store) GetGeoidHeight(lat, lon float64) (float64, error) {
 
 	// Load grid on first access.
 	if s.grid == nil {
-		if err := s.loadGrid(lat, lon); err != nil {
-			return 0, fmt.Errorf("failed to load geoid grid: %w", err)
+		if err := retry.Do("geoid", func() error { return s.loadGrid(lat, lon) }); err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrUnavailable, err)
 		}
 	}
 
@@ -48,6 +67,9 @@ func (s *Store) GetGeoidHeight(lat, lon float64) (float64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("failed to interpolate geoid height: %w", err)
 	}
+	if math.IsNaN(height) {
+		return 0, fmt.Errorf("no geoid height at (%.4f, %.4f): grid cell is fill", lat, lon)
+	}
 
 	return height, nil
 }
@@ -97,8 +119,11 @@ func (s *Store) loadGrid(targetLat, targetLon float64) error {
 		return fmt.Errorf("longitude variable not found (tried: %v)", lonNames)
 	}
 
-	// Calculate subset indices with ±2 degree margin.
-	const margin = 2.0 // Degrees.
+	// Calculate subset indices within the margin.
+	margin := s.margin.Resolve(lonData, latData)
+	if margin == 0 {
+		margin = math.Inf(1)
+	}
 	latStartIdx := findNearestIndex(latData, targetLat-margin)
 	latEndIdx := findNearestIndex(latData, targetLat+margin)
 	lonStartIdx := findNearestIndex(lonData, targetLon-margin)
@@ -190,7 +215,8 @@ func (s *Store) loadGrid(targetLat, targetLon float64) error {
 		Values: values,
 	}
 
-	// Validate grid.
+	// Flip descending axes, then validate grid.
+	s.grid.EnsureAscending()
 	if err := s.grid.Validate(); err != nil {
 		return fmt.Errorf("invalid grid: %w", err)
 	}
@@ -311,6 +337,9 @@ func read2DFloat64VarSubset(v netcdf.Var, startRow, startCol, nRows, nCols int)
 		return nil, fmt.Errorf("unsupported data type: %v", varType)
 	}
 
+	// Fill values, in the stored units, become NaN.
+	ncfill.Of(v).Mask(flatData)
+
 	// Apply scale_factor if present.
 	scaleAttr := v.Attr("scale_factor")
 	attrLen, err := scaleAttr.Len()
@@ -344,18 +373,20 @@ func read2DFloat64VarSubset(v netcdf.Var, startRow, startCol, nRows, nCols int)
 	return values, nil
 }
 
-// findNearestIndex finds the index of the value closest to target in a sorted array.
+// findNearestIndex finds the index of the value closest to target in a sorted
+// (ascending or descending) array.
 func findNearestIndex(arr []float64, target float64) int {
 	if len(arr) == 0 {
 		return 0
 	}
+	desc := arr[0] > arr[len(arr)-1]
 
 	// Binary search for efficiency with large arrays.
 	left, right := 0, len(arr)-1
 
 	for left < right {
 		mid := (left + right) / 2
-		if arr[mid] < target {
+		if (!desc && arr[mid] < target) || (desc && arr[mid] > target) {
 			left = mid + 1
 		} else {
 			right = mid
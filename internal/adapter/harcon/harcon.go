@@ -0,0 +1,170 @@
+// Package harcon reads published harmonic constants of tide stations,
+// NOAA CO-OPS harcon JSON or CSV constituent lists (e.g., IHO tables), and
+// converts them to station overrides.
+package harcon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// Constant is a published constituent: amplitude and Greenwich phase lag
+// G (the V+u convention).
+type Constant struct {
+	Name         string
+	AmplitudeM   float64
+	GreenwichDeg float64
+}
+
+// noaaHarcon is the harcon.json response of the NOAA CO-OPS metadata API
+// (https://api.tidesandcurrents.noaa.gov/mdapi/prod/webapi/stations/{id}/harcon.json).
+type noaaHarcon struct {
+	Units        string `json:"units"`
+	Constituents []struct {
+		Name      string   `json:"name"`
+		Amplitude float64  `json:"amplitude"`
+		PhaseGMT  *float64 `json:"phase_GMT"`
+	} `json:"HarmonicConstituents"`
+}
+
+// ReadNOAA reads a NOAA CO-OPS harcon.json file. Amplitudes are in its
+// units (feet or meters); phases are its GMT phases.
+func ReadNOAA(r io.Reader) ([]Constant, error) {
+	var h noaaHarcon
+	if err := json.NewDecoder(r).Decode(&h); err != nil {
+		return nil, fmt.Errorf("invalid harcon JSON: %w", err)
+	}
+	var units string
+	switch strings.ToLower(h.Units) {
+	case "feet", "ft", "english":
+		units = "ft"
+	case "meters", "m", "metric", "":
+		units = "m"
+	default:
+		return nil, fmt.Errorf("unsupported harcon units %q", h.Units)
+	}
+	scale, _ := domain.AmplitudeUnitScale(units)
+	if len(h.Constituents) == 0 {
+		return nil, errors.New("no HarmonicConstituents in harcon JSON")
+	}
+	constants := make([]Constant, 0, len(h.Constituents))
+	for _, c := range h.Constituents {
+		if c.PhaseGMT == nil {
+			return nil, fmt.Errorf("%s: no phase_GMT", c.Name)
+		}
+		constants = append(constants, Constant{Name: c.Name, AmplitudeM: c.Amplitude * scale, GreenwichDeg: *c.PhaseGMT})
+	}
+	return constants, nil
+}
+
+// Header names of the CSV columns, matched case-insensitively.
+//
+//nolint:gochecknoglobals // Intentional: read-only column aliases.
+var (
+	nameHeaders      = []string{"name", "constituent"}
+	amplitudeHeaders = []string{"amplitude", "amp", "h"}
+	phaseHeaders     = []string{"phase", "phase_gmt", "g", "greenwich phase"}
+)
+
+// ReadCSV reads a constituent list with a header row naming its
+// constituent, amplitude and Greenwich phase columns (e.g., "Name,
+// Amplitude, Phase" as downloaded from NOAA CO-OPS station pages, or an
+// IHO table saved as CSV). Amplitudes are in units (m, cm, mm or ft).
+func ReadCSV(r io.Reader, units string) ([]Constant, error) {
+	scale, err := domain.AmplitudeUnitScale(units)
+	if err != nil {
+		return nil, err
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) < 2 {
+		return nil, errors.New("CSV has no constituent rows")
+	}
+	nameCol, ampCol, phaseCol := column(rows[0], nameHeaders), column(rows[0], amplitudeHeaders), column(rows[0], phaseHeaders)
+	if nameCol < 0 || ampCol < 0 || phaseCol < 0 {
+		return nil, fmt.Errorf("CSV header %q needs name, amplitude and phase columns", rows[0])
+	}
+
+	constants := make([]Constant, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		line := i + 2
+		if max(nameCol, ampCol, phaseCol) >= len(row) || strings.TrimSpace(row[nameCol]) == "" {
+			continue
+		}
+		amplitude, err := strconv.ParseFloat(strings.TrimSpace(row[ampCol]), 64)
+		if err != nil || amplitude < 0 {
+			return nil, fmt.Errorf("line %d: invalid amplitude %q", line, row[ampCol])
+		}
+		phase, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(row[phaseCol]), "°"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid phase %q", line, row[phaseCol])
+		}
+		constants = append(constants, Constant{Name: strings.TrimSpace(row[nameCol]), AmplitudeM: amplitude * scale, GreenwichDeg: phase})
+	}
+	return constants, nil
+}
+
+// column returns the index of the first header among names, else -1.
+func column(header []string, names []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, name := range names {
+			if h == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// OverrideConstituent is a constituent of a station override entry.
+type OverrideConstituent struct {
+	Name       string  `json:"name"`
+	AmplitudeM float64 `json:"amplitude_m"`
+	PhaseDeg   float64 `json:"phase_deg"`
+}
+
+// Convert maps published constants of a station at longitude lon to
+// station override constituents, converting Greenwich phase lags to the
+// override phase convention. Constituents that are not standard, or have
+// zero amplitude, are returned in skipped.
+func Convert(constants []Constant, lon float64) (converted []OverrideConstituent, skipped []string) {
+	seen := make(map[string]bool, len(constants))
+	for _, c := range constants {
+		name, ok := domain.CanonicalConstituentName(c.Name)
+		if !ok || c.AmplitudeM == 0 || seen[name] {
+			skipped = append(skipped, c.Name)
+			continue
+		}
+		phase, ok := domain.OverridePhase(name, c.GreenwichDeg, lon)
+		if !ok {
+			skipped = append(skipped, c.Name)
+			continue
+		}
+		seen[name] = true
+		converted = append(converted, OverrideConstituent{
+			Name:       name,
+			AmplitudeM: round(c.AmplitudeM, 6),
+			PhaseDeg:   round(phase, 6),
+		})
+	}
+	return converted, skipped
+}
+
+func round(v float64, places int) float64 {
+	pow := math.Pow(10, float64(places))
+	return math.Round(v*pow) / pow
+}
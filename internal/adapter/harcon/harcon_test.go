@@ -0,0 +1,72 @@
+package harcon
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestReadNOAA_ConvertsFeet(t *testing.T) {
+	in := `{"units":"feet","HarmonicConstituents":[
+		{"number":1,"name":"M2","description":"Principal lunar semidiurnal constituent","amplitude":1.0,"phase_GMT":150.5,"phase_local":200.1,"speed":28.984104},
+		{"number":2,"name":"S2","amplitude":0.5,"phase_GMT":170,"speed":30}]}`
+	constants, err := ReadNOAA(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadNOAA: %v", err)
+	}
+	if len(constants) != 2 {
+		t.Fatalf("got %d constants, want 2", len(constants))
+	}
+	if c := constants[0]; c.Name != "M2" || math.Abs(c.AmplitudeM-0.3048) > 1e-12 || c.GreenwichDeg != 150.5 {
+		t.Errorf("M2 = %+v", c)
+	}
+
+	if _, err := ReadNOAA(strings.NewReader(`{"units":"fathoms","HarmonicConstituents":[{"name":"M2","amplitude":1,"phase_GMT":0}]}`)); err == nil {
+		t.Error("unknown units accepted")
+	}
+	if _, err := ReadNOAA(strings.NewReader(`{"units":"meters","HarmonicConstituents":[{"name":"M2","amplitude":1}]}`)); err == nil {
+		t.Error("constituent without phase_GMT accepted")
+	}
+}
+
+func TestReadCSV_MatchesHeaders(t *testing.T) {
+	in := "Constituent, Amp, Phase_GMT, Speed\nM2, 55.2, 160.3°, 28.984\n\nK1, 24, 190, 15.04\n"
+	constants, err := ReadCSV(strings.NewReader(in), "cm")
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	if len(constants) != 2 {
+		t.Fatalf("got %d constants, want 2", len(constants))
+	}
+	if c := constants[0]; c.Name != "M2" || math.Abs(c.AmplitudeM-0.552) > 1e-12 || c.GreenwichDeg != 160.3 {
+		t.Errorf("M2 = %+v", c)
+	}
+
+	if _, err := ReadCSV(strings.NewReader("name,amplitude\nM2,1\n"), "m"); err == nil {
+		t.Error("CSV without phase column accepted")
+	}
+	if _, err := ReadCSV(strings.NewReader("name,amplitude,phase\nM2,abc,1\n"), "m"); err == nil {
+		t.Error("invalid amplitude accepted")
+	}
+}
+
+func TestConvert_SkipsUnknownAndConvertsPhase(t *testing.T) {
+	constants := []Constant{
+		{Name: "m2", AmplitudeM: 0.5, GreenwichDeg: 150},
+		{Name: "XYZ9", AmplitudeM: 0.1, GreenwichDeg: 10},
+		{Name: "S2", AmplitudeM: 0, GreenwichDeg: 10},
+	}
+	converted, skipped := Convert(constants, 139.77)
+	if len(converted) != 1 || converted[0].Name != "M2" {
+		t.Fatalf("converted = %+v", converted)
+	}
+	want, _ := domain.OverridePhase("M2", 150, 139.77)
+	if math.Abs(converted[0].PhaseDeg-want) > 1e-6 {
+		t.Errorf("M2 phase = %v, want %v", converted[0].PhaseDeg, want)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("skipped = %v, want XYZ9 and S2", skipped)
+	}
+}
@@ -1,4 +1,5 @@
-// Package interp provides bilinear interpolation for 2D grids.
+// Package interp provides bilinear interpolation for regular and curvilinear
+// 2D grids.
 package interp
 
 import (
@@ -110,6 +111,38 @@ func (g *Grid2D) Validate() error {
 	return nil
 }
 
+// EnsureAscending flips axes stored in descending order (e.g., latitude
+// 90→-90, common in NetCDF products) together with the values, so the grid
+// satisfies Validate. Coordinate and value slices are copied, not modified
+// in place, as they may alias data read from a file.
+func (g *Grid2D) EnsureAscending() {
+	if len(g.Y) > 1 && g.Y[0] > g.Y[len(g.Y)-1] {
+		y := make([]float64, len(g.Y))
+		values := make([][]float64, len(g.Values))
+		for i := range g.Y {
+			y[i] = g.Y[len(g.Y)-1-i]
+		}
+		for i := range g.Values {
+			values[i] = g.Values[len(g.Values)-1-i]
+		}
+		g.Y, g.Values = y, values
+	}
+	if len(g.X) > 1 && g.X[0] > g.X[len(g.X)-1] {
+		x := make([]float64, len(g.X))
+		for j := range g.X {
+			x[j] = g.X[len(g.X)-1-j]
+		}
+		values := make([][]float64, len(g.Values))
+		for i, row := range g.Values {
+			values[i] = make([]float64, len(row))
+			for j := range row {
+				values[i][j] = row[len(row)-1-j]
+			}
+		}
+		g.X, g.Values = x, values
+	}
+}
+
 // InterpolateAt performs bilinear interpolation at a given point.
 func (g *Grid2D) InterpolateAt(x, y float64) (float64, error) {
 	if err := g.Validate(); err != nil {
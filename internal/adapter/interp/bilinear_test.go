@@ -235,3 +235,38 @@ func TestGrid2D_Validate(t *testing.T) {
 		})
 	}
 }
+
+// TestGrid2D_EnsureAscending tests flipping descending axes with their values.
+func TestGrid2D_EnsureAscending(t *testing.T) {
+	lat := []float64{2.0, 1.0, 0.0}
+	grid := &Grid2D{
+		X:      []float64{11.0, 10.0},
+		Y:      lat,
+		Values: [][]float64{{21, 20}, {11, 10}, {1, 0}},
+	}
+	grid.EnsureAscending()
+
+	if err := grid.Validate(); err != nil {
+		t.Fatalf("expected valid grid after flip: %v", err)
+	}
+	if lat[0] != 2.0 {
+		t.Errorf("expected source axis to be left untouched, got %v", lat)
+	}
+	want := [][]float64{{0, 1}, {10, 11}, {20, 21}}
+	for i := range want {
+		for j := range want[i] {
+			if grid.Values[i][j] != want[i][j] {
+				t.Fatalf("values = %v, want %v", grid.Values, want)
+			}
+		}
+	}
+
+	// Value at (x=10.5, y=1.5) is the mean of 10, 11, 20, 21.
+	got, err := grid.InterpolateAt(10.5, 1.5)
+	if err != nil {
+		t.Fatalf("InterpolateAt: %v", err)
+	}
+	if math.Abs(got-15.5) > 1e-9 {
+		t.Errorf("expected 15.5, got %v", got)
+	}
+}
@@ -0,0 +1,299 @@
+package interp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// locateCandidates is the number of nearest cell centers whose cells
+	// are tested for a point; locateCandidatesWide is the retry for grids
+	// whose cell sizes vary sharply.
+	locateCandidates     = 8
+	locateCandidatesWide = 64
+
+	// cellTolerance admits points on cell edges despite rounding in the
+	// inverse mapping.
+	cellTolerance = 1e-9
+)
+
+// CurvilinearGrid is a grid whose node coordinates are 2D arrays, as in
+// rotated or curvilinear regional models: X[i][j] and Y[i][j] are the
+// coordinates (e.g., longitude and latitude) of node (i, j), and each cell
+// is the quadrilateral of nodes (i, j), (i, j+1), (i+1, j), (i+1, j+1).
+// Points are located with a kd-tree of cell centers and an inverse
+// bilinear mapping within the candidate cells.
+type CurvilinearGrid struct {
+	X, Y [][]float64
+
+	tree                   kdTree
+	minX, maxX, minY, maxY float64
+}
+
+// Cell is a point located in a curvilinear grid: the cell's first node
+// (I, J) and the point's local coordinates in the cell, T along j and U
+// along i, both in [0, 1].
+type Cell struct {
+	I, J int
+	T, U float64
+}
+
+// Weights returns the bilinear weights of nodes (I, J), (I, J+1),
+// (I+1, J) and (I+1, J+1).
+func (c Cell) Weights() [4]float64 {
+	return [4]float64{(1 - c.T) * (1 - c.U), c.T * (1 - c.U), (1 - c.T) * c.U, c.T * c.U}
+}
+
+// NewCurvilinearGrid indexes a grid given the coordinates of its nodes.
+// Cells with a NaN corner coordinate (masked nodes) are never located.
+func NewCurvilinearGrid(x, y [][]float64) (*CurvilinearGrid, error) {
+	if len(x) < 2 || len(x) != len(y) {
+		return nil, fmt.Errorf("coordinate arrays must have the same number (>= 2) of rows, got %d and %d", len(x), len(y))
+	}
+	cols := len(x[0])
+	if cols < 2 {
+		return nil, errors.New("coordinate arrays must have at least 2 columns")
+	}
+	for i := range x {
+		if len(x[i]) != cols || len(y[i]) != cols {
+			return nil, fmt.Errorf("row %d has %d and %d values, expected %d", i, len(x[i]), len(y[i]), cols)
+		}
+	}
+
+	g := &CurvilinearGrid{
+		X: x, Y: y,
+		minX: math.Inf(1), maxX: math.Inf(-1), minY: math.Inf(1), maxY: math.Inf(-1),
+	}
+	points := make([]kdPoint, 0, (len(x)-1)*(cols-1))
+	for i := 0; i < len(x)-1; i++ {
+		for j := 0; j < cols-1; j++ {
+			cx, cy, ok := g.center(i, j)
+			if !ok {
+				continue
+			}
+			points = append(points, kdPoint{x: cx, y: cy, i: int32(i), j: int32(j)}) //nolint:gosec // G115: Grid dimensions fit in int32.
+		}
+	}
+	if len(points) == 0 {
+		return nil, errors.New("grid has no cell with valid coordinates")
+	}
+	for i := range x {
+		for j := range x[i] {
+			if math.IsNaN(x[i][j]) || math.IsNaN(y[i][j]) {
+				continue
+			}
+			g.minX, g.maxX = math.Min(g.minX, x[i][j]), math.Max(g.maxX, x[i][j])
+			g.minY, g.maxY = math.Min(g.minY, y[i][j]), math.Max(g.maxY, y[i][j])
+		}
+	}
+	g.tree = newKDTree(points)
+	return g, nil
+}
+
+// Shape returns the number of node rows and columns.
+func (g *CurvilinearGrid) Shape() (rows, cols int) {
+	return len(g.X), len(g.X[0])
+}
+
+// Bounds returns the extent of the node coordinates.
+func (g *CurvilinearGrid) Bounds() (minX, maxX, minY, maxY float64) {
+	return g.minX, g.maxX, g.minY, g.maxY
+}
+
+// Locate finds the cell containing (x, y). It reports false for points
+// outside the grid or in masked cells.
+func (g *CurvilinearGrid) Locate(x, y float64) (Cell, bool) {
+	if x < g.minX || x > g.maxX || y < g.minY || y > g.maxY {
+		return Cell{}, false
+	}
+	for _, k := range []int{locateCandidates, locateCandidatesWide} {
+		for _, p := range g.tree.nearest(x, y, k) {
+			if cell, ok := g.inverse(int(p.i), int(p.j), x, y); ok {
+				return cell, true
+			}
+		}
+	}
+	return Cell{}, false
+}
+
+// InterpolateAt interpolates values, indexed like the node coordinates,
+// at (x, y).
+func (g *CurvilinearGrid) InterpolateAt(values [][]float64, x, y float64) (float64, error) {
+	rows, cols := g.Shape()
+	if len(values) != rows || len(values[0]) != cols {
+		return 0, fmt.Errorf("values are %dx%d, grid is %dx%d", len(values), len(values[0]), rows, cols)
+	}
+	cell, ok := g.Locate(x, y)
+	if !ok {
+		return 0, fmt.Errorf("point (%.6f, %.6f) is outside the grid", x, y)
+	}
+	w := cell.Weights()
+	i, j := cell.I, cell.J
+	return w[0]*values[i][j] + w[1]*values[i][j+1] + w[2]*values[i+1][j] + w[3]*values[i+1][j+1], nil
+}
+
+// center returns the mean of a cell's corners.
+func (g *CurvilinearGrid) center(i, j int) (x, y float64, ok bool) {
+	x = (g.X[i][j] + g.X[i][j+1] + g.X[i+1][j] + g.X[i+1][j+1]) / 4
+	y = (g.Y[i][j] + g.Y[i][j+1] + g.Y[i+1][j] + g.Y[i+1][j+1]) / 4
+	return x, y, !math.IsNaN(x) && !math.IsNaN(y)
+}
+
+// inverse maps (x, y) to local coordinates in cell (i, j) by Newton
+// iteration on the bilinear mapping, reporting whether the point lies in
+// the cell.
+func (g *CurvilinearGrid) inverse(i, j int, x, y float64) (Cell, bool) {
+	x00, y00 := g.X[i][j], g.Y[i][j]
+	x10, y10 := g.X[i][j+1], g.Y[i][j+1]
+	x01, y01 := g.X[i+1][j], g.Y[i+1][j]
+	x11, y11 := g.X[i+1][j+1], g.Y[i+1][j+1]
+
+	t, u := 0.5, 0.5
+	for range 20 {
+		px := (1-t)*(1-u)*x00 + t*(1-u)*x10 + (1-t)*u*x01 + t*u*x11
+		py := (1-t)*(1-u)*y00 + t*(1-u)*y10 + (1-t)*u*y01 + t*u*y11
+		// Jacobian of the mapping.
+		dxdt := (1-u)*(x10-x00) + u*(x11-x01)
+		dydt := (1-u)*(y10-y00) + u*(y11-y01)
+		dxdu := (1-t)*(x01-x00) + t*(x11-x10)
+		dydu := (1-t)*(y01-y00) + t*(y11-y10)
+		det := dxdt*dydu - dxdu*dydt
+		if det == 0 || math.IsNaN(det) {
+			return Cell{}, false
+		}
+		ex, ey := x-px, y-py
+		dt := (ex*dydu - ey*dxdu) / det
+		du := (ey*dxdt - ex*dydt) / det
+		t, u = t+dt, u+du
+		if math.Abs(dt) < 1e-12 && math.Abs(du) < 1e-12 {
+			break
+		}
+	}
+	if t < -cellTolerance || t > 1+cellTolerance || u < -cellTolerance || u > 1+cellTolerance {
+		return Cell{}, false
+	}
+	return Cell{I: i, J: j, T: math.Max(0, math.Min(1, t)), U: math.Max(0, math.Min(1, u))}, true
+}
+
+// kdPoint is a cell center and its cell.
+type kdPoint struct {
+	x, y float64
+	i, j int32
+}
+
+// kdTree is a 2D tree stored implicitly: each range's median (by x at even
+// depths, y at odd) splits its lower and upper halves.
+type kdTree struct {
+	points []kdPoint
+}
+
+func newKDTree(points []kdPoint) kdTree {
+	t := kdTree{points: points}
+	t.build(0, len(points), 0)
+	return t
+}
+
+func (t *kdTree) build(lo, hi, depth int) {
+	if hi-lo < 2 {
+		return
+	}
+	mid := (lo + hi) / 2
+	t.selectNth(lo, hi, mid, depth%2)
+	t.build(lo, mid, depth+1)
+	t.build(mid+1, hi, depth+1)
+}
+
+// selectNth partially orders points[lo:hi] so points[n] holds the value
+// it would have if sorted on axis, with smaller values before it.
+func (t *kdTree) selectNth(lo, hi, n, axis int) {
+	key := func(p kdPoint) float64 {
+		if axis == 0 {
+			return p.x
+		}
+		return p.y
+	}
+	pts := t.points
+	for hi-lo > 1 {
+		pivot := key(pts[(lo+hi)/2])
+		i, j := lo, hi-1
+		for i <= j {
+			for key(pts[i]) < pivot {
+				i++
+			}
+			for key(pts[j]) > pivot {
+				j--
+			}
+			if i <= j {
+				pts[i], pts[j] = pts[j], pts[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case n <= j:
+			hi = j + 1
+		case n >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// nearest returns up to k points closest to (x, y), closest first.
+func (t *kdTree) nearest(x, y float64, k int) []kdPoint {
+	best := make([]kdNeighbor, 0, k)
+	t.search(0, len(t.points), 0, x, y, k, &best)
+	out := make([]kdPoint, len(best))
+	for i, n := range best {
+		out[i] = n.point
+	}
+	return out
+}
+
+type kdNeighbor struct {
+	point kdPoint
+	dist  float64
+}
+
+func (t *kdTree) search(lo, hi, depth int, x, y float64, k int, best *[]kdNeighbor) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	p := t.points[mid]
+	dx, dy := p.x-x, p.y-y
+	insertNeighbor(best, kdNeighbor{point: p, dist: dx*dx + dy*dy}, k)
+
+	diff := x - p.x
+	if depth%2 == 1 {
+		diff = y - p.y
+	}
+	near, far := [2]int{lo, mid}, [2]int{mid + 1, hi}
+	if diff > 0 {
+		near, far = far, near
+	}
+	t.search(near[0], near[1], depth+1, x, y, k, best)
+	if len(*best) < k || diff*diff < (*best)[len(*best)-1].dist {
+		t.search(far[0], far[1], depth+1, x, y, k, best)
+	}
+}
+
+// insertNeighbor adds n to the distance-ordered list, keeping the k closest.
+func insertNeighbor(best *[]kdNeighbor, n kdNeighbor, k int) {
+	b := *best
+	if len(b) == k && n.dist >= b[k-1].dist {
+		return
+	}
+	pos := len(b)
+	for pos > 0 && b[pos-1].dist > n.dist {
+		pos--
+	}
+	if len(b) < k {
+		b = append(b, kdNeighbor{})
+	}
+	copy(b[pos+1:], b[pos:len(b)-1])
+	b[pos] = n
+	*best = b
+}
@@ -0,0 +1,107 @@
+package interp
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+)
+
+// rotatedGrid returns the node coordinates of a rows x cols grid with
+// spacing step, rotated by angle degrees around (x0, y0), and a linear
+// field over it.
+func rotatedGrid(rows, cols int, x0, y0, step, angle float64) (x, y, values [][]float64) {
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	x, y, values = make([][]float64, rows), make([][]float64, rows), make([][]float64, rows)
+	for i := range rows {
+		x[i], y[i], values[i] = make([]float64, cols), make([]float64, cols), make([]float64, cols)
+		for j := range cols {
+			x[i][j] = x0 + step*(float64(j)*cos-float64(i)*sin)
+			y[i][j] = y0 + step*(float64(j)*sin+float64(i)*cos)
+			values[i][j] = linearField(x[i][j], y[i][j])
+		}
+	}
+	return x, y, values
+}
+
+func linearField(x, y float64) float64 {
+	return 2*x - 3*y + 1
+}
+
+func TestCurvilinearGrid_RotatedGridInterpolatesLinearFieldExactly(t *testing.T) {
+	x, y, values := rotatedGrid(40, 60, 135, 30, 0.1, 30)
+	g, err := NewCurvilinearGrid(x, y)
+	if err != nil {
+		t.Fatalf("NewCurvilinearGrid: %v", err)
+	}
+
+	//nolint:gosec // G404: Deterministic test points.
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 500 {
+		// Random points inside the rotated rectangle.
+		i, j := rng.Float64()*39, rng.Float64()*59
+		sin, cos := math.Sincos(30 * math.Pi / 180)
+		px := 135 + 0.1*(j*cos-i*sin)
+		py := 30 + 0.1*(j*sin+i*cos)
+
+		got, err := g.InterpolateAt(values, px, py)
+		if err != nil {
+			t.Fatalf("InterpolateAt(%v, %v): %v", px, py, err)
+		}
+		if want := linearField(px, py); math.Abs(got-want) > 1e-9 {
+			t.Fatalf("InterpolateAt(%v, %v) = %v, want %v", px, py, got, want)
+		}
+		cell, _ := g.Locate(px, py)
+		if cell.I != int(i) || cell.J != int(j) {
+			t.Fatalf("Locate(%v, %v) = cell (%d, %d), want (%d, %d)", px, py, cell.I, cell.J, int(i), int(j))
+		}
+	}
+
+	// Inside the bounding box but outside the rotated grid.
+	minX, _, _, maxY := g.Bounds()
+	if _, ok := g.Locate(minX+0.01, maxY-0.01); ok {
+		t.Error("located a point outside the rotated grid")
+	}
+	if _, err := g.InterpolateAt(values, 0, 0); err == nil {
+		t.Error("expected an error outside the grid")
+	}
+}
+
+func TestCurvilinearGrid_MaskedCells(t *testing.T) {
+	x, y, _ := rotatedGrid(3, 4, 0, 0, 1, 0)
+	x[1][1], y[1][1] = math.NaN(), math.NaN()
+	g, err := NewCurvilinearGrid(x, y)
+	if err != nil {
+		t.Fatalf("NewCurvilinearGrid: %v", err)
+	}
+	// Cells touching the masked node are never located.
+	if _, ok := g.Locate(0.5, 0.5); ok {
+		t.Error("located a point in a masked cell")
+	}
+	if cell, ok := g.Locate(2.5, 0.5); !ok || cell.I != 0 || cell.J != 2 {
+		t.Errorf("Locate(2.5, 0.5) = %+v, %v; want cell (0, 2)", cell, ok)
+	}
+}
+
+func TestKDTree_NearestMatchesBruteForce(t *testing.T) {
+	//nolint:gosec // G404: Deterministic test points.
+	rng := rand.New(rand.NewPCG(3, 4))
+	points := make([]kdPoint, 1000)
+	for n := range points {
+		points[n] = kdPoint{x: rng.Float64(), y: rng.Float64(), i: int32(n)} //nolint:gosec // G115: Small test index.
+	}
+	brute := append([]kdPoint(nil), points...)
+	tree := newKDTree(points)
+
+	for range 50 {
+		qx, qy := rng.Float64(), rng.Float64()
+		dist := func(p kdPoint) float64 { return (p.x-qx)*(p.x-qx) + (p.y-qy)*(p.y-qy) }
+		sort.Slice(brute, func(a, b int) bool { return dist(brute[a]) < dist(brute[b]) })
+		got := tree.nearest(qx, qy, 5)
+		for n := range got {
+			if got[n].i != brute[n].i {
+				t.Fatalf("nearest(%v, %v)[%d] = point %d, want %d", qx, qy, n, got[n].i, brute[n].i)
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package interp
+
+import "math"
+
+// SubsetMargin bounds the region of a grid loaded around a location.
+// The margin in degrees is scaled to the grid's resolution, so fine grids
+// load no more than MaxCells cells on each side of the location and coarse
+// grids at least MinCells.
+type SubsetMargin struct {
+	Degrees  float64 // Margin around the location; 0 loads the entire grid.
+	MinCells int     // Cells the margin spans at least; 0 for no minimum.
+	MaxCells int     // Cells the margin spans at most; 0 for no maximum.
+}
+
+// DefaultSubsetMargin is ±2° of at least 4 cells (so 1° grids still load
+// enough neighbors to interpolate) and at most 480 (2° of a 15" GEBCO grid).
+var DefaultSubsetMargin = SubsetMargin{Degrees: 2, MinCells: 4, MaxCells: 480}
+
+// Resolve returns the margin in degrees on a grid with the given axes.
+// The spacing of the finer axis sets the cell size.
+func (m SubsetMargin) Resolve(x, y []float64) float64 {
+	if m.Degrees <= 0 {
+		return 0
+	}
+	step := math.Min(axisStep(x), axisStep(y))
+	if math.IsInf(step, 1) || step == 0 {
+		return m.Degrees
+	}
+	margin := m.Degrees
+	if m.MinCells > 0 {
+		margin = math.Max(margin, float64(m.MinCells)*step)
+	}
+	if m.MaxCells > 0 {
+		margin = math.Min(margin, float64(m.MaxCells)*step)
+	}
+	return margin
+}
+
+// axisStep returns the mean spacing of an axis, or +Inf with fewer than
+// two coordinates.
+func axisStep(axis []float64) float64 {
+	if len(axis) < 2 {
+		return math.Inf(1)
+	}
+	return math.Abs(axis[len(axis)-1]-axis[0]) / float64(len(axis)-1)
+}
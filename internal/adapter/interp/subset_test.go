@@ -0,0 +1,40 @@
+package interp
+
+import (
+	"math"
+	"testing"
+)
+
+// axis returns n coordinates from 0 spaced by step.
+func axis(n int, step float64) []float64 {
+	a := make([]float64, n)
+	for i := range a {
+		a[i] = float64(i) * step
+	}
+	return a
+}
+
+func TestSubsetMarginResolve(t *testing.T) {
+	gebco := axis(86401, 1.0/240) // 15" global longitudes.
+	coarse := axis(361, 1)
+
+	tests := []struct {
+		name   string
+		margin SubsetMargin
+		x, y   []float64
+		want   float64
+	}{
+		{"default on GEBCO", DefaultSubsetMargin, gebco, axis(43201, 1.0/240), 2},
+		{"capped on GEBCO", SubsetMargin{Degrees: 5, MaxCells: 480}, gebco, axis(43201, 1.0/240), 2},
+		{"widened on a coarse grid", DefaultSubsetMargin, coarse, axis(181, 1), 4},
+		{"finer axis sets the cell", SubsetMargin{Degrees: 5, MaxCells: 10}, coarse, axis(1801, 0.1), 1},
+		{"no bounds", SubsetMargin{Degrees: 3}, gebco, gebco, 3},
+		{"entire grid", SubsetMargin{MinCells: 4}, coarse, coarse, 0},
+		{"single coordinate", DefaultSubsetMargin, []float64{0}, []float64{0}, 2},
+	}
+	for _, tt := range tests {
+		if got := tt.margin.Resolve(tt.x, tt.y); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Resolve = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
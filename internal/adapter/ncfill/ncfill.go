@@ -0,0 +1,102 @@
+// Package ncfill recognizes the fill values of NetCDF variables, so that
+// readers turn them into NaN before interpolating.
+package ncfill
+
+import (
+	"math"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+const (
+	// Default is the NetCDF default fill of float and double variables,
+	// used by variables of those types without a fill attribute.
+	Default = 9.969209968386869e36
+
+	// tolerance is the relative difference within which a value matches
+	// the fill value: a float fill stored as a double attribute (or the
+	// reverse) differs from the data in the digits beyond float precision.
+	tolerance = 1e-6
+)
+
+// Fill is the fill value of a variable.
+type Fill struct {
+	value float64
+	ok    bool
+}
+
+// Of returns the fill value of v: its _FillValue or missing_value
+// attribute, whatever its numeric type, else the NetCDF default for float
+// and double variables.
+func Of(v netcdf.Var) Fill {
+	for _, name := range []string{"_FillValue", "missing_value"} {
+		if value, ok := attrValue(v.Attr(name)); ok {
+			return Fill{value: value, ok: true}
+		}
+	}
+	if t, err := v.Type(); err == nil && (t == netcdf.FLOAT || t == netcdf.DOUBLE) {
+		return Fill{value: Default, ok: true}
+	}
+	return Fill{}
+}
+
+// Value returns the fill value; ok is false when the variable has none.
+func (f Fill) Value() (value float64, ok bool) {
+	return f.value, f.ok
+}
+
+// Is reports whether x is fill: NaN, or the fill value up to float
+// precision.
+func (f Fill) Is(x float64) bool {
+	if math.IsNaN(x) {
+		return true
+	}
+	if !f.ok {
+		return false
+	}
+	return x == f.value || math.Abs(x-f.value) <= tolerance*math.Abs(f.value)
+}
+
+// Mask replaces fill values in place with NaN.
+func (f Fill) Mask(values []float64) {
+	for i, x := range values {
+		if f.Is(x) {
+			values[i] = math.NaN()
+		}
+	}
+}
+
+// attrValue reads the first value of a numeric attribute by its type.
+func attrValue(a netcdf.Attr) (float64, bool) {
+	if n, err := a.Len(); err != nil || n == 0 {
+		return 0, false
+	}
+	t, err := a.Type()
+	if err != nil {
+		return 0, false
+	}
+	switch t {
+	case netcdf.DOUBLE:
+		buf := make([]float64, 1)
+		err = a.ReadFloat64s(buf)
+		return buf[0], err == nil
+	case netcdf.FLOAT:
+		buf := make([]float32, 1)
+		err = a.ReadFloat32s(buf)
+		return float64(buf[0]), err == nil
+	case netcdf.INT:
+		buf := make([]int32, 1)
+		err = a.ReadInt32s(buf)
+		return float64(buf[0]), err == nil
+	case netcdf.SHORT:
+		buf := make([]int16, 1)
+		err = a.ReadInt16s(buf)
+		return float64(buf[0]), err == nil
+	case netcdf.BYTE:
+		buf := make([]int8, 1)
+		err = a.ReadInt8s(buf)
+		return float64(buf[0]), err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,100 @@
+package ncfill
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+)
+
+// readMasked writes a 1x3 variable whose middle cell is fill and returns
+// it read back and masked.
+func readMasked(t *testing.T, v ncfixture.Var) []float64 {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "h.nc")
+	v.Name = "h"
+	v.Values = [][]float32{{1.5, ncfixture.FillValue, -2}}
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: []float64{35}, Lon: []float64{139, 140, 141}, Vars: []ncfixture.Var{v}})
+
+	f, err := netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	nv, err := f.Var("h")
+	if err != nil {
+		t.Fatalf("h: %v", err)
+	}
+
+	values := make([]float64, 3)
+	switch {
+	case v.Double:
+		err = nv.ReadFloat64s(values)
+	case v.Pack != nil:
+		raw := make([]int16, 3)
+		err = nv.ReadInt16s(raw)
+		for i, x := range raw {
+			values[i] = float64(x)
+		}
+	default:
+		raw := make([]float32, 3)
+		err = nv.ReadFloat32s(raw)
+		for i, x := range raw {
+			values[i] = float64(x)
+		}
+	}
+	if err != nil {
+		t.Fatalf("read h: %v", err)
+	}
+	Of(nv).Mask(values)
+	return values
+}
+
+func TestFill_MasksAcrossAttributeTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		v    ncfixture.Var
+	}{
+		{"float data, float attribute", ncfixture.Var{Fill: true}},
+		{"float data, double attribute", ncfixture.Var{Fill: true, Fill64: true}},
+		{"double data, float attribute", ncfixture.Var{Fill: true, Double: true}},
+		{"missing_value attribute", ncfixture.Var{Fill: true, FillAttr: "missing_value", Fill64: true}},
+		{"default fill without attribute", ncfixture.Var{}},
+		{"packed", ncfixture.Var{Fill: true, Pack: &ncfixture.Packing{Scale: 0.5, Offset: 0}}},
+	}
+	for _, tt := range tests {
+		values := readMasked(t, tt.v)
+		if !math.IsNaN(values[1]) {
+			t.Errorf("%s: fill cell = %v, want NaN", tt.name, values[1])
+		}
+		if math.IsNaN(values[0]) || math.IsNaN(values[2]) {
+			t.Errorf("%s: data cells masked: %v", tt.name, values)
+		}
+	}
+}
+
+func TestFill_Is(t *testing.T) {
+	f := Fill{value: 1e20, ok: true}
+	for _, x := range []float64{1e20, float64(float32(1e20)), math.NaN()} {
+		if !f.Is(x) {
+			t.Errorf("Is(%v) = false, want true", x)
+		}
+	}
+	for _, x := range []float64{0, 1e19, -1e20} {
+		if f.Is(x) {
+			t.Errorf("Is(%v) = true, want false", x)
+		}
+	}
+
+	// A zero fill value matches exactly.
+	zero := Fill{ok: true}
+	if !zero.Is(0) || zero.Is(1e-30) {
+		t.Error("zero fill value matched inexactly")
+	}
+	if (Fill{}).Is(Default) {
+		t.Error("variable without fill value matched the default fill")
+	}
+}
@@ -0,0 +1,114 @@
+// Package observation provides access to recent tide gauge observations.
+package observation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/internal/jma"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// defaultCacheTTL bounds how often the same station file is re-downloaded.
+const defaultCacheTTL = 10 * time.Minute
+
+// JMASource loads hourly observations from JMA fixed-width TXT files.
+// The location template may be a local path or an HTTP URL and supports
+// the placeholders {station} and {year} (JST year).
+type JMASource struct {
+	template string
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedObservations
+}
+
+type cachedObservations struct {
+	fetchedAt    time.Time
+	observations []domain.TideLevel
+}
+
+// NewJMASource creates a JMA observation source for the given location template.
+func NewJMASource(template string) *JMASource {
+	return &JMASource{
+		template: template,
+		ttl:      defaultCacheTTL,
+		cache:    make(map[string]cachedObservations),
+	}
+}
+
+// Recent returns observations for station at or after since, in time order.
+func (s *JMASource) Recent(station string, since time.Time) ([]domain.TideLevel, error) {
+	now := time.Now()
+	years := []int{since.In(jma.JSTLocation).Year()}
+	if y := now.In(jma.JSTLocation).Year(); y != years[0] {
+		years = append(years, y)
+	}
+
+	out := make([]domain.TideLevel, 0)
+	for _, year := range years {
+		levels, err := s.load(station, year, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range levels {
+			if !l.Time.Before(since) {
+				out = append(out, l)
+			}
+		}
+	}
+	return out, nil
+}
+
+// Between returns observations for station in [start, end], in time order,
+// reading only the years of the period.
+func (s *JMASource) Between(station string, start, end time.Time) ([]domain.TideLevel, error) {
+	now := time.Now()
+	out := make([]domain.TideLevel, 0)
+	for year := start.In(jma.JSTLocation).Year(); year <= end.In(jma.JSTLocation).Year(); year++ {
+		levels, err := s.load(station, year, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range levels {
+			if !l.Time.Before(start) && !l.Time.After(end) {
+				out = append(out, l)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *JMASource) load(station string, year int, now time.Time) ([]domain.TideLevel, error) {
+	location := s.resolve(station, year)
+
+	s.mu.Lock()
+	if cached, ok := s.cache[location]; ok && now.Sub(cached.fetchedAt) < s.ttl {
+		s.mu.Unlock()
+		return cached.observations, nil
+	}
+	s.mu.Unlock()
+
+	records, err := jma.LoadStationRecordsFromPath(location, station)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load observations for station %s: %w", station, err)
+	}
+	obs := jma.Observations(records)
+	levels := make([]domain.TideLevel, len(obs))
+	for i, o := range obs {
+		levels[i] = domain.TideLevel{Time: o.Time, HeightM: o.HeightM}
+	}
+
+	s.mu.Lock()
+	s.cache[location] = cachedObservations{fetchedAt: now, observations: levels}
+	s.mu.Unlock()
+	return levels, nil
+}
+
+func (s *JMASource) resolve(station string, year int) string {
+	r := strings.NewReplacer("{station}", station, "{year}", strconv.Itoa(year))
+	return r.Replace(s.template)
+}
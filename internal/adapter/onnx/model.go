@@ -0,0 +1,526 @@
+// Package onnx loads small ONNX models and evaluates them without a native
+// runtime.
+//
+// Only what offline-trained tabular regressors need is supported: one float
+// input, float/double initializers and the operators Gemm, MatMul, Add, Sub,
+// Mul, Div, Relu, LeakyRelu, Sigmoid, Tanh and Identity on tensors of rank
+// two or less. Models using anything else are rejected at load time.
+package onnx
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// maxModelSize bounds the size of a model file.
+const maxModelSize = 64 << 20
+
+// ONNX tensor element types.
+const (
+	typeFloat  = 1
+	typeDouble = 11
+)
+
+// ErrUnsupported reports a model feature outside the supported subset.
+var ErrUnsupported = errors.New("unsupported ONNX feature")
+
+// Model is a loaded ONNX graph.
+type Model struct {
+	Producer string            // producer_name and producer_version.
+	Doc      string            // Model doc_string.
+	Metadata map[string]string // metadata_props.
+	Digest   string            // SHA-256 of the model file, hex.
+
+	input        string
+	inputWidth   int // Last input dimension; 0 when symbolic.
+	output       string
+	nodes        []node
+	initializers map[string]*tensor
+}
+
+type node struct {
+	op      string
+	domain  string
+	inputs  []string
+	outputs []string
+	floats  map[string]float64 // Float attributes.
+	ints    map[string]int64   // Int attributes.
+}
+
+// tensor is a dense row-major tensor of rank two or less.
+type tensor struct {
+	shape []int
+	data  []float64
+}
+
+// LoadFile reads and parses a model file.
+func LoadFile(path string) (*Model, error) {
+	//nolint:gosec // G304: Model path from config.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model %s: %w", path, err)
+	}
+	if len(data) > maxModelSize {
+		return nil, fmt.Errorf("model %s exceeds %d bytes", path, maxModelSize)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse decodes a serialized ModelProto and checks that it runs.
+func Parse(data []byte) (*Model, error) {
+	sum := sha256.Sum256(data)
+	m := &Model{
+		Metadata:     map[string]string{},
+		Digest:       hex.EncodeToString(sum[:]),
+		initializers: map[string]*tensor{},
+	}
+	var producer, version string
+	var graph []byte
+	r := &protoReader{b: data}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 2 && wire == wireBytes:
+			producer, err = r.string()
+		case field == 3 && wire == wireBytes:
+			version, err = r.string()
+		case field == 6 && wire == wireBytes:
+			m.Doc, err = r.string()
+		case field == 7 && wire == wireBytes:
+			graph, err = r.bytes()
+		case field == 14 && wire == wireBytes:
+			var b []byte
+			if b, err = r.bytes(); err == nil {
+				err = m.parseMetadataProp(b)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	m.Producer = producer
+	if version != "" {
+		m.Producer += " " + version
+	}
+	if graph == nil {
+		return nil, errors.New("model has no graph")
+	}
+	if err := m.parseGraph(graph); err != nil {
+		return nil, err
+	}
+
+	// A dry run catches shape mismatches at load time rather than per request.
+	width := m.inputWidth
+	if width == 0 {
+		width = 1
+	}
+	if _, err := m.Run(make([]float64, width)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// InputWidth returns the number of input features, or 0 when the model
+// declares a symbolic width.
+func (m *Model) InputWidth() int { return m.inputWidth }
+
+// Run evaluates the model on one feature row and returns the flattened output.
+func (m *Model) Run(input []float64) ([]float64, error) {
+	if m.inputWidth > 0 && len(input) != m.inputWidth {
+		return nil, fmt.Errorf("model expects %d inputs, got %d", m.inputWidth, len(input))
+	}
+	values := make(map[string]*tensor, len(m.initializers)+len(m.nodes)+1)
+	for name, t := range m.initializers {
+		values[name] = t
+	}
+	values[m.input] = &tensor{shape: []int{1, len(input)}, data: input}
+
+	for _, n := range m.nodes {
+		args := make([]*tensor, len(n.inputs))
+		for i, name := range n.inputs {
+			if name == "" {
+				continue // Omitted optional input.
+			}
+			t, ok := values[name]
+			if !ok {
+				return nil, fmt.Errorf("%s: input %q is not defined", n.op, name)
+			}
+			args[i] = t
+		}
+		out, err := n.eval(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", n.op, err)
+		}
+		values[n.outputs[0]] = out
+	}
+
+	out, ok := values[m.output]
+	if !ok {
+		return nil, fmt.Errorf("graph output %q is not produced", m.output)
+	}
+	return out.data, nil
+}
+
+func (m *Model) parseMetadataProp(b []byte) error {
+	var key, value string
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			key, err = r.string()
+		case field == 2 && wire == wireBytes:
+			value, err = r.string()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	m.Metadata[key] = value
+	return nil
+}
+
+//nolint:gocyclo // One case per GraphProto field.
+func (m *Model) parseGraph(b []byte) error {
+	type valueInfo struct {
+		name  string
+		width int
+	}
+	var inputs, outputs []valueInfo
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		if wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return err
+			}
+			continue
+		}
+		sub, err := r.bytes()
+		if err != nil {
+			return err
+		}
+		switch field {
+		case 1:
+			n, err := parseNode(sub)
+			if err != nil {
+				return err
+			}
+			m.nodes = append(m.nodes, n)
+		case 5:
+			name, t, err := parseTensor(sub)
+			if err != nil {
+				return fmt.Errorf("initializer %q: %w", name, err)
+			}
+			m.initializers[name] = t
+		case 11, 12:
+			name, width, err := parseValueInfo(sub)
+			if err != nil {
+				return err
+			}
+			if field == 11 {
+				inputs = append(inputs, valueInfo{name, width})
+			} else {
+				outputs = append(outputs, valueInfo{name, width})
+			}
+		}
+	}
+
+	// Graph inputs that are not initializers are fed at run time.
+	var feeds []valueInfo
+	for _, in := range inputs {
+		if _, ok := m.initializers[in.name]; !ok {
+			feeds = append(feeds, in)
+		}
+	}
+	if len(feeds) != 1 {
+		return fmt.Errorf("%w: graph has %d inputs, want 1", ErrUnsupported, len(feeds))
+	}
+	if len(outputs) != 1 {
+		return fmt.Errorf("%w: graph has %d outputs, want 1", ErrUnsupported, len(outputs))
+	}
+	m.input, m.inputWidth = feeds[0].name, feeds[0].width
+	m.output = outputs[0].name
+	return nil
+}
+
+func parseNode(b []byte) (node, error) {
+	n := node{floats: map[string]float64{}, ints: map[string]int64{}}
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return n, err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			var s string
+			s, err = r.string()
+			n.inputs = append(n.inputs, s)
+		case field == 2 && wire == wireBytes:
+			var s string
+			s, err = r.string()
+			n.outputs = append(n.outputs, s)
+		case field == 4 && wire == wireBytes:
+			n.op, err = r.string()
+		case field == 5 && wire == wireBytes:
+			var sub []byte
+			if sub, err = r.bytes(); err == nil {
+				err = n.parseAttribute(sub)
+			}
+		case field == 7 && wire == wireBytes:
+			n.domain, err = r.string()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return n, err
+		}
+	}
+	if n.domain != "" && n.domain != "ai.onnx" {
+		return n, fmt.Errorf("%w: operator domain %q", ErrUnsupported, n.domain)
+	}
+	if _, ok := operators[n.op]; !ok {
+		return n, fmt.Errorf("%w: operator %q", ErrUnsupported, n.op)
+	}
+	if len(n.outputs) == 0 {
+		return n, fmt.Errorf("%s node has no output", n.op)
+	}
+	return n, nil
+}
+
+func (n *node) parseAttribute(b []byte) error {
+	var name string
+	var f float64
+	var i int64
+	var hasF, hasI bool
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			name, err = r.string()
+		case field == 2 && wire == wireFixed32:
+			var v uint32
+			v, err = r.fixed32()
+			f, hasF = float64(math.Float32frombits(v)), true
+		case field == 3 && wire == wireVarint:
+			var v uint64
+			v, err = r.varint()
+			i, hasI = int64(v), true
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if hasF {
+		n.floats[name] = f
+	}
+	if hasI {
+		n.ints[name] = i
+	}
+	return nil
+}
+
+//nolint:gocyclo // One case per TensorProto field.
+func parseTensor(b []byte) (string, *tensor, error) {
+	var name string
+	var dims []int64
+	var dataType int64
+	var raw []byte
+	var values []float64
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return name, nil, err
+		}
+		switch {
+		case field == 1:
+			dims, err = r.int64s(wire, dims)
+		case field == 2 && wire == wireVarint:
+			var v uint64
+			v, err = r.varint()
+			dataType = int64(v)
+		case field == 4:
+			values, err = r.float32s(wire, values)
+		case field == 8 && wire == wireBytes:
+			name, err = r.string()
+		case field == 9 && wire == wireBytes:
+			raw, err = r.bytes()
+		case field == 10:
+			values, err = r.float64s(wire, values)
+		case field == 14 && wire == wireBytes:
+			return name, nil, fmt.Errorf("%w: external tensor data", ErrUnsupported)
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return name, nil, err
+		}
+	}
+	if len(dims) > 2 {
+		return name, nil, fmt.Errorf("%w: rank %d tensor", ErrUnsupported, len(dims))
+	}
+
+	if raw != nil {
+		switch dataType {
+		case typeFloat:
+			if len(raw)%4 != 0 {
+				return name, nil, errTruncated
+			}
+			for i := 0; i < len(raw); i += 4 {
+				values = append(values, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[i:]))))
+			}
+		case typeDouble:
+			if len(raw)%8 != 0 {
+				return name, nil, errTruncated
+			}
+			for i := 0; i < len(raw); i += 8 {
+				values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(raw[i:])))
+			}
+		}
+	}
+	if dataType != typeFloat && dataType != typeDouble {
+		return name, nil, fmt.Errorf("%w: tensor element type %d", ErrUnsupported, dataType)
+	}
+
+	t := &tensor{shape: make([]int, len(dims)), data: values}
+	size := 1
+	for i, d := range dims {
+		t.shape[i] = int(d)
+		size *= int(d)
+	}
+	if size != len(values) {
+		return name, nil, fmt.Errorf("shape %v holds %d values, got %d", dims, size, len(values))
+	}
+	return name, t, nil
+}
+
+// parseValueInfo returns a ValueInfoProto's name and the fixed size of its
+// last tensor dimension (0 when symbolic or absent).
+func parseValueInfo(b []byte) (string, int, error) {
+	var name string
+	width := 0
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return name, 0, err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			name, err = r.string()
+		case field == 2 && wire == wireBytes:
+			var typ []byte
+			if typ, err = r.bytes(); err == nil {
+				width, err = lastDim(typ)
+			}
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return name, 0, err
+		}
+	}
+	return name, width, nil
+}
+
+// lastDim walks TypeProto.tensor_type.shape.dim and returns the last
+// dim_value.
+func lastDim(typ []byte) (int, error) {
+	// TypeProto.tensor_type (1) -> Tensor.shape (2) -> TensorShapeProto.dim (1).
+	b := typ
+	for _, want := range []int{1, 2} {
+		sub, err := findField(b, want)
+		if err != nil || sub == nil {
+			return 0, err
+		}
+		b = sub
+	}
+	width := 0
+	r := &protoReader{b: b}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		if field != 1 || wire != wireBytes {
+			if err := r.skip(wire); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		dim, err := r.bytes()
+		if err != nil {
+			return 0, err
+		}
+		width = 0
+		d := &protoReader{b: dim}
+		for !d.done() {
+			f, w, err := d.next()
+			if err != nil {
+				return 0, err
+			}
+			if f == 1 && w == wireVarint {
+				v, err := d.varint()
+				if err != nil {
+					return 0, err
+				}
+				width = int(v)
+			} else if err := d.skip(w); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return width, nil
+}
+
+// findField returns the last length-delimited value of field, or nil.
+func findField(b []byte, field int) ([]byte, error) {
+	var found []byte
+	r := &protoReader{b: b}
+	for !r.done() {
+		f, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if f == field && wire == wireBytes {
+			if found, err = r.bytes(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := r.skip(wire); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
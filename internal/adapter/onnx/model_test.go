@@ -0,0 +1,194 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// pb builds protobuf messages for test models.
+type pb []byte
+
+func (b pb) key(field, wire int) pb {
+	return binary.AppendUvarint(b, uint64(field<<3|wire))
+}
+
+func (b pb) varint(field int, v int64) pb {
+	return binary.AppendUvarint(b.key(field, wireVarint), uint64(v))
+}
+
+func (b pb) bytes(field int, v []byte) pb {
+	b = binary.AppendUvarint(b.key(field, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+func (b pb) str(field int, s string) pb { return b.bytes(field, []byte(s)) }
+
+func (b pb) float(field int, v float32) pb {
+	return binary.LittleEndian.AppendUint32(b.key(field, wireFixed32), math.Float32bits(v))
+}
+
+// floatTensor encodes a FLOAT initializer, as raw_data when raw is set and
+// as packed float_data otherwise.
+func floatTensor(name string, dims []int64, values []float32, raw bool) pb {
+	var t pb
+	for _, d := range dims {
+		t = t.varint(1, d)
+	}
+	t = t.varint(2, typeFloat)
+	var data []byte
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+	}
+	if raw {
+		t = t.bytes(9, data)
+	} else {
+		t = t.bytes(4, data)
+	}
+	return t.str(8, name)
+}
+
+func valueInfo(name string, dims ...int64) pb {
+	var shape pb
+	for _, d := range dims {
+		shape = shape.bytes(1, pb(nil).varint(1, d))
+	}
+	tensorType := pb(nil).varint(1, typeFloat).bytes(2, shape)
+	return pb(nil).str(1, name).bytes(2, pb(nil).bytes(1, tensorType))
+}
+
+func nodeProto(op string, inputs, outputs []string, attrs ...pb) pb {
+	var n pb
+	for _, in := range inputs {
+		n = n.str(1, in)
+	}
+	for _, out := range outputs {
+		n = n.str(2, out)
+	}
+	n = n.str(4, op)
+	for _, a := range attrs {
+		n = n.bytes(5, a)
+	}
+	return n
+}
+
+// mlp encodes y = W2·relu(W1·x + b1) + b2 for 7 features and 2 hidden
+// units, using Gemm for the first layer and MatMul+Add for the second.
+func mlp(hiddenOp string) []byte {
+	w1 := []float32{ // 2x7, applied transposed.
+		0, 0, 0, 0, 0, 0, 1,
+		0, 0, 0, 0, 0, 0, -1,
+	}
+	graph := pb(nil).
+		bytes(1, nodeProto("Gemm", []string{"x", "w1", "b1"}, []string{"h"}, pb(nil).str(1, "transB").varint(3, 1))).
+		bytes(1, nodeProto(hiddenOp, []string{"h"}, []string{"a"})).
+		bytes(1, nodeProto("MatMul", []string{"a", "w2"}, []string{"m"})).
+		bytes(1, nodeProto("Add", []string{"m", "b2"}, []string{"y"})).
+		str(2, "residual").
+		bytes(5, floatTensor("w1", []int64{2, 7}, w1, true)).
+		bytes(5, floatTensor("b1", []int64{2}, []float32{0, 0}, false)).
+		bytes(5, floatTensor("w2", []int64{2, 1}, []float32{0.1, 0.2}, true)).
+		bytes(5, floatTensor("b2", []int64{1}, []float32{0.05}, false)).
+		bytes(11, valueInfo("x", 1, 7)).
+		bytes(12, valueInfo("y", 1, 1))
+	meta := pb(nil).str(1, "attribution").str(2, "Test residual, trained on TK 2020-2024")
+	return pb(nil).varint(1, 8).str(2, "test").str(3, "1.0").bytes(7, graph).bytes(14, meta)
+}
+
+func TestParseAndRun(t *testing.T) {
+	m, err := Parse(mlp("Relu"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if m.InputWidth() != 7 || m.Producer != "test 1.0" {
+		t.Errorf("unexpected model: width %d, producer %q", m.InputWidth(), m.Producer)
+	}
+
+	// Positive height activates the first hidden unit, negative the second.
+	for _, tc := range []struct{ height, want float64 }{
+		{1.5, 0.05 + 0.1*1.5},
+		{-2, 0.05 + 0.2*2},
+		{0, 0.05},
+	} {
+		out, err := m.Run([]float64{35, 139, 0, 1, 0, 1, tc.height})
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		if len(out) != 1 || math.Abs(out[0]-tc.want) > 1e-6 {
+			t.Errorf("height %g: expected %g, got %v", tc.height, tc.want, out)
+		}
+	}
+
+	if _, err := m.Run([]float64{1, 2}); err == nil {
+		t.Error("expected error for wrong feature count")
+	}
+}
+
+func TestParseRejectsUnsupportedOperator(t *testing.T) {
+	_, err := Parse(mlp("Softplus"))
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestParseRejectsTruncatedModel(t *testing.T) {
+	data := mlp("Relu")
+	if _, err := Parse(data[:len(data)/2]); err == nil {
+		t.Fatal("expected error for truncated model")
+	}
+}
+
+func TestResidualModelCorrectsHarmonicHeight(t *testing.T) {
+	m, err := Parse(mlp("Relu"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	r, err := NewResidualModel(m, "residual.onnx")
+	if err != nil {
+		t.Fatalf("NewResidualModel: %v", err)
+	}
+	if r.Attribution() != "Test residual, trained on TK 2020-2024" {
+		t.Errorf("unexpected attribution %q", r.Attribution())
+	}
+	if r.Name() != "harmonic_v0+onnx_residual_"+m.Digest[:12] {
+		t.Errorf("unexpected name %q", r.Name())
+	}
+
+	params := domain.PredictionParams{
+		Constituents:    []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: 28.9841042}},
+		MSL:             0.5,
+		NodalCorrection: domain.NewAstronomicalNodalCorrection(),
+		ReferenceTime:   time.Unix(0, 0).UTC(),
+		Model:           r,
+	}
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	harmonic := domain.CalculateTideHeight(at, params)
+
+	if got := params.Height(at); got != harmonic {
+		t.Errorf("expected uncorrected %g without a position, got %g", harmonic, got)
+	}
+
+	params.Position = &domain.Position{Lat: 35, Lon: 139}
+	want := harmonic + 0.05 + 0.1*math.Max(harmonic, 0) + 0.2*math.Max(-harmonic, 0)
+	if got := params.Height(at); math.Abs(got-want) > 1e-6 {
+		t.Errorf("expected corrected %g, got %g", want, got)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	f := Features(time.Date(2025, 1, 1, 6, 0, 0, 0, time.FixedZone("JST", 9*3600)), domain.Position{Lat: 35, Lon: 139}, 1.2)
+	if len(f) != FeatureCount {
+		t.Fatalf("expected %d features, got %d", FeatureCount, len(f))
+	}
+	// 06:00 JST is 21:00 UTC on Dec 31 2024.
+	if math.Abs(f[4]-math.Sin(2*math.Pi*21/24)) > 1e-9 || math.Abs(f[5]-math.Cos(2*math.Pi*21/24)) > 1e-9 {
+		t.Errorf("unexpected hour features %v", f[4:6])
+	}
+	if f[3] < 0.99 || f[6] != 1.2 {
+		t.Errorf("unexpected features %v", f)
+	}
+}
@@ -0,0 +1,212 @@
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// operators maps supported op types to their kernels.
+//
+//nolint:gochecknoglobals // Intentional: static operator table.
+var operators = map[string]func(n *node, args []*tensor) (*tensor, error){
+	"Gemm":      gemm,
+	"MatMul":    matmul,
+	"Add":       elementwise(func(a, b float64) float64 { return a + b }),
+	"Sub":       elementwise(func(a, b float64) float64 { return a - b }),
+	"Mul":       elementwise(func(a, b float64) float64 { return a * b }),
+	"Div":       elementwise(func(a, b float64) float64 { return a / b }),
+	"Relu":      unary(func(_ *node, x float64) float64 { return math.Max(x, 0) }),
+	"LeakyRelu": unary(leakyRelu),
+	"Sigmoid":   unary(func(_ *node, x float64) float64 { return 1 / (1 + math.Exp(-x)) }),
+	"Tanh":      unary(func(_ *node, x float64) float64 { return math.Tanh(x) }),
+	"Identity":  unary(func(_ *node, x float64) float64 { return x }),
+}
+
+func (n *node) eval(args []*tensor) (*tensor, error) {
+	return operators[n.op](n, args)
+}
+
+func (n *node) float(name string, def float64) float64 {
+	if v, ok := n.floats[name]; ok {
+		return v
+	}
+	return def
+}
+
+func arg(args []*tensor, i int) (*tensor, error) {
+	if i >= len(args) || args[i] == nil {
+		return nil, fmt.Errorf("missing input %d", i)
+	}
+	return args[i], nil
+}
+
+// dims2 views a tensor of rank two or less as rows x cols.
+func (t *tensor) dims2() (rows, cols int) {
+	switch len(t.shape) {
+	case 0:
+		return 1, 1
+	case 1:
+		return 1, t.shape[0]
+	default:
+		return t.shape[0], t.shape[1]
+	}
+}
+
+func unary(f func(n *node, x float64) float64) func(*node, []*tensor) (*tensor, error) {
+	return func(n *node, args []*tensor) (*tensor, error) {
+		x, err := arg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		out := &tensor{shape: x.shape, data: make([]float64, len(x.data))}
+		for i, v := range x.data {
+			out.data[i] = f(n, v)
+		}
+		return out, nil
+	}
+}
+
+func leakyRelu(n *node, x float64) float64 {
+	if x < 0 {
+		return n.float("alpha", 0.01) * x
+	}
+	return x
+}
+
+// elementwise applies f elementwise with numpy-style broadcasting.
+func elementwise(f func(a, b float64) float64) func(*node, []*tensor) (*tensor, error) {
+	return func(_ *node, args []*tensor) (*tensor, error) {
+		a, err := arg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := arg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return broadcast(a, b, f)
+	}
+}
+
+func broadcast(a, b *tensor, f func(a, b float64) float64) (*tensor, error) {
+	ar, ac := a.dims2()
+	br, bc := b.dims2()
+	rows, err := broadcastDim(ar, br)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := broadcastDim(ac, bc)
+	if err != nil {
+		return nil, err
+	}
+	shape := a.shape
+	if len(b.shape) > len(shape) {
+		shape = b.shape
+	}
+	shape = append([]int(nil), shape...)
+	switch len(shape) {
+	case 1:
+		shape[0] = cols
+	case 2:
+		shape[0], shape[1] = rows, cols
+	}
+
+	out := &tensor{shape: shape, data: make([]float64, rows*cols)}
+	for i := range rows {
+		for j := range cols {
+			x := a.data[(i%ar)*ac+j%ac]
+			y := b.data[(i%br)*bc+j%bc]
+			out.data[i*cols+j] = f(x, y)
+		}
+	}
+	return out, nil
+}
+
+func broadcastDim(a, b int) (int, error) {
+	switch {
+	case a == b, b == 1:
+		return a, nil
+	case a == 1:
+		return b, nil
+	}
+	return 0, fmt.Errorf("cannot broadcast dimensions %d and %d", a, b)
+}
+
+func matmul(_ *node, args []*tensor) (*tensor, error) {
+	a, err := arg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := arg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return product(a, b, false, false)
+}
+
+// gemm computes alpha*A'*B' + beta*C.
+func gemm(n *node, args []*tensor) (*tensor, error) {
+	a, err := arg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := arg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	out, err := product(a, b, n.ints["transA"] != 0, n.ints["transB"] != 0)
+	if err != nil {
+		return nil, err
+	}
+	alpha, beta := n.float("alpha", 1), n.float("beta", 1)
+	for i := range out.data {
+		out.data[i] *= alpha
+	}
+	if len(args) < 3 || args[2] == nil {
+		return out, nil
+	}
+	scaled := &tensor{shape: args[2].shape, data: make([]float64, len(args[2].data))}
+	for i, v := range args[2].data {
+		scaled.data[i] = beta * v
+	}
+	sum, err := broadcast(out, scaled, func(x, y float64) float64 { return x + y })
+	if err != nil {
+		return nil, err
+	}
+	if len(sum.data) != len(out.data) {
+		return nil, errors.New("bias does not broadcast to the product shape")
+	}
+	return sum, nil
+}
+
+// product multiplies two matrices, optionally transposed.
+func product(a, b *tensor, transA, transB bool) (*tensor, error) {
+	ar, ac := a.dims2()
+	br, bc := b.dims2()
+	aStride, bStride := ac, bc
+	at := func(i, k int) float64 { return a.data[i*aStride+k] }
+	bt := func(k, j int) float64 { return b.data[k*bStride+j] }
+	if transA {
+		ar, ac = ac, ar
+		at = func(i, k int) float64 { return a.data[k*aStride+i] }
+	}
+	if transB {
+		br, bc = bc, br
+		bt = func(k, j int) float64 { return b.data[j*bStride+k] }
+	}
+	if ac != br {
+		return nil, fmt.Errorf("cannot multiply %dx%d by %dx%d", ar, ac, br, bc)
+	}
+	out := &tensor{shape: []int{ar, bc}, data: make([]float64, ar*bc)}
+	for i := range ar {
+		for j := range bc {
+			var s float64
+			for k := range ac {
+				s += at(i, k) * bt(k, j)
+			}
+			out.data[i*bc+j] = s
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,157 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// protoReader decodes the protobuf wire format field by field.
+type protoReader struct {
+	b []byte
+}
+
+func (r *protoReader) done() bool { return len(r.b) == 0 }
+
+// next reads a field key.
+func (r *protoReader) next() (field, wire int, err error) {
+	key, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(key >> 3), int(key & 7), nil
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	r.b = r.b[n:]
+	return v, nil
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(r.b)) {
+		return nil, errTruncated
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b, nil
+}
+
+func (r *protoReader) fixed32() (uint32, error) {
+	if len(r.b) < 4 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v, nil
+}
+
+func (r *protoReader) fixed64() (uint64, error) {
+	if len(r.b) < 8 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint64(r.b)
+	r.b = r.b[8:]
+	return v, nil
+}
+
+// skip discards a field value of the given wire type.
+func (r *protoReader) skip(wire int) error {
+	var err error
+	switch wire {
+	case wireVarint:
+		_, err = r.varint()
+	case wireFixed64:
+		_, err = r.fixed64()
+	case wireBytes:
+		_, err = r.bytes()
+	case wireFixed32:
+		_, err = r.fixed32()
+	default:
+		err = fmt.Errorf("unsupported protobuf wire type %d", wire)
+	}
+	return err
+}
+
+// string reads a length-delimited string field.
+func (r *protoReader) string() (string, error) {
+	b, err := r.bytes()
+	return string(b), err
+}
+
+// int64s reads a repeated int64 field, packed or not.
+func (r *protoReader) int64s(wire int, out []int64) ([]int64, error) {
+	if wire == wireVarint {
+		v, err := r.varint()
+		return append(out, int64(v)), err
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return out, err
+	}
+	packed := &protoReader{b: b}
+	for !packed.done() {
+		v, err := packed.varint()
+		if err != nil {
+			return out, err
+		}
+		out = append(out, int64(v))
+	}
+	return out, nil
+}
+
+// float32s reads a repeated float field, packed or not.
+func (r *protoReader) float32s(wire int, out []float64) ([]float64, error) {
+	if wire == wireFixed32 {
+		v, err := r.fixed32()
+		return append(out, float64(math.Float32frombits(v))), err
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return out, err
+	}
+	if len(b)%4 != 0 {
+		return out, errTruncated
+	}
+	for i := 0; i < len(b); i += 4 {
+		out = append(out, float64(math.Float32frombits(binary.LittleEndian.Uint32(b[i:]))))
+	}
+	return out, nil
+}
+
+// float64s reads a repeated double field, packed or not.
+func (r *protoReader) float64s(wire int, out []float64) ([]float64, error) {
+	if wire == wireFixed64 {
+		v, err := r.fixed64()
+		return append(out, math.Float64frombits(v)), err
+	}
+	b, err := r.bytes()
+	if err != nil {
+		return out, err
+	}
+	if len(b)%8 != 0 {
+		return out, errTruncated
+	}
+	for i := 0; i < len(b); i += 8 {
+		out = append(out, math.Float64frombits(binary.LittleEndian.Uint64(b[i:])))
+	}
+	return out, nil
+}
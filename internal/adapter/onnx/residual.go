@@ -0,0 +1,105 @@
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// ResidualModelKey selects the harmonic sum corrected by a residual model.
+const ResidualModelKey = "onnx_residual"
+
+// FeatureCount is the number of inputs a residual model takes, in order:
+//
+//	0 latitude (deg)
+//	1 longitude (deg)
+//	2 sin(2π·day of year/365.25), UTC
+//	3 cos(2π·day of year/365.25), UTC
+//	4 sin(2π·hour/24), UTC
+//	5 cos(2π·hour/24), UTC
+//	6 harmonic height (m, above MSL plus any datum offset)
+//
+// The model's first output is the residual in meters added to the harmonic
+// height.
+const FeatureCount = 7
+
+// ResidualModel corrects the harmonic sum with a residual learned offline
+// from observation archives. Predictions without a known position are left
+// uncorrected.
+type ResidualModel struct {
+	model       *Model
+	name        string
+	attribution string
+}
+
+// NewResidualModel wraps a loaded model; source names the model file in the
+// default attribution.
+func NewResidualModel(m *Model, source string) (*ResidualModel, error) {
+	if w := m.InputWidth(); w != 0 && w != FeatureCount {
+		return nil, fmt.Errorf("residual model takes %d features, want %d", w, FeatureCount)
+	}
+	out, err := m.Run(make([]float64, FeatureCount))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, errors.New("residual model has an empty output")
+	}
+
+	attribution := m.Metadata["attribution"]
+	if attribution == "" {
+		attribution = m.Doc
+	}
+	if attribution == "" {
+		attribution = fmt.Sprintf("Residual correction %s (sha256 %s)", source, m.Digest[:12])
+	}
+	return &ResidualModel{
+		model:       m,
+		name:        "harmonic_v0+onnx_residual_" + m.Digest[:12],
+		attribution: attribution,
+	}, nil
+}
+
+// Name identifies the base model and the residual model digest.
+func (r *ResidualModel) Name() string { return r.name }
+
+// Attribution credits the residual model in response metadata.
+func (r *ResidualModel) Attribution() string { return r.attribution }
+
+// HeightAt returns the harmonic height at t plus the model residual.
+func (r *ResidualModel) HeightAt(t time.Time, params domain.PredictionParams) float64 {
+	h := domain.CalculateTideHeight(t, params)
+	if params.Position == nil {
+		return h
+	}
+	out, err := r.model.Run(Features(t, *params.Position, h))
+	if err != nil || math.IsNaN(out[0]) || math.IsInf(out[0], 0) {
+		// Shapes are checked at load time; a non-finite residual is dropped.
+		return h
+	}
+	return h + out[0]
+}
+
+// Features returns the residual model inputs for a prediction.
+func Features(t time.Time, pos domain.Position, height float64) []float64 {
+	t = t.UTC()
+	day := float64(t.YearDay()-1) + dayFraction(t)
+	hour := 24 * dayFraction(t)
+	return []float64{
+		pos.Lat,
+		pos.Lon,
+		math.Sin(2 * math.Pi * day / 365.25),
+		math.Cos(2 * math.Pi * day / 365.25),
+		math.Sin(2 * math.Pi * hour / 24),
+		math.Cos(2 * math.Pi * hour / 24),
+		height,
+	}
+}
+
+func dayFraction(t time.Time) float64 {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return t.Sub(midnight).Hours() / 24
+}
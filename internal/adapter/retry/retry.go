@@ -0,0 +1,189 @@
+// Package retry retries file reads that fail with transient I/O errors, such
+// as EIO from a GCS FUSE mount, with jittered exponential backoff.
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	"go.ngs.io/tides-api/internal/metrics"
+)
+
+// NetCDF library error codes treated as transient.
+const (
+	ncEIO     = -68  // NC_EIO: generic I/O error.
+	ncEHDFERR = -101 // NC_EHDFERR: HDF5 error, typically a failed read.
+)
+
+// Policy configures retries.
+type Policy struct {
+	Attempts  int           // Total attempts including the first; 1 disables retries.
+	BaseDelay time.Duration // Wait before the first retry; doubled per retry.
+	MaxDelay  time.Duration // Cap on a single wait.
+	Jitter    float64       // Fraction of each wait that is randomized (0-1).
+}
+
+// DefaultPolicy retries twice after 100 ms and 200 ms (±50%).
+func DefaultPolicy() Policy {
+	return Policy{
+		Attempts:  3,
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  2 * time.Second,
+		Jitter:    0.5,
+	}
+}
+
+// Validate checks the policy bounds.
+func (p Policy) Validate() error {
+	switch {
+	case p.Attempts < 1:
+		return fmt.Errorf("attempts must be at least 1, got %d", p.Attempts)
+	case p.BaseDelay < 0 || p.MaxDelay < 0:
+		return errors.New("delays must not be negative")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("jitter must be between 0 and 1, got %g", p.Jitter)
+	}
+	return nil
+}
+
+// Stats counts retries of one operation.
+type Stats struct {
+	Retries   int64 `json:"retries"`   // Retry attempts made.
+	Recovered int64 `json:"recovered"` // Operations that succeeded after retrying.
+	Exhausted int64 `json:"exhausted"` // Operations that still failed after all attempts.
+}
+
+// Retrier runs operations under a policy and counts retries per operation.
+type Retrier struct {
+	mu     sync.Mutex
+	policy Policy
+	stats  map[string]*Stats
+	sleep  func(time.Duration)
+}
+
+// New creates a retrier.
+func New(p Policy) *Retrier {
+	return &Retrier{policy: p, stats: make(map[string]*Stats), sleep: time.Sleep}
+}
+
+//nolint:gochecknoglobals // Intentional: shared by all file-backed stores.
+var defaultRetrier = New(DefaultPolicy())
+
+// Default returns the retrier used by the NetCDF stores.
+func Default() *Retrier {
+	return defaultRetrier
+}
+
+// Do runs fn with the default retrier.
+func Do(op string, fn func() error) error {
+	return defaultRetrier.Do(op, fn)
+}
+
+// SetPolicy replaces the policy.
+func (r *Retrier) SetPolicy(p Policy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = p
+}
+
+// Do runs fn, retrying while it fails with a transient error. op names the
+// operation in stats and read duration metrics (e.g., "fes", "gebco"). The
+// last error is returned.
+func (r *Retrier) Do(op string, fn func() error) (err error) {
+	r.mu.Lock()
+	p := r.policy
+	r.mu.Unlock()
+
+	start := time.Now()
+	defer func() { metrics.ObserveRead(op, start, err) }()
+	err = fn()
+	retried := false
+	for attempt := 1; err != nil && attempt < p.Attempts && IsTransient(err); attempt++ {
+		r.count(op, func(s *Stats) { s.Retries++ })
+		retried = true
+		r.sleep(p.delay(attempt))
+		err = fn()
+	}
+	switch {
+	case !retried:
+	case err == nil:
+		r.count(op, func(s *Stats) { s.Recovered++ })
+	default:
+		r.count(op, func(s *Stats) { s.Exhausted++ })
+	}
+	return err
+}
+
+// delay returns the jittered wait before retry n (1-based).
+func (p Policy) delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxDelay)
+	if p.Jitter > 0 {
+		//nolint:gosec // G404: Jitter does not need a secure source.
+		d = time.Duration(float64(d) * (1 - p.Jitter + 2*p.Jitter*rand.Float64()))
+	}
+	return d
+}
+
+func (r *Retrier) count(op string, update func(*Stats)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats[op]
+	if s == nil {
+		s = &Stats{}
+		r.stats[op] = s
+	}
+	update(s)
+}
+
+// Stats returns the counters per operation.
+func (r *Retrier) Stats() map[string]Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]Stats, len(r.stats))
+	for op, s := range r.stats {
+		out[op] = *s
+	}
+	return out
+}
+
+// transientErrnos are system errors a remote file system may return for an
+// otherwise readable file.
+//
+//nolint:gochecknoglobals // Intentional: fixed error classification.
+var transientErrnos = []error{
+	syscall.EIO,
+	syscall.EAGAIN,
+	syscall.EINTR,
+	syscall.ETIMEDOUT,
+	syscall.ESTALE,
+	syscall.ENOTCONN,
+}
+
+// IsTransient reports whether err may succeed on retry. NetCDF errors with
+// a positive code are system errno values.
+func IsTransient(err error) bool {
+	var ncErr netcdf.Error
+	if errors.As(err, &ncErr) {
+		code := int(ncErr)
+		if code <= 0 {
+			return code == ncEIO || code == ncEHDFERR
+		}
+		err = syscall.Errno(code)
+	}
+	for _, e := range transientErrnos {
+		if errors.Is(err, e) {
+			return true
+		}
+	}
+	return false
+}
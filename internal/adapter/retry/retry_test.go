@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+func newTestRetrier(attempts int) (*Retrier, *[]time.Duration) {
+	var waits []time.Duration
+	r := New(Policy{Attempts: attempts, BaseDelay: 100 * time.Millisecond, MaxDelay: 250 * time.Millisecond})
+	r.sleep = func(d time.Duration) { waits = append(waits, d) }
+	return r, &waits
+}
+
+func TestDoRecoversFromTransientError(t *testing.T) {
+	r, waits := newTestRetrier(4)
+	calls := 0
+	err := r.Do("fes", func() error {
+		calls++
+		if calls < 4 {
+			return fmt.Errorf("failed to open NetCDF file: %w", netcdf.Error(int(syscall.EIO)))
+		}
+		return nil
+	})
+	if err != nil || calls != 4 {
+		t.Fatalf("expected success on 4th call, got %v after %d calls", err, calls)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 250 * time.Millisecond}
+	if fmt.Sprint(*waits) != fmt.Sprint(want) {
+		t.Errorf("expected waits %v, got %v", want, *waits)
+	}
+	if s := r.Stats()["fes"]; s != (Stats{Retries: 3, Recovered: 1}) {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestDoGivesUpAndSkipsPermanentErrors(t *testing.T) {
+	r, _ := newTestRetrier(3)
+	calls := 0
+	err := r.Do("gebco", func() error {
+		calls++
+		return &os.PathError{Op: "read", Path: "x.nc", Err: syscall.EIO}
+	})
+	if !errors.Is(err, syscall.EIO) || calls != 3 {
+		t.Fatalf("expected EIO after 3 calls, got %v after %d", err, calls)
+	}
+	if s := r.Stats()["gebco"]; s != (Stats{Retries: 2, Exhausted: 1}) {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	calls = 0
+	err = r.Do("mss", func() error {
+		calls++
+		return netcdf.Error(int(syscall.ENOENT))
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("expected no retry of a missing file, got %v after %d calls", err, calls)
+	}
+	if _, ok := r.Stats()["mss"]; ok {
+		t.Error("expected no stats without retries")
+	}
+}
+
+func TestPolicyJitterBounds(t *testing.T) {
+	p := Policy{Attempts: 3, BaseDelay: time.Second, MaxDelay: time.Second, Jitter: 0.5}
+	for range 100 {
+		if d := p.delay(1); d < 500*time.Millisecond || d > 1500*time.Millisecond {
+			t.Fatalf("delay %v outside ±50%% of 1s", d)
+		}
+	}
+	if err := (Policy{Attempts: 0}).Validate(); err == nil {
+		t.Error("expected zero attempts to be invalid")
+	}
+}
@@ -0,0 +1,57 @@
+// Package shadow replays API requests against another server instance.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxResponseBytes bounds the shadow response read for comparison.
+const maxResponseBytes = 32 << 20
+
+// Client sends requests to a shadow instance.
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient creates a client for the instance at baseURL
+// (e.g., https://canary.example.com).
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Target returns the base URL requests are replayed against.
+func (c *Client) Target() string {
+	return c.baseURL
+}
+
+// Replay sends method and target (path and query) with header and body to
+// the shadow instance and returns its status and body.
+func (c *Client) Replay(method, target string, header http.Header, body []byte) (int, []byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+target, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header = header.Clone()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("shadow request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("failed to read shadow response: %w", err)
+	}
+	return resp.StatusCode, b, nil
+}
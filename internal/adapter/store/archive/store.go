@@ -0,0 +1,220 @@
+// Package archive provides a file-based archive of hourly tide observations.
+package archive
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+//nolint:gochecknoglobals // Intentional: Fixed archive file header.
+var header = []string{"time", "height_m", "synthetic"}
+
+// Store archives observations as one CSV file per station and UTC year:
+// <dir>/<STATION>/<year>.csv with columns time (RFC3339, UTC), height_m and
+// synthetic (1 for gap-filled values). Files without the synthetic column
+// are read as fully observed.
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore creates an archive rooted at dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Append merges observed levels into the archive, replacing existing values
+// (including synthetic ones) at the same timestamp. It returns the number of
+// timestamps not previously archived as observed.
+func (s *Store) Append(station string, levels []domain.TideLevel) (int, error) {
+	return s.merge(station, levels, false)
+}
+
+// Fill archives synthetic levels at timestamps that have no observation.
+// Observed values are never overwritten. It returns the number of levels written.
+func (s *Store) Fill(station string, levels []domain.TideLevel) (int, error) {
+	return s.merge(station, levels, true)
+}
+
+func (s *Store) merge(station string, levels []domain.TideLevel, synthetic bool) (int, error) {
+	station, err := normalizeStation(station)
+	if err != nil {
+		return 0, err
+	}
+
+	byYear := make(map[int][]domain.TideLevel)
+	for _, l := range levels {
+		y := l.Time.UTC().Year()
+		byYear[y] = append(byYear[y], l)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	written := 0
+	for year, batch := range byYear {
+		path := s.path(station, year)
+		existing, err := readFile(path)
+		if err != nil {
+			return written, err
+		}
+		merged := make(map[int64]domain.ArchivedLevel, len(existing)+len(batch))
+		for _, l := range existing {
+			merged[l.Time.Unix()] = l
+		}
+		for _, l := range batch {
+			key := l.Time.Unix()
+			prev, ok := merged[key]
+			if synthetic && ok && !prev.Synthetic {
+				continue
+			}
+			if synthetic || !ok || prev.Synthetic {
+				written++
+			}
+			merged[key] = domain.ArchivedLevel{Time: l.Time.UTC(), HeightM: l.HeightM, Synthetic: synthetic}
+		}
+		out := make([]domain.ArchivedLevel, 0, len(merged))
+		for _, l := range merged {
+			out = append(out, l)
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+		if err := writeFile(path, out); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Query returns archived observations for station in [start, end], in time order.
+func (s *Store) Query(station string, start, end time.Time) ([]domain.ArchivedLevel, error) {
+	station, err := normalizeStation(station)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]domain.ArchivedLevel, 0)
+	for year := start.UTC().Year(); year <= end.UTC().Year(); year++ {
+		levels, err := readFile(s.path(station, year))
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range levels {
+			if !l.Time.Before(start) && !l.Time.After(end) {
+				out = append(out, l)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *Store) path(station string, year int) string {
+	return filepath.Join(s.dir, station, fmt.Sprintf("%d.csv", year))
+}
+
+// normalizeStation upper-cases the code and rejects values unsafe as a path element.
+func normalizeStation(station string) (string, error) {
+	station = strings.ToUpper(strings.TrimSpace(station))
+	if station == "" || strings.ContainsAny(station, `/\.`) {
+		return "", fmt.Errorf("invalid station code: %q", station)
+	}
+	return station, nil
+}
+
+// readFile reads an archive file; a missing file yields no observations.
+func readFile(path string) ([]domain.ArchivedLevel, error) {
+	//nolint:gosec // G304: Path built from configured archive dir and validated station code.
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	if _, err := reader.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read archive header: %w", err)
+	}
+
+	levels := make([]domain.ArchivedLevel, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive record: %w", err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("invalid archive record: expected at least 2 columns, got %d", len(record))
+		}
+		t, err := time.Parse(time.RFC3339, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive time %q: %w", record[0], err)
+		}
+		h, err := strconv.ParseFloat(record[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive height %q: %w", record[1], err)
+		}
+		synthetic := len(record) > 2 && record[2] == "1"
+		levels = append(levels, domain.ArchivedLevel{Time: t.UTC(), HeightM: h, Synthetic: synthetic})
+	}
+	return levels, nil
+}
+
+// writeFile atomically replaces an archive file.
+func writeFile(path string, levels []domain.ArchivedLevel) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*.csv")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := csv.NewWriter(tmp)
+	_ = w.Write(header)
+	for _, l := range levels {
+		flag := "0"
+		if l.Synthetic {
+			flag = "1"
+		}
+		_ = w.Write([]string{
+			l.Time.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(l.HeightM, 'f', 3, 64),
+			flag,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace archive file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestAppendAndQuery(t *testing.T) {
+	s := NewStore(t.TempDir())
+	base := time.Date(2024, 12, 31, 22, 0, 0, 0, time.UTC)
+	levels := []domain.TideLevel{
+		{Time: base, HeightM: 1.0},
+		{Time: base.Add(time.Hour), HeightM: 1.1},
+		{Time: base.Add(2 * time.Hour), HeightM: 1.2}, // Next year's file.
+	}
+
+	added, err := s.Append("tk", levels)
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if added != 3 {
+		t.Fatalf("added = %d, want 3", added)
+	}
+
+	// Re-appending overlapping data only counts new timestamps and overwrites values.
+	added, err = s.Append("TK", []domain.TideLevel{
+		{Time: base.Add(time.Hour), HeightM: 1.15},
+		{Time: base.Add(3 * time.Hour), HeightM: 1.3},
+	})
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if added != 1 {
+		t.Fatalf("added = %d, want 1", added)
+	}
+
+	got, err := s.Query("TK", base.Add(time.Hour), base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	want := []float64{1.15, 1.2, 1.3}
+	if len(got) != len(want) {
+		t.Fatalf("got %d levels, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].HeightM != w {
+			t.Errorf("level %d = %.3f, want %.3f", i, got[i].HeightM, w)
+		}
+	}
+}
+
+func TestQueryMissingStation(t *testing.T) {
+	s := NewStore(t.TempDir())
+	got, err := s.Query("XX", time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("got %d levels, want 0", len(got))
+	}
+}
+
+func TestInvalidStation(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if _, err := s.Append("../etc", nil); err == nil {
+		t.Fatal("expected error for path-like station code")
+	}
+}
+
+func TestFillNeverOverwritesObservations(t *testing.T) {
+	s := NewStore(t.TempDir())
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := s.Append("KZ", []domain.TideLevel{{Time: base, HeightM: 1.0}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	filled, err := s.Fill("KZ", []domain.TideLevel{
+		{Time: base, HeightM: 9.9},
+		{Time: base.Add(time.Hour), HeightM: 1.1},
+	})
+	if err != nil {
+		t.Fatalf("fill: %v", err)
+	}
+	if filled != 1 {
+		t.Fatalf("filled = %d, want 1", filled)
+	}
+
+	// A later real observation replaces the synthetic value.
+	added, err := s.Append("KZ", []domain.TideLevel{{Time: base.Add(time.Hour), HeightM: 1.2}})
+	if err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if added != 1 {
+		t.Fatalf("added = %d, want 1", added)
+	}
+
+	got, err := s.Query("KZ", base, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(got) != 2 || got[0].HeightM != 1.0 || got[1].HeightM != 1.2 || got[0].Synthetic || got[1].Synthetic {
+		t.Fatalf("unexpected archive contents: %+v", got)
+	}
+}
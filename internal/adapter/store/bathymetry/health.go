@@ -0,0 +1,63 @@
+package bathymetry
+
+import (
+	"time"
+
+	"go.ngs.io/tides-api/internal/adapter/circuit"
+)
+
+// Data files backing a LocalStore, as reported in its health.
+const (
+	ComponentGEBCO = "gebco"
+	ComponentMSS   = "mss"
+	ComponentGeoid = "geoid"
+)
+
+const (
+	// minRetryBackoff is the wait before retrying a failed data file.
+	minRetryBackoff = 5 * time.Second
+	// maxRetryBackoff caps the doubling retry wait.
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// ComponentHealth is the health of one data file backing a store.
+type ComponentHealth struct {
+	Component string        `json:"component"`
+	Healthy   bool          `json:"healthy"`
+	State     circuit.State `json:"state"`
+	Error     string        `json:"error,omitempty"`
+	Failures  int           `json:"consecutive_failures,omitempty"`
+	Since     time.Time     `json:"since,omitzero"`      // First failure of the current outage.
+	NextRetry time.Time     `json:"next_retry,omitzero"` // Earliest reload attempt.
+}
+
+// HealthReporter is implemented by stores that track the health of their
+// data files.
+type HealthReporter interface {
+	Health() []ComponentHealth
+}
+
+// newComponentBreaker opens on the first failure, so an unreadable file
+// (e.g., a FUSE mount hiccup) is retried with backoff rather than on every
+// request.
+func newComponentBreaker(name string, now func() time.Time) *circuit.Breaker {
+	return circuit.New(name, circuit.Config{
+		Threshold:   1,
+		MinCooldown: minRetryBackoff,
+		MaxCooldown: maxRetryBackoff,
+		Now:         now,
+	})
+}
+
+func componentHealth(b *circuit.Breaker) ComponentHealth {
+	st := b.Status()
+	return ComponentHealth{
+		Component: st.Name,
+		Healthy:   st.Failures == 0,
+		State:     st.State,
+		Error:     st.Error,
+		Failures:  st.Failures,
+		Since:     st.Since,
+		NextRetry: st.RetryAt,
+	}
+}
@@ -0,0 +1,110 @@
+package bathymetry
+
+import (
+	"math"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// landPathStepKm is the spacing of elevation samples along a path,
+	// about the size of a GEBCO 15" cell.
+	landPathStepKm = 0.5
+	// landPathEndKm is the length at each end of a path where land is
+	// ignored: tide gauges and harbor locations often fall on coastal land
+	// cells.
+	landPathEndKm = 2.0
+	// minLandRunKm is the shortest stretch of land blocking a path, so
+	// breakwaters and islets below the grid resolution do not.
+	minLandRunKm = 1.0
+
+	earthRadiusKm = 6371.0
+)
+
+// LandPathChecker is implemented by stores that can tell whether the
+// straight path between two locations crosses land.
+type LandPathChecker interface {
+	// CrossesLand reports whether the great-circle path between two
+	// locations crosses land; ok is false when elevations are unavailable
+	// along it.
+	CrossesLand(lat1, lon1, lat2, lon2 float64) (crosses, ok bool)
+}
+
+// CrossesLand samples the depth grid every landPathStepKm along the
+// great-circle path from (lat1, lon1) to (lat2, lon2) and reports whether
+// it crosses at least minLandRunKm of land away from its ends. The grid
+// around (lat1, lon1) is loaded if needed; ok is false without GEBCO data
+// or when the path leaves the loaded grid.
+func (s *LocalStore) CrossesLand(lat1, lon1, lat2, lon2 float64) (crosses, ok bool) {
+	if s.gebcoPath == "" {
+		return false, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.depthGrid == nil || !s.depthBounds.contains(lat1, lon1) {
+		if reason := s.load(s.gebcoHealth, func() error { return s.loadDepthGrid(lat1, lon1) }); reason != "" {
+			return false, false
+		}
+	}
+
+	length := greatCircleKm(lat1, lon1, lat2, lon2)
+	steps := int(math.Ceil(length / landPathStepKm))
+	var landRun float64
+	for i := 1; i < steps; i++ {
+		along := length * float64(i) / float64(steps)
+		if along < landPathEndKm || length-along < landPathEndKm {
+			landRun = 0
+			continue
+		}
+		lat, lon := intermediatePoint(lat1, lon1, lat2, lon2, float64(i)/float64(steps))
+		if !s.depthBounds.contains(lat, lon) {
+			return false, false
+		}
+		elevation, err := s.depthGrid.InterpolateAt(normalizeLonForAxis(s.depthGrid.X, lon), lat)
+		if err != nil {
+			return false, false
+		}
+		if s.vertical == PositiveDown {
+			elevation = -elevation
+		}
+		if elevation <= 0 {
+			landRun = 0
+			continue
+		}
+		if landRun += length / float64(steps); landRun >= minLandRunKm {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// greatCircleKm returns the great-circle distance between two locations.
+func greatCircleKm(lat1, lon1, lat2, lon2 float64) float64 {
+	return earthRadiusKm * centralAngle(lat1, lon1, lat2, lon2)
+}
+
+// centralAngle returns the angle in radians between two locations, seen
+// from the center of the Earth (haversine formula).
+func centralAngle(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := domain.Deg2Rad(lat1), domain.Deg2Rad(lat2)
+	dPhi, dLambda := phi2-phi1, domain.Deg2Rad(lon2-lon1)
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// intermediatePoint returns the location at fraction f of the great-circle
+// path between two locations.
+func intermediatePoint(lat1, lon1, lat2, lon2, f float64) (lat, lon float64) {
+	delta := centralAngle(lat1, lon1, lat2, lon2)
+	if delta == 0 {
+		return lat1, lon1
+	}
+	phi1, lambda1 := domain.Deg2Rad(lat1), domain.Deg2Rad(lon1)
+	phi2, lambda2 := domain.Deg2Rad(lat2), domain.Deg2Rad(lon2)
+	a := math.Sin((1-f)*delta) / math.Sin(delta)
+	b := math.Sin(f*delta) / math.Sin(delta)
+	x := a*math.Cos(phi1)*math.Cos(lambda1) + b*math.Cos(phi2)*math.Cos(lambda2)
+	y := a*math.Cos(phi1)*math.Sin(lambda1) + b*math.Cos(phi2)*math.Sin(lambda2)
+	z := a*math.Sin(phi1) + b*math.Sin(phi2)
+	return domain.Rad2Deg(math.Atan2(z, math.Hypot(x, y))), domain.Rad2Deg(math.Atan2(y, x))
+}
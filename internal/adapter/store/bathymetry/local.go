@@ -2,24 +2,57 @@
 package bathymetry
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/fhs/go-netcdf/netcdf"
 
+	"go.ngs.io/tides-api/internal/adapter/circuit"
 	"go.ngs.io/tides-api/internal/adapter/geoid"
 	"go.ngs.io/tides-api/internal/adapter/interp"
-	"go.ngs.io/tides-api/internal/domain"
+	"go.ngs.io/tides-api/internal/adapter/ncfill"
+	"go.ngs.io/tides-api/internal/adapter/retry"
+	"go.ngs.io/tides-api/internal/metrics"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
+// VerticalConvention describes the sign of values in a bathymetry dataset.
+type VerticalConvention string
+
+const (
+	// PositiveUp datasets store elevation: negative below sea level (e.g., GEBCO).
+	PositiveUp VerticalConvention = "positive_up"
+	// PositiveDown datasets store depth: positive below sea level.
+	PositiveDown VerticalConvention = "positive_down"
+)
+
+// ParseVerticalConvention parses "positive_up" or "positive_down".
+func ParseVerticalConvention(s string) (VerticalConvention, error) {
+	switch c := VerticalConvention(strings.ToLower(s)); c {
+	case PositiveUp, PositiveDown:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown vertical convention %q (want positive_up or positive_down)", s)
+	}
+}
+
 // LocalStore loads bathymetry and MSL data from local NetCDF files.
 // These files can be local disk files or GCS FUSE-mounted files.
 type LocalStore struct {
 	gebcoPath  string // Path to GEBCO NetCDF file (e.g., /mnt/bathymetry/gebco_2024.nc).
 	mssPath    string // Path to MSS NetCDF file (e.g., /mnt/bathymetry/dtu21_mss.nc).
 	geoidStore *geoid.Store
+	timeIndex  int // Index along leading (e.g., time) dimensions of 3D variables.
+	vertical   VerticalConvention
+
+	// Regions of the grids loaded around a location.
+	gebcoMargin interp.SubsetMargin
+	mssMargin   interp.SubsetMargin
 
 	// Cached grids (loaded on demand).
 	depthGrid   *interp.Grid2D
@@ -27,6 +60,12 @@ type LocalStore struct {
 	mslGrid     *interp.Grid2D
 	mslBounds   *gridBounds
 	mu          sync.RWMutex
+
+	// Data file health, reported without waiting on a slow load holding mu.
+	gebcoHealth *circuit.Breaker
+	mssHealth   *circuit.Breaker
+	geoidHealth *circuit.Breaker
+	now         func() time.Time
 }
 
 type gridBounds struct {
@@ -100,104 +139,226 @@ func normalizeLonForAxis(lons []float64, lon float64) float64 {
 // NewLocalStore creates a new local file-based bathymetry store.
 // Paths can point to GCS FUSE-mounted files (e.g., /mnt/bathymetry/data.nc).
 func NewLocalStore(gebcoPath, mssPath string, geoidStore *geoid.Store) *LocalStore {
-	return &LocalStore{
+	s := &LocalStore{
 		gebcoPath:  gebcoPath,
 		mssPath:    mssPath,
 		geoidStore: geoidStore,
+		vertical:   PositiveUp,
+		now:        time.Now,
+
+		gebcoMargin: interp.DefaultSubsetMargin,
+		mssMargin:   interp.DefaultSubsetMargin,
 	}
+	now := func() time.Time { return s.now() }
+	s.gebcoHealth = newComponentBreaker(ComponentGEBCO, now)
+	s.mssHealth = newComponentBreaker(ComponentMSS, now)
+	s.geoidHealth = newComponentBreaker(ComponentGeoid, now)
+	return s
+}
+
+// SetVerticalConvention sets the sign convention of the bathymetry dataset
+// (default PositiveUp).
+func (s *LocalStore) SetVerticalConvention(c VerticalConvention) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vertical = c
 }
 
-// GetMetadata retrieves bathymetry and MSL data for a location.
-func (s *LocalStore) GetMetadata(lat, lon float64) (*domain.LocationMetadata, error) {
+// SetTimeIndex selects the index along leading dimensions (e.g., time) of
+// variables with more than two dimensions. Singleton leading dimensions are
+// always read at index 0. Cached grids are dropped.
+func (s *LocalStore) SetTimeIndex(i int) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.timeIndex = i
+	s.mslGrid, s.mslBounds = nil, nil
+	s.depthGrid, s.depthBounds = nil, nil
+}
 
-	// Load MSL grid if needed.
+// SetSubsetMargins sets the regions of the GEBCO and MSS grids loaded
+// around a location (default interp.DefaultSubsetMargin). Cached grids are
+// dropped.
+func (s *LocalStore) SetSubsetMargins(gebco, mss interp.SubsetMargin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gebcoMargin, s.mssMargin = gebco, mss
+	s.mslGrid, s.mslBounds = nil, nil
+	s.depthGrid, s.depthBounds = nil, nil
+}
+
+// GetMetadata retrieves bathymetry and MSL data for a location. Datasets
+// that fail to load are retried with backoff and listed in Degraded; when no
+// dataset contributes, metadata carries only the degradation reasons.
+func (s *LocalStore) GetMetadata(lat, lon float64) (metadata *domain.LocationMetadata, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer func() { metrics.BathymetryLookups.With(lookupResult(metadata)).Inc() }()
+
+	var degraded []string
+
+	// Load MSL grid if needed. A stale grid elsewhere is not used.
+	mslGrid := s.mslGrid
 	if s.mssPath != "" && (s.mslGrid == nil || !s.mslBounds.contains(lat, lon)) {
-		if err := s.loadMSSGrid(lat, lon); err != nil {
-			// MSL is optional - log warning but continue.
-			fmt.Fprintf(os.Stderr, "Warning: failed to load MSS grid: %v\n", err)
+		// MSL is optional - flag degradation but continue.
+		if reason := s.load(s.mssHealth, func() error { return s.loadMSSGrid(lat, lon) }); reason != "" {
+			degraded = append(degraded, reason)
+			mslGrid = nil
+		} else {
+			mslGrid = s.mslGrid
 		}
 	}
 
 	// Load depth grid if needed.
+	depthGrid := s.depthGrid
 	if s.gebcoPath != "" && (s.depthGrid == nil || !s.depthBounds.contains(lat, lon)) {
-		if err := s.loadDepthGrid(lat, lon); err != nil {
-			// Depth is optional - log warning but continue.
-			fmt.Fprintf(os.Stderr, "Warning: failed to load depth grid: %v\n", err)
+		// Depth is optional - flag degradation but continue.
+		if reason := s.load(s.gebcoHealth, func() error { return s.loadDepthGrid(lat, lon) }); reason != "" {
+			degraded = append(degraded, reason)
+			depthGrid = nil
+		} else {
+			depthGrid = s.depthGrid
 		}
 	}
 
-	// If no grids are available, return nil.
-	if s.mslGrid == nil && s.depthGrid == nil {
+	// If no grids are available, return nil (or only the degradation).
+	if mslGrid == nil && depthGrid == nil {
+		if len(degraded) > 0 {
+			return &domain.LocationMetadata{Degraded: degraded}, nil
+		}
 		return nil, nil
 	}
 
-	metadata := &domain.LocationMetadata{
-		MSL:        0.0,
-		DatumName:  "EGM2008",
-		SourceName: "Local/GCS FUSE",
+	metadata = &domain.LocationMetadata{
+		MSL:      0.0,
+		Datum:    domain.DatumEGM2008,
+		Degraded: degraded,
 	}
 
-	// Interpolate MSL.
-	//nolint:nestif // Grid interpolation logic with multiple error paths.
-	if s.mslGrid != nil {
-		lonMSL := normalizeLonForAxis(s.mslGrid.X, lon)
-		msl, err := s.mslGrid.InterpolateAt(lonMSL, lat)
-		if err != nil {
+	// Interpolate MSL. Cells next to fill (e.g., land in MSS products)
+	// give NaN, leaving MSL unavailable.
+	msl := math.NaN()
+	if mslGrid != nil {
+		lonMSL := normalizeLonForAxis(mslGrid.X, lon)
+		if msl, err = mslGrid.InterpolateAt(lonMSL, lat); err != nil {
 			// If interpolation fails (e.g., out of bounds), return nil.
 			return nil, nil
 		}
+	}
+	//nolint:nestif // Geoid correction with multiple error paths.
+	if !math.IsNaN(msl) {
 
 		// DTU21 MSS is referenced to WGS84 ellipsoid.
 		// Apply geoid correction to convert to orthometric height (local datum).
 		// H (orthometric) = h (ellipsoidal) - N (geoid height).
 		if s.geoidStore != nil {
-			geoidHeight, err := s.geoidStore.GetGeoidHeight(lat, lon)
-			if err == nil {
+			geoidHeight, err := s.geoidHeight(lat, lon)
+			switch {
+			case err == nil:
 				// Apply correction: subtract geoid height from ellipsoidal MSL.
 				msl -= geoidHeight
-				metadata.DatumName = "EGM2008 (geoid-corrected)"
-			} else {
+				metadata.Datum = domain.DatumEGM2008GeoidCorrected
+			case errors.Is(err, errBackoff) || errors.Is(err, geoid.ErrUnavailable):
+				metadata.Degraded = append(metadata.Degraded, s.geoidHealth.Reason())
+			default:
 				// Log warning but continue with uncorrected value.
 				fmt.Fprintf(os.Stderr, "Warning: geoid correction failed: %v\n", err)
 			}
 		}
 
 		metadata.MSL = msl
-		metadata.SourceName = "DTU21 MSS"
+		metadata.Sources = append(metadata.Sources, domain.SourceDTU21MSS)
 	}
 
 	// Interpolate depth.
 	//nolint:nestif // Grid interpolation logic with multiple conditional paths.
-	if s.depthGrid != nil {
-		lonDepth := normalizeLonForAxis(s.depthGrid.X, lon)
-		depth, err := s.depthGrid.InterpolateAt(lonDepth, lat)
-		// If interpolation fails, depth remains nil.
-		if err == nil {
-			// GEBCO uses negative values for depth below sea level.
-			// Convert to positive depth.
-			if depth < 0 {
-				positiveDepth := -depth
-				metadata.DepthM = &positiveDepth
-			}
-			if metadata.SourceName == "DTU21 MSS" {
-				metadata.SourceName = "GEBCO 2025 + DTU21 MSS"
-			} else {
-				metadata.SourceName = "GEBCO 2025"
+	if depthGrid != nil {
+		lonDepth := normalizeLonForAxis(depthGrid.X, lon)
+		depth, err := depthGrid.InterpolateAt(lonDepth, lat)
+		// If interpolation fails or meets fill, depth remains nil.
+		if err == nil && !math.IsNaN(depth) {
+			// Normalize to depth below sea level (positive down).
+			// GEBCO uses negative elevations for depth below sea level.
+			if s.vertical != PositiveDown {
+				depth = -depth
 			}
+			metadata.DepthM = &depth
+			metadata.Land = depth < 0
+			metadata.Sources = append([]domain.SourceCode{domain.SourceGEBCO2025}, metadata.Sources...)
 		}
 	}
 
 	return metadata, nil
 }
 
+// lookupResult classifies a lookup for metrics.
+func lookupResult(metadata *domain.LocationMetadata) string {
+	switch {
+	case metadata != nil && len(metadata.Degraded) > 0:
+		return metrics.ResultDegraded
+	case metadata == nil || len(metadata.Sources) == 0:
+		return metrics.ResultNoData
+	default:
+		return metrics.ResultOK
+	}
+}
+
+// errBackoff reports a data file skipped while waiting to retry.
+var errBackoff = errors.New("waiting to retry")
+
+// load runs fn unless its breaker is open and records the outcome. It
+// returns the degradation reason, or "" when the grid loaded.
+func (s *LocalStore) load(b *circuit.Breaker, fn func() error) string {
+	if !b.Allow() {
+		return b.Reason()
+	}
+	if err := fn(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		b.Record(err)
+		return b.Reason()
+	}
+	b.Record(nil)
+	return ""
+}
+
+// geoidHeight looks up the geoid height unless the geoid breaker is open.
+// Only load failures count against the geoid file.
+func (s *LocalStore) geoidHeight(lat, lon float64) (float64, error) {
+	if !s.geoidHealth.Allow() {
+		return 0, errBackoff
+	}
+	height, err := s.geoidStore.GetGeoidHeight(lat, lon)
+	if errors.Is(err, geoid.ErrUnavailable) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		s.geoidHealth.Record(err)
+	} else {
+		s.geoidHealth.Record(nil)
+	}
+	return height, err
+}
+
+// Health reports the configured data files and their load status.
+func (s *LocalStore) Health() []ComponentHealth {
+	var out []ComponentHealth
+	if s.gebcoPath != "" {
+		out = append(out, componentHealth(s.gebcoHealth))
+	}
+	if s.mssPath != "" {
+		out = append(out, componentHealth(s.mssHealth))
+	}
+	if s.geoidStore != nil {
+		out = append(out, componentHealth(s.geoidHealth))
+	}
+	return out
+}
+
 // loadMSSGrid loads a subset of the MSS NetCDF file around the target location.
 func (s *LocalStore) loadMSSGrid(lat, lon float64) error {
-	// Load NetCDF grid subset with ±2 degree margin.
 	// DTU21 uses "mean_sea_surf_sol2" variable name.
-	const margin = 2.0 // Degrees.
-	grid, err := loadNetCDFGridSubset(s.mssPath, "lat", "lon", "mean_sea_surf_sol2", lat, lon, margin)
+	var grid *interp.Grid2D
+	err := retry.Do(ComponentMSS, func() (err error) {
+		grid, err = loadNetCDFGridSubset(s.mssPath, "lat", "lon", "mean_sea_surf_sol2", lat, lon, s.mssMargin, s.timeIndex)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to load MSS grid: %w", err)
 	}
@@ -209,10 +370,12 @@ func (s *LocalStore) loadMSSGrid(lat, lon float64) error {
 
 // loadDepthGrid loads a subset of the GEBCO NetCDF file around the target location.
 func (s *LocalStore) loadDepthGrid(lat, lon float64) error {
-	// Load NetCDF grid subset with ±2 degree margin.
 	// GEBCO uses "elevation" variable (negative for depth below sea level).
-	const margin = 2.0 // Degrees.
-	grid, err := loadNetCDFGridSubset(s.gebcoPath, "lat", "lon", "elevation", lat, lon, margin)
+	var grid *interp.Grid2D
+	err := retry.Do(ComponentGEBCO, func() (err error) {
+		grid, err = loadNetCDFGridSubset(s.gebcoPath, "lat", "lon", "elevation", lat, lon, s.gebcoMargin, s.timeIndex)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to load GEBCO grid: %w", err)
 	}
@@ -228,11 +391,13 @@ func (s *LocalStore) Close() error {
 }
 
 // loadNetCDFGridSubset reads a subset of a 2D grid from a NetCDF file.
-// If margin is 0, the entire grid is loaded.
-// If margin > 0, only data within ±margin degrees of (targetLat, targetLon) is loaded.
+// Only data within the margin around (targetLat, targetLon), resolved for
+// the grid's resolution, is loaded; a margin of 0 degrees loads the entire grid.
+// Variables may have leading dimensions before lat/lon (e.g., [time, lat, lon]);
+// these are read at timeIndex, or at 0 when they have length 1.
 //
 //nolint:gocyclo,nestif,gosec // Complex NetCDF loading logic with many cases.
-func loadNetCDFGridSubset(filepath, latVarName, lonVarName, dataVarName string, targetLat, targetLon, margin float64) (*interp.Grid2D, error) {
+func loadNetCDFGridSubset(filepath, latVarName, lonVarName, dataVarName string, targetLat, targetLon float64, subset interp.SubsetMargin, timeIndex int) (*interp.Grid2D, error) {
 	// Open NetCDF file.
 	nc, err := netcdf.OpenFile(filepath, netcdf.NOWRITE)
 	if err != nil {
@@ -278,6 +443,7 @@ func loadNetCDFGridSubset(filepath, latVarName, lonVarName, dataVarName string,
 	}
 
 	// Calculate subset indices if margin is specified.
+	margin := subset.Resolve(lonData, latData)
 	var latStart, latEnd, lonStart, lonEnd int
 	var subsetLat, subsetLon []float64
 
@@ -343,24 +509,28 @@ func loadNetCDFGridSubset(filepath, latVarName, lonVarName, dataVarName string,
 		return nil, fmt.Errorf("data variable not found (tried: %v)", dataNames)
 	}
 
-	// Read 2D data array.
+	// Read 2D data array; the last two dimensions are spatial.
 	dims, err := dataVar.Dims()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dimensions: %w", err)
 	}
-	if len(dims) != 2 {
+	if len(dims) < 2 {
 		return nil, fmt.Errorf("expected 2D data, got %dD", len(dims))
 	}
+	lead, err := leadingIndices(dims[:len(dims)-2], timeIndex)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine which dimension is lat and which is lon.
 	nLat := len(latData)
 	nLon := len(lonData)
 
-	dim0Len, err := dims[0].Len()
+	dim0Len, err := dims[len(dims)-2].Len()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dim0 length: %w", err)
 	}
-	dim1Len, err := dims[1].Len()
+	dim1Len, err := dims[len(dims)-1].Len()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get dim1 length: %w", err)
 	}
@@ -391,16 +561,16 @@ func loadNetCDFGridSubset(filepath, latVarName, lonVarName, dataVarName string,
 	switch order {
 	case latLonOrder:
 		// Data is [lat, lon].
-		if margin > 0 {
-			values, err = read2DFloat64VarSubset(dataVar, latStart, lonStart, nSubsetLat, nSubsetLon)
+		if margin > 0 || len(lead) > 0 {
+			values, err = read2DFloat64VarSubset(dataVar, lead, latStart, lonStart, nSubsetLat, nSubsetLon)
 		} else {
 			values, err = read2DFloat64Var(dataVar, nLat, nLon)
 		}
 	case lonLatOrder:
 		// Data is [lon, lat] - need to transpose.
 		var transposed [][]float64
-		if margin > 0 {
-			transposed, err = read2DFloat64VarSubset(dataVar, lonStart, latStart, nSubsetLon, nSubsetLat)
+		if margin > 0 || len(lead) > 0 {
+			transposed, err = read2DFloat64VarSubset(dataVar, lead, lonStart, latStart, nSubsetLon, nSubsetLat)
 		} else {
 			transposed, err = read2DFloat64Var(dataVar, nLon, nLat)
 		}
@@ -424,7 +594,8 @@ func loadNetCDFGridSubset(filepath, latVarName, lonVarName, dataVarName string,
 		Values: values,
 	}
 
-	// Validate grid.
+	// Flip descending axes, then validate grid.
+	grid.EnsureAscending()
 	if err := grid.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid grid: %w", err)
 	}
@@ -515,6 +686,9 @@ func read2DFloat64Var(v netcdf.Var, nRows, nCols int) ([][]float64, error) {
 		return nil, fmt.Errorf("unsupported data type: %v (expected DOUBLE, FLOAT, INT, or SHORT)", varType)
 	}
 
+	// Fill values, in the stored units, become NaN.
+	ncfill.Of(v).Mask(flatData)
+
 	// Apply scale_factor if present.
 	scaleAttr := v.Attr("scale_factor")
 	attrLen, err := scaleAttr.Len()
@@ -554,10 +728,32 @@ func read2DFloat64Var(v netcdf.Var, nRows, nCols int) ([][]float64, error) {
 	return values, nil
 }
 
+// leadingIndices returns the read position along dimensions preceding lat/lon:
+// 0 for singleton dimensions, index otherwise.
+func leadingIndices(dims []netcdf.Dim, index int) ([]uint64, error) {
+	lead := make([]uint64, len(dims))
+	for i, d := range dims {
+		n, err := d.Len()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get leading dimension length: %w", err)
+		}
+		if n == 1 {
+			continue
+		}
+		if index < 0 || uint64(index) >= n {
+			name, _ := d.Name()
+			return nil, fmt.Errorf("index %d out of range for dimension %s (length %d)", index, name, n)
+		}
+		lead[i] = uint64(index)
+	}
+	return lead, nil
+}
+
 // read2DFloat64VarSubset reads a subset of a 2D float64 array from a NetCDF variable.
-// Reads data starting at [startRow, startCol] with dimensions [nRows, nCols].
+// Reads data starting at [lead..., startRow, startCol] with dimensions [1..., nRows, nCols],
+// where lead holds fixed indices of any leading dimensions (e.g., time).
 // Supports the same data types as read2DFloat64Var.
-func read2DFloat64VarSubset(v netcdf.Var, startRow, startCol, nRows, nCols int) ([][]float64, error) {
+func read2DFloat64VarSubset(v netcdf.Var, lead []uint64, startRow, startCol, nRows, nCols int) ([][]float64, error) {
 	// Get variable type.
 	varType, err := v.Type()
 	if err != nil {
@@ -568,10 +764,16 @@ func read2DFloat64VarSubset(v netcdf.Var, startRow, startCol, nRows, nCols int)
 	totalSize := nRows * nCols
 
 	// Prepare start and count arrays for hyperslab reading.
+	start := make([]uint64, 0, len(lead)+2)
+	count := make([]uint64, 0, len(lead)+2)
+	for _, idx := range lead {
+		start = append(start, idx)
+		count = append(count, 1)
+	}
 	//nolint:gosec // G115: Safe int to uint64 conversion for NetCDF indices.
-	start := []uint64{uint64(startRow), uint64(startCol)}
+	start = append(start, uint64(startRow), uint64(startCol))
 	//nolint:gosec // G115: Safe int to uint64 conversion for NetCDF dimensions.
-	count := []uint64{uint64(nRows), uint64(nCols)}
+	count = append(count, uint64(nRows), uint64(nCols))
 
 	// Read data based on type.
 	switch varType {
@@ -618,6 +820,9 @@ func read2DFloat64VarSubset(v netcdf.Var, startRow, startCol, nRows, nCols int)
 		return nil, fmt.Errorf("unsupported data type: %v (expected DOUBLE, FLOAT, INT, or SHORT)", varType)
 	}
 
+	// Fill values, in the stored units, become NaN.
+	ncfill.Of(v).Mask(flatData)
+
 	// Apply scale_factor if present.
 	scaleAttr := v.Attr("scale_factor")
 	attrLen, err := scaleAttr.Len()
@@ -677,18 +882,20 @@ func transpose2D(data [][]float64) [][]float64 {
 	return transposed
 }
 
-// findNearestIndex finds the index of the value closest to target in a sorted array.
+// findNearestIndex finds the index of the value closest to target in a sorted
+// (ascending or descending) array.
 func findNearestIndex(arr []float64, target float64) int {
 	if len(arr) == 0 {
 		return 0
 	}
+	desc := arr[0] > arr[len(arr)-1]
 
 	// Binary search for efficiency with large arrays.
 	left, right := 0, len(arr)-1
 
 	for left < right {
 		mid := (left + right) / 2
-		if arr[mid] < target {
+		if (!desc && arr[mid] < target) || (desc && arr[mid] > target) {
 			left = mid + 1
 		} else {
 			right = mid
@@ -1,48 +1,21 @@
 package bathymetry
 
 import (
-	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
-	"github.com/fhs/go-netcdf/netcdf"
+	"go.ngs.io/tides-api/internal/adapter/geoid"
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
 // Helper to create a minimal GEBCO-like NetCDF file with the given elevation data.
 func createElevationTestFile(t *testing.T, path string, latVals, lonVals []float64, values [][]float32) {
 	t.Helper()
-	//nolint:gosec // G301: Standard test directory permissions.
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		t.Fatalf("mkdir: %v", err)
-	}
-	f, err := netcdf.CreateFile(path, netcdf.CLOBBER)
-	if err != nil {
-		t.Fatalf("create nc: %v", err)
-	}
-	defer func() { _ = f.Close() }()
-
-	latDim, _ := f.AddDim("lat", uint64(len(latVals)))
-	lonDim, _ := f.AddDim("lon", uint64(len(lonVals)))
-	vlat, _ := f.AddVar("lat", netcdf.DOUBLE, []netcdf.Dim{latDim})
-	vlon, _ := f.AddVar("lon", netcdf.DOUBLE, []netcdf.Dim{lonDim})
-	velev, _ := f.AddVar("elevation", netcdf.FLOAT, []netcdf.Dim{latDim, lonDim})
-
-	if err := f.EndDef(); err != nil {
-		t.Fatalf("enddef: %v", err)
-	}
-	if err := vlat.WriteFloat64s(latVals); err != nil {
-		t.Fatalf("write lat: %v", err)
-	}
-	if err := vlon.WriteFloat64s(lonVals); err != nil {
-		t.Fatalf("write lon: %v", err)
-	}
-	flat := make([]float32, 0, len(latVals)*len(lonVals))
-	for i := range values {
-		flat = append(flat, values[i]...)
-	}
-	if err := velev.WriteFloat32s(flat); err != nil {
-		t.Fatalf("write elevation: %v", err)
-	}
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: latVals, Lon: lonVals, Vars: []ncfixture.Var{
+		{Name: "elevation", Values: values},
+	}})
 }
 
 func TestLocalStoreReloadsDepthGridForDistantLocations(t *testing.T) {
@@ -103,3 +76,250 @@ func TestLocalStoreHandlesWrappedLongitude(t *testing.T) {
 		t.Fatalf("expected depth metadata for wrapped longitude, got %+v", meta)
 	}
 }
+
+func TestLocalStoreHandlesDescendingLatitude(t *testing.T) {
+	latVals := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
+	lonVals := []float64{0, 1}
+	values := make([][]float32, len(latVals))
+	for i, lat := range latVals {
+		values[i] = []float32{float32(-10*lat - 1), float32(-10*lat - 1)}
+	}
+	dir := t.TempDir()
+	gebcoPath := filepath.Join(dir, "gebco_desc.nc")
+	createElevationTestFile(t, gebcoPath, latVals, lonVals, values)
+
+	store := NewLocalStore(gebcoPath, "", nil)
+	meta, err := store.GetMetadata(2.5, 0.5)
+	if err != nil {
+		t.Fatalf("GetMetadata descending lat: %v", err)
+	}
+	if meta == nil || meta.DepthM == nil {
+		t.Fatalf("expected depth metadata, got %+v", meta)
+	}
+	if got := *meta.DepthM; got < 25.9 || got > 26.1 {
+		t.Errorf("expected depth ~26 m at 2.5°N, got %.2f", got)
+	}
+}
+
+// createMSSTestFile creates an MSS-like NetCDF file with a [time, lat, lon] variable.
+func createMSSTestFile(t *testing.T, path string, latVals, lonVals []float64, steps [][][]float32) {
+	t.Helper()
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: latVals, Lon: lonVals, Vars: []ncfixture.Var{
+		{Name: "mean_sea_surf_sol2", Steps: steps},
+	}})
+}
+
+func TestLocalStoreReads3DMSS(t *testing.T) {
+	latVals := []float64{30, 31, 32}
+	lonVals := []float64{130, 131, 132}
+	step := func(v float32) [][]float32 {
+		return [][]float32{{v, v, v}, {v, v, v}, {v, v, v}}
+	}
+	dir := t.TempDir()
+
+	// Singleton time dimension.
+	single := filepath.Join(dir, "mss_single.nc")
+	createMSSTestFile(t, single, latVals, lonVals, [][][]float32{step(1.5)})
+	meta, err := NewLocalStore("", single, nil).GetMetadata(31.0, 131.0)
+	if err != nil || meta == nil {
+		t.Fatalf("GetMetadata singleton time: %+v, %v", meta, err)
+	}
+	if meta.MSL != 1.5 {
+		t.Errorf("expected MSL 1.5, got %v", meta.MSL)
+	}
+
+	// Multiple time steps select by index.
+	multi := filepath.Join(dir, "mss_multi.nc")
+	createMSSTestFile(t, multi, latVals, lonVals, [][][]float32{step(1.0), step(2.0)})
+	store := NewLocalStore("", multi, nil)
+	store.SetTimeIndex(1)
+	meta, err = store.GetMetadata(31.0, 131.0)
+	if err != nil || meta == nil {
+		t.Fatalf("GetMetadata time index 1: %+v, %v", meta, err)
+	}
+	if meta.MSL != 2.0 {
+		t.Errorf("expected MSL 2.0 at time index 1, got %v", meta.MSL)
+	}
+
+	// Out-of-range index leaves MSL unavailable and flags the MSS file.
+	store.SetTimeIndex(5)
+	if meta, _ := store.GetMetadata(31.0, 131.0); meta == nil || len(meta.Sources) > 0 || len(meta.Degraded) != 1 {
+		t.Errorf("expected only a degradation for out-of-range time index, got %+v", meta)
+	}
+}
+
+func TestLocalStoreReadsPackedTransposedElevation(t *testing.T) {
+	dir := t.TempDir()
+	gebcoPath := filepath.Join(dir, "gebco_packed.nc")
+	ncfixture.Write(t, gebcoPath, ncfixture.Grid{
+		Lat:        []float64{30, 31},
+		Lon:        []float64{130, 131, 132},
+		Transposed: true,
+		Vars: []ncfixture.Var{{
+			Name:   "elevation",
+			Values: [][]float32{{-100, -200, -300}, {-100, -200, -300}},
+			Pack:   &ncfixture.Packing{Scale: 0.5},
+		}},
+	})
+
+	meta, err := NewLocalStore(gebcoPath, "", nil).GetMetadata(30.5, 131.0)
+	if err != nil || meta == nil || meta.DepthM == nil {
+		t.Fatalf("GetMetadata packed: %+v, %v", meta, err)
+	}
+	if got := *meta.DepthM; got < 199.9 || got > 200.1 {
+		t.Errorf("expected depth ~200 m from scaled int16 data, got %.2f", got)
+	}
+}
+
+func TestLocalStoreReportsLandElevation(t *testing.T) {
+	latVals := []float64{30, 31}
+	lonVals := []float64{130, 131}
+	dir := t.TempDir()
+
+	// Elevation dataset: +20 m is land.
+	gebcoPath := filepath.Join(dir, "gebco_land.nc")
+	createElevationTestFile(t, gebcoPath, latVals, lonVals, [][]float32{{20, 20}, {20, 20}})
+	meta, err := NewLocalStore(gebcoPath, "", nil).GetMetadata(30.5, 130.5)
+	if err != nil || meta == nil || meta.DepthM == nil {
+		t.Fatalf("GetMetadata land: %+v, %v", meta, err)
+	}
+	if !meta.Land || *meta.DepthM != -20 {
+		t.Errorf("expected land with depth -20, got land=%v depth=%v", meta.Land, *meta.DepthM)
+	}
+
+	// Depth dataset: +20 m is water.
+	store := NewLocalStore(gebcoPath, "", nil)
+	store.SetVerticalConvention(PositiveDown)
+	meta, err = store.GetMetadata(30.5, 130.5)
+	if err != nil || meta == nil || meta.DepthM == nil {
+		t.Fatalf("GetMetadata positive down: %+v, %v", meta, err)
+	}
+	if meta.Land || *meta.DepthM != 20 {
+		t.Errorf("expected water with depth 20, got land=%v depth=%v", meta.Land, *meta.DepthM)
+	}
+}
+
+func TestLocalStoreFlagsAndRecoversUnreadableFile(t *testing.T) {
+	dir := t.TempDir()
+	gebcoPath := filepath.Join(dir, "gebco.nc")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewLocalStore(gebcoPath, "", nil)
+	store.now = func() time.Time { return now }
+
+	// Missing file: degraded, not an error.
+	meta, err := store.GetMetadata(30.5, 130.5)
+	if err != nil || meta == nil || meta.DepthM != nil || len(meta.Degraded) != 1 {
+		t.Fatalf("GetMetadata missing file: %+v, %v", meta, err)
+	}
+	health := store.Health()
+	if len(health) != 1 || health[0].Healthy || health[0].Failures != 1 {
+		t.Fatalf("expected unhealthy gebco, got %+v", health)
+	}
+	if want := now.Add(minRetryBackoff); !health[0].NextRetry.Equal(want) {
+		t.Errorf("expected next retry %v, got %v", want, health[0].NextRetry)
+	}
+
+	// The file reappears, but it is not retried until the backoff elapses.
+	createElevationTestFile(t, gebcoPath, []float64{30, 31}, []float64{130, 131}, [][]float32{{-20, -20}, {-20, -20}})
+	if meta, _ := store.GetMetadata(30.5, 130.5); meta == nil || meta.DepthM != nil {
+		t.Fatalf("expected no reload during backoff, got %+v", meta)
+	}
+	if h := store.Health(); h[0].Failures != 1 {
+		t.Errorf("expected no attempt during backoff, got %+v", h)
+	}
+
+	now = now.Add(minRetryBackoff)
+	meta, err = store.GetMetadata(30.5, 130.5)
+	if err != nil || meta == nil || meta.DepthM == nil || *meta.DepthM != 20 || len(meta.Degraded) != 0 {
+		t.Fatalf("expected recovery, got %+v, %v", meta, err)
+	}
+	if h := store.Health(); !h[0].Healthy {
+		t.Errorf("expected healthy gebco after recovery, got %+v", h)
+	}
+}
+
+func TestLocalStoreCrossesLand(t *testing.T) {
+	// 0.01° columns (~1 km) of sea, except a 3 km wide peninsula
+	// running north-south at 130.10-130.12°E.
+	latVals := []float64{30, 30.1}
+	var lonVals []float64
+	row := []float32{}
+	for i := 0; i <= 30; i++ {
+		lon := 130 + float64(i)/100
+		lonVals = append(lonVals, lon)
+		elevation := float32(-30)
+		if i >= 10 && i <= 12 {
+			elevation = 50
+		}
+		row = append(row, elevation)
+	}
+	gebcoPath := filepath.Join(t.TempDir(), "gebco.nc")
+	createElevationTestFile(t, gebcoPath, latVals, lonVals, [][]float32{row, row})
+	store := NewLocalStore(gebcoPath, "", nil)
+
+	if crosses, ok := store.CrossesLand(30.05, 130.02, 30.05, 130.25); !ok || !crosses {
+		t.Errorf("across the peninsula: crosses=%v ok=%v, want true, true", crosses, ok)
+	}
+	if crosses, ok := store.CrossesLand(30.05, 130.13, 30.05, 130.28); !ok || crosses {
+		t.Errorf("same coast: crosses=%v ok=%v, want false, true", crosses, ok)
+	}
+	// Land at the ends (a gauge on the shore) does not block.
+	if crosses, ok := store.CrossesLand(30.05, 130.12, 30.05, 130.28); !ok || crosses {
+		t.Errorf("from the shore: crosses=%v ok=%v, want false, true", crosses, ok)
+	}
+	if _, ok := NewLocalStore("", "", nil).CrossesLand(30.05, 130.02, 30.05, 130.25); ok {
+		t.Error("path checked without GEBCO data")
+	}
+}
+
+func TestLocalStoreMasksFill(t *testing.T) {
+	const fill = ncfixture.FillValue
+	latVals := []float64{30, 31, 32}
+	lonVals := []float64{130, 131, 132}
+	// The western column is land: fill in every grid.
+	values := func(v float32) [][]float32 {
+		return [][]float32{{fill, v, v}, {fill, v, v}, {fill, v, v}}
+	}
+	dir := t.TempDir()
+	gebcoPath := filepath.Join(dir, "gebco.nc")
+	mssPath := filepath.Join(dir, "mss.nc")
+	geoidPath := filepath.Join(dir, "geoid.nc")
+	ncfixture.Write(t, gebcoPath, ncfixture.Grid{Lat: latVals, Lon: lonVals, Vars: []ncfixture.Var{
+		{Name: "elevation", Values: values(-40), Fill: true},
+	}})
+	ncfixture.Write(t, mssPath, ncfixture.Grid{Lat: latVals, Lon: lonVals, Vars: []ncfixture.Var{
+		{Name: "mean_sea_surf_sol2", Values: values(35), Fill: true},
+	}})
+	ncfixture.Write(t, geoidPath, ncfixture.Grid{Lat: latVals, Lon: lonVals, Vars: []ncfixture.Var{
+		{Name: "geoid", Values: values(34), Fill: true},
+	}})
+
+	sea, err := NewLocalStore(gebcoPath, mssPath, geoid.NewStore(geoidPath)).GetMetadata(31, 131.5)
+	if err != nil || sea == nil || sea.DepthM == nil {
+		t.Fatalf("GetMetadata at sea: %+v, %v", sea, err)
+	}
+	if *sea.DepthM != 40 || sea.MSL != 1 || sea.Datum != domain.DatumEGM2008GeoidCorrected {
+		t.Errorf("at sea: depth %v, MSL %v (%s), want 40 and 1 geoid corrected", *sea.DepthM, sea.MSL, sea.Datum)
+	}
+
+	// Next to the fill column nothing is interpolated from fill.
+	shore, err := NewLocalStore(gebcoPath, mssPath, geoid.NewStore(geoidPath)).GetMetadata(31, 130.5)
+	if err != nil || shore == nil {
+		t.Fatalf("GetMetadata on the shore: %+v, %v", shore, err)
+	}
+	if shore.DepthM != nil || shore.MSL != 0 || len(shore.Sources) != 0 {
+		t.Errorf("on the shore: depth %v, MSL %v, sources %v; want neither", shore.DepthM, shore.MSL, shore.Sources)
+	}
+
+	// Fill in the geoid alone leaves MSL uncorrected.
+	ncfixture.Write(t, geoidPath, ncfixture.Grid{Lat: latVals, Lon: lonVals, Vars: []ncfixture.Var{
+		{Name: "geoid", Values: [][]float32{{fill, fill, fill}, {fill, fill, fill}, {fill, fill, fill}}, Fill: true},
+	}})
+	uncorrected, err := NewLocalStore("", mssPath, geoid.NewStore(geoidPath)).GetMetadata(31, 131.5)
+	if err != nil || uncorrected == nil {
+		t.Fatalf("GetMetadata with geoid fill: %+v, %v", uncorrected, err)
+	}
+	if uncorrected.MSL != 35 || uncorrected.Datum != domain.DatumEGM2008 {
+		t.Errorf("with geoid fill: MSL %v (%s), want 35 uncorrected", uncorrected.MSL, uncorrected.Datum)
+	}
+}
@@ -1,6 +1,6 @@
 package bathymetry
 
-import "go.ngs.io/tides-api/internal/domain"
+import "go.ngs.io/tides-api/pkg/domain"
 
 // Store provides access to bathymetry (depth) and mean sea level data.
 type Store interface {
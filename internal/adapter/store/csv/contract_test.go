@@ -0,0 +1,31 @@
+package csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/store/storetest"
+)
+
+func TestConstituentLoaderContract(t *testing.T) {
+	storetest.Run(t, storetest.Harness{
+		Stations: true,
+		New: func(t *testing.T) store.ConstituentLoader {
+			dir := t.TempDir()
+			// Amplitudes in centimeters to exercise unit conversion.
+			lines := []string{"# units: cm", "constituent,amplitude,phase_deg"}
+			for _, c := range storetest.Seed {
+				lines = append(lines, fmt.Sprintf("%s,%g,%g", c.Raw, c.AmplitudeM*100, c.PhaseDeg))
+			}
+			path := filepath.Join(dir, "mock_"+storetest.StationID+"_constituents.csv")
+			if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			return NewConstituentStore(dir)
+		},
+	})
+}
@@ -2,13 +2,17 @@
 package csv
 
 import (
+	"bufio"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
-	"go.ngs.io/tides-api/internal/domain"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
 // ConstituentStore provides access to tidal constituent data.
@@ -24,90 +28,359 @@ func NewConstituentStore(dataDir string) *ConstituentStore {
 }
 
 // LoadForStation loads constituent parameters for a named station.
+// Secondary ports have none; predict them from their metadata.
 func (s *ConstituentStore) LoadForStation(stationID string) ([]domain.ConstituentParam, error) {
+	meta, constituents, err := s.LoadStation(stationID)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Secondary != nil {
+		return nil, fmt.Errorf("station %s is a secondary port of %s and has no constituents", stationID, meta.Secondary.Reference)
+	}
+	return constituents, nil
+}
+
+// LoadStationMetadata loads the metadata rows of a station file.
+func (s *ConstituentStore) LoadStationMetadata(stationID string) (*domain.StationMetadata, error) {
+	meta, _, err := s.LoadStation(stationID)
+	return meta, err
+}
+
+// LoadStation loads a station file's metadata and constituents.
+func (s *ConstituentStore) LoadStation(stationID string) (*domain.StationMetadata, []domain.ConstituentParam, error) {
 	// Construct file path.
 	filename := fmt.Sprintf("%s/mock_%s_constituents.csv", s.dataDir, strings.ToLower(stationID))
 
 	//nolint:gosec // G304: File path constructed from dataDir (config) and stationID (validated).
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file for station %s: %w", stationID, err)
+		return nil, nil, fmt.Errorf("failed to open CSV file for station %s: %w", stationID, err)
 	}
 	defer func() { _ = file.Close() }()
 
-	reader := csv.NewReader(file)
+	meta, constituents, err := ReadStation(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta.ID = stationID
+
+	if len(constituents) == 0 && meta.Secondary == nil {
+		return nil, nil, fmt.Errorf("no constituents found in CSV for station %s", stationID)
+	}
+
+	return meta, constituents, nil
+}
+
+// Column names of the station CSV format.
+const (
+	colConstituent = "constituent"
+	colAmplitudeM  = "amplitude_m"
+	colAmplitude   = "amplitude" // Amplitude in the file's "units".
+	colPhaseDeg    = "phase_deg"
+	colSpeed       = "speed_deg_per_hr"
+)
+
+// ReadStation reads a station CSV. Version 1 is the bare
+// "constituent,amplitude_m,phase_deg" table. Version 2 adds optional
+// "# key: value" metadata rows before the header (station_name, lat, lon,
+// datum_offset_m, units) and an optional speed_deg_per_hr column; columns
+// are matched by header name. Secondary ports have only metadata rows:
+// reference_station and the differences from it.
+//
+//nolint:gocyclo // Sequential parsing of metadata, header and rows.
+func ReadStation(r io.Reader) (*domain.StationMetadata, []domain.ConstituentParam, error) {
+	meta := &domain.StationMetadata{Units: "m"}
+	br := bufio.NewReader(r)
+
+	// Metadata rows.
+	for {
+		peek, err := br.Peek(1)
+		if err != nil || peek[0] != '#' {
+			break
+		}
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("failed to read CSV metadata: %w", err)
+		}
+		if err := parseMetadataRow(meta, line); err != nil {
+			return nil, nil, err
+		}
+	}
+	if sp := meta.Secondary; sp != nil {
+		if sp.Reference == "" {
+			return nil, nil, errors.New("invalid CSV metadata: secondary port differences without reference_station")
+		}
+		if rest, _ := io.ReadAll(br); strings.TrimSpace(string(rest)) != "" {
+			return nil, nil, errors.New("invalid CSV: secondary port files have no constituent table")
+		}
+		return meta, nil, nil
+	}
+	scale, err := domain.AmplitudeUnitScale(meta.Units)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader := csv.NewReader(br)
 	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
 
 	// Read header.
 	header, err := reader.Read()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
-	}
-
-	// Validate header.
-	expectedHeaders := []string{"constituent", "amplitude_m", "phase_deg"}
-	if len(header) != len(expectedHeaders) {
-		return nil, fmt.Errorf("invalid CSV header: expected %v, got %v", expectedHeaders, header)
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
 	}
 
+	// Map columns by name.
+	cols := map[string]int{}
 	for i, h := range header {
-		if h != expectedHeaders[i] {
-			return nil, fmt.Errorf("invalid CSV header: expected column %d to be %s, got %s", i, expectedHeaders[i], h)
+		h = strings.ToLower(strings.TrimSpace(h))
+		switch h {
+		case colConstituent, colAmplitudeM, colAmplitude, colPhaseDeg, colSpeed:
+			if _, dup := cols[h]; dup {
+				return nil, nil, fmt.Errorf("invalid CSV header: duplicate column %s", h)
+			}
+			cols[h] = i
+		default:
+			return nil, nil, fmt.Errorf("invalid CSV header: unknown column %q", h)
+		}
+	}
+	ampCol, ampInMeters := cols[colAmplitudeM]
+	if !ampInMeters {
+		var ok bool
+		if ampCol, ok = cols[colAmplitude]; !ok {
+			return nil, nil, fmt.Errorf("invalid CSV header: missing %s or %s column", colAmplitudeM, colAmplitude)
 		}
 	}
+	if ampInMeters && meta.Units != "m" {
+		return nil, nil, fmt.Errorf("invalid CSV header: %s conflicts with units %s (use %s)", colAmplitudeM, meta.Units, colAmplitude)
+	}
+	nameCol, ok := cols[colConstituent]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid CSV header: missing %s column", colConstituent)
+	}
+	phaseCol, ok := cols[colPhaseDeg]
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid CSV header: missing %s column", colPhaseDeg)
+	}
+	speedCol, hasSpeed := cols[colSpeed]
 
 	// Read data rows.
 	constituents := make([]domain.ConstituentParam, 0)
 
 	for {
 		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
 		if err != nil {
-			// EOF is expected.
-			if err.Error() == "EOF" {
-				break
-			}
-			return nil, fmt.Errorf("failed to read CSV record: %w", err)
+			return nil, nil, fmt.Errorf("failed to read CSV record: %w", err)
 		}
 
-		if len(record) != 3 {
-			return nil, fmt.Errorf("invalid CSV record: expected 3 columns, got %d", len(record))
+		if len(record) != len(header) {
+			return nil, nil, fmt.Errorf("invalid CSV record: expected %d columns, got %d", len(header), len(record))
 		}
 
-		name := strings.TrimSpace(record[0])
-		amplitudeStr := strings.TrimSpace(record[1])
-		phaseStr := strings.TrimSpace(record[2])
+		name := strings.TrimSpace(record[nameCol])
+		amplitudeStr := strings.TrimSpace(record[ampCol])
+		phaseStr := strings.TrimSpace(record[phaseCol])
 
 		// Parse amplitude.
 		amplitude, err := strconv.ParseFloat(amplitudeStr, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid amplitude for constituent %s: %w", name, err)
+			return nil, nil, fmt.Errorf("invalid amplitude for constituent %s: %w", name, err)
 		}
 
 		// Parse phase.
 		phase, err := strconv.ParseFloat(phaseStr, 64)
 		if err != nil {
-			return nil, fmt.Errorf("invalid phase for constituent %s: %w", name, err)
+			return nil, nil, fmt.Errorf("invalid phase for constituent %s: %w", name, err)
 		}
 
-		// Get angular speed from standard constituents.
-		speed, ok := domain.GetConstituentSpeed(name)
-		if !ok {
-			return nil, fmt.Errorf("unknown constituent: %s", name)
+		// Resolve dataset spelling and get angular speed from standard constituents.
+		// An explicit speed allows constituents outside the standard table.
+		speed, known := 0.0, false
+		if canonical, ok := domain.CanonicalConstituentName(name); ok {
+			name = canonical
+			speed, known = domain.GetConstituentSpeed(name)
+		}
+		if hasSpeed {
+			if speedStr := strings.TrimSpace(record[speedCol]); speedStr != "" {
+				speed, err = strconv.ParseFloat(speedStr, 64)
+				if err != nil {
+					return nil, nil, fmt.Errorf("invalid speed for constituent %s: %w", name, err)
+				}
+				known = true
+			}
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("unknown constituent: %s", name)
 		}
 
 		constituents = append(constituents, domain.ConstituentParam{
 			Name:          name,
-			AmplitudeM:    amplitude,
-			PhaseDeg:      phase,
+			AmplitudeM:    amplitude * scale,
+			PhaseDeg:      domain.NormalizePhaseDeg(phase),
 			SpeedDegPerHr: speed,
 		})
 	}
 
+	return meta, constituents, nil
+}
+
+// parseMetadataRow applies one "# key: value" row.
+func parseMetadataRow(meta *domain.StationMetadata, line string) error {
+	key, value, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "#"), ":")
+	if !ok {
+		// Plain comment.
+		return nil
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+
+	parseFloat := func() (*float64, error) {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV metadata %s: %w", key, err)
+		}
+		return &v, nil
+	}
+
+	var err error
+	if strings.HasPrefix(key, "hw_") || strings.HasPrefix(key, "lw_") || key == "reference_station" {
+		return parseSecondaryRow(meta, key, value)
+	}
+	switch key {
+	case "station_name":
+		meta.Name = value
+	case "lat":
+		meta.Lat, err = parseFloat()
+	case "lon":
+		meta.Lon, err = parseFloat()
+	case "datum_offset_m":
+		meta.DatumOffsetM, err = parseFloat()
+	case "units":
+		meta.Units = strings.ToLower(value)
+	}
+	// Unknown keys are kept as comments.
+	return err
+}
+
+// parseSecondaryRow applies one secondary port metadata row. Time offsets
+// are in minutes; heights are ratio × reference height + diff.
+func parseSecondaryRow(meta *domain.StationMetadata, key, value string) error {
+	sp := meta.Secondary
+	if sp == nil {
+		sp = &domain.SecondaryPort{HighWaterRatio: 1, LowWaterRatio: 1}
+		meta.Secondary = sp
+	}
+	if key == "reference_station" {
+		sp.Reference = value
+		return nil
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid CSV metadata %s: %w", key, err)
+	}
+	minutes := time.Duration(v * float64(time.Minute))
+	switch key {
+	case "hw_time_offset_min":
+		sp.HighWaterTimeOffset = minutes
+	case "lw_time_offset_min":
+		sp.LowWaterTimeOffset = minutes
+	case "hw_height_ratio":
+		sp.HighWaterRatio = v
+	case "lw_height_ratio":
+		sp.LowWaterRatio = v
+	case "hw_height_diff_m":
+		sp.HighWaterDiffM = v
+	case "lw_height_diff_m":
+		sp.LowWaterDiffM = v
+	default:
+		return fmt.Errorf("invalid CSV metadata: unknown secondary port key %s", key)
+	}
+	return nil
+}
+
+// WriteStation writes a version 2 station CSV with amplitudes in meters.
+func WriteStation(w io.Writer, meta *domain.StationMetadata, constituents []domain.ConstituentParam) error {
+	bw := bufio.NewWriter(w)
+	if meta != nil {
+		if meta.Name != "" {
+			fmt.Fprintf(bw, "# station_name: %s\n", meta.Name)
+		}
+		if meta.Lat != nil && meta.Lon != nil {
+			fmt.Fprintf(bw, "# lat: %s\n# lon: %s\n", formatFloat(*meta.Lat), formatFloat(*meta.Lon))
+		}
+		if meta.DatumOffsetM != nil {
+			fmt.Fprintf(bw, "# datum_offset_m: %s\n", formatFloat(*meta.DatumOffsetM))
+		}
+	}
+	fmt.Fprintln(bw, "# units: m")
+
+	writer := csv.NewWriter(bw)
+	if err := writer.Write([]string{colConstituent, colAmplitudeM, colPhaseDeg, colSpeed}); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, c := range constituents {
+		record := []string{c.Name, formatFloat(c.AmplitudeM), formatFloat(c.PhaseDeg), formatFloat(c.SpeedDegPerHr)}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// validStationID reports whether id is safe to use in a file name.
+func validStationID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// SaveStation writes a station file, replacing any existing one atomically.
+func (s *ConstituentStore) SaveStation(stationID string, meta *domain.StationMetadata, constituents []domain.ConstituentParam) error {
+	if !validStationID(stationID) {
+		return fmt.Errorf("invalid station id %q (letters, digits, '_' and '-' only)", stationID)
+	}
 	if len(constituents) == 0 {
-		return nil, fmt.Errorf("no constituents found in CSV for station %s", stationID)
+		return fmt.Errorf("no constituents to save for station %s", stationID)
 	}
+	filename := fmt.Sprintf("%s/mock_%s_constituents.csv", s.dataDir, strings.ToLower(stationID))
 
-	return constituents, nil
+	tmp, err := os.CreateTemp(s.dataDir, ".station-*.csv")
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file for station %s: %w", stationID, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := WriteStation(tmp, meta, constituents); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write CSV file for station %s: %w", stationID, err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace CSV file for station %s: %w", stationID, err)
+	}
+	return nil
 }
 
 // LoadForLocation loads constituent parameters for a lat/lon location.
@@ -0,0 +1,157 @@
+package csv
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestReadStation_Version1(t *testing.T) {
+	meta, params, err := ReadStation(strings.NewReader("constituent,amplitude_m,phase_deg\nM2,0.62,145.0\nk1,0.18,30.0\n"))
+	if err != nil {
+		t.Fatalf("ReadStation: %v", err)
+	}
+	if meta.Units != "m" || meta.Name != "" || meta.DatumOffsetM != nil {
+		t.Errorf("unexpected metadata for v1 file: %+v", meta)
+	}
+	if len(params) != 2 || params[1].Name != "K1" || params[1].SpeedDegPerHr == 0 {
+		t.Errorf("unexpected constituents: %+v", params)
+	}
+}
+
+func TestReadStation_Version2(t *testing.T) {
+	input := strings.Join([]string{
+		"# Harbor master constants, 2024 analysis",
+		"# station_name: Example Harbor",
+		"# lat: 35.65",
+		"# lon: 139.77",
+		"# datum_offset_m: 1.1",
+		"# units: cm",
+		"constituent,amplitude,phase_deg,speed_deg_per_hr",
+		"M2,62,145.0,",
+		"X1,5,10.0,12.5",
+		"",
+	}, "\n")
+
+	meta, params, err := ReadStation(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadStation: %v", err)
+	}
+	if meta.Name != "Example Harbor" || *meta.Lat != 35.65 || *meta.Lon != 139.77 || *meta.DatumOffsetM != 1.1 {
+		t.Errorf("unexpected metadata: %+v", meta)
+	}
+	if len(params) != 2 {
+		t.Fatalf("expected 2 constituents, got %+v", params)
+	}
+	if math.Abs(params[0].AmplitudeM-0.62) > 1e-12 || params[0].SpeedDegPerHr != 28.9841042 {
+		t.Errorf("unexpected M2: %+v", params[0])
+	}
+	// Speed override admits a non-standard constituent.
+	if params[1].Name != "X1" || params[1].SpeedDegPerHr != 12.5 || math.Abs(params[1].AmplitudeM-0.05) > 1e-12 {
+		t.Errorf("unexpected X1: %+v", params[1])
+	}
+}
+
+func TestReadStation_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unknown constituent":         "constituent,amplitude_m,phase_deg\nX1,0.1,10\n",
+		"unknown column":              "constituent,amplitude_m,phase_deg,notes\nM2,0.1,10,x\n",
+		"missing phase":               "constituent,amplitude_m\nM2,0.1\n",
+		"meters with cm units":        "# units: cm\nconstituent,amplitude_m,phase_deg\nM2,0.1,10\n",
+		"bad units":                   "# units: fathoms\nconstituent,amplitude,phase_deg\nM2,0.1,10\n",
+		"bad metadata":                "# lat: north\nconstituent,amplitude_m,phase_deg\nM2,0.1,10\n",
+		"short row":                   "constituent,amplitude_m,phase_deg\nM2,0.1\n",
+		"secondary without reference": "# hw_time_offset_min: 10\n",
+		"secondary with table":        "# reference_station: tokyo\nconstituent,amplitude_m,phase_deg\nM2,0.1,10\n",
+		"bad secondary key":           "# reference_station: tokyo\n# hw_height_offset: 1\n",
+	}
+	for name, input := range tests {
+		if _, _, err := ReadStation(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadStation_SetsID(t *testing.T) {
+	dir := t.TempDir()
+	content := "# station_name: Tokyo\nconstituent,amplitude_m,phase_deg\nM2,0.62,145.0\n"
+	if err := os.WriteFile(filepath.Join(dir, "mock_tokyo_constituents.csv"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	meta, params, err := NewConstituentStore(dir).LoadStation("Tokyo")
+	if err != nil {
+		t.Fatalf("LoadStation: %v", err)
+	}
+	if meta.ID != "Tokyo" || meta.Name != "Tokyo" || len(params) != 1 {
+		t.Errorf("unexpected station: %+v %+v", meta, params)
+	}
+}
+
+func TestLoadStation_SecondaryPort(t *testing.T) {
+	dir := t.TempDir()
+	content := strings.Join([]string{
+		"# station_name: Small Harbor",
+		"# reference_station: tokyo",
+		"# hw_time_offset_min: 25",
+		"# lw_time_offset_min: -10.5",
+		"# hw_height_ratio: 0.9",
+		"# lw_height_diff_m: -0.05",
+		"",
+	}, "\n")
+	if err := os.WriteFile(filepath.Join(dir, "mock_harbor_constituents.csv"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	s := NewConstituentStore(dir)
+	meta, params, err := s.LoadStation("harbor")
+	if err != nil {
+		t.Fatalf("LoadStation: %v", err)
+	}
+	want := domain.SecondaryPort{
+		Reference:           "tokyo",
+		HighWaterTimeOffset: 25 * time.Minute,
+		LowWaterTimeOffset:  -630 * time.Second,
+		HighWaterRatio:      0.9,
+		LowWaterRatio:       1,
+		LowWaterDiffM:       -0.05,
+	}
+	if meta.Secondary == nil || *meta.Secondary != want || len(params) != 0 {
+		t.Errorf("unexpected secondary port: %+v %+v", meta.Secondary, params)
+	}
+	if _, err := s.LoadForStation("harbor"); err == nil {
+		t.Error("LoadForStation of a secondary port: expected error")
+	}
+}
+
+func TestSaveStation_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := NewConstituentStore(dir)
+	lat, lon, offset := 35.65, 139.77, 1.1
+	meta := &domain.StationMetadata{Name: "Harumi", Lat: &lat, Lon: &lon, DatumOffsetM: &offset}
+	params := []domain.ConstituentParam{
+		{Name: "M2", AmplitudeM: 0.62, PhaseDeg: 145, SpeedDegPerHr: 28.9841042},
+		{Name: "X1", AmplitudeM: 0.05, PhaseDeg: 10, SpeedDegPerHr: 12.5},
+	}
+	if err := store.SaveStation("Harumi", meta, params); err != nil {
+		t.Fatalf("SaveStation: %v", err)
+	}
+
+	gotMeta, gotParams, err := store.LoadStation("harumi")
+	if err != nil {
+		t.Fatalf("LoadStation: %v", err)
+	}
+	if gotMeta.Name != "Harumi" || *gotMeta.Lat != lat || *gotMeta.DatumOffsetM != offset {
+		t.Errorf("unexpected metadata: %+v", gotMeta)
+	}
+	if len(gotParams) != 2 || gotParams[1] != params[1] {
+		t.Errorf("unexpected constituents: %+v", gotParams)
+	}
+
+	if err := store.SaveStation("../etc", meta, params); err == nil {
+		t.Error("expected error for unsafe station id")
+	}
+}
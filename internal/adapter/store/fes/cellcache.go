@@ -0,0 +1,163 @@
+package fes
+
+import (
+	"container/list"
+	"sync"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+)
+
+const (
+	// cellEntryBytes approximates the memory of a cached cell: its 2x2
+	// values, key, list element and map slot.
+	cellEntryBytes = 320
+	// axesEntryBytes approximates the memory of cached axes besides their
+	// coordinates.
+	axesEntryBytes = 256
+)
+
+// cellFile identifies a variable of a constituent file read with a fill
+// policy.
+type cellFile struct {
+	path     string
+	variable string
+	fill     FillPolicy
+}
+
+// cellKey identifies the 2x2 cell of a file whose lower corner is at grid
+// indices (lat, lon).
+type cellKey struct {
+	file     cellFile
+	lat, lon int
+}
+
+// cellCache is an LRU of the 2x2 grid cells read around points and of the
+// axes of the regular grids they belong to, bounded by an estimate of its
+// memory. Repeated nearby queries (e.g., map tiles or a moving vessel) are
+// interpolated from it without opening NetCDF files. Cached values are
+// shared and must not be modified. A nil cache caches nothing.
+type cellCache struct {
+	limit int64
+
+	mu        sync.Mutex
+	order     *list.List // Front is most recently used.
+	entries   map[any]*list.Element
+	bytes     int64
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type cellEntry struct {
+	key      any // cellKey or cellFile (axes).
+	values   [][]float64
+	lat, lon []float64
+	bytes    int64
+}
+
+func newCellCache(limitBytes int64) *cellCache {
+	return &cellCache{limit: limitBytes, order: list.New(), entries: make(map[any]*list.Element)}
+}
+
+// cell returns the values of a cached cell.
+func (c *cellCache) cell(key cellKey) ([][]float64, bool) {
+	e, ok := c.get(key)
+	if !ok {
+		return nil, false
+	}
+	return e.values, true
+}
+
+// axes returns the cached axes of a regular grid file.
+func (c *cellCache) axes(file cellFile) (lat, lon []float64, ok bool) {
+	e, ok := c.get(file)
+	if !ok {
+		return nil, nil, false
+	}
+	return e.lat, e.lon, true
+}
+
+func (c *cellCache) putCell(key cellKey, values [][]float64) {
+	c.put(&cellEntry{key: key, values: values, bytes: cellEntryBytes})
+}
+
+func (c *cellCache) putAxes(file cellFile, lat, lon []float64) {
+	c.put(&cellEntry{key: file, lat: lat, lon: lon, bytes: axesEntryBytes + 8*int64(len(lat)+len(lon))})
+}
+
+func (c *cellCache) get(key any) (*cellEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	c.hits++
+	return el.Value.(*cellEntry), true
+}
+
+// put adds an entry, evicting the least recently used entries past the
+// memory limit. Entries larger than the limit are not kept.
+func (c *cellCache) put(e *cellEntry) {
+	if c == nil || e.bytes > c.limit {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[e.key]; ok {
+		// Filled concurrently.
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	c.bytes += e.bytes
+	for c.bytes > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		evicted := oldest.Value.(*cellEntry)
+		delete(c.entries, evicted.key)
+		c.bytes -= evicted.bytes
+		c.evictions++
+	}
+}
+
+func (c *cellCache) stats() store.MemoryCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := store.MemoryCacheStats{
+		LimitBytes: c.limit,
+		Bytes:      c.bytes,
+		Entries:    c.order.Len(),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+	if total := c.hits + c.misses; total > 0 {
+		stats.HitRate = float64(c.hits) / float64(total)
+	}
+	return stats
+}
+
+// SetCellCache keeps up to limitBytes (estimated) of the grid cells read
+// around points, so nearby queries skip NetCDF reads; 0 disables it. It
+// must be called before the store is used.
+func (s *Store) SetCellCache(limitBytes int64) {
+	if limitBytes <= 0 {
+		s.cells = nil
+		return
+	}
+	s.cells = newCellCache(limitBytes)
+}
+
+// CellCacheStats reports the grid cell cache; ok is false when disabled.
+func (s *Store) CellCacheStats() (stats store.MemoryCacheStats, ok bool) {
+	if s.cells == nil {
+		return stats, false
+	}
+	return s.cells.stats(), true
+}
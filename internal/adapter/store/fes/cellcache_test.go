@@ -0,0 +1,74 @@
+package fes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCellCache_ServesNearbyPointsWithoutFiles(t *testing.T) {
+	dir := t.TempDir()
+	createCombinedAmpPhaseNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{1, 2}, {3, 4}},
+		[][]float32{{10, 20}, {30, 40}},
+	)
+	s := NewStore(dir)
+	s.SetConstituents([]string{"M2"})
+	s.SetCellCache(1 << 20)
+	if _, err := s.ScanFiles(); err != nil {
+		t.Fatalf("ScanFiles: %v", err)
+	}
+	want, err := NewStore(dir).LoadForLocation(35.6, 139.7)
+	if err != nil {
+		t.Fatalf("LoadForLocation (uncached): %v", err)
+	}
+	if _, err := s.LoadForLocation(35.2, 139.3); err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+
+	// A point of the same cell is interpolated from the cache, without the file.
+	if err := os.Remove(filepath.Join(dir, "m2.nc")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.LoadForLocation(35.6, 139.7)
+	if err != nil {
+		t.Fatalf("LoadForLocation (cached): %v", err)
+	}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("cached = %+v, want %+v", got, want)
+	}
+	stats, ok := s.CellCacheStats()
+	if !ok || stats.Hits == 0 || stats.Entries == 0 || stats.Bytes > stats.LimitBytes {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestCellCache_EvictsPastLimit(t *testing.T) {
+	c := newCellCache(3 * cellEntryBytes)
+	file := cellFile{path: "m2.nc", variable: amplitudeVarName, fill: FillNaN}
+	for i := range 5 {
+		c.putCell(cellKey{file: file, lat: i}, [][]float64{{1, 2}, {3, 4}})
+	}
+	if _, ok := c.cell(cellKey{file: file, lat: 0}); ok {
+		t.Error("least recently used cell kept past the limit")
+	}
+	if _, ok := c.cell(cellKey{file: file, lat: 4}); !ok {
+		t.Error("most recent cell evicted")
+	}
+	stats := c.stats()
+	if stats.Entries != 3 || stats.Evictions != 2 || stats.Bytes != 3*cellEntryBytes {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Axes larger than the whole cache are not kept.
+	c.putAxes(file, make([]float64, 1000), make([]float64, 1000))
+	if _, _, ok := c.axes(file); ok {
+		t.Error("axes larger than the limit cached")
+	}
+
+	var disabled *cellCache
+	disabled.putCell(cellKey{file: file}, nil)
+	if _, ok := disabled.cell(cellKey{file: file}); ok {
+		t.Error("nil cache returned a cell")
+	}
+}
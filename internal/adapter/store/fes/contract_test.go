@@ -0,0 +1,43 @@
+package fes
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/store/storetest"
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+)
+
+// writeSeed writes each seeded constituent as a uniform combined file named
+// by its dataset spelling, with amplitudes in centimeters.
+func writeSeed(t *testing.T, dir string) {
+	t.Helper()
+	uniform := func(v float64) [][]float32 {
+		return [][]float32{{float32(v), float32(v)}, {float32(v), float32(v)}}
+	}
+	for _, c := range storetest.Seed {
+		ncfixture.Write(t, filepath.Join(dir, strings.ToLower(c.Raw)+".nc"), ncfixture.Grid{
+			Lat: testLat,
+			Lon: testLon,
+			Vars: []ncfixture.Var{
+				{Name: "amplitude", Values: uniform(c.AmplitudeM * 100)},
+				{Name: "phase", Values: uniform(c.PhaseDeg)},
+			},
+		})
+	}
+}
+
+func TestConstituentLoaderContract(t *testing.T) {
+	storetest.Run(t, storetest.Harness{
+		Locations: true,
+		New: func(t *testing.T) store.ConstituentLoader {
+			dir := t.TempDir()
+			writeSeed(t, dir)
+			s := NewStore(dir)
+			s.SetConstituents(nil)
+			return s
+		},
+	})
+}
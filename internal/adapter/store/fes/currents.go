@@ -0,0 +1,180 @@
+package fes
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.ngs.io/tides-api/internal/adapter/retry"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	eastwardDir  = "eastward_velocity"
+	northwardDir = "northward_velocity"
+)
+
+// velocityVars names the amplitude (cm/s) and phase (degrees) variables of
+// a velocity component in the FES2014 current files.
+type velocityVars struct {
+	amplitude, phase string
+}
+
+var (
+	eastwardVars  = velocityVars{amplitude: "Ua", phase: "Ug"}
+	northwardVars = velocityVars{amplitude: "Va", phase: "Vg"}
+)
+
+// CurrentStore reads FES tidal current constituents: one file per
+// constituent under eastward_velocity/ and northward_velocity/, as in the
+// FES2014 distribution (e.g., eastward_velocity/m2.nc holding Ua and Ug).
+// It is separate from Store because the elevation store matches files by
+// base name and would pick up velocity files.
+type CurrentStore struct {
+	dataDir   string
+	fill      FillPolicy
+	wetRadius int // FillNearest search radius in grid cells.
+
+	mu      sync.Mutex
+	scanned bool
+	files   map[string][2]string // Eastward and northward file of each constituent.
+}
+
+// NewCurrentStore creates a store for the current files under dataDir. The
+// directory is scanned on first use.
+func NewCurrentStore(dataDir string) *CurrentStore {
+	return &CurrentStore{dataDir: dataDir, fill: FillNaN, wetRadius: nearestWetRadius}
+}
+
+// SetFillPolicy sets how fill values are treated (default FillNaN).
+func (s *CurrentStore) SetFillPolicy(p FillPolicy) {
+	s.fill = p
+}
+
+// SetNearestWetRadius sets the FillNearest search radius in grid cells
+// (default nearestWetRadius).
+func (s *CurrentStore) SetNearestWetRadius(cells int) {
+	s.wetRadius = cells
+}
+
+// scan pairs the eastward and northward files of each constituent once.
+// Constituents missing either component are left out.
+func (s *CurrentStore) scan() (map[string][2]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scanned {
+		return s.files, nil
+	}
+	list := func(dir string) (map[string]string, error) {
+		entries, err := os.ReadDir(filepath.Join(s.dataDir, dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read FES current directory: %w", err)
+		}
+		files := make(map[string]string)
+		for _, e := range entries {
+			base := strings.ToLower(e.Name())
+			if e.IsDir() || filepath.Ext(base) != ".nc" {
+				continue
+			}
+			if name, ok := domain.CanonicalConstituentName(strings.TrimSuffix(base, ".nc")); ok {
+				files[name] = filepath.Join(s.dataDir, dir, e.Name())
+			}
+		}
+		return files, nil
+	}
+	east, err := list(eastwardDir)
+	if err != nil {
+		return nil, err
+	}
+	north, err := list(northwardDir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][2]string)
+	for name, e := range east {
+		if n, ok := north[name]; ok {
+			files[name] = [2]string{e, n}
+		}
+	}
+	s.files, s.scanned = files, true
+	return files, nil
+}
+
+// Constituents returns the constituents with both current components,
+// sorted.
+func (s *CurrentStore) Constituents() ([]string, error) {
+	files, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(files)), nil
+}
+
+// LoadCurrentsForLocation interpolates the eastward and northward velocity
+// constituents at a location, with amplitudes in m/s and Greenwich phase
+// lags. Constituents are returned in the same order for both components.
+// A location without any constituent wraps domain.ErrOutOfCoverage.
+func (s *CurrentStore) LoadCurrentsForLocation(lat, lon float64) (east, north []domain.ConstituentParam, err error) {
+	files, err := s.scan()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no FES current files found in %s", s.dataDir)
+	}
+	point := []domain.Position{{Lat: lat, Lon: normalizeLon360(lon)}}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		speed, ok := domain.GetConstituentSpeed(name)
+		if !ok {
+			continue
+		}
+		u, err := s.interpolate(files[name][0], eastwardVars, point)
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("constituent %s eastward velocity: %w", name, err)
+		}
+		v, err := s.interpolate(files[name][1], northwardVars, point)
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("constituent %s northward velocity: %w", name, err)
+		}
+		east = append(east, domain.ConstituentParam{Name: name, AmplitudeM: u[0], PhaseDeg: u[1], SpeedDegPerHr: speed})
+		north = append(north, domain.ConstituentParam{Name: name, AmplitudeM: v[0], PhaseDeg: v[1], SpeedDegPerHr: speed})
+	}
+	if len(east) == 0 {
+		return nil, nil, fmt.Errorf("no FES currents at (%.4f, %.4f): %w", lat, lon, domain.ErrOutOfCoverage)
+	}
+	return east, north, nil
+}
+
+// interpolate reads the amplitude (converted from cm/s to m/s) and phase
+// of a velocity component at a point.
+func (s *CurrentStore) interpolate(path string, names velocityVars, point []domain.Position) ([2]float64, error) {
+	var out [2]float64
+	for i, name := range []string{names.amplitude, names.phase} {
+		var values []float64
+		var errs []error
+		err := retry.Do(retryOp, func() (err error) {
+			values, _, errs, err = interpolatePointsFromNetCDF(path, nil, name, point, s.fill, s.wetRadius, nil)
+			return err
+		})
+		if err != nil {
+			return out, fmt.Errorf("failed to interpolate %s: %w", name, err)
+		}
+		if errs[0] != nil {
+			return out, errs[0]
+		}
+		out[i] = values[0]
+	}
+	out[0] /= 100.0
+	return out, nil
+}
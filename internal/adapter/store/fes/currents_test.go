@@ -0,0 +1,64 @@
+package fes
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// writeCurrents writes uniform M2 velocity files in the FES2014 layout,
+// with amplitudes in cm/s, and an S2 file with only the eastward component.
+func writeCurrents(t *testing.T, dir string) {
+	t.Helper()
+	uniform := func(v float32) [][]float32 { return [][]float32{{v, v}, {v, v}} }
+	for _, sub := range []string{eastwardDir, northwardDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ncfixture.Write(t, filepath.Join(dir, eastwardDir, "m2.nc"), ncfixture.Grid{
+		Lat: testLat, Lon: testLon,
+		Vars: []ncfixture.Var{{Name: "Ua", Values: uniform(30)}, {Name: "Ug", Values: uniform(120)}},
+	})
+	ncfixture.Write(t, filepath.Join(dir, northwardDir, "m2.nc"), ncfixture.Grid{
+		Lat: testLat, Lon: testLon,
+		Vars: []ncfixture.Var{{Name: "Va", Values: uniform(40)}, {Name: "Vg", Values: uniform(300)}},
+	})
+	ncfixture.Write(t, filepath.Join(dir, eastwardDir, "s2.nc"), ncfixture.Grid{
+		Lat: testLat, Lon: testLon,
+		Vars: []ncfixture.Var{{Name: "Ua", Values: uniform(10)}, {Name: "Ug", Values: uniform(0)}},
+	})
+}
+
+func TestCurrentStore_LoadCurrentsForLocation(t *testing.T) {
+	dir := t.TempDir()
+	writeCurrents(t, dir)
+	s := NewCurrentStore(dir)
+
+	names, err := s.Constituents()
+	if err != nil || len(names) != 1 || names[0] != "M2" {
+		t.Fatalf("Constituents() = %v, %v, want [M2]", names, err)
+	}
+	east, north, err := s.LoadCurrentsForLocation(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadCurrentsForLocation: %v", err)
+	}
+	if len(east) != 1 || len(north) != 1 {
+		t.Fatalf("got %d eastward and %d northward constituents, want 1", len(east), len(north))
+	}
+	if math.Abs(east[0].AmplitudeM-0.3) > 1e-6 || math.Abs(east[0].PhaseDeg-120) > 1e-4 {
+		t.Errorf("eastward M2 = %.4f m/s, %.2f°, want 0.3 m/s, 120°", east[0].AmplitudeM, east[0].PhaseDeg)
+	}
+	if math.Abs(north[0].AmplitudeM-0.4) > 1e-6 || math.Abs(north[0].PhaseDeg-300) > 1e-4 {
+		t.Errorf("northward M2 = %.4f m/s, %.2f°, want 0.4 m/s, 300°", north[0].AmplitudeM, north[0].PhaseDeg)
+	}
+
+	if _, _, err := s.LoadCurrentsForLocation(10, 139.5); !errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Errorf("outside the grid: error = %v, want ErrOutOfCoverage", err)
+	}
+}
@@ -0,0 +1,156 @@
+package fes
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	"go.ngs.io/tides-api/internal/adapter/interp"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// curvilinearGrids holds the located grids of curvilinear files by path,
+// rebuilt when a file changes: indexing the cells of a regional grid costs
+// far more than a point read.
+//
+//nolint:gochecknoglobals // Intentional: Shared by the stores reading the same files.
+var curvilinearGrids = struct {
+	sync.Mutex
+	byPath map[string]cachedGrid
+}{byPath: make(map[string]cachedGrid)}
+
+type cachedGrid struct {
+	size    int64
+	modTime time.Time
+	vars    Variables
+	grid    *interp.CurvilinearGrid
+}
+
+// is2D reports whether a coordinate variable is 2D, as in curvilinear
+// (e.g., rotated) regional models.
+func is2D(nc netcdf.Dataset, name string) bool {
+	v, err := nc.Var(name)
+	if err != nil {
+		return false
+	}
+	dims, err := v.Dims()
+	return err == nil && len(dims) == 2
+}
+
+// curvilinearGrid returns the located grid of a file with 2D latitude and
+// longitude variables.
+func curvilinearGrid(nc netcdf.Dataset, path string, vars Variables) (*interp.CurvilinearGrid, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	curvilinearGrids.Lock()
+	cached, ok := curvilinearGrids.byPath[path]
+	curvilinearGrids.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) && cached.vars == vars {
+		return cached.grid, nil
+	}
+
+	lat, err := readCoordinate2D(nc, vars.Lat)
+	if err != nil {
+		return nil, fmt.Errorf("latitude variable %s: %w", vars.Lat, err)
+	}
+	lon, err := readCoordinate2D(nc, vars.Lon)
+	if err != nil {
+		return nil, fmt.Errorf("longitude variable %s: %w", vars.Lon, err)
+	}
+	grid, err := interp.NewCurvilinearGrid(lon, lat)
+	if err != nil {
+		return nil, fmt.Errorf("curvilinear grid: %w", err)
+	}
+
+	curvilinearGrids.Lock()
+	curvilinearGrids.byPath[path] = cachedGrid{size: info.Size(), modTime: info.ModTime(), vars: vars, grid: grid}
+	curvilinearGrids.Unlock()
+	return grid, nil
+}
+
+// readCoordinate2D reads a 2D coordinate variable with fill values as NaN.
+func readCoordinate2D(nc netcdf.Dataset, name string) ([][]float64, error) {
+	v, err := nc.Var(name)
+	if err != nil {
+		return nil, err
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dimensions: %w", err)
+	}
+	if len(dims) != 2 {
+		return nil, fmt.Errorf("expected 2D variable, got %dD", len(dims))
+	}
+	rows, err := dims[0].Len()
+	if err != nil {
+		return nil, err
+	}
+	cols, err := dims[1].Len()
+	if err != nil {
+		return nil, err
+	}
+	values, err := read2DFloat64Var(v, int(rows), int(cols)) //nolint:gosec // G115: NetCDF dimensions fit in int.
+	if err != nil {
+		return nil, err
+	}
+	maskFill(values, v, FillNaN)
+	return values, nil
+}
+
+// interpolateCurvilinear interpolates at a point of a curvilinear grid,
+// reading the 4 nodes of the cell containing it. lon is in [0, 360);
+// it is wrapped to [-180, 180) for grids using that convention. fallbackKm
+// is as for interpolateRegular.
+func interpolateCurvilinear(g *interp.CurvilinearGrid, sample func(row0, col0, nRows, nCols int) ([][]float64, error), lat, lon float64, fill FillPolicy, radius int) (value, fallbackKm float64, err error) {
+	if minLon, _, _, _ := g.Bounds(); minLon < 0 && lon >= 180 {
+		lon -= 360
+	}
+	cell, ok := g.Locate(lon, lat)
+	if !ok {
+		return 0, 0, fmt.Errorf("point (%.4f, %.4f) outside grid bounds: %w", lat, lon, domain.ErrOutOfCoverage)
+	}
+
+	values, err := sample(cell.I, cell.J, 2, 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	result := weightedMean([4]float64{values[0][0], values[0][1], values[1][0], values[1][1]}, cell.Weights())
+	if !math.IsNaN(result) {
+		return result, 0, nil
+	}
+
+	// All nodes of the cell are fill (land or outside the model domain).
+	if fill == FillNearest && radius > 0 {
+		rows, cols := g.Shape()
+		row0, row1 := max(cell.I-radius, 0), min(cell.I+1+radius, rows-1)
+		col0, col1 := max(cell.J-radius, 0), min(cell.J+1+radius, cols-1)
+		window, err := sample(row0, col0, row1-row0+1, col1-col0+1)
+		if err != nil {
+			return 0, 0, err
+		}
+		cosLat := math.Cos(domain.Deg2Rad(lat))
+		best, found := math.Inf(1), false
+		for i := range window {
+			for j := range window[i] {
+				if math.IsNaN(window[i][j]) {
+					continue
+				}
+				dLat := g.Y[row0+i][col0+j] - lat
+				dLon := (g.X[row0+i][col0+j] - lon) * cosLat
+				if d := dLat*dLat + dLon*dLon; d < best {
+					best, value, found = d, window[i][j], true
+				}
+			}
+		}
+		if found {
+			return value, math.Sqrt(best) * kmPerDegree, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("point (%.4f, %.4f): %w", lat, lon, domain.ErrOutOfCoverage)
+}
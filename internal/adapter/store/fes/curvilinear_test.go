@@ -0,0 +1,86 @@
+package fes
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// writeRotatedNC writes a 10x10 grid with 0.1° spacing rotated by 20°
+// around (lat0, lon0), with amplitude (cm) linear in lat/lon and a
+// constant phase.
+func writeRotatedNC(t *testing.T, path string, lat0, lon0 float64) {
+	t.Helper()
+	sin, cos := math.Sincos(domain.Deg2Rad(20))
+	const n = 10
+	lat, lon := make([][]float64, n), make([][]float64, n)
+	amp, pha := make([][]float32, n), make([][]float32, n)
+	for i := range n {
+		lat[i], lon[i] = make([]float64, n), make([]float64, n)
+		amp[i], pha[i] = make([]float32, n), make([]float32, n)
+		for j := range n {
+			lon[i][j] = lon0 + 0.1*(float64(j)*cos-float64(i)*sin)
+			lat[i][j] = lat0 + 0.1*(float64(j)*sin+float64(i)*cos)
+			amp[i][j] = float32(rotatedAmplitude(lat[i][j]-lat0, lon[i][j]-lon0))
+			pha[i][j] = 45
+		}
+	}
+	ncfixture.WriteCurvilinear(t, path, ncfixture.Curvilinear{Lat: lat, Lon: lon, Vars: []ncfixture.Var{
+		{Name: "amplitude", Values: amp},
+		{Name: "phase", Values: pha},
+	}})
+}
+
+// rotatedAmplitude is the amplitude field in cm at offsets from the grid origin.
+func rotatedAmplitude(dLat, dLon float64) float64 {
+	return 50 + 20*dLat - 10*dLon
+}
+
+func TestLoadForLocation_CurvilinearGrid(t *testing.T) {
+	dir := t.TempDir()
+	writeRotatedNC(t, filepath.Join(dir, "m2.nc"), 35, 139)
+
+	s := NewStore(dir)
+	params, err := s.LoadForLocation(35.4, 139.3)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	// Bilinear interpolation is exact for a linear field on a rotated grid.
+	if got, want := params[0].AmplitudeM, rotatedAmplitude(0.4, 0.3)/100; math.Abs(got-want) > 1e-6 {
+		t.Errorf("amplitude = %v, want %v", got, want)
+	}
+	if got := params[0].PhaseDeg; math.Abs(got-45) > 1e-6 {
+		t.Errorf("phase = %v, want 45", got)
+	}
+
+	// Inside the bounding box, outside the rotated grid.
+	_, err = s.LoadForLocation(35.05, 138.72)
+	if !errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Errorf("error = %v, want out of coverage", err)
+	}
+
+	idx, err := BuildIndex(dir)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+	if f := idx.Constituents["M2"].Amplitude; !f.Curvilinear || f.Lat.Size != 100 || f.Lon.Min >= 139 {
+		t.Errorf("indexed amplitude file = %+v, want a curvilinear grid of 100 nodes", f)
+	}
+}
+
+func TestLoadForLocation_CurvilinearGridNegativeLongitudes(t *testing.T) {
+	dir := t.TempDir()
+	writeRotatedNC(t, filepath.Join(dir, "m2.nc"), 40, -70)
+
+	params, err := NewStore(dir).LoadForLocation(40.4, -69.7)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	if got, want := params[0].AmplitudeM, rotatedAmplitude(0.4, 0.3)/100; math.Abs(got-want) > 1e-6 {
+		t.Errorf("amplitude = %v, want %v", got, want)
+	}
+}
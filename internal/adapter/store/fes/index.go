@@ -0,0 +1,340 @@
+package fes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// IndexFileName is the index file looked up in a FES directory.
+	IndexFileName = "fes-index.json"
+	// IndexFormat is the version of the index file layout.
+	IndexFormat = 1
+)
+
+// Index maps the constituents of a FES directory to their files and
+// variables, as found once by the fes-index command. A store with an index
+// neither scans the directory nor guesses variable names.
+type Index struct {
+	Format       int                   `json:"format"`
+	Created      time.Time             `json:"created"`
+	Constituents map[string]IndexEntry `json:"constituents"` // By canonical name.
+}
+
+// IndexEntry holds the amplitude and phase files of a constituent. Both
+// may be the same (combined) file.
+type IndexEntry struct {
+	Amplitude IndexedFile `json:"amplitude"`
+	Phase     IndexedFile `json:"phase"`
+}
+
+// IndexedFile describes a constituent file.
+type IndexedFile struct {
+	Path      string    `json:"path"` // Relative to the FES directory, slash-separated.
+	Variables Variables `json:"variables"`
+	Units     string    `json:"units,omitempty"` // Of the data (or real) variable.
+	// Curvilinear marks 2D latitude and longitude variables (e.g., a
+	// rotated regional grid).
+	Curvilinear bool `json:"curvilinear,omitempty"`
+	Lat         Axis `json:"lat"`
+	Lon         Axis `json:"lon"`
+}
+
+// Axis describes a coordinate variable. Min and Max bound the grid.
+type Axis struct {
+	Size  int     `json:"size"` // Values (nodes of a 2D variable).
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Step  float64 `json:"step,omitempty"` // Mean spacing of a 1D axis.
+	Units string  `json:"units,omitempty"`
+}
+
+// Names returns the indexed constituents sorted by name.
+func (idx *Index) Names() []string {
+	return slices.Sorted(maps.Keys(idx.Constituents))
+}
+
+// BuildIndex scans dataDir and describes the files of every constituent
+// the server would find there. Constituents whose files cannot be read are
+// logged and left out.
+func BuildIndex(dataDir string) (*Index, error) {
+	s := NewStore(dataDir)
+	names, err := s.GetAvailableConstituents()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	config := DefaultConfig()
+	idx := &Index{
+		Format:       IndexFormat,
+		Created:      time.Now().UTC(),
+		Constituents: make(map[string]IndexEntry, len(names)),
+	}
+	for _, name := range names {
+		ampPath, phaPath, err := s.findConstituentFiles(name)
+		if err != nil {
+			log.Printf("Warning: FES constituent %s not indexed: %v", name, err)
+			continue
+		}
+		amp, err := describeFile(dataDir, ampPath, config.AmplitudeVarName)
+		if err != nil {
+			log.Printf("Warning: FES constituent %s not indexed: %v", name, err)
+			continue
+		}
+		pha, err := describeFile(dataDir, phaPath, config.PhaseVarName)
+		if err != nil {
+			log.Printf("Warning: FES constituent %s not indexed: %v", name, err)
+			continue
+		}
+		idx.Constituents[name] = IndexEntry{Amplitude: amp, Phase: pha}
+	}
+	if len(idx.Constituents) == 0 {
+		return nil, fmt.Errorf("no FES constituents indexed in %s", dataDir)
+	}
+	return idx, nil
+}
+
+// describeFile detects the variables of a constituent file and reads its axes.
+func describeFile(dataDir, path, dataVarName string) (IndexedFile, error) {
+	rel, err := filepath.Rel(dataDir, path)
+	if err != nil {
+		return IndexedFile{}, err
+	}
+	nc, err := netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		return IndexedFile{}, fmt.Errorf("failed to open %s: %w", rel, err)
+	}
+	defer func() { _ = nc.Close() }()
+
+	config := DefaultConfig()
+	vars, err := detectVariables(nc, config.LatVarName, config.LonVarName, dataVarName)
+	if err != nil {
+		return IndexedFile{}, fmt.Errorf("%s: %w", rel, err)
+	}
+	f := IndexedFile{Path: filepath.ToSlash(rel), Variables: vars, Curvilinear: is2D(nc, vars.Lat)}
+	if f.Lat, err = describeAxis(nc, vars.Lat); err != nil {
+		return IndexedFile{}, fmt.Errorf("%s: latitude %s: %w", rel, vars.Lat, err)
+	}
+	if f.Lon, err = describeAxis(nc, vars.Lon); err != nil {
+		return IndexedFile{}, fmt.Errorf("%s: longitude %s: %w", rel, vars.Lon, err)
+	}
+	data := vars.Data
+	if data == "" {
+		data = vars.Real
+	}
+	if v, err := nc.Var(data); err == nil {
+		f.Units = textAttr(v, "units")
+	}
+	return f, nil
+}
+
+// describeAxis reads the extent of a 1D or 2D coordinate variable.
+func describeAxis(nc netcdf.Dataset, name string) (Axis, error) {
+	var a Axis
+	if is2D(nc, name) {
+		nodes, err := readCoordinate2D(nc, name)
+		if err != nil {
+			return Axis{}, err
+		}
+		a.Min, a.Max = math.Inf(1), math.Inf(-1)
+		for _, row := range nodes {
+			for _, v := range row {
+				if !math.IsNaN(v) {
+					a.Size++
+					a.Min, a.Max = math.Min(a.Min, v), math.Max(a.Max, v)
+				}
+			}
+		}
+		if a.Size == 0 {
+			return Axis{}, errors.New("no valid coordinates")
+		}
+	} else {
+		values, err := readCoordinate(nc, name)
+		if err != nil {
+			return Axis{}, err
+		}
+		if len(values) == 0 {
+			return Axis{}, errors.New("empty axis")
+		}
+		a = Axis{Size: len(values), Min: slices.Min(values), Max: slices.Max(values)}
+		if len(values) > 1 {
+			a.Step = (a.Max - a.Min) / float64(len(values)-1)
+		}
+	}
+	if v, err := nc.Var(name); err == nil {
+		a.Units = textAttr(v, "units")
+	}
+	return a, nil
+}
+
+// textAttr returns a text attribute of a variable, or "" if absent.
+func textAttr(v netcdf.Var, name string) string {
+	a := v.Attr(name)
+	n, err := a.Len()
+	if err != nil || n == 0 {
+		return ""
+	}
+	buf := make([]byte, n)
+	if err := a.ReadBytes(buf); err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(buf), "\x00")
+}
+
+// ReadIndex reads an index file.
+func ReadIndex(path string) (*Index, error) {
+	//nolint:gosec // G304: File path from env var or flag.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FES index: %w", err)
+	}
+	var idx Index
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return nil, fmt.Errorf("invalid FES index %s: %w", path, err)
+	}
+	if idx.Format != IndexFormat {
+		return nil, fmt.Errorf("FES index %s has format %d, want %d (rebuild it with fes-index)", path, idx.Format, IndexFormat)
+	}
+	return &idx, nil
+}
+
+// Write writes the index to path, replacing it atomically.
+func (idx *Index) Write(path string) error {
+	b, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode FES index: %w", err)
+	}
+	tmp := path + ".tmp"
+	//nolint:gosec // G306: The index is not secret.
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write FES index: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write FES index: %w", err)
+	}
+	return nil
+}
+
+// SetIndex makes the store use an index instead of scanning its data
+// directory. It fails if a constituent is unknown or an indexed file is
+// missing, so a stale index is caught at startup.
+func (s *Store) SetIndex(idx *Index) error {
+	if len(idx.Constituents) == 0 {
+		return errors.New("FES index lists no constituents")
+	}
+	for name, entry := range idx.Constituents {
+		if _, ok := domain.GetConstituentSpeed(name); !ok {
+			return fmt.Errorf("FES index: unknown constituent %s", name)
+		}
+		for _, f := range []IndexedFile{entry.Amplitude, entry.Phase} {
+			if _, err := os.Stat(s.indexedPath(f.Path)); err != nil {
+				return fmt.Errorf("FES index: constituent %s: %w (rebuild it with fes-index)", name, err)
+			}
+		}
+	}
+	s.mu.Lock()
+	s.index = idx
+	s.mu.Unlock()
+	return nil
+}
+
+// LoadIndex reads an index file and sets it on the store.
+func (s *Store) LoadIndex(path string) error {
+	idx, err := ReadIndex(path)
+	if err != nil {
+		return err
+	}
+	if err := s.SetIndex(idx); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// scannedFiles are the amplitude and phase files of a constituent.
+type scannedFiles struct {
+	amplitude, phase string
+}
+
+// ScanFiles walks the data directory once and keeps the file paths of
+// every constituent found, so reads no longer walk the directory. It is the
+// in-memory counterpart of an index for directories without one: files
+// added or removed later are seen after a restart. It returns the number
+// of constituents found.
+func (s *Store) ScanFiles() (int, error) {
+	names, err := s.GetAvailableConstituents()
+	if err != nil {
+		return 0, err
+	}
+	files := make(map[string]scannedFiles, len(names))
+	for _, name := range names {
+		amp, pha, err := s.findConstituentFiles(name)
+		if err != nil {
+			log.Printf("Warning: FES constituent %s skipped: %v", name, err)
+			continue
+		}
+		files[name] = scannedFiles{amplitude: amp, phase: pha}
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("no FES NetCDF files found in %s", s.dataDir)
+	}
+	s.mu.Lock()
+	s.files = files
+	s.mu.Unlock()
+	return len(files), nil
+}
+
+// indexedPath resolves a path of the index against the data directory.
+func (s *Store) indexedPath(rel string) string {
+	return filepath.Join(s.dataDir, filepath.FromSlash(rel))
+}
+
+// ConstituentCoverage describes the amplitude grid of a constituent, from
+// the index if set, else by reading the coordinates of its file.
+func (s *Store) ConstituentCoverage(name string) (store.GridCoverage, error) {
+	canonical, ok := domain.CanonicalConstituentName(name)
+	if !ok {
+		return store.GridCoverage{}, fmt.Errorf("unknown constituent %s: %w", name, domain.ErrConstituentUnavailable)
+	}
+	available, err := s.GetAvailableConstituents()
+	if err != nil {
+		return store.GridCoverage{}, fmt.Errorf("failed to get available constituents: %w", err)
+	}
+	if !slices.Contains(available, canonical) {
+		return store.GridCoverage{}, fmt.Errorf("%s: %w", canonical, domain.ErrConstituentUnavailable)
+	}
+
+	s.mu.RLock()
+	idx := s.index
+	s.mu.RUnlock()
+	var f IndexedFile
+	if idx != nil {
+		f = idx.Constituents[canonical].Amplitude
+	} else {
+		amp, _, err := s.constituentFiles(canonical)
+		if err != nil {
+			return store.GridCoverage{}, fmt.Errorf("%s: %w", err, domain.ErrConstituentUnavailable)
+		}
+		if f, err = describeFile(s.dataDir, amp.path, DefaultConfig().AmplitudeVarName); err != nil {
+			return store.GridCoverage{}, err
+		}
+	}
+	axis := func(a Axis) store.GridAxis {
+		return store.GridAxis{Size: a.Size, Min: a.Min, Max: a.Max, Step: a.Step}
+	}
+	return store.GridCoverage{Curvilinear: f.Curvilinear, Lat: axis(f.Lat), Lon: axis(f.Lon)}, nil
+}
@@ -0,0 +1,106 @@
+package fes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIndex_MatchesDirectoryScan(t *testing.T) {
+	dir := t.TempDir()
+	createCombinedAmpPhaseNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{1, 2}, {3, 4}},
+		[][]float32{{10, 20}, {30, 40}},
+	)
+	createCombinedReImNC(t, filepath.Join(dir, "ocean_tide", "s2.nc"),
+		[][]float32{{3, 3}, {3, 3}},
+		[][]float32{{4, 4}, {4, 4}},
+	)
+
+	idx, err := BuildIndex(dir)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+	s2 := idx.Constituents["S2"]
+	if s2.Amplitude.Path != "ocean_tide/s2.nc" || s2.Amplitude.Variables.Real != "hRe" || s2.Amplitude.Variables.Imag != "hIm" {
+		t.Errorf("S2 amplitude = %+v, want the hRe/hIm pair of ocean_tide/s2.nc", s2.Amplitude)
+	}
+	m2 := idx.Constituents["M2"]
+	if m2.Phase.Variables.Data != "phase" || m2.Amplitude.Lat.Size != 2 || m2.Amplitude.Lon.Min != 139 || m2.Amplitude.Lon.Max != 140 {
+		t.Errorf("M2 = %+v, want phase variable and the fixture axes", m2)
+	}
+
+	path := filepath.Join(dir, IndexFileName)
+	if err := idx.Write(path); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	indexed := NewStore(dir)
+	if err := indexed.LoadIndex(path); err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	want, err := NewStore(dir).LoadForLocation(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation (scan): %v", err)
+	}
+	got, err := indexed.LoadForLocation(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation (index): %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("constituent %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIndex_StaleIndexRejected(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "m2.nc")
+	createCombinedAmpPhaseNC(t, file,
+		[][]float32{{1, 2}, {3, 4}},
+		[][]float32{{10, 20}, {30, 40}},
+	)
+	idx, err := BuildIndex(dir)
+	if err != nil {
+		t.Fatalf("BuildIndex: %v", err)
+	}
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewStore(dir).SetIndex(idx); err == nil {
+		t.Error("expected an error for an index listing a missing file")
+	}
+}
+
+func TestScanFiles_KeepsPathsOfStartup(t *testing.T) {
+	dir := t.TempDir()
+	createCombinedAmpPhaseNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{1, 2}, {3, 4}},
+		[][]float32{{10, 20}, {30, 40}},
+	)
+	s := NewStore(dir)
+	s.SetConstituents(nil)
+	if n, err := s.ScanFiles(); err != nil || n != 1 {
+		t.Fatalf("ScanFiles = %d, %v; want 1 constituent", n, err)
+	}
+
+	// Files added after the scan are not seen.
+	createCombinedAmpPhaseNC(t, filepath.Join(dir, "s2.nc"),
+		[][]float32{{1, 1}, {1, 1}},
+		[][]float32{{0, 0}, {0, 0}},
+	)
+	params, err := s.LoadForLocation(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	if len(params) != 1 || params[0].Name != "M2" {
+		t.Errorf("params = %+v, want M2 only", params)
+	}
+
+	if _, err := NewStore(t.TempDir()).ScanFiles(); err == nil {
+		t.Error("expected an error for a directory without constituent files")
+	}
+}
@@ -2,29 +2,145 @@
 package fes
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
+	"log"
+	"maps"
 	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fhs/go-netcdf/netcdf"
 
+	"go.ngs.io/tides-api/internal/adapter/circuit"
 	"go.ngs.io/tides-api/internal/adapter/interp"
-	"go.ngs.io/tides-api/internal/domain"
+	"go.ngs.io/tides-api/internal/adapter/ncfill"
+	"go.ngs.io/tides-api/internal/adapter/retry"
+	"go.ngs.io/tides-api/internal/metrics"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
 const (
 	amplitudeVarName = "amplitude"
+
+	// nearestWetRadius is the default search radius in grid cells for
+	// FillNearest.
+	nearestWetRadius = 4
+
+	// kmPerDegree converts distances in degrees of latitude to kilometers.
+	kmPerDegree = 111.195
+
+	// retryOp names FES file reads in retry stats.
+	retryOp = "fes"
+
+	// Constituent circuits open after circuitThreshold consecutive read
+	// failures and are retried after circuitMinCooldown, doubling up to
+	// circuitMaxCooldown.
+	circuitThreshold   = 3
+	circuitMinCooldown = 30 * time.Second
+	circuitMaxCooldown = 10 * time.Minute
+)
+
+// FillPolicy selects how NetCDF fill values (land or no-data cells) are
+// treated when interpolating at a point.
+type FillPolicy string
+
+const (
+	// FillNaN excludes fill cells from interpolation. Points with no wet
+	// neighbor are out of coverage.
+	FillNaN FillPolicy = "nan"
+	// FillNearest is FillNaN, but points with no wet neighbor take the value
+	// of the nearest wet grid point within the search radius (default
+	// nearestWetRadius cells; see Store.SetNearestWetRadius).
+	FillNearest FillPolicy = "nearest"
+	// FillZero replaces fill values with 0 (legacy behavior; biases coastal
+	// amplitudes and phases toward zero).
+	FillZero FillPolicy = "zero"
 )
 
+// ParseFillPolicy parses "nan", "nearest" or "zero".
+func ParseFillPolicy(s string) (FillPolicy, error) {
+	switch p := FillPolicy(strings.ToLower(s)); p {
+	case FillNaN, FillNearest, FillZero:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown fill policy %q (want nan, nearest or zero)", s)
+	}
+}
+
+// AllConstituents selects every constituent with files in the data directory.
+const AllConstituents = "all"
+
+// DefaultConstituents returns the constituents requested for a location by
+// default: the 8 major constituents (~95% of the tidal signal in deep water),
+// shallow-water overtides for coastal accuracy, and the long-period
+// constituents of extended datasets (e.g., FES2014's 34 waves).
+func DefaultConstituents() []string {
+	return []string{
+		"M2", "S2", "N2", "K2", "K1", "O1", "P1", "Q1",
+		"M4", "MS4", "MN4", "S4",
+		"Mf", "Mm", "MSf", "Mtm", "MSqm", "Ssa", "Sa", "Node",
+	}
+}
+
+// ParseConstituents parses a requested constituent set: "" or "default" for
+// DefaultConstituents, "all" for every available constituent (nil), or a
+// comma-separated list of constituent names.
+func ParseConstituents(s string) ([]string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "default":
+		return DefaultConstituents(), nil
+	case AllConstituents:
+		return nil, nil
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, ok := domain.CanonicalConstituentName(field)
+		if !ok {
+			return nil, fmt.Errorf("unknown constituent %q", field)
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no constituents in %q (want default, all or a comma-separated list)", s)
+	}
+	return names, nil
+}
+
 // Store provides access to FES2014/2022 NetCDF tidal constituent data.
 type Store struct {
-	dataDir string
-	cache   map[string]*Grid // Cache loaded grids.
-	mu      sync.RWMutex     // Protect cache.
+	dataDir  string
+	cache    map[string]*Grid // Cache loaded grids.
+	mu       sync.RWMutex     // Protect cache.
+	reported map[string]bool  // Unknown constituents already logged.
+	// File base names of each constituent (e.g., "la2" for LAM2), as found
+	// by GetAvailableConstituents.
+	spellings map[string][]string
+	fill      FillPolicy   // Fill value handling for point interpolation.
+	wetRadius int          // FillNearest search radius in grid cells.
+	circuits  *circuit.Set // Per-constituent read failures.
+	// Constituents requested for a location; nil requests all available.
+	constituents []string
+	// Constituent files found by fes-index; nil scans the data directory.
+	index *Index
+	// Constituent files found by ScanFiles; nil walks the data directory
+	// on every read.
+	files map[string]scannedFiles
+	cells *cellCache // Grid cells read around points; nil disables.
 }
 
 // Grid holds amplitude and phase grids for a constituent.
@@ -62,40 +178,80 @@ func DefaultConfig() FileConfig {
 // NewStore creates a new FES NetCDF store.
 func NewStore(dataDir string) *Store {
 	return &Store{
-		dataDir: dataDir,
-		cache:   make(map[string]*Grid),
+		dataDir:      dataDir,
+		cache:        make(map[string]*Grid),
+		reported:     make(map[string]bool),
+		fill:         FillNaN,
+		wetRadius:    nearestWetRadius,
+		constituents: DefaultConstituents(),
+		circuits: circuit.NewSet(circuit.Config{
+			Threshold:   circuitThreshold,
+			MinCooldown: circuitMinCooldown,
+			MaxCooldown: circuitMaxCooldown,
+		}),
 	}
 }
 
+// Circuits reports constituents whose reads are failing. While a circuit
+// is open the constituent is skipped; a trial read is made periodically.
+func (s *Store) Circuits() []circuit.Status {
+	return s.circuits.Failing()
+}
+
+// SetConstituents sets the constituents requested for a location (see
+// ParseConstituents); nil requests all available constituents.
+func (s *Store) SetConstituents(names []string) {
+	s.constituents = names
+}
+
+// SetFillPolicy sets how fill values are treated (default FillNaN).
+func (s *Store) SetFillPolicy(p FillPolicy) {
+	s.fill = p
+}
+
+// SetNearestWetRadius sets how many grid cells around a land point
+// FillNearest searches for a wet value (default nearestWetRadius).
+func (s *Store) SetNearestWetRadius(cells int) {
+	s.wetRadius = cells
+}
+
 // LoadForLocation loads constituent parameters for a lat/lon location
 // using bilinear interpolation from FES NetCDF grids.
 // NOTE: Does NOT cache grids to avoid OOM in Cloud Run.
 func (s *Store) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error) {
-	// Load constituents based on location.
-	// Major 8 constituents provide ~95% of tidal signal in deep water.
-	// For shallow water areas, include overtide constituents (M4, M6, MS4, MN4).
-	majorConstituents := []string{"M2", "S2", "N2", "K2", "K1", "O1", "P1", "Q1"}
-
-	// Add shallow water constituents for coastal/shallow areas.
-	// Check if we're in a potentially shallow area (this is a heuristic).
-	// A more sophisticated approach would use bathymetry data.
-	shallowWaterConstituents := []string{"M4", "MS4", "MN4", "S4"}
+	params, errs := s.LoadForLocations([]domain.Position{{Lat: lat, Lon: lon}})
+	return params[0], errs[0]
+}
 
-	// Include shallow water constituents for all requests to maintain accuracy.
-	requestedConstituents := make([]string, 0, len(majorConstituents)+len(shallowWaterConstituents))
-	requestedConstituents = append(requestedConstituents, majorConstituents...)
-	requestedConstituents = append(requestedConstituents, shallowWaterConstituents...)
+// LoadForLocations loads constituent parameters for several locations,
+// opening each constituent file once for all of them. Results and errors
+// are indexed like points.
+func (s *Store) LoadForLocations(points []domain.Position) ([][]domain.ConstituentParam, []error) {
+	params := make([][]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+	fail := func(err error) ([][]domain.ConstituentParam, []error) {
+		for i := range errs {
+			errs[i] = err
+		}
+		return params, errs
+	}
 
 	// Verify at least some constituents are available.
 	available, err := s.GetAvailableConstituents()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get available constituents: %w", err)
+		return fail(fmt.Errorf("failed to get available constituents: %w", err))
 	}
 	if len(available) == 0 {
-		return nil, fmt.Errorf("no FES NetCDF files found in %s", s.dataDir)
+		return fail(fmt.Errorf("no FES NetCDF files found in %s", s.dataDir))
 	}
 
-	// Use only constituents that exist in the data directory.
+	// Use only constituents that exist in the data directory. When all are
+	// requested, the default set keeps its order and the rest follow by name.
+	requestedConstituents := s.constituents
+	if requestedConstituents == nil {
+		sort.Strings(available)
+		requestedConstituents = append(DefaultConstituents(), available...)
+	}
 	constituents := make([]string, 0, len(requestedConstituents))
 	availableMap := make(map[string]bool)
 	for _, c := range available {
@@ -104,18 +260,16 @@ func (s *Store) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, er
 	for _, c := range requestedConstituents {
 		if availableMap[c] {
 			constituents = append(constituents, c)
+			delete(availableMap, c)
 		}
 	}
 
 	// Load and interpolate each constituent.
-	params := make([]domain.ConstituentParam, 0, len(constituents))
-
+	outOfCoverage := make([]bool, len(points))
 	for _, constName := range constituents {
-		// Load constituent WITHOUT caching to avoid OOM.
-		// Each request reads only the 4 grid points needed for bilinear interpolation.
-		amplitude, phase, err := s.interpolateConstituentAtPoint(constName, lat, lon)
-		if err != nil {
-			// Skip constituents that fail to load (log warning in production).
+		// Skip constituents whose reads keep failing.
+		breaker := s.circuits.Get(constName)
+		if !breaker.Allow() {
 			continue
 		}
 
@@ -126,19 +280,139 @@ func (s *Store) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, er
 			continue
 		}
 
-		params = append(params, domain.ConstituentParam{
-			Name:          constName,
-			AmplitudeM:    amplitude,
-			PhaseDeg:      phase,
-			SpeedDegPerHr: speed,
-		})
+		// Load constituent WITHOUT caching to avoid OOM.
+		// Each point reads only the 4 grid points needed for bilinear interpolation.
+		amplitude, phase, pointErrs, err := s.interpolateConstituentAtPoints(constName, points)
+		if err != nil {
+			// Skip constituents that fail to load.
+			breaker.Record(err)
+			continue
+		}
+		// No coverage is not a read failure.
+		breaker.Record(nil)
+
+		for i := range points {
+			if pointErrs[i] != nil {
+				outOfCoverage[i] = true
+				continue
+			}
+			params[i] = append(params[i], domain.ConstituentParam{
+				Name:          constName,
+				AmplitudeM:    amplitude[i],
+				PhaseDeg:      domain.NormalizePhaseDeg(phase[i]),
+				SpeedDegPerHr: speed,
+			})
+		}
+	}
+
+	for i, p := range points {
+		if len(params[i]) > 0 {
+			continue
+		}
+		if outOfCoverage[i] {
+			errs[i] = fmt.Errorf("no valid constituents found for location (%.4f, %.4f): %w", p.Lat, p.Lon, domain.ErrOutOfCoverage)
+		} else {
+			errs[i] = fmt.Errorf("no valid constituents found for location (%.4f, %.4f)", p.Lat, p.Lon)
+		}
+	}
+	return params, errs
+}
+
+// LoadConstituentAt interpolates a single constituent at a location, so
+// sampling a map reads one constituent instead of all of them per point.
+func (s *Store) LoadConstituentAt(name string, lat, lon float64) (domain.ConstituentParam, error) {
+	params, errs := s.LoadConstituentAtPoints(name, []domain.Position{{Lat: lat, Lon: lon}})
+	return params[0], errs[0]
+}
+
+// LoadConstituentAtPoints interpolates a single constituent at several
+// locations, reading each of its files once.
+func (s *Store) LoadConstituentAtPoints(name string, points []domain.Position) ([]domain.ConstituentParam, []error) {
+	params := make([]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+	fail := func(err error) ([]domain.ConstituentParam, []error) {
+		for i := range errs {
+			errs[i] = err
+		}
+		return params, errs
+	}
+	canonical, ok := domain.CanonicalConstituentName(name)
+	if !ok {
+		return fail(fmt.Errorf("unknown constituent %s", name))
+	}
+	speed, _ := domain.GetConstituentSpeed(canonical)
+
+	// Dataset spellings are found by scanning the data directory once.
+	s.mu.RLock()
+	scanned := s.spellings != nil || s.index != nil || s.files != nil
+	s.mu.RUnlock()
+	if !scanned {
+		if _, err := s.GetAvailableConstituents(); err != nil {
+			return fail(fmt.Errorf("failed to get available constituents: %w", err))
+		}
+	}
+
+	breaker := s.circuits.Get(canonical)
+	if !breaker.Allow() {
+		return fail(fmt.Errorf("constituent %s: reads failing, circuit open", canonical))
+	}
+	amplitude, phase, pointErrs, err := s.interpolateConstituentAtPoints(canonical, points)
+	breaker.Record(err)
+	if err != nil {
+		return fail(fmt.Errorf("constituent %s: %w", canonical, err))
 	}
 
-	if len(params) == 0 {
-		return nil, fmt.Errorf("no valid constituents found for location (%.4f, %.4f)", lat, lon)
+	for i, p := range points {
+		if pointErrs[i] != nil {
+			errs[i] = fmt.Errorf("constituent %s at (%.4f, %.4f): %w", canonical, p.Lat, p.Lon, pointErrs[i])
+			continue
+		}
+		params[i] = domain.ConstituentParam{
+			Name:          canonical,
+			AmplitudeM:    amplitude[i],
+			PhaseDeg:      domain.NormalizePhaseDeg(phase[i]),
+			SpeedDegPerHr: speed,
+		}
 	}
+	return params, errs
+}
 
-	return params, nil
+// WetFallback returns the distance in kilometers from a location to the
+// wet grid point FillNearest takes its values from, or 0 when the location
+// is interpolated from its own grid cell. The land mask is read from the
+// amplitude of the first requested constituent available.
+func (s *Store) WetFallback(lat, lon float64) (float64, error) {
+	if s.fill != FillNearest {
+		return 0, nil
+	}
+	if _, err := s.GetAvailableConstituents(); err != nil {
+		return 0, fmt.Errorf("failed to get available constituents: %w", err)
+	}
+	requested := s.constituents
+	if requested == nil {
+		requested = DefaultConstituents()
+	}
+	for _, name := range requested {
+		amp, _, err := s.constituentFiles(name)
+		if err != nil {
+			continue
+		}
+		point := []domain.Position{{Lat: lat, Lon: normalizeLon360(lon)}}
+		var fallbackKm []float64
+		var errs []error
+		err = retry.Do(retryOp, func() (err error) {
+			_, fallbackKm, errs, err = interpolatePointsFromNetCDF(amp.path, amp.vars, amplitudeVarName, point, s.fill, s.wetRadius, s.cells)
+			return err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("constituent %s: %w", name, err)
+		}
+		if errs[0] != nil {
+			return 0, errs[0]
+		}
+		return fallbackKm[0], nil
+	}
+	return 0, fmt.Errorf("no requested constituent available in %s", s.dataDir)
 }
 
 // normalizeLon360 maps arbitrary degree longitudes into the [0, 360) range.
@@ -160,6 +434,16 @@ func (s *Store) LoadForStation(_ string) ([]domain.ConstituentParam, error) {
 
 // GetAvailableConstituents returns the list of constituents available in FES data.
 func (s *Store) GetAvailableConstituents() ([]string, error) {
+	s.mu.RLock()
+	idx, files := s.index, s.files
+	s.mu.RUnlock()
+	if idx != nil {
+		return idx.Names(), nil
+	}
+	if files != nil {
+		return slices.Sorted(maps.Keys(files)), nil
+	}
+
 	// Check if dataDir exists.
 	if _, err := os.Stat(s.dataDir); os.IsNotExist(err) {
 		return nil, fmt.Errorf("FES data directory does not exist: %s", s.dataDir)
@@ -167,6 +451,8 @@ func (s *Store) GetAvailableConstituents() ([]string, error) {
 
 	// Map to store unique constituent names.
 	constituentMap := make(map[string]bool)
+	unknown := make(map[string]bool)
+	spellings := make(map[string][]string)
 
 	// Recursively walk directory for NetCDF files.
 	err := filepath.WalkDir(s.dataDir, func(_ string, d fs.DirEntry, err error) error {
@@ -187,9 +473,13 @@ func (s *Store) GetAvailableConstituents() ([]string, error) {
 		if baseName == "" {
 			return nil
 		}
-		constName := strings.ToUpper(baseName)
-		if _, ok := domain.GetConstituentSpeed(constName); ok {
+		if constName, ok := domain.CanonicalConstituentName(baseName); ok {
 			constituentMap[constName] = true
+			if base := strings.ToLower(baseName); !slices.Contains(spellings[constName], base) {
+				spellings[constName] = append(spellings[constName], base)
+			}
+		} else {
+			unknown[constName] = true
 		}
 		return nil
 	})
@@ -215,13 +505,19 @@ func (s *Store) GetAvailableConstituents() ([]string, error) {
 			return nil
 		})
 		if found {
-			upper := strings.ToUpper(base)
-			if _, ok := domain.GetConstituentSpeed(upper); ok {
-				constituentMap[upper] = true
+			if constName, ok := domain.CanonicalConstituentName(base); ok {
+				constituentMap[constName] = true
 			}
 		}
 	}
 
+	// Report files for constituents without a known speed instead of dropping them silently.
+	s.reportUnknown(unknown)
+
+	s.mu.Lock()
+	s.spellings = spellings
+	s.mu.Unlock()
+
 	// Convert map to slice.
 	constituents := make([]string, 0, len(constituentMap))
 	for name := range constituentMap {
@@ -231,6 +527,36 @@ func (s *Store) GetAvailableConstituents() ([]string, error) {
 	return constituents, nil
 }
 
+// reportUnknown logs each unknown constituent name once per store.
+func (s *Store) reportUnknown(unknown map[string]bool) {
+	if len(unknown) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name := range unknown {
+		if s.reported[name] {
+			continue
+		}
+		s.reported[name] = true
+		log.Printf("Warning: FES constituent %s has no known speed; ignoring its files", name)
+	}
+}
+
+// fileBases returns the lower-case file base names to try for a constituent:
+// its identifier, then the dataset spellings found in the data directory.
+func (s *Store) fileBases(name string) []string {
+	bases := []string{strings.ToLower(name)}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, base := range s.spellings[name] {
+		if !slices.Contains(bases, base) {
+			bases = append(bases, base)
+		}
+	}
+	return bases
+}
+
 // findFirstFile searches for the first matching file from a list of candidates.
 // It performs a case-insensitive search under the given base directory.
 func (s *Store) findFirstFile(candidates []string) (string, error) {
@@ -269,54 +595,128 @@ func (s *Store) findFirstFile(candidates []string) (string, error) {
 	return "", fmt.Errorf("not found")
 }
 
-// interpolateConstituentAtPoint reads only the 4 grid points needed for bilinear interpolation.
-// This avoids loading entire grids (which can be 100+ MB each) into memory.
-func (s *Store) interpolateConstituentAtPoint(name string, lat, lon float64) (amplitude, phase float64, err error) {
-	nameLower := strings.ToLower(name)
+// interpolateConstituentAtPoints interpolates a constituent at several
+// points, reading each of its files once and only the 4 grid points around
+// each point, rather than loading entire grids (100+ MB each). errs holds the out-of-coverage
+// error of each point; err is a failure to read the files.
+func (s *Store) interpolateConstituentAtPoints(name string, points []domain.Position) (amplitude, phase []float64, errs []error, err error) {
 	config := DefaultConfig()
+	defer func() {
+		if err != nil {
+			metrics.InterpolationFailures.With(retryOp, metrics.ReasonRead).Inc()
+		}
+	}()
 
-	// Find amplitude and phase files.
-	ampCandidates := []string{
-		fmt.Sprintf("ocean_tide/%s.nc", nameLower),
-		fmt.Sprintf("%s.nc", nameLower),
-		fmt.Sprintf("%s_amplitude.nc", nameLower),
-		fmt.Sprintf("%s_amp.nc", nameLower),
-	}
-	phaCandidates := []string{
-		fmt.Sprintf("ocean_tide/%s.nc", nameLower),
-		fmt.Sprintf("%s.nc", nameLower),
-		fmt.Sprintf("%s_phase.nc", nameLower),
-		fmt.Sprintf("%s_pha.nc", nameLower),
+	amp, pha, err := s.constituentFiles(name)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	ampPath, err := s.findFirstFile(ampCandidates)
+	// Read amplitude and phase at each lat/lon (only 4 points each).
+	norm := make([]domain.Position, len(points))
+	for i, p := range points {
+		norm[i] = domain.Position{Lat: p.Lat, Lon: normalizeLon360(p.Lon)}
+	}
+	var ampErrs, phaErrs []error
+	err = retry.Do(retryOp, func() (err error) {
+		amplitude, _, ampErrs, err = interpolatePointsFromNetCDF(amp.path, amp.vars, config.AmplitudeVarName, norm, s.fill, s.wetRadius, s.cells)
+		return err
+	})
 	if err != nil {
-		return 0, 0, fmt.Errorf("amplitude file not found for constituent %s", name)
+		return nil, nil, nil, fmt.Errorf("failed to interpolate amplitude: %w", err)
 	}
-	phaPath, err := s.findFirstFile(phaCandidates)
+	err = retry.Do(retryOp, func() (err error) {
+		phase, _, phaErrs, err = interpolatePointsFromNetCDF(pha.path, pha.vars, config.PhaseVarName, norm, s.fill, s.wetRadius, s.cells)
+		return err
+	})
 	if err != nil {
-		return 0, 0, fmt.Errorf("phase file not found for constituent %s", name)
+		return nil, nil, nil, fmt.Errorf("failed to interpolate phase: %w", err)
+	}
+
+	// Convert cm to meters (ocean_tide files are converted when read).
+	cm := !strings.Contains(strings.ToLower(amp.path), "ocean_tide")
+	errs = make([]error, len(points))
+	noData := 0
+	for i := range points {
+		switch {
+		case ampErrs[i] != nil:
+			errs[i] = fmt.Errorf("failed to interpolate amplitude: %w", ampErrs[i])
+			noData++
+		case phaErrs[i] != nil:
+			errs[i] = fmt.Errorf("failed to interpolate phase: %w", phaErrs[i])
+			noData++
+		case cm:
+			amplitude[i] /= 100.0
+		}
+	}
+	if noData > 0 {
+		metrics.InterpolationFailures.With(retryOp, metrics.ResultNoData).Add(noData)
 	}
+	return amplitude, phase, errs, nil
+}
 
-	// Read amplitude and phase at the specific lat/lon (only 4 points each).
-	normLon := normalizeLon360(lon)
-	amplitude, err = interpolatePointFromNetCDF(ampPath, config.LatVarName, config.LonVarName, config.AmplitudeVarName, lat, normLon)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to interpolate amplitude: %w", err)
+// fileRef is a constituent file and, when indexed, its variables.
+type fileRef struct {
+	path string
+	vars *Variables // Nil to detect when read.
+}
+
+// constituentFiles returns the amplitude and phase files of a constituent,
+// from the index or the startup scan if set.
+func (s *Store) constituentFiles(name string) (amp, pha fileRef, err error) {
+	s.mu.RLock()
+	idx, files := s.index, s.files
+	s.mu.RUnlock()
+	if idx != nil {
+		entry, ok := idx.Constituents[name]
+		if !ok {
+			return fileRef{}, fileRef{}, fmt.Errorf("constituent %s not in FES index", name)
+		}
+		amp = fileRef{path: s.indexedPath(entry.Amplitude.Path), vars: &entry.Amplitude.Variables}
+		pha = fileRef{path: s.indexedPath(entry.Phase.Path), vars: &entry.Phase.Variables}
+		return amp, pha, nil
 	}
-	phase, err = interpolatePointFromNetCDF(phaPath, config.LatVarName, config.LonVarName, config.PhaseVarName, lat, normLon)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to interpolate phase: %w", err)
+	if files != nil {
+		f, ok := files[name]
+		if !ok {
+			return fileRef{}, fileRef{}, fmt.Errorf("constituent %s not found in FES directory scan", name)
+		}
+		return fileRef{path: f.amplitude}, fileRef{path: f.phase}, nil
 	}
 
-	// Convert cm to meters.
-	amplitude /= 100.0
+	ampPath, phaPath, err := s.findConstituentFiles(name)
+	return fileRef{path: ampPath}, fileRef{path: phaPath}, err
+}
+
+// findConstituentFiles searches the data directory for the amplitude and
+// phase files of a constituent under any spelling of its name.
+func (s *Store) findConstituentFiles(name string) (ampPath, phaPath string, err error) {
+	bases := s.fileBases(name)
+	var ampCandidates, phaCandidates []string
+	for _, pattern := range []string{"ocean_tide/%s.nc", "%s.nc", "%s_amplitude.nc", "%s_amp.nc"} {
+		for _, base := range bases {
+			ampCandidates = append(ampCandidates, fmt.Sprintf(pattern, base))
+		}
+	}
+	for _, pattern := range []string{"ocean_tide/%s.nc", "%s.nc", "%s_phase.nc", "%s_pha.nc"} {
+		for _, base := range bases {
+			phaCandidates = append(phaCandidates, fmt.Sprintf(pattern, base))
+		}
+	}
 
-	return amplitude, phase, nil
+	ampPath, err = s.findFirstFile(ampCandidates)
+	if err != nil {
+		return "", "", fmt.Errorf("amplitude file not found for constituent %s", name)
+	}
+	phaPath, err = s.findFirstFile(phaCandidates)
+	if err != nil {
+		return "", "", fmt.Errorf("phase file not found for constituent %s", name)
+	}
+	return ampPath, phaPath, nil
 }
 
 // loadConstituent loads amplitude and phase grids for a constituent.
-// Deprecated: Loads entire grids into memory. Use interpolateConstituentAtPoint instead.
+// Deprecated: Loads entire grids into memory. Use interpolateConstituentAtPoints instead.
 func (s *Store) loadConstituent(name string) (*Grid, error) {
 	// Check cache first.
 	s.mu.RLock()
@@ -352,13 +752,21 @@ func (s *Store) loadConstituent(name string) (*Grid, error) {
 	}
 
 	// Load amplitude grid.
-	ampGrid, err := loadNetCDFGrid(ampPath, config.LatVarName, config.LonVarName, config.AmplitudeVarName)
+	var ampGrid *interp.Grid2D
+	err = retry.Do(retryOp, func() (err error) {
+		ampGrid, err = loadNetCDFGrid(ampPath, config.LatVarName, config.LonVarName, config.AmplitudeVarName)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load amplitude for %s: %w", name, err)
 	}
 
 	// Load phase grid.
-	phaGrid, err := loadNetCDFGrid(phaPath, config.LatVarName, config.LonVarName, config.PhaseVarName)
+	var phaGrid *interp.Grid2D
+	err = retry.Do(retryOp, func() (err error) {
+		phaGrid, err = loadNetCDFGrid(phaPath, config.LatVarName, config.LonVarName, config.PhaseVarName)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to load phase for %s: %w", name, err)
 	}
@@ -378,60 +786,39 @@ func (s *Store) loadConstituent(name string) (*Grid, error) {
 	return grid, nil
 }
 
-// interpolatePointFromNetCDF reads only 4 grid points around (lat, lon) and interpolates.
-// This minimizes memory usage by avoiding loading entire grids.
-//
-//nolint:gocyclo,nestif // Complex NetCDF subset reading logic with multiple fallback paths.
-func interpolatePointFromNetCDF(filepath, latVarName, lonVarName, dataVarName string, lat, lon float64) (float64, error) {
-	// Open NetCDF file.
-	nc, err := netcdf.OpenFile(filepath, netcdf.NOWRITE)
-	if err != nil {
-		return 0, fmt.Errorf("failed to open NetCDF file: %w", err)
-	}
-	defer func() { _ = nc.Close() }()
-
-	// Try multiple variable name patterns.
-	latNames := []string{latVarName, "latitude", "lat", "y"}
-	lonNames := []string{lonVarName, "longitude", "lon", "x"}
+// Variables names the variables read from a constituent file: 1D latitude
+// and longitude coordinates, and either the amplitude or phase variable or
+// a real/imaginary pair they are computed from.
+type Variables struct {
+	Lat  string `json:"lat"`
+	Lon  string `json:"lon"`
+	Data string `json:"data,omitempty"`
+	Real string `json:"real,omitempty"`
+	Imag string `json:"imag,omitempty"`
+}
 
-	// Read full coordinate arrays (these are small: 1D arrays of ~2881 and ~5760 points).
-	var latData []float64
-	var latFound bool
-	for _, name := range latNames {
-		if v, err := nc.Var(name); err == nil {
-			latData, err = readFloat64Var(v)
-			if err == nil {
-				latFound = true
-				break
+// detectVariables finds the variables of a constituent file among the
+// names used by FES releases and derived products. dataVarName selects
+// amplitude or phase candidates.
+func detectVariables(nc netcdf.Dataset, latVarName, lonVarName, dataVarName string) (Variables, error) {
+	first := func(names []string) (string, bool) {
+		for _, name := range names {
+			if _, err := nc.Var(name); err == nil {
+				return name, true
 			}
 		}
-	}
-	if !latFound {
-		return 0, fmt.Errorf("latitude variable not found (tried: %v)", latNames)
+		return "", false
 	}
 
-	var lonData []float64
-	var lonFound bool
-	for _, name := range lonNames {
-		if v, err := nc.Var(name); err == nil {
-			lonData, err = readFloat64Var(v)
-			if err == nil {
-				lonFound = true
-				break
-			}
-		}
-	}
-	if !lonFound {
-		return 0, fmt.Errorf("longitude variable not found (tried: %v)", lonNames)
+	var vars Variables
+	var ok bool
+	latNames := []string{latVarName, "latitude", "lat", "y"}
+	if vars.Lat, ok = first(latNames); !ok {
+		return Variables{}, fmt.Errorf("latitude variable not found (tried: %v)", latNames)
 	}
-
-	// Find grid cell indices surrounding the target point.
-	// latData and lonData should be monotonically increasing.
-	latIdx := findGridCell(latData, lat)
-	lonIdx := findGridCell(lonData, lon)
-
-	if latIdx < 0 || lonIdx < 0 {
-		return 0, fmt.Errorf("point (%.4f, %.4f) outside grid bounds", lat, lon)
+	lonNames := []string{lonVarName, "longitude", "lon", "x"}
+	if vars.Lon, ok = first(lonNames); !ok {
+		return Variables{}, fmt.Errorf("longitude variable not found (tried: %v)", lonNames)
 	}
 
 	// Build candidate data variable names.
@@ -453,147 +840,338 @@ func interpolatePointFromNetCDF(filepath, latVarName, lonVarName, dataVarName st
 		)
 	}
 	dataNames = append(dataNames, "data", "z")
+	if vars.Data, ok = first(dataNames); ok {
+		return vars, nil
+	}
 
-	// Find data variable.
-	var dataVar netcdf.Var
-	var dataFound bool
-	for _, name := range dataNames {
-		if v, err := nc.Var(name); err == nil {
-			dataVar = v
-			dataFound = true
-			break
+	// Try complex pair (real/imag).
+	var haveRe, haveIm bool
+	vars.Real, haveRe = first([]string{"hRe", "Hre", "hre", "Re", "RE", "real", "Real"})
+	vars.Imag, haveIm = first([]string{"hIm", "Him", "him", "Im", "IM", "imag", "Imag"})
+	if !haveRe || !haveIm {
+		return Variables{}, fmt.Errorf("data variable not found (tried: %v), and no complex pair detected", dataNames)
+	}
+	return vars, nil
+}
+
+// gridFile is an open constituent file: its coordinates (the 1D axes of a
+// regular grid, or a curvilinear grid) and a reader of windows of values.
+type gridFile struct {
+	nc       netcdf.Dataset
+	lat, lon []float64
+	curv     *interp.CurvilinearGrid
+	// sample reads a window of values [lat][lon] with fill values masked.
+	sample func(lat0, lon0, nLatC, nLonC int) ([][]float64, error)
+}
+
+// openGridFile opens a constituent file and reads its coordinates.
+// Variables are detected when vars is nil (no index); dataVarName selects
+// amplitude or phase. The caller closes f.nc.
+//
+//nolint:gocyclo,nestif // Complex NetCDF subset reading logic with multiple fallback paths.
+func openGridFile(filepath string, vars *Variables, dataVarName string, fill FillPolicy) (f *gridFile, err error) {
+	// Open NetCDF file.
+	nc, err := netcdf.OpenFile(filepath, netcdf.NOWRITE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open NetCDF file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = nc.Close()
+		}
+	}()
+
+	if vars == nil {
+		config := DefaultConfig()
+		detected, err := detectVariables(nc, config.LatVarName, config.LonVarName, dataVarName)
+		if err != nil {
+			return nil, err
 		}
+		vars = &detected
 	}
-	if !dataFound {
-		// Try complex pair (real/imag).
-		realCandidates := []string{"hRe", "Hre", "hre", "Re", "RE", "real", "Real"}
-		imagCandidates := []string{"hIm", "Him", "him", "Im", "IM", "imag", "Imag"}
 
-		var realVar, imagVar netcdf.Var
-		var haveRe, haveIm bool
-		for _, rn := range realCandidates {
-			if v, err := nc.Var(rn); err == nil {
-				realVar = v
-				haveRe = true
-				break
-			}
+	// Read full coordinate arrays: 1D axes of a regular grid (these are
+	// small: ~2881 and ~5760 points), or the 2D node coordinates of a
+	// curvilinear regional grid.
+	var latData, lonData []float64
+	var curv *interp.CurvilinearGrid
+	var nRows, nCols int
+	if is2D(nc, vars.Lat) {
+		if curv, err = curvilinearGrid(nc, filepath, *vars); err != nil {
+			return nil, err
 		}
-		for _, in := range imagCandidates {
-			if v, err := nc.Var(in); err == nil {
-				imagVar = v
-				haveIm = true
-				break
-			}
+		nRows, nCols = curv.Shape()
+	} else {
+		if latData, err = readCoordinate(nc, vars.Lat); err != nil {
+			return nil, fmt.Errorf("latitude variable %s: %w", vars.Lat, err)
 		}
-		if !haveRe || !haveIm {
-			return 0, fmt.Errorf("data variable not found (tried: %v), and no complex pair detected", dataNames)
+		if lonData, err = readCoordinate(nc, vars.Lon); err != nil {
+			return nil, fmt.Errorf("longitude variable %s: %w", vars.Lon, err)
 		}
+		nRows, nCols = len(latData), len(lonData)
+	}
 
-		// Read 2x2 subset from real and imag.
-		reVals, err := readSubset2x2(realVar, len(latData), len(lonData), latIdx, lonIdx)
+	// Apply cm->m conversion for amplitude from ocean_tide combined files.
+	want := strings.ToLower(dataVarName)
+	isAmplitude := strings.Contains(want, "amp") || strings.Contains(want, "ampl") || want == amplitudeVarName
+	toMeters := isAmplitude && strings.Contains(strings.ToLower(filepath), "ocean_tide")
+
+	var sample func(lat0, lon0, nLatC, nLonC int) ([][]float64, error)
+
+	if vars.Data != "" {
+		dataVar, err := nc.Var(vars.Data)
 		if err != nil {
-			return 0, fmt.Errorf("failed to read real subset: %w", err)
+			return nil, fmt.Errorf("data variable %s: %w", vars.Data, err)
 		}
-		imVals, err := readSubset2x2(imagVar, len(latData), len(lonData), latIdx, lonIdx)
+		sample = func(lat0, lon0, nLatC, nLonC int) ([][]float64, error) {
+			values, err := readSubset(dataVar, nRows, nCols, lat0, lon0, nLatC, nLonC)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read data subset: %w", err)
+			}
+			maskFill(values, dataVar, fill)
+			if toMeters {
+				scale(values, 0.01)
+			}
+			return values, nil
+		}
+	} else {
+		realVar, err := nc.Var(vars.Real)
+		if err != nil {
+			return nil, fmt.Errorf("real variable %s: %w", vars.Real, err)
+		}
+		imagVar, err := nc.Var(vars.Imag)
 		if err != nil {
-			return 0, fmt.Errorf("failed to read imag subset: %w", err)
+			return nil, fmt.Errorf("imaginary variable %s: %w", vars.Imag, err)
 		}
 
-		// Handle fill values.
-		if fv, ok := getFillValue(realVar); ok {
-			for i := range reVals {
-				for j := range reVals[i] {
-					if reVals[i][j] == fv {
-						reVals[i][j] = 0
-					}
-				}
+		sample = func(lat0, lon0, nLatC, nLonC int) ([][]float64, error) {
+			reVals, err := readSubset(realVar, nRows, nCols, lat0, lon0, nLatC, nLonC)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read real subset: %w", err)
 			}
-		}
-		if fv, ok := getFillValue(imagVar); ok {
-			for i := range imVals {
-				for j := range imVals[i] {
-					if imVals[i][j] == fv {
-						imVals[i][j] = 0
+			imVals, err := readSubset(imagVar, nRows, nCols, lat0, lon0, nLatC, nLonC)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read imag subset: %w", err)
+			}
+			maskFill(reVals, realVar, fill)
+			maskFill(imVals, imagVar, fill)
+
+			// Compute amplitude or phase (NaN components stay NaN).
+			values := make([][]float64, nLatC)
+			for i := range values {
+				values[i] = make([]float64, nLonC)
+				for j := range values[i] {
+					re := reVals[i][j]
+					im := imVals[i][j]
+					if isAmplitude {
+						values[i][j] = math.Hypot(re, im)
+					} else {
+						deg := domain.Rad2Deg(math.Atan2(im, re))
+						if deg < 0 {
+							deg += 360.0
+						}
+						values[i][j] = deg
 					}
 				}
 			}
+			if toMeters {
+				scale(values, 0.01)
+			}
+			return values, nil
 		}
+	}
 
-		// Compute amplitude or phase.
-		want := strings.ToLower(dataVarName)
-		values := make([][]float64, 2)
-		for i := 0; i < 2; i++ {
-			values[i] = make([]float64, 2)
-			for j := 0; j < 2; j++ {
-				re := reVals[i][j]
-				im := imVals[i][j]
-				if strings.Contains(want, "amp") || strings.Contains(want, "ampl") || want == amplitudeVarName {
-					values[i][j] = math.Hypot(re, im)
-				} else {
-					deg := domain.Rad2Deg(math.Atan2(im, re))
-					if deg < 0 {
-						deg += 360.0
-					}
-					values[i][j] = deg
-				}
+	return &gridFile{nc: nc, lat: latData, lon: lonData, curv: curv, sample: sample}, nil
+}
+
+// interpolatePointsFromNetCDF reads only the 4 grid points around each
+// point and interpolates, opening the file and reading its coordinates once.
+// This minimizes memory usage by avoiding loading entire grids. Points
+// without wet neighbors get domain.ErrOutOfCoverage in errs unless fill is
+// FillNearest; any other error fails the whole file. Variables are detected
+// when vars is nil (no index); dataVarName selects amplitude or phase.
+//
+// With a cell cache, the cells read around points of a regular grid and
+// the grid's axes are kept, and the file is opened only when a point needs
+// a cell not cached.
+func interpolatePointsFromNetCDF(filepath string, vars *Variables, dataVarName string, points []domain.Position, fill FillPolicy, radius int, cells *cellCache) (values, fallbackKm []float64, errs []error, err error) {
+	var f *gridFile
+	defer func() {
+		if f != nil {
+			_ = f.nc.Close()
+		}
+	}()
+	open := func() (*gridFile, error) {
+		if f == nil {
+			opened, err := openGridFile(filepath, vars, dataVarName, fill)
+			if err != nil {
+				return nil, err
 			}
+			f = opened
 		}
+		return f, nil
+	}
 
-		// Apply cm->m conversion for amplitude from ocean_tide combined files.
-		if (strings.Contains(want, "amp") || want == amplitudeVarName) && strings.Contains(strings.ToLower(filepath), "ocean_tide") {
-			for i := range values {
-				for j := range values[i] {
-					values[i][j] /= 100.0
-				}
+	file := cellFile{path: filepath, variable: dataVarName, fill: fill}
+	latData, lonData, cached := cells.axes(file)
+	if !cached {
+		if _, err := open(); err != nil {
+			return nil, nil, nil, err
+		}
+		latData, lonData = f.lat, f.lon
+		if f.curv == nil {
+			cells.putAxes(file, latData, lonData)
+		}
+	}
+	// sample reads a window through the cache; only 2x2 cells are cached,
+	// not the wider windows searched for the nearest wet value.
+	sample := func(lat0, lon0, nLatC, nLonC int) ([][]float64, error) {
+		key := cellKey{file: file, lat: lat0, lon: lon0}
+		cell := nLatC == 2 && nLonC == 2
+		if cell {
+			if values, ok := cells.cell(key); ok {
+				return values, nil
 			}
 		}
+		f, err := open()
+		if err != nil {
+			return nil, err
+		}
+		values, err := f.sample(lat0, lon0, nLatC, nLonC)
+		if err == nil && cell {
+			cells.putCell(key, values)
+		}
+		return values, err
+	}
+
+	values, fallbackKm, errs = make([]float64, len(points)), make([]float64, len(points)), make([]error, len(points))
+	for i, p := range points {
+		var v, km float64
+		if f != nil && f.curv != nil {
+			v, km, err = interpolateCurvilinear(f.curv, f.sample, p.Lat, p.Lon, fill, radius)
+		} else {
+			v, km, err = interpolateRegular(latData, lonData, sample, p.Lat, p.Lon, fill, radius)
+		}
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			errs[i] = err
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		values[i], fallbackKm[i] = v, km
+	}
+	return values, fallbackKm, errs, nil
+}
 
-		// Bilinear interpolation.
-		return bilinearInterpolate(latData[latIdx:latIdx+2], lonData[lonIdx:lonIdx+2], values, lat, lon), nil
+// interpolateRegular interpolates at a point of a grid with 1D axes,
+// reading the 2x2 cell around it. fallbackKm is the distance to the wet
+// grid point whose value was taken under FillNearest, or 0.
+func interpolateRegular(latData, lonData []float64, sample func(lat0, lon0, nLatC, nLonC int) ([][]float64, error), lat, lon float64, fill FillPolicy, radius int) (value, fallbackKm float64, err error) {
+	// Find grid cell indices surrounding the target point.
+	latIdx := findGridCell(latData, lat)
+	lonIdx := findGridCell(lonData, lon)
+	if latIdx < 0 || lonIdx < 0 {
+		return 0, 0, fmt.Errorf("point (%.4f, %.4f) outside grid bounds: %w", lat, lon, domain.ErrOutOfCoverage)
 	}
 
-	// Read 2x2 subset from data variable.
-	values, err := readSubset2x2(dataVar, len(latData), len(lonData), latIdx, lonIdx)
+	// Bilinear interpolation over the surrounding 2x2 cell.
+	values, err := sample(latIdx, lonIdx, 2, 2)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read data subset: %w", err)
+		return 0, 0, err
+	}
+	result := bilinearInterpolate(latData[latIdx:latIdx+2], lonData[lonIdx:lonIdx+2], values, lat, lon)
+	if !math.IsNaN(result) {
+		return result, 0, nil
 	}
 
-	// Handle fill values.
-	if fv, ok := getFillValue(dataVar); ok {
-		for i := range values {
-			for j := range values[i] {
-				if values[i][j] == fv {
-					values[i][j] = 0
-				}
-			}
+	// All surrounding cells are fill (land or outside the model domain).
+	if fill == FillNearest && radius > 0 {
+		lat0, lat1 := max(latIdx-radius, 0), min(latIdx+1+radius, len(latData)-1)
+		lon0, lon1 := max(lonIdx-radius, 0), min(lonIdx+1+radius, len(lonData)-1)
+		window, err := sample(lat0, lon0, lat1-lat0+1, lon1-lon0+1)
+		if err != nil {
+			return 0, 0, err
+		}
+		if v, km, ok := nearestWet(latData[lat0:lat1+1], lonData[lon0:lon1+1], window, lat, lon); ok {
+			return v, km, nil
 		}
 	}
+	return 0, 0, fmt.Errorf("point (%.4f, %.4f): %w", lat, lon, domain.ErrOutOfCoverage)
+}
 
-	// Unit conversion for amplitude grids.
-	if (strings.Contains(strings.ToLower(dataVarName), "amp") || strings.ToLower(dataVarName) == amplitudeVarName) &&
-		strings.Contains(strings.ToLower(filepath), "ocean_tide") {
-		for i := range values {
-			for j := range values[i] {
-				values[i][j] /= 100.0
+// readCoordinate reads a 1D coordinate variable.
+func readCoordinate(nc netcdf.Dataset, name string) ([]float64, error) {
+	v, err := nc.Var(name)
+	if err != nil {
+		return nil, err
+	}
+	return readFloat64Var(v)
+}
+
+// maskFill replaces fill values (and NaN) in place according to the policy:
+// NaN for FillNaN and FillNearest, 0 for FillZero.
+func maskFill(values [][]float64, v netcdf.Var, fill FillPolicy) {
+	fv := ncfill.Of(v)
+	for i := range values {
+		fv.Mask(values[i])
+		if fill != FillZero {
+			continue
+		}
+		for j := range values[i] {
+			if math.IsNaN(values[i][j]) {
+				values[i][j] = 0
 			}
 		}
 	}
+}
+
+// scale multiplies values in place.
+func scale(values [][]float64, factor float64) {
+	for i := range values {
+		for j := range values[i] {
+			values[i][j] *= factor
+		}
+	}
+}
 
-	// Bilinear interpolation.
-	return bilinearInterpolate(latData[latIdx:latIdx+2], lonData[lonIdx:lonIdx+2], values, lat, lon), nil
+// nearestWet returns the non-NaN value closest to (lat, lon) in a window
+// and its distance in kilometers.
+func nearestWet(lats, lons []float64, values [][]float64, lat, lon float64) (value, km float64, ok bool) {
+	cosLat := math.Cos(domain.Deg2Rad(lat))
+	best := math.Inf(1)
+	for i := range values {
+		for j := range values[i] {
+			if math.IsNaN(values[i][j]) {
+				continue
+			}
+			dLat := lats[i] - lat
+			dLon := (lons[j] - lon) * cosLat
+			if d := dLat*dLat + dLon*dLon; d < best {
+				best, value, ok = d, values[i][j], true
+			}
+		}
+	}
+	return value, math.Sqrt(best) * kmPerDegree, ok
 }
 
 // findGridCell finds the index of the grid cell containing the given coordinate value.
-// Returns the lower index of the cell (i such that coords[i] <= val < coords[i+1]).
+// Returns the lower index of the cell (i such that val lies between coords[i] and
+// coords[i+1]); coords may be ascending or descending.
 // Returns -1 if val is outside the grid bounds.
 func findGridCell(coords []float64, val float64) int {
 	n := len(coords)
 	if n < 2 {
 		return -1
 	}
+	desc := coords[0] > coords[n-1]
 
 	// Check bounds.
-	if val < coords[0] || val > coords[n-1] {
+	lo, hi := coords[0], coords[n-1]
+	if desc {
+		lo, hi = hi, lo
+	}
+	if val < lo || val > hi {
 		return -1
 	}
 
@@ -601,7 +1179,7 @@ func findGridCell(coords []float64, val float64) int {
 	left, right := 0, n-1
 	for left < right-1 {
 		mid := (left + right) / 2
-		if coords[mid] <= val {
+		if (coords[mid] <= val) != desc || coords[mid] == val {
 			left = mid
 		} else {
 			right = mid
@@ -611,13 +1189,13 @@ func findGridCell(coords []float64, val float64) int {
 	return left
 }
 
-// readSubset2x2 reads a 2x2 subset from a NetCDF variable.
-// It reads data[latIdx:latIdx+2, lonIdx:lonIdx+2].
+// readSubset reads data[latIdx:latIdx+nLatC, lonIdx:lonIdx+nLonC] from a
+// NetCDF variable as [lat][lon], whatever the variable's dimension order.
 //
 //nolint:nestif // Type checking for NetCDF variable requires nested switch.
-func readSubset2x2(v netcdf.Var, nLat, nLon, latIdx, lonIdx int) ([][]float64, error) {
+func readSubset(v netcdf.Var, nLat, nLon, latIdx, lonIdx, nLatC, nLonC int) ([][]float64, error) {
 	// Verify indices are valid.
-	if latIdx < 0 || latIdx >= nLat-1 || lonIdx < 0 || lonIdx >= nLon-1 {
+	if latIdx < 0 || latIdx+nLatC > nLat || lonIdx < 0 || lonIdx+nLonC > nLon {
 		return nil, fmt.Errorf("invalid indices: latIdx=%d, lonIdx=%d, nLat=%d, nLon=%d", latIdx, lonIdx, nLat, nLon)
 	}
 
@@ -647,11 +1225,11 @@ func readSubset2x2(v netcdf.Var, nLat, nLon, latIdx, lonIdx int) ([][]float64, e
 	switch (dimPair{dim0Len, dim1Len}) {
 	case dimPair{uint64(nLat), uint64(nLon)}:
 		// Data is [lat, lon] - read directly.
-		flat, err = readSubsetFlat(v, latIdx, lonIdx, 2, 2)
+		flat, err = readSubsetFlat(v, latIdx, lonIdx, nLatC, nLonC)
 		needTranspose = false
 	case dimPair{uint64(nLon), uint64(nLat)}:
 		// Data is [lon, lat] - read transposed.
-		flat, err = readSubsetFlat(v, lonIdx, latIdx, 2, 2)
+		flat, err = readSubsetFlat(v, lonIdx, latIdx, nLonC, nLatC)
 		needTranspose = true
 	default:
 		return nil, fmt.Errorf("dimension mismatch: data is [%d, %d], expected [%d, %d] or [%d, %d]",
@@ -662,16 +1240,18 @@ func readSubset2x2(v netcdf.Var, nLat, nLon, latIdx, lonIdx int) ([][]float64, e
 		return nil, err
 	}
 
-	// Convert flat array to 2D.
-	values := make([][]float64, 2)
-	if needTranspose {
-		// flat is [lon, lat], need to transpose to [lat, lon].
-		values[0] = []float64{flat[0], flat[2]}
-		values[1] = []float64{flat[1], flat[3]}
-	} else {
-		// flat is [lat, lon].
-		values[0] = flat[0:2]
-		values[1] = flat[2:4]
+	// Convert flat array to 2D [lat, lon].
+	values := make([][]float64, nLatC)
+	for i := range values {
+		if needTranspose {
+			// flat is [lon, lat].
+			values[i] = make([]float64, nLonC)
+			for j := range values[i] {
+				values[i][j] = flat[j*nLatC+i]
+			}
+		} else {
+			values[i] = flat[i*nLonC : (i+1)*nLonC]
+		}
 	}
 
 	return values, nil
@@ -733,18 +1313,36 @@ func readSubsetFlat(v netcdf.Var, start0, start1, count0, count1 int) ([]float64
 }
 
 // bilinearInterpolate performs bilinear interpolation on a 2x2 grid.
+// NaN corners (fill values) are excluded and the remaining weights renormalized,
+// so coastal points use only wet neighbors. The result is NaN when no corner
+// with nonzero weight is wet.
 func bilinearInterpolate(lats, lons []float64, values [][]float64, lat, lon float64) float64 {
 	// Normalize coordinates to [0, 1].
 	dx := (lon - lons[0]) / (lons[1] - lons[0])
 	dy := (lat - lats[0]) / (lats[1] - lats[0])
 
-	// Bilinear interpolation formula.
-	v00 := values[0][0]
-	v01 := values[0][1]
-	v10 := values[1][0]
-	v11 := values[1][1]
+	// Bilinear weights for v00, v01, v10, v11.
+	corners := [4]float64{values[0][0], values[0][1], values[1][0], values[1][1]}
+	weights := [4]float64{(1 - dx) * (1 - dy), dx * (1 - dy), (1 - dx) * dy, dx * dy}
+	return weightedMean(corners, weights)
+}
 
-	return (1-dx)*(1-dy)*v00 + dx*(1-dy)*v01 + (1-dx)*dy*v10 + dx*dy*v11
+// weightedMean averages the corners of a cell with bilinear weights,
+// excluding NaN (fill) corners and renormalizing the remaining weights.
+// It returns NaN if no corner with a nonzero weight is wet.
+func weightedMean(corners, weights [4]float64) float64 {
+	var sum, wsum float64
+	for k, v := range corners {
+		if math.IsNaN(v) || weights[k] == 0 {
+			continue
+		}
+		sum += weights[k] * v
+		wsum += weights[k]
+	}
+	if wsum == 0 {
+		return math.NaN()
+	}
+	return sum / wsum
 }
 
 // loadNetCDFGrid reads a 2D grid from a NetCDF file.
@@ -896,24 +1494,8 @@ func loadNetCDFGrid(filepath, latVarName, lonVarName, dataVarName string) (*inte
 		}
 
 		// Handle fill values for complex components (replace with 0).
-		if fv, ok := getFillValue(realVar); ok {
-			for i := range reVals {
-				for j := range reVals[i] {
-					if reVals[i][j] == fv {
-						reVals[i][j] = 0
-					}
-				}
-			}
-		}
-		if fv, ok := getFillValue(imagVar); ok {
-			for i := range imVals {
-				for j := range imVals[i] {
-					if imVals[i][j] == fv {
-						imVals[i][j] = 0
-					}
-				}
-			}
-		}
+		maskFill(reVals, realVar, FillZero)
+		maskFill(imVals, imagVar, FillZero)
 
 		// Decide whether amplitude or phase is requested based on dataVarName hint.
 		want := strings.ToLower(dataVarName)
@@ -947,6 +1529,7 @@ func loadNetCDFGrid(filepath, latVarName, lonVarName, dataVarName string) (*inte
 		}
 
 		grid := &interp.Grid2D{X: lonData, Y: latData, Values: values}
+		grid.EnsureAscending()
 		if err := grid.Validate(); err != nil {
 			return nil, fmt.Errorf("invalid grid: %w", err)
 		}
@@ -1000,15 +1583,8 @@ func loadNetCDFGrid(filepath, latVarName, lonVarName, dataVarName string) (*inte
 	}
 
 	// Replace _FillValue or missing_value with 0 to avoid huge artifacts.
-	if fv, ok := getFillValue(dataVar); ok {
-		for i := range values {
-			for j := range values[i] {
-				if values[i][j] == fv {
-					values[i][j] = 0
-				}
-			}
-		}
-	}
+	// Full grids feed interp.Grid2D, which does not handle NaN.
+	maskFill(values, dataVar, FillZero)
 
 	// Unit conversion for amplitude grids: known FES ocean_tide files use centimeters.
 	// If reading from ocean_tide path and variable name indicates amplitude, convert cm->m.
@@ -1028,7 +1604,8 @@ func loadNetCDFGrid(filepath, latVarName, lonVarName, dataVarName string) (*inte
 		Values: values,
 	}
 
-	// Validate grid.
+	// Flip descending axes, then validate grid.
+	grid.EnsureAscending()
 	if err := grid.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid grid: %w", err)
 	}
@@ -1036,34 +1613,6 @@ func loadNetCDFGrid(filepath, latVarName, lonVarName, dataVarName string) (*inte
 	return grid, nil
 }
 
-// getFillValue returns the _FillValue or missing_value attribute if present as float64.
-func getFillValue(v netcdf.Var) (float64, bool) {
-	for _, name := range []string{"_FillValue", "missing_value"} {
-		a := v.Attr(name)
-		if a == (netcdf.Attr{}) {
-			continue
-		}
-		if n, err := a.Len(); err == nil && n > 0 {
-			// Try float64
-			buf64 := make([]float64, 1)
-			if err := a.ReadFloat64s(buf64); err == nil {
-				return buf64[0], true
-			}
-			// Try float32
-			buf32 := make([]float32, 1)
-			if err := a.ReadFloat32s(buf32); err == nil {
-				return float64(buf32[0]), true
-			}
-			// Try int32
-			bufi := make([]int32, 1)
-			if err := a.ReadInt32s(bufi); err == nil {
-				return float64(bufi[0]), true
-			}
-		}
-	}
-	return 0, false
-}
-
 // readFloat64Var reads a 1D float64 array from a NetCDF variable.
 func readFloat64Var(v netcdf.Var) ([]float64, error) {
 	dims, err := v.Dims()
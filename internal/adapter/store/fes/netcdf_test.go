@@ -1,140 +1,53 @@
 package fes
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
 
-	"github.com/fhs/go-netcdf/netcdf"
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
-// createBaseNC is a helper to create a minimal NetCDF with common setup.
-// It does NOT call EndDef - that must be done by the caller after adding all variables.
-func createBaseNC(t *testing.T, path string) (f netcdf.Dataset, latDim netcdf.Dim, lonDim netcdf.Dim) {
-	t.Helper()
-	//nolint:gosec // G301: Standard test directory permissions.
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		t.Fatalf("mkdir: %v", err)
-	}
-	var err error
-	f, err = netcdf.CreateFile(path, netcdf.CLOBBER)
-	if err != nil {
-		t.Fatalf("create nc: %v", err)
-	}
-
-	latDim, _ = f.AddDim("lat", 2)
-	lonDim, _ = f.AddDim("lon", 2)
-	_, _ = f.AddVar("lat", netcdf.DOUBLE, []netcdf.Dim{latDim})
-	_, _ = f.AddVar("lon", netcdf.DOUBLE, []netcdf.Dim{lonDim})
-
-	return f, latDim, lonDim
-}
-
-func add2DVar(t *testing.T, f netcdf.Dataset, varName string, latDim, lonDim netcdf.Dim) netcdf.Var {
-	t.Helper()
-	v, err := f.AddVar(varName, netcdf.FLOAT, []netcdf.Dim{latDim, lonDim})
-	if err != nil {
-		t.Fatalf("add var %s: %v", varName, err)
-	}
-	return v
-}
-
-func write2DVar(t *testing.T, v netcdf.Var, varName string, values [][]float32) {
-	t.Helper()
-	flat := []float32{values[0][0], values[0][1], values[1][0], values[1][1]}
-	if err := v.WriteFloat32s(flat); err != nil {
-		t.Fatalf("write %s: %v", varName, err)
-	}
-}
-
-// finalizeTwoVarNC completes a NetCDF file with two 2D variables by calling EndDef and writing lat/lon coordinates.
-func finalizeTwoVarNC(t *testing.T, f netcdf.Dataset, v1, v2 netcdf.Var, v1Name string, v1Data [][]float32, v2Name string, v2Data [][]float32) {
-	t.Helper()
-	if err := f.EndDef(); err != nil {
-		t.Fatalf("enddef: %v", err)
-	}
-
-	vlat, _ := f.Var("lat")
-	vlon, _ := f.Var("lon")
-	if err := vlat.WriteFloat64s([]float64{35.0, 36.0}); err != nil {
-		t.Fatalf("write lat: %v", err)
-	}
-	if err := vlon.WriteFloat64s([]float64{139.0, 140.0}); err != nil {
-		t.Fatalf("write lon: %v", err)
-	}
-
-	write2DVar(t, v1, v1Name, v1Data)
-	write2DVar(t, v2, v2Name, v2Data)
-}
+// testLat and testLon are the axes of the 2x2 fixtures.
+var (
+	testLat = []float64{35.0, 36.0}
+	testLon = []float64{139.0, 140.0}
+)
 
 // createCombinedAmpPhaseNC creates a minimal combined NetCDF with lat, lon, amplitude, phase (2x2).
 func createCombinedAmpPhaseNC(t *testing.T, path string, amp [][]float32, phase [][]float32) {
 	t.Helper()
-	f, latDim, lonDim := createBaseNC(t, path)
-	defer func() { _ = f.Close() }()
-
-	vAmp := add2DVar(t, f, "amplitude", latDim, lonDim)
-	vPhase := add2DVar(t, f, "phase", latDim, lonDim)
-
-	finalizeTwoVarNC(t, f, vAmp, vPhase, "amplitude", amp, "phase", phase)
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: testLat, Lon: testLon, Vars: []ncfixture.Var{
+		{Name: "amplitude", Values: amp},
+		{Name: "phase", Values: phase},
+	}})
 }
 
 func createAmpOnlyNC(t *testing.T, path string, values [][]float32) {
 	t.Helper()
-	f, latDim, lonDim := createBaseNC(t, path)
-	defer func() { _ = f.Close() }()
-
-	vAmp := add2DVar(t, f, "amplitude", latDim, lonDim)
-
-	if err := f.EndDef(); err != nil {
-		t.Fatalf("enddef: %v", err)
-	}
-
-	vlat, _ := f.Var("lat")
-	vlon, _ := f.Var("lon")
-	if err := vlat.WriteFloat64s([]float64{35.0, 36.0}); err != nil {
-		t.Fatalf("write lat: %v", err)
-	}
-	if err := vlon.WriteFloat64s([]float64{139.0, 140.0}); err != nil {
-		t.Fatalf("write lon: %v", err)
-	}
-
-	write2DVar(t, vAmp, "amplitude", values)
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: testLat, Lon: testLon, Vars: []ncfixture.Var{
+		{Name: "amplitude", Values: values},
+	}})
 }
 
 func createPhaseOnlyNC(t *testing.T, path string, values [][]float32) {
 	t.Helper()
-	f, latDim, lonDim := createBaseNC(t, path)
-	defer func() { _ = f.Close() }()
-
-	vPhase := add2DVar(t, f, "phase", latDim, lonDim)
-
-	if err := f.EndDef(); err != nil {
-		t.Fatalf("enddef: %v", err)
-	}
-
-	vlat, _ := f.Var("lat")
-	vlon, _ := f.Var("lon")
-	if err := vlat.WriteFloat64s([]float64{35.0, 36.0}); err != nil {
-		t.Fatalf("write lat: %v", err)
-	}
-	if err := vlon.WriteFloat64s([]float64{139.0, 140.0}); err != nil {
-		t.Fatalf("write lon: %v", err)
-	}
-
-	write2DVar(t, vPhase, "phase", values)
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: testLat, Lon: testLon, Vars: []ncfixture.Var{
+		{Name: "phase", Values: values},
+	}})
 }
 
 // createCombinedReImNC creates a minimal combined NetCDF with lat, lon, hRe, hIm (2x2).
 func createCombinedReImNC(t *testing.T, path string, re [][]float32, im [][]float32) {
 	t.Helper()
-	f, latDim, lonDim := createBaseNC(t, path)
-	defer func() { _ = f.Close() }()
-
-	vRe := add2DVar(t, f, "hRe", latDim, lonDim)
-	vIm := add2DVar(t, f, "hIm", latDim, lonDim)
-
-	finalizeTwoVarNC(t, f, vRe, vIm, "hRe", re, "hIm", im)
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: testLat, Lon: testLon, Vars: []ncfixture.Var{
+		{Name: "hRe", Values: re},
+		{Name: "hIm", Values: im},
+	}})
 }
 
 func TestGetAvailableConstituents_RecursiveDetectsShallow(t *testing.T) {
@@ -212,6 +125,25 @@ func TestLoadConstituent_SingleFileReIm_Derived(t *testing.T) {
 	}
 }
 
+func TestLoadForLocation_OceanTideReImConvertedOnce(t *testing.T) {
+	dir := t.TempDir()
+	createCombinedReImNC(t, filepath.Join(dir, "ocean_tide", "m2.nc"),
+		[][]float32{{3, 3}, {3, 3}},
+		[][]float32{{4, 4}, {4, 4}},
+	)
+	params, err := NewStore(dir).LoadForLocation(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation failed: %v", err)
+	}
+	// 5 cm -> 0.05 m, converted exactly once.
+	if got := params[0].AmplitudeM; math.Abs(got-0.05) > 1e-6 {
+		t.Errorf("amplitude = %v, want 0.05", got)
+	}
+	if got, want := params[0].PhaseDeg, domain.Rad2Deg(math.Atan2(4, 3)); math.Abs(got-want) > 1e-4 {
+		t.Errorf("phase = %v, want %v", got, want)
+	}
+}
+
 func TestLoadForLocation_WrapsNegativeLongitude(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "m2.nc")
@@ -233,6 +165,50 @@ func TestLoadForLocation_WrapsNegativeLongitude(t *testing.T) {
 	}
 }
 
+func TestLoadForLocation_ConfiguredConstituents(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"m2.nc", "m6.nc"} {
+		createCombinedAmpPhaseNC(t, filepath.Join(dir, name),
+			[][]float32{{1, 2}, {3, 4}},
+			[][]float32{{10, 20}, {30, 40}},
+		)
+	}
+	names := func(params []domain.ConstituentParam) []string {
+		out := make([]string, len(params))
+		for i, p := range params {
+			out[i] = p.Name
+		}
+		return out
+	}
+
+	s := NewStore(dir)
+	for _, tc := range []struct {
+		setting string
+		want    []string
+	}{
+		{"", []string{"M2"}},          // M6 is not in the default set.
+		{"all", []string{"M2", "M6"}}, // Default order first, then the rest.
+		{"m6, M6", []string{"M6"}},
+	} {
+		constituents, err := ParseConstituents(tc.setting)
+		if err != nil {
+			t.Fatalf("ParseConstituents(%q): %v", tc.setting, err)
+		}
+		s.SetConstituents(constituents)
+		params, err := s.LoadForLocation(35.5, 140)
+		if err != nil {
+			t.Fatalf("LoadForLocation with %q: %v", tc.setting, err)
+		}
+		if got := names(params); fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("constituents with %q = %v, want %v", tc.setting, got, tc.want)
+		}
+	}
+
+	if _, err := ParseConstituents("M2,XX9"); err == nil {
+		t.Error("expected error for unknown constituent")
+	}
+}
+
 func TestLoadConstituent_PrefersCombinedGlobalFile(t *testing.T) {
 	dir := t.TempDir()
 	createAmpOnlyNC(t, filepath.Join(dir, "q1_amplitude.nc"), [][]float32{{100, 100}, {100, 100}})
@@ -250,3 +226,352 @@ func TestLoadConstituent_PrefersCombinedGlobalFile(t *testing.T) {
 		t.Fatalf("expected combined file amplitude 0.01, got %v", got)
 	}
 }
+
+// testFill is the NetCDF default float fill value.
+const testFill = ncfixture.FillValue
+
+// createMaskedNC creates a combined amplitude/phase NetCDF on a 1° grid
+// starting at (35, 139), with a _FillValue attribute marking land cells.
+func createMaskedNC(t *testing.T, path string, amp, phase [][]float32) {
+	t.Helper()
+	lats := make([]float64, len(amp))
+	for i := range lats {
+		lats[i] = 35 + float64(i)
+	}
+	lons := make([]float64, len(amp[0]))
+	for j := range lons {
+		lons[j] = 139 + float64(j)
+	}
+	ncfixture.Write(t, path, ncfixture.Grid{Lat: lats, Lon: lons, Vars: []ncfixture.Var{
+		{Name: "amplitude", Values: amp, Fill: true},
+		{Name: "phase", Values: phase, Fill: true},
+	}})
+}
+
+func TestLoadForLocation_CoastalPointIgnoresFill(t *testing.T) {
+	dir := t.TempDir()
+	createMaskedNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{100, 200}, {testFill, 400}},
+		[][]float32{{10, 20}, {testFill, 30}},
+	)
+
+	tests := []struct {
+		policy    FillPolicy
+		amplitude float64
+		phase     float64
+	}{
+		// Cell center: equal weights over the three wet corners.
+		{FillNaN, 7.0 / 3.0, 20},
+		{FillNearest, 7.0 / 3.0, 20},
+		// Legacy: the land corner pulls the result toward zero.
+		{FillZero, 1.75, 15},
+	}
+	for _, tt := range tests {
+		s := NewStore(dir)
+		s.SetFillPolicy(tt.policy)
+		params, err := s.LoadForLocation(35.5, 139.5)
+		if err != nil {
+			t.Fatalf("%s: LoadForLocation: %v", tt.policy, err)
+		}
+		if got := params[0].AmplitudeM; math.Abs(got-tt.amplitude) > 1e-9 {
+			t.Errorf("%s: amplitude = %v, want %v", tt.policy, got, tt.amplitude)
+		}
+		if got := params[0].PhaseDeg; math.Abs(got-tt.phase) > 1e-9 {
+			t.Errorf("%s: phase = %v, want %v", tt.policy, got, tt.phase)
+		}
+	}
+}
+
+// A double fill attribute on float data (or the reverse) matches the fill
+// cells only up to float precision; they must not leak into interpolation.
+func TestLoadForLocation_FillAttributeTypeMismatch(t *testing.T) {
+	amp := [][]float32{{100, 200}, {testFill, 400}}
+	phase := [][]float32{{10, 20}, {testFill, 30}}
+	for _, v := range []ncfixture.Var{{Fill: true, Fill64: true}, {Fill: true, Double: true}} {
+		dir := t.TempDir()
+		ampVar, phaseVar := v, v
+		ampVar.Name, ampVar.Values = "amplitude", amp
+		phaseVar.Name, phaseVar.Values = "phase", phase
+		ncfixture.Write(t, filepath.Join(dir, "m2.nc"), ncfixture.Grid{
+			Lat: []float64{35, 36}, Lon: []float64{139, 140},
+			Vars: []ncfixture.Var{ampVar, phaseVar},
+		})
+
+		params, err := NewStore(dir).LoadForLocation(35.5, 139.5)
+		if err != nil {
+			t.Fatalf("LoadForLocation: %v", err)
+		}
+		if got, want := params[0].AmplitudeM, 7.0/3.0; math.Abs(got-want) > 1e-9 {
+			t.Errorf("double fill %v, double data %v: amplitude = %v, want %v", v.Fill64, v.Double, got, want)
+		}
+		if got := params[0].PhaseDeg; math.Abs(got-20) > 1e-9 {
+			t.Errorf("double fill %v, double data %v: phase = %v, want 20", v.Fill64, v.Double, got)
+		}
+	}
+}
+
+func TestLoadForLocation_LandPointIsOutOfCoverage(t *testing.T) {
+	dir := t.TempDir()
+	createMaskedNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{testFill, testFill}, {testFill, testFill}},
+		[][]float32{{testFill, testFill}, {testFill, testFill}},
+	)
+
+	_, err := NewStore(dir).LoadForLocation(35.5, 139.5)
+	if !errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Fatalf("expected ErrOutOfCoverage, got %v", err)
+	}
+}
+
+func TestLoadForLocations_MatchesSingleLoads(t *testing.T) {
+	dir := t.TempDir()
+	createMaskedNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{100, 200, 300}, {testFill, testFill, 500}, {testFill, testFill, 600}},
+		[][]float32{{10, 20, 30}, {testFill, testFill, 40}, {testFill, testFill, 50}},
+	)
+	createCombinedAmpPhaseNC(t, filepath.Join(dir, "s2.nc"),
+		[][]float32{{50, 60}, {70, 80}},
+		[][]float32{{90, 100}, {110, 120}},
+	)
+
+	s := NewStore(dir)
+	points := []domain.Position{
+		{Lat: 35.5, Lon: 139.5},  // Coastal.
+		{Lat: 36.5, Lon: 139.5},  // Land in m2, outside s2.
+		{Lat: 35.2, Lon: 139.9},  // Both grids.
+		{Lat: 35.2, Lon: -220.1}, // The same, wrapped.
+		{Lat: 10, Lon: 10},       // Outside both.
+		{Lat: 35.5, Lon: 139.5},  // Repeated.
+	}
+	params, errs := s.LoadForLocations(points)
+	if len(params) != len(points) || len(errs) != len(points) {
+		t.Fatalf("got %d results and %d errors for %d points", len(params), len(errs), len(points))
+	}
+	for i, p := range points {
+		want, wantErr := s.LoadForLocation(p.Lat, p.Lon)
+		if (errs[i] == nil) != (wantErr == nil) || errors.Is(errs[i], domain.ErrOutOfCoverage) != errors.Is(wantErr, domain.ErrOutOfCoverage) {
+			t.Errorf("point %d: error = %v, want %v", i, errs[i], wantErr)
+			continue
+		}
+		if fmt.Sprint(params[i]) != fmt.Sprint(want) {
+			t.Errorf("point %d: params = %v, want %v", i, params[i], want)
+		}
+	}
+	if !errors.Is(errs[1], domain.ErrOutOfCoverage) || !errors.Is(errs[4], domain.ErrOutOfCoverage) {
+		t.Errorf("errors = %v, want out of coverage for points 1 and 4", errs)
+	}
+	if len(params[2]) != 2 {
+		t.Errorf("point 2: params = %v, want M2 and S2", params[2])
+	}
+}
+
+func TestLoadForLocation_NearestWetFallback(t *testing.T) {
+	dir := t.TempDir()
+	amp := make([][]float32, 4)
+	phase := make([][]float32, 4)
+	for i := range amp {
+		amp[i] = []float32{testFill, testFill, testFill, testFill}
+		phase[i] = []float32{testFill, testFill, testFill, testFill}
+	}
+	amp[3][3], phase[3][3] = 500, 45 // Only wet point, at (38, 142).
+	createMaskedNC(t, filepath.Join(dir, "m2.nc"), amp, phase)
+
+	s := NewStore(dir)
+	if _, err := s.LoadForLocation(35.5, 139.5); !errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Fatalf("expected ErrOutOfCoverage with default policy, got %v", err)
+	}
+
+	s.SetFillPolicy(FillNearest)
+	params, err := s.LoadForLocation(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	if params[0].AmplitudeM != 5 || params[0].PhaseDeg != 45 {
+		t.Errorf("expected nearest wet values (5 m, 45°), got %+v", params[0])
+	}
+	// 2.5° of latitude and longitude away at 35.5°N.
+	km, err := s.WetFallback(35.5, 139.5)
+	want := math.Hypot(2.5, 2.5*math.Cos(domain.Deg2Rad(35.5))) * kmPerDegree
+	if err != nil || math.Abs(km-want) > 1e-6 {
+		t.Errorf("WetFallback = %v, %v; want %v km", km, err, want)
+	}
+	if km, err := s.WetFallback(37.5, 141.5); err != nil || km != 0 {
+		t.Errorf("WetFallback in a wet cell = %v, %v; want 0", km, err)
+	}
+
+	// The wet point is 3 cells away.
+	s.SetNearestWetRadius(1)
+	if _, err := s.LoadForLocation(35.5, 139.5); !errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Errorf("expected ErrOutOfCoverage beyond the search radius, got %v", err)
+	}
+}
+
+func TestBilinearInterpolate_NaNWithZeroWeight(t *testing.T) {
+	nan := math.NaN()
+	values := [][]float64{{1, nan}, {nan, nan}}
+	// Exactly on the wet corner: other corners have zero weight.
+	if got := bilinearInterpolate([]float64{0, 1}, []float64{0, 1}, values, 0, 0); got != 1 {
+		t.Errorf("expected 1 on wet corner, got %v", got)
+	}
+	// Exactly on a dry corner: no wet corner has weight.
+	if got := bilinearInterpolate([]float64{0, 1}, []float64{0, 1}, values, 1, 1); !math.IsNaN(got) {
+		t.Errorf("expected NaN on dry corner, got %v", got)
+	}
+}
+
+func TestParseFillPolicy(t *testing.T) {
+	if p, err := ParseFillPolicy("Nearest"); err != nil || p != FillNearest {
+		t.Errorf("ParseFillPolicy(Nearest) = %q, %v", p, err)
+	}
+	if _, err := ParseFillPolicy("mask"); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestLoadForLocation_TransposedDims(t *testing.T) {
+	dir := t.TempDir()
+	ncfixture.Write(t, filepath.Join(dir, "m2.nc"), ncfixture.Grid{
+		Lat:        testLat,
+		Lon:        []float64{139, 140, 141},
+		Transposed: true,
+		Vars: []ncfixture.Var{
+			{Name: "amplitude", Values: [][]float32{{100, 200, 300}, {100, 200, 300}}},
+			{Name: "phase", Values: [][]float32{{10, 20, 30}, {10, 20, 30}}},
+		},
+	})
+
+	params, err := NewStore(dir).LoadForLocation(35.5, 140.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	if got := params[0].AmplitudeM; math.Abs(got-2.5) > 1e-9 {
+		t.Errorf("amplitude = %v, want 2.5", got)
+	}
+	if got := params[0].PhaseDeg; math.Abs(got-25) > 1e-9 {
+		t.Errorf("phase = %v, want 25", got)
+	}
+
+	grid, err := NewStore(dir).loadConstituent("M2")
+	if err != nil {
+		t.Fatalf("loadConstituent: %v", err)
+	}
+	if got := grid.Amplitude.Values[0][2]; got != 300 {
+		t.Errorf("expected (lat, lon) grid after transpose, got %v at [0][2]", got)
+	}
+}
+
+func TestLoadForLocation_AntimeridianOn360Axis(t *testing.T) {
+	dir := t.TempDir()
+	// Written as 178°, 179°, 180°, 181° on the 0–360° axis.
+	ncfixture.Write(t, filepath.Join(dir, "m2.nc"), ncfixture.Grid{
+		Lat:    testLat,
+		Lon:    []float64{-180, -179, 178, 179},
+		Lon360: true,
+		Vars: []ncfixture.Var{
+			{Name: "amplitude", Values: [][]float32{{300, 400, 100, 200}, {300, 400, 100, 200}}},
+			{Name: "phase", Values: [][]float32{{30, 40, 10, 20}, {30, 40, 10, 20}}},
+		},
+	})
+
+	s := NewStore(dir)
+	for _, tt := range []struct {
+		lon, amplitude float64
+	}{
+		{-179.5, 3.5},
+		{179.5, 2.5}, // Across the antimeridian.
+		{180.5, 3.5},
+	} {
+		params, err := s.LoadForLocation(35.5, tt.lon)
+		if err != nil {
+			t.Fatalf("LoadForLocation(%v): %v", tt.lon, err)
+		}
+		if got := params[0].AmplitudeM; math.Abs(got-tt.amplitude) > 1e-9 {
+			t.Errorf("lon %v: amplitude = %v, want %v", tt.lon, got, tt.amplitude)
+		}
+	}
+}
+
+func TestFindGridCell_Descending(t *testing.T) {
+	asc := []float64{-1, 0, 1, 2}
+	desc := []float64{2, 1, 0, -1}
+	tests := []struct {
+		coords []float64
+		val    float64
+		want   int
+	}{
+		{asc, 0.5, 1},
+		{asc, 2, 2},
+		{asc, 3, -1},
+		{desc, 0.5, 1},
+		{desc, 1, 1},
+		{desc, 2, 0},
+		{desc, -1, 2},
+		{desc, -1.5, -1},
+	}
+	for _, tt := range tests {
+		if got := findGridCell(tt.coords, tt.val); got != tt.want {
+			t.Errorf("findGridCell(%v, %v) = %d, want %d", tt.coords, tt.val, got, tt.want)
+		}
+	}
+}
+
+func TestLoadForLocation_DescendingLatitude(t *testing.T) {
+	dir := t.TempDir()
+	// Row 0 is 36°N (north to south).
+	ncfixture.Write(t, filepath.Join(dir, "m2.nc"), ncfixture.Grid{
+		Lat: []float64{36.0, 35.0},
+		Lon: testLon,
+		Vars: []ncfixture.Var{
+			{Name: "amplitude", Values: [][]float32{{300, 300}, {100, 100}}},
+			{Name: "phase", Values: [][]float32{{30, 30}, {10, 10}}},
+		},
+	})
+
+	params, err := NewStore(dir).LoadForLocation(35.25, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	if got := params[0].AmplitudeM; math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("amplitude = %v, want 1.5", got)
+	}
+	if got := params[0].PhaseDeg; math.Abs(got-15) > 1e-9 {
+		t.Errorf("phase = %v, want 15", got)
+	}
+
+	grid, err := NewStore(dir).loadConstituent("M2")
+	if err != nil {
+		t.Fatalf("loadConstituent: %v", err)
+	}
+	if grid.Amplitude.Y[0] != 35.0 || grid.Amplitude.Values[0][0] != 100 {
+		t.Errorf("expected grid flipped to ascending latitude, got Y=%v values=%v", grid.Amplitude.Y, grid.Amplitude.Values)
+	}
+}
+
+func TestLoadConstituentAt_ReadsOneConstituent(t *testing.T) {
+	dir := t.TempDir()
+	createCombinedAmpPhaseNC(t, filepath.Join(dir, "MSF.nc"),
+		[][]float32{{10, 10}, {10, 10}},
+		[][]float32{{-30, -30}, {-30, -30}},
+	)
+	createMaskedNC(t, filepath.Join(dir, "m2.nc"),
+		[][]float32{{testFill, testFill}, {testFill, testFill}},
+		[][]float32{{testFill, testFill}, {testFill, testFill}},
+	)
+	s := NewStore(dir)
+
+	// The dataset spelling is found without a prior directory scan.
+	p, err := s.LoadConstituentAt("msf", 35.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadConstituentAt: %v", err)
+	}
+	if p.Name != "MSf" || math.Abs(p.AmplitudeM-0.1) > 1e-6 || math.Abs(p.PhaseDeg-330) > 1e-4 {
+		t.Errorf("got %+v, want MSf 0.1 m at 330°", p)
+	}
+
+	if _, err := s.LoadConstituentAt("M2", 35.5, 139.5); !errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Errorf("land point: expected ErrOutOfCoverage, got %v", err)
+	}
+	if _, err := s.LoadConstituentAt("XX9", 35.5, 139.5); err == nil {
+		t.Error("unknown constituent: want an error")
+	}
+}
@@ -0,0 +1,75 @@
+package fes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DatasetVersion identifies the data the store interpolates: the path,
+// size and modification time of every NetCDF file under the data
+// directory, the fill policy and the constituent selection. It changes
+// whenever a file is replaced, so persisted interpolations keyed by it
+// are never reused across datasets. With an index, only the indexed files
+// and their variables are hashed.
+func (s *Store) DatasetVersion() (string, error) {
+	h := sha256.New()
+	s.mu.RLock()
+	idx := s.index
+	s.mu.RUnlock()
+	if idx != nil {
+		if err := s.hashIndexed(h, idx); err != nil {
+			return "", err
+		}
+	} else if err := s.hashFiles(h); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(h, "fill=%s\nconstituents=%s\n", s.fill, strings.Join(s.constituents, ","))
+	return "fes:" + hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// hashFiles hashes the NetCDF files under the data directory.
+func (s *Store) hashFiles(h hash.Hash) error {
+	err := filepath.WalkDir(s.dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".nc") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dataDir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\n", filepath.ToSlash(rel), info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk FES directory: %w", err)
+	}
+	return nil
+}
+
+// hashIndexed hashes the indexed files and their variables.
+func (s *Store) hashIndexed(h hash.Hash, idx *Index) error {
+	for _, name := range idx.Names() {
+		entry := idx.Constituents[name]
+		for _, f := range []IndexedFile{entry.Amplitude, entry.Phase} {
+			info, err := os.Stat(s.indexedPath(f.Path))
+			if err != nil {
+				return fmt.Errorf("failed to stat indexed FES file: %w", err)
+			}
+			fmt.Fprintf(h, "%s\x00%s\x00%+v\x00%d\x00%d\n", name, f.Path, f.Variables, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package geocache
+
+import (
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/store/storetest"
+)
+
+func TestConstituentLoaderContract(t *testing.T) {
+	storetest.Run(t, storetest.Harness{
+		Stations:  true,
+		Locations: true,
+		New: func(*testing.T) store.ConstituentLoader {
+			return NewLoader(storetest.Memory{}, 16)
+		},
+	})
+}
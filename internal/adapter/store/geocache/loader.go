@@ -0,0 +1,316 @@
+// Package geocache caches interpolated constituent sets per geohash cell.
+package geocache
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"sync"
+
+	"go.ngs.io/tides-api/internal/adapter/circuit"
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// Precision is the geohash length of a cache cell (about 1.2 x 0.6 km).
+// FES grids are 1/16° or coarser, so constituents vary little within a cell.
+const Precision = 6
+
+// Backing persists cell sets beyond the LRU, e.g., across restarts.
+type Backing interface {
+	Get(cell string) ([]domain.ConstituentParam, bool, error)
+	Put(cell string, params []domain.ConstituentParam) error
+}
+
+// Loader wraps a ConstituentLoader and serves location queries from an LRU
+// of constituent sets interpolated at geohash cell centers. Station queries
+// are passed through.
+type Loader struct {
+	inner    store.ConstituentLoader
+	capacity int
+	backing  Backing // Optional second level behind the LRU.
+
+	mu             sync.Mutex
+	order          *list.List // Front is most recently used.
+	entries        map[string]*list.Element
+	hits           int64
+	misses         int64
+	evictions      int64
+	fallbacks      int64
+	persistentHits int64
+}
+
+type entry struct {
+	cell   string
+	params []domain.ConstituentParam
+}
+
+// NewLoader creates a cache holding up to capacity cells.
+func NewLoader(inner store.ConstituentLoader, capacity int) *Loader {
+	return &Loader{
+		inner:    inner,
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// SetBacking sets a persistent store consulted on LRU misses and filled
+// with every cell interpolated. Its errors are logged and otherwise
+// ignored, so a failing store only costs interpolations.
+func (l *Loader) SetBacking(b Backing) {
+	l.backing = b
+}
+
+// LoadForStation delegates to the wrapped loader.
+func (l *Loader) LoadForStation(stationID string) ([]domain.ConstituentParam, error) {
+	return l.inner.LoadForStation(stationID)
+}
+
+// LoadForLocation returns the constituent set of the location's cell,
+// interpolating it at the cell center on a miss. If the center has no data
+// (e.g., it falls on land near the coast) the exact location is loaded
+// instead and not cached.
+func (l *Loader) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error) {
+	cell := domain.EncodeGeohash(lat, lon, Precision)
+	if params, ok := l.lookup(cell); ok {
+		return clone(params), nil
+	}
+
+	centerLat, centerLon, _ := domain.DecodeGeohash(cell)
+	params, err := l.inner.LoadForLocation(centerLat, centerLon)
+	if err != nil {
+		l.mu.Lock()
+		l.fallbacks++
+		l.mu.Unlock()
+		return l.inner.LoadForLocation(lat, lon)
+	}
+	l.keep(cell, params)
+	return clone(params), nil
+}
+
+// LoadForLocations is LoadForLocation for several points. The missing
+// cells are interpolated in one call to the wrapped loader, each once
+// however many points fall in it, and the fallbacks to exact locations in
+// another.
+func (l *Loader) LoadForLocations(points []domain.Position) ([][]domain.ConstituentParam, []error) {
+	params := make([][]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+
+	// Missing cells in order of first use, with the points falling in each.
+	var cells []string
+	waiting := make(map[string][]int)
+	for i, p := range points {
+		cell := domain.EncodeGeohash(p.Lat, p.Lon, Precision)
+		if idx, ok := waiting[cell]; ok {
+			// Served by the load of the cell's first point.
+			l.mu.Lock()
+			l.hits++
+			l.mu.Unlock()
+			waiting[cell] = append(idx, i)
+			continue
+		}
+		if cached, ok := l.lookup(cell); ok {
+			params[i] = clone(cached)
+			continue
+		}
+		cells = append(cells, cell)
+		waiting[cell] = []int{i}
+	}
+	if len(cells) == 0 {
+		return params, errs
+	}
+
+	centers := make([]domain.Position, len(cells))
+	for j, cell := range cells {
+		centers[j].Lat, centers[j].Lon, _ = domain.DecodeGeohash(cell)
+	}
+	loaded, loadErrs := store.LoadForLocations(l.inner, centers)
+	var fallback []int
+	for j, cell := range cells {
+		if loadErrs[j] != nil {
+			fallback = append(fallback, waiting[cell]...)
+			continue
+		}
+		l.keep(cell, loaded[j])
+		for _, i := range waiting[cell] {
+			params[i] = clone(loaded[j])
+		}
+	}
+	if len(fallback) == 0 {
+		return params, errs
+	}
+
+	l.mu.Lock()
+	l.fallbacks += int64(len(fallback))
+	l.mu.Unlock()
+	exact := make([]domain.Position, len(fallback))
+	for k, i := range fallback {
+		exact[k] = points[i]
+	}
+	exactParams, exactErrs := store.LoadForLocations(l.inner, exact)
+	for k, i := range fallback {
+		params[i], errs[i] = exactParams[k], exactErrs[k]
+	}
+	return params, errs
+}
+
+// lookup returns a cell set from the LRU or else the backing store,
+// counting the hit or miss.
+func (l *Loader) lookup(cell string) ([]domain.ConstituentParam, bool) {
+	l.mu.Lock()
+	if el, ok := l.entries[cell]; ok {
+		l.order.MoveToFront(el)
+		l.hits++
+		params := el.Value.(*entry).params
+		l.mu.Unlock()
+		return params, true
+	}
+	l.misses++
+	l.mu.Unlock()
+
+	params, ok := l.loadBacking(cell)
+	if !ok {
+		return nil, false
+	}
+	l.mu.Lock()
+	l.persistentHits++
+	l.mu.Unlock()
+	l.add(cell, params)
+	return params, true
+}
+
+// loadBacking reads a cell from the backing store, if set.
+func (l *Loader) loadBacking(cell string) ([]domain.ConstituentParam, bool) {
+	if l.backing == nil {
+		return nil, false
+	}
+	params, ok, err := l.backing.Get(cell)
+	if err != nil {
+		log.Printf("Warning: failed to read persisted constituent cell: %v", err)
+		return nil, false
+	}
+	return params, ok
+}
+
+// keep caches a set interpolated at a cell center and persists it to the
+// backing store.
+func (l *Loader) keep(cell string, params []domain.ConstituentParam) {
+	// A set loaded while datasets are failing may be missing constituents.
+	if len(l.Circuits()) > 0 {
+		return
+	}
+	if l.backing != nil {
+		if err := l.backing.Put(cell, params); err != nil {
+			log.Printf("Warning: failed to persist constituent cell: %v", err)
+		}
+	}
+	l.add(cell, params)
+}
+
+// add inserts a cell set into the LRU, evicting the least recently used
+// cells past capacity.
+func (l *Loader) add(cell string, params []domain.ConstituentParam) {
+	l.mu.Lock()
+	if el, ok := l.entries[cell]; ok {
+		// Filled concurrently.
+		l.order.MoveToFront(el)
+	} else {
+		l.entries[cell] = l.order.PushFront(&entry{cell: cell, params: params})
+		for l.order.Len() > l.capacity {
+			oldest := l.order.Back()
+			l.order.Remove(oldest)
+			delete(l.entries, oldest.Value.(*entry).cell)
+			l.evictions++
+		}
+	}
+	l.mu.Unlock()
+}
+
+// LoadConstituentAt reads one constituent from the wrapped loader. Map
+// sampling bypasses the cache so it does not evict frequently used cells.
+func (l *Loader) LoadConstituentAt(name string, lat, lon float64) (domain.ConstituentParam, error) {
+	return store.LoadConstituentAt(l.inner, name, lat, lon)
+}
+
+// LoadConstituentAtPoints reads one constituent at several locations from
+// the wrapped loader, bypassing the cache like LoadConstituentAt.
+func (l *Loader) LoadConstituentAtPoints(name string, points []domain.Position) ([]domain.ConstituentParam, []error) {
+	return store.LoadConstituentAtPoints(l.inner, name, points)
+}
+
+// ConstituentCoverage delegates to the wrapped loader, if it reports coverage.
+func (l *Loader) ConstituentCoverage(name string) (store.GridCoverage, error) {
+	if r, ok := l.inner.(store.CoverageReporter); ok {
+		return r.ConstituentCoverage(name)
+	}
+	return store.GridCoverage{}, fmt.Errorf("%s: %w", name, domain.ErrConstituentUnavailable)
+}
+
+// WetFallback delegates to the wrapped loader, if it falls back to wet
+// grid points, at the point LoadForLocation interpolates: the cell center,
+// or the exact location when the center has no data.
+func (l *Loader) WetFallback(lat, lon float64) (float64, error) {
+	r, ok := l.inner.(store.WetFallbackReporter)
+	if !ok {
+		return 0, nil
+	}
+	centerLat, centerLon, _ := domain.DecodeGeohash(domain.EncodeGeohash(lat, lon, Precision))
+	if km, err := r.WetFallback(centerLat, centerLon); err == nil {
+		return km, nil
+	}
+	return r.WetFallback(lat, lon)
+}
+
+// CacheStats returns hit/miss counters and the current fill.
+func (l *Loader) CacheStats() store.CacheStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := store.CacheStats{
+		Capacity:       l.capacity,
+		Entries:        l.order.Len(),
+		Hits:           l.hits,
+		Misses:         l.misses,
+		Evictions:      l.evictions,
+		Fallbacks:      l.fallbacks,
+		PersistentHits: l.persistentHits,
+	}
+	if total := l.hits + l.misses; total > 0 {
+		stats.HitRate = float64(l.hits) / float64(total)
+	}
+	return stats
+}
+
+// Cached reports whether the location's cell is in the LRU, without
+// counting a hit or a miss.
+func (l *Loader) Cached(lat, lon float64) bool {
+	cell := domain.EncodeGeohash(lat, lon, Precision)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.entries[cell]
+	return ok
+}
+
+// CellCacheStats delegates to the wrapped loader, if it caches grid cells.
+func (l *Loader) CellCacheStats() (stats store.MemoryCacheStats, ok bool) {
+	if r, ok := l.inner.(store.CellCacheReporter); ok {
+		return r.CellCacheStats()
+	}
+	return stats, false
+}
+
+// Circuits delegates to the wrapped loader, if it reports circuits.
+func (l *Loader) Circuits() []circuit.Status {
+	if r, ok := l.inner.(store.CircuitReporter); ok {
+		return r.Circuits()
+	}
+	return nil
+}
+
+// clone copies a cached set so callers cannot modify the cache.
+func clone(params []domain.ConstituentParam) []domain.ConstituentParam {
+	out := make([]domain.ConstituentParam, len(params))
+	copy(out, params)
+	return out
+}
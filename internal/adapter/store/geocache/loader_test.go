@@ -0,0 +1,257 @@
+package geocache
+
+import (
+	"errors"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/circuit"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// countingLoader returns one constituent whose amplitude is the latitude queried.
+type countingLoader struct {
+	calls   int
+	failAll bool
+	failLat float64
+}
+
+func (c *countingLoader) LoadForStation(string) ([]domain.ConstituentParam, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *countingLoader) LoadForLocation(lat, _ float64) ([]domain.ConstituentParam, error) {
+	c.calls++
+	if c.failAll || lat == c.failLat {
+		return nil, errors.New("no data")
+	}
+	return []domain.ConstituentParam{{Name: "M2", AmplitudeM: lat}}, nil
+}
+
+func TestLoadForLocation_HitsWithinCell(t *testing.T) {
+	inner := &countingLoader{}
+	l := NewLoader(inner, 10)
+
+	first, err := l.LoadForLocation(35.6544, 139.7447)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// About 100 m away, same geohash-6 cell.
+	second, err := l.LoadForLocation(35.6550, 139.7450)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner calls = %d, want 1", inner.calls)
+	}
+	if first[0].AmplitudeM != second[0].AmplitudeM {
+		t.Errorf("expected identical sets within a cell, got %v and %v", first, second)
+	}
+
+	// Callers must not be able to modify cached values.
+	second[0].AmplitudeM = -1
+	third, _ := l.LoadForLocation(35.6544, 139.7447)
+	if third[0].AmplitudeM == -1 {
+		t.Error("cache was modified through a returned slice")
+	}
+
+	stats := l.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if !l.Cached(35.6550, 139.7450) || l.Cached(35.7, 139.7447) {
+		t.Error("Cached does not match the cells loaded")
+	}
+	if l.CacheStats() != stats {
+		t.Error("Cached changed the cache stats")
+	}
+}
+
+func TestLoadForLocation_Evicts(t *testing.T) {
+	inner := &countingLoader{}
+	l := NewLoader(inner, 2)
+
+	for _, lat := range []float64{10, 20, 30, 10} {
+		if _, err := l.LoadForLocation(lat, 0); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+	stats := l.CacheStats()
+	if stats.Evictions != 2 || stats.Entries != 2 || stats.Hits != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestLoadForLocation_FallsBackToExactPoint(t *testing.T) {
+	cell := domain.EncodeGeohash(35.6544, 139.7447, Precision)
+	centerLat, _, _ := domain.DecodeGeohash(cell)
+	inner := &countingLoader{failLat: centerLat}
+	l := NewLoader(inner, 10)
+
+	params, err := l.LoadForLocation(35.6544, 139.7447)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if params[0].AmplitudeM != 35.6544 {
+		t.Errorf("expected exact-point set, got %v", params)
+	}
+	if stats := l.CacheStats(); stats.Fallbacks != 1 || stats.Entries != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	inner.failAll = true
+	if _, err := l.LoadForLocation(0, 0); err == nil {
+		t.Error("expected error when no data is available")
+	}
+}
+
+// multiLoader is a countingLoader that also loads several points per call.
+type multiLoader struct {
+	countingLoader
+	batches [][]domain.Position
+}
+
+func (m *multiLoader) LoadForLocations(points []domain.Position) ([][]domain.ConstituentParam, []error) {
+	m.batches = append(m.batches, points)
+	params := make([][]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+	for i, p := range points {
+		params[i], errs[i] = m.LoadForLocation(p.Lat, p.Lon)
+	}
+	return params, errs
+}
+
+func TestLoadForLocations_LoadsMissingCellsTogether(t *testing.T) {
+	fallbackCell := domain.EncodeGeohash(34.6937, 135.5023, Precision)
+	centerLat, _, _ := domain.DecodeGeohash(fallbackCell)
+	inner := &multiLoader{countingLoader: countingLoader{failLat: centerLat}}
+	l := NewLoader(inner, 10)
+	if _, err := l.LoadForLocation(43.0621, 141.3544); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	points := []domain.Position{
+		{Lat: 35.6544, Lon: 139.7447},
+		{Lat: 35.6550, Lon: 139.7450}, // Same cell.
+		{Lat: 43.0621, Lon: 141.3544}, // Cached.
+		{Lat: 34.6937, Lon: 135.5023}, // Center without data.
+	}
+	params, errs := l.LoadForLocations(points)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("point %d: %v", i, err)
+		}
+	}
+	// One call for the missing cells, one for the exact fallback point.
+	if len(inner.batches) != 2 || len(inner.batches[0]) != 2 || len(inner.batches[1]) != 1 {
+		t.Errorf("batches = %v, want 2 cells then 1 exact point", inner.batches)
+	}
+	if params[0][0].AmplitudeM != params[1][0].AmplitudeM || params[3][0].AmplitudeM != 34.6937 {
+		t.Errorf("unexpected sets: %v", params)
+	}
+	params[0][0].AmplitudeM = -1
+	if params[1][0].AmplitudeM == -1 {
+		t.Error("points of a cell share a slice")
+	}
+	if stats := l.CacheStats(); stats.Hits != 2 || stats.Misses != 3 || stats.Fallbacks != 1 || stats.Entries != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+// failingLoader reports a failing dataset circuit.
+type failingLoader struct {
+	countingLoader
+	failing []circuit.Status
+}
+
+func (f *failingLoader) Circuits() []circuit.Status { return f.failing }
+
+func TestLoadForLocation_SkipsCacheWhileDatasetsFail(t *testing.T) {
+	inner := &failingLoader{failing: []circuit.Status{{Name: "S2", State: circuit.Open, Failures: 3}}}
+	l := NewLoader(inner, 10)
+
+	for range 2 {
+		if _, err := l.LoadForLocation(35.6544, 139.7447); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+	if inner.calls != 2 || l.CacheStats().Entries != 0 {
+		t.Errorf("expected uncached loads while a circuit fails, got %d calls, %+v", inner.calls, l.CacheStats())
+	}
+	if len(l.Circuits()) != 1 {
+		t.Errorf("expected circuits forwarded, got %+v", l.Circuits())
+	}
+
+	inner.failing = nil
+	_, _ = l.LoadForLocation(35.6544, 139.7447)
+	_, _ = l.LoadForLocation(35.6544, 139.7447)
+	if inner.calls != 3 {
+		t.Errorf("expected caching after recovery, got %d calls", inner.calls)
+	}
+}
+
+func TestLoadConstituentAt_BypassesCache(t *testing.T) {
+	inner := &countingLoader{}
+	l := NewLoader(inner, 10)
+
+	p, err := l.LoadConstituentAt("m2", 35.6544, 139.7447)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	// The exact point is read, not the cell center.
+	if p.Name != "M2" || p.AmplitudeM != 35.6544 {
+		t.Errorf("got %+v, want M2 at the exact latitude", p)
+	}
+	if stats := l.CacheStats(); stats.Entries != 0 || stats.Misses != 0 {
+		t.Errorf("sampling touched the cache: %+v", stats)
+	}
+
+	if _, err := l.LoadConstituentAt("K1", 35.6544, 139.7447); err == nil {
+		t.Error("missing constituent: want an error")
+	}
+}
+
+// mapBacking is an in-memory Backing.
+type mapBacking map[string][]domain.ConstituentParam
+
+func (m mapBacking) Get(cell string) ([]domain.ConstituentParam, bool, error) {
+	p, ok := m[cell]
+	return p, ok, nil
+}
+
+func (m mapBacking) Put(cell string, params []domain.ConstituentParam) error {
+	m[cell] = params
+	return nil
+}
+
+func TestLoadForLocation_Backing(t *testing.T) {
+	backing := mapBacking{}
+	inner := &countingLoader{}
+	l := NewLoader(inner, 10)
+	l.SetBacking(backing)
+
+	first, err := l.LoadForLocation(35.6544, 139.7447)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(backing) != 1 {
+		t.Fatalf("expected the interpolated cell persisted, got %d cells", len(backing))
+	}
+
+	// A new loader, as after a restart, serves the cell from the backing.
+	restarted := NewLoader(inner, 10)
+	restarted.SetBacking(backing)
+	second, err := restarted.LoadForLocation(35.6544, 139.7447)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("inner calls = %d, want 1", inner.calls)
+	}
+	if second[0] != first[0] {
+		t.Errorf("got %+v, want %+v", second, first)
+	}
+	_, _ = restarted.LoadForLocation(35.6544, 139.7447)
+	if stats := restarted.CacheStats(); stats.PersistentHits != 1 || stats.Hits != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
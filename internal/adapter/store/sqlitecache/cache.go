@@ -0,0 +1,129 @@
+// Package sqlitecache persists interpolated constituent sets per geohash
+// cell and computed datum tables in SQLite files, so a cold-started server
+// (e.g., on a mounted volume) does not compute them again.
+package sqlitecache
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Registers the sqlite3 driver.
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// retention is how long cells of a dataset version no server opened
+// are kept. Servers sharing the file with other versions keep theirs.
+const retention = 7 * 24 * time.Hour
+
+const schema = `
+CREATE TABLE IF NOT EXISTS versions (
+	version    TEXT PRIMARY KEY,
+	opened_at  INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS cells (
+	version TEXT NOT NULL,
+	cell    TEXT NOT NULL,
+	params  TEXT NOT NULL,
+	PRIMARY KEY (version, cell)
+);`
+
+// Cache stores constituent sets of one dataset version. Sets stored
+// under other versions are never returned.
+type Cache struct {
+	db      *sql.DB
+	version string
+}
+
+// Open opens (creating if needed) the cache file at path for the dataset
+// version, and drops the cells of versions not opened within a week.
+func Open(path, version string) (*Cache, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open constituent cache: %w", err)
+	}
+	c := &Cache{db: db, version: version}
+	if err := c.init(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize constituent cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *Cache) init() error {
+	ctx := context.Background()
+	if _, err := c.db.ExecContext(ctx, schema); err != nil {
+		return err
+	}
+	now := time.Now()
+	if _, err := c.db.ExecContext(ctx,
+		`INSERT INTO versions (version, opened_at) VALUES (?, ?)
+		 ON CONFLICT (version) DO UPDATE SET opened_at = excluded.opened_at`,
+		c.version, now.Unix()); err != nil {
+		return err
+	}
+	cutoff := now.Add(-retention).Unix()
+	if _, err := c.db.ExecContext(ctx,
+		`DELETE FROM cells WHERE version IN (SELECT version FROM versions WHERE opened_at < ?)`,
+		cutoff); err != nil {
+		return err
+	}
+	_, err := c.db.ExecContext(ctx, `DELETE FROM versions WHERE opened_at < ?`, cutoff)
+	return err
+}
+
+// Version returns the dataset version the cache serves.
+func (c *Cache) Version() string {
+	return c.version
+}
+
+// Get returns the stored constituent set of a cell, if any.
+func (c *Cache) Get(cell string) ([]domain.ConstituentParam, bool, error) {
+	var data string
+	err := c.db.QueryRowContext(context.Background(),
+		`SELECT params FROM cells WHERE version = ? AND cell = ?`, c.version, cell).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cell %s: %w", cell, err)
+	}
+	var params []domain.ConstituentParam
+	if err := json.Unmarshal([]byte(data), &params); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cell %s: %w", cell, err)
+	}
+	return params, true, nil
+}
+
+// Put stores the constituent set of a cell, replacing any previous one.
+func (c *Cache) Put(cell string, params []domain.ConstituentParam) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode cell %s: %w", cell, err)
+	}
+	if _, err := c.db.ExecContext(context.Background(),
+		`INSERT OR REPLACE INTO cells (version, cell, params) VALUES (?, ?, ?)`,
+		c.version, cell, string(data)); err != nil {
+		return fmt.Errorf("failed to write cell %s: %w", cell, err)
+	}
+	return nil
+}
+
+// Len returns the number of cells stored for the dataset version.
+func (c *Cache) Len() (int, error) {
+	var n int
+	if err := c.db.QueryRowContext(context.Background(),
+		`SELECT COUNT(*) FROM cells WHERE version = ?`, c.version).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count cells: %w", err)
+	}
+	return n, nil
+}
+
+// Close closes the cache file.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
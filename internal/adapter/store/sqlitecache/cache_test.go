@@ -0,0 +1,85 @@
+package sqlitecache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestPutGetAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cells.db")
+	params := []domain.ConstituentParam{
+		{Name: "M2", AmplitudeM: 0.5, PhaseDeg: 120, SpeedDegPerHr: 28.9841042},
+		{Name: "S2", AmplitudeM: 0.2, PhaseDeg: 150, SpeedDegPerHr: 30},
+	}
+
+	c, err := Open(path, "v1")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, ok, err := c.Get("xn76ur"); err != nil || ok {
+		t.Fatalf("get before put = %v, %v; want miss", ok, err)
+	}
+	if err := c.Put("xn76ur", params); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	c, err = Open(path, "v1")
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, ok, err := c.Get("xn76ur")
+	if err != nil || !ok {
+		t.Fatalf("get after reopen = %v, %v; want hit", ok, err)
+	}
+	if len(got) != 2 || got[0] != params[0] || got[1] != params[1] {
+		t.Errorf("got %+v, want %+v", got, params)
+	}
+	_ = c.Close()
+
+	// Another dataset version never sees the cell.
+	other, err := Open(path, "v2")
+	if err != nil {
+		t.Fatalf("open v2: %v", err)
+	}
+	defer other.Close()
+	if _, ok, _ := other.Get("xn76ur"); ok {
+		t.Error("cell of v1 returned for v2")
+	}
+}
+
+func TestOpenDropsStaleVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cells.db")
+	old, err := Open(path, "old")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := old.Put("xn76ur", []domain.ConstituentParam{{Name: "M2"}}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	stale := time.Now().Add(-retention - time.Hour).Unix()
+	if _, err := old.db.ExecContext(context.Background(),
+		`UPDATE versions SET opened_at = ? WHERE version = 'old'`, stale); err != nil {
+		t.Fatalf("age version: %v", err)
+	}
+	_ = old.Close()
+
+	c, err := Open(path, "new")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer c.Close()
+	var n int
+	if err := c.db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM cells`).Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("%d cells left, want stale version dropped", n)
+	}
+}
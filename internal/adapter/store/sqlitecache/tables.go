@@ -0,0 +1,74 @@
+package sqlitecache
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const tablesSchema = `
+CREATE TABLE IF NOT EXISTS tables (
+	key     TEXT PRIMARY KEY,
+	data    BLOB NOT NULL,
+	used_at INTEGER NOT NULL
+);`
+
+// Tables stores encoded results (e.g., datum tables) by a key that
+// identifies their computation, such as a fingerprint. Results not read
+// or written within a week are dropped when the file is opened, since
+// keys of earlier datasets or code versions are never asked for again.
+type Tables struct {
+	db *sql.DB
+}
+
+// OpenTables opens (creating if needed) the table cache file at path. It
+// may be the file of a constituent cache.
+func OpenTables(path string) (*Tables, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open table cache: %w", err)
+	}
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, tablesSchema); err == nil {
+		_, err = db.ExecContext(ctx, `DELETE FROM tables WHERE used_at < ?`, time.Now().Add(-retention).Unix())
+	}
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize table cache %s: %w", path, err)
+	}
+	return &Tables{db: db}, nil
+}
+
+// Get returns the stored result of a key, if any.
+func (t *Tables) Get(key string) ([]byte, bool, error) {
+	ctx := context.Background()
+	var data []byte
+	err := t.db.QueryRowContext(ctx, `SELECT data FROM tables WHERE key = ?`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read table %s: %w", key, err)
+	}
+	if _, err := t.db.ExecContext(ctx, `UPDATE tables SET used_at = ? WHERE key = ?`, time.Now().Unix(), key); err != nil {
+		return nil, false, fmt.Errorf("failed to touch table %s: %w", key, err)
+	}
+	return data, true, nil
+}
+
+// Put stores the result of a key, replacing any previous one.
+func (t *Tables) Put(key string, data []byte) error {
+	if _, err := t.db.ExecContext(context.Background(),
+		`INSERT OR REPLACE INTO tables (key, data, used_at) VALUES (?, ?, ?)`,
+		key, data, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to write table %s: %w", key, err)
+	}
+	return nil
+}
+
+// Close closes the cache file.
+func (t *Tables) Close() error {
+	return t.db.Close()
+}
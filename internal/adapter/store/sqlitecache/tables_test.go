@@ -0,0 +1,40 @@
+package sqlitecache
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTables_PutGetAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	// Tables share the file of a constituent cache.
+	cells, err := Open(path, "v1")
+	if err != nil {
+		t.Fatalf("open cells: %v", err)
+	}
+	defer func() { _ = cells.Close() }()
+
+	tables, err := OpenTables(path)
+	if err != nil {
+		t.Fatalf("open tables: %v", err)
+	}
+	if _, ok, err := tables.Get("sha256:ab"); err != nil || ok {
+		t.Fatalf("get before put = %v, %v; want miss", ok, err)
+	}
+	if err := tables.Put("sha256:ab", []byte(`{"msl":0.1}`)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := tables.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	tables, err = OpenTables(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer func() { _ = tables.Close() }()
+	data, ok, err := tables.Get("sha256:ab")
+	if err != nil || !ok || string(data) != `{"msl":0.1}` {
+		t.Fatalf("get after reopen = %q, %v, %v; want hit", data, ok, err)
+	}
+}
@@ -1,13 +1,187 @@
-// Package store defines interfaces for loading tidal constituent data.
+// Package store defines interfaces for loading tidal constituent data:
+// the public loaders of pkg/store and the optional capabilities the
+// server's adapters add to them.
 package store
 
-import "go.ngs.io/tides-api/internal/domain"
+import (
+	"fmt"
 
-// ConstituentLoader is the interface for loading tidal constituent parameters.
-type ConstituentLoader interface {
-	// LoadForStation loads parameters for a named station (e.g., "tokyo").
-	LoadForStation(stationID string) ([]domain.ConstituentParam, error)
+	"go.ngs.io/tides-api/internal/adapter/circuit"
+	"go.ngs.io/tides-api/pkg/domain"
+	"go.ngs.io/tides-api/pkg/store"
+)
 
-	// LoadForLocation loads parameters for a lat/lon location (using interpolation for FES).
-	LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error)
+// ConstituentLoader is the interface for loading tidal constituent
+// parameters, defined in pkg/store.
+type ConstituentLoader = store.ConstituentLoader
+
+// ConstituentSampler is implemented by loaders that can read a single
+// constituent at a location, e.g. to sample it over a map.
+type ConstituentSampler interface {
+	LoadConstituentAt(name string, lat, lon float64) (domain.ConstituentParam, error)
+}
+
+// LoadConstituentAt returns one constituent at a location, reading only it
+// when l is a ConstituentSampler.
+func LoadConstituentAt(l ConstituentLoader, name string, lat, lon float64) (domain.ConstituentParam, error) {
+	if s, ok := l.(ConstituentSampler); ok {
+		return s.LoadConstituentAt(name, lat, lon)
+	}
+	params, err := l.LoadForLocation(lat, lon)
+	if err != nil {
+		return domain.ConstituentParam{}, err
+	}
+	canonical, _ := domain.CanonicalConstituentName(name)
+	for _, p := range params {
+		if p.Name == canonical {
+			return p, nil
+		}
+	}
+	return domain.ConstituentParam{}, fmt.Errorf("constituent %s not available at (%.4f, %.4f)", name, lat, lon)
+}
+
+// MultiPointSampler is implemented by loaders that read a constituent at
+// several locations more cheaply together than one at a time.
+type MultiPointSampler interface {
+	// LoadConstituentAtPoints returns the constituent and error of each
+	// point.
+	LoadConstituentAtPoints(name string, points []domain.Position) ([]domain.ConstituentParam, []error)
+}
+
+// LoadConstituentAtPoints reads one constituent at several locations,
+// together when l is a MultiPointSampler. Results and errors are indexed
+// like points.
+func LoadConstituentAtPoints(l ConstituentLoader, name string, points []domain.Position) ([]domain.ConstituentParam, []error) {
+	if m, ok := l.(MultiPointSampler); ok {
+		return m.LoadConstituentAtPoints(name, points)
+	}
+	params := make([]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+	for i, p := range points {
+		params[i], errs[i] = LoadConstituentAt(l, name, p.Lat, p.Lon)
+	}
+	return params, errs
+}
+
+// MultiLocationLoader is implemented by loaders that load several
+// locations more cheaply together than one at a time, e.g. by opening each
+// dataset file once.
+type MultiLocationLoader interface {
+	// LoadForLocations returns the parameters and error of each point.
+	LoadForLocations(points []domain.Position) ([][]domain.ConstituentParam, []error)
+}
+
+// LoadForLocations loads several locations, together when l is a
+// MultiLocationLoader. Results and errors are indexed like points.
+func LoadForLocations(l ConstituentLoader, points []domain.Position) ([][]domain.ConstituentParam, []error) {
+	if m, ok := l.(MultiLocationLoader); ok {
+		return m.LoadForLocations(points)
+	}
+	params := make([][]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+	for i, p := range points {
+		params[i], errs[i] = l.LoadForLocation(p.Lat, p.Lon)
+	}
+	return params, errs
+}
+
+// CurrentLoader loads tidal current constituents, defined in pkg/store.
+type CurrentLoader = store.CurrentLoader
+
+// GridCoverage describes the grid a constituent is interpolated from.
+// Longitudes are in the convention of the dataset (e.g., 0-360).
+type GridCoverage struct {
+	Curvilinear bool     `json:"curvilinear"` // 2D coordinates, e.g., a rotated regional grid.
+	Lat         GridAxis `json:"lat"`
+	Lon         GridAxis `json:"lon"`
+}
+
+// GridAxis is the extent of a grid coordinate.
+type GridAxis struct {
+	Size int     `json:"size"` // Values (nodes of a curvilinear grid).
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step,omitempty"` // Mean spacing of a 1D axis.
+}
+
+// CoverageReporter is implemented by loaders that interpolate gridded
+// datasets.
+type CoverageReporter interface {
+	// ConstituentCoverage describes the grid of a constituent; errors wrap
+	// domain.ErrConstituentUnavailable when the dataset has none.
+	ConstituentCoverage(name string) (GridCoverage, error)
+}
+
+// WetFallbackReporter is implemented by loaders that can take the values
+// of land points from the nearest wet grid point.
+type WetFallbackReporter interface {
+	// WetFallback returns the distance in kilometers from a location to the
+	// grid point its values are taken from, or 0 when it is interpolated
+	// from its own grid cell.
+	WetFallback(lat, lon float64) (float64, error)
+}
+
+// StationMetadataLoader is implemented by loaders whose station files
+// describe the station (name, location, datum offset).
+type StationMetadataLoader interface {
+	LoadStationMetadata(stationID string) (*domain.StationMetadata, error)
+}
+
+// StationLister is implemented by loaders that can enumerate their
+// stations.
+type StationLister interface {
+	ListStations() ([]string, error)
+}
+
+// StationWriter is implemented by loaders that can store imported stations.
+type StationWriter interface {
+	SaveStation(stationID string, meta *domain.StationMetadata, constituents []domain.ConstituentParam) error
+}
+
+// CacheStats reports the effectiveness of a caching loader.
+type CacheStats struct {
+	Capacity  int     `json:"capacity"`
+	Entries   int     `json:"entries"`
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Evictions int64   `json:"evictions"`
+	Fallbacks int64   `json:"fallbacks"`
+	HitRate   float64 `json:"hit_rate"`
+	// PersistentHits counts misses served from a persistent cache.
+	PersistentHits int64 `json:"persistent_hits,omitempty"`
+}
+
+// CacheStatsReporter is implemented by loaders that cache results.
+type CacheStatsReporter interface {
+	CacheStats() CacheStats
+}
+
+// CacheProbe is implemented by caching loaders that can tell whether a
+// location query would be served from memory, e.g., for request logs.
+type CacheProbe interface {
+	Cached(lat, lon float64) bool
+}
+
+// MemoryCacheStats reports a cache bounded by memory.
+type MemoryCacheStats struct {
+	LimitBytes int64   `json:"limit_bytes"`
+	Bytes      int64   `json:"bytes"` // Estimated.
+	Entries    int     `json:"entries"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Evictions  int64   `json:"evictions"`
+	HitRate    float64 `json:"hit_rate"`
+}
+
+// CellCacheReporter is implemented by loaders caching the grid cells read
+// around points; ok is false when the cache is disabled.
+type CellCacheReporter interface {
+	CellCacheStats() (stats MemoryCacheStats, ok bool)
+}
+
+// CircuitReporter is implemented by loaders that skip repeatedly failing
+// datasets behind circuit breakers.
+type CircuitReporter interface {
+	// Circuits returns the breakers whose last call failed.
+	Circuits() []circuit.Status
 }
@@ -0,0 +1,231 @@
+// Package storetest is a conformance suite for store.ConstituentLoader
+// implementations. Each adapter seeds its own data format with Seed and runs
+// the suite, so loaders agree on error semantics, units, phase ranges and
+// constituent names.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// StationID is the station seeded by station loaders.
+const StationID = "contract"
+
+// Seeded region of location loaders: data must cover [35, 36]°N x
+// [139, 140]°E with uniform values.
+const (
+	InsideLat  = 35.5
+	InsideLon  = 139.5
+	OutsideLat = -40.0 // South Atlantic, far from the seeded region.
+	OutsideLon = -20.0
+)
+
+// Constituent is a seeded constituent.
+type Constituent struct {
+	Raw        string  // Spelling written to the dataset.
+	Name       string  // Identifier the loader must return.
+	AmplitudeM float64 // Written in the dataset's native unit.
+	PhaseDeg   float64 // Written as is; loaders return it wrapped to [0, 360).
+}
+
+// Seed is the data every harness writes.
+//
+//nolint:gochecknoglobals // Intentional: Read-only test fixture.
+var Seed = []Constituent{
+	{Raw: "m2", Name: "M2", AmplitudeM: 0.62, PhaseDeg: 145},
+	{Raw: "K1", Name: "K1", AmplitudeM: 0.18, PhaseDeg: -30},
+	{Raw: "MSF", Name: "MSf", AmplitudeM: 0.01, PhaseDeg: 360},
+	{Raw: "msq", Name: "MSqm", AmplitudeM: 0.005, PhaseDeg: 200},
+}
+
+// Harness describes a loader under test.
+type Harness struct {
+	// New returns a loader holding Seed: at StationID when Stations is set
+	// and over the seeded region when Locations is set.
+	New       func(t *testing.T) store.ConstituentLoader
+	Stations  bool
+	Locations bool
+}
+
+// Run runs the conformance suite.
+func Run(t *testing.T, h Harness) {
+	t.Helper()
+
+	t.Run("Station", func(t *testing.T) {
+		l := h.New(t)
+		if !h.Stations {
+			params, err := l.LoadForStation(StationID)
+			checkUnsupported(t, "LoadForStation", params, err)
+			return
+		}
+		params, err := l.LoadForStation(StationID)
+		if err != nil {
+			t.Fatalf("LoadForStation(%q): %v", StationID, err)
+		}
+		checkSeed(t, params)
+
+		params, err = l.LoadForStation("no_such_station")
+		if err == nil || params != nil {
+			t.Errorf("unknown station: got %d params, err %v; want nil and an error", len(params), err)
+		}
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			t.Errorf("unknown station must not report ErrOutOfCoverage: %v", err)
+		}
+	})
+
+	t.Run("Location", func(t *testing.T) {
+		l := h.New(t)
+		if !h.Locations {
+			params, err := l.LoadForLocation(InsideLat, InsideLon)
+			checkUnsupported(t, "LoadForLocation", params, err)
+			return
+		}
+		params, err := l.LoadForLocation(InsideLat, InsideLon)
+		if err != nil {
+			t.Fatalf("LoadForLocation(%v, %v): %v", InsideLat, InsideLon, err)
+		}
+		checkSeed(t, params)
+
+		// The same place with a wrapped longitude.
+		params, err = l.LoadForLocation(InsideLat, InsideLon-360)
+		if err != nil {
+			t.Fatalf("LoadForLocation(%v, %v): %v", InsideLat, InsideLon-360, err)
+		}
+		checkSeed(t, params)
+
+		params, err = l.LoadForLocation(OutsideLat, OutsideLon)
+		if !errors.Is(err, domain.ErrOutOfCoverage) || params != nil {
+			t.Errorf("outside coverage: got %d params, err %v; want nil and ErrOutOfCoverage", len(params), err)
+		}
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		l := h.New(t)
+		load := func() ([]domain.ConstituentParam, error) {
+			if h.Stations {
+				return l.LoadForStation(StationID)
+			}
+			return l.LoadForLocation(InsideLat, InsideLon)
+		}
+		if !h.Stations && !h.Locations {
+			t.Skip("loader supports no queries")
+		}
+		params, err := load()
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		for i := range params {
+			params[i].AmplitudeM = -1
+		}
+		params, err = load()
+		if err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		checkSeed(t, params)
+	})
+}
+
+// checkUnsupported asserts a query the loader does not support fails
+// without claiming the location is out of coverage.
+func checkUnsupported(t *testing.T, op string, params []domain.ConstituentParam, err error) {
+	t.Helper()
+	if err == nil || params != nil {
+		t.Errorf("unsupported %s: got %d params, err %v; want nil and an error", op, len(params), err)
+	}
+	if errors.Is(err, domain.ErrOutOfCoverage) {
+		t.Errorf("unsupported %s must not report ErrOutOfCoverage: %v", op, err)
+	}
+}
+
+// checkSeed asserts params hold exactly the seeded constituents, with
+// canonical names, standard speeds, amplitudes in meters and phases in
+// [0, 360).
+func checkSeed(t *testing.T, params []domain.ConstituentParam) {
+	t.Helper()
+	const tolerance = 1e-4 // Float32 datasets.
+
+	want := make(map[string]Constituent, len(Seed))
+	for _, c := range Seed {
+		want[c.Name] = c
+	}
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		if canonical, ok := domain.CanonicalConstituentName(p.Name); !ok || canonical != p.Name {
+			t.Errorf("%s: not a canonical constituent name (want %q)", p.Name, canonical)
+		}
+		if seen[p.Name] {
+			t.Errorf("%s: returned twice", p.Name)
+		}
+		seen[p.Name] = true
+
+		if speed, _ := domain.GetConstituentSpeed(p.Name); p.SpeedDegPerHr != speed {
+			t.Errorf("%s: speed = %v, want %v", p.Name, p.SpeedDegPerHr, speed)
+		}
+		if math.IsNaN(p.AmplitudeM) || math.IsInf(p.AmplitudeM, 0) || p.AmplitudeM < 0 {
+			t.Errorf("%s: invalid amplitude %v", p.Name, p.AmplitudeM)
+		}
+		if !(p.PhaseDeg >= 0 && p.PhaseDeg < 360) {
+			t.Errorf("%s: phase %v outside [0, 360)", p.Name, p.PhaseDeg)
+		}
+
+		c, ok := want[p.Name]
+		if !ok {
+			t.Errorf("%s: not seeded", p.Name)
+			continue
+		}
+		if math.Abs(p.AmplitudeM-c.AmplitudeM) > tolerance {
+			t.Errorf("%s: amplitude = %v m, want %v m", p.Name, p.AmplitudeM, c.AmplitudeM)
+		}
+		wantPhase := domain.NormalizePhaseDeg(c.PhaseDeg)
+		if d := math.Abs(p.PhaseDeg - wantPhase); math.Min(d, 360-d) > tolerance {
+			t.Errorf("%s: phase = %v, want %v", p.Name, p.PhaseDeg, wantPhase)
+		}
+	}
+	for name := range want {
+		if !seen[name] {
+			t.Errorf("%s: missing", name)
+		}
+	}
+}
+
+// Memory is an in-memory loader holding Seed at StationID and over the
+// seeded region. It is the reference implementation of the suite and an
+// inner loader for testing decorators such as caches.
+type Memory struct{}
+
+// LoadForStation returns Seed for StationID.
+func (Memory) LoadForStation(stationID string) ([]domain.ConstituentParam, error) {
+	if stationID != StationID {
+		return nil, fmt.Errorf("unknown station %s", stationID)
+	}
+	return seedParams(), nil
+}
+
+// LoadForLocation returns Seed inside the seeded region.
+func (Memory) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error) {
+	lon = math.Mod(math.Mod(lon, 360)+360, 360)
+	if lat < 35 || lat > 36 || lon < 139 || lon > 140 {
+		return nil, fmt.Errorf("location (%.4f, %.4f): %w", lat, lon, domain.ErrOutOfCoverage)
+	}
+	return seedParams(), nil
+}
+
+func seedParams() []domain.ConstituentParam {
+	params := make([]domain.ConstituentParam, len(Seed))
+	for i, c := range Seed {
+		speed, _ := domain.GetConstituentSpeed(c.Name)
+		params[i] = domain.ConstituentParam{
+			Name:          c.Name,
+			AmplitudeM:    c.AmplitudeM,
+			PhaseDeg:      domain.NormalizePhaseDeg(c.PhaseDeg),
+			SpeedDegPerHr: speed,
+		}
+	}
+	return params
+}
@@ -0,0 +1,15 @@
+package storetest
+
+import (
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+)
+
+func TestMemoryConforms(t *testing.T) {
+	Run(t, Harness{
+		Stations:  true,
+		Locations: true,
+		New:       func(*testing.T) store.ConstituentLoader { return Memory{} },
+	})
+}
@@ -0,0 +1,44 @@
+package tpxo
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/store/storetest"
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// writeSeed writes each seeded constituent as a uniform elevation file named
+// by its dataset spelling, with complex amplitudes in millimeters on a
+// 0–360° axis.
+func writeSeed(t *testing.T, dir string) {
+	t.Helper()
+	uniform := func(v float64) [][]float32 {
+		return [][]float32{{float32(v), float32(v)}, {float32(v), float32(v)}}
+	}
+	for _, c := range storetest.Seed {
+		g := domain.Deg2Rad(c.PhaseDeg)
+		ncfixture.Write(t, filepath.Join(dir, "h_"+c.Raw+"_tpxo9_atlas_30_v5.nc"), ncfixture.Grid{
+			Lat: []float64{35, 36}, Lon: []float64{139, 140},
+			LatName: "lat_z", LonName: "lon_z", Transposed: true, Lon360: true,
+			Vars: []ncfixture.Var{
+				{Name: "hRe", Values: uniform(c.AmplitudeM * 1000 * math.Cos(g)), Units: "mm"},
+				{Name: "hIm", Values: uniform(-c.AmplitudeM * 1000 * math.Sin(g)), Units: "mm"},
+			},
+		})
+	}
+}
+
+func TestConstituentLoaderContract(t *testing.T) {
+	storetest.Run(t, storetest.Harness{
+		Locations: true,
+		New: func(t *testing.T) store.ConstituentLoader {
+			dir := t.TempDir()
+			writeSeed(t, dir)
+			return NewStore(dir)
+		},
+	})
+}
@@ -0,0 +1,488 @@
+// Package tpxo provides access to TPXO9-atlas NetCDF tidal constituent
+// data, an alternative to the FES grids.
+//
+// The atlas ships one elevation file per constituent (h_<con>_*.nc), holding
+// the real and imaginary parts of the complex amplitude (hRe, hIm) over
+// separate lon_z and lat_z axes, and a grid file (grid_*.nc) whose mz
+// variable is the land mask. Transport files (u_*.nc) are not read: the
+// API predicts heights only. OTIS binary files are not supported; convert
+// them with the OSU tools first.
+package tpxo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	"go.ngs.io/tides-api/internal/adapter/ncfill"
+	"go.ngs.io/tides-api/internal/metrics"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	latVarName  = "lat_z"
+	lonVarName  = "lon_z"
+	realVarName = "hRe"
+	imagVarName = "hIm"
+	maskVarName = "mz"
+	// sourceName names TPXO reads in metrics.
+	sourceName = "tpxo"
+)
+
+// Store reads TPXO9-atlas constituent files from a data directory.
+type Store struct {
+	dataDir string
+
+	mu       sync.Mutex
+	scanned  bool
+	files    map[string]string // Elevation file of each constituent.
+	gridPath string            // Grid file with the land mask; "" if absent.
+}
+
+// NewStore creates a store for the TPXO files under dataDir. The directory
+// is scanned on first use.
+func NewStore(dataDir string) *Store {
+	return &Store{dataDir: dataDir}
+}
+
+// scan finds the elevation files and the grid file once.
+func (s *Store) scan() (map[string]string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scanned {
+		return s.files, s.gridPath, nil
+	}
+	files := make(map[string]string)
+	var gridPath string
+	err := filepath.WalkDir(s.dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		base := strings.ToLower(d.Name())
+		if d.IsDir() || filepath.Ext(base) != ".nc" {
+			return nil
+		}
+		if strings.HasPrefix(base, "grid_") {
+			gridPath = path
+			return nil
+		}
+		if name, ok := constituentOf(base); ok {
+			if _, dup := files[name]; !dup {
+				files[name] = path
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scan TPXO directory %s: %w", s.dataDir, err)
+	}
+	s.files, s.gridPath, s.scanned = files, gridPath, true
+	return files, gridPath, nil
+}
+
+// constituentOf returns the constituent of an elevation file name such as
+// "h_m2_tpxo9_atlas_30_v5.nc".
+func constituentOf(base string) (string, bool) {
+	rest, ok := strings.CutPrefix(base, "h_")
+	if !ok {
+		return "", false
+	}
+	token, _, _ := strings.Cut(strings.TrimSuffix(rest, ".nc"), "_")
+	return domain.CanonicalConstituentName(token)
+}
+
+// Constituents returns the constituents with elevation files, sorted.
+func (s *Store) Constituents() ([]string, error) {
+	files, _, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadForStation is not supported by the TPXO store (only lat/lon queries).
+func (s *Store) LoadForStation(_ string) ([]domain.ConstituentParam, error) {
+	return nil, errors.New("TPXO store does not support station_id queries - use lat/lon parameters")
+}
+
+// LoadForLocation interpolates every constituent at a location.
+func (s *Store) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error) {
+	params, errs := s.LoadForLocations([]domain.Position{{Lat: lat, Lon: lon}})
+	return params[0], errs[0]
+}
+
+// LoadForLocations interpolates every constituent at several locations,
+// opening each file once. The complex amplitude is interpolated bilinearly
+// over the wet corners of the grid cell, then converted to an amplitude in
+// meters and a Greenwich phase lag, as in the FES store. Results and errors
+// are indexed like points; points on land or outside the grid wrap
+// domain.ErrOutOfCoverage.
+func (s *Store) LoadForLocations(points []domain.Position) ([][]domain.ConstituentParam, []error) {
+	params := make([][]domain.ConstituentParam, len(points))
+	errs := make([]error, len(points))
+	fail := func(err error) ([][]domain.ConstituentParam, []error) {
+		for i := range errs {
+			errs[i] = err
+		}
+		return params, errs
+	}
+
+	files, gridPath, err := s.scan()
+	if err != nil {
+		return fail(err)
+	}
+	if len(files) == 0 {
+		return fail(fmt.Errorf("no TPXO elevation files found in %s", s.dataDir))
+	}
+	var masks [][4]bool
+	if gridPath != "" {
+		if masks, err = readMasks(gridPath, points); err != nil {
+			return fail(err)
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		speed, ok := domain.GetConstituentSpeed(name)
+		if !ok {
+			continue
+		}
+		start := time.Now()
+		values, err := interpolateFile(files[name], points, masks)
+		metrics.ObserveRead(sourceName, start, err)
+		if err != nil {
+			metrics.InterpolationFailures.With(sourceName, metrics.ReasonRead).Inc()
+			return fail(fmt.Errorf("constituent %s: %w", name, err))
+		}
+		for i, h := range values {
+			if math.IsNaN(real(h)) {
+				metrics.InterpolationFailures.With(sourceName, metrics.ResultNoData).Inc()
+				continue
+			}
+			params[i] = append(params[i], domain.ConstituentParam{
+				Name:          name,
+				AmplitudeM:    math.Hypot(real(h), imag(h)),
+				PhaseDeg:      domain.NormalizePhaseDeg(domain.Rad2Deg(math.Atan2(-imag(h), real(h)))),
+				SpeedDegPerHr: speed,
+			})
+		}
+	}
+
+	for i, p := range points {
+		if len(params[i]) == 0 {
+			errs[i] = fmt.Errorf("no TPXO constituents at (%.4f, %.4f): %w", p.Lat, p.Lon, domain.ErrOutOfCoverage)
+		}
+	}
+	return params, errs
+}
+
+// interpolateFile interpolates the complex amplitude of an elevation file,
+// in meters, at each point; NaN marks points without wet corners. masks,
+// if set, tells the wet corners of each point.
+func interpolateFile(path string, points []domain.Position, masks [][4]bool) ([]complex128, error) {
+	g, err := openGrid(path)
+	if err != nil {
+		return nil, err
+	}
+	defer g.close()
+	re, err := g.nc.Var(realVarName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: missing %s: %w", path, realVarName, err)
+	}
+	im, err := g.nc.Var(imagVarName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: missing %s: %w", path, imagVarName, err)
+	}
+	factor, err := unitFactor(textAttr(re, "units"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := make([]complex128, len(points))
+	for k, p := range points {
+		values[k] = complex(math.NaN(), math.NaN())
+		c, ok := g.cell(p)
+		if !ok {
+			continue
+		}
+		reCorners, err := g.readCorners(re, c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: read %s: %w", path, realVarName, err)
+		}
+		imCorners, err := g.readCorners(im, c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: read %s: %w", path, imagVarName, err)
+		}
+		var sumRe, sumIm, wsum float64
+		for n, w := range c.weights {
+			wet := reCorners[n] != 0 || imCorners[n] != 0 // The atlas holds zeros on land.
+			if masks != nil {
+				wet = masks[k][n]
+			}
+			if w == 0 || !wet || math.IsNaN(reCorners[n]) || math.IsNaN(imCorners[n]) {
+				continue
+			}
+			sumRe += w * reCorners[n]
+			sumIm += w * imCorners[n]
+			wsum += w
+		}
+		if wsum > 0 {
+			values[k] = complex(sumRe/wsum*factor, sumIm/wsum*factor)
+		}
+	}
+	return values, nil
+}
+
+// readMasks reads the wet corners of each point from the grid file.
+func readMasks(path string, points []domain.Position) ([][4]bool, error) {
+	g, err := openGrid(path)
+	if err != nil {
+		return nil, err
+	}
+	defer g.close()
+	mz, err := g.nc.Var(maskVarName)
+	if err != nil {
+		return nil, fmt.Errorf("%s: missing %s: %w", path, maskVarName, err)
+	}
+	masks := make([][4]bool, len(points))
+	for k, p := range points {
+		c, ok := g.cell(p)
+		if !ok {
+			continue
+		}
+		corners, err := g.readCorners(mz, c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: read %s: %w", path, maskVarName, err)
+		}
+		for n, m := range corners {
+			masks[k][n] = m > 0
+		}
+	}
+	return masks, nil
+}
+
+// unitFactor returns the factor converting amplitudes in units to meters.
+func unitFactor(units string) (float64, error) {
+	switch strings.ToLower(strings.TrimSpace(units)) {
+	case "", "m", "meter", "meters", "metre", "metres":
+		return 1, nil
+	case "cm", "centimeter", "centimeters":
+		return 0.01, nil
+	case "mm", "millimeter", "millimeters":
+		return 0.001, nil
+	default:
+		return 0, fmt.Errorf("unsupported amplitude units %q", units)
+	}
+}
+
+// grid is an open TPXO file and its axes.
+type grid struct {
+	nc       netcdf.Dataset
+	lat, lon []float64 // Ascending; lon on the file's own range (0–360° in the atlas).
+	latDim   string    // Dimension of lat_z, telling the layout of data variables.
+}
+
+// cell is the grid cell around a point: its lower indices and the bilinear
+// weights of its corners (lat0/lon0, lat0/lon1, lat1/lon0, lat1/lon1).
+type cell struct {
+	lat0, lon0 int
+	weights    [4]float64
+}
+
+func openGrid(path string) (g *grid, err error) {
+	nc, err := netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = nc.Close()
+		}
+	}()
+	g = &grid{nc: nc}
+	if g.lat, g.latDim, err = readAxis(nc, latVarName); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if g.lon, _, err = readAxis(nc, lonVarName); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return g, nil
+}
+
+func (g *grid) close() {
+	_ = g.nc.Close()
+}
+
+// cell locates a point, wrapping its longitude onto the file's axis; ok is
+// false outside the grid.
+func (g *grid) cell(p domain.Position) (cell, bool) {
+	lon := p.Lon
+	if lon < g.lon[0] {
+		lon += 360
+	} else if lon > g.lon[len(g.lon)-1] {
+		lon -= 360
+	}
+	i, ok := lowerIndex(g.lat, p.Lat)
+	if !ok {
+		return cell{}, false
+	}
+	j, ok := lowerIndex(g.lon, lon)
+	if !ok {
+		return cell{}, false
+	}
+	dy := (p.Lat - g.lat[i]) / (g.lat[i+1] - g.lat[i])
+	dx := (lon - g.lon[j]) / (g.lon[j+1] - g.lon[j])
+	return cell{lat0: i, lon0: j, weights: [4]float64{(1 - dx) * (1 - dy), dx * (1 - dy), (1 - dx) * dy, dx * dy}}, true
+}
+
+// lowerIndex returns i such that axis[i] <= x <= axis[i+1].
+func lowerIndex(axis []float64, x float64) (int, bool) {
+	n := len(axis)
+	if n < 2 || x < axis[0] || x > axis[n-1] {
+		return 0, false
+	}
+	i := sort.SearchFloat64s(axis, x)
+	if i > 0 {
+		i--
+	}
+	return min(i, n-2), true
+}
+
+// readCorners reads the 4 corners of a cell from a 2D variable stored as
+// (lat, lon) or (lon, lat), with fill values as NaN.
+func (g *grid) readCorners(v netcdf.Var, c cell) ([4]float64, error) {
+	var corners [4]float64
+	dims, err := v.Dims()
+	if err != nil {
+		return corners, err
+	}
+	if len(dims) != 2 {
+		return corners, fmt.Errorf("expected 2D variable, got %dD", len(dims))
+	}
+	first, err := dims[0].Name()
+	if err != nil {
+		return corners, err
+	}
+	latFirst := first == g.latDim
+	start := []uint64{uint64(c.lat0), uint64(c.lon0)} //nolint:gosec // G115: Indices are within the axes.
+	if !latFirst {
+		start[0], start[1] = start[1], start[0]
+	}
+	flat, err := readSlice(v, start, []uint64{2, 2})
+	if err != nil {
+		return corners, err
+	}
+	ncfill.Of(v).Mask(flat)
+	if latFirst {
+		copy(corners[:], flat)
+	} else {
+		// flat is [lon][lat].
+		corners = [4]float64{flat[0], flat[2], flat[1], flat[3]}
+	}
+	return corners, nil
+}
+
+// readAxis reads a 1D ascending coordinate variable and its dimension name.
+func readAxis(nc netcdf.Dataset, name string) ([]float64, string, error) {
+	v, err := nc.Var(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("missing %s: %w", name, err)
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(dims) != 1 {
+		return nil, "", fmt.Errorf("%s: expected 1D axis, got %dD", name, len(dims))
+	}
+	dim, err := dims[0].Name()
+	if err != nil {
+		return nil, "", err
+	}
+	n, err := dims[0].Len()
+	if err != nil {
+		return nil, "", err
+	}
+	values, err := readSlice(v, []uint64{0}, []uint64{n})
+	if err != nil {
+		return nil, "", fmt.Errorf("read %s: %w", name, err)
+	}
+	if !sort.Float64sAreSorted(values) {
+		return nil, "", fmt.Errorf("%s is not ascending", name)
+	}
+	return values, dim, nil
+}
+
+// readSlice reads a hyperslab of a numeric variable as float64.
+func readSlice(v netcdf.Var, start, count []uint64) ([]float64, error) {
+	total := uint64(1)
+	for _, c := range count {
+		total *= c
+	}
+	t, err := v.Type()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get var type: %w", err)
+	}
+	out := make([]float64, total)
+	switch t {
+	case netcdf.DOUBLE:
+		err = v.ReadFloat64Slice(out, start, count)
+	case netcdf.FLOAT:
+		err = convert(out, func(buf []float32) error { return v.ReadFloat32Slice(buf, start, count) })
+	case netcdf.INT:
+		err = convert(out, func(buf []int32) error { return v.ReadInt32Slice(buf, start, count) })
+	case netcdf.SHORT:
+		err = convert(out, func(buf []int16) error { return v.ReadInt16Slice(buf, start, count) })
+	case netcdf.BYTE:
+		err = convert(out, func(buf []int8) error { return v.ReadInt8Slice(buf, start, count) })
+	default:
+		return nil, fmt.Errorf("unsupported data type: %v", t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// convert reads values of type T with read and stores them in out.
+func convert[T int8 | int16 | int32 | float32](out []float64, read func([]T) error) error {
+	buf := make([]T, len(out))
+	if err := read(buf); err != nil {
+		return err
+	}
+	for i, x := range buf {
+		out[i] = float64(x)
+	}
+	return nil
+}
+
+// textAttr returns a text attribute of a variable, or "" if absent.
+func textAttr(v netcdf.Var, name string) string {
+	a := v.Attr(name)
+	n, err := a.Len()
+	if err != nil || n == 0 {
+		return ""
+	}
+	buf := make([]byte, n)
+	if err := a.ReadBytes(buf); err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(buf), "\x00")
+}
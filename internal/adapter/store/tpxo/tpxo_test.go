@@ -0,0 +1,98 @@
+package tpxo
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// writeAtlas writes an M2 elevation file of uniform complex amplitude
+// (in mm, stored (lon, lat) like the atlas) and a grid file with mask.
+func writeAtlas(t *testing.T, dir string, re, im float32, mask [][]float32) {
+	t.Helper()
+	grid := func(vars ...ncfixture.Var) ncfixture.Grid {
+		return ncfixture.Grid{
+			Lat: []float64{34, 35}, Lon: []float64{139, 140},
+			LatName: "lat_z", LonName: "lon_z", Transposed: true,
+			Vars: vars,
+		}
+	}
+	uniform := func(x float32) [][]float32 { return [][]float32{{x, x}, {x, x}} }
+	// A land corner holds garbage the mask must exclude.
+	hRe := uniform(re)
+	hRe[1][1] = 9999
+	ncfixture.Write(t, filepath.Join(dir, "h_m2_tpxo9_atlas_30_v5.nc"), grid(
+		ncfixture.Var{Name: "hRe", Values: hRe, Units: "mm"},
+		ncfixture.Var{Name: "hIm", Values: uniform(im), Units: "mm"},
+	))
+	// Transport files are ignored.
+	ncfixture.Write(t, filepath.Join(dir, "u_m2_tpxo9_atlas_30_v5.nc"), grid(
+		ncfixture.Var{Name: "uRe", Values: uniform(1)},
+	))
+	if mask != nil {
+		ncfixture.Write(t, filepath.Join(dir, "grid_tpxo9_atlas_30_v5.nc"), grid(
+			ncfixture.Var{Name: "mz", Values: mask},
+		))
+	}
+}
+
+func TestLoadForLocation_ComplexAmplitudeToGreenwichPhase(t *testing.T) {
+	dir := t.TempDir()
+	// 0.5 m at G = 120°: h = A e^{-iG}.
+	g := domain.Deg2Rad(120)
+	writeAtlas(t, dir, float32(500*math.Cos(g)), float32(-500*math.Sin(g)), [][]float32{{1, 1}, {1, 0}})
+
+	s := NewStore(dir)
+	names, err := s.Constituents()
+	if err != nil || len(names) != 1 || names[0] != "M2" {
+		t.Fatalf("Constituents() = %v, %v, want [M2]", names, err)
+	}
+	params, err := s.LoadForLocation(34.5, 139.5)
+	if err != nil {
+		t.Fatalf("LoadForLocation: %v", err)
+	}
+	if len(params) != 1 {
+		t.Fatalf("got %d constituents, want 1", len(params))
+	}
+	p := params[0]
+	if math.Abs(p.AmplitudeM-0.5) > 1e-6 || math.Abs(p.PhaseDeg-120) > 1e-4 {
+		t.Errorf("M2 = %.6f m, %.4f°, want 0.5 m, 120°", p.AmplitudeM, p.PhaseDeg)
+	}
+	if want, _ := domain.GetConstituentSpeed("M2"); p.SpeedDegPerHr != want {
+		t.Errorf("speed = %v, want %v", p.SpeedDegPerHr, want)
+	}
+}
+
+func TestLoadForLocations_OutOfCoverage(t *testing.T) {
+	dir := t.TempDir()
+	writeAtlas(t, dir, 100, 0, [][]float32{{0, 0}, {0, 1}})
+
+	params, errs := NewStore(dir).LoadForLocations([]domain.Position{
+		{Lat: 34.1, Lon: 139.1}, // The wet corner carries some weight.
+		{Lat: 34, Lon: 139},     // A land node: the wet corner carries none.
+		{Lat: 40, Lon: 139.5},   // Outside the grid.
+	})
+	if errs[0] != nil || len(params[0]) != 1 {
+		t.Errorf("point near a wet corner: %v, %v", params[0], errs[0])
+	}
+	for i := 1; i < 3; i++ {
+		if !errors.Is(errs[i], domain.ErrOutOfCoverage) {
+			t.Errorf("point %d: error = %v, want ErrOutOfCoverage", i, errs[i])
+		}
+	}
+}
+
+func TestUnitFactor(t *testing.T) {
+	for units, want := range map[string]float64{"": 1, "m": 1, "cm": 0.01, "millimeter": 0.001, "MM": 0.001} {
+		if got, err := unitFactor(units); err != nil || got != want {
+			t.Errorf("unitFactor(%q) = %v, %v, want %v", units, got, err, want)
+		}
+	}
+	if _, err := unitFactor("feet"); err == nil {
+		t.Error("unitFactor(feet) succeeded")
+	}
+}
@@ -0,0 +1,227 @@
+// Package vlm provides vertical land motion rates (e.g., glacial isostatic
+// adjustment or subsidence grids) for relative sea level predictions.
+package vlm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fhs/go-netcdf/netcdf"
+
+	"go.ngs.io/tides-api/internal/adapter/interp"
+	"go.ngs.io/tides-api/internal/adapter/ncfill"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// fillThreshold flags values above it as fill, for undeclared sentinels
+// (e.g., 1e36 in a double variable without a _FillValue attribute).
+const fillThreshold = 1e30
+
+// Grid is a vertical land motion rate grid in mm/yr, positive for uplift.
+// Land motion grids are coarse, so the whole grid is read at once.
+type Grid struct {
+	grid *interp.Grid2D
+	name string
+}
+
+// Open reads a NetCDF grid of vertical land motion rates with 1D latitude
+// and longitude variables. Rates in m/yr (per the units attribute) are
+// converted to mm/yr.
+func Open(path string) (*Grid, error) {
+	nc, err := netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open land motion grid: %w", err)
+	}
+	defer func() { _ = nc.Close() }()
+
+	lat, err := readAxis(nc, []string{"lat", "latitude", "y"})
+	if err != nil {
+		return nil, fmt.Errorf("%s: latitude: %w", path, err)
+	}
+	lon, err := readAxis(nc, []string{"lon", "longitude", "x"})
+	if err != nil {
+		return nil, fmt.Errorf("%s: longitude: %w", path, err)
+	}
+	values, err := readRates(nc, []string{"vlm", "rate", "vertical_rate", "dhdt", "up", "gia"}, len(lat), len(lon))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	grid := &interp.Grid2D{X: lon, Y: lat, Values: values}
+	grid.EnsureAscending()
+	if err := grid.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: invalid grid: %w", path, err)
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Grid{grid: grid, name: filepath.Base(path) + ":" + digest}, nil
+}
+
+// Name identifies the grid by file name and content digest.
+func (g *Grid) Name() string {
+	return g.name
+}
+
+// RateAt returns the interpolated rate in mm/yr at a location. Locations
+// outside the grid or next to fill cells are out of coverage.
+func (g *Grid) RateAt(lat, lon float64) (float64, error) {
+	x := lon
+	if g.grid.X[0] >= 0 && g.grid.X[len(g.grid.X)-1] > 180 && x < 0 {
+		x += 360 // Grid in [0, 360).
+	}
+	rate, err := g.grid.InterpolateAt(x, lat)
+	if err != nil {
+		return 0, fmt.Errorf("land motion at (%.4f, %.4f): %w: %w", lat, lon, domain.ErrOutOfCoverage, err)
+	}
+	if math.IsNaN(rate) {
+		return 0, fmt.Errorf("land motion at (%.4f, %.4f): %w", lat, lon, domain.ErrOutOfCoverage)
+	}
+	return rate, nil
+}
+
+// readAxis reads the first of names found as a 1D variable.
+func readAxis(nc netcdf.Dataset, names []string) ([]float64, error) {
+	for _, name := range names {
+		v, err := nc.Var(name)
+		if err != nil {
+			continue
+		}
+		dims, err := v.Dims()
+		if err != nil {
+			return nil, err
+		}
+		if len(dims) != 1 {
+			return nil, fmt.Errorf("%s: expected 1D variable, got %dD", name, len(dims))
+		}
+		n, err := dims[0].Len()
+		if err != nil {
+			return nil, err
+		}
+		values, err := readFloats(v, int(n)) //nolint:gosec // G115: NetCDF dimensions fit in int.
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("variable not found (tried: %v)", names)
+}
+
+// readRates reads the first of names found as a [lat, lon] or [lon, lat]
+// variable, with fill values as NaN.
+func readRates(nc netcdf.Dataset, names []string, nLat, nLon int) ([][]float64, error) {
+	var v netcdf.Var
+	var name string
+	for _, n := range names {
+		if found, err := nc.Var(n); err == nil {
+			v, name = found, n
+			break
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("rate variable not found (tried: %v)", names)
+	}
+	dims, err := v.Dims()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(dims) != 2 {
+		return nil, fmt.Errorf("%s: expected 2D variable, got %dD", name, len(dims))
+	}
+	rows, err := dims[0].Len()
+	if err != nil {
+		return nil, err
+	}
+	flat, err := readFloats(v, nLat*nLon)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	scale := 1.0
+	switch units := strings.ToLower(attrText(v, "units")); units {
+	case "", "mm/yr", "mm/year", "mm yr-1", "mm/a", "mm a-1":
+	case "m/yr", "m/year", "m yr-1", "m/a", "m a-1":
+		scale = 1000
+	default:
+		return nil, fmt.Errorf("%s: unsupported units %q (expected mm/yr or m/yr)", name, units)
+	}
+	fill := ncfill.Of(v)
+
+	transposed := rows != uint64(nLat) //nolint:gosec // G115: Axis lengths fit in uint64.
+	values := make([][]float64, nLat)
+	for i := range values {
+		values[i] = make([]float64, nLon)
+		for j := range values[i] {
+			r := flat[i*nLon+j]
+			if transposed {
+				r = flat[j*nLat+i]
+			}
+			if fill.Is(r) || math.Abs(r) > fillThreshold {
+				r = math.NaN()
+			}
+			values[i][j] = r * scale
+		}
+	}
+	return values, nil
+}
+
+// readFloats reads a DOUBLE or FLOAT variable of n values.
+func readFloats(v netcdf.Var, n int) ([]float64, error) {
+	typ, err := v.Type()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variable type: %w", err)
+	}
+	values := make([]float64, n)
+	switch typ {
+	case netcdf.DOUBLE:
+		err = v.ReadFloat64s(values)
+	case netcdf.FLOAT:
+		f32 := make([]float32, n)
+		err = v.ReadFloat32s(f32)
+		for i, f := range f32 {
+			values[i] = float64(f)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported data type: %v (expected DOUBLE or FLOAT)", typ)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// attrText returns a text attribute of a variable, or "" if absent.
+func attrText(v netcdf.Var, name string) string {
+	a := v.Attr(name)
+	n, err := a.Len()
+	if err != nil || n == 0 {
+		return ""
+	}
+	buf := make([]byte, n)
+	if err := a.ReadBytes(buf); err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(buf), "\x00")
+}
+
+// fileDigest returns the first 12 hex digits of a file's SHA-256.
+func fileDigest(path string) (string, error) {
+	//nolint:gosec // G304: File path from env var.
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read land motion grid: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read land motion grid: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
@@ -0,0 +1,51 @@
+package vlm
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.ngs.io/tides-api/internal/testutil/ncfixture"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestGrid_RateAt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vlm.nc")
+	ncfixture.Write(t, path, ncfixture.Grid{
+		Lat: []float64{36, 35, 34}, // Descending, as in many products.
+		Lon: []float64{139, 140},
+		Vars: []ncfixture.Var{{
+			Name: "vlm",
+			Values: [][]float32{
+				{-2, -4},
+				{-6, -8},
+				{ncfixture.FillValue, -8},
+			},
+			Fill: true,
+		}},
+	})
+
+	g, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !strings.HasPrefix(g.Name(), "vlm.nc:") || len(g.Name()) != len("vlm.nc:")+12 {
+		t.Errorf("Name() = %q, want file name and digest", g.Name())
+	}
+
+	rate, err := g.RateAt(35.5, 139.5)
+	if err != nil {
+		t.Fatalf("RateAt: %v", err)
+	}
+	if math.Abs(rate+5) > 1e-9 {
+		t.Errorf("rate = %v, want -5", rate)
+	}
+
+	for _, loc := range [][2]float64{{34.5, 139.5}, {10, 139.5}} {
+		if _, err := g.RateAt(loc[0], loc[1]); !errors.Is(err, domain.ErrOutOfCoverage) {
+			t.Errorf("RateAt(%v, %v) error = %v, want out of coverage", loc[0], loc[1], err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package xlsx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// Layout maps a sheet's columns to constituent fields. Columns are given as
+// header text (case-insensitive) or as column letters ("B").
+type Layout struct {
+	Sheet       string `json:"sheet,omitempty"`      // Sheet name; the first sheet when empty.
+	HeaderRow   int    `json:"header_row,omitempty"` // 1-based header row; data starts below it. Default 1.
+	Constituent string `json:"constituent"`
+	Amplitude   string `json:"amplitude"`
+	Phase       string `json:"phase"`
+	Speed       string `json:"speed,omitempty"` // Optional angular speed override (deg/hr).
+	Units       string `json:"units,omitempty"` // Amplitude units: m (default), cm, mm or ft.
+}
+
+// DefaultLayout matches sheets laid out like the station CSV format.
+func DefaultLayout() Layout {
+	return Layout{
+		HeaderRow:   1,
+		Constituent: "constituent",
+		Amplitude:   "amplitude",
+		Phase:       "phase",
+		Units:       "m",
+	}
+}
+
+// Row is one data row of an import preview.
+type Row struct {
+	Row           int     `json:"row"` // 1-based sheet row.
+	Constituent   string  `json:"constituent"`
+	AmplitudeM    float64 `json:"amplitude_m"`
+	PhaseDeg      float64 `json:"phase_deg"`
+	SpeedDegPerHr float64 `json:"speed_deg_per_hr"`
+	Error         string  `json:"error,omitempty"`
+}
+
+// Preview is the result of mapping a sheet with a layout. Constituents is
+// only usable when Valid is true.
+type Preview struct {
+	Sheet        string                    `json:"sheet"`
+	Layout       Layout                    `json:"layout"`
+	Rows         []Row                     `json:"rows"`
+	Errors       []string                  `json:"errors,omitempty"`
+	Valid        bool                      `json:"valid"`
+	Constituents []domain.ConstituentParam `json:"-"`
+}
+
+// Import maps sheet rows below the header to constituents. Layout problems
+// and per-row problems are reported in the preview rather than as errors;
+// rows with an empty constituent cell are skipped.
+func Import(sheet *Sheet, layout Layout) *Preview {
+	if layout.HeaderRow <= 0 {
+		layout.HeaderRow = 1
+	}
+	if layout.Units == "" {
+		layout.Units = "m"
+	}
+	preview := &Preview{Sheet: sheet.Name, Layout: layout, Rows: []Row{}}
+	fail := func(format string, args ...any) *Preview {
+		preview.Errors = append(preview.Errors, fmt.Sprintf(format, args...))
+		return preview
+	}
+
+	scale, err := domain.AmplitudeUnitScale(layout.Units)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if layout.HeaderRow > len(sheet.Rows) {
+		return fail("header row %d is beyond the last row (%d)", layout.HeaderRow, len(sheet.Rows))
+	}
+	header := sheet.Rows[layout.HeaderRow-1]
+
+	nameCol, err := resolveColumn(header, "constituent", layout.Constituent)
+	if err != nil {
+		fail("%v", err)
+	}
+	ampCol, err := resolveColumn(header, "amplitude", layout.Amplitude)
+	if err != nil {
+		fail("%v", err)
+	}
+	phaseCol, err := resolveColumn(header, "phase", layout.Phase)
+	if err != nil {
+		fail("%v", err)
+	}
+	speedCol := -1
+	if layout.Speed != "" {
+		if speedCol, err = resolveColumn(header, "speed", layout.Speed); err != nil {
+			fail("%v", err)
+		}
+	}
+	if len(preview.Errors) > 0 {
+		return preview
+	}
+
+	seen := make(map[string]int)
+	for i := layout.HeaderRow; i < len(sheet.Rows); i++ {
+		cells := sheet.Rows[i]
+		name := cell(cells, nameCol)
+		if name == "" {
+			continue
+		}
+		row := Row{Row: i + 1, Constituent: name}
+		if err := mapRow(&row, cells, ampCol, phaseCol, speedCol, scale); err != nil {
+			row.Error = err.Error()
+		} else if prev, dup := seen[row.Constituent]; dup {
+			row.Error = fmt.Sprintf("duplicate constituent (also row %d)", prev)
+		} else {
+			seen[row.Constituent] = row.Row
+		}
+		preview.Rows = append(preview.Rows, row)
+	}
+
+	preview.Valid = len(preview.Rows) > 0
+	if !preview.Valid {
+		return fail("no constituent rows below header row %d", layout.HeaderRow)
+	}
+	for _, row := range preview.Rows {
+		if row.Error != "" {
+			preview.Valid = false
+			preview.Errors = append(preview.Errors, fmt.Sprintf("row %d: %s", row.Row, row.Error))
+		}
+	}
+	if preview.Valid {
+		preview.Constituents = make([]domain.ConstituentParam, 0, len(preview.Rows))
+		for _, row := range preview.Rows {
+			preview.Constituents = append(preview.Constituents, domain.ConstituentParam{
+				Name:          row.Constituent,
+				AmplitudeM:    row.AmplitudeM,
+				PhaseDeg:      row.PhaseDeg,
+				SpeedDegPerHr: row.SpeedDegPerHr,
+			})
+		}
+	}
+	return preview
+}
+
+// mapRow parses one data row into row.
+func mapRow(row *Row, cells []string, ampCol, phaseCol, speedCol int, scale float64) error {
+	amplitude, err := parseNumber(cell(cells, ampCol))
+	if err != nil {
+		return fmt.Errorf("invalid amplitude: %w", err)
+	}
+	if amplitude < 0 {
+		return fmt.Errorf("negative amplitude %g", amplitude)
+	}
+	row.AmplitudeM = amplitude * scale
+
+	phase, err := parseNumber(cell(cells, phaseCol))
+	if err != nil {
+		return fmt.Errorf("invalid phase: %w", err)
+	}
+	row.PhaseDeg = math.Mod(math.Mod(phase, 360)+360, 360)
+
+	known := false
+	if canonical, ok := domain.CanonicalConstituentName(row.Constituent); ok {
+		row.Constituent = canonical
+		row.SpeedDegPerHr, known = domain.GetConstituentSpeed(canonical)
+	}
+	if speedCol >= 0 {
+		if s := cell(cells, speedCol); s != "" {
+			if row.SpeedDegPerHr, err = parseNumber(s); err != nil {
+				return fmt.Errorf("invalid speed: %w", err)
+			}
+			known = true
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown constituent %s (add a speed column to import it)", row.Constituent)
+	}
+	return nil
+}
+
+// resolveColumn finds a column by header text, then by column letter.
+func resolveColumn(header []string, field, spec string) (int, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return 0, fmt.Errorf("no column configured for %s", field)
+	}
+	for i, h := range header {
+		if strings.EqualFold(h, spec) {
+			return i, nil
+		}
+	}
+	if strings.Trim(strings.ToUpper(spec), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == "" {
+		if col, err := cellColumn(spec); err == nil {
+			return col, nil
+		}
+	}
+	return 0, fmt.Errorf("%s column %q not found in header row", field, spec)
+}
+
+func cell(cells []string, col int) string {
+	if col < len(cells) {
+		return cells[col]
+	}
+	return ""
+}
+
+// parseNumber parses a numeric cell, tolerating thousands separators and a
+// trailing degree sign.
+func parseNumber(s string) (float64, error) {
+	s = strings.TrimSuffix(strings.ReplaceAll(s, ",", ""), "°")
+	if s == "" {
+		return 0, errors.New("empty cell")
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,222 @@
+// Package xlsx reads harmonic constant tables from Excel (.xlsx) workbooks.
+//
+// Only the parts needed for tabular data are read: the workbook sheet list,
+// shared strings and cell values. Formulas are read as their cached values.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxPartSize bounds the decompressed size of one workbook part.
+	maxPartSize = 64 << 20
+	// maxRows bounds the rows read from one sheet.
+	maxRows = 100000
+)
+
+// Sheet is one worksheet as rows of cell text. Rows and cells are indexed
+// from zero (row 1 / column A); missing cells are empty strings.
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+type workbookXML struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationshipsXML struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type richTextXML struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (s richTextXML) text() string {
+	if len(s.Runs) == 0 {
+		return s.T
+	}
+	var b strings.Builder
+	for _, r := range s.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type sharedStringsXML struct {
+	Items []richTextXML `xml:"si"`
+}
+
+type worksheetXML struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string      `xml:"r,attr"`
+			T      string      `xml:"t,attr"`
+			V      string      `xml:"v"`
+			Inline richTextXML `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadSheet reads the named worksheet, or the first one when name is empty.
+func ReadSheet(r io.ReaderAt, size int64, name string) (*Sheet, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+
+	var wb workbookXML
+	if err := decodePart(zr, "xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	if len(wb.Sheets) == 0 {
+		return nil, errors.New("workbook has no sheets")
+	}
+	idx := 0
+	if name != "" {
+		idx = -1
+		names := make([]string, 0, len(wb.Sheets))
+		for i, s := range wb.Sheets {
+			if strings.EqualFold(s.Name, name) {
+				idx = i
+				break
+			}
+			names = append(names, s.Name)
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("sheet %q not found (have %s)", name, strings.Join(names, ", "))
+		}
+	}
+
+	var rels relationshipsXML
+	if err := decodePart(zr, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	target := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == wb.Sheets[idx].RID {
+			target = rel.Target
+			break
+		}
+	}
+	if target == "" {
+		return nil, fmt.Errorf("sheet %q has no worksheet part", wb.Sheets[idx].Name)
+	}
+	if strings.HasPrefix(target, "/") {
+		target = strings.TrimPrefix(target, "/")
+	} else {
+		target = path.Join("xl", target)
+	}
+
+	var shared sharedStringsXML
+	if err := decodePart(zr, "xl/sharedStrings.xml", &shared); err != nil && !errors.Is(err, errPartMissing) {
+		return nil, err
+	}
+
+	var ws worksheetXML
+	if err := decodePart(zr, target, &ws); err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{Name: wb.Sheets[idx].Name}
+	for i, row := range ws.Rows {
+		rowNum := row.R
+		if rowNum == 0 {
+			rowNum = i + 1
+		}
+		if rowNum > maxRows {
+			return nil, fmt.Errorf("sheet %q exceeds %d rows", sheet.Name, maxRows)
+		}
+		if rowNum > len(sheet.Rows) {
+			sheet.Rows = append(sheet.Rows, make([][]string, rowNum-len(sheet.Rows))...)
+		}
+		cells := sheet.Rows[rowNum-1]
+		for j, c := range row.Cells {
+			col := j
+			if c.R != "" {
+				if col, err = cellColumn(c.R); err != nil {
+					return nil, err
+				}
+			}
+			var text string
+			switch c.T {
+			case "s":
+				n, err := strconv.Atoi(strings.TrimSpace(c.V))
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, fmt.Errorf("cell %s: invalid shared string index %q", c.R, c.V)
+				}
+				text = shared.Items[n].text()
+			case "inlineStr":
+				text = c.Inline.text()
+			default:
+				text = c.V
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			cells[col] = strings.TrimSpace(text)
+		}
+		sheet.Rows[rowNum-1] = cells
+	}
+	return sheet, nil
+}
+
+// errPartMissing reports an absent workbook part; sharedStrings.xml is optional.
+var errPartMissing = errors.New("workbook part missing")
+
+func decodePart(zr *zip.Reader, name string, v any) error {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer func() { _ = rc.Close() }()
+		if err := xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", errPartMissing, name)
+}
+
+// cellColumn returns the zero-based column of a cell reference such as "B12".
+func cellColumn(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 || n > 3 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
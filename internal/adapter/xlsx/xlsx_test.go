@@ -0,0 +1,123 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+// buildWorkbook creates a minimal workbook with a cover sheet and a
+// "Constants" sheet holding the given worksheet XML rows.
+func buildWorkbook(t *testing.T, rows string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Cover" sheetId="1" r:id="rId1"/><sheet name="Constants" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="worksheet" Target="/xl/worksheets/sheet2.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Harbor constants</t></si><si><t>Name</t></si><si><r><t>Amp </t></r><r><t>(cm)</t></r></si><si><t>Phase</t></si><si><t>M2</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c></row>` + rows + `</sheetData></worksheet>`,
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+const constantsRows = `
+<row r="3"><c r="B3" t="s"><v>1</v></c><c r="C3" t="s"><v>2</v></c><c r="D3" t="s"><v>3</v></c><c r="E3" t="inlineStr"><is><t>Speed</t></is></c></row>
+<row r="4"><c r="B4" t="s"><v>4</v></c><c r="C4"><v>62</v></c><c r="D4"><v>-215</v></c></row>
+<row r="5"><c r="B5" t="inlineStr"><is><t>k1</t></is></c><c r="C5"><v>18</v></c><c r="D5" t="str"><v>30°</v></c></row>
+<row r="7"><c r="B7" t="inlineStr"><is><t>X1</t></is></c><c r="C7"><v>5</v></c><c r="D7"><v>10</v></c><c r="E7"><v>12.5</v></c></row>`
+
+func readConstants(t *testing.T, rows string) *Sheet {
+	t.Helper()
+	wb := buildWorkbook(t, rows)
+	sheet, err := ReadSheet(bytes.NewReader(wb), int64(len(wb)), "constants")
+	if err != nil {
+		t.Fatalf("ReadSheet: %v", err)
+	}
+	return sheet
+}
+
+func TestReadSheet(t *testing.T) {
+	sheet := readConstants(t, constantsRows)
+	if sheet.Name != "Constants" || len(sheet.Rows) != 7 {
+		t.Fatalf("unexpected sheet %q with %d rows", sheet.Name, len(sheet.Rows))
+	}
+	if got := strings.Join(sheet.Rows[2], "|"); got != "|Name|Amp (cm)|Phase|Speed" {
+		t.Errorf("header = %q", got)
+	}
+	if len(sheet.Rows[5]) != 0 {
+		t.Errorf("expected empty row 6, got %v", sheet.Rows[5])
+	}
+
+	wb := buildWorkbook(t, "")
+	if first, err := ReadSheet(bytes.NewReader(wb), int64(len(wb)), ""); err != nil || first.Name != "Cover" {
+		t.Errorf("expected first sheet, got %+v, %v", first, err)
+	}
+	if _, err := ReadSheet(bytes.NewReader(wb), int64(len(wb)), "Missing"); err == nil {
+		t.Error("expected error for missing sheet")
+	}
+}
+
+func TestImport(t *testing.T) {
+	layout := Layout{HeaderRow: 3, Constituent: "name", Amplitude: "Amp (cm)", Phase: "D", Speed: "speed", Units: "cm"}
+	preview := Import(readConstants(t, constantsRows), layout)
+	if !preview.Valid {
+		t.Fatalf("expected valid preview, got errors %v", preview.Errors)
+	}
+	if len(preview.Constituents) != 3 {
+		t.Fatalf("expected 3 constituents, got %+v", preview.Constituents)
+	}
+	m2, k1, x1 := preview.Constituents[0], preview.Constituents[1], preview.Constituents[2]
+	if m2.Name != "M2" || math.Abs(m2.AmplitudeM-0.62) > 1e-12 || m2.PhaseDeg != 145 {
+		t.Errorf("unexpected M2: %+v", m2)
+	}
+	if k1.Name != "K1" || k1.PhaseDeg != 30 || k1.SpeedDegPerHr == 0 {
+		t.Errorf("unexpected K1: %+v", k1)
+	}
+	if x1.SpeedDegPerHr != 12.5 || preview.Rows[2].Row != 7 {
+		t.Errorf("unexpected X1: %+v (row %d)", x1, preview.Rows[2].Row)
+	}
+}
+
+func TestImport_Validation(t *testing.T) {
+	sheet := readConstants(t, constantsRows+`
+<row r="8"><c r="B8" t="s"><v>4</v></c><c r="C8"><v>1</v></c><c r="D8"><v>2</v></c></row>`)
+
+	// Without the speed column X1 is unknown; row 8 repeats M2.
+	preview := Import(sheet, Layout{HeaderRow: 3, Constituent: "B", Amplitude: "C", Phase: "D", Units: "cm"})
+	if preview.Valid || preview.Constituents != nil {
+		t.Fatal("expected invalid preview")
+	}
+	if len(preview.Errors) != 2 || !strings.Contains(preview.Errors[0], "row 7") || !strings.Contains(preview.Errors[1], "duplicate") {
+		t.Errorf("unexpected errors: %v", preview.Errors)
+	}
+
+	preview = Import(sheet, Layout{HeaderRow: 3, Constituent: "name", Amplitude: "amplitude", Phase: "phase"})
+	if preview.Valid || len(preview.Errors) != 1 || !strings.Contains(preview.Errors[0], "amplitude column") {
+		t.Errorf("expected missing amplitude column, got %v", preview.Errors)
+	}
+}
@@ -0,0 +1,334 @@
+package http
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// adminAuth rejects requests without "Authorization: Bearer <token>".
+func adminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ExportSnapshot handles GET /admin/snapshot.
+func (h *Handler) ExportSnapshot(c *gin.Context) {
+	c.Header("Content-Disposition", `attachment; filename="tides-api-snapshot.json"`)
+	c.JSON(http.StatusOK, h.prediction(c).ExportSnapshot())
+}
+
+// RestoreSnapshot handles POST /admin/snapshot.
+func (h *Handler) RestoreSnapshot(c *gin.Context) {
+	var snapshot usecase.Snapshot
+	if !bindJSON(c, &snapshot, "invalid snapshot") {
+		return
+	}
+	result, err := h.prediction(c).RestoreSnapshot(&snapshot)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Warm handles POST /v1/admin/warm?locations=lat,lon|lat,lon: preloads the
+// constituents and bathymetry of the locations, e.g. from a scheduled job
+// right after a deployment, so that first users do not wait for reads.
+func (h *Handler) Warm(c *gin.Context) {
+	locations, err := parseWarmLocations(c.QueryArray("locations"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.prediction(c).Warm(locations))
+}
+
+// parseWarmLocations parses "lat,lon" pairs separated by "|", from one or
+// more values. (Go's query parsing rejects ";".)
+func parseWarmLocations(values []string) ([]usecase.WarmupLocation, error) {
+	var locations []usecase.WarmupLocation
+	for _, pair := range strings.Split(strings.Join(values, "|"), "|") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		latStr, lonStr, ok := strings.Cut(pair, ",")
+		if !ok {
+			return nil, fmt.Errorf("invalid location %q (expected lat,lon)", pair)
+		}
+		lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+		if err != nil || lat < -90 || lat > 90 {
+			return nil, fmt.Errorf("invalid latitude in %q", pair)
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+		if err != nil || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("invalid longitude in %q", pair)
+		}
+		locations = append(locations, usecase.WarmupLocation{Lat: lat, Lon: lon})
+	}
+	if len(locations) == 0 || len(locations) > usecase.MaxWarmLocations {
+		return nil, fmt.Errorf("locations must list 1 to %d lat,lon pairs separated by |", usecase.MaxWarmLocations)
+	}
+	return locations, nil
+}
+
+// GetMetrics handles GET /admin/metrics.
+func (h *Handler) GetMetrics(c *gin.Context) {
+	metrics := gin.H{"time": h.prediction(c).Clock().Now().UTC().Format(time.RFC3339)}
+	if stats, ok := h.prediction(c).ConstituentCacheStats(); ok {
+		metrics["constituent_cache"] = stats
+	}
+	if stats, ok := h.prediction(c).GridCellCacheStats(); ok {
+		metrics["grid_cell_cache"] = stats
+	}
+	metrics["file_retries"] = h.prediction(c).FileRetryStats()
+	metrics["coalesced_predictions"] = h.prediction(c).CoalescingStats()
+	if circuits, ok := h.prediction(c).DatasetCircuits(); ok {
+		metrics["dataset_circuits"] = circuits
+	}
+	if h.shadow != nil {
+		metrics["shadow"] = h.shadow.Stats()
+	}
+	if h.scheduler != nil {
+		metrics["priority"] = h.scheduler.Stats()
+	}
+	c.JSON(http.StatusOK, metrics)
+}
+
+// ImportStation handles POST /admin/stations/:id/import.
+//
+// The multipart form carries the workbook as "file", an optional JSON
+// "layout" (fields not given keep their defaults) and optional
+// station_name, lat, lon and datum_offset_m values. The import is only
+// previewed unless commit=true.
+func (h *Handler) ImportStation(c *gin.Context) {
+	req := usecase.StationImportRequest{
+		StationID: c.Param("id"),
+		Layout:    usecase.DefaultImportLayout(),
+		Commit:    c.Query("commit") == "true",
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		writeBodyError(c, err, "missing workbook file")
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read workbook: %v", err)})
+		return
+	}
+	defer func() { _ = f.Close() }()
+	if req.Workbook, err = io.ReadAll(f); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("failed to read workbook: %v", err)})
+		return
+	}
+
+	if layout := c.PostForm("layout"); layout != "" {
+		if err := json.Unmarshal([]byte(layout), &req.Layout); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid layout: %v", err)})
+			return
+		}
+	}
+	req.Station.Name = c.PostForm("station_name")
+	for field, dst := range map[string]**float64{
+		"lat":            &req.Station.Lat,
+		"lon":            &req.Station.Lon,
+		"datum_offset_m": &req.Station.DatumOffsetM,
+	} {
+		if v := c.PostForm(field); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s: %v", field, err)})
+				return
+			}
+			*dst = &f
+		}
+	}
+
+	result, err := h.prediction(c).ImportStationWorkbook(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	status := http.StatusOK
+	if !result.Valid {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, result)
+}
+
+// datumEstimateRequest is the body of POST /admin/estimate-datum.
+type datumEstimateRequest struct {
+	Station string    `json:"station" binding:"required"`
+	Start   time.Time `json:"start" binding:"required"`
+	End     time.Time `json:"end" binding:"required"`
+	Lat     *float64  `json:"lat"`
+	Lon     *float64  `json:"lon"`
+	Write   bool      `json:"write"`
+}
+
+// EstimateDatum handles POST /v1/admin/estimate-datum: the recommended
+// datum_offset_m of a JMA station from its observations over a period,
+// optionally written to the datum offset table.
+func (h *Handler) EstimateDatum(c *gin.Context) {
+	var body datumEstimateRequest
+	if !bindJSON(c, &body, "invalid request body") {
+		return
+	}
+
+	estimate, err := h.prediction(c).EstimateDatumOffset(usecase.DatumEstimateRequest{
+		Station: body.Station,
+		Lat:     body.Lat,
+		Lon:     body.Lon,
+		Start:   body.Start.UTC(),
+		End:     body.End.UTC(),
+		Write:   body.Write,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecase.ErrInvalidDatumEstimate):
+			status = http.StatusBadRequest
+		case errors.Is(err, usecase.ErrObservationFetch):
+			status = http.StatusBadGateway
+		case errors.Is(err, usecase.ErrNoObservationSource):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, estimate)
+}
+
+// radiusTuningRequest is the body of POST /admin/tune-radii.
+type radiusTuningRequest struct {
+	Stations    []string `json:"stations"`
+	MinRadiusKm float64  `json:"min_radius_km"`
+	MaxRadiusKm float64  `json:"max_radius_km"`
+	Days        int      `json:"days"`
+	Write       bool     `json:"write"`
+}
+
+// TuneRadii handles POST /admin/tune-radii: the radius of each station
+// override within which it improves on pure FES, estimated from the other
+// override stations, optionally written to the station override table.
+func (h *Handler) TuneRadii(c *gin.Context) {
+	var body radiusTuningRequest
+	if !bindJSON(c, &body, "invalid request body") {
+		return
+	}
+
+	tuning, err := h.prediction(c).TuneOverrideRadii(usecase.RadiusTuningRequest{
+		Stations:    body.Stations,
+		MinRadiusKm: body.MinRadiusKm,
+		MaxRadiusKm: body.MaxRadiusKm,
+		Days:        body.Days,
+		Write:       body.Write,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tuning)
+}
+
+// ExportFeed handles GET /admin/feed: a ZIP of a year of predicted highs
+// and lows per station (the current year by default), for publishing tide
+// tables without querying the API.
+func (h *Handler) ExportFeed(c *gin.Context) {
+	uc := h.prediction(c)
+	req := usecase.FeedRequest{
+		Year:     uc.Clock().Now().Year(),
+		Datum:    c.Query("datum"),
+		Timezone: c.Query("timezone"),
+	}
+	if yearStr := c.Query("year"); yearStr != "" {
+		year, err := strconv.Atoi(yearStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid year"})
+			return
+		}
+		req.Year = year
+	}
+	if s := c.Query("stations"); s != "" {
+		for _, id := range strings.Split(s, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				req.Stations = append(req.Stations, id)
+			}
+		}
+	}
+
+	// Buffered, so that a failing station is reported instead of a
+	// truncated archive.
+	var buf bytes.Buffer
+	if err := uc.WriteFeed(&buf, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tides-feed-%d.zip"`, req.Year))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// ListRecalibrations handles GET /admin/recalibrations: staged station
+// table refits, newest first.
+func (h *Handler) ListRecalibrations(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"proposals": h.recalibrate.Proposals()})
+}
+
+// RunRecalibration handles POST /admin/recalibrations: refits the station
+// tables now and stages the result.
+func (h *Handler) RunRecalibration(c *gin.Context) {
+	proposal, err := h.recalibrate.Recalibrate()
+	h.writeRecalibration(c, proposal, err)
+}
+
+// GetRecalibration handles GET /admin/recalibrations/:id.
+func (h *Handler) GetRecalibration(c *gin.Context) {
+	proposal, err := h.recalibrate.Proposal(c.Param("id"))
+	h.writeRecalibration(c, proposal, err)
+}
+
+// ApproveRecalibration handles POST /admin/recalibrations/:id/approve:
+// writes the proposal to the datum offset and station override tables.
+func (h *Handler) ApproveRecalibration(c *gin.Context) {
+	proposal, err := h.recalibrate.Approve(c.Param("id"))
+	h.writeRecalibration(c, proposal, err)
+}
+
+// RejectRecalibration handles POST /admin/recalibrations/:id/reject.
+func (h *Handler) RejectRecalibration(c *gin.Context) {
+	proposal, err := h.recalibrate.Reject(c.Param("id"))
+	h.writeRecalibration(c, proposal, err)
+}
+
+func (h *Handler) writeRecalibration(c *gin.Context, proposal *usecase.RecalibrationProposal, err error) {
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecase.ErrRecalibrationNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, usecase.ErrRecalibrationConflict):
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, proposal)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
+	"go.ngs.io/tides-api/pkg/store"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", adminAuth("secret"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := map[string]int{
+		"Bearer secret": http.StatusNoContent,
+		"secret":        http.StatusUnauthorized, // Without the scheme.
+		"Bearer other":  http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer ":       http.StatusUnauthorized,
+		"":              http.StatusUnauthorized,
+	}
+	for header, status := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != status {
+			t.Errorf("Authorization %q = %d, want %d", header, w.Code, status)
+		}
+	}
+}
+
+// observationsFunc adapts a function to usecase.ObservationHistory.
+type observationsFunc func(station string, start, end time.Time) ([]domain.TideLevel, error)
+
+func (f observationsFunc) Between(station string, start, end time.Time) ([]domain.TideLevel, error) {
+	return f(station, start, end)
+}
+
+// failingLoader fails to load constituents for any station or location.
+type failingLoader struct{}
+
+func (failingLoader) LoadForStation(string) ([]domain.ConstituentParam, error) {
+	return failingLoader{}.LoadForLocation(0, 0)
+}
+
+func (failingLoader) LoadForLocation(float64, float64) ([]domain.ConstituentParam, error) {
+	return nil, errors.New("disk read failed")
+}
+
+func TestEstimateDatumStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	// Observations 0.3 m above the M2 tide.
+	lat, lon, offset := 35.0, 139.0, 0.3
+	observed := func(string, time.Time, time.Time) ([]domain.TideLevel, error) {
+		levels, err := usecase.NewPredictionUseCase(nil, m2Loader{}, nil).PredictSeries(usecase.PredictionRequest{
+			Lat: &lat, Lon: &lon, Start: start, End: start.Add(48 * time.Hour), Interval: time.Hour, DatumOffsetM: &offset,
+		})
+		return levels, err
+	}
+	unreachable := func(string, time.Time, time.Time) ([]domain.TideLevel, error) {
+		return nil, errors.New("jma: 503 Service Unavailable")
+	}
+	valid := `{"station":"TK","lat":35,"lon":139,"start":"2025-06-01T00:00:00Z","end":"2025-06-03T00:00:00Z"}`
+
+	tests := []struct {
+		name         string
+		loader       store.ConstituentLoader
+		observations usecase.ObservationHistory
+		body         string
+		status       int
+	}{
+		{"estimate", m2Loader{}, observationsFunc(observed), valid, http.StatusOK},
+		{"no observation source", m2Loader{}, nil, valid, http.StatusServiceUnavailable},
+		{"unknown station without location", m2Loader{}, observationsFunc(observed), `{"station":"TK","start":"2025-06-01T00:00:00Z","end":"2025-06-03T00:00:00Z"}`, http.StatusBadRequest},
+		{"reversed period", m2Loader{}, observationsFunc(observed), `{"station":"TK","lat":35,"lon":139,"start":"2025-06-03T00:00:00Z","end":"2025-06-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"latitude out of range", m2Loader{}, observationsFunc(observed), `{"station":"TK","lat":95,"lon":139,"start":"2025-06-01T00:00:00Z","end":"2025-06-03T00:00:00Z"}`, http.StatusBadRequest},
+		{"observations unreachable", m2Loader{}, observationsFunc(unreachable), valid, http.StatusBadGateway},
+		{"constituents unreadable", failingLoader{}, observationsFunc(observed), valid, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := usecase.NewPredictionUseCase(nil, tt.loader, nil)
+			if tt.observations != nil {
+				uc.SetObservationHistory(tt.observations)
+			}
+			router := gin.New()
+			router.POST("/estimate-datum", NewHandler(Services{Prediction: uc}).EstimateDatum)
+			req := httptest.NewRequest(http.MethodPost, "/estimate-datum", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d %s, want %d", w.Code, w.Body, tt.status)
+			}
+			if tt.status == http.StatusOK && !strings.Contains(w.Body.String(), `"datum_offset_m":0.3`) {
+				t.Errorf("estimate = %s, want an offset of 0.3 m", w.Body)
+			}
+		})
+	}
+}
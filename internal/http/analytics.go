@@ -0,0 +1,71 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// defaultTopLocations is the number of geohash cells reported per origin.
+const defaultTopLocations = 10
+
+// usageMiddleware records every request in the usage analytics.
+func usageMiddleware(a *usecase.UsageAnalytics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		ev := usecase.UsageEvent{
+			Origin:   usageOrigin(c.Request),
+			Endpoint: c.Request.Method + " " + endpoint,
+			Status:   c.Writer.Status(),
+			Latency:  time.Since(start),
+		}
+		lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(c.Query("lon"), 64)
+		if latErr == nil && lonErr == nil && lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180 {
+			ev.HasLocation, ev.Lat, ev.Lon = true, lat, lon
+		}
+		a.Record(ev)
+	}
+}
+
+// usageOrigin labels a request by API key, then by Origin header host.
+// API keys are hashed so the report never exposes them.
+func usageOrigin(req *http.Request) string {
+	if key := req.Header.Get(apiKeyHeader); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:])[:12]
+	}
+	if origin := req.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			return "origin:" + u.Host
+		}
+	}
+	return "anonymous"
+}
+
+// GetUsageAnalytics handles GET /admin/analytics.
+func (h *Handler) GetUsageAnalytics(c *gin.Context) {
+	top := defaultTopLocations
+	if s := c.Query("top"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "top must be a positive integer"})
+			return
+		}
+		top = n
+	}
+	c.JSON(http.StatusOK, h.analytics.Report(top))
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request body limits. JSON bodies are decoded as they are read, so a
+// request never holds more than its limit in memory.
+const (
+	// maxJSONBodyBytes bounds the bodies of the JSON POST endpoints; the
+	// largest allowed (10000 heights or observations) take well under 1 MiB.
+	maxJSONBodyBytes = 2 << 20
+	// maxSnapshotBytes bounds restored state snapshots.
+	maxSnapshotBytes = 32 << 20
+	// maxWorkbookBytes bounds uploaded workbooks.
+	maxWorkbookBytes = 10 << 20
+)
+
+// Accepted request content types.
+const (
+	contentTypeJSON      = "application/json"
+	contentTypeMultipart = "multipart/form-data"
+)
+
+// limitBody rejects requests whose Content-Type is not mediaType with 415
+// and bounds the body to maxBytes; reads past it fail with
+// *http.MaxBytesError, reported as 413 by bindJSON and the upload handlers.
+// Requests without a body pass.
+func limitBody(maxBytes int64, mediaType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", maxBytes)})
+			return
+		}
+		if c.Request.ContentLength != 0 {
+			got, _, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+			if err != nil || !strings.EqualFold(got, mediaType) {
+				c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": fmt.Sprintf("Content-Type must be %s", mediaType)})
+				return
+			}
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		c.Next()
+	}
+}
+
+// bindJSON decodes and validates a JSON body into v. On failure it writes
+// the error response, 413 past the body limit or else 400 with message,
+// and returns false.
+func bindJSON(c *gin.Context, v any, message string) bool {
+	err := c.ShouldBindJSON(v)
+	if err == nil {
+		return true
+	}
+	writeBodyError(c, err, message)
+	return false
+}
+
+// writeBodyError writes the response for a body that could not be read:
+// 413 past the body limit, else 400 with message.
+func writeBodyError(c *gin.Context, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s: %v", message, err)})
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamed hides the length of a body, as for a chunked request.
+func streamed(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/json", io.NopCloser(io.MultiReader(strings.NewReader(body))))
+	req.ContentLength = -1
+	return req
+}
+
+func TestLimitBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var decoded struct{ Names []string }
+	router.POST("/json", limitBody(64, contentTypeJSON), func(c *gin.Context) {
+		if bindJSON(c, &decoded, "invalid request body") {
+			c.Status(http.StatusNoContent)
+		}
+	})
+	router.POST("/upload", limitBody(64, contentTypeMultipart), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	valid := `{"names":["tokyo","osaka"]}`
+	oversized := `{"names":["` + strings.Repeat("x", 64) + `"]}`
+	tests := []struct {
+		name        string
+		req         *http.Request
+		contentType string
+		status      int
+	}{
+		{"valid", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(valid)), "application/json", http.StatusNoContent},
+		{"valid with charset", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(valid)), "Application/JSON; charset=utf-8", http.StatusNoContent},
+		{"valid streamed", streamed(valid), "application/json", http.StatusNoContent},
+		{"announced oversized", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(oversized)), "application/json", http.StatusRequestEntityTooLarge},
+		{"streamed oversized", streamed(oversized), "application/json", http.StatusRequestEntityTooLarge},
+		{"malformed", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(`{"names":`)), "application/json", http.StatusBadRequest},
+		{"text", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(valid)), "text/plain", http.StatusUnsupportedMediaType},
+		{"no content type", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(valid)), "", http.StatusUnsupportedMediaType},
+		{"form for JSON", httptest.NewRequest(http.MethodPost, "/json", strings.NewReader(valid)), "multipart/form-data; boundary=x", http.StatusUnsupportedMediaType},
+		{"no body", httptest.NewRequest(http.MethodPost, "/upload", nil), "", http.StatusNoContent},
+		{"form", httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("--x--")), "multipart/form-data; boundary=x", http.StatusNoContent},
+		{"JSON for form", httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(valid)), "application/json", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded.Names = nil
+			if tt.contentType != "" {
+				tt.req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d %s, want %d", w.Code, w.Body, tt.status)
+			}
+			if tt.status == http.StatusNoContent && tt.req.URL.Path == "/json" && len(decoded.Names) != 2 {
+				t.Errorf("decoded %v", decoded.Names)
+			}
+		})
+	}
+}
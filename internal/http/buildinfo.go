@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// BuildInfo identifies the server build.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+}
+
+// featureFlags lists the optional capabilities of the server.
+type featureFlags struct {
+	usecase.Features
+	SurgeAlerts   bool `json:"surge_alerts"`
+	Archive       bool `json:"archive"`
+	Tenants       bool `json:"tenants"`
+	Recalibration bool `json:"recalibration"`
+	Shadow        bool `json:"shadow"`
+	Admin         bool `json:"admin"`
+	Docs          bool `json:"docs"`
+	Metrics       bool `json:"metrics"`
+}
+
+// versionResponse is the body of GET /v1/version.
+type versionResponse struct {
+	BuildInfo
+	GoVersion string                 `json:"go_version"`
+	Features  featureFlags           `json:"features"`
+	Data      usecase.DatasetSummary `json:"data"`
+}
+
+// GetVersion handles GET /v1/version: the build, the enabled features and
+// the dataset versions of the request's tenant, so that clients can adapt
+// (e.g., skip depth rendering without bathymetry).
+func (h *Handler) GetVersion(c *gin.Context) {
+	uc := h.prediction(c)
+	c.JSON(http.StatusOK, versionResponse{
+		BuildInfo: h.build,
+		GoVersion: runtime.Version(),
+		Features: featureFlags{
+			Features:      uc.Features(),
+			SurgeAlerts:   h.monitorUC != nil,
+			Archive:       h.archiveUC != nil,
+			Tenants:       h.tenants,
+			Recalibration: h.recalibrate != nil,
+			Shadow:        h.shadow != nil,
+			Admin:         h.admin,
+			Docs:          h.docs,
+			Metrics:       h.metrics,
+		},
+		Data: uc.Datasets(),
+	})
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// GetCotidalChart handles GET /v1/charts/cotidal: co-tidal and co-range
+// lines of a constituent over a bbox, as GeoJSON.
+func (h *Handler) GetCotidalChart(c *gin.Context) {
+	bbox, err := parseBBox(c.Query("bbox"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := usecase.ChartRequest{Constituent: c.Query("constituent"), BBox: bbox}
+	for _, p := range []struct {
+		name string
+		dst  *float64
+	}{
+		{"resolution", &req.ResolutionDeg},
+		{"phase_step", &req.PhaseStepDeg},
+		{"amplitude_step", &req.AmplitudeStepM},
+	} {
+		s := c.Query(p.name)
+		if s == "" {
+			continue
+		}
+		if *p.dst, err = strconv.ParseFloat(s, 64); err != nil || *p.dst <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s must be a positive number", p.name)})
+			return
+		}
+	}
+
+	response, err := h.prediction(c).CotidalChart(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAmphidromes handles GET /v1/charts/amphidromes: amphidromic points of
+// a constituent within a bbox, as GeoJSON.
+func (h *Handler) GetAmphidromes(c *gin.Context) {
+	bbox, err := parseBBox(c.Query("bbox"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := usecase.AmphidromeRequest{Constituent: c.Query("constituent"), BBox: bbox}
+	if s := c.Query("resolution"); s != "" {
+		if req.ResolutionDeg, err = strconv.ParseFloat(s, 64); err != nil || req.ResolutionDeg <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "resolution must be a positive number"})
+			return
+		}
+	}
+
+	response, err := h.prediction(c).Amphidromes(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/geo+json")
+	c.JSON(http.StatusOK, response)
+}
+
+// parseBBox parses a GeoJSON-ordered "minLon,minLat,maxLon,maxLat" box.
+func parseBBox(s string) (domain.BBox, error) {
+	if s == "" {
+		return domain.BBox{}, errors.New("bbox parameter is required (minLon,minLat,maxLon,maxLat)")
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return domain.BBox{}, fmt.Errorf("invalid bbox %q (expected minLon,minLat,maxLon,maxLat)", s)
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return domain.BBox{}, fmt.Errorf("invalid bbox %q: %w", s, err)
+		}
+		v[i] = f
+	}
+	b := domain.BBox{MinLon: v[0], MinLat: v[1], MaxLon: v[2], MaxLat: v[3]}
+	if err := b.Validate(); err != nil {
+		return domain.BBox{}, err
+	}
+	return b, nil
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// m2Loader returns a 1 m M2 tide for any station or location.
+type m2Loader struct{}
+
+func (m2Loader) LoadForStation(string) ([]domain.ConstituentParam, error) {
+	return m2Loader{}.LoadForLocation(0, 0)
+}
+
+func (m2Loader) LoadForLocation(float64, float64) ([]domain.ConstituentParam, error) {
+	speed, _ := domain.GetConstituentSpeed("M2")
+	return []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: speed}}, nil
+}
+
+// newTestRouter serves a prediction use case of m2Loader whose clock is
+// frozen at now.
+func newTestRouter(now time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewPredictionUseCase(m2Loader{}, m2Loader{}, nil)
+	uc.SetClock(domain.FixedClock{Time: now})
+	return SetupRouter(Services{Prediction: uc})
+}
+
+func TestFixedClock(t *testing.T) {
+	now := time.Date(2030, 6, 1, 12, 0, 30, 0, time.UTC)
+	router := newTestRouter(now)
+
+	t.Run("health", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var body struct{ Time string }
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Time != "2030-06-01T12:00:30Z" {
+			t.Errorf("health time = %q (%v), want the frozen time", body.Time, err)
+		}
+	})
+
+	t.Run("default range", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/tides/predictions?lat=35&lon=139&interval=1h", nil))
+		var body usecase.PredictionResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("predictions: %d %s", w.Code, w.Body)
+		}
+		// The frozen day in Japan.
+		if n := len(body.Predictions); n < 24 || body.Predictions[0].Time != "2030-06-01T00:00:00+09:00" {
+			t.Errorf("predictions from %v (%d points), want the day of 2030-06-01 JST", body.Predictions[0].Time, n)
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		server := httptest.NewServer(router)
+		defer server.Close()
+		resp, err := http.Get(server.URL + "/v1/tides/stream?lat=35&lon=139&timezone=utc")
+		if err != nil {
+			t.Fatalf("stream: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			var point usecase.PredictionPoint
+			if err := json.Unmarshal([]byte(data), &point); err != nil || point.Time != "2030-06-01T12:00:30Z" {
+				t.Errorf("first event %s (%v), want at the frozen time", data, err)
+			}
+			return
+		}
+		t.Fatalf("no stream event: %v", scanner.Err())
+	})
+}
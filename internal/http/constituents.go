@@ -0,0 +1,216 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/i18n"
+	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// defaultConstituentPage and maxConstituentPage bound the limit of a
+	// constituent list page.
+	defaultConstituentPage = 50
+	maxConstituentPage     = 200
+)
+
+// ConstituentListResponse is the response for listing constituents.
+type ConstituentListResponse struct {
+	Name          string            `json:"name"`
+	SpeedDegPerHr float64           `json:"speed_deg_per_hr"`
+	FrequencyCPD  float64           `json:"frequency_cpd"`
+	PeriodHours   float64           `json:"period_h"`
+	Type          string            `json:"type"`
+	Description   string            `json:"description,omitempty"`
+	Links         map[string]string `json:"links"`
+}
+
+// constituentFilter selects constituents by type and frequency.
+type constituentFilter struct {
+	types          []domain.ConstituentType // Any type when empty.
+	minCPD, maxCPD *float64
+}
+
+func (f constituentFilter) match(c domain.Constituent) bool {
+	if len(f.types) > 0 && !slices.Contains(f.types, c.Type()) {
+		return false
+	}
+	cpd := c.FrequencyCPD()
+	return (f.minCPD == nil || cpd >= *f.minCPD) && (f.maxCPD == nil || cpd <= *f.maxCPD)
+}
+
+// parseConstituentFilter reads the type (comma-separated), min_cpd and
+// max_cpd query parameters.
+func parseConstituentFilter(c *gin.Context) (constituentFilter, error) {
+	var f constituentFilter
+	if v := c.Query("type"); v != "" {
+		for _, name := range strings.Split(v, ",") {
+			t := domain.ConstituentType(strings.ToLower(strings.TrimSpace(name)))
+			if !slices.Contains(domain.ConstituentTypes, t) {
+				return f, fmt.Errorf("invalid type %q (expected one of %v)", name, domain.ConstituentTypes)
+			}
+			f.types = append(f.types, t)
+		}
+	}
+	for name, dst := range map[string]**float64{"min_cpd": &f.minCPD, "max_cpd": &f.maxCPD} {
+		if v := c.Query(name); v != "" {
+			x, err := strconv.ParseFloat(v, 64)
+			if err != nil || x < 0 {
+				return f, fmt.Errorf("invalid %s: must be a non-negative number", name)
+			}
+			*dst = &x
+		}
+	}
+	if f.minCPD != nil && f.maxCPD != nil && *f.minCPD > *f.maxCPD {
+		return f, errors.New("min_cpd must not exceed max_cpd")
+	}
+	return f, nil
+}
+
+// pageParams reads the limit and offset query parameters.
+func pageParams(c *gin.Context) (limit, offset int, err error) {
+	limit = defaultConstituentPage
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > maxConstituentPage {
+			return 0, 0, fmt.Errorf("limit must be an integer from 1 to %d", maxConstituentPage)
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// pageLink returns the URL of the request with the given page.
+func pageLink(c *gin.Context, offset, limit int) string {
+	q := c.Request.URL.Query()
+	q.Set("offset", strconv.Itoa(offset))
+	q.Set("limit", strconv.Itoa(limit))
+	u := url.URL{Path: c.Request.URL.Path, RawQuery: q.Encode()}
+	return u.String()
+}
+
+// GetConstituentsList handles GET /v1/constituents: the constituents in
+// order of speed, optionally filtered by type and frequency range, a page
+// at a time. Pages link to their neighbors and each constituent to its
+// grid coverage. Descriptions are localized according to the
+// Accept-Language header.
+func (h *Handler) GetConstituentsList(c *gin.Context) {
+	filter, err := parseConstituentFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	limit, offset, err := pageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	lang := requestLanguage(c)
+
+	constituents := slices.DeleteFunc(domain.GetAllConstituents(), func(c domain.Constituent) bool {
+		return !filter.match(c)
+	})
+	total := len(constituents)
+	page := constituents[min(offset, total):min(offset+limit, total)]
+
+	response := make([]ConstituentListResponse, len(page))
+	for i, con := range page {
+		response[i] = ConstituentListResponse{
+			Name:          con.Name,
+			SpeedDegPerHr: con.SpeedDegPerHr,
+			FrequencyCPD:  con.FrequencyCPD(),
+			PeriodHours:   con.PeriodHours(),
+			Type:          string(con.Type()),
+			Description:   i18n.ConstituentDescription(lang, con.Name),
+			Links:         map[string]string{"coverage": "/v1/constituents/" + url.PathEscape(con.Name) + "/coverage"},
+		}
+	}
+
+	links := gin.H{"self": pageLink(c, offset, limit)}
+	if offset+limit < total {
+		links["next"] = pageLink(c, offset+limit, limit)
+	}
+	if offset > 0 {
+		links["prev"] = pageLink(c, max(offset-limit, 0), limit)
+	}
+
+	c.Header("Content-Language", lang)
+	c.JSON(http.StatusOK, gin.H{
+		"constituents": response,
+		"count":        len(response),
+		"total":        total,
+		"offset":       offset,
+		"limit":        limit,
+		"links":        links,
+	})
+}
+
+// GetConstituentCoverage handles GET /v1/constituents/:name/coverage: the
+// extent of the grid the constituent is interpolated from.
+func (h *Handler) GetConstituentCoverage(c *gin.Context) {
+	name := c.Param("name")
+	coverage, err := h.prediction(c).ConstituentCoverage(name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrConstituentUnavailable) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	canonical, _ := domain.CanonicalConstituentName(name)
+	c.JSON(http.StatusOK, gin.H{"constituent": canonical, "coverage": coverage})
+}
+
+// GetConstituentGrid handles GET /v1/constituents/:name/grid: the
+// amplitude and phase of a constituent at the dataset grid nodes within
+// bbox, every stride-th node, as JSON arrays or (format=geojson) GeoJSON
+// Points.
+func (h *Handler) GetConstituentGrid(c *gin.Context) {
+	bbox, err := parseBBox(c.Query("bbox"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := usecase.GridPreviewRequest{Constituent: c.Param("name"), BBox: bbox}
+	if s := c.Query("stride"); s != "" {
+		if req.Stride, err = strconv.Atoi(s); err != nil || req.Stride < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "stride must be a positive integer"})
+			return
+		}
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "geojson" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or geojson"})
+		return
+	}
+
+	response, err := h.prediction(c).ConstituentGrid(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrConstituentUnavailable) || errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "geojson" {
+		c.Header("Content-Type", "application/geo+json")
+		c.JSON(http.StatusOK, response.FeatureCollection())
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
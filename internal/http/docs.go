@@ -0,0 +1,47 @@
+package http
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPISpec describes the public endpoints (admin endpoints are left out).
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// swaggerUIVersion is the exact swagger-ui-dist release the explorer loads,
+// so that new releases on the CDN do not change it.
+const swaggerUIVersion = "5.17.14"
+
+// docsPage loads Swagger UI from a CDN and points it at the spec, so the
+// server ships no UI assets.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Tide API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css" crossorigin="anonymous">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin="anonymous"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui", tryItOutEnabled: true});
+  </script>
+</body>
+</html>
+`
+
+// GetOpenAPI handles GET /v1/openapi.json, and GET /openapi.json with docs
+// enabled: the OpenAPI 3 description of the API.
+func (h *Handler) GetOpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", openAPISpec)
+}
+
+// GetDocs handles GET /docs: an interactive explorer of the OpenAPI spec.
+func (h *Handler) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
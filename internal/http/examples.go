@@ -0,0 +1,26 @@
+package http
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// examplePairs are the canonical request/response pairs of GET
+// /v1/examples. They are generated by TestExamples from the requests in
+// examples_test.go (go test ./internal/http -run TestExamples -update), so
+// they are those of the version built and cost the server nothing to serve.
+//
+//go:embed examples.json
+var examplePairs []byte
+
+// GetExamples handles GET /v1/examples: canonical request/response pairs
+// of this version, for client contract tests.
+func (h *Handler) GetExamples(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version":  h.build.Version,
+		"examples": json.RawMessage(examplePairs),
+	})
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite examples.json from the example requests")
+
+// example is a canonical request to the API.
+type example struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	Query       string          `json:"query,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// exampleResponse is a response to an example request.
+type exampleResponse struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// examplePair is an example request with its response.
+type examplePair struct {
+	example
+	Response exampleResponse `json:"response"`
+}
+
+// exampleRequests are the canonical requests of GET /v1/examples. They use
+// client-supplied constituents or no dataset at all, so that their
+// responses depend only on the server version, not on its data.
+var exampleRequests = []example{
+	{
+		Name:        "custom-predictions",
+		Description: "Predictions and extrema for client-supplied constituents.",
+		Method:      http.MethodPost,
+		Path:        "/v1/tides/predictions",
+		Query:       "decimals=3",
+		Body: json.RawMessage(`{"constituents":[{"name":"M2","amplitude_m":0.62,"phase_deg":145.0},{"name":"K1","amplitude_m":0.18,"phase_deg":30.0}],` +
+			`"start":"2025-10-21T00:00:00Z","end":"2025-10-21T06:00:00Z","interval":"1h"}`),
+	},
+	{
+		Name:        "custom-predictions-fields",
+		Description: "Predictions in feet and JST with only the selected fields.",
+		Method:      http.MethodPost,
+		Path:        "/v1/tides/predictions",
+		Query:       "units=ft&decimals=2&fields=source,datum,predictions",
+		Body: json.RawMessage(`{"constituents":[{"name":"M2","amplitude_m":0.62,"phase_deg":145.0}],` +
+			`"start":"2025-10-21T00:00:00Z","end":"2025-10-21T03:00:00Z","interval":"1h","timezone":"JST"}`),
+	},
+	{
+		Name:        "constituents",
+		Description: "The first page of the constituent list.",
+		Method:      http.MethodGet,
+		Path:        "/v1/constituents",
+		Query:       "limit=3",
+	},
+	{
+		Name:        "invalid-interval",
+		Description: "A request rejected with the error format.",
+		Method:      http.MethodPost,
+		Path:        "/v1/tides/predictions",
+		Body: json.RawMessage(`{"constituents":[{"name":"M2","amplitude_m":0.62,"phase_deg":145.0}],` +
+			`"start":"2025-10-21T00:00:00Z","end":"2025-10-22T00:00:00Z","interval":"7x"}`),
+	},
+	{
+		Name:        "invalid-latitude",
+		Description: "A query rejected with the error format.",
+		Method:      http.MethodGet,
+		Path:        "/v1/tides/predictions",
+		Query:       "lat=91&lon=139.8",
+	},
+}
+
+// TestExamples checks that examples.json holds the responses of this
+// version to the example requests; -update rewrites it.
+func TestExamples(t *testing.T) {
+	router := newTestRouter(time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC))
+	pairs := make([]examplePair, len(exampleRequests))
+	for i, e := range exampleRequests {
+		target := e.Path
+		if e.Query != "" {
+			target += "?" + e.Query
+		}
+		req := httptest.NewRequest(e.Method, target, bytes.NewReader(e.Body))
+		if e.Body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if !json.Valid(w.Body.Bytes()) {
+			t.Fatalf("%s: response is not JSON: %s", e.Name, w.Body)
+		}
+		pairs[i] = examplePair{example: e, Response: exampleResponse{Status: w.Code, Body: w.Body.Bytes()}}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pairs); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.Bytes()
+
+	if *update {
+		if err := os.WriteFile("examples.json", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if !bytes.Equal(got, examplePairs) {
+		t.Error("examples.json is out of date; run go test ./internal/http -run TestExamples -update")
+	}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// fieldTree is a set of dotted JSON field paths, e.g.
+// "predictions.time,extrema". A nil subtree selects the whole value; paths
+// through arrays apply to every element.
+type fieldTree map[string]fieldTree
+
+// parseFieldTree parses a comma-separated path list. An empty list yields
+// a nil tree.
+func parseFieldTree(s string) (fieldTree, error) {
+	if s == "" {
+		return nil, nil
+	}
+	tree := fieldTree{}
+	for _, path := range strings.Split(s, ",") {
+		names := strings.Split(strings.TrimSpace(path), ".")
+		node := tree
+		for i, name := range names {
+			if name == "" {
+				return nil, fmt.Errorf("empty name in %q", path)
+			}
+			sub, seen := node[name]
+			if seen && sub == nil {
+				break // An ancestor already selects the whole value.
+			}
+			if i == len(names)-1 {
+				node[name] = nil
+				break
+			}
+			if sub == nil {
+				sub = fieldTree{}
+				node[name] = sub
+			}
+			node = sub
+		}
+	}
+	return tree, nil
+}
+
+// keep returns v with only the fields in t.
+func (t fieldTree) keep(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for name, sub := range t {
+			x, ok := v[name]
+			if !ok {
+				continue
+			}
+			if sub != nil {
+				x = sub.keep(x)
+			}
+			out[name] = x
+		}
+		return out
+	case []any:
+		for i := range v {
+			v[i] = t.keep(v[i])
+		}
+	}
+	return v
+}
+
+// drop returns v without the fields in t.
+func (t fieldTree) drop(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, sub := range t {
+			if sub == nil {
+				delete(v, name)
+			} else if x, ok := v[name]; ok {
+				v[name] = sub.drop(x)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = t.drop(v[i])
+		}
+	}
+	return v
+}
+
+// toJSONValue converts v to its generic JSON form, keeping numbers as
+// written.
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode response: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseFieldTree(t *testing.T) {
+	tests := []struct {
+		in   string
+		want fieldTree
+	}{
+		{"", nil},
+		{"datum", fieldTree{"datum": nil}},
+		{"predictions.time, extrema", fieldTree{"predictions": {"time": nil}, "extrema": nil}},
+		{"extrema.highs.time,extrema.lows", fieldTree{"extrema": {"highs": {"time": nil}, "lows": nil}}},
+		// A parent path selects the whole value, in either order.
+		{"predictions.time,predictions", fieldTree{"predictions": nil}},
+		{"predictions,predictions.time", fieldTree{"predictions": nil}},
+	}
+	for _, tt := range tests {
+		got, err := parseFieldTree(tt.in)
+		if err != nil {
+			t.Errorf("parseFieldTree(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseFieldTree(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"datum,", "predictions..time", ".datum", "datum,,extrema"} {
+		if _, err := parseFieldTree(in); err == nil {
+			t.Errorf("parseFieldTree(%q) accepted", in)
+		}
+	}
+}
+
+const fieldsDoc = `{
+  "datum": "MSL",
+  "meta": {"model": "harmonic_v0", "dataset": "fes2014"},
+  "predictions": [
+    {"time": "2025-10-21T00:00:00Z", "height_m": 0.12},
+    {"time": "2025-10-21T01:00:00Z", "height_m": 0.34}
+  ],
+  "extrema": {"highs": [{"time": "2025-10-21T05:00:00Z", "height_m": 0.6}], "lows": []}
+}`
+
+func TestFieldTreeKeepDrop(t *testing.T) {
+	tests := []struct {
+		name, fields, exclude, want string
+	}{
+		{
+			name:   "nested through arrays",
+			fields: "datum,predictions.height_m,extrema.highs.time",
+			want:   `{"datum":"MSL","predictions":[{"height_m":0.12},{"height_m":0.34}],"extrema":{"highs":[{"time":"2025-10-21T05:00:00Z"}]}}`,
+		},
+		{
+			name:   "unknown fields",
+			fields: "datum,nope,meta.nope,predictions.nope",
+			want:   `{"datum":"MSL","meta":{},"predictions":[{},{}]}`,
+		},
+		{
+			name:    "exclude nested",
+			exclude: "meta,predictions.time,extrema.lows",
+			want:    `{"datum":"MSL","predictions":[{"height_m":0.12},{"height_m":0.34}],"extrema":{"highs":[{"time":"2025-10-21T05:00:00Z","height_m":0.6}]}}`,
+		},
+		{
+			name:    "exclude unknown",
+			exclude: "nope,meta.nope,predictions.nope.deeper",
+			want:    fieldsDoc,
+		},
+		{
+			name:    "fields then exclude",
+			fields:  "meta,predictions",
+			exclude: "meta.dataset,predictions.time",
+			want:    `{"meta":{"model":"harmonic_v0"},"predictions":[{"height_m":0.12},{"height_m":0.34}]}`,
+		},
+		{
+			name:    "exclude a selected parent",
+			fields:  "datum,meta.model",
+			exclude: "meta",
+			want:    `{"datum":"MSL"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc any
+			if err := json.Unmarshal([]byte(fieldsDoc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			fields, err := parseFieldTree(tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			exclude, err := parseFieldTree(tt.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// As responseOptions.apply does.
+			if fields != nil {
+				doc = fields.keep(doc)
+			}
+			if exclude != nil {
+				doc = exclude.drop(doc)
+			}
+			var want any
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(doc, want) {
+				got, _ := json.Marshal(doc)
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldsQuery(t *testing.T) {
+	router := newTestRouter(time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC))
+	base := "/v1/tides/predictions?lat=35&lon=139&interval=6h"
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+"&fields=datum,predictions&exclude=predictions.height_m", nil))
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("fields and exclude: %d %s", w.Code, w.Body)
+	}
+	predictions, _ := body["predictions"].([]any)
+	if len(body) != 2 || body["datum"] != "MSL" || len(predictions) == 0 {
+		t.Fatalf("fields and exclude: %s", w.Body)
+	}
+	if point, _ := predictions[0].(map[string]any); len(point) != 1 || point["time"] == nil {
+		t.Errorf("prediction = %v, want its time only", predictions[0])
+	}
+
+	for _, query := range []string{"&fields=predictions..time", "&exclude=,datum", "&fields=datum&format=csv"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s = %d %s, want 400", query, w.Code, w.Body)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// Output formats of prediction responses.
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// Media types of the output formats.
+const (
+	contentTypeCSV    = "text/csv; charset=utf-8"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// Point kinds of CSV and NDJSON rows.
+const (
+	pointPrediction = "prediction"
+	pointHigh       = "high"
+	pointLow        = "low"
+)
+
+// parseOutputFormat selects the output format from the format query
+// parameter or, without one, the supported media type of the Accept header
+// with the highest quality, the first of equals; JSON by default. Media
+// types with q=0 are not acceptable, and */* stands for JSON.
+func parseOutputFormat(c *gin.Context) (string, error) {
+	if format := c.Query("format"); format != "" {
+		switch format = strings.ToLower(format); format {
+		case formatJSON, formatCSV, formatNDJSON:
+			return format, nil
+		default:
+			return "", fmt.Errorf("invalid format %q (expected json, csv or ndjson)", format)
+		}
+	}
+	best, bestQ := formatJSON, 0.0
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		var format string
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			format = formatJSON
+		case "text/csv":
+			format = formatCSV
+		case "application/x-ndjson", "application/ndjson":
+			format = formatNDJSON
+		default:
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	return best, nil
+}
+
+// pointRow is a prediction or extremum as a CSV or NDJSON row.
+type pointRow struct {
+	Type string `json:"type"` // pointPrediction, pointHigh or pointLow.
+	usecase.PredictionPoint
+}
+
+// pointRows lists the predictions of a response followed by its extrema
+// in time order.
+func pointRows(r *usecase.PredictionResponse) []pointRow {
+	rows := make([]pointRow, 0, len(r.Predictions)+len(r.Extrema.Highs)+len(r.Extrema.Lows))
+	for _, p := range r.Predictions {
+		rows = append(rows, pointRow{Type: pointPrediction, PredictionPoint: p})
+	}
+	var extrema []pointRow
+	for _, p := range r.Extrema.Highs {
+		extrema = append(extrema, pointRow{Type: pointHigh, PredictionPoint: p})
+	}
+	for _, p := range r.Extrema.Lows {
+		extrema = append(extrema, pointRow{Type: pointLow, PredictionPoint: p})
+	}
+	// Times share the response's offset, so they sort as strings.
+	slices.SortStableFunc(extrema, func(a, b pointRow) int { return strings.Compare(a.Time, b.Time) })
+	return append(rows, extrema...)
+}
+
+// setResponseHeaders describes a CSV or NDJSON response, whose rows carry
+// no metadata, in headers.
+func setResponseHeaders(c *gin.Context, r *usecase.PredictionResponse) {
+	c.Header("X-Tide-Source", r.Source)
+	c.Header("X-Tide-Datum", r.Datum)
+	c.Header("X-Tide-Timezone", r.Timezone)
+	c.Header("X-Tide-Fingerprint", r.Fingerprint)
+	if units := r.Meta["units"]; units != "" {
+		c.Header("X-Tide-Units", units)
+	}
+}
+
+// writeCSV writes the points of a response as CSV with a header row.
+// Depth and ensemble range columns are present when any point has them.
+func writeCSV(c *gin.Context, r *usecase.PredictionResponse) {
+	rows := pointRows(r)
+	var depth, spread bool
+	for _, row := range rows {
+		depth = depth || row.DepthM != nil
+		spread = spread || row.MinM != nil
+	}
+	header := []string{"time", "type", "height_m"}
+	if depth {
+		header = append(header, "depth_m")
+	}
+	if spread {
+		header = append(header, "min_m", "max_m")
+	}
+
+	setResponseHeaders(c, r)
+	c.Header("Content-Type", contentTypeCSV)
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	for _, row := range rows {
+		record := []string{row.Time, row.Type, formatCSVFloat(&row.HeightM)}
+		if depth {
+			record = append(record, formatCSVFloat(row.DepthM))
+		}
+		if spread {
+			record = append(record, formatCSVFloat(row.MinM), formatCSVFloat(row.MaxM))
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+}
+
+// writeNDJSON writes the points of a response as one JSON object per line.
+func writeNDJSON(c *gin.Context, r *usecase.PredictionResponse) {
+	setResponseHeaders(c, r)
+	c.Header("Content-Type", contentTypeNDJSON)
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	for _, row := range pointRows(r) {
+		if err := enc.Encode(row); err != nil {
+			return
+		}
+	}
+}
+
+// formatCSVFloat formats an optional value, or "" when absent.
+func formatCSVFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query, accept, want string
+	}{
+		{"", "", formatJSON},
+		{"format=CSV", "application/json", formatCSV},
+		{"format=ndjson", "", formatNDJSON},
+		{"", "text/csv", formatCSV},
+		{"", "application/ndjson", formatNDJSON},
+		{"", "text/html, text/csv", formatCSV},
+		{"", "text/csv;q=0.1, application/json", formatJSON},
+		{"", "application/json;q=0.5, application/x-ndjson;q=0.8", formatNDJSON},
+		{"", "text/csv;q=0.9, application/x-ndjson;q=0.9", formatCSV},
+		{"", "text/csv;q=0.5, */*", formatJSON},
+		{"", "text/csv;q=0", formatJSON},
+		{"", "text/csv;q=0, application/x-ndjson;q=0.1", formatNDJSON},
+		{"", "text/csv;q=high, application/x-ndjson;q=0.1", formatNDJSON},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+		if tt.accept != "" {
+			c.Request.Header.Set("Accept", tt.accept)
+		}
+		got, err := parseOutputFormat(c)
+		if err != nil || got != tt.want {
+			t.Errorf("format %q, Accept %q: %q (%v), want %q", tt.query, tt.accept, got, err, tt.want)
+		}
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?format=xml", nil)
+	if _, err := parseOutputFormat(c); err == nil {
+		t.Error("format=xml accepted")
+	}
+}
+
+func TestWritePoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f := func(v float64) *float64 { return &v }
+	plain := &usecase.PredictionResponse{
+		Source:   "fes2014",
+		Datum:    "MSL",
+		Timezone: "+09:00",
+		Predictions: []usecase.PredictionPoint{
+			{Time: "2025-10-21T00:00:00+09:00", HeightM: 0.1},
+			{Time: "2025-10-21T06:00:00+09:00", HeightM: -0.2},
+		},
+		Extrema: usecase.ExtremaResponse{
+			Highs: []usecase.PredictionPoint{{Time: "2025-10-21T05:00:00+09:00", HeightM: 0.6}},
+			Lows:  []usecase.PredictionPoint{{Time: "2025-10-21T01:00:00+09:00", HeightM: -0.5}},
+		},
+		Meta:        map[string]string{"units": "m"},
+		Fingerprint: "abc",
+	}
+	deep := &usecase.PredictionResponse{
+		Source:   "csv",
+		Datum:    "LAT",
+		Timezone: "Z",
+		Predictions: []usecase.PredictionPoint{
+			{Time: "2025-10-21T00:00:00Z", HeightM: 1, DepthM: f(5.5), MinM: f(0.9), MaxM: f(1.2)},
+		},
+		Extrema:     usecase.ExtremaResponse{Lows: []usecase.PredictionPoint{{Time: "2025-10-21T03:00:00Z", HeightM: 0.5}}},
+		Fingerprint: "def",
+	}
+
+	tests := []struct {
+		name        string
+		write       func(*gin.Context, *usecase.PredictionResponse)
+		response    *usecase.PredictionResponse
+		contentType string
+		body        string
+		headers     map[string]string
+	}{
+		{
+			name:        "CSV predictions then extrema in time order",
+			write:       writeCSV,
+			response:    plain,
+			contentType: contentTypeCSV,
+			body: "time,type,height_m\n" +
+				"2025-10-21T00:00:00+09:00,prediction,0.1\n" +
+				"2025-10-21T06:00:00+09:00,prediction,-0.2\n" +
+				"2025-10-21T01:00:00+09:00,low,-0.5\n" +
+				"2025-10-21T05:00:00+09:00,high,0.6\n",
+			headers: map[string]string{"X-Tide-Source": "fes2014", "X-Tide-Datum": "MSL", "X-Tide-Timezone": "+09:00", "X-Tide-Fingerprint": "abc", "X-Tide-Units": "m"},
+		},
+		{
+			name:        "CSV depth and range columns",
+			write:       writeCSV,
+			response:    deep,
+			contentType: contentTypeCSV,
+			body: "time,type,height_m,depth_m,min_m,max_m\n" +
+				"2025-10-21T00:00:00Z,prediction,1,5.5,0.9,1.2\n" +
+				"2025-10-21T03:00:00Z,low,0.5,,,\n",
+			headers: map[string]string{"X-Tide-Source": "csv", "X-Tide-Datum": "LAT", "X-Tide-Timezone": "Z", "X-Tide-Fingerprint": "def", "X-Tide-Units": ""},
+		},
+		{
+			name:        "NDJSON",
+			write:       writeNDJSON,
+			response:    plain,
+			contentType: contentTypeNDJSON,
+			body: `{"type":"prediction","time":"2025-10-21T00:00:00+09:00","height_m":0.1}` + "\n" +
+				`{"type":"prediction","time":"2025-10-21T06:00:00+09:00","height_m":-0.2}` + "\n" +
+				`{"type":"low","time":"2025-10-21T01:00:00+09:00","height_m":-0.5}` + "\n" +
+				`{"type":"high","time":"2025-10-21T05:00:00+09:00","height_m":0.6}` + "\n",
+			headers: map[string]string{"X-Tide-Source": "fes2014", "X-Tide-Fingerprint": "abc", "X-Tide-Units": "m"},
+		},
+		{
+			name:        "NDJSON depth and range fields",
+			write:       writeNDJSON,
+			response:    deep,
+			contentType: contentTypeNDJSON,
+			body: `{"type":"prediction","time":"2025-10-21T00:00:00Z","height_m":1,"depth_m":5.5,"min_m":0.9,"max_m":1.2}` + "\n" +
+				`{"type":"low","time":"2025-10-21T03:00:00Z","height_m":0.5}` + "\n",
+			headers: map[string]string{"X-Tide-Datum": "LAT", "X-Tide-Timezone": "Z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			tt.write(c, tt.response)
+			if w.Code != http.StatusOK || w.Header().Get("Content-Type") != tt.contentType {
+				t.Errorf("status %d, Content-Type %q, want 200 %q", w.Code, w.Header().Get("Content-Type"), tt.contentType)
+			}
+			if w.Body.String() != tt.body {
+				t.Errorf("body:\n%s\nwant:\n%s", w.Body, tt.body)
+			}
+			for name, want := range tt.headers {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPredictionsAccept(t *testing.T) {
+	router := newTestRouter(time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct{ accept, contentType string }{
+		{"text/csv", contentTypeCSV},
+		{"text/csv;q=0.1, application/json", "application/json; charset=utf-8"},
+		{"application/json;q=0, application/x-ndjson", contentTypeNDJSON},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/v1/tides/predictions?lat=35&lon=139&interval=6h", nil)
+		req.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("Accept %q: %d %q, want %q", tt.accept, w.Code, w.Header().Get("Content-Type"), tt.contentType)
+		}
+		if tt.contentType != "application/json; charset=utf-8" && w.Header().Get("X-Tide-Fingerprint") == "" {
+			t.Errorf("Accept %q: no X-Tide-Fingerprint", tt.accept)
+		}
+	}
+}
@@ -2,65 +2,165 @@
 package http
 
 import (
-    "fmt"
-    "net/http"
-    "strconv"
-    "time"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
-	"go.ngs.io/tides-api/internal/domain"
-    "go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/internal/i18n"
+	"go.ngs.io/tides-api/internal/usecase"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
 // Handler handles HTTP requests for tide predictions.
 type Handler struct {
 	predictionUC *usecase.PredictionUseCase
+	monitorUC    *usecase.MonitorUseCase
+	archiveUC    *usecase.ArchiveUseCase
+	analytics    *usecase.UsageAnalytics
+	recalibrate  *usecase.RecalibrationUseCase
+	shadow       *usecase.ShadowEvaluator
+	scheduler    *Scheduler
+	build        BuildInfo
+	tenants      bool // Datasets are scoped per tenant.
+	admin        bool // Admin endpoints are enabled.
+	docs         bool // The API explorer is served.
+	metrics      bool // Prometheus metrics are served.
 }
 
 // NewHandler creates a new HTTP handler.
-func NewHandler(predictionUC *usecase.PredictionUseCase) *Handler {
+func NewHandler(services Services) *Handler {
 	return &Handler{
-		predictionUC: predictionUC,
+		predictionUC: services.Prediction,
+		monitorUC:    services.Monitor,
+		archiveUC:    services.Archive,
+		analytics:    services.Analytics,
+		recalibrate:  services.Recalibration,
+		shadow:       services.Shadow,
+		scheduler:    services.Scheduler,
+		build:        services.Build,
+		tenants:      services.Tenants != nil,
 	}
 }
 
 // GetPredictions handles GET /v1/tides/predictions.
 func (h *Handler) GetPredictions(c *gin.Context) {
-    // Parse query parameters.
-    latStr := c.Query("lat")
-    lonStr := c.Query("lon")
-    stationID := c.Query("station_id")
-    startStr := c.Query("start")
-    endStr := c.Query("end")
-    intervalStr := c.Query("interval")
-    datum := c.Query("datum")
-    source := c.Query("source")
-    timezone := c.Query("timezone") // "utc" (default) or "jst".
-    datumOffsetStr := c.Query("datum_offset_m")
-    phaseConv := c.Query("phase_convention") // "fes_greenwich" (default) or "vu"
+	req, err := parsePredictionRequest(c, h.prediction(c).Clock().Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts, err := parseResponseOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Language = requestLanguage(c)
+	c.Header("Content-Language", req.Language)
+
+	// Execute use case.
+	response, err := h.prediction(c).Execute(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	opts.write(c, response)
+}
+
+// parsePredictionRequest reads the location, time range and output
+// options shared by the prediction query endpoints. Relative bounds are
+// resolved from now; without a time range, the day of now at the location
+// is predicted.
+//
+//nolint:gocyclo // Sequential parameter parsing with defaults.
+func parsePredictionRequest(c *gin.Context, now time.Time) (usecase.PredictionRequest, error) {
+	req, err := parsePredictionTarget(c)
+	if err != nil {
+		return req, err
+	}
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+	intervalStr := c.Query("interval")
+	timezone := req.Timezone
+
+	// Parse time range, resolved in the requested timezone, else the one
+	// of the coordinates. Station queries need a start, end or days.
+	daysStr := c.Query("days")
+	if startStr == "" && endStr == "" && daysStr == "" && (req.Lat == nil || req.Lon == nil) {
+		return req, errors.New("start parameter is required")
+	}
+	loc := requestedZone(timezone)
+	if timezone == "" && req.Lat != nil && req.Lon != nil {
+		loc, _ = resolveTimezoneForLatLon(*req.Lat, *req.Lon)
+	}
+	start, end, err := resolveTimeRange(startStr, endStr, daysStr, now, loc)
+	if err != nil {
+		return req, err
+	}
+	req.Start, req.End = start, end
+
+	// If timezone not provided but lat/lon present, set output TZ based on coordinates (always-on).
+	if req.Timezone == "" && req.Lat != nil && req.Lon != nil {
+		_, tzCode := resolveTimezoneForLatLon(*req.Lat, *req.Lon)
+		req.Timezone = tzCode
+	}
+
+	// Parse interval (default: 30m for better readability).
+	if intervalStr == "" {
+		intervalStr = "30m"
+	}
+
+	interval, err := parseInterval(intervalStr)
+	if err != nil {
+		return req, err
+	}
+	req.Interval = interval
+	return req, nil
+}
+
+// parsePredictionTarget parses the query parameters of a prediction other
+// than its time range and interval: the location or station and the
+// corrections. Timezone is left as given.
+func parsePredictionTarget(c *gin.Context) (usecase.PredictionRequest, error) {
+	// Parse query parameters.
+	latStr := c.Query("lat")
+	lonStr := c.Query("lon")
+	stationID := c.Query("station_id")
+	datum := c.Query("datum")
+	source := c.Query("source")
+	timezone := c.Query("timezone") // "utc" (default) or "jst".
+	datumOffsetStr := c.Query("datum_offset_m")
+	phaseConv := c.Query("phase_convention") // "fes_greenwich" (default) or "vu"
 
 	// Build request.
-    req := usecase.PredictionRequest{
-        Datum:    datum,
-        Source:   source,
-        Timezone: timezone,
-    }
-    if phaseConv != "" {
-        req.PhaseConvention = phaseConv
-    }
+	req := usecase.PredictionRequest{
+		Datum:    datum,
+		Source:   source,
+		Timezone: timezone,
+	}
+	if phaseConv != "" {
+		req.PhaseConvention = phaseConv
+	}
 
 	// Parse lat/lon.
 	if latStr != "" && lonStr != "" {
 		lat, err := strconv.ParseFloat(latStr, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid latitude: %v", err)})
-			return
+			return req, fmt.Errorf("invalid latitude: %w", err)
 		}
 		lon, err := strconv.ParseFloat(lonStr, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid longitude: %v", err)})
-			return
+			return req, fmt.Errorf("invalid longitude: %w", err)
 		}
 		req.Lat = &lat
 		req.Lon = &lon
@@ -71,94 +171,691 @@ func (h *Handler) GetPredictions(c *gin.Context) {
 		req.StationID = &stationID
 	}
 
-    // Parse time range. If missing and lat/lon provided, default to local (resolved) current day 00:00-24:00.
-    //nolint:nestif // Time range parsing with multiple default scenarios.
-    if startStr == "" && endStr == "" && req.Lat != nil && req.Lon != nil {
-        // Resolve simple timezone: JST for Japan bounding box, otherwise UTC.
-        loc, tzCode := resolveTimezoneForLatLon(*req.Lat, *req.Lon)
-        if timezone == "" {
-            req.Timezone = tzCode
-        }
-        nowLocal := time.Now().In(loc)
-        y, m, d := nowLocal.Date()
-        startLocal := time.Date(y, m, d, 0, 0, 0, 0, loc)
-        endLocal := startLocal.Add(24 * time.Hour)
-        req.Start = startLocal.UTC()
-        req.End = endLocal.UTC()
-    } else {
-        if startStr == "" {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "start parameter is required"})
-            return
-        }
-        if endStr == "" {
-            c.JSON(http.StatusBadRequest, gin.H{"error": "end parameter is required"})
-            return
-        }
-        start, err := time.Parse(time.RFC3339, startStr)
-        if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid start time (expected RFC3339): %v", err)})
-            return
-        }
-        end, err := time.Parse(time.RFC3339, endStr)
-        if err != nil {
-            c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid end time (expected RFC3339): %v", err)})
-            return
-        }
-        req.Start = start.UTC()
-        req.End = end.UTC()
-    }
-
-    // If timezone not provided but lat/lon present, set output TZ based on coordinates (always-on).
-    if req.Timezone == "" && req.Lat != nil && req.Lon != nil {
-        _, tzCode := resolveTimezoneForLatLon(*req.Lat, *req.Lon)
-        req.Timezone = tzCode
-    }
-
-    // Parse interval (default: 30m for better readability).
-    if intervalStr == "" {
-        intervalStr = "30m"
-    }
-
-	interval, err := time.ParseDuration(intervalStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid interval: %v", err)})
-		return
-	}
-	req.Interval = interval
-
 	// Parse optional datum offset.
 	if datumOffsetStr != "" {
 		off, err := strconv.ParseFloat(datumOffsetStr, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid datum_offset_m: %v", err)})
-			return
+			return req, fmt.Errorf("invalid datum_offset_m: %w", err)
 		}
 		req.DatumOffsetM = &off
 	}
 
-    // Execute use case.
-    response, err := h.predictionUC.Execute(req)
+	if slrStr := c.Query("slr_m"); slrStr != "" {
+		slr, err := strconv.ParseFloat(slrStr, 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid slr_m: %w", err)
+		}
+		req.SLRM = &slr
+	}
+	req.SLRScenario = c.Query("slr_scenario")
+	req.IncludeVLM = c.Query("include_vlm") == "true"
+	req.Nowcast = c.Query("nowcast") == "true"
+	req.Ensemble = c.Query("ensemble") == "true"
+	req.Debug = c.Query("debug") == "true"
+	if s := c.Query("constituents"); s != "" {
+		names, err := usecase.ParseConstituentSelection(s)
+		if err != nil {
+			return req, fmt.Errorf("invalid constituents: %w", err)
+		}
+		req.Select = names
+	}
+
+	return req, nil
+}
+
+// GetCrossings handles GET /v1/tides/crossings: the times the tide passes
+// the "height" query parameter (meters, relative to the response datum).
+func (h *Handler) GetCrossings(c *gin.Context) {
+	heightStr := c.Query("height")
+	if heightStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "height parameter is required"})
+		return
+	}
+	height, err := strconv.ParseFloat(heightStr, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid height: %v", err)})
+		return
+	}
+	req, err := parsePredictionRequest(c, h.prediction(c).Clock().Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.prediction(c).FindCrossings(req, height)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCurrents handles GET /v1/tides/currents: the tidal current at a
+// location, with slack water and maximum flood and ebb times.
+func (h *Handler) GetCurrents(c *gin.Context) {
+	req, err := parsePredictionRequest(c, h.prediction(c).Clock().Now())
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	response, err := h.prediction(c).PredictCurrents(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDatums handles GET /v1/tides/datums: the tidal datum table of a
+// location or station, which needs no time range.
+func (h *Handler) GetDatums(c *gin.Context) {
+	req, err := parseComparedSite(c, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Source = c.Query("source")
+	req.Datum = c.Query("datum")
+	for name, dst := range map[string]**float64{
+		"datum_offset_m": &req.DatumOffsetM,
+		"slr_m":          &req.SLRM,
+	} {
+		if v := c.Query(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s: %v", name, err)})
+				return
+			}
+			*dst = &f
+		}
+	}
+	req.SLRScenario = c.Query("slr_scenario")
+	req.IncludeVLM = c.Query("include_vlm") == "true"
+	req.Ensemble = c.Query("ensemble") == "true"
+
+	response, err := h.prediction(c).TidalDatums(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// spectrumRequest is the body of POST /v1/tides/spectrum.
+type spectrumRequest struct {
+	Observations []struct {
+		Time    time.Time `json:"time" binding:"required"`
+		HeightM *float64  `json:"height_m" binding:"required"`
+	} `json:"observations" binding:"required,min=1,dive"`
+}
+
+// GetSpectrum handles GET /v1/tides/spectrum: the amplitude spectrum of
+// the predicted series.
+func (h *Handler) GetSpectrum(c *gin.Context) {
+	h.writeSpectrum(c, nil)
+}
+
+// PostSpectrum handles POST /v1/tides/spectrum: as GET, with the residual
+// spectrum of the observations in the body.
+func (h *Handler) PostSpectrum(c *gin.Context) {
+	var body spectrumRequest
+	if !bindJSON(c, &body, "invalid request body") {
+		return
+	}
+	observations := make([]domain.TideLevel, len(body.Observations))
+	for i, o := range body.Observations {
+		observations[i] = domain.TideLevel{Time: o.Time.UTC(), HeightM: *o.HeightM}
+	}
+	h.writeSpectrum(c, observations)
+}
+
+func (h *Handler) writeSpectrum(c *gin.Context, observations []domain.TideLevel) {
+	req, err := parsePredictionRequest(c, h.prediction(c).Clock().Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.prediction(c).Spectrum(req, observations)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWindows handles GET /v1/tides/windows: ranked time windows over the
+// next days in which the tide satisfies the task's constraints.
+//
+//nolint:gocyclo // Sequential parameter parsing.
+func (h *Handler) GetWindows(c *gin.Context) {
+	req := usecase.WindowRequest{
+		Prediction: usecase.PredictionRequest{Timezone: c.Query("timezone"), Datum: c.Query("datum")},
+	}
+	pr := &req.Prediction
+
+	// Location.
+	if latStr, lonStr := c.Query("lat"), c.Query("lon"); latStr != "" && lonStr != "" {
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid latitude: %v", err)})
+			return
+		}
+		lon, err := strconv.ParseFloat(lonStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid longitude: %v", err)})
+			return
+		}
+		pr.Lat, pr.Lon = &lat, &lon
+		if pr.Timezone == "" {
+			_, pr.Timezone = resolveTimezoneForLatLon(lat, lon)
+		}
+	}
+	if stationID := c.Query("station_id"); stationID != "" {
+		pr.StationID = &stationID
+	}
+
+	// Horizon: start (default now) plus days (default 7).
+	pr.Start = h.prediction(c).Clock().Now().UTC().Truncate(time.Minute)
+	if startStr := c.Query("start"); startStr != "" {
+		start, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid start time (expected RFC3339): %v", err)})
+			return
+		}
+		pr.Start = start.UTC()
+	}
+	days := 7
+	if daysStr := c.Query("days"); daysStr != "" {
+		var err error
+		if days, err = strconv.Atoi(daysStr); err != nil || days < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+			return
+		}
+		// Checked before the horizon is computed, which overflows for large days.
+		if maxDays := int(usecase.MaxWindowSpan / (24 * time.Hour)); days > maxDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("days must be at most %d", maxDays)})
+			return
+		}
+	}
+	pr.End = pr.Start.Add(time.Duration(days) * 24 * time.Hour)
+
+	// Constraints.
+	durationStr := c.Query("duration")
+	if durationStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "duration parameter is required (e.g., 2h)"})
+		return
+	}
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid duration: %v", err)})
+		return
+	}
+	req.Duration = duration
+	for name, dst := range map[string]**float64{
+		"min_depth_m":    &req.MinDepthM,
+		"min_height_m":   &req.MinHeightM,
+		"max_current_ms": &req.MaxCurrentMS,
+		"datum_offset_m": &pr.DatumOffsetM,
+		"slr_m":          &pr.SLRM,
+	} {
+		if v := c.Query(name); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s: %v", name, err)})
+				return
+			}
+			*dst = &f
+		}
+	}
+	pr.SLRScenario = c.Query("slr_scenario")
+	req.DaylightOnly = c.Query("daylight_only") == "true"
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if req.Limit, err = strconv.Atoi(limitStr); err != nil || req.Limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+	}
+
+	response, err := h.prediction(c).PlanWindows(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, domain.ErrOutOfCoverage):
+			status = http.StatusNotFound
+		case errors.Is(err, usecase.ErrDataUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetComparison handles GET /v1/tides/compare: high-water lag, range ratio
+// and per-constituent lags between a "from" and a "to" site.
+func (h *Handler) GetComparison(c *gin.Context) {
+	from, err := parseComparedSite(c, "from_")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	to, err := parseComparedSite(c, "to_")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	at := h.prediction(c).Clock().Now().UTC()
+	if atStr := c.Query("time"); atStr != "" {
+		if at, err = time.Parse(time.RFC3339, atStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid time (expected RFC3339): %v", err)})
+			return
+		}
+	}
+
+	response, err := h.prediction(c).CompareSites(from, to, at)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
+// parseComparedSite reads the station_id or lat/lon of one compared site
+// from parameters with the given prefix.
+func parseComparedSite(c *gin.Context, prefix string) (usecase.PredictionRequest, error) {
+	req := usecase.PredictionRequest{PhaseConvention: c.Query("phase_convention")}
+	if stationID := c.Query(prefix + "station_id"); stationID != "" {
+		req.StationID = &stationID
+	}
+	if latStr, lonStr := c.Query(prefix+"lat"), c.Query(prefix+"lon"); latStr != "" && lonStr != "" {
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid %slat: %w", prefix, err)
+		}
+		lon, err := strconv.ParseFloat(lonStr, 64)
+		if err != nil {
+			return req, fmt.Errorf("invalid %slon: %w", prefix, err)
+		}
+		req.Lat, req.Lon = &lat, &lon
+	}
+	return req, nil
+}
+
+// customPredictionRequest is the body of POST /v1/tides/predictions.
+type customPredictionRequest struct {
+	Constituents []struct {
+		Name          string  `json:"name" binding:"required"`
+		AmplitudeM    float64 `json:"amplitude_m"`
+		PhaseDeg      float64 `json:"phase_deg"`
+		SpeedDegPerHr float64 `json:"speed_deg_per_hr"` // Optional for standard constituents.
+	} `json:"constituents" binding:"required,min=1,dive"`
+	Start           time.Time `json:"start" binding:"required"`
+	End             time.Time `json:"end" binding:"required"`
+	Interval        string    `json:"interval"`
+	DatumOffsetM    *float64  `json:"datum_offset_m"`
+	Timezone        string    `json:"timezone"`
+	PhaseConvention string    `json:"phase_convention"`
+	Debug           bool      `json:"debug"`
+}
+
+// PostPredictions handles POST /v1/tides/predictions: predictions and
+// extrema for a client-supplied constituent set, which is not stored.
+func (h *Handler) PostPredictions(c *gin.Context) {
+	var body customPredictionRequest
+	if !bindJSON(c, &body, "invalid request body") {
+		return
+	}
+
+	req := usecase.PredictionRequest{
+		Start:           body.Start.UTC(),
+		End:             body.End.UTC(),
+		DatumOffsetM:    body.DatumOffsetM,
+		Timezone:        body.Timezone,
+		PhaseConvention: body.PhaseConvention,
+		Debug:           body.Debug,
+		Constituents:    make([]domain.ConstituentParam, len(body.Constituents)),
+	}
+	for i, c := range body.Constituents {
+		req.Constituents[i] = domain.ConstituentParam{
+			Name:          c.Name,
+			AmplitudeM:    c.AmplitudeM,
+			PhaseDeg:      c.PhaseDeg,
+			SpeedDegPerHr: c.SpeedDegPerHr,
+		}
+	}
+
+	// Parse interval (default: 30m, as for GET).
+	if body.Interval == "" {
+		body.Interval = "30m"
+	}
+	interval, err := parseInterval(body.Interval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Interval = interval
+
+	opts, err := parseResponseOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := h.prediction(c).Execute(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorBody(err))
+		return
+	}
+
+	opts.write(c, response)
+}
+
+// batchItem is a request of the body of POST /v1/tides/predictions:batch,
+// with the parameters of GET /v1/tides/predictions.
+type batchItem struct {
+	ID              string   `json:"id"` // Echoed in the result.
+	StationID       *string  `json:"station_id"`
+	Lat             *float64 `json:"lat"`
+	Lon             *float64 `json:"lon"`
+	Start           string   `json:"start"`
+	End             string   `json:"end"`
+	Days            *int     `json:"days"`
+	Interval        string   `json:"interval"`
+	Datum           string   `json:"datum"`
+	Source          string   `json:"source"`
+	DatumOffsetM    *float64 `json:"datum_offset_m"`
+	Timezone        string   `json:"timezone"`
+	PhaseConvention string   `json:"phase_convention"`
+	SLRM            *float64 `json:"slr_m"`
+	SLRScenario     string   `json:"slr_scenario"`
+	IncludeVLM      bool     `json:"include_vlm"`
+	Nowcast         bool     `json:"nowcast"`
+	Ensemble        bool     `json:"ensemble"`
+	Debug           bool     `json:"debug"`
+}
+
+// request resolves an item as parsePredictionRequest resolves query
+// parameters.
+func (b batchItem) request(now time.Time) (usecase.PredictionRequest, error) {
+	req := usecase.PredictionRequest{
+		Lat:             b.Lat,
+		Lon:             b.Lon,
+		StationID:       b.StationID,
+		Datum:           b.Datum,
+		Source:          b.Source,
+		DatumOffsetM:    b.DatumOffsetM,
+		Timezone:        b.Timezone,
+		PhaseConvention: b.PhaseConvention,
+		SLRM:            b.SLRM,
+		SLRScenario:     b.SLRScenario,
+		IncludeVLM:      b.IncludeVLM,
+		Nowcast:         b.Nowcast,
+		Ensemble:        b.Ensemble,
+		Debug:           b.Debug,
+	}
+	hasLatLon := req.Lat != nil && req.Lon != nil
+
+	days := ""
+	if b.Days != nil {
+		days = strconv.Itoa(*b.Days)
+	}
+	if b.Start == "" && b.End == "" && days == "" && !hasLatLon {
+		return req, errors.New("start is required")
+	}
+	loc := requestedZone(b.Timezone)
+	if b.Timezone == "" && hasLatLon {
+		loc, req.Timezone = resolveTimezoneForLatLon(*req.Lat, *req.Lon)
+	}
+	var err error
+	if req.Start, req.End, err = resolveTimeRange(b.Start, b.End, days, now, loc); err != nil {
+		return req, err
+	}
+
+	// Default: 30m, as for GET.
+	if b.Interval == "" {
+		b.Interval = "30m"
+	}
+	if req.Interval, err = parseInterval(b.Interval); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+// PostPredictionsBatch handles POST /v1/tides/predictions:batch: the
+// predictions of up to usecase.MaxBatchRequests requests, given as a JSON
+// array, in one response. Each result carries the status and body its
+// request would get from GET /v1/tides/predictions; the response options
+// apply to every result.
+func (h *Handler) PostPredictionsBatch(c *gin.Context) {
+	var items []batchItem
+	if !bindJSON(c, &items, "invalid request body") {
+		return
+	}
+	if len(items) == 0 || len(items) > usecase.MaxBatchRequests {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("batch must have 1 to %d requests", usecase.MaxBatchRequests)})
+		return
+	}
+	opts, err := parseResponseOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if opts.format != formatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "batch results are JSON only"})
+		return
+	}
+
+	uc := h.prediction(c)
+	now := uc.Clock().Now()
+	language := requestLanguage(c)
+	c.Header("Content-Language", language)
+
+	results := make([]gin.H, len(items))
+	reqs := make([]usecase.PredictionRequest, 0, len(items))
+	parsed := make([]int, 0, len(items)) // Index of each request in items.
+	for i, item := range items {
+		req, err := item.request(now)
+		if err != nil {
+			results[i] = batchResult(item.ID, http.StatusBadRequest, errorBody(err))
+			continue
+		}
+		req.Language = language
+		reqs = append(reqs, req)
+		parsed = append(parsed, i)
+	}
+
+	if len(reqs) > 0 {
+		batch, err := uc.ExecuteBatch(reqs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for j, r := range batch {
+			i := parsed[j]
+			if r.Err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(r.Err, domain.ErrOutOfCoverage) {
+					status = http.StatusNotFound
+				}
+				results[i] = batchResult(items[i].ID, status, errorBody(r.Err))
+				continue
+			}
+			v, err := opts.apply(r.Response)
+			if err != nil {
+				results[i] = batchResult(items[i].ID, http.StatusInternalServerError, gin.H{"error": err.Error()})
+				continue
+			}
+			results[i] = batchResult(items[i].ID, http.StatusOK, gin.H{"response": v})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// batchResult is the result of a batch request: its id, status and body
+// fields (the response, or the error).
+func batchResult(id string, status int, body gin.H) gin.H {
+	result := gin.H{"status": status}
+	if id != "" {
+		result["id"] = id
+	}
+	for k, v := range body {
+		result[k] = v
+	}
+	return result
+}
+
+// sampleRequest is the body of POST /v1/tides/heights.
+type sampleRequest struct {
+	StationID       *string     `json:"station_id"`
+	Lat             *float64    `json:"lat"`
+	Lon             *float64    `json:"lon"`
+	Times           []time.Time `json:"times" binding:"required,min=1"`
+	Datum           string      `json:"datum"`
+	Source          string      `json:"source"`
+	DatumOffsetM    *float64    `json:"datum_offset_m"`
+	Timezone        string      `json:"timezone"`
+	PhaseConvention string      `json:"phase_convention"`
+	SLRM            *float64    `json:"slr_m"`
+	SLRScenario     string      `json:"slr_scenario"`
+	IncludeVLM      bool        `json:"include_vlm"`
+	Nowcast         bool        `json:"nowcast"`
+	Ensemble        bool        `json:"ensemble"`
+	Debug           bool        `json:"debug"`
+}
+
+// PostHeights handles POST /v1/tides/heights: heights at exactly the
+// client-supplied instants, which need not be regularly spaced.
+func (h *Handler) PostHeights(c *gin.Context) {
+	var body sampleRequest
+	if !bindJSON(c, &body, "invalid request body") {
+		return
+	}
+	opts, err := parseResponseOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req := usecase.PredictionRequest{
+		StationID:       body.StationID,
+		Lat:             body.Lat,
+		Lon:             body.Lon,
+		Datum:           body.Datum,
+		Source:          body.Source,
+		DatumOffsetM:    body.DatumOffsetM,
+		Timezone:        body.Timezone,
+		PhaseConvention: body.PhaseConvention,
+		Language:        requestLanguage(c),
+		SLRM:            body.SLRM,
+		SLRScenario:     body.SLRScenario,
+		IncludeVLM:      body.IncludeVLM,
+		Nowcast:         body.Nowcast,
+		Ensemble:        body.Ensemble,
+		Debug:           body.Debug,
+	}
+	if req.Timezone == "" && req.Lat != nil && req.Lon != nil {
+		_, req.Timezone = resolveTimezoneForLatLon(*req.Lat, *req.Lon)
+	}
+	c.Header("Content-Language", req.Language)
+
+	response, err := h.prediction(c).PredictAt(req, body.Times)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	opts.write(c, response)
+}
+
+// intervalPresets are named values of the interval parameter.
+//
+//nolint:gochecknoglobals // Intentional: fixed parameter aliases.
+var intervalPresets = map[string]time.Duration{
+	"hourly": time.Hour,
+	"10min":  10 * time.Minute,
+	"1min":   time.Minute,
+}
+
+// parseInterval parses an interval parameter: a preset or a Go duration.
+func parseInterval(s string) (time.Duration, error) {
+	if d, ok := intervalPresets[s]; ok {
+		return d, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval (expected a duration such as 30m, or hourly, 10min or 1min): %w", err)
+	}
+	return d, nil
+}
+
+// errorBody returns the JSON body of a failed request. When the range has
+// too many points for the interval, it carries the longest range allowed
+// (e.g., "166h40m"), usable as an end offset, so clients can shorten it.
+func errorBody(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	var rangeErr *usecase.RangeError
+	if errors.As(err, &rangeErr) {
+		body["interval"] = usecase.FormatDuration(rangeErr.Interval)
+		body["max_range"] = usecase.FormatDuration(rangeErr.MaxRange)
+	}
+	return body
+}
+
+// requestedZone returns the location of a timezone query parameter
+// ("jst", else UTC), as used for output timestamps.
+func requestedZone(timezone string) *time.Location {
+	if timezone == "jst" || timezone == "JST" {
+		return time.FixedZone("JST", 9*60*60)
+	}
+	return time.FixedZone("UTC", 0)
+}
+
 // resolveTimezoneForLatLon returns a best-effort location and label based on lat/lon.
 // Currently: Japan bounding box -> JST (+09:00), otherwise UTC.
 func resolveTimezoneForLatLon(lat, lon float64) (*time.Location, string) {
-    // Rough Japan bounding box (includes main islands): 20–46N, 122–154E
-    if lat >= 20 && lat <= 46 && lon >= 122 && lon <= 154 {
-        return time.FixedZone("JST", 9*60*60), "jst"
-    }
-    return time.FixedZone("UTC", 0), "utc"
+	// Rough Japan bounding box (includes main islands): 20–46N, 122–154E
+	if lat >= 20 && lat <= 46 && lon >= 122 && lon <= 154 {
+		return time.FixedZone("JST", 9*60*60), "jst"
+	}
+	return time.FixedZone("UTC", 0), "utc"
 }
 
 // GetConstituents handles GET /v1/constituents.
 func (h *Handler) GetConstituents(c *gin.Context) {
-	constituents := h.predictionUC.GetAllConstituents()
+	constituents := h.prediction(c).GetAllConstituents()
 
 	// Convert to response format.
 	type ConstituentInfo struct {
@@ -179,60 +876,142 @@ func (h *Handler) GetConstituents(c *gin.Context) {
 	})
 }
 
-// HealthCheck handles GET /healthz.
-func (h *Handler) HealthCheck(c *gin.Context) {
+// GetSurgeAlerts handles GET /v1/monitor/alerts.
+func (h *Handler) GetSurgeAlerts(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-		"time":   time.Now().UTC().Format(time.RFC3339),
+		"alerts": h.monitorUC.Alerts(),
 	})
 }
 
-// ConstituentListResponse is the response for listing constituents.
-type ConstituentListResponse struct {
-	Name          string  `json:"name"`
-	SpeedDegPerHr float64 `json:"speed_deg_per_hr"`
-	Description   string  `json:"description,omitempty"`
-}
-
-// GetConstituentsList returns a detailed list of all constituents.
-func (h *Handler) GetConstituentsList(c *gin.Context) {
-	constituents := domain.GetAllConstituents()
-
-	// Add descriptions for major constituents.
-	descriptions := map[string]string{
-		"M2":  "Principal lunar semidiurnal",
-		"S2":  "Principal solar semidiurnal",
-		"N2":  "Larger lunar elliptic semidiurnal",
-		"K2":  "Lunisolar semidiurnal",
-		"K1":  "Lunar diurnal",
-		"O1":  "Lunar diurnal",
-		"P1":  "Solar diurnal",
-		"Q1":  "Solar diurnal",
-		"M4":  "Shallow water overtide of M2",
-		"M6":  "Shallow water overtide of M2",
-		"MK3": "Shallow water terdiurnal",
-		"S4":  "Shallow water overtide of S2",
-		"MN4": "Shallow water quarter diurnal",
-		"MS4": "Shallow water quarter diurnal",
-		"Mf":  "Lunisolar fortnightly",
-		"Mm":  "Lunar monthly",
-		"Ssa": "Solar semiannual",
-		"Sa":  "Solar annual",
-	}
-
-	response := make([]ConstituentListResponse, len(constituents))
-	for i, c := range constituents {
-		response[i] = ConstituentListResponse{
-			Name:          c.Name,
-			SpeedDegPerHr: c.SpeedDegPerHr,
-			Description:   descriptions[c.Name],
+// GetDashboard handles GET /v1/monitor/dashboard.
+func (h *Handler) GetDashboard(c *gin.Context) {
+	// Snapshots are recomputed at most once a minute.
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, h.monitorUC.Dashboard())
+}
+
+// GetArchivedObservations handles GET /v1/observations/archive.
+func (h *Handler) GetArchivedObservations(c *gin.Context) {
+	q := usecase.ArchiveQuery{Station: c.Query("station")}
+
+	start, end, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	q.Start = start
+	q.End = end
+
+	if latStr, lonStr := c.Query("lat"), c.Query("lon"); latStr != "" || lonStr != "" {
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid latitude: %v", err)})
+			return
 		}
+		lon, err := strconv.ParseFloat(lonStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid longitude: %v", err)})
+			return
+		}
+		q.Lat = &lat
+		q.Lon = &lon
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"constituents": response,
-		"count":        len(response),
-	})
+	resp, err := h.archiveUC.Query(q)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// FillArchiveGaps handles POST /v1/observations/archive/fill.
+func (h *Handler) FillArchiveGaps(c *gin.Context) {
+	req := usecase.GapFillRequest{Station: c.Query("station")}
+
+	start, end, ok := parseTimeRange(c)
+	if !ok {
+		return
+	}
+	req.Start = start
+	req.End = end
+
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid latitude: %v", err)})
+		return
+	}
+	lon, err := strconv.ParseFloat(c.Query("lon"), 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid longitude: %v", err)})
+		return
+	}
+	req.Lat = lat
+	req.Lon = lon
+
+	resp, err := h.archiveUC.FillGaps(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// parseTimeRange parses the required RFC3339 start and end query parameters.
+// On failure it writes a 400 response and returns ok=false.
+func parseTimeRange(c *gin.Context) (start, end time.Time, ok bool) {
+	start, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid start time (expected RFC3339): %v", err)})
+		return time.Time{}, time.Time{}, false
+	}
+	end, err = time.Parse(time.RFC3339, c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid end time (expected RFC3339): %v", err)})
+		return time.Time{}, time.Time{}, false
+	}
+	return start.UTC(), end.UTC(), true
+}
+
+// HealthCheck handles GET /health and /healthz. The status is "degraded" while an
+// optional data file or table is unreadable; the server still answers predictions.
+func (h *Handler) HealthCheck(c *gin.Context) {
+	response := gin.H{
+		"status":     "ok",
+		"time":       h.prediction(c).Clock().Now().UTC().Format(time.RFC3339),
+		"version":    h.build.Version,
+		"commit":     h.build.Commit,
+		"build_date": h.build.BuildDate,
+		"data":       h.prediction(c).Datasets(),
+	}
+	if stores, ok := h.prediction(c).StoreHealth(); ok {
+		response["stores"] = stores
+		for _, s := range stores {
+			if !s.Healthy {
+				response["status"] = "degraded"
+			}
+		}
+	}
+	tables := h.prediction(c).TableHealth()
+	response["tables"] = tables
+	for _, t := range tables {
+		if !t.Healthy {
+			response["status"] = "degraded"
+		}
+	}
+	if circuits, ok := h.prediction(c).DatasetCircuits(); ok && len(circuits) > 0 {
+		response["datasets"] = circuits
+		response["status"] = "degraded"
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// requestLanguage resolves the response language from the lang query parameter
+// (if supported) or the Accept-Language header.
+func requestLanguage(c *gin.Context) string {
+	if lang := c.Query("lang"); i18n.Supported(lang) {
+		return lang
+	}
+	return i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
 }
 
 // GetBathymetry handles GET /v1/bathymetry.
@@ -269,26 +1048,42 @@ func (h *Handler) GetBathymetry(c *gin.Context) {
 	}
 
 	// Get bathymetry data.
-	metadata, err := h.predictionUC.GetBathymetry(lat, lon)
+	metadata, err := h.prediction(c).GetBathymetry(lat, lon)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		status := http.StatusNotFound
+		if errors.Is(err, usecase.ErrDataUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Build response.
+	// Build response with stable codes and localized labels.
+	lang := requestLanguage(c)
+	codes := usecase.SourceCodes(metadata)
 	response := gin.H{
 		"location": gin.H{
 			"lat": lat,
 			"lon": lon,
 		},
-		"msl_m":      metadata.MSL,
-		"datum_name": metadata.DatumName,
-		"source":     metadata.SourceName,
+		"msl_m":        metadata.MSL,
+		"datum_code":   metadata.Datum,
+		"datum_name":   i18n.DatumLabel(lang, string(metadata.Datum)),
+		"source_codes": codes,
+		"source":       i18n.SourcesLabel(lang, codes),
 	}
 
 	if metadata.DepthM != nil {
 		response["depth_m"] = *metadata.DepthM
 	}
+	if len(metadata.Degraded) > 0 {
+		response["degraded"] = true
+		response["degraded_reasons"] = metadata.Degraded
+	}
+	if metadata.Land {
+		response["land"] = true
+	}
 
+	c.Header("Content-Language", lang)
 	c.JSON(http.StatusOK, response)
 }
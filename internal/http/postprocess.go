@@ -0,0 +1,179 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+const metersPerFoot = 0.3048
+
+// postprocessor adjusts a prediction response for presentation. Processors
+// run after the use case, so they never change the fingerprint.
+type postprocessor func(*usecase.PredictionResponse)
+
+// responseOptions are the output query parameters shared by the prediction
+// endpoints.
+type responseOptions struct {
+	chain   []postprocessor
+	fields  fieldTree // Fields to keep; nil keeps all.
+	exclude fieldTree // Fields to drop after fields is applied.
+	format  string    // formatJSON, formatCSV or formatNDJSON.
+}
+
+// parseResponseOptions reads the output query parameters. Processors are
+// chained in application order: downsampling, unit conversion, then rounding.
+func parseResponseOptions(c *gin.Context) (responseOptions, error) {
+	var opts responseOptions
+
+	if s := c.Query("max_points"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 2 {
+			return opts, errors.New("max_points must be an integer of at least 2")
+		}
+		opts.chain = append(opts.chain, downsample(n))
+	}
+
+	switch units := c.Query("units"); units {
+	case "", "m":
+	case "ft":
+		opts.chain = append(opts.chain, toFeet)
+	default:
+		return opts, fmt.Errorf("invalid units %q (expected m or ft)", units)
+	}
+
+	if s := c.Query("decimals"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d < 0 || d > 3 {
+			return opts, errors.New("decimals must be an integer from 0 to 3")
+		}
+		opts.chain = append(opts.chain, roundHeights(d))
+	}
+
+	var err error
+	if opts.fields, err = parseFieldTree(c.Query("fields")); err != nil {
+		return opts, fmt.Errorf("invalid fields: %w", err)
+	}
+	if opts.exclude, err = parseFieldTree(c.Query("exclude")); err != nil {
+		return opts, fmt.Errorf("invalid exclude: %w", err)
+	}
+	if opts.format, err = parseOutputFormat(c); err != nil {
+		return opts, err
+	}
+	if opts.format != formatJSON && (opts.fields != nil || opts.exclude != nil) {
+		return opts, errors.New("fields and exclude apply to JSON output only")
+	}
+
+	return opts, nil
+}
+
+// write runs the chain on response and writes it in the selected format,
+// with the selected fields for JSON.
+func (o responseOptions) write(c *gin.Context, response *usecase.PredictionResponse) {
+	c.Set(predictionContextKey, response) // For the request log.
+	c.Header("Vary", "Accept")
+	switch o.format {
+	case formatCSV, formatNDJSON:
+		for _, p := range o.chain {
+			p(response)
+		}
+		if o.format == formatCSV {
+			writeCSV(c, response)
+		} else {
+			writeNDJSON(c, response)
+		}
+		return
+	}
+	v, err := o.apply(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// apply runs the chain on response and returns it with the selected fields.
+func (o responseOptions) apply(response *usecase.PredictionResponse) (any, error) {
+	for _, p := range o.chain {
+		p(response)
+	}
+	if o.fields == nil && o.exclude == nil {
+		return response, nil
+	}
+	v, err := toJSONValue(response)
+	if err != nil {
+		return nil, err
+	}
+	if o.fields != nil {
+		v = o.fields.keep(v)
+	}
+	if o.exclude != nil {
+		v = o.exclude.drop(v)
+	}
+	return v, nil
+}
+
+// downsample keeps every k-th prediction so that at most n remain.
+// Extrema are kept, as their times are exact.
+func downsample(n int) postprocessor {
+	return func(r *usecase.PredictionResponse) {
+		total := len(r.Predictions)
+		if total <= n {
+			return
+		}
+		step := (total + n - 1) / n
+		kept := make([]usecase.PredictionPoint, 0, n)
+		for i := 0; i < total; i += step {
+			kept = append(kept, r.Predictions[i])
+		}
+		r.Predictions = kept
+		r.Meta["downsampled_from"] = strconv.Itoa(total)
+	}
+}
+
+// toFeet converts every height and depth to feet. Field names keep their
+// _m suffix; meta.units reports the unit.
+func toFeet(r *usecase.PredictionResponse) {
+	mapHeights(r, func(v float64) float64 { return v / metersPerFoot })
+	r.Meta["units"] = "ft"
+}
+
+// roundHeights rounds every height and depth to d decimal places.
+func roundHeights(d int) postprocessor {
+	scale := math.Pow10(d)
+	return func(r *usecase.PredictionResponse) {
+		mapHeights(r, func(v float64) float64 { return math.Round(v*scale) / scale })
+	}
+}
+
+// mapHeights replaces every vertical value in r with fn of it. Optional
+// values get fresh pointers, as the originals may be shared with cached
+// metadata.
+func mapHeights(r *usecase.PredictionResponse, fn func(float64) float64) {
+	opt := func(v **float64) {
+		if *v != nil {
+			x := fn(**v)
+			*v = &x
+		}
+	}
+	points := func(ps []usecase.PredictionPoint) {
+		for i := range ps {
+			p := &ps[i]
+			p.HeightM = fn(p.HeightM)
+			opt(&p.DepthM)
+			opt(&p.MinM)
+			opt(&p.MaxM)
+		}
+	}
+	points(r.Predictions)
+	points(r.Extrema.Highs)
+	points(r.Extrema.Lows)
+	opt(&r.MSL)
+	opt(&r.SeabedDepth)
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// priorityHeader selects the priority class of a request; responses
+	// echo the class applied.
+	priorityHeader = "X-Priority"
+	// priorityContextKey stores the priority class in the gin context.
+	priorityContextKey = "priority"
+)
+
+// Priority is a request scheduling class.
+type Priority string
+
+// Priority classes.
+const (
+	// PriorityInteractive requests (the default) are served at once.
+	PriorityInteractive Priority = "interactive"
+	// PriorityBatch requests share a few slots and queue for them.
+	PriorityBatch Priority = "batch"
+)
+
+// SchedulerConfig bounds the batch class.
+type SchedulerConfig struct {
+	BatchConcurrency  int           // Batch requests served at once.
+	BatchQueue        int           // Batch requests waiting for a slot; more are rejected.
+	BatchQueueTimeout time.Duration // Longest wait for a slot.
+	BatchKeys         []string      // API keys whose requests are always batch.
+}
+
+// DefaultSchedulerConfig returns the default batch limits.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{BatchConcurrency: 2, BatchQueue: 32, BatchQueueTimeout: 30 * time.Second}
+}
+
+// Scheduler admits API requests by priority class, so large grid jobs
+// cannot starve interactive map queries: interactive requests run at once,
+// while batch requests (sent with "X-Priority: batch" or with a batch API
+// key) run a few at a time and wait in a bounded queue for a slot.
+type Scheduler struct {
+	cfg       SchedulerConfig
+	batchKeys map[string]bool
+	slots     chan struct{} // Batch slots in use.
+
+	mu    sync.Mutex
+	stats SchedulerStats
+}
+
+// SchedulerStats reports the requests of each priority class.
+type SchedulerStats struct {
+	Interactive ClassStats `json:"interactive"`
+	Batch       ClassStats `json:"batch"`
+}
+
+// ClassStats reports the requests of a priority class.
+type ClassStats struct {
+	InFlight int64 `json:"in_flight"`
+	Queued   int64 `json:"queued"`
+	Served   int64 `json:"served"`
+	Rejected int64 `json:"rejected"` // Queue full or wait timed out.
+}
+
+// NewScheduler creates a scheduler with the given batch limits.
+func NewScheduler(cfg SchedulerConfig) (*Scheduler, error) {
+	if cfg.BatchConcurrency < 1 {
+		return nil, errors.New("batch concurrency must be at least 1")
+	}
+	if cfg.BatchQueue < 0 {
+		return nil, errors.New("batch queue must not be negative")
+	}
+	if cfg.BatchQueueTimeout <= 0 {
+		return nil, errors.New("batch queue timeout must be positive")
+	}
+	s := &Scheduler{
+		cfg:       cfg,
+		batchKeys: make(map[string]bool, len(cfg.BatchKeys)),
+		slots:     make(chan struct{}, cfg.BatchConcurrency),
+	}
+	for _, k := range cfg.BatchKeys {
+		s.batchKeys[k] = true
+	}
+	return s, nil
+}
+
+// Stats returns the current and cumulative requests of each class.
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// classify returns the priority class of a request. A batch API key cannot
+// be raised to interactive by the header.
+func (s *Scheduler) classify(req *http.Request) (Priority, error) {
+	if key := req.Header.Get(apiKeyHeader); key != "" && s.batchKeys[key] {
+		return PriorityBatch, nil
+	}
+	switch p := Priority(strings.ToLower(req.Header.Get(priorityHeader))); p {
+	case "", PriorityInteractive:
+		return PriorityInteractive, nil
+	case PriorityBatch:
+		return PriorityBatch, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q (expected %s or %s)", priorityHeader, p, PriorityInteractive, PriorityBatch)
+	}
+}
+
+// errBatchQueueFull and errBatchQueueTimeout reject batch requests.
+var (
+	errBatchQueueFull    = errors.New("too many batch requests queued")
+	errBatchQueueTimeout = errors.New("timed out waiting for a batch slot")
+)
+
+// admit waits until a request of class p may run and returns the function
+// releasing its slot.
+func (s *Scheduler) admit(ctx context.Context, p Priority) (release func(), err error) {
+	class := func() *ClassStats {
+		if p == PriorityBatch {
+			return &s.stats.Batch
+		}
+		return &s.stats.Interactive
+	}
+	started := func() {
+		s.mu.Lock()
+		class().InFlight++
+		s.mu.Unlock()
+	}
+	release = func() {
+		if p == PriorityBatch {
+			<-s.slots
+		}
+		s.mu.Lock()
+		class().InFlight--
+		class().Served++
+		s.mu.Unlock()
+	}
+	if p != PriorityBatch {
+		started()
+		return release, nil
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		started()
+		return release, nil
+	default:
+	}
+
+	s.mu.Lock()
+	if s.stats.Batch.Queued >= int64(s.cfg.BatchQueue) {
+		s.stats.Batch.Rejected++
+		s.mu.Unlock()
+		return nil, errBatchQueueFull
+	}
+	s.stats.Batch.Queued++
+	s.mu.Unlock()
+	dequeue := func(rejected bool) {
+		s.mu.Lock()
+		s.stats.Batch.Queued--
+		if rejected {
+			s.stats.Batch.Rejected++
+		}
+		s.mu.Unlock()
+	}
+
+	timer := time.NewTimer(s.cfg.BatchQueueTimeout)
+	defer timer.Stop()
+	select {
+	case s.slots <- struct{}{}:
+		dequeue(false)
+		started()
+		return release, nil
+	case <-timer.C:
+		dequeue(true)
+		return nil, errBatchQueueTimeout
+	case <-ctx.Done():
+		// The client is gone.
+		dequeue(false)
+		return nil, ctx.Err()
+	}
+}
+
+// priorityMiddleware classifies requests and holds batch requests until a
+// batch slot is free. Rejected batch requests get 429 with a Retry-After.
+func priorityMiddleware(s *Scheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := s.classify(c.Request)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(priorityContextKey, p)
+		c.Header(priorityHeader, string(p))
+
+		release, err := s.admit(c.Request.Context(), p)
+		if err != nil {
+			c.Header("Retry-After", strconv.Itoa(max(int(s.cfg.BatchQueueTimeout.Seconds()), 1)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
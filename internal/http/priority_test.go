@@ -0,0 +1,172 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestScheduler(t *testing.T, concurrency, queue int, timeout time.Duration) *Scheduler {
+	t.Helper()
+	s, err := NewScheduler(SchedulerConfig{BatchConcurrency: concurrency, BatchQueue: queue, BatchQueueTimeout: timeout})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// waitQueued waits until n batch requests are queued.
+func waitQueued(t *testing.T, s *Scheduler, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Stats().Batch.Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("queued = %d, want %d", s.Stats().Batch.Queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerQueueFull(t *testing.T) {
+	s := newTestScheduler(t, 1, 1, time.Minute)
+	ctx := context.Background()
+
+	release, err := s.admit(ctx, PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admitted := make(chan func())
+	go func() {
+		r, err := s.admit(ctx, PriorityBatch)
+		if err != nil {
+			t.Error(err)
+			close(admitted)
+			return
+		}
+		admitted <- r
+	}()
+	waitQueued(t, s, 1)
+
+	if _, err := s.admit(ctx, PriorityBatch); !errors.Is(err, errBatchQueueFull) {
+		t.Fatalf("third batch request: %v, want %v", err, errBatchQueueFull)
+	}
+	// Interactive requests do not wait for batch slots.
+	interactive, err := s.admit(ctx, PriorityInteractive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactive()
+
+	// Releasing the slot admits the queued request.
+	release()
+	second, ok := <-admitted
+	if !ok {
+		t.FailNow()
+	}
+	want := SchedulerStats{
+		Interactive: ClassStats{Served: 1},
+		Batch:       ClassStats{InFlight: 1, Served: 1, Rejected: 1},
+	}
+	if got := s.Stats(); got != want {
+		t.Errorf("stats = %+v, want %+v", got, want)
+	}
+	second()
+	if got := s.Stats().Batch; got.InFlight != 0 || got.Served != 2 {
+		t.Errorf("batch stats after release = %+v", got)
+	}
+}
+
+func TestSchedulerQueueTimeout(t *testing.T) {
+	s := newTestScheduler(t, 1, 1, 20*time.Millisecond)
+	release, err := s.admit(context.Background(), PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	start := time.Now()
+	if _, err := s.admit(context.Background(), PriorityBatch); !errors.Is(err, errBatchQueueTimeout) {
+		t.Fatalf("queued request: %v, want %v", err, errBatchQueueTimeout)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("rejected after %v, before the queue timeout", waited)
+	}
+	if got := s.Stats().Batch; got.Queued != 0 || got.Rejected != 1 || got.InFlight != 1 {
+		t.Errorf("batch stats = %+v", got)
+	}
+}
+
+func TestSchedulerClientCancelWhileQueued(t *testing.T) {
+	s := newTestScheduler(t, 1, 1, time.Minute)
+	release, err := s.admit(context.Background(), PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := s.admit(ctx, PriorityBatch)
+		done <- err
+	}()
+	waitQueued(t, s, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled request: %v, want %v", err, context.Canceled)
+	}
+	// A client that went away is not counted as rejected.
+	if got := s.Stats().Batch; got.Queued != 0 || got.Rejected != 0 {
+		t.Errorf("batch stats = %+v", got)
+	}
+}
+
+func TestPriorityMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := newTestScheduler(t, 1, 0, 5*time.Second)
+	router := gin.New()
+	router.Use(priorityMiddleware(s))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	serve := func(priority string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if priority != "" {
+			req.Header.Set(priorityHeader, priority)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(""); w.Code != http.StatusNoContent || w.Header().Get(priorityHeader) != "interactive" {
+		t.Errorf("default = %d %s %q", w.Code, priorityHeader, w.Header().Get(priorityHeader))
+	}
+	if w := serve("Batch"); w.Code != http.StatusNoContent || w.Header().Get(priorityHeader) != "batch" {
+		t.Errorf("batch = %d %s %q", w.Code, priorityHeader, w.Header().Get(priorityHeader))
+	}
+	if w := serve("urgent"); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid priority = %d, want 400", w.Code)
+	}
+
+	// With the only slot taken and no queue, batch requests are turned away.
+	release, err := s.admit(context.Background(), PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	w := serve("batch")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "5" {
+		t.Errorf("full = %d Retry-After %q, want 429 after 5", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := serve("interactive"); w.Code != http.StatusNoContent {
+		t.Errorf("interactive while batch is full = %d", w.Code)
+	}
+	if got := s.Stats(); got.Batch.Rejected != 1 || got.Batch.Served != 1 || got.Interactive.Served != 2 {
+		t.Errorf("stats = %+v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/metrics"
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// prometheusContentType is the media type of the Prometheus text format.
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricsMiddleware counts requests by route template, so that paths with
+// parameters (e.g., /v1/constituents/:name/grid) share a series, and the
+// constituents of prediction responses by source.
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.HTTPRequests.With(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.With(route).Observe(time.Since(start).Seconds())
+		if v, ok := c.Get(predictionContextKey); ok {
+			if p, ok := v.(*usecase.PredictionResponse); ok {
+				metrics.PredictionConstituents.With(p.Source).Observe(float64(len(p.Constituents)))
+			}
+		}
+	}
+}
+
+// GetPrometheusMetrics handles GET /metrics: the metrics of the default
+// registry in the Prometheus text format.
+func (h *Handler) GetPrometheusMetrics(c *gin.Context) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", prometheusContentType)
+	if err := metrics.Default().WriteText(c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
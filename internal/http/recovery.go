@@ -0,0 +1,94 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorReporter delivers incident reports to an error tracking service
+// (e.g., a webhook relaying to Sentry).
+type ErrorReporter interface {
+	Notify(payload any) error
+}
+
+// Incident is a request that panicked, as reported to the ErrorReporter.
+type Incident struct {
+	ID        string    `json:"incident_id"`
+	RequestID string    `json:"request_id,omitempty"`
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Query     string    `json:"query,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+	Build     BuildInfo `json:"build"`
+}
+
+// recoveryMiddleware turns a panic in a handler (e.g., on a malformed
+// NetCDF read) into a 500 response carrying an incident ID, logs it with
+// its stack and reports it when a reporter is set, instead of gin's
+// recovery that leaves clients and trackers without a reference.
+func recoveryMiddleware(reporter ErrorReporter, build BuildInfo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				// Aborted responses are handled by net/http.
+				panic(r)
+			}
+			incident := Incident{
+				ID:        randomID(),
+				RequestID: requestID(c),
+				Time:      time.Now().UTC(),
+				Method:    c.Request.Method,
+				Path:      c.Request.URL.Path,
+				Query:     c.Request.URL.RawQuery,
+				Panic:     fmt.Sprint(r),
+				Stack:     string(debug.Stack()),
+				Build:     build,
+			}
+			if v, ok := c.Get(tenantContextKey); ok {
+				if t, ok := v.(*Tenant); ok {
+					incident.Tenant = t.Name
+				}
+			}
+			log.Printf("Panic (incident %s, request %s): %s %s: %s\n%s", incident.ID, incident.RequestID, incident.Method, c.Request.URL.RequestURI(), incident.Panic, incident.Stack)
+			if reporter != nil {
+				go func() {
+					if err := reporter.Notify(incident); err != nil {
+						log.Printf("Warning: failed to report incident %s: %v", incident.ID, err)
+					}
+				}()
+			}
+
+			c.Header("X-Incident-ID", incident.ID)
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":       "internal server error",
+				"incident_id": incident.ID,
+			})
+		}()
+		c.Next()
+	}
+}
+
+// randomID returns a random identifier, e.g., of an incident.
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// requestIDHeader carries the request ID; responses always set it.
+	requestIDHeader = "X-Request-ID"
+	// cloudTraceHeader carries the trace of requests served on Cloud Run.
+	cloudTraceHeader = "X-Cloud-Trace-Context"
+	// requestIDContextKey stores the request ID in the gin context.
+	requestIDContextKey = "request_id"
+	// maxRequestIDLength bounds client-supplied request IDs.
+	maxRequestIDLength = 128
+)
+
+// requestIDMiddleware identifies each request for its logs and responses:
+// by the client's X-Request-ID when valid, else by the trace ID of Cloud
+// Run's X-Cloud-Trace-Context (so logs join the request's trace), else by
+// a random ID.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id, _, _ = strings.Cut(c.GetHeader(cloudTraceHeader), "/")
+			if !validRequestID(id) {
+				id = randomID()
+			}
+		}
+		c.Set(requestIDContextKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID reports whether id is a non-empty, bounded token safe to
+// echo in headers and logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestID returns the ID of the request, if identified.
+func requestID(c *gin.Context) string {
+	return c.GetString(requestIDContextKey)
+}
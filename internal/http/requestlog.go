@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+const (
+	// maxLoggedBodyBytes bounds the request body kept for the slow-request log.
+	maxLoggedBodyBytes = 16 << 10
+	// predictionContextKey stores the prediction response in the gin
+	// context.
+	predictionContextKey = "prediction"
+)
+
+// RequestLog writes structured request logs: every request slower than a
+// threshold, with its full parameters and timing breakdown, and a sample
+// of the others. Entries carry the request ID, so that the logs of a
+// request can be found from its X-Request-ID response header.
+type RequestLog struct {
+	logger        *slog.Logger
+	slowThreshold time.Duration
+	sampleRate    float64
+}
+
+// NewRequestLog creates a request log. A zero slowThreshold disables the
+// slow-request log; sampleRate (0-1) is the fraction of other requests logged.
+func NewRequestLog(logger *slog.Logger, slowThreshold time.Duration, sampleRate float64) *RequestLog {
+	return &RequestLog{logger: logger, slowThreshold: slowThreshold, sampleRate: sampleRate}
+}
+
+// requestLogMiddleware logs slow requests and a sample of the others once
+// served, replacing gin's per-request logger.
+func requestLogMiddleware(l *RequestLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		var body []byte
+		if c.Request.Body != nil && c.Request.Method != http.MethodGet {
+			b, err := io.ReadAll(io.LimitReader(c.Request.Body, maxLoggedBodyBytes))
+			if err == nil {
+				body = b
+			}
+			c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), c.Request.Body))
+		}
+
+		c.Next()
+
+		latency := time.Since(start)
+		slow := l.slowThreshold > 0 && latency >= l.slowThreshold
+		//nolint:gosec // G404: Sampling needs no cryptographic randomness.
+		if !slow && rand.Float64() >= l.sampleRate {
+			return
+		}
+
+		attrs := []slog.Attr{
+			slog.String("request_id", requestID(c)),
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", c.Writer.Status()),
+			slog.Float64("latency_ms", milliseconds(latency)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if q := c.Request.URL.RawQuery; q != "" {
+			attrs = append(attrs, slog.String("query", q))
+		}
+		if lat, lon, ok := requestLocation(c, body); ok {
+			attrs = append(attrs, slog.Float64("lat", lat), slog.Float64("lon", lon))
+		}
+		if v, ok := c.Get(tenantContextKey); ok {
+			if t, ok := v.(*Tenant); ok {
+				attrs = append(attrs, slog.String("tenant", t.Name))
+			}
+		}
+		if v, ok := c.Get(priorityContextKey); ok {
+			if p, ok := v.(Priority); ok {
+				attrs = append(attrs, slog.String("priority", string(p)))
+			}
+		}
+		if v, ok := c.Get(predictionContextKey); ok {
+			if p, ok := v.(*usecase.PredictionResponse); ok {
+				attrs = append(attrs,
+					slog.String("source", p.Source),
+					slog.Int("constituent_count", len(p.Constituents)),
+				)
+				if p.ConstituentCache != "" {
+					attrs = append(attrs, slog.String("cache", p.ConstituentCache))
+				}
+				attrs = append(attrs, slog.Group("timing",
+					slog.Float64("constituent_load_ms", milliseconds(p.Timing.Load)),
+					slog.Float64("corrections_ms", milliseconds(p.Timing.Corrections)),
+					slog.Float64("synthesis_ms", milliseconds(p.Timing.Synthesis)),
+				))
+			}
+		}
+
+		if !slow {
+			l.logger.LogAttrs(c.Request.Context(), slog.LevelInfo, "request", attrs...)
+			return
+		}
+		if len(body) > 0 {
+			attrs = append(attrs, slog.String("body", string(body)))
+		}
+		attrs = append(attrs, slog.Float64("threshold_ms", milliseconds(l.slowThreshold)))
+		l.logger.LogAttrs(c.Request.Context(), slog.LevelWarn, "slow request", attrs...)
+	}
+}
+
+// requestLocation returns the lat/lon of a request, from its query or,
+// for POST requests, from the logged part of its JSON body.
+func requestLocation(c *gin.Context, body []byte) (lat, lon float64, ok bool) {
+	if s, t := c.Query("lat"), c.Query("lon"); s != "" && t != "" {
+		var errLat, errLon error
+		lat, errLat = strconv.ParseFloat(s, 64)
+		lon, errLon = strconv.ParseFloat(t, 64)
+		return lat, lon, errLat == nil && errLon == nil
+	}
+	var loc struct {
+		Lat *float64 `json:"lat"`
+		Lon *float64 `json:"lon"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &loc) != nil || loc.Lat == nil || loc.Lon == nil {
+		return 0, 0, false
+	}
+	return *loc.Lat, *loc.Lon, true
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
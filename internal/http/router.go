@@ -1,6 +1,8 @@
 package http
 
 import (
+	"log"
+	"net/http"
 	"os"
 	"strings"
 
@@ -10,10 +12,55 @@ import (
 	"go.ngs.io/tides-api/internal/usecase"
 )
 
-// SetupRouter creates and configures the Gin router.
-func SetupRouter(predictionUC *usecase.PredictionUseCase) *gin.Engine {
+// Services bundles the use cases served over HTTP.
+// Optional use cases may be nil, in which case their routes are not registered.
+type Services struct {
+	Prediction *usecase.PredictionUseCase
+	Monitor    *usecase.MonitorUseCase
+	Archive    *usecase.ArchiveUseCase
+	// Tenants optionally scopes prediction data per API key or host.
+	// When nil, all requests use Prediction.
+	Tenants *TenantResolver
+	// Analytics optionally records usage per API key or origin.
+	Analytics *usecase.UsageAnalytics
+	// Recalibration optionally stages scheduled refits of the station
+	// tables for admin approval.
+	Recalibration *usecase.RecalibrationUseCase
+	// Shadow optionally replays a sample of /v1/tides requests to another
+	// instance and compares the responses.
+	Shadow *usecase.ShadowEvaluator
+	// Scheduler optionally limits the concurrency of batch-priority
+	// requests to /v1.
+	Scheduler *Scheduler
+	// RequestLog optionally replaces gin's per-request logger with
+	// structured slow-request and sampled request logs.
+	RequestLog *RequestLog
+	// Build identifies the running server in health checks.
+	Build BuildInfo
+	// ErrorReporter optionally receives incident reports of panicking
+	// requests.
+	ErrorReporter ErrorReporter
+	// Docs serves the OpenAPI spec at /openapi.json and an interactive
+	// explorer at /docs.
+	Docs bool
+	// Metrics serves Prometheus metrics at /metrics.
+	Metrics bool
+}
 
-	router := gin.Default()
+// SetupRouter creates and configures the Gin router.
+func SetupRouter(services Services) *gin.Engine {
+	router := gin.New()
+	router.Use(requestIDMiddleware())
+	if services.RequestLog != nil {
+		router.Use(requestLogMiddleware(services.RequestLog))
+	} else {
+		router.Use(gin.Logger())
+	}
+	if services.Metrics {
+		// Outside recovery, so that panicking requests count as 500s.
+		router.Use(metricsMiddleware())
+	}
+	router.Use(recoveryMiddleware(services.ErrorReporter, services.Build))
 
 	// Setup CORS middleware.
 	corsConfig := cors.DefaultConfig()
@@ -27,25 +74,141 @@ func SetupRouter(predictionUC *usecase.PredictionUseCase) *gin.Engine {
 		corsConfig.AllowAllOrigins = true
 	}
 
+	corsConfig.AddAllowHeaders(apiKeyHeader, priorityHeader, requestIDHeader)
+	corsConfig.AddExposeHeaders("X-Tenant", priorityHeader, requestIDHeader)
+
 	router.Use(cors.New(corsConfig))
+	if services.Analytics != nil {
+		router.Use(usageMiddleware(services.Analytics))
+	}
+	if services.Tenants != nil {
+		router.Use(tenantMiddleware(services.Tenants))
+	}
 
 	// Create handler.
-	handler := NewHandler(predictionUC)
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	handler := NewHandler(services)
+	handler.admin = adminToken != ""
+	handler.docs = services.Docs
+	handler.metrics = services.Metrics
 
 	// API v1 routes.
 	v1 := router.Group("/v1")
+	if services.Scheduler != nil {
+		v1.Use(priorityMiddleware(services.Scheduler))
+	}
+	// Query parameters are checked against the spec before the handlers.
+	if ops, err := specQueryParams(openAPISpec); err != nil {
+		log.Printf("Warning: query validation disabled: %v", err)
+	} else {
+		v1.Use(validateQuery(ops))
+	}
+	v1.GET("/openapi.json", handler.GetOpenAPI)
 	// Tide predictions.
 	tides := v1.Group("/tides")
+	if services.Shadow != nil {
+		tides.Use(shadowMiddleware(services.Shadow))
+	}
 	tides.GET("/predictions", handler.GetPredictions)
+	tides.POST("/predictions", limitBody(maxJSONBodyBytes, contentTypeJSON), handler.PostPredictions)
+	tides.POST("/predictions:batch", customMethod("batch"), limitBody(maxJSONBodyBytes, contentTypeJSON), handler.PostPredictionsBatch)
+	tides.POST("/heights", limitBody(maxJSONBodyBytes, contentTypeJSON), handler.PostHeights)
+	tides.GET("/crossings", handler.GetCrossings)
+	tides.GET("/currents", handler.GetCurrents)
+	tides.GET("/windows", handler.GetWindows)
+	tides.GET("/datums", handler.GetDatums)
+	tides.GET("/compare", handler.GetComparison)
+	tides.GET("/spectrum", handler.GetSpectrum)
+	tides.POST("/spectrum", limitBody(maxJSONBodyBytes, contentTypeJSON), handler.PostSpectrum)
+	// Outside the tides group, as shadowing records whole responses.
+	v1.GET("/tides/stream", handler.GetTideStream)
 
 	// Constituents.
 	v1.GET("/constituents", handler.GetConstituentsList)
+	v1.GET("/constituents/:name/coverage", handler.GetConstituentCoverage)
+	v1.GET("/constituents/:name/grid", handler.GetConstituentGrid)
+
+	// Station discovery.
+	v1.GET("/stations", handler.GetStations)
+
+	// Build, features and dataset versions.
+	v1.GET("/version", handler.GetVersion)
+
+	// Request/response examples for client contract tests.
+	v1.GET("/examples", handler.GetExamples)
 
 	// Bathymetry.
 	v1.GET("/bathymetry", handler.GetBathymetry)
 
+	// Charts.
+	v1.GET("/charts/cotidal", handler.GetCotidalChart)
+	v1.GET("/charts/amphidromes", handler.GetAmphidromes)
+
+	// Station monitoring.
+	if services.Monitor != nil {
+		v1.GET("/monitor/alerts", handler.GetSurgeAlerts)
+		v1.GET("/monitor/dashboard", handler.GetDashboard)
+	}
+
+	// Observation archive.
+	if services.Archive != nil {
+		v1.GET("/observations/archive", handler.GetArchivedObservations)
+		v1.POST("/observations/archive/fill", handler.FillArchiveGaps)
+	}
+
+	// Admin (enabled only when ADMIN_TOKEN is set), under /v1/admin and,
+	// as first served, /admin.
+	for _, prefix := range []string{"/v1/admin", "/admin"} {
+		if adminToken == "" {
+			break
+		}
+		admin := router.Group(prefix, adminAuth(adminToken))
+		admin.GET("/snapshot", handler.ExportSnapshot)
+		admin.POST("/snapshot", limitBody(maxSnapshotBytes, contentTypeJSON), handler.RestoreSnapshot)
+		admin.POST("/warm", handler.Warm)
+		admin.GET("/metrics", handler.GetMetrics)
+		admin.POST("/stations/:id/import", limitBody(maxWorkbookBytes, contentTypeMultipart), handler.ImportStation)
+		admin.POST("/estimate-datum", limitBody(maxJSONBodyBytes, contentTypeJSON), handler.EstimateDatum)
+		admin.POST("/tune-radii", limitBody(maxJSONBodyBytes, contentTypeJSON), handler.TuneRadii)
+		admin.GET("/feed", handler.ExportFeed)
+		if services.Analytics != nil {
+			admin.GET("/analytics", handler.GetUsageAnalytics)
+		}
+		if services.Recalibration != nil {
+			admin.GET("/recalibrations", handler.ListRecalibrations)
+			admin.POST("/recalibrations", handler.RunRecalibration)
+			admin.GET("/recalibrations/:id", handler.GetRecalibration)
+			admin.POST("/recalibrations/:id/approve", handler.ApproveRecalibration)
+			admin.POST("/recalibrations/:id/reject", handler.RejectRecalibration)
+		}
+	}
+
+	// API explorer (enabled only with API_DOCS).
+	if services.Docs {
+		router.GET("/openapi.json", handler.GetOpenAPI)
+		router.GET("/docs", handler.GetDocs)
+	}
+
+	// Prometheus metrics (enabled only with METRICS_ENABLED).
+	if services.Metrics {
+		router.GET("/metrics", handler.GetPrometheusMetrics)
+	}
+
 	// Health check.
 	router.GET("/health", handler.HealthCheck)
+	router.GET("/healthz", handler.HealthCheck)
 
 	return router
 }
+
+// customMethod guards a route registered as /resource:method. Gin reads
+// :method as a path parameter of the resource segment, so the guard answers
+// 404 for any other suffix, as in /resourceX. Gin unescapes \: routes only
+// in Engine.Run, not when the router is served by an http.Server or in tests.
+func customMethod(method string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Param(method) != ":"+method {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+		}
+	}
+}
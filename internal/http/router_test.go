@@ -0,0 +1,44 @@
+package http
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// TestSpecCoversRoutes checks that openapi.json, which is written by hand,
+// describes every public /v1 route with all optional services enabled, and
+// nothing that is not served.
+func TestSpecCoversRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ADMIN_TOKEN", "secret")
+	ops, err := specQueryParams(openAPISpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := SetupRouter(Services{
+		Prediction: usecase.NewPredictionUseCase(m2Loader{}, m2Loader{}, nil),
+		Monitor:    &usecase.MonitorUseCase{},
+		Archive:    &usecase.ArchiveUseCase{},
+	})
+
+	served := make(map[string]bool)
+	for _, r := range router.Routes() {
+		op := r.Method + " " + r.Path
+		served[op] = true
+		if !strings.HasPrefix(r.Path, "/v1/") || strings.HasPrefix(r.Path, "/v1/admin/") {
+			continue
+		}
+		if _, ok := ops[op]; !ok {
+			t.Errorf("%s is served but not in openapi.json", op)
+		}
+	}
+	for op := range ops {
+		if !served[op] {
+			t.Errorf("%s is in openapi.json but not served", op)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+// maxShadowBodyBytes bounds request bodies replayed to the shadow instance.
+const maxShadowBodyBytes = 1 << 20
+
+// shadowHeaders are the request headers replayed; the API key selects the
+// same tenant on the shadow instance.
+//
+//nolint:gochecknoglobals // Intentional: fixed header list.
+var shadowHeaders = []string{"Content-Type", "Accept", apiKeyHeader}
+
+// bodyRecorder keeps a copy of the response body.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// shadowMiddleware replays a sample of requests to the shadow instance
+// once served, comparing responses in the background.
+func shadowMiddleware(s *usecase.ShadowEvaluator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.Sample() {
+			c.Next()
+			return
+		}
+		var body []byte
+		if c.Request.Body != nil {
+			b, err := io.ReadAll(io.LimitReader(c.Request.Body, maxShadowBodyBytes+1))
+			if err != nil || len(b) > maxShadowBodyBytes {
+				c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), c.Request.Body))
+				c.Next()
+				return
+			}
+			body = b
+			c.Request.Body = io.NopCloser(bytes.NewReader(b))
+		}
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		header := make(http.Header)
+		for _, h := range shadowHeaders {
+			if v := c.Request.Header.Get(h); v != "" {
+				header.Set(h, v)
+			}
+		}
+		s.Submit(usecase.ShadowRequest{
+			Method:   c.Request.Method,
+			Target:   c.Request.URL.RequestURI(),
+			Header:   header,
+			Body:     body,
+			Status:   recorder.Status(),
+			Response: recorder.body.Bytes(),
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStations handles GET /v1/stations: the station files, whose IDs are
+// valid station_id values, and the station overrides applied to lat/lon
+// predictions. With lat and lon, stations are sorted nearest first and the
+// nearest is also returned on its own.
+func (h *Handler) GetStations(c *gin.Context) {
+	lat, lon, err := parseOptionalLocation(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	stations, err := h.prediction(c).ListStations(lat, lon)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	response := gin.H{"count": len(stations), "stations": stations}
+	if lat != nil && len(stations) > 0 && stations[0].DistanceKm != nil {
+		response["nearest"] = stations[0]
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// parseOptionalLocation reads the lat and lon query parameters, which are
+// optional but must be given together.
+func parseOptionalLocation(c *gin.Context) (lat, lon *float64, err error) {
+	latStr, lonStr := c.Query("lat"), c.Query("lon")
+	if latStr == "" && lonStr == "" {
+		return nil, nil, nil
+	}
+	if latStr == "" || lonStr == "" {
+		return nil, nil, errors.New("lat and lon must be given together")
+	}
+	la, err := strconv.ParseFloat(latStr, 64)
+	if err != nil || la < -90 || la > 90 {
+		return nil, nil, errors.New("latitude must be a number between -90 and 90")
+	}
+	lo, err := strconv.ParseFloat(lonStr, 64)
+	if err != nil || lo < -180 || lo > 180 {
+		return nil, nil, errors.New("longitude must be a number between -180 and 180")
+	}
+	return &la, &lo, nil
+}
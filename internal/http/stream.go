@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// defaultStreamEvery is the default period of stream events.
+	defaultStreamEvery = time.Minute
+	// minStreamEvery and maxStreamEvery bound the period of stream events.
+	minStreamEvery = time.Second
+	maxStreamEvery = time.Hour
+)
+
+// GetTideStream handles GET /v1/tides/stream: Server-Sent Events carrying
+// the predicted height of a location or station now, then every "every"
+// (default 1m), so dashboards need not poll the predictions endpoint. The
+// constituents are loaded once (and again hourly), not for each event.
+// Location and correction parameters are those of the predictions
+// endpoint; a failure after the stream started ends it with an "error"
+// event.
+func (h *Handler) GetTideStream(c *gin.Context) {
+	req, err := parsePredictionTarget(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	every := defaultStreamEvery
+	if v := c.Query("every"); v != "" {
+		if every, err = time.ParseDuration(v); err != nil || every < minStreamEvery || every > maxStreamEvery {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("every must be a duration from %s to %s", minStreamEvery, maxStreamEvery)})
+			return
+		}
+	}
+	// A stream would hold a batch slot for as long as it is open.
+	if p, _ := c.Get(priorityContextKey); p == PriorityBatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "streams are not available to batch priority requests"})
+		return
+	}
+	if req.Timezone == "" && req.Lat != nil && req.Lon != nil {
+		_, req.Timezone = resolveTimezoneForLatLon(*req.Lat, *req.Lon)
+	}
+
+	uc := h.prediction(c)
+	stream, err := uc.NewHeightStream(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrOutOfCoverage) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, errorBody(err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering (nginx).
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	first := true
+	c.Stream(func(io.Writer) bool {
+		if !first {
+			select {
+			case <-ticker.C:
+			case <-c.Request.Context().Done():
+				return false
+			}
+		}
+		first = false
+		point, err := stream.At(uc.Clock().Now())
+		if err != nil {
+			c.SSEvent("error", gin.H{"error": err.Error()})
+			return false
+		}
+		c.SSEvent("height", point)
+		return true
+	})
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"go.ngs.io/tides-api/internal/usecase"
+)
+
+const (
+	// apiKeyHeader carries the tenant API key.
+	apiKeyHeader = "X-API-Key"
+	// tenantContextKey stores the resolved tenant in the gin context.
+	tenantContextKey = "tenant"
+)
+
+// Tenant is a dataset scope (data directories, overrides) served by this deployment.
+type Tenant struct {
+	Name       string
+	Prediction *usecase.PredictionUseCase
+}
+
+// TenantResolver selects a tenant by API key, then by Host header, falling
+// back to the default tenant.
+type TenantResolver struct {
+	def    *Tenant
+	byKey  map[string]*Tenant
+	byHost map[string]*Tenant
+}
+
+// NewTenantResolver creates a resolver with the given default tenant.
+func NewTenantResolver(def *Tenant) *TenantResolver {
+	return &TenantResolver{
+		def:    def,
+		byKey:  make(map[string]*Tenant),
+		byHost: make(map[string]*Tenant),
+	}
+}
+
+// Add registers a tenant for the given API keys and host names.
+func (r *TenantResolver) Add(t *Tenant, apiKeys, hosts []string) {
+	for _, k := range apiKeys {
+		r.byKey[k] = t
+	}
+	for _, h := range hosts {
+		r.byHost[strings.ToLower(h)] = t
+	}
+}
+
+// resolve returns the tenant for a request; ok is false for an unknown API key.
+func (r *TenantResolver) resolve(req *http.Request) (t *Tenant, ok bool) {
+	if key := req.Header.Get(apiKeyHeader); key != "" {
+		t, ok = r.byKey[key]
+		return t, ok
+	}
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if t, found := r.byHost[strings.ToLower(host)]; found {
+		return t, true
+	}
+	return r.def, true
+}
+
+// tenantMiddleware stores the resolved tenant in the context and rejects
+// unknown API keys, so tenants never fall back to another tenant's data.
+func tenantMiddleware(r *TenantResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, ok := r.resolve(c.Request)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unknown API key"})
+			return
+		}
+		c.Set(tenantContextKey, t)
+		c.Header("X-Tenant", t.Name)
+		c.Next()
+	}
+}
+
+// prediction returns the prediction use case of the request's tenant.
+func (h *Handler) prediction(c *gin.Context) *usecase.PredictionUseCase {
+	if v, ok := c.Get(tenantContextKey); ok {
+		if t, ok := v.(*Tenant); ok && t.Prediction != nil {
+			return t.Prediction
+		}
+	}
+	return h.predictionUC
+}
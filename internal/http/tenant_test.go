@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolver := NewTenantResolver(&Tenant{Name: "default"})
+	resolver.Add(&Tenant{Name: "port-authority"}, []string{"key-pa"}, []string{"Tides.Port.Example"})
+	resolver.Add(&Tenant{Name: "harbor"}, []string{"key-harbor"}, []string{"harbor.example"})
+	router := gin.New()
+	router.Use(tenantMiddleware(resolver))
+	router.GET("/", func(c *gin.Context) {
+		v, _ := c.Get(tenantContextKey)
+		tenant, _ := v.(*Tenant)
+		c.String(http.StatusOK, tenant.Name)
+	})
+
+	tests := []struct {
+		name, key, host string
+		status          int
+		tenant          string
+	}{
+		{"default", "", "api.example", http.StatusOK, "default"},
+		{"API key", "key-pa", "api.example", http.StatusOK, "port-authority"},
+		{"API key over host", "key-pa", "harbor.example", http.StatusOK, "port-authority"},
+		{"unknown API key", "nope", "harbor.example", http.StatusUnauthorized, ""},
+		{"host", "", "harbor.example", http.StatusOK, "harbor"},
+		{"host case", "", "HARBOR.Example", http.StatusOK, "harbor"},
+		{"host port", "", "tides.port.example:8443", http.StatusOK, "port-authority"},
+		{"IPv6 host", "", "[::1]:8080", http.StatusOK, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d %s, want %d", w.Code, w.Body, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			if w.Body.String() != tt.tenant || w.Header().Get("X-Tenant") != tt.tenant {
+				t.Errorf("tenant = %q, X-Tenant %q, want %q", w.Body, w.Header().Get("X-Tenant"), tt.tenant)
+			}
+		})
+	}
+}
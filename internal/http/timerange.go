@@ -0,0 +1,93 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRangeDays bounds the days query parameter; the point limit of the
+// interval applies as well.
+const maxRangeDays = 366
+
+// resolveTimeRange resolves the start, end and days query parameters of the
+// prediction endpoints in loc. start and end take RFC3339 times, dates
+// (midnight in loc), "now", "today" (midnight in loc) or signed offsets
+// such as -6h or +3d: start offsets count from now, end offsets from start.
+// days sets end to start plus whole days instead. Without start, the range
+// starts today, and without end or days it spans one day.
+func resolveTimeRange(startStr, endStr, daysStr string, now time.Time, loc *time.Location) (start, end time.Time, err error) {
+	if endStr != "" && daysStr != "" {
+		return start, end, errors.New("end and days are mutually exclusive")
+	}
+
+	start = midnight(now, loc)
+	if startStr != "" {
+		if start, err = parseTimeBound(startStr, now, now, loc); err != nil {
+			return start, end, fmt.Errorf("invalid start time (expected RFC3339, a date, now, today or an offset like -6h): %w", err)
+		}
+	}
+
+	switch {
+	case daysStr != "":
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > maxRangeDays {
+			return start, end, fmt.Errorf("days must be an integer from 1 to %d", maxRangeDays)
+		}
+		end = start.In(loc).AddDate(0, 0, days)
+	case endStr != "":
+		if end, err = parseTimeBound(endStr, start, now, loc); err != nil {
+			return start, end, fmt.Errorf("invalid end time (expected RFC3339, a date, now, today or an offset like +48h): %w", err)
+		}
+	case startStr == "":
+		end = start.In(loc).AddDate(0, 0, 1)
+	default:
+		return start, end, errors.New("end parameter is required (or days)")
+	}
+	return start.UTC(), end.UTC(), nil
+}
+
+// parseTimeBound parses one bound of a time range. Signed offsets count
+// from anchor; "now" and "today" from now. Spaces are read as "+", which
+// unescaped in a query string (end=+48h, or a +09:00 zone) decodes to.
+func parseTimeBound(s string, anchor, now time.Time, loc *time.Location) (time.Time, error) {
+	s = strings.ReplaceAll(s, " ", "+")
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return midnight(now, loc), nil
+	}
+	if s[0] == '+' || s[0] == '-' {
+		return addOffset(anchor, s, loc)
+	}
+	if d, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return d, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// addOffset adds a signed offset to t: whole days ("+3d", calendar days
+// in loc) or a Go duration ("-6h", "+90m").
+func addOffset(t time.Time, offset string, loc *time.Location) (time.Time, error) {
+	if days, ok := strings.CutSuffix(offset, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return t, fmt.Errorf("invalid offset %q", offset)
+		}
+		return t.In(loc).AddDate(0, 0, n), nil
+	}
+	d, err := time.ParseDuration(offset)
+	if err != nil {
+		return t, fmt.Errorf("invalid offset %q", offset)
+	}
+	return t.Add(d), nil
+}
+
+// midnight returns the start of the day of t in loc.
+func midnight(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestResolveTimeRange(t *testing.T) {
+	jst := time.FixedZone("JST", 9*3600)
+	now := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	at := func(s string) time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm.UTC()
+	}
+
+	tests := []struct {
+		name             string
+		start, end, days string
+		loc              *time.Location
+		wantStart        time.Time
+		wantEnd          time.Time
+	}{
+		{"default day", "", "", "", time.UTC, at("2025-06-01T00:00:00Z"), at("2025-06-02T00:00:00Z")},
+		{"default day in JST", "", "", "", jst, at("2025-05-31T15:00:00Z"), at("2025-06-01T15:00:00Z")},
+		{"now plus hours", "now", "+48h", "", time.UTC, now, now.Add(48 * time.Hour)},
+		{"offsets", "-6h", "+3d", "", time.UTC, now.Add(-6 * time.Hour), now.Add(66 * time.Hour)},
+		{"dates", "2025-01-01", "2025-01-03", "", jst, at("2024-12-31T15:00:00Z"), at("2025-01-02T15:00:00Z")},
+		{"RFC3339", "2025-01-01T00:00:00Z", "2025-01-01T06:00:00Z", "", time.UTC, at("2025-01-01T00:00:00Z"), at("2025-01-01T06:00:00Z")},
+		{"today and days", "today", "", "2", jst, at("2025-05-31T15:00:00Z"), at("2025-06-02T15:00:00Z")},
+	}
+	for _, tt := range tests {
+		start, end, err := resolveTimeRange(tt.start, tt.end, tt.days, now, tt.loc)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+			t.Errorf("%s: range %s to %s, want %s to %s", tt.name, start, end, tt.wantStart, tt.wantEnd)
+		}
+	}
+}
+
+func TestResolveTimeRange_UnescapedPlus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	q, err := url.ParseQuery("start=2025-06-01T09:00:00+09:00&end=+48h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, end, err := resolveTimeRange(q.Get("start"), q.Get("end"), "", now, time.UTC)
+	if err != nil {
+		t.Fatalf("resolveTimeRange(%q, %q): %v", q.Get("start"), q.Get("end"), err)
+	}
+	if want := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC); !start.Equal(want) || !end.Equal(want.Add(48*time.Hour)) {
+		t.Errorf("range %s to %s, want 48h from %s", start, end, want)
+	}
+}
+
+func TestResolveTimeRange_Errors(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range []struct{ start, end, days string }{
+		{"now", "+1d", "1"},
+		{"2025-06-01T00:00:00Z", "", ""},
+		{"yesterday", "", ""},
+		{"now", "+2x", ""},
+		{"now", "", "0"},
+		{"now", "", "367"},
+	} {
+		if _, _, err := resolveTimeRange(tt.start, tt.end, tt.days, now, time.UTC); err == nil {
+			t.Errorf("resolveTimeRange(%q, %q, %q): expected an error", tt.start, tt.end, tt.days)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryParam is a query parameter of an operation of the OpenAPI spec.
+type queryParam struct {
+	Name    string
+	Type    string   // "string", "number", "integer" or "boolean".
+	Enum    []string // Allowed values, matched case-insensitively; nil allows any.
+	Minimum *float64
+	Maximum *float64
+}
+
+// specParameter is a parameter object of the spec, or a reference to one.
+type specParameter struct {
+	Ref    string `json:"$ref"`
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Schema struct {
+		Type    string   `json:"type"`
+		Enum    []string `json:"enum"`
+		Minimum *float64 `json:"minimum"`
+		Maximum *float64 `json:"maximum"`
+	} `json:"schema"`
+}
+
+// specQueryParams reads the query parameters of each operation of an
+// OpenAPI spec, keyed by method and gin route (see specRoute).
+func specQueryParams(spec []byte) (map[string][]queryParam, error) {
+	var doc struct {
+		Paths      map[string]map[string]struct{ Parameters []specParameter } `json:"paths"`
+		Components struct {
+			Parameters map[string]specParameter `json:"parameters"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+	ops := make(map[string][]queryParam)
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			var params []queryParam
+			for _, p := range op.Parameters {
+				if p.Ref != "" {
+					name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+					resolved, ok := doc.Components.Parameters[name]
+					if !ok {
+						return nil, fmt.Errorf("%s %s: unknown parameter %s", method, path, p.Ref)
+					}
+					p = resolved
+				}
+				if p.In != "query" {
+					continue
+				}
+				params = append(params, queryParam{
+					Name:    p.Name,
+					Type:    p.Schema.Type,
+					Enum:    p.Schema.Enum,
+					Minimum: p.Schema.Minimum,
+					Maximum: p.Schema.Maximum,
+				})
+			}
+			ops[strings.ToUpper(method)+" "+specRoute(path)] = params
+		}
+	}
+	return ops, nil
+}
+
+// specRoute converts a spec path to the gin route it is served by: path
+// parameters {name} become :name. Custom methods, as in
+// /v1/tides/predictions:batch, are kept as they are (see customMethod).
+func specRoute(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			segments[i] = ":" + s[1:len(s)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// validateQuery rejects requests whose query parameters do not match the
+// type, enum or bounds the spec declares for their operation. Parameters
+// the spec does not declare are left to the handlers.
+func validateQuery(ops map[string][]queryParam) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params, ok := ops[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+		query := c.Request.URL.Query()
+		for _, p := range params {
+			for _, value := range query[p.Name] {
+				if err := p.check(value); err != nil {
+					c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid %s: %v", p.Name, err)})
+					return
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// check validates a value of the parameter. Empty values are treated as
+// absent, as by the handlers.
+func (p queryParam) check(value string) error {
+	if value == "" {
+		return nil
+	}
+	var number float64
+	switch p.Type {
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		number = float64(n)
+	case "number":
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		number = x
+	case "boolean":
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		return nil
+	}
+	if p.Minimum != nil && number < *p.Minimum {
+		return fmt.Errorf("%s is below %g", value, *p.Minimum)
+	}
+	if p.Maximum != nil && number > *p.Maximum {
+		return fmt.Errorf("%s is above %g", value, *p.Maximum)
+	}
+	if p.Enum != nil {
+		for _, allowed := range p.Enum {
+			if strings.EqualFold(value, allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(p.Enum, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func float(x float64) *float64 { return &x }
+
+func TestQueryParamCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		param queryParam
+		value string
+		ok    bool
+	}{
+		{"empty integer", queryParam{Type: "integer"}, "", true},
+		{"empty enum", queryParam{Type: "string", Enum: []string{"a"}}, "", true},
+		{"integer", queryParam{Type: "integer"}, "12", true},
+		{"integer fraction", queryParam{Type: "integer"}, "1.5", false},
+		{"integer text", queryParam{Type: "integer"}, "ten", false},
+		{"number", queryParam{Type: "number"}, "-35.5", true},
+		{"number text", queryParam{Type: "number"}, "north", false},
+		{"boolean true", queryParam{Type: "boolean"}, "true", true},
+		{"boolean false", queryParam{Type: "boolean"}, "false", true},
+		{"boolean 1", queryParam{Type: "boolean"}, "1", false},
+		{"boolean uppercase", queryParam{Type: "boolean"}, "TRUE", false},
+		{"enum", queryParam{Type: "string", Enum: []string{"MSL", "LAT"}}, "LAT", true},
+		{"enum case", queryParam{Type: "string", Enum: []string{"MSL", "LAT"}}, "lat", true},
+		{"enum other", queryParam{Type: "string", Enum: []string{"MSL", "LAT"}}, "MLLW", false},
+		{"string", queryParam{Type: "string"}, "anything", true},
+		{"minimum", queryParam{Type: "number", Minimum: float(-90)}, "-90", true},
+		{"below minimum", queryParam{Type: "number", Minimum: float(-90)}, "-90.1", false},
+		{"maximum", queryParam{Type: "integer", Maximum: float(10)}, "10", true},
+		{"above maximum", queryParam{Type: "integer", Maximum: float(10)}, "11", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.param.check(tt.value)
+			if (err == nil) != tt.ok {
+				t.Errorf("check(%q) = %v, want ok=%v", tt.value, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSpecQueryParams(t *testing.T) {
+	spec := []byte(`{
+  "paths": {
+    "/v1/things/{id}": {
+      "get": {
+        "parameters": [
+          {"$ref": "#/components/parameters/limit"},
+          {"name": "id", "in": "path", "schema": {"type": "string"}},
+          {"name": "kind", "in": "query", "schema": {"type": "string", "enum": ["a", "b"]}}
+        ]
+      }
+    },
+    "/v1/things:batch": {"post": {"parameters": []}}
+  },
+  "components": {
+    "parameters": {
+      "limit": {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 100}}
+    }
+  }
+}`)
+	ops, err := specQueryParams(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, ok := ops["GET /v1/things/:id"]
+	if !ok {
+		t.Fatalf("GET /v1/things/:id missing from %v", ops)
+	}
+	if len(params) != 2 {
+		t.Fatalf("params = %+v, want limit and kind (path parameters left out)", params)
+	}
+	limit := params[0]
+	if limit.Name != "limit" || limit.Type != "integer" || limit.Minimum == nil || *limit.Minimum != 1 || limit.Maximum == nil || *limit.Maximum != 100 {
+		t.Errorf("resolved $ref = %+v", limit)
+	}
+	if kind := params[1]; kind.Name != "kind" || len(kind.Enum) != 2 {
+		t.Errorf("kind = %+v", kind)
+	}
+	if _, ok := ops["POST /v1/things:batch"]; !ok {
+		t.Errorf("POST /v1/things:batch missing from %v", ops)
+	}
+
+	if _, err := specQueryParams([]byte(`{"paths": {"/v1/x": {"get": {"parameters": [{"$ref": "#/components/parameters/nope"}]}}}}`)); err == nil {
+		t.Error("unknown $ref accepted")
+	}
+}
+
+func TestSpecRoute(t *testing.T) {
+	tests := map[string]string{
+		"/v1/tides/predictions":             "/v1/tides/predictions",
+		"/v1/tides/predictions:batch":       "/v1/tides/predictions:batch",
+		"/v1/constituents/{name}/coverage":  "/v1/constituents/:name/coverage",
+		"/v1/constituents/{name}:summarize": "/v1/constituents/{name}:summarize",
+	}
+	for path, want := range tests {
+		if got := specRoute(path); got != want {
+			t.Errorf("specRoute(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestValidateQuery(t *testing.T) {
+	router := newTestRouter(time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		target  string
+		invalid string // Parameter the validator rejects, if any.
+	}{
+		{"/v1/tides/predictions?lat=35&lon=139", ""},
+		{"/v1/tides/predictions?lat=north&lon=139", "lat"},
+		{"/v1/tides/predictions?lat=35&lon=139&units=yards", "units"},
+		{"/v1/tides/predictions?lat=35&lon=139&units=", ""},
+		{"/v1/tides/predictions?lat=35&lon=139&timezone=mars", "timezone"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if tt.invalid == "" && w.Code != http.StatusOK {
+			t.Errorf("GET %s = %d %s, want 200", tt.target, w.Code, w.Body)
+		}
+		if tt.invalid != "" && (w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"invalid `+tt.invalid+`: `)) {
+			t.Errorf("GET %s = %d %s, want 400 for %s", tt.target, w.Code, w.Body, tt.invalid)
+		}
+	}
+
+	// The batch custom method is looked up by the route it matched.
+	body := `[{"lat":35,"lon":139}]`
+	for target, status := range map[string]int{
+		"/v1/tides/predictions:batch?units=m":     http.StatusOK,
+		"/v1/tides/predictions:batch?units=yards": http.StatusBadRequest,
+		"/v1/tides/predictionsX?units=m":          http.StatusNotFound,
+	} {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != status {
+			t.Errorf("POST %s = %d %s, want %d", target, w.Code, w.Body, status)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetWindowsDays(t *testing.T) {
+	router := newTestRouter(time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC))
+	base := "/v1/tides/windows?lat=35&lon=139&min_height_m=0&duration=1h&days="
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+"14", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("14 days = %d %s, want 200", w.Code, w.Body)
+	}
+	// 200000 days overflow a time.Duration.
+	for _, days := range []string{"15", "200000", "9223372036854775807"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, base+days, nil))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "at most 14") {
+			t.Errorf("%s days = %d %s, want 400 at most 14", days, w.Code, w.Body)
+		}
+	}
+}
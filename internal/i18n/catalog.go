@@ -0,0 +1,195 @@
+// Package i18n provides localized message catalogs for API responses.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported language tags.
+const (
+	English  = "en"
+	Japanese = "ja"
+)
+
+// DefaultLanguage is used when no supported language is requested.
+const DefaultLanguage = English
+
+// catalogs maps language tag -> message key -> localized text.
+//
+//nolint:gochecknoglobals // Intentional: Read-only message catalog.
+var catalogs = map[string]map[string]string{
+	English: {
+		"constituent.M2":   "Principal lunar semidiurnal",
+		"constituent.S2":   "Principal solar semidiurnal",
+		"constituent.N2":   "Larger lunar elliptic semidiurnal",
+		"constituent.K2":   "Lunisolar semidiurnal",
+		"constituent.2N2":  "Lunar elliptic semidiurnal, second order",
+		"constituent.MU2":  "Variational semidiurnal",
+		"constituent.NU2":  "Larger lunar evectional",
+		"constituent.L2":   "Smaller lunar elliptic semidiurnal",
+		"constituent.LAM2": "Smaller lunar evectional",
+		"constituent.EPS2": "Lunar semidiurnal (evection and variation)",
+		"constituent.T2":   "Larger solar elliptic semidiurnal",
+		"constituent.R2":   "Smaller solar elliptic semidiurnal",
+		"constituent.K1":   "Lunisolar diurnal",
+		"constituent.O1":   "Lunar diurnal",
+		"constituent.P1":   "Solar diurnal",
+		"constituent.Q1":   "Larger lunar elliptic diurnal",
+		"constituent.J1":   "Smaller lunar elliptic diurnal",
+		"constituent.M1":   "Lunar diurnal (NOAA M1)",
+		"constituent.OO1":  "Lunar diurnal, second order",
+		"constituent.S1":   "Solar diurnal (radiational)",
+		"constituent.M4":   "Shallow water overtide of M2",
+		"constituent.M6":   "Shallow water overtide of M2",
+		"constituent.MK3":  "Shallow water terdiurnal",
+		"constituent.S4":   "Shallow water overtide of S2",
+		"constituent.MN4":  "Shallow water quarter diurnal",
+		"constituent.MS4":  "Shallow water quarter diurnal",
+		"constituent.M3":   "Lunar terdiurnal",
+		"constituent.N4":   "Shallow water overtide of N2",
+		"constituent.M8":   "Shallow water overtide of M2",
+		"constituent.MKS2": "Shallow water semidiurnal",
+		"constituent.Mf":   "Lunisolar fortnightly",
+		"constituent.Mm":   "Lunar monthly",
+		"constituent.Ssa":  "Solar semiannual",
+		"constituent.Sa":   "Solar annual",
+		"constituent.MSf":  "Lunisolar synodic fortnightly",
+		"constituent.Mtm":  "Lunar termensual",
+		"constituent.MSqm": "Lunisolar quarter-monthly",
+		"constituent.Node": "Lunar nodal (18.6-year)",
+
+		"datum.egm2008":                 "EGM2008",
+		"datum.egm2008_geoid_corrected": "EGM2008 (geoid-corrected)",
+
+		"source.gebco_2025": "GEBCO 2025",
+		"source.dtu21_mss":  "DTU21 MSS",
+	},
+	Japanese: {
+		"constituent.M2":   "主太陰半日周潮",
+		"constituent.S2":   "主太陽半日周潮",
+		"constituent.N2":   "主太陰楕円潮",
+		"constituent.K2":   "日月合成半日周潮",
+		"constituent.2N2":  "2次太陰楕円潮",
+		"constituent.MU2":  "太陰二均差潮",
+		"constituent.NU2":  "主太陰出差潮",
+		"constituent.L2":   "副太陰楕円潮",
+		"constituent.LAM2": "副太陰出差潮",
+		"constituent.EPS2": "太陰出差・二均差潮",
+		"constituent.T2":   "主太陽楕円潮",
+		"constituent.R2":   "副太陽楕円潮",
+		"constituent.K1":   "日月合成日周潮",
+		"constituent.O1":   "主太陰日周潮",
+		"constituent.P1":   "主太陽日周潮",
+		"constituent.Q1":   "主太陰楕円日周潮",
+		"constituent.J1":   "副太陰楕円日周潮",
+		"constituent.M1":   "太陰日周潮（M1）",
+		"constituent.OO1":  "2次太陰日周潮",
+		"constituent.S1":   "太陽日周潮（放射潮）",
+		"constituent.M4":   "M2の倍潮（浅海分潮）",
+		"constituent.M6":   "M2の3倍潮（浅海分潮）",
+		"constituent.MK3":  "浅海1/3日周潮",
+		"constituent.S4":   "S2の倍潮（浅海分潮）",
+		"constituent.MN4":  "浅海1/4日周潮",
+		"constituent.MS4":  "浅海1/4日周潮",
+		"constituent.M3":   "太陰1/3日周潮",
+		"constituent.N4":   "N2の倍潮（浅海分潮）",
+		"constituent.M8":   "M2の4倍潮（浅海分潮）",
+		"constituent.MKS2": "浅海半日周潮",
+		"constituent.Mf":   "日月合成半月周潮",
+		"constituent.Mm":   "太陰月周潮",
+		"constituent.Ssa":  "太陽半年周潮",
+		"constituent.Sa":   "太陽年周潮",
+		"constituent.MSf":  "日月合成朔望半月周潮",
+		"constituent.Mtm":  "太陰1/3月周潮",
+		"constituent.MSqm": "日月合成1/4月周潮",
+		"constituent.Node": "月交点周期潮（18.6年）",
+
+		"datum.egm2008":                 "EGM2008",
+		"datum.egm2008_geoid_corrected": "EGM2008（ジオイド補正済み）",
+
+		"source.gebco_2025": "GEBCO 2025",
+		"source.dtu21_mss":  "DTU21 平均海面高",
+	},
+}
+
+// T returns the localized message for key in lang.
+// Falls back to the default language, then to an empty string.
+func T(lang, key string) string {
+	if msgs, ok := catalogs[lang]; ok {
+		if msg, ok := msgs[key]; ok {
+			return msg
+		}
+	}
+	return catalogs[DefaultLanguage][key]
+}
+
+// ConstituentDescription returns the localized description of a constituent.
+func ConstituentDescription(lang, name string) string {
+	return T(lang, "constituent."+name)
+}
+
+// DatumLabel returns the localized label of a datum code, or the code
+// itself when the catalog has no label for it.
+func DatumLabel(lang, code string) string {
+	if label := T(lang, "datum."+code); label != "" {
+		return label
+	}
+	return code
+}
+
+// SourcesLabel returns the localized labels of source codes joined with " + ".
+func SourcesLabel(lang string, codes []string) string {
+	labels := make([]string, len(codes))
+	for i, code := range codes {
+		if labels[i] = T(lang, "source."+code); labels[i] == "" {
+			labels[i] = code
+		}
+	}
+	return strings.Join(labels, " + ")
+}
+
+// Supported reports whether lang has a message catalog.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// FromAcceptLanguage selects the best supported language from an
+// Accept-Language header value (e.g., "ja-JP,ja;q=0.9,en;q=0.8").
+func FromAcceptLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	candidates := make([]candidate, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		// Reduce region subtags (e.g., "ja-JP" -> "ja").
+		if idx := strings.IndexAny(tag, "-_"); idx > 0 {
+			tag = tag[:idx]
+		}
+		if q > 0 && Supported(tag) {
+			candidates = append(candidates, candidate{lang: tag, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
@@ -0,0 +1,52 @@
+package i18n
+
+import "testing"
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", English},
+		{"ja", Japanese},
+		{"ja-JP,ja;q=0.9,en;q=0.8", Japanese},
+		{"en-US,en;q=0.9,ja;q=0.5", English},
+		{"fr-FR,ja;q=0.7", Japanese},
+		{"en;q=0.3,ja;q=0.8", Japanese},
+		{"de,fr", English},
+		{"ja;q=0", English},
+	}
+
+	for _, tt := range tests {
+		if got := FromAcceptLanguage(tt.header); got != tt.expected {
+			t.Errorf("FromAcceptLanguage(%q): expected %s, got %s", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestConstituentDescriptionFallsBackToEnglish(t *testing.T) {
+	if got := ConstituentDescription(Japanese, "M2"); got != "主太陰半日周潮" {
+		t.Errorf("expected Japanese description for M2, got %q", got)
+	}
+	if got := ConstituentDescription("fr", "S2"); got != "Principal solar semidiurnal" {
+		t.Errorf("expected English fallback for S2, got %q", got)
+	}
+	if got := ConstituentDescription(Japanese, "UNKNOWN"); got != "" {
+		t.Errorf("expected empty description for unknown constituent, got %q", got)
+	}
+}
+
+func TestDatumAndSourceLabels(t *testing.T) {
+	if got := DatumLabel(Japanese, "egm2008_geoid_corrected"); got != "EGM2008（ジオイド補正済み）" {
+		t.Errorf("unexpected Japanese datum label %q", got)
+	}
+	if got := DatumLabel(English, "custom_datum"); got != "custom_datum" {
+		t.Errorf("expected unknown datum code as label, got %q", got)
+	}
+	if got := SourcesLabel(English, []string{"gebco_2025", "dtu21_mss"}); got != "GEBCO 2025 + DTU21 MSS" {
+		t.Errorf("unexpected sources label %q", got)
+	}
+	if got := SourcesLabel(Japanese, nil); got != "" {
+		t.Errorf("expected empty label without sources, got %q", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +28,31 @@ type HourlyRecord struct {
 	Valid   [24]bool
 }
 
+// Observation is a single valid hourly height.
+type Observation struct {
+	Time    time.Time // UTC.
+	HeightM float64
+}
+
+// Observations flattens records into time-ordered valid hourly observations.
+// Hours marked missing (999) are skipped.
+func Observations(records []HourlyRecord) []Observation {
+	out := make([]Observation, 0, len(records)*24)
+	for _, rec := range records {
+		for hour := 0; hour < 24; hour++ {
+			if !rec.Valid[hour] {
+				continue
+			}
+			out = append(out, Observation{
+				Time:    rec.Time.Add(time.Duration(hour) * time.Hour).UTC(),
+				HeightM: rec.Hourly[hour],
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
+
 // ParseHourlyLine parses a single fixed-width JMA line into an HourlyRecord.
 func ParseHourlyLine(line string) (*HourlyRecord, error) {
 	if len(line) < 80 {
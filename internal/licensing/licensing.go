@@ -0,0 +1,148 @@
+// Package licensing declares the attribution and usage terms of the
+// datasets the server serves data from.
+package licensing
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Dataset identifiers. Those of location metadata datasets are their
+// domain.SourceCode values.
+const (
+	FES         = "fes"
+	FESCurrents = "fes_currents"
+	TPXO        = "tpxo"
+	CSV         = "csv"
+	Custom      = "custom"
+	GEBCO2025   = "gebco_2025"
+	DTU21MSS    = "dtu21_mss"
+)
+
+// AcceptanceEnv is the configuration that acknowledges dataset licenses.
+const AcceptanceEnv = "DATASET_LICENSES_ACCEPTED"
+
+// ErrNotAccepted is returned for datasets whose license must be accepted
+// before they are loaded.
+var ErrNotAccepted = errors.New("license not accepted")
+
+// License is the attribution and usage terms of a dataset.
+type License struct {
+	Dataset string `json:"dataset"`
+	Name    string `json:"name"`
+	// Attribution is the credit responses carry when they use the dataset.
+	Attribution string `json:"attribution"`
+	Terms       string `json:"terms,omitempty"`
+	URL         string `json:"url,omitempty"`
+	// RequiresAcceptance marks datasets that are loaded only once their
+	// license is acknowledged in the configuration.
+	RequiresAcceptance bool `json:"requires_acceptance"`
+}
+
+// licenses is the license of each dataset.
+//
+//nolint:gochecknoglobals // Intentional: Read-only license catalog.
+var licenses = map[string]License{
+	FES: {
+		Dataset:     FES,
+		Name:        "FES2014/2022 tidal model",
+		Attribution: "FES2014/2022 tidal model, produced by NOVELTIS, LEGOS and CLS and distributed by AVISO+ with support from CNES",
+		Terms:       "Use is subject to the AVISO+ license agreement accepted at registration.",
+		URL:         "https://www.aviso.altimetry.fr/",
+	},
+	FESCurrents: {
+		Dataset:     FESCurrents,
+		Name:        "FES2014/2022 tidal currents",
+		Attribution: "FES2014/2022 tidal model (tidal currents), produced by NOVELTIS, LEGOS and CLS and distributed by AVISO+ with support from CNES",
+		Terms:       "Use is subject to the AVISO+ license agreement accepted at registration.",
+		URL:         "https://www.aviso.altimetry.fr/",
+	},
+	TPXO: {
+		Dataset:            TPXO,
+		Name:               "TPXO9-atlas tidal model",
+		Attribution:        "TPXO9-atlas tidal model (Egbert & Erofeeva, Oregon State University)",
+		Terms:              "Free for academic and non-commercial use; commercial use requires a license from Oregon State University.",
+		URL:                "https://www.tpxo.net/",
+		RequiresAcceptance: true,
+	},
+	CSV: {
+		Dataset:     CSV,
+		Name:        "Mock CSV station constituents",
+		Attribution: "Mock CSV (for dev). Replace with FES later.",
+	},
+	Custom: {
+		Dataset:     Custom,
+		Name:        "Client-supplied constituents",
+		Attribution: "Client-supplied constituents (not stored).",
+	},
+	GEBCO2025: {
+		Dataset:     GEBCO2025,
+		Name:        "GEBCO 2025 Grid",
+		Attribution: "GEBCO Compilation Group (2025) GEBCO 2025 Grid",
+		Terms:       "Free to use, copy and adapt with acknowledgement of the source.",
+		URL:         "https://www.gebco.net/",
+	},
+	DTU21MSS: {
+		Dataset:     DTU21MSS,
+		Name:        "DTU21 Mean Sea Surface",
+		Attribution: "DTU21 Mean Sea Surface (DTU Space)",
+	},
+}
+
+// Lookup returns the license of a dataset.
+func Lookup(dataset string) (License, bool) {
+	l, ok := licenses[dataset]
+	return l, ok
+}
+
+// Datasets returns the identifiers of the datasets with a declared license.
+func Datasets() []string {
+	return slices.Sorted(maps.Keys(licenses))
+}
+
+// Attribution returns the attributions of datasets, in order and once
+// each, for response metadata. Datasets without a license are skipped.
+func Attribution(datasets ...string) string {
+	var parts []string
+	for _, d := range datasets {
+		l, ok := licenses[d]
+		if !ok || slices.Contains(parts, l.Attribution) {
+			continue
+		}
+		parts = append(parts, l.Attribution)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Acceptance is the set of datasets whose license has been acknowledged.
+type Acceptance map[string]bool
+
+// ParseAcceptance parses a comma-separated list of dataset identifiers
+// (e.g., "tpxo").
+func ParseAcceptance(s string) (Acceptance, error) {
+	accepted := make(Acceptance)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if _, ok := licenses[field]; !ok {
+			return nil, fmt.Errorf("unknown dataset %q (expected one of %s)", field, strings.Join(Datasets(), ", "))
+		}
+		accepted[field] = true
+	}
+	return accepted, nil
+}
+
+// Check returns ErrNotAccepted if dataset requires license acceptance and
+// it has not been given.
+func (a Acceptance) Check(dataset string) error {
+	l, ok := licenses[dataset]
+	if !ok || !l.RequiresAcceptance || a[dataset] {
+		return nil
+	}
+	return fmt.Errorf("%s: %w (%s; review %s and add %s to %s)", l.Name, ErrNotAccepted, strings.TrimSuffix(l.Terms, "."), l.URL, dataset, AcceptanceEnv)
+}
@@ -0,0 +1,39 @@
+package licensing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAttribution(t *testing.T) {
+	got := Attribution(TPXO, FES, "unknown", GEBCO2025, FES)
+	parts := strings.Split(got, "; ")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "TPXO9-atlas") || !strings.HasPrefix(parts[1], "FES2014/2022") || !strings.HasPrefix(parts[2], "GEBCO") {
+		t.Errorf("Attribution = %q, want TPXO, FES and GEBCO credits once each, in order", got)
+	}
+	if got := Attribution(); got != "" {
+		t.Errorf("Attribution() = %q, want empty", got)
+	}
+}
+
+func TestAcceptance(t *testing.T) {
+	none, err := ParseAcceptance("")
+	if err != nil {
+		t.Fatalf("ParseAcceptance: %v", err)
+	}
+	if err := none.Check(TPXO); !errors.Is(err, ErrNotAccepted) {
+		t.Errorf("TPXO without acceptance: got %v, want ErrNotAccepted", err)
+	}
+	if err := none.Check(FES); err != nil {
+		t.Errorf("FES: %v", err)
+	}
+
+	accepted, err := ParseAcceptance(" TPXO ,")
+	if err != nil || accepted.Check(TPXO) != nil {
+		t.Errorf("TPXO accepted: %v, %v", err, accepted.Check(TPXO))
+	}
+	if _, err := ParseAcceptance("tpxo,fes2030"); err == nil {
+		t.Error("unknown dataset: expected an error")
+	}
+}
@@ -0,0 +1,53 @@
+// Package metrics counts requests, dataset reads and lookups for
+// Prometheus scraping. The metrics are collected whether or not they are
+// exposed; collection costs an atomic add or a short lock per event.
+package metrics
+
+import "time"
+
+// Bucket upper bounds.
+//
+//nolint:gochecknoglobals // Intentional: Read-only bucket layouts.
+var (
+	// DurationBuckets spans cached lookups (1 ms) to cold reads from a
+	// FUSE-mounted bucket (10 s), in seconds.
+	DurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	// ConstituentBuckets spans single constituents to the full FES2014 set.
+	ConstituentBuckets = []float64{1, 4, 8, 13, 16, 22, 26, 34, 38}
+)
+
+// Metrics of the default registry.
+//
+//nolint:gochecknoglobals // Intentional: shared by all instrumented packages.
+var (
+	HTTPRequests = defaultRegistry.NewCounterVec("tides_http_requests_total",
+		"HTTP requests served, by method, route and status.", "method", "route", "status")
+	HTTPRequestDuration = defaultRegistry.NewHistogramVec("tides_http_request_duration_seconds",
+		"Time to serve HTTP requests, by route.", DurationBuckets, "route")
+	PredictionConstituents = defaultRegistry.NewHistogramVec("tides_prediction_constituents",
+		"Constituents synthesized per prediction response, by source.", ConstituentBuckets, "source")
+	NetCDFReadDuration = defaultRegistry.NewHistogramVec("tides_netcdf_read_duration_seconds",
+		"Time to open and read NetCDF files, retries included, by dataset and result.", DurationBuckets, "dataset", "result")
+	InterpolationFailures = defaultRegistry.NewCounterVec("tides_interpolation_failures_total",
+		"Constituent interpolations that failed, by dataset and reason (read or no_data).", "dataset", "reason")
+	BathymetryLookups = defaultRegistry.NewCounterVec("tides_bathymetry_lookups_total",
+		"Bathymetry and mean sea surface lookups, by result (ok, no_data or degraded).", "result")
+)
+
+// Result labels.
+const (
+	ResultOK       = "ok"
+	ResultError    = "error"
+	ResultNoData   = "no_data"
+	ResultDegraded = "degraded"
+	ReasonRead     = "read"
+)
+
+// ObserveRead records the duration of a NetCDF read that started at start.
+func ObserveRead(dataset string, start time.Time, err error) {
+	result := ResultOK
+	if err != nil {
+		result = ResultError
+	}
+	NetCDFReadDuration.With(dataset, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,239 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry holds metrics and writes them in the Prometheus text format.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+// metric is a family of labeled series.
+type metric interface {
+	write(w io.Writer) error
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+//nolint:gochecknoglobals // Intentional: shared by all instrumented packages.
+var defaultRegistry = NewRegistry()
+
+// Default returns the registry of the metrics defined in this package.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+// (version 0.0.4), in order of registration.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := slices.Clone(r.metrics)
+	r.mu.Unlock()
+	for _, m := range metrics {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// family is the name, help and label names shared by the series of a metric.
+type family struct {
+	name   string
+	help   string
+	labels []string
+}
+
+// key identifies the series of label values.
+func (f family) key(values []string) string {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs formats label values, with extra pairs appended, as {a="x",...}.
+func (f family) labelPairs(values []string, extra ...string) string {
+	if len(f.labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(f.labels)+len(extra)/2)
+	for i, l := range f.labels {
+		pairs = append(pairs, l+`="`+escapeLabel(values[i])+`"`)
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, extra[i]+`="`+escapeLabel(extra[i+1])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (f family) header(w io.Writer, kind string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, kind)
+	return err
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	family
+	mu     sync.Mutex
+	series map[string]*Counter
+}
+
+// Counter is a monotonically increasing count.
+type Counter struct {
+	values []string
+	n      atomic.Int64
+}
+
+// NewCounterVec creates a counter with the given label names and registers it.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{family: family{name: name, help: help, labels: labels}, series: make(map[string]*Counter)}
+	r.register(v)
+	return v
+}
+
+// With returns the counter of the label values, given in label order.
+func (v *CounterVec) With(values ...string) *Counter {
+	key := v.key(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	c, ok := v.series[key]
+	if !ok {
+		c = &Counter{values: slices.Clone(values)}
+		v.series[key] = c
+	}
+	return c
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.n.Add(1)
+}
+
+// Add adds n (non-negative) to the counter.
+func (c *Counter) Add(n int) {
+	c.n.Add(int64(n))
+}
+
+func (v *CounterVec) write(w io.Writer) error {
+	if err := v.header(w, "counter"); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, key := range slices.Sorted(maps.Keys(v.series)) {
+		c := v.series[key]
+		if _, err := fmt.Fprintf(w, "%s%s %d\n", v.name, v.labelPairs(c.values), c.n.Load()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	family
+	buckets []float64 // Upper bounds, ascending.
+	mu      sync.Mutex
+	series  map[string]*Histogram
+}
+
+// Histogram counts observations in cumulative buckets.
+type Histogram struct {
+	values []string
+	bounds []float64 // Bucket upper bounds of the vector.
+	mu     sync.Mutex
+	counts []uint64 // Per bucket, not cumulative; the last is +Inf.
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram with the given bucket upper bounds
+// and label names and registers it.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	v := &HistogramVec{
+		family:  family{name: name, help: help, labels: labels},
+		buckets: slices.Sorted(slices.Values(buckets)),
+		series:  make(map[string]*Histogram),
+	}
+	r.register(v)
+	return v
+}
+
+// With returns the histogram of the label values, given in label order.
+func (v *HistogramVec) With(values ...string) *Histogram {
+	key := v.key(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	h, ok := v.series[key]
+	if !ok {
+		h = &Histogram{values: slices.Clone(values), bounds: v.buckets, counts: make([]uint64, len(v.buckets)+1)}
+		v.series[key] = h
+	}
+	return h
+}
+
+// Observe records a value.
+func (h *Histogram) Observe(x float64) {
+	i, _ := slices.BinarySearch(h.bounds, x) // The first bucket with x <= bound.
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += x
+	h.mu.Unlock()
+}
+
+func (v *HistogramVec) write(w io.Writer) error {
+	if err := v.header(w, "histogram"); err != nil {
+		return err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, key := range slices.Sorted(maps.Keys(v.series)) {
+		h := v.series[key]
+		h.mu.Lock()
+		counts, sum := slices.Clone(h.counts), h.sum
+		h.mu.Unlock()
+
+		var cumulative uint64
+		for i, n := range counts {
+			cumulative += n
+			le := "+Inf"
+			if i < len(v.buckets) {
+				le = formatFloat(v.buckets[i])
+			}
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", v.name, v.labelPairs(h.values, "le", le), cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+			v.name, v.labelPairs(h.values), formatFloat(sum), v.name, v.labelPairs(h.values), cumulative); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("test_requests_total", "Requests.", "route")
+	durations := r.NewHistogramVec("test_duration_seconds", "Durations.", []float64{1, 0.1}, "route")
+
+	requests.With("/v1/tides").Inc()
+	requests.With("/v1/tides").Add(2)
+	requests.With(`a"b`).Inc()
+	for _, x := range []float64{0.05, 0.1, 0.5, 3} {
+		durations.With("/v1/tides").Observe(x)
+	}
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	want := `# HELP test_requests_total Requests.
+# TYPE test_requests_total counter
+test_requests_total{route="/v1/tides"} 3
+test_requests_total{route="a\"b"} 1
+# HELP test_duration_seconds Durations.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{route="/v1/tides",le="0.1"} 2
+test_duration_seconds_bucket{route="/v1/tides",le="1"} 3
+test_duration_seconds_bucket{route="/v1/tides",le="+Inf"} 4
+test_duration_seconds_sum{route="/v1/tides"} 3.65
+test_duration_seconds_count{route="/v1/tides"} 4
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteText:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWithPanicsOnLabelCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	NewRegistry().NewCounterVec("test_total", "Test.", "a", "b").With("x")
+}
@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Node kinds, named as JSON Schema types.
+const (
+	kindObject = "object"
+	kindArray  = "array"
+	kindString = "string"
+	kindNumber = "number"
+	kindBool   = "boolean"
+	kindNull   = "null"
+)
+
+// node is a parsed JSON value with the byte offset where it starts.
+type node struct {
+	kind   string
+	offset int64
+	obj    []member
+	arr    []*node
+	str    string
+	num    float64
+	isInt  bool
+	b      bool
+}
+
+type member struct {
+	key    string
+	offset int64 // Offset of the key.
+	val    *node
+}
+
+func (n *node) hasType(types []string) bool {
+	for _, t := range types {
+		if t == n.kind || (t == "integer" && n.kind == kindNumber && n.isInt) {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns scalar values for enum comparison.
+func (n *node) value() any {
+	switch n.kind {
+	case kindString:
+		return n.str
+	case kindNumber:
+		return n.num
+	case kindBool:
+		return n.b
+	case kindNull:
+		return nil
+	default:
+		return n.kind
+	}
+}
+
+// parse decodes data into a node tree, recording value offsets.
+func parse(data []byte) (*node, error) {
+	p := &parser{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.UseNumber()
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, p.syntaxError(errors.New("unexpected data after top-level value"))
+	}
+	return root, nil
+}
+
+type parser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// start returns the offset of the next value, skipping separators.
+func (p *parser) start() int64 {
+	off := p.dec.InputOffset()
+	for off < int64(len(p.data)) {
+		switch p.data[off] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			off++
+		default:
+			return off
+		}
+	}
+	return off
+}
+
+func (p *parser) syntaxError(err error) error {
+	var se *json.SyntaxError
+	off := p.dec.InputOffset()
+	if errors.As(err, &se) {
+		off = se.Offset
+	}
+	line, col := position(p.data, off)
+	return fmt.Errorf("invalid JSON at %d:%d: %w", line, col, err)
+}
+
+func (p *parser) value() (*node, error) {
+	n := &node{offset: p.start()}
+	tok, err := p.dec.Token()
+	if err != nil {
+		return nil, p.syntaxError(err)
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			n.kind = kindObject
+			for p.dec.More() {
+				keyOffset := p.start()
+				keyTok, err := p.dec.Token()
+				if err != nil {
+					return nil, p.syntaxError(err)
+				}
+				key, _ := keyTok.(string)
+				val, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.obj = append(n.obj, member{key: key, offset: keyOffset, val: val})
+			}
+		case '[':
+			n.kind = kindArray
+			for p.dec.More() {
+				val, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.arr = append(n.arr, val)
+			}
+		}
+		// Closing delimiter.
+		if _, err := p.dec.Token(); err != nil {
+			return nil, p.syntaxError(err)
+		}
+	case string:
+		n.kind, n.str = kindString, t
+	case json.Number:
+		f, err := t.Float64()
+		if err != nil {
+			return nil, p.syntaxError(err)
+		}
+		_, intErr := t.Int64()
+		n.kind, n.num, n.isInt = kindNumber, f, intErr == nil
+	case bool:
+		n.kind, n.b = kindBool, t
+	case nil:
+		n.kind = kindNull
+	}
+	return n, nil
+}
@@ -0,0 +1,308 @@
+// Package schema validates the JSON data files (station overrides, datum
+// offsets and nodal coefficients) against the JSON Schemas in schemas/.
+//
+// The validator implements the subset of JSON Schema (2020-12) the bundled
+// schemas use: type, enum, required, properties, patternProperties,
+// additionalProperties, items, minItems, minLength, minimum, maximum and
+// local "#/$defs/..." references. Errors carry the JSON pointer and the
+// line and column of the offending value.
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Data file kinds.
+const (
+	StationOverrides = "jma_station_overrides"
+	DatumOffsets     = "jma_datum_offsets"
+	AstroCoeffs      = "astro_coeffs"
+)
+
+//go:embed schemas/*.schema.json
+var schemaFiles embed.FS
+
+// Kinds returns the data file kinds with a schema.
+func Kinds() []string {
+	return []string{StationOverrides, DatumOffsets, AstroCoeffs}
+}
+
+// Error is one schema violation.
+type Error struct {
+	Pointer string // JSON pointer of the offending value ("" for the document).
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e Error) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, pointer, e.Message)
+}
+
+// ValidationError lists all violations in a document.
+type ValidationError struct {
+	Kind   string
+	Errors []Error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid %s: %s", e.Kind, strings.Join(msgs, "; "))
+}
+
+// Validate checks a document of the given kind. It returns a
+// *ValidationError listing every violation, or a plain error when the
+// document is not JSON or the kind is unknown.
+func Validate(kind string, data []byte) error {
+	s, err := load(kind)
+	if err != nil {
+		return err
+	}
+	doc, err := parse(data)
+	if err != nil {
+		return err
+	}
+	v := &validator{root: s, data: data}
+	v.validate(s, doc, "")
+	if len(v.errs) > 0 {
+		return &ValidationError{Kind: kind, Errors: sortedErrors(v.errs)}
+	}
+	return nil
+}
+
+// ValidateFile reads and validates a data file.
+func ValidateFile(kind, path string) error {
+	//nolint:gosec // G304: Data file path from config or CLI flag.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return Validate(kind, data)
+}
+
+// schema is a compiled JSON Schema node.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 json.RawMessage    `json:"type"`
+	Enum                 []any              `json:"enum"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	PatternProperties    map[string]*schema `json:"patternProperties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	MinItems             *int               `json:"minItems"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	Defs                 map[string]*schema `json:"$defs"`
+
+	types      []string
+	patterns   map[*regexp.Regexp]*schema
+	additional *schema // nil: any additional property allowed.
+	noExtra    bool    // additionalProperties: false.
+}
+
+func load(kind string) (*schema, error) {
+	b, err := schemaFiles.ReadFile("schemas/" + kind + ".schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown data file kind %q", kind)
+	}
+	var s schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", kind, err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", kind, err)
+	}
+	return &s, nil
+}
+
+func (s *schema) compile() error {
+	if len(s.Type) > 0 {
+		if err := json.Unmarshal(s.Type, &s.types); err != nil {
+			var t string
+			if err := json.Unmarshal(s.Type, &t); err != nil {
+				return fmt.Errorf("invalid type: %s", s.Type)
+			}
+			s.types = []string{t}
+		}
+	}
+	if len(s.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil {
+			s.noExtra = !allowed
+		} else {
+			s.additional = &schema{}
+			if err := json.Unmarshal(s.AdditionalProperties, s.additional); err != nil {
+				return fmt.Errorf("invalid additionalProperties: %w", err)
+			}
+		}
+	}
+	if len(s.PatternProperties) > 0 {
+		s.patterns = make(map[*regexp.Regexp]*schema, len(s.PatternProperties))
+		for p, sub := range s.PatternProperties {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+			s.patterns[re] = sub
+		}
+	}
+
+	children := []*schema{s.Items, s.additional}
+	for _, m := range []map[string]*schema{s.Properties, s.PatternProperties, s.Defs} {
+		for _, sub := range m {
+			children = append(children, sub)
+		}
+	}
+	for _, sub := range children {
+		if sub != nil {
+			if err := sub.compile(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type validator struct {
+	root *schema
+	data []byte
+	errs []Error
+}
+
+func (v *validator) fail(n *node, pointer, format string, args ...any) {
+	line, col := position(v.data, n.offset)
+	v.errs = append(v.errs, Error{Pointer: pointer, Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) resolve(s *schema) *schema {
+	for s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		def := v.root.Defs[name]
+		if !ok || def == nil {
+			return &schema{}
+		}
+		s = def
+	}
+	return s
+}
+
+//nolint:gocyclo // One branch per supported keyword.
+func (v *validator) validate(s *schema, n *node, pointer string) {
+	s = v.resolve(s)
+
+	if len(s.types) > 0 && !n.hasType(s.types) {
+		v.fail(n, pointer, "expected %s, got %s", strings.Join(s.types, " or "), n.kind)
+		return
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(n.value()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(n, pointer, "value %v is not one of %v", n.value(), s.Enum)
+		}
+	}
+
+	switch n.kind {
+	case kindNumber:
+		if s.Minimum != nil && n.num < *s.Minimum {
+			v.fail(n, pointer, "%g is less than minimum %g", n.num, *s.Minimum)
+		}
+		if s.Maximum != nil && n.num > *s.Maximum {
+			v.fail(n, pointer, "%g is greater than maximum %g", n.num, *s.Maximum)
+		}
+	case kindString:
+		if s.MinLength != nil && len([]rune(n.str)) < *s.MinLength {
+			v.fail(n, pointer, "string is shorter than %d", *s.MinLength)
+		}
+	case kindArray:
+		if s.MinItems != nil && len(n.arr) < *s.MinItems {
+			v.fail(n, pointer, "array has fewer than %d items", *s.MinItems)
+		}
+		if s.Items != nil {
+			for i, item := range n.arr {
+				v.validate(s.Items, item, fmt.Sprintf("%s/%d", pointer, i))
+			}
+		}
+	case kindObject:
+		present := make(map[string]bool, len(n.obj))
+		for _, m := range n.obj {
+			present[m.key] = true
+		}
+		for _, r := range s.Required {
+			if !present[r] {
+				v.fail(n, pointer, "missing required property %q", r)
+			}
+		}
+		for _, m := range n.obj {
+			child := pointer + "/" + escapePointer(m.key)
+			matched := false
+			if sub, ok := s.Properties[m.key]; ok {
+				v.validate(sub, m.val, child)
+				matched = true
+			}
+			for re, sub := range s.patterns {
+				if re.MatchString(m.key) {
+					v.validate(sub, m.val, child)
+					matched = true
+				}
+			}
+			switch {
+			case matched:
+			case s.noExtra:
+				v.fail(&node{offset: m.offset}, child, "unknown property %q", m.key)
+			case s.additional != nil:
+				v.validate(s.additional, m.val, child)
+			}
+		}
+	case kindBool, kindNull:
+	}
+}
+
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// position converts a byte offset into a 1-based line and column.
+func position(data []byte, offset int64) (line, col int) {
+	line, col = 1, 1
+	for i := int64(0); i < offset && i < int64(len(data)); i++ {
+		if data[i] == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+// sortedErrors orders errors by position.
+func sortedErrors(errs []Error) []Error {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
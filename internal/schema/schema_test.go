@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate_DatumOffsets(t *testing.T) {
+	valid := `[{"name": "A0", "lat": 44.35, "lon": 143.37, "offset_m": 0.71}]`
+	if err := Validate(DatumOffsets, []byte(valid)); err != nil {
+		t.Fatalf("expected valid document, got %v", err)
+	}
+
+	invalid := `[
+  {"name": "A0", "lat": 44.35, "lon": 143.37, "offset_m": 0.71},
+  {"name": "A1", "lat": 133.9, "lon": 130.9, "offset": 1.2}
+]`
+	err := Validate(DatumOffsets, []byte(invalid))
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	want := []string{
+		`3:3: /1: missing required property "offset_m"`,
+		`3:25: /1/lat: 133.9 is greater than maximum 90`,
+		`3:46: /1/offset: unknown property "offset"`,
+	}
+	if len(verr.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), verr.Errors)
+	}
+	for i, w := range want {
+		if got := verr.Errors[i].Error(); got != w {
+			t.Errorf("error %d = %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestValidate_AstroCoeffs(t *testing.T) {
+	valid := `{"version": "1.0", "coeffs": [{"name": "M2", "f0": 1, "u_sin": {"1": -2.14}, "_nonlinear": null}]}`
+	if err := Validate(AstroCoeffs, []byte(valid)); err != nil {
+		t.Fatalf("expected valid document, got %v", err)
+	}
+
+	invalid := `{"version": "1.0", "coeffs": [{"name": "M2", "u_sin": {"one": "x"}}]}`
+	err := Validate(AstroCoeffs, []byte(invalid))
+	if err == nil || !strings.Contains(err.Error(), `/coeffs/0/u_sin/one: unknown property "one"`) {
+		t.Errorf("expected unknown harmonic key error, got %v", err)
+	}
+}
+
+func TestValidate_Overrides(t *testing.T) {
+	invalid := `[{"name": "A0", "lat": 44.3, "lon": 143.3, "constituents": [{"name": "M2", "amplitude_m": "0.1", "phase_deg": 110}]}]`
+	err := Validate(StationOverrides, []byte(invalid))
+	if err == nil || !strings.Contains(err.Error(), "/0/constituents/0/amplitude_m: expected number, got string") {
+		t.Errorf("expected type error, got %v", err)
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	err := Validate(DatumOffsets, []byte("[\n  {\"name\": \"A0\",}\n]"))
+	var verr *ValidationError
+	if err == nil || errors.As(err, &verr) || !strings.Contains(err.Error(), "invalid JSON at 2:") {
+		t.Errorf("expected JSON syntax error with position, got %v", err)
+	}
+}
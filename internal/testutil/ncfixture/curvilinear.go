@@ -0,0 +1,87 @@
+package ncfixture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// Curvilinear describes a fixture file with 2D "lat" and "lon" variables
+// over ("y", "x") dimensions, as written by rotated or curvilinear
+// regional models.
+type Curvilinear struct {
+	Lat, Lon [][]float64 // Node coordinates, indexed [y][x].
+	// Vars are indexed [y][x]. Steps and Transposed are not supported.
+	Vars []Var
+}
+
+// WriteCurvilinear creates the file at path (and its directory), failing
+// the test on any error.
+func WriteCurvilinear(t testing.TB, path string, c Curvilinear) {
+	t.Helper()
+	//nolint:gosec // G301: Standard test directory permissions.
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	f, err := netcdf.CreateFile(path, netcdf.CLOBBER)
+	if err != nil {
+		t.Fatalf("create nc: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	//nolint:gosec // G115: Fixture sizes are small.
+	yDim, err := f.AddDim("y", uint64(len(c.Lat)))
+	if err != nil {
+		t.Fatalf("add y dim: %v", err)
+	}
+	//nolint:gosec // G115: Fixture sizes are small.
+	xDim, err := f.AddDim("x", uint64(len(c.Lat[0])))
+	if err != nil {
+		t.Fatalf("add x dim: %v", err)
+	}
+	dims := []netcdf.Dim{yDim, xDim}
+	vlat, err := f.AddVar("lat", netcdf.DOUBLE, dims)
+	if err != nil {
+		t.Fatalf("add lat: %v", err)
+	}
+	vlon, err := f.AddVar("lon", netcdf.DOUBLE, dims)
+	if err != nil {
+		t.Fatalf("add lon: %v", err)
+	}
+	ncVars := make([]netcdf.Var, len(c.Vars))
+	for i, v := range c.Vars {
+		typ := netcdf.FLOAT
+		if v.Pack != nil {
+			typ = netcdf.SHORT
+		}
+		if ncVars[i], err = f.AddVar(v.Name, typ, dims); err != nil {
+			t.Fatalf("add %s: %v", v.Name, err)
+		}
+		writeAttrs(t, ncVars[i], v)
+	}
+
+	if err := f.EndDef(); err != nil {
+		t.Fatalf("enddef: %v", err)
+	}
+	flat := func(values [][]float64) []float64 {
+		var out []float64
+		for _, row := range values {
+			out = append(out, row...)
+		}
+		return out
+	}
+	if err := vlat.WriteFloat64s(flat(c.Lat)); err != nil {
+		t.Fatalf("write lat: %v", err)
+	}
+	if err := vlon.WriteFloat64s(flat(c.Lon)); err != nil {
+		t.Fatalf("write lon: %v", err)
+	}
+	cols := identity(len(c.Lat[0]))
+	for i, v := range c.Vars {
+		if err := writeData(ncVars[i], v, layout(v.Values, cols, false)); err != nil {
+			t.Fatalf("write %s: %v", v.Name, err)
+		}
+	}
+}
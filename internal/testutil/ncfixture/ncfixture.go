@@ -0,0 +1,279 @@
+// Package ncfixture writes small synthetic NetCDF files for store tests.
+package ncfixture
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+// FillValue is the NetCDF default float fill value. Cells holding it are
+// written as fill in variables with Fill set.
+const FillValue float32 = 9.96921e36
+
+// FillValue64 is FillValue written as a double: it differs from the float
+// value beyond float precision, as in files declaring a double fill for
+// float data.
+const FillValue64 float64 = 9.96921e36
+
+// PackedFill is the _FillValue of packed variables.
+const PackedFill int16 = -32767
+
+// Grid describes a fixture file: 1D lat/lon coordinate variables and data
+// variables over them.
+type Grid struct {
+	Lat, Lon         []float64 // Written in the given order (e.g., descending latitude).
+	LatName, LonName string    // Dimension and coordinate names; default "lat" and "lon".
+	Vars             []Var
+
+	// Transposed stores data variables as (lon, lat) instead of (lat, lon).
+	Transposed bool
+	// Lon360 writes the longitude axis in [0, 360), rotating the data columns
+	// so the axis stays ascending.
+	Lon360 bool
+}
+
+// Var is a data variable. Values are indexed [lat][lon] in the order of
+// Grid.Lat and Grid.Lon, whatever the storage layout.
+type Var struct {
+	Name   string
+	Values [][]float32
+	// Steps adds a leading "time" dimension, indexed [time][lat][lon].
+	// Values is ignored when set.
+	Steps [][][]float32
+
+	// Fill writes a fill attribute (FillValue, or PackedFill when packed);
+	// cells equal to FillValue become fill.
+	Fill bool
+	// FillAttr names the fill attribute; default "_FillValue".
+	FillAttr string
+	// Fill64 writes the fill attribute of an unpacked variable as a
+	// double (FillValue64) instead of a float.
+	Fill64 bool
+	// Double stores the variable as double, with FillValue64 in fill cells.
+	Double bool
+	// Pack stores the variable as int16 with scale_factor and add_offset.
+	Pack *Packing
+	// Units writes a "units" attribute (e.g., "mm").
+	Units string
+}
+
+// Packing is the int16 encoding of a packed variable:
+// value = stored*Scale + Offset.
+type Packing struct {
+	Scale  float64
+	Offset float64
+}
+
+// Write creates the file at path (and its directory), failing the test on
+// any error.
+func Write(t testing.TB, path string, g Grid) {
+	t.Helper()
+	//nolint:gosec // G301: Standard test directory permissions.
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	lon, cols := g.Lon, identity(len(g.Lon))
+	if g.Lon360 {
+		lon, cols = rotate360(t, g.Lon)
+	}
+	latName, lonName := nameOr(g.LatName, "lat"), nameOr(g.LonName, "lon")
+
+	f, err := netcdf.CreateFile(path, netcdf.CLOBBER)
+	if err != nil {
+		t.Fatalf("create nc: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	//nolint:gosec // G115: Fixture sizes are small.
+	latDim, err := f.AddDim(latName, uint64(len(g.Lat)))
+	if err != nil {
+		t.Fatalf("add lat dim: %v", err)
+	}
+	//nolint:gosec // G115: Fixture sizes are small.
+	lonDim, err := f.AddDim(lonName, uint64(len(lon)))
+	if err != nil {
+		t.Fatalf("add lon dim: %v", err)
+	}
+	vlat, err := f.AddVar(latName, netcdf.DOUBLE, []netcdf.Dim{latDim})
+	if err != nil {
+		t.Fatalf("add lat: %v", err)
+	}
+	vlon, err := f.AddVar(lonName, netcdf.DOUBLE, []netcdf.Dim{lonDim})
+	if err != nil {
+		t.Fatalf("add lon: %v", err)
+	}
+
+	spatial := []netcdf.Dim{latDim, lonDim}
+	if g.Transposed {
+		spatial = []netcdf.Dim{lonDim, latDim}
+	}
+	var timeDim netcdf.Dim
+	var haveTime bool
+	ncVars := make([]netcdf.Var, len(g.Vars))
+	for i, v := range g.Vars {
+		dims := spatial
+		if v.Steps != nil {
+			if !haveTime {
+				//nolint:gosec // G115: Fixture sizes are small.
+				if timeDim, err = f.AddDim("time", uint64(len(v.Steps))); err != nil {
+					t.Fatalf("add time dim: %v", err)
+				}
+				haveTime = true
+			}
+			dims = append([]netcdf.Dim{timeDim}, spatial...)
+		}
+		typ := netcdf.FLOAT
+		switch {
+		case v.Pack != nil:
+			typ = netcdf.SHORT
+		case v.Double:
+			typ = netcdf.DOUBLE
+		}
+		if ncVars[i], err = f.AddVar(v.Name, typ, dims); err != nil {
+			t.Fatalf("add %s: %v", v.Name, err)
+		}
+		writeAttrs(t, ncVars[i], v)
+	}
+
+	if err := f.EndDef(); err != nil {
+		t.Fatalf("enddef: %v", err)
+	}
+	if err := vlat.WriteFloat64s(g.Lat); err != nil {
+		t.Fatalf("write lat: %v", err)
+	}
+	if err := vlon.WriteFloat64s(lon); err != nil {
+		t.Fatalf("write lon: %v", err)
+	}
+	for i, v := range g.Vars {
+		steps := v.Steps
+		if steps == nil {
+			steps = [][][]float32{v.Values}
+		}
+		var flat []float32
+		for _, step := range steps {
+			flat = append(flat, layout(step, cols, g.Transposed)...)
+		}
+		if err := writeData(ncVars[i], v, flat); err != nil {
+			t.Fatalf("write %s: %v", v.Name, err)
+		}
+	}
+}
+
+func writeAttrs(t testing.TB, nv netcdf.Var, v Var) {
+	t.Helper()
+	if v.Units != "" {
+		if err := nv.Attr("units").WriteBytes([]byte(v.Units)); err != nil {
+			t.Fatalf("write units: %v", err)
+		}
+	}
+	if v.Pack != nil {
+		if err := nv.Attr("scale_factor").WriteFloat64s([]float64{v.Pack.Scale}); err != nil {
+			t.Fatalf("write scale_factor: %v", err)
+		}
+		if err := nv.Attr("add_offset").WriteFloat64s([]float64{v.Pack.Offset}); err != nil {
+			t.Fatalf("write add_offset: %v", err)
+		}
+	}
+	if !v.Fill {
+		return
+	}
+	attr := nv.Attr(nameOr(v.FillAttr, "_FillValue"))
+	var err error
+	switch {
+	case v.Pack != nil:
+		err = attr.WriteInt16s([]int16{PackedFill})
+	case v.Fill64:
+		err = attr.WriteFloat64s([]float64{FillValue64})
+	default:
+		err = attr.WriteFloat32s([]float32{FillValue})
+	}
+	if err != nil {
+		t.Fatalf("write fill attr: %v", err)
+	}
+}
+
+func writeData(nv netcdf.Var, v Var, flat []float32) error {
+	if v.Double {
+		values := make([]float64, len(flat))
+		for i, x := range flat {
+			values[i] = float64(x)
+			if v.Fill && x == FillValue {
+				values[i] = FillValue64
+			}
+		}
+		return nv.WriteFloat64s(values)
+	}
+	if v.Pack == nil {
+		return nv.WriteFloat32s(flat)
+	}
+	packed := make([]int16, len(flat))
+	for i, x := range flat {
+		if v.Fill && x == FillValue {
+			packed[i] = PackedFill
+			continue
+		}
+		packed[i] = int16(math.Round((float64(x) - v.Pack.Offset) / v.Pack.Scale))
+	}
+	return nv.WriteInt16s(packed)
+}
+
+// layout flattens [lat][lon] values in storage order, picking columns in
+// the order of cols.
+func layout(values [][]float32, cols []int, transposed bool) []float32 {
+	flat := make([]float32, 0, len(values)*len(cols))
+	if transposed {
+		for _, j := range cols {
+			for i := range values {
+				flat = append(flat, values[i][j])
+			}
+		}
+		return flat
+	}
+	for i := range values {
+		for _, j := range cols {
+			flat = append(flat, values[i][j])
+		}
+	}
+	return flat
+}
+
+// rotate360 maps longitudes into [0, 360) and returns them ascending with
+// the source column of each.
+func rotate360(t testing.TB, lon []float64) ([]float64, []int) {
+	t.Helper()
+	cols := identity(len(lon))
+	wrapped := make([]float64, len(lon))
+	for i, x := range lon {
+		wrapped[i] = math.Mod(math.Mod(x, 360)+360, 360)
+	}
+	sort.SliceStable(cols, func(a, b int) bool { return wrapped[cols[a]] < wrapped[cols[b]] })
+	out := make([]float64, len(lon))
+	for i, j := range cols {
+		out[i] = wrapped[j]
+		if i > 0 && out[i] == out[i-1] {
+			t.Fatalf("duplicate longitude %v on the 0–360° axis", out[i])
+		}
+	}
+	return out, cols
+}
+
+func identity(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
+
+func nameOr(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,63 @@
+package ncfixture
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/fhs/go-netcdf/netcdf"
+)
+
+func TestWrite_Lon360TransposedPacked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "grid.nc")
+	Write(t, path, Grid{
+		Lat:        []float64{10, 20},
+		Lon:        []float64{-10, 0, 10},
+		Transposed: true,
+		Lon360:     true,
+		Vars: []Var{{
+			Name:   "h",
+			Values: [][]float32{{1, 2, 3}, {4, FillValue, 6}},
+			Fill:   true,
+			Pack:   &Packing{Scale: 0.5, Offset: 1},
+		}},
+	})
+
+	f, err := netcdf.OpenFile(path, netcdf.NOWRITE)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	vlon, err := f.Var("lon")
+	if err != nil {
+		t.Fatalf("lon: %v", err)
+	}
+	lon := make([]float64, 3)
+	if err := vlon.ReadFloat64s(lon); err != nil {
+		t.Fatalf("read lon: %v", err)
+	}
+	if want := []float64{0, 10, 350}; !reflect.DeepEqual(lon, want) {
+		t.Errorf("lon = %v, want %v", lon, want)
+	}
+
+	vh, err := f.Var("h")
+	if err != nil {
+		t.Fatalf("h: %v", err)
+	}
+	dims, err := vh.Dims()
+	if err != nil {
+		t.Fatalf("dims: %v", err)
+	}
+	if name, _ := dims[0].Name(); name != "lon" {
+		t.Errorf("first dimension = %q, want lon", name)
+	}
+	h := make([]int16, 6)
+	if err := vh.ReadInt16s(h); err != nil {
+		t.Fatalf("read h: %v", err)
+	}
+	// Columns 0°, 10°, 350° (from -10°), each [lat 10, lat 20]; (v-1)/0.5.
+	if want := []int16{2, PackedFill, 4, 10, 0, 6}; !reflect.DeepEqual(h, want) {
+		t.Errorf("h = %v, want %v", h, want)
+	}
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// usageGeohashPrecision buckets locations into roughly 39 x 20 km cells.
+	usageGeohashPrecision = 4
+	// maxUsageOrigins bounds memory; further origins are counted as "other".
+	maxUsageOrigins = 1000
+	// maxUsageBuckets bounds geohash buckets per origin; further cells are counted as "other".
+	maxUsageBuckets = 5000
+	// usageOverflowKey collects origins and buckets beyond the limits.
+	usageOverflowKey = "other"
+)
+
+// latencyBoundsMs are the upper bounds of the latency histogram buckets.
+//
+//nolint:gochecknoglobals // Intentional: fixed histogram layout.
+var latencyBoundsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// UsageEvent is one served request.
+type UsageEvent struct {
+	Origin   string
+	Endpoint string
+	Status   int
+	Latency  time.Duration
+	// HasLocation is set when the request carried lat/lon.
+	HasLocation bool
+	Lat         float64
+	Lon         float64
+}
+
+// LatencySummary summarizes request latency. Percentiles are histogram
+// bucket upper bounds; requests slower than the last bound report MaxMs.
+type LatencySummary struct {
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// LocationCount is the number of requests within one geohash cell.
+type LocationCount struct {
+	Geohash  string `json:"geohash"`
+	Requests int64  `json:"requests"`
+}
+
+// OriginUsage is the usage of one API key or origin.
+type OriginUsage struct {
+	Origin       string           `json:"origin"`
+	Requests     int64            `json:"requests"`
+	Errors       int64            `json:"errors"`
+	Endpoints    map[string]int64 `json:"endpoints"`
+	Latency      LatencySummary   `json:"latency"`
+	TopLocations []LocationCount  `json:"top_locations"`
+	LastSeen     time.Time        `json:"last_seen"`
+}
+
+// UsageReport is the analytics endpoint and export payload.
+type UsageReport struct {
+	Since         time.Time     `json:"since"`
+	GeneratedAt   time.Time     `json:"generated_at"`
+	TotalRequests int64         `json:"total_requests"`
+	Origins       []OriginUsage `json:"origins"`
+}
+
+// originStats accumulates usage for one origin.
+type originStats struct {
+	requests  int64
+	errors    int64
+	endpoints map[string]int64
+	buckets   map[string]int64
+	latency   []int64 // Histogram counts; the last bucket is overflow.
+	totalMs   float64
+	maxMs     float64
+	lastSeen  time.Time
+}
+
+// UsageAnalytics aggregates request usage per API key or origin in memory.
+type UsageAnalytics struct {
+	mu      sync.Mutex
+	since   time.Time
+	origins map[string]*originStats
+}
+
+// NewUsageAnalytics creates an empty usage aggregator.
+func NewUsageAnalytics() *UsageAnalytics {
+	return &UsageAnalytics{
+		since:   time.Now().UTC(),
+		origins: make(map[string]*originStats),
+	}
+}
+
+// Record adds one request to the aggregates.
+func (a *UsageAnalytics) Record(ev UsageEvent) {
+	ms := float64(ev.Latency) / float64(time.Millisecond)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := a.origins[ev.Origin]
+	if s == nil {
+		origin := ev.Origin
+		if len(a.origins) >= maxUsageOrigins {
+			origin = usageOverflowKey
+		}
+		if s = a.origins[origin]; s == nil {
+			s = &originStats{
+				endpoints: make(map[string]int64),
+				buckets:   make(map[string]int64),
+				latency:   make([]int64, len(latencyBoundsMs)+1),
+			}
+			a.origins[origin] = s
+		}
+	}
+
+	s.requests++
+	if ev.Status >= 400 {
+		s.errors++
+	}
+	s.endpoints[ev.Endpoint]++
+	s.latency[sort.SearchFloat64s(latencyBoundsMs, ms)]++
+	s.totalMs += ms
+	if ms > s.maxMs {
+		s.maxMs = ms
+	}
+	s.lastSeen = time.Now().UTC()
+
+	if ev.HasLocation {
+		cell := domain.EncodeGeohash(ev.Lat, ev.Lon, usageGeohashPrecision)
+		if _, ok := s.buckets[cell]; !ok && len(s.buckets) >= maxUsageBuckets {
+			cell = usageOverflowKey
+		}
+		s.buckets[cell]++
+	}
+}
+
+// Report returns usage per origin, busiest first, with up to topN locations each.
+func (a *UsageAnalytics) Report(topN int) *UsageReport {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	report := &UsageReport{
+		Since:       a.since,
+		GeneratedAt: time.Now().UTC(),
+		Origins:     make([]OriginUsage, 0, len(a.origins)),
+	}
+	for origin, s := range a.origins {
+		endpoints := make(map[string]int64, len(s.endpoints))
+		for k, v := range s.endpoints {
+			endpoints[k] = v
+		}
+		report.Origins = append(report.Origins, OriginUsage{
+			Origin:       origin,
+			Requests:     s.requests,
+			Errors:       s.errors,
+			Endpoints:    endpoints,
+			Latency:      s.latencySummary(),
+			TopLocations: topLocations(s.buckets, topN),
+			LastSeen:     s.lastSeen,
+		})
+		report.TotalRequests += s.requests
+	}
+	sort.Slice(report.Origins, func(i, j int) bool {
+		if report.Origins[i].Requests != report.Origins[j].Requests {
+			return report.Origins[i].Requests > report.Origins[j].Requests
+		}
+		return report.Origins[i].Origin < report.Origins[j].Origin
+	})
+	return report
+}
+
+func (s *originStats) latencySummary() LatencySummary {
+	if s.requests == 0 {
+		return LatencySummary{}
+	}
+	percentile := func(p float64) float64 {
+		rank := int64(p * float64(s.requests))
+		if rank < 1 {
+			rank = 1
+		}
+		var seen int64
+		for i, n := range s.latency {
+			seen += n
+			if seen >= rank {
+				if i < len(latencyBoundsMs) {
+					return min(latencyBoundsMs[i], s.maxMs)
+				}
+				break
+			}
+		}
+		return s.maxMs
+	}
+	return LatencySummary{
+		MeanMs: s.totalMs / float64(s.requests),
+		P50Ms:  percentile(0.50),
+		P95Ms:  percentile(0.95),
+		P99Ms:  percentile(0.99),
+		MaxMs:  s.maxMs,
+	}
+}
+
+func topLocations(buckets map[string]int64, topN int) []LocationCount {
+	out := make([]LocationCount, 0, len(buckets))
+	for cell, n := range buckets {
+		out = append(out, LocationCount{Geohash: cell, Requests: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Requests != out[j].Requests {
+			return out[i].Requests > out[j].Requests
+		}
+		return out[i].Geohash < out[j].Geohash
+	})
+	if topN > 0 && len(out) > topN {
+		out = out[:topN]
+	}
+	return out
+}
+
+// Export writes the full report as JSON to path, replacing it atomically.
+func (a *UsageAnalytics) Export(path string) error {
+	b, err := json.MarshalIndent(a.Report(0), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode usage report: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".usage-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create usage export: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write usage export: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write usage export: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace usage export: %w", err)
+	}
+	return nil
+}
+
+// RunExport writes the report to path every interval until ctx is done,
+// and once more on shutdown.
+func (a *UsageAnalytics) RunExport(ctx context.Context, interval time.Duration, path string) {
+	export := func() {
+		if err := a.Export(path); err != nil {
+			log.Printf("Warning: usage export failed: %v", err)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			export()
+			return
+		case <-ticker.C:
+			export()
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// maxArchiveQuery bounds the span of a single archive query.
+const maxArchiveQuery = 366 * 24 * time.Hour
+
+// ObservationArchive persists and serves historical observations.
+type ObservationArchive interface {
+	// Append merges observations for a station and returns the number of new timestamps.
+	Append(station string, levels []domain.TideLevel) (int, error)
+	// Fill archives synthetic levels where no observation exists and returns the number written.
+	Fill(station string, levels []domain.TideLevel) (int, error)
+	// Query returns archived levels in [start, end], in time order.
+	Query(station string, start, end time.Time) ([]domain.ArchivedLevel, error)
+}
+
+// ArchiveQuery selects archived observations for a station.
+// When Lat/Lon are set, predictions and residuals are included.
+type ArchiveQuery struct {
+	Station string
+	Start   time.Time
+	End     time.Time
+	Lat     *float64
+	Lon     *float64
+}
+
+// ArchivedPoint is an archived observation, optionally paired with a prediction.
+// Synthetic points were gap-filled and carry no residual.
+type ArchivedPoint struct {
+	Time       string   `json:"time"`
+	ObservedM  float64  `json:"observed_m"`
+	Synthetic  bool     `json:"synthetic,omitempty"`
+	PredictedM *float64 `json:"predicted_m,omitempty"`
+	ResidualM  *float64 `json:"residual_m,omitempty"`
+}
+
+// ArchiveResponse is the result of an archive query.
+type ArchiveResponse struct {
+	Station      string          `json:"station"`
+	Start        string          `json:"start"`
+	End          string          `json:"end"`
+	Count        int             `json:"count"`
+	Observations []ArchivedPoint `json:"observations"`
+	// Gaps lists hours without a real observation (synthetic points count as missing).
+	Gaps         []domain.Gap `json:"gaps"`
+	MissingHours int          `json:"missing_hours"`
+}
+
+// GapFillRequest selects a window of the archive to gap-fill for a station
+// located at Lat/Lon.
+type GapFillRequest struct {
+	Station string
+	Start   time.Time
+	End     time.Time
+	Lat     float64
+	Lon     float64
+}
+
+// GapFillResponse reports the result of gap filling.
+type GapFillResponse struct {
+	Station string       `json:"station"`
+	Gaps    []domain.Gap `json:"gaps"`
+	Filled  int          `json:"filled"`
+	BiasM   float64      `json:"bias_m"` // Mean observed minus predicted, added to filled values.
+}
+
+// ArchiveUseCase ingests observations into the archive and queries them
+// alongside predictions.
+type ArchiveUseCase struct {
+	predictionUC *PredictionUseCase
+	archive      ObservationArchive
+	source       ObservationSource
+}
+
+// NewArchiveUseCase creates an archive use case.
+// source may be nil when only querying.
+func NewArchiveUseCase(predictionUC *PredictionUseCase, archive ObservationArchive, source ObservationSource) *ArchiveUseCase {
+	return &ArchiveUseCase{
+		predictionUC: predictionUC,
+		archive:      archive,
+		source:       source,
+	}
+}
+
+// Ingest archives observations for station at or after since.
+func (a *ArchiveUseCase) Ingest(station string, since time.Time) (int, error) {
+	if a.source == nil {
+		return 0, fmt.Errorf("no observation source configured")
+	}
+	levels, err := a.source.Recent(station, since)
+	if err != nil {
+		return 0, err
+	}
+	added, err := a.archive.Append(station, levels)
+	if err != nil {
+		return added, fmt.Errorf("failed to archive observations for station %s: %w", station, err)
+	}
+	return added, nil
+}
+
+// Run periodically ingests the last lookback of observations for stations
+// until ctx is canceled.
+func (a *ArchiveUseCase) Run(ctx context.Context, interval, lookback time.Duration, stations []string) {
+	ingest := func() {
+		since := a.predictionUC.Clock().Now().UTC().Add(-lookback)
+		for _, st := range stations {
+			added, err := a.Ingest(st, since)
+			if err != nil {
+				log.Printf("Warning: archive ingestion failed for %s: %v", st, err)
+				continue
+			}
+			if added > 0 {
+				log.Printf("Archived %d new observations for %s", added, st)
+			}
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	ingest()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ingest()
+		}
+	}
+}
+
+// Query returns archived observations, paired with predictions when a location is given.
+func (a *ArchiveUseCase) Query(q ArchiveQuery) (*ArchiveResponse, error) {
+	if err := validateArchiveWindow(q.Station, q.Start, q.End); err != nil {
+		return nil, err
+	}
+	if (q.Lat == nil) != (q.Lon == nil) {
+		return nil, fmt.Errorf("lat and lon must be provided together")
+	}
+
+	observed, err := a.archive.Query(q.Station, q.Start, q.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archive: %w", err)
+	}
+
+	predicted := make(map[int64]float64)
+	if q.Lat != nil && len(observed) > 0 {
+		series, err := a.predictionUC.PredictSeries(PredictionRequest{
+			Lat:      q.Lat,
+			Lon:      q.Lon,
+			Start:    observed[0].Time,
+			End:      observed[len(observed)-1].Time,
+			Interval: time.Hour,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range series {
+			predicted[p.Time.Unix()] = p.HeightM
+		}
+	}
+
+	points := make([]ArchivedPoint, 0, len(observed))
+	for _, o := range observed {
+		point := ArchivedPoint{
+			Time:      o.Time.Format(time.RFC3339),
+			ObservedM: roundToDecimal(o.HeightM),
+			Synthetic: o.Synthetic,
+		}
+		if p, ok := predicted[o.Time.Unix()]; ok {
+			pred := roundToDecimal(p)
+			point.PredictedM = &pred
+			if !o.Synthetic {
+				resid := roundToDecimal(o.HeightM - p)
+				point.ResidualM = &resid
+			}
+		}
+		points = append(points, point)
+	}
+
+	gaps := domain.FindGaps(realObservations(observed), q.Start, q.End, time.Hour)
+	missing := 0
+	for _, g := range gaps {
+		missing += g.Missing
+	}
+
+	return &ArchiveResponse{
+		Station:      q.Station,
+		Start:        q.Start.Format(time.RFC3339),
+		End:          q.End.Format(time.RFC3339),
+		Count:        len(points),
+		Observations: points,
+		Gaps:         gaps,
+		MissingHours: missing,
+	}, nil
+}
+
+// FillGaps writes bias-corrected harmonic predictions, flagged as synthetic,
+// into every missing hour of the window. The bias is the mean residual of the
+// real observations in the window so filled values follow the gauge datum.
+func (a *ArchiveUseCase) FillGaps(req GapFillRequest) (*GapFillResponse, error) {
+	if err := validateArchiveWindow(req.Station, req.Start, req.End); err != nil {
+		return nil, err
+	}
+
+	archived, err := a.archive.Query(req.Station, req.Start, req.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archive: %w", err)
+	}
+	observed := realObservations(archived)
+	gaps := domain.FindGaps(observed, req.Start, req.End, time.Hour)
+	resp := &GapFillResponse{Station: req.Station, Gaps: gaps}
+	if len(gaps) == 0 {
+		return resp, nil
+	}
+
+	lat, lon := req.Lat, req.Lon
+	series, err := a.predictionUC.PredictSeries(PredictionRequest{
+		Lat:      &lat,
+		Lon:      &lon,
+		Start:    req.Start.Truncate(time.Hour),
+		End:      req.End,
+		Interval: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	residuals := domain.Residuals(observed, series)
+	bias := 0.0
+	for _, r := range residuals {
+		bias += r.HeightM
+	}
+	if len(residuals) > 0 {
+		bias /= float64(len(residuals))
+	}
+	resp.BiasM = roundToDecimal(bias)
+
+	predicted := make(map[int64]float64, len(series))
+	for _, p := range series {
+		predicted[p.Time.Unix()] = p.HeightM
+	}
+	fill := make([]domain.TideLevel, 0)
+	for _, t := range domain.MissingTimes(gaps, time.Hour) {
+		if p, ok := predicted[t.Unix()]; ok {
+			fill = append(fill, domain.TideLevel{Time: t, HeightM: p + bias})
+		}
+	}
+
+	filled, err := a.archive.Fill(req.Station, fill)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fill archive gaps: %w", err)
+	}
+	resp.Filled = filled
+	return resp, nil
+}
+
+func validateArchiveWindow(station string, start, end time.Time) error {
+	if station == "" {
+		return fmt.Errorf("station is required")
+	}
+	if !end.After(start) {
+		return fmt.Errorf("end time must be after start time")
+	}
+	if end.Sub(start) > maxArchiveQuery {
+		return fmt.Errorf("time range too large (max 366 days)")
+	}
+	return nil
+}
+
+// realObservations drops synthetic levels.
+func realObservations(levels []domain.ArchivedLevel) []domain.TideLevel {
+	out := make([]domain.TideLevel, 0, len(levels))
+	for _, l := range levels {
+		if !l.Synthetic {
+			out = append(out, domain.TideLevel{Time: l.Time, HeightM: l.HeightM})
+		}
+	}
+	return out
+}
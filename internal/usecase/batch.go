@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// MaxBatchRequests bounds the requests of a prediction batch.
+	MaxBatchRequests = 100
+	// maxBatchPoints bounds the predicted points of a whole batch, so a
+	// batch costs no more than a few requests of the longest range.
+	maxBatchPoints = 5 * maxPredictionPoints
+)
+
+// BatchResult is the outcome of one request of a batch.
+type BatchResult struct {
+	Response *PredictionResponse
+	Err      error
+}
+
+// ExecuteBatch performs several tide predictions. The FES constituents of
+// all lat/lon requests are loaded together first, so each dataset file is
+// opened once per batch rather than once per location. Results are indexed
+// like reqs; a failing request does not fail the others. An error is
+// returned only for a batch that is empty or too large.
+func (uc *PredictionUseCase) ExecuteBatch(reqs []PredictionRequest) ([]BatchResult, error) {
+	if len(reqs) == 0 {
+		return nil, errors.New("batch has no requests")
+	}
+	if len(reqs) > MaxBatchRequests {
+		return nil, fmt.Errorf("batch has %d requests, at most %d allowed", len(reqs), MaxBatchRequests)
+	}
+	points := 0
+	for _, req := range reqs {
+		if req.Interval > 0 && req.End.After(req.Start) {
+			points += int(req.End.Sub(req.Start)/req.Interval) + 1
+		}
+	}
+	if points > maxBatchPoints {
+		return nil, fmt.Errorf("batch predicts %d points, at most %d allowed", points, maxBatchPoints)
+	}
+
+	fes := uc.preload(reqs)
+	results := make([]BatchResult, len(reqs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(reqs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i].Response, results[i].Err = uc.execute(reqs[i], fes)
+			}
+		}()
+	}
+	for i := range reqs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, nil
+}
+
+// preload loads the FES constituents of the valid lat/lon FES requests of
+// a batch in one call to the FES store.
+func (uc *PredictionUseCase) preload(reqs []PredictionRequest) store.ConstituentLoader {
+	loader := preloadedLoader{ConstituentLoader: *uc.fesStore, sets: make(map[domain.Position]preloadedSet)}
+	var points []domain.Position
+	for _, req := range reqs {
+		if req.Lat == nil || req.Lon == nil || req.StationID != nil || len(req.Constituents) > 0 ||
+			req.Ensemble || req.Source == sourceCSV || uc.gridSource(req) != sourceFES || req.Validate() != nil {
+			continue
+		}
+		p := domain.Position{Lat: *req.Lat, Lon: *req.Lon}
+		if _, ok := loader.sets[p]; !ok {
+			loader.sets[p] = preloadedSet{}
+			points = append(points, p)
+		}
+	}
+	if len(points) == 0 {
+		return *uc.fesStore
+	}
+	params, errs := store.LoadForLocations(*uc.fesStore, points)
+	for i, p := range points {
+		loader.sets[p] = preloadedSet{params: params[i], err: errs[i]}
+	}
+	return loader
+}
+
+// preloadedLoader serves the locations of a batch from constituent sets
+// loaded together, and any other location from the wrapped loader.
+type preloadedLoader struct {
+	store.ConstituentLoader
+	sets map[domain.Position]preloadedSet
+}
+
+type preloadedSet struct {
+	params []domain.ConstituentParam
+	err    error
+}
+
+// LoadForLocation returns a copy of the preloaded set of a location, as
+// requests at the same location share it.
+func (l preloadedLoader) LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error) {
+	if set, ok := l.sets[domain.Position{Lat: lat, Lon: lon}]; ok {
+		return slices.Clone(set.params), set.err
+	}
+	return l.ConstituentLoader.LoadForLocation(lat, lon)
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// maxChartNodesPerSide bounds the sampled grid; every node is a
+	// constituent read.
+	maxChartNodesPerSide = 100
+	// defaultChartNodesPerSide sets the resolution when none is given.
+	defaultChartNodesPerSide = 50
+	// maxChartLevels bounds the contour levels of each kind.
+	maxChartLevels             = 60
+	defaultChartPhaseStepDeg   = 30.0
+	defaultChartRangeLineCount = 8
+)
+
+// ChartRequest selects a co-tidal chart.
+type ChartRequest struct {
+	Constituent    string // Default M2.
+	BBox           domain.BBox
+	ResolutionDeg  float64 // Grid spacing; 0 picks one for the box.
+	PhaseStepDeg   float64 // Co-tidal line spacing; 0 means 30°.
+	AmplitudeStepM float64 // Co-range line spacing; 0 picks a round step.
+}
+
+// FeatureCollection is a GeoJSON (RFC 7946) feature collection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	BBox     []float64 `json:"bbox,omitempty"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature.
+type Feature struct {
+	Type       string         `json:"type"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry.
+type Geometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// ChartResponse is a co-tidal chart: co-tidal lines (equal Greenwich phase
+// lag) and co-range lines (equal amplitude) as GeoJSON MultiLineStrings.
+type ChartResponse struct {
+	FeatureCollection
+	Constituent    string  `json:"constituent"`
+	ResolutionDeg  float64 `json:"resolution_deg"`
+	PhaseStepDeg   float64 `json:"phase_step_deg"`
+	AmplitudeStepM float64 `json:"amplitude_step_m"`
+}
+
+// CotidalChart contours the phase and amplitude of a constituent, sampled
+// from the FES grids over a box.
+func (uc *PredictionUseCase) CotidalChart(req ChartRequest) (*ChartResponse, error) {
+	if req.Constituent == "" {
+		req.Constituent = "M2"
+	}
+	name, ok := domain.CanonicalConstituentName(req.Constituent)
+	if !ok {
+		return nil, fmt.Errorf("unknown constituent %s", req.Constituent)
+	}
+	if req.PhaseStepDeg == 0 {
+		req.PhaseStepDeg = defaultChartPhaseStepDeg
+	}
+	if req.PhaseStepDeg < 0 || req.PhaseStepDeg > 180 || 360/req.PhaseStepDeg > maxChartLevels {
+		return nil, fmt.Errorf("phase step must be between %g and 180 degrees", 360.0/maxChartLevels)
+	}
+	if req.AmplitudeStepM < 0 {
+		return nil, errors.New("amplitude step must be positive")
+	}
+
+	amplitude, phase, err := uc.sampleConstituent(name, req.BBox, req.ResolutionDeg)
+	if err != nil {
+		return nil, err
+	}
+	lo, hi, ok := amplitude.Range()
+	if !ok {
+		return nil, fmt.Errorf("no %s data in bbox: %w", name, domain.ErrOutOfCoverage)
+	}
+	if req.AmplitudeStepM == 0 {
+		req.AmplitudeStepM = niceStep((hi - lo) / defaultChartRangeLineCount)
+	}
+	if (hi-lo)/req.AmplitudeStepM > maxChartLevels {
+		return nil, fmt.Errorf("amplitude step too small: more than %d co-range lines", maxChartLevels)
+	}
+
+	response := &ChartResponse{
+		FeatureCollection: FeatureCollection{
+			Type:     "FeatureCollection",
+			BBox:     []float64{req.BBox.MinLon, req.BBox.MinLat, req.BBox.MaxLon, req.BBox.MaxLat},
+			Features: []Feature{},
+		},
+		Constituent:    name,
+		ResolutionDeg:  roundCoord(amplitude.Lats[1] - amplitude.Lats[0]),
+		PhaseStepDeg:   req.PhaseStepDeg,
+		AmplitudeStepM: req.AmplitudeStepM,
+	}
+
+	// A co-tidal line of phase φ is where sin(G − φ) = 0 and cos(G − φ) > 0,
+	// which avoids contouring across the 360° wrap.
+	for phi := 0.0; phi < 360; phi += req.PhaseStepDeg {
+		sin := phase.Map(func(g float64) float64 { return math.Sin(domain.Deg2Rad(g - phi)) })
+		cos := phase.Map(func(g float64) float64 { return math.Cos(domain.Deg2Rad(g - phi)) })
+		facing := func(i, j int) bool {
+			return cos.Values[i][j]+cos.Values[i][j+1]+cos.Values[i+1][j]+cos.Values[i+1][j+1] > 0
+		}
+		response.add(sin.Contour(0, facing), map[string]any{
+			"kind": "co_tidal", "constituent": name, "phase_deg": phi,
+		})
+	}
+	for k := math.Ceil(lo / req.AmplitudeStepM); k*req.AmplitudeStepM <= hi; k++ {
+		level := roundToDecimal(k * req.AmplitudeStepM)
+		if level <= 0 {
+			continue
+		}
+		response.add(amplitude.Contour(level, nil), map[string]any{
+			"kind": "co_range", "constituent": name, "amplitude_m": level,
+		})
+	}
+	return response, nil
+}
+
+// AmphidromeRequest selects a constituent and box to search.
+type AmphidromeRequest struct {
+	Constituent   string // Default M2.
+	BBox          domain.BBox
+	ResolutionDeg float64 // Grid spacing; 0 picks one for the box.
+}
+
+// AmphidromeResponse lists amphidromic points as GeoJSON Points.
+type AmphidromeResponse struct {
+	FeatureCollection
+	Constituent   string  `json:"constituent"`
+	ResolutionDeg float64 `json:"resolution_deg"`
+}
+
+// Amphidromes locates the amphidromic points of a constituent within a box
+// from the FES grids. Points are found to within a grid cell; a finer
+// resolution locates them more precisely.
+func (uc *PredictionUseCase) Amphidromes(req AmphidromeRequest) (*AmphidromeResponse, error) {
+	if req.Constituent == "" {
+		req.Constituent = "M2"
+	}
+	name, ok := domain.CanonicalConstituentName(req.Constituent)
+	if !ok {
+		return nil, fmt.Errorf("unknown constituent %s", req.Constituent)
+	}
+	amplitude, phase, err := uc.sampleConstituent(name, req.BBox, req.ResolutionDeg)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, ok := amplitude.Range(); !ok {
+		return nil, fmt.Errorf("no %s data in bbox: %w", name, domain.ErrOutOfCoverage)
+	}
+
+	response := &AmphidromeResponse{
+		FeatureCollection: FeatureCollection{
+			Type:     "FeatureCollection",
+			BBox:     []float64{req.BBox.MinLon, req.BBox.MinLat, req.BBox.MaxLon, req.BBox.MaxLat},
+			Features: []Feature{},
+		},
+		Constituent:   name,
+		ResolutionDeg: roundCoord(amplitude.Lats[1] - amplitude.Lats[0]),
+	}
+	for _, a := range domain.FindAmphidromes(amplitude, phase) {
+		rotation := "clockwise"
+		if a.Anticlockwise {
+			rotation = "anticlockwise"
+		}
+		response.Features = append(response.Features, Feature{
+			Type: "Feature",
+			Geometry: Geometry{
+				Type:        "Point",
+				Coordinates: [2]float64{roundCoord(a.Location[0]), roundCoord(a.Location[1])},
+			},
+			Properties: map[string]any{
+				"kind":                 "amphidrome",
+				"constituent":          name,
+				"rotation":             rotation,
+				"cell_min_amplitude_m": roundToDecimal(a.CellMinAmplitudeM),
+			},
+		})
+	}
+	return response, nil
+}
+
+// add appends lines as one MultiLineString feature, if there are any.
+func (r *ChartResponse) add(lines [][]domain.LonLat, properties map[string]any) {
+	if len(lines) == 0 {
+		return
+	}
+	coords := make([][][2]float64, len(lines))
+	for i, line := range lines {
+		coords[i] = make([][2]float64, len(line))
+		for k, p := range line {
+			coords[i][k] = [2]float64{roundCoord(p[0]), roundCoord(p[1])}
+		}
+	}
+	r.Features = append(r.Features, Feature{
+		Type:       "Feature",
+		Geometry:   Geometry{Type: "MultiLineString", Coordinates: coords},
+		Properties: properties,
+	})
+}
+
+// sampleConstituent reads a constituent's amplitude and phase on a grid
+// over b. Nodes without data are NaN; other read failures fail the chart.
+func (uc *PredictionUseCase) sampleConstituent(name string, b domain.BBox, resolutionDeg float64) (amplitude, phase domain.Grid, err error) {
+	if err := b.Validate(); err != nil {
+		return amplitude, phase, err
+	}
+	span := math.Max(b.MaxLat-b.MinLat, b.MaxLon-b.MinLon)
+	if resolutionDeg == 0 {
+		resolutionDeg = span / (defaultChartNodesPerSide - 1)
+	}
+	if resolutionDeg < 0 || span/resolutionDeg+1 > maxChartNodesPerSide {
+		return amplitude, phase, fmt.Errorf("resolution too fine: at most %d grid nodes per side (at least %.4g degrees for this bbox)",
+			maxChartNodesPerSide, span/(maxChartNodesPerSide-1))
+	}
+	if uc.fesStore == nil || *uc.fesStore == nil {
+		return amplitude, phase, errors.New("FES data is not configured")
+	}
+
+	amplitude = domain.NewGrid(b, resolutionDeg)
+	phase = domain.NewGrid(b, resolutionDeg)
+	if len(amplitude.Lats) < 2 || len(amplitude.Lons) < 2 {
+		return amplitude, phase, errors.New("resolution too coarse: the bbox needs at least 2 grid nodes per side")
+	}
+	for i, lat := range amplitude.Lats {
+		for j, lon := range amplitude.Lons {
+			p, err := store.LoadConstituentAt(*uc.fesStore, name, lat, lon)
+			if errors.Is(err, domain.ErrOutOfCoverage) {
+				continue
+			}
+			if err != nil {
+				return amplitude, phase, fmt.Errorf("failed to sample %s: %w", name, err)
+			}
+			amplitude.Values[i][j] = p.AmplitudeM
+			phase.Values[i][j] = p.PhaseDeg
+		}
+	}
+	return amplitude, phase, nil
+}
+
+// niceStep rounds a contour interval up to 1, 2 or 5 times a power of ten.
+func niceStep(raw float64) float64 {
+	if raw <= 0 || math.IsNaN(raw) {
+		return 0.1
+	}
+	scale := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*scale {
+			return m * scale
+		}
+	}
+	return 10 * scale
+}
+
+// roundCoord rounds a coordinate to 1e-5 degrees (about 1 m).
+func roundCoord(v float64) float64 {
+	return math.Round(v*1e5) / 1e5
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// fixedNowcaster returns the same residual estimate for every request.
+type fixedNowcaster struct{ at time.Time }
+
+func (n fixedNowcaster) Nowcast(PredictionRequest) (*Nowcast, error) {
+	return &Nowcast{
+		Station:  "TK",
+		Config:   domain.DefaultNowcastConfig(),
+		Estimate: domain.ResidualEstimate{Time: n.at, ResidualM: 0.2},
+	}, nil
+}
+
+func TestFixedClockDrivesNowcastStaleness(t *testing.T) {
+	speed, _ := domain.GetConstituentSpeed("M2")
+	uc := NewPredictionUseCase(nil, fallbackLoader{params: []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: speed}}}, nil)
+	now := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	uc.SetClock(domain.FixedClock{Time: now})
+	lat, lon := 35.0, 139.0
+	req := PredictionRequest{Lat: &lat, Lon: &lon, Nowcast: true, Interval: time.Hour, Start: now, End: now.Add(3 * time.Hour)}
+
+	// An hour before the frozen time: current, however long ago in wall time.
+	uc.SetNowcaster(fixedNowcaster{at: now.Add(-time.Hour)})
+	resp, err := uc.Execute(req)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if resp.Degraded || resp.Meta["nowcast_station"] != "TK" {
+		t.Errorf("recent observation: degraded %v (%v), nowcast station %q", resp.Degraded, resp.DegradedReasons, resp.Meta["nowcast_station"])
+	}
+
+	// Beyond the horizon before the frozen time: stale.
+	uc.SetNowcaster(fixedNowcaster{at: now.Add(-13 * time.Hour)})
+	resp, err = uc.Execute(req)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !resp.Degraded || len(resp.DegradedReasons) != 1 || !strings.Contains(resp.DegradedReasons[0], "13h0m0s old") {
+		t.Errorf("stale observation: degraded %v (%v), want 13h old", resp.Degraded, resp.DegradedReasons)
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// CoalescingStats reports how many predictions were shared by concurrent
+// identical requests.
+type CoalescingStats struct {
+	Executed  int64 `json:"executed"`  // Predictions computed.
+	Coalesced int64 `json:"coalesced"` // Requests served by another's computation.
+}
+
+// coalescer shares the prediction of a request with the identical
+// requests that arrive while it is computed, so a burst of them (e.g., a
+// popular page loading) costs one synthesis and one set of dataset reads.
+// Results are not kept once the computation returns.
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+	stats CoalescingStats
+}
+
+// errPredictionPanicked is the error of requests coalesced with one whose
+// prediction panicked.
+var errPredictionPanicked = errors.New("prediction failed")
+
+type coalescedCall struct {
+	done     chan struct{}
+	response *PredictionResponse
+	err      error
+	shared   bool // Another request waits for the result.
+}
+
+// do returns the result of fn for key, or of the call of fn already running
+// for key. Every caller gets its own copy of a shared response, as handlers
+// post-process responses in place.
+func (g *coalescer) do(key string, fn func() (*PredictionResponse, error)) (*PredictionResponse, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		call.shared = true
+		g.stats.Coalesced++
+		g.mu.Unlock()
+		<-call.done
+		return cloneResponse(call.response), call.err
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*coalescedCall)
+	}
+	call := &coalescedCall{done: make(chan struct{}), err: errPredictionPanicked}
+	g.calls[key] = call
+	g.stats.Executed++
+	g.mu.Unlock()
+
+	// finish removes the call, so later requests compute anew, and releases
+	// the waiters.
+	finish := func() (shared bool) {
+		g.mu.Lock()
+		delete(g.calls, key)
+		shared = call.shared
+		g.mu.Unlock()
+		close(call.done)
+		return shared
+	}
+	finished := false
+	defer func() {
+		// Waiters must not hang if fn panics (the recovery middleware
+		// answers 500); they get errPredictionPanicked.
+		if !finished {
+			finish()
+		}
+	}()
+	call.response, call.err = fn()
+	finished = true
+	if finish() {
+		return cloneResponse(call.response), call.err
+	}
+	return call.response, call.err
+}
+
+// Stats returns the coalescing counters.
+func (g *coalescer) Stats() CoalescingStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stats
+}
+
+// coalescingKey identifies the prediction of a request: requests with equal
+// keys get identical responses.
+func coalescingKey(req PredictionRequest) (string, bool) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// cloneResponse copies the slices and maps of a response, which
+// post-processing replaces or modifies; pointed-to values are shared.
+func cloneResponse(r *PredictionResponse) *PredictionResponse {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.Constituents = slices.Clone(r.Constituents)
+	c.Predictions = slices.Clone(r.Predictions)
+	c.Extrema.Highs = slices.Clone(r.Extrema.Highs)
+	c.Extrema.Lows = slices.Clone(r.Extrema.Lows)
+	c.Meta = maps.Clone(r.Meta)
+	c.DegradedReasons = slices.Clone(r.DegradedReasons)
+	return &c
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescer_SharesConcurrentCalls(t *testing.T) {
+	var g coalescer
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() (*PredictionResponse, error) {
+		calls.Add(1)
+		<-release
+		return &PredictionResponse{Predictions: []PredictionPoint{{HeightM: 1}}, Meta: map[string]string{}}, nil
+	}
+
+	const n = 8
+	responses := make([]*PredictionResponse, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], _ = g.do("k", fn)
+		}()
+	}
+	for g.Stats().Executed+g.Stats().Coalesced < n {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn called %d times, want 1", calls.Load())
+	}
+	if stats := g.Stats(); stats.Executed != 1 || stats.Coalesced != n-1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	// Each caller may post-process its response.
+	responses[0].Predictions[0].HeightM = 2
+	responses[0].Meta["units"] = "ft"
+	for i, r := range responses[1:] {
+		if r.Predictions[0].HeightM != 1 || len(r.Meta) != 0 {
+			t.Errorf("response %d changed through another: %+v", i+1, r)
+		}
+	}
+
+	// Finished calls are not reused.
+	_, _ = g.do("k", fn)
+	if calls.Load() != 2 {
+		t.Errorf("fn called %d times, want 2", calls.Load())
+	}
+}
+
+func TestCoalescer_PanicReleasesWaiters(t *testing.T) {
+	var g coalescer
+	release := make(chan struct{})
+	panicked := make(chan any)
+	go func() {
+		defer func() { panicked <- recover() }()
+		_, _ = g.do("k", func() (*PredictionResponse, error) {
+			<-release
+			panic("synthesis")
+		})
+	}()
+	for g.Stats().Executed == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waited := make(chan error)
+	go func() {
+		_, err := g.do("k", func() (*PredictionResponse, error) { return nil, nil })
+		waited <- err
+	}()
+	for g.Stats().Coalesced == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if p := <-panicked; p == nil {
+		t.Error("panic not propagated to the computing caller")
+	}
+	if err := <-waited; !errors.Is(err, errPredictionPanicked) {
+		t.Errorf("waiter error = %v, want errPredictionPanicked", err)
+	}
+	if _, err := g.do("k", func() (*PredictionResponse, error) { return &PredictionResponse{}, nil }); err != nil {
+		t.Errorf("call after a panic: %v", err)
+	}
+}
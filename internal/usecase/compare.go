@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// ComparisonResponse relates the tides at two sites, e.g. to transfer
+// observations from a harbor gauge to an offshore anchorage.
+type ComparisonResponse struct {
+	From ComparedSite `json:"from"`
+	To   ComparedSite `json:"to"`
+	// At is the instant nodal corrections are evaluated at.
+	At string `json:"at"`
+	// ReferenceConstituent is the largest common constituent at From;
+	// its time lag is the high-water lag.
+	ReferenceConstituent string                 `json:"reference_constituent"`
+	HighWaterLagMinutes  float64                `json:"high_water_lag_minutes"` // Positive when To is later.
+	RangeRatio           float64                `json:"range_ratio"`            // To over From.
+	Constituents         []ConstituentLagResult `json:"constituents"`
+}
+
+// ComparedSite describes one site of a comparison.
+type ComparedSite struct {
+	Source     string   `json:"source"`
+	FormFactor *float64 `json:"form_factor,omitempty"` // (K1 + O1) / (M2 + S2).
+	TideType   string   `json:"tide_type,omitempty"`
+	// Fingerprint identifies the constituents and pipeline of the site.
+	Fingerprint string `json:"fingerprint"`
+	Degradation
+}
+
+// ConstituentLagResult relates one common constituent at the two sites.
+type ConstituentLagResult struct {
+	Name           string  `json:"name"`
+	AmplitudeRatio float64 `json:"amplitude_ratio"`
+	PhaseLagDeg    float64 `json:"phase_lag_deg"`
+	TimeLagMinutes float64 `json:"time_lag_minutes"`
+}
+
+// CompareSites compares the tides at the sites of two requests from their
+// constituent phases. Only the location, station or constituents of each
+// request and the phase convention are used.
+func (uc *PredictionUseCase) CompareSites(from, to PredictionRequest, at time.Time) (*ComparisonResponse, error) {
+	if err := from.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid from site: %w", err)
+	}
+	if err := to.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid to site: %w", err)
+	}
+	fromPrep, err := uc.prepare(from)
+	if err != nil {
+		return nil, fmt.Errorf("from site: %w", err)
+	}
+	toPrep, err := uc.prepare(to)
+	if err != nil {
+		return nil, fmt.Errorf("to site: %w", err)
+	}
+
+	for _, p := range []*preparedPrediction{fromPrep, toPrep} {
+		if p.secondary != nil {
+			return nil, fmt.Errorf("secondary ports have no constituents to compare - compare reference station %s instead", p.secondary.Reference)
+		}
+	}
+
+	cmp, err := domain.CompareTides(at, fromPrep.params, toPrep.params)
+	if err != nil {
+		return nil, fmt.Errorf("compare sites: %w", err)
+	}
+
+	response := &ComparisonResponse{
+		From:                 uc.comparedSite(fromPrep),
+		To:                   uc.comparedSite(toPrep),
+		At:                   at.UTC().Format(time.RFC3339),
+		ReferenceConstituent: cmp.Reference,
+		HighWaterLagMinutes:  roundToDecimal(cmp.HighWaterLag.Minutes()),
+		RangeRatio:           roundToDecimal(cmp.RangeRatio),
+		Constituents:         make([]ConstituentLagResult, len(cmp.Constituents)),
+	}
+	for i, c := range cmp.Constituents {
+		response.Constituents[i] = ConstituentLagResult{
+			Name:           c.Name,
+			AmplitudeRatio: roundToDecimal(c.AmplitudeRatio),
+			PhaseLagDeg:    roundToDecimal(c.PhaseLagDeg),
+			TimeLagMinutes: roundToDecimal(c.TimeLag.Minutes()),
+		}
+	}
+	return response, nil
+}
+
+func (uc *PredictionUseCase) comparedSite(p *preparedPrediction) ComparedSite {
+	site := ComparedSite{
+		Source:      p.source,
+		Fingerprint: newComputationProvenance(uc.codeVersion, uc.tables, p).Fingerprint(),
+		Degradation: p.degradation(),
+	}
+	if f, ok := domain.FormFactor(p.constituents); ok {
+		f = roundToDecimal(f)
+		site.FormFactor = &f
+		site.TideType = domain.TideType(f)
+	}
+	return site
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// constituentFallback records the constituents a prediction took from the
+// fallback dataset because its preferred dataset lacks them.
+type constituentFallback struct {
+	source string
+	names  []string
+}
+
+// fillMissingConstituents adds to the constituents of the preferred
+// dataset those of the fallback dataset it lacks at a location, e.g. FES
+// shallow-water constituents missing from TPXO. Constituents whose
+// fallback reads are failing are left out by the fallback loader (see
+// DatasetCircuits); a fallback that cannot load the location adds nothing,
+// and only other failures are returned as degradation reasons.
+func fillMissingConstituents(constituents []domain.ConstituentParam, fallback store.ConstituentLoader, source string, lat, lon float64) ([]domain.ConstituentParam, *constituentFallback, []string) {
+	if fallback == nil {
+		return constituents, nil, nil
+	}
+	extra, err := fallback.LoadForLocation(lat, lon)
+	if errors.Is(err, domain.ErrOutOfCoverage) {
+		return constituents, nil, nil
+	}
+	if err != nil {
+		return constituents, nil, []string{fmt.Sprintf("constituent fallback: %s: %v", source, err)}
+	}
+	have := make(map[string]bool, len(constituents))
+	for _, c := range constituents {
+		have[c.Name] = true
+	}
+	var filled *constituentFallback
+	for _, c := range extra {
+		if have[c.Name] {
+			continue
+		}
+		if filled == nil {
+			filled = &constituentFallback{source: source}
+			constituents = append([]domain.ConstituentParam(nil), constituents...)
+		}
+		constituents = append(constituents, c)
+		filled.names = append(filled.names, c.Name)
+	}
+	return constituents, filled, nil
+}
+
+// addFallbackMeta records the constituents taken from the fallback
+// dataset in response metadata.
+func (p *preparedPrediction) addFallbackMeta(meta map[string]string) {
+	if p.fallback == nil {
+		return
+	}
+	meta["constituent_fallback"] = p.fallback.source + ":" + strings.Join(p.fallback.names, ",")
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// fallbackLoader returns fixed constituents for any location.
+type fallbackLoader struct {
+	params []domain.ConstituentParam
+	err    error
+}
+
+func (l fallbackLoader) LoadForStation(string) ([]domain.ConstituentParam, error) {
+	return nil, errors.New("not supported")
+}
+
+func (l fallbackLoader) LoadForLocation(float64, float64) ([]domain.ConstituentParam, error) {
+	return l.params, l.err
+}
+
+func TestFillMissingConstituents(t *testing.T) {
+	preferred := []domain.ConstituentParam{{Name: "M2", AmplitudeM: 0.5}, {Name: "K1", AmplitudeM: 0.2}}
+	fes := fallbackLoader{params: []domain.ConstituentParam{{Name: "M2", AmplitudeM: 0.6}, {Name: "M4", AmplitudeM: 0.01}}}
+
+	got, filled, degraded := fillMissingConstituents(preferred, fes, sourceFES, 35, 139)
+	if len(got) != 3 || got[0].AmplitudeM != 0.5 || got[2].Name != "M4" {
+		t.Errorf("constituents = %+v, want the preferred M2 and K1 plus FES M4", got)
+	}
+	if filled == nil || filled.source != sourceFES || len(filled.names) != 1 || filled.names[0] != "M4" {
+		t.Errorf("fallback = %+v, want fes:M4", filled)
+	}
+	if len(degraded) != 0 || len(preferred) != 2 {
+		t.Errorf("degraded = %v, preferred modified: %v", degraded, preferred)
+	}
+
+	outside := fallbackLoader{err: domain.ErrOutOfCoverage}
+	if got, filled, degraded := fillMissingConstituents(preferred, outside, sourceFES, 35, 139); len(got) != 2 || filled != nil || degraded != nil {
+		t.Errorf("outside the fallback: %v, %+v, %v", got, filled, degraded)
+	}
+	failing := fallbackLoader{err: errors.New("read failed")}
+	if got, filled, degraded := fillMissingConstituents(preferred, failing, sourceFES, 35, 139); len(got) != 2 || filled != nil || len(degraded) != 1 {
+		t.Errorf("failing fallback: %v, %+v, %v", got, filled, degraded)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// ParseConstituentSelection parses a comma-separated list of constituent
+// names (e.g., "M2,S2,K1,O1") into their canonical names.
+func ParseConstituentSelection(s string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, ok := domain.CanonicalConstituentName(field)
+		if !ok {
+			return nil, fmt.Errorf("unknown constituent %q", field)
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no constituents in %q (want a comma-separated list like M2,S2,K1,O1)", s)
+	}
+	return names, nil
+}
+
+// selectConstituents restricts constituents to the selected names. Names
+// the set lacks are read one by one from loader, e.g. Sa on a server
+// configured with fewer constituents; without a loader (station queries)
+// they are an error.
+func selectConstituents(constituents []domain.ConstituentParam, names []string, loader store.ConstituentLoader, lat, lon float64) ([]domain.ConstituentParam, error) {
+	loaded := make(map[string]domain.ConstituentParam, len(constituents))
+	for _, c := range constituents {
+		loaded[c.Name] = c
+	}
+	selected := make([]domain.ConstituentParam, 0, len(names))
+	for _, name := range names {
+		if c, ok := loaded[name]; ok {
+			selected = append(selected, c)
+			continue
+		}
+		if loader == nil {
+			return nil, fmt.Errorf("constituent %s is not available for this station", name)
+		}
+		c, err := store.LoadConstituentAt(loader, name, lat, lon)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load selected constituent %s: %w", name, err)
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
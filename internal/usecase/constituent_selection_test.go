@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"testing"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestParseConstituentSelection(t *testing.T) {
+	got, err := ParseConstituentSelection(" m2,S2,,sa,M2")
+	if err != nil || len(got) != 3 || got[0] != "M2" || got[1] != "S2" || got[2] != "Sa" {
+		t.Errorf("ParseConstituentSelection = %v, %v, want [M2 S2 Sa]", got, err)
+	}
+	for _, s := range []string{"M2,XX9", ","} {
+		if _, err := ParseConstituentSelection(s); err == nil {
+			t.Errorf("ParseConstituentSelection(%q) succeeded", s)
+		}
+	}
+}
+
+func TestSelectConstituents(t *testing.T) {
+	loaded := []domain.ConstituentParam{{Name: "M2", AmplitudeM: 0.5}, {Name: "S2", AmplitudeM: 0.2}, {Name: "K1", AmplitudeM: 0.3}}
+	dataset := fallbackLoader{params: []domain.ConstituentParam{{Name: "Sa", AmplitudeM: 0.1}}}
+
+	got, err := selectConstituents(loaded, []string{"K1", "M2", "Sa"}, dataset, 35, 139)
+	if err != nil {
+		t.Fatalf("selectConstituents: %v", err)
+	}
+	if len(got) != 3 || got[0].Name != "K1" || got[1].Name != "M2" || got[2].Name != "Sa" || got[2].AmplitudeM != 0.1 {
+		t.Errorf("selected = %+v, want K1 and M2 plus Sa from the dataset", got)
+	}
+
+	if _, err := selectConstituents(loaded, []string{"M2", "Sa"}, nil, 0, 0); err == nil {
+		t.Error("station without Sa: expected an error")
+	}
+}
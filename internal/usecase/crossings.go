@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// CrossingsResponse lists the times the tide crosses a target height.
+type CrossingsResponse struct {
+	Source        string            `json:"source"`
+	Datum         string            `json:"datum"`
+	Timezone      string            `json:"timezone"`
+	TargetHeightM float64           `json:"target_height_m"`
+	Crossings     []CrossingPoint   `json:"crossings"`
+	Meta          map[string]string `json:"meta"`
+	Fingerprint   string            `json:"fingerprint"`
+	Scenario      *SLRScenario      `json:"scenario,omitempty"` // Set for sea level rise scenarios.
+	Degradation
+}
+
+// CrossingPoint is one crossing of the target height.
+type CrossingPoint struct {
+	Time      string `json:"time"`
+	Direction string `json:"direction"` // "rising" or "falling".
+}
+
+// FindCrossings returns all times within the request window at which the
+// predicted height (relative to the request datum) crosses targetM. The
+// request interval only needs to pass validation; crossings are solved on
+// the model heights to one-second resolution.
+func (uc *PredictionUseCase) FindCrossings(req PredictionRequest, targetM float64) (*CrossingsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	prepared, err := uc.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, tzLabel := outputZone(req.Timezone)
+	crossings := domain.FindCrossings(req.Start, req.End, targetM, prepared.params)
+	points := make([]CrossingPoint, len(crossings))
+	for i, c := range crossings {
+		points[i] = CrossingPoint{Time: c.Time.In(loc).Format(time.RFC3339), Direction: c.Direction}
+	}
+
+	provenance := newComputationProvenance(uc.codeVersion, uc.tables, prepared)
+	meta := provenance.Meta()
+	prepared.addNowcastMeta(meta)
+	prepared.addLandMotionMeta(meta)
+	prepared.addEnsembleMeta(meta)
+	prepared.addFallbackMeta(meta)
+	return &CrossingsResponse{
+		Source:        prepared.source,
+		Datum:         prepared.datumName(),
+		Timezone:      tzLabel,
+		TargetHeightM: targetM,
+		Crossings:     points,
+		Meta:          meta,
+		Fingerprint:   provenance.Fingerprint(),
+		Scenario:      prepared.scenario,
+		Degradation:   prepared.degradation(),
+	}, nil
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.ngs.io/tides-api/internal/licensing"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// currentAxisDays is the hourly window from the start of a request over
+// which the flood-ebb axis is estimated, long enough to cover the
+// spring-neap cycle whatever the requested range.
+const currentAxisDays = 30
+
+// CurrentsResponse is a tidal current prediction.
+type CurrentsResponse struct {
+	Source   string `json:"source"`
+	Timezone string `json:"timezone"`
+	// FloodDirectionDeg is the direction the flood flows toward, clockwise
+	// from true north; the ebb flows the opposite way.
+	FloodDirectionDeg float64             `json:"flood_direction_deg"`
+	EbbDirectionDeg   float64             `json:"ebb_direction_deg"`
+	Constituents      []string            `json:"constituents"`
+	Currents          []CurrentPoint      `json:"currents"`
+	Events            []CurrentEventPoint `json:"events"`
+	Meta              map[string]string   `json:"meta"`
+}
+
+// CurrentPoint is the predicted current at one time.
+type CurrentPoint struct {
+	Time         string  `json:"time"`
+	SpeedMS      float64 `json:"speed_ms"`
+	DirectionDeg float64 `json:"direction_deg"` // Direction flowed toward.
+	EastMS       float64 `json:"east_ms"`
+	NorthMS      float64 `json:"north_ms"`
+}
+
+// CurrentEventPoint is a slack water or a maximum flood or ebb current.
+type CurrentEventPoint struct {
+	Time         string  `json:"time"`
+	Type         string  `json:"type"` // "slack", "max_flood" or "max_ebb".
+	SpeedMS      float64 `json:"speed_ms"`
+	DirectionDeg float64 `json:"direction_deg"`
+}
+
+// PredictCurrents predicts the tidal current at a location from the
+// eastward and northward velocity constituents, with the times of slack
+// water and maximum flood and ebb in the request window. The flood
+// direction is the principal axis of the current, oriented along the flow
+// while the FES tide rises; without FES heights at the location the axis
+// is left unoriented.
+func (uc *PredictionUseCase) PredictCurrents(req PredictionRequest) (*CurrentsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.Lat == nil || req.Lon == nil {
+		return nil, errors.New("tidal currents need lat/lon parameters")
+	}
+	if len(req.Select) > 0 {
+		return nil, errors.New("tidal currents do not support constituent selection")
+	}
+	lat, lon := *req.Lat, *req.Lon
+	params, err := uc.currentParams(lat, lon)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := map[string]string{
+		"attribution": licensing.Attribution(licensing.FESCurrents),
+	}
+	axisStart := req.Start.UTC().Truncate(time.Hour)
+	axisSeries := domain.GenerateCurrents(axisStart, axisStart.Add(currentAxisDays*24*time.Hour), time.Hour, params)
+	flood := domain.PrincipalAxisDeg(axisSeries)
+	var heights []domain.ConstituentParam
+	if uc.fesStore != nil && *uc.fesStore != nil {
+		heights, _ = (*uc.fesStore).LoadForLocation(lat, lon)
+	}
+	if len(heights) > 0 {
+		tide := currentComponent(heights, lon)
+		rising := make([]float64, len(axisSeries))
+		for i, c := range axisSeries {
+			rising[i] = domain.CalculateTideHeight(c.Time.Add(time.Minute), tide) - domain.CalculateTideHeight(c.Time, tide)
+		}
+		flood = domain.OrientFlood(flood, axisSeries, rising)
+	} else {
+		meta["flood_direction"] = "unoriented (no FES heights at this location)"
+	}
+
+	loc, tzLabel := outputZone(req.Timezone)
+	series := domain.GenerateCurrents(req.Start, req.End, req.Interval, params)
+	currents := make([]CurrentPoint, len(series))
+	for i, c := range series {
+		currents[i] = CurrentPoint{
+			Time:         c.Time.In(loc).Format(time.RFC3339),
+			SpeedMS:      roundToDecimal(c.SpeedMS),
+			DirectionDeg: roundToDecimal(c.DirectionDeg),
+			EastMS:       roundToDecimal(c.EastMS),
+			NorthMS:      roundToDecimal(c.NorthMS),
+		}
+	}
+	found := domain.FindCurrentEvents(req.Start, req.End, params, flood)
+	events := make([]CurrentEventPoint, len(found))
+	for i, e := range found {
+		events[i] = CurrentEventPoint{
+			Time:         e.Time.In(loc).Format(time.RFC3339),
+			Type:         e.Type,
+			SpeedMS:      roundToDecimal(e.Velocity.SpeedMS),
+			DirectionDeg: roundToDecimal(e.Velocity.DirectionDeg),
+		}
+	}
+
+	names := make([]string, len(params.East.Constituents))
+	for i, c := range params.East.Constituents {
+		names[i] = c.Name
+	}
+	return &CurrentsResponse{
+		Source:            sourceFES,
+		Timezone:          tzLabel,
+		FloodDirectionDeg: roundToDecimal(flood),
+		EbbDirectionDeg:   roundToDecimal(domain.NormalizePhaseDeg(flood + 180)),
+		Constituents:      names,
+		Currents:          currents,
+		Events:            events,
+		Meta:              meta,
+	}, nil
+}
+
+// currentParams loads the velocity constituents at a location.
+func (uc *PredictionUseCase) currentParams(lat, lon float64) (domain.CurrentParams, error) {
+	if uc.currentStore == nil {
+		return domain.CurrentParams{}, errors.New("tidal currents are not configured")
+	}
+	east, north, err := uc.currentStore.LoadCurrentsForLocation(lat, lon)
+	if err != nil {
+		return domain.CurrentParams{}, fmt.Errorf("failed to load current constituents: %w", err)
+	}
+	// Both components list the same constituents, so they sort alike.
+	east, north = domain.SortedConstituents(east), domain.SortedConstituents(north)
+	return domain.CurrentParams{East: currentComponent(east, lon), North: currentComponent(north, lon)}, nil
+}
+
+// currentComponent returns the prediction parameters of FES constituents,
+// whose phases are Greenwich lags referenced to 2012.
+func currentComponent(constituents []domain.ConstituentParam, lon float64) domain.PredictionParams {
+	refTime := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodal := domain.NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(refTime)
+	return domain.PredictionParams{
+		Constituents:    constituents,
+		Longitude:       lon,
+		NodalCorrection: nodal,
+		ReferenceTime:   refTime,
+		PhaseConvention: domain.PhaseConvFESGreenwich,
+	}
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// dashboardTTL bounds how often the dashboard snapshot is recomputed.
+	dashboardTTL = time.Minute
+	// dashboardHorizon is how far ahead to search for the next extremum.
+	// One M2 period plus margin guarantees at least one high and one low.
+	dashboardHorizon = 13 * time.Hour
+)
+
+// ExtremumEvent is a predicted high or low water.
+type ExtremumEvent struct {
+	Type    string  `json:"type"` // "high" or "low".
+	Time    string  `json:"time"`
+	HeightM float64 `json:"height_m"`
+}
+
+// StationStatus is a dashboard snapshot of a monitored station.
+type StationStatus struct {
+	Station            string            `json:"station"`
+	Name               string            `json:"name"`
+	Lat                float64           `json:"lat"`
+	Lon                float64           `json:"lon"`
+	PredictedHeightM   *float64          `json:"predicted_height_m,omitempty"`
+	Trend              string            `json:"trend,omitempty"` // "rising" or "falling".
+	TrendMPerHr        *float64          `json:"trend_m_per_hr,omitempty"`
+	NextExtremum       *ExtremumEvent    `json:"next_extremum,omitempty"`
+	ResidualM          *float64          `json:"residual_m,omitempty"`
+	ResidualObservedAt string            `json:"residual_observed_at,omitempty"`
+	AlertLevel         domain.AlertLevel `json:"alert_level,omitempty"`
+	Error              string            `json:"error,omitempty"`
+}
+
+// DashboardResponse is the multi-station dashboard payload.
+type DashboardResponse struct {
+	GeneratedAt string          `json:"generated_at"`
+	Stations    []StationStatus `json:"stations"`
+}
+
+// dashboardCache holds the last computed dashboard snapshot.
+type dashboardCache struct {
+	mu       sync.Mutex
+	snapshot *DashboardResponse
+	at       time.Time
+}
+
+// Dashboard returns the current prediction, trend, next extremum and latest
+// observation residual for every monitored station. The snapshot is cached
+// for up to a minute so frequently refreshing dashboards stay cheap.
+func (m *MonitorUseCase) Dashboard() *DashboardResponse {
+	m.dashboard.mu.Lock()
+	defer m.dashboard.mu.Unlock()
+
+	now := m.predictionUC.Clock().Now().UTC()
+	if m.dashboard.snapshot != nil && now.Sub(m.dashboard.at) < dashboardTTL {
+		return m.dashboard.snapshot
+	}
+
+	alerts := make(map[string]SurgeAlert)
+	for _, a := range m.Alerts() {
+		alerts[a.Station] = a
+	}
+
+	resp := &DashboardResponse{
+		GeneratedAt: now.Format(time.RFC3339),
+		Stations:    make([]StationStatus, 0, len(m.stations)),
+	}
+	for _, st := range m.stations {
+		status := m.stationStatus(st, now)
+		if a, ok := alerts[st.Station]; ok {
+			status.ResidualM = a.LatestResidualM
+			status.ResidualObservedAt = a.ObservedAt
+			status.AlertLevel = a.Level
+		}
+		resp.Stations = append(resp.Stations, status)
+	}
+
+	m.dashboard.snapshot = resp
+	m.dashboard.at = now
+	return resp
+}
+
+func (m *MonitorUseCase) stationStatus(st MonitoredStation, now time.Time) StationStatus {
+	status := StationStatus{
+		Station: st.Station,
+		Name:    st.Name,
+		Lat:     st.Lat,
+		Lon:     st.Lon,
+	}
+
+	lat, lon := st.Lat, st.Lon
+	start := now.Truncate(time.Minute)
+	series, err := m.predictionUC.PredictSeries(PredictionRequest{
+		Lat:      &lat,
+		Lon:      &lon,
+		Start:    start,
+		End:      start.Add(dashboardHorizon),
+		Interval: time.Minute,
+	})
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if len(series) < 2 {
+		status.Error = "not enough predictions"
+		return status
+	}
+
+	current := roundToDecimal(series[0].HeightM)
+	status.PredictedHeightM = &current
+
+	rate := (series[1].HeightM - series[0].HeightM) / series[1].Time.Sub(series[0].Time).Hours()
+	roundedRate := roundToDecimal(rate)
+	status.TrendMPerHr = &roundedRate
+	if rate >= 0 {
+		status.Trend = "rising"
+	} else {
+		status.Trend = "falling"
+	}
+
+	status.NextExtremum = nextExtremum(series)
+	return status
+}
+
+// nextExtremum returns the earliest high or low water in the series.
+func nextExtremum(series []domain.TideLevel) *ExtremumEvent {
+	extrema := domain.RefineExtrema(series, domain.FindExtrema(series))
+
+	var (
+		next domain.TideLevel
+		kind string
+	)
+	if len(extrema.Highs) > 0 {
+		next, kind = extrema.Highs[0], "high"
+	}
+	if len(extrema.Lows) > 0 && (kind == "" || extrema.Lows[0].Time.Before(next.Time)) {
+		next, kind = extrema.Lows[0], "low"
+	}
+	if kind == "" {
+		return nil
+	}
+	return &ExtremumEvent{
+		Type:    kind,
+		Time:    next.Time.UTC().Format(time.RFC3339),
+		HeightM: roundToDecimal(next.HeightM),
+	}
+}
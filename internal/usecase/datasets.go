@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"go.ngs.io/tides-api/internal/adapter/store/bathymetry"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// DatasetSummary identifies the model, nodal coefficients and station
+// tables predictions use, as recorded in response provenance.
+type DatasetSummary struct {
+	Model         string   `json:"model"`
+	NodalCoeffs   string   `json:"nodal_coeffs"`
+	StationTables string   `json:"station_tables"`
+	Ensemble      []string `json:"ensemble,omitempty"`
+	VLM           string   `json:"vlm,omitempty"` // Land motion grid of include_vlm requests.
+	SLRScenarios  []string `json:"slr_scenarios,omitempty"`
+	SeismicEvents []string `json:"seismic_events,omitempty"`
+}
+
+// Datasets summarizes the datasets in use.
+func (uc *PredictionUseCase) Datasets() DatasetSummary {
+	summary := DatasetSummary{
+		Model:         uc.model.Name(),
+		NodalCoeffs:   domain.NewAstronomicalNodalCorrection().CoeffsVersion(),
+		StationTables: uc.tables.digest(),
+	}
+	for _, m := range uc.ensemble {
+		summary.Ensemble = append(summary.Ensemble, m.Name)
+	}
+	if uc.landMotion != nil {
+		summary.VLM = uc.landMotion.Name()
+	}
+	for _, s := range uc.scenarios {
+		summary.SLRScenarios = append(summary.SLRScenarios, s.Name)
+	}
+	for _, e := range uc.seismicEvents {
+		summary.SeismicEvents = append(summary.SeismicEvents, e.Name)
+	}
+	return summary
+}
+
+// Features lists the optional prediction capabilities that are configured.
+type Features struct {
+	Bathymetry       bool `json:"bathymetry"` // Seabed depth and water depth.
+	MSS              bool `json:"mss"`        // Mean sea surface heights.
+	Geoid            bool `json:"geoid"`      // Geoid correction of the mean sea surface.
+	ConstituentCache bool `json:"constituent_cache"`
+	Ensemble         bool `json:"ensemble"`
+	Nowcast          bool `json:"nowcast"`
+	DatumEstimate    bool `json:"datum_estimate"`
+	VLM              bool `json:"vlm"` // Vertical land motion (include_vlm requests).
+}
+
+// Features reports the optional capabilities configured.
+func (uc *PredictionUseCase) Features() Features {
+	_, cached := uc.ConstituentCacheStats()
+	features := Features{
+		ConstituentCache: cached,
+		Ensemble:         len(uc.ensemble) > 0,
+		Nowcast:          uc.nowcaster != nil,
+		DatumEstimate:    uc.observations != nil,
+		VLM:              uc.landMotion != nil,
+	}
+	stores, _ := uc.StoreHealth()
+	for _, s := range stores {
+		switch s.Component {
+		case bathymetry.ComponentGEBCO:
+			features.Bathymetry = true
+		case bathymetry.ComponentMSS:
+			features.MSS = true
+		case bathymetry.ComponentGeoid:
+			features.Geoid = true
+		}
+	}
+	return features
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// maxDatumEstimateSpan bounds the observation period of an estimate.
+	maxDatumEstimateSpan = 366 * 24 * time.Hour
+	// minDatumEstimatePoints is the fewest paired hours accepted; a day
+	// averages out the main tidal cycles.
+	minDatumEstimatePoints = 24
+)
+
+var (
+	// ErrNoObservationSource reports an operation needing past observations
+	// on a server without an observation source.
+	ErrNoObservationSource = errors.New("no observation source configured")
+	// ErrInvalidDatumEstimate reports a datum estimate request that cannot
+	// be served as given, such as a missing station or too few observations
+	// in its period.
+	ErrInvalidDatumEstimate = errors.New("invalid datum estimate request")
+	// ErrObservationFetch reports observations that could not be read from
+	// the observation source.
+	ErrObservationFetch = errors.New("reading observations failed")
+)
+
+// ObservationHistory provides station observations over past periods.
+type ObservationHistory interface {
+	// Between returns observations in [start, end], in time order.
+	Between(station string, start, end time.Time) ([]domain.TideLevel, error)
+}
+
+// SetObservationHistory sets where station observations are read from
+// (e.g., JMA hourly files) for datum offset estimates.
+func (uc *PredictionUseCase) SetObservationHistory(h ObservationHistory) {
+	uc.observations = h
+}
+
+// DatumEstimateRequest selects a JMA station and observation period.
+type DatumEstimateRequest struct {
+	Station    string
+	Lat, Lon   *float64 // Default: the station's datum offset entry.
+	Start, End time.Time
+	// Write stores the estimate in the datum offset table.
+	Write bool
+}
+
+// DatumEstimate is the recommended datum offset for a station: the mean of
+// observed minus predicted heights, predicted without any datum offset.
+type DatumEstimate struct {
+	Station      string  `json:"station"`
+	Lat          float64 `json:"lat"`
+	Lon          float64 `json:"lon"`
+	Start        string  `json:"start"`
+	End          string  `json:"end"`
+	PairedPoints int     `json:"paired_points"`
+	DatumOffsetM float64 `json:"datum_offset_m"`
+	RMSEM        float64 `json:"rmse_m"` // Residual RMSE around the mean.
+	MinResidualM float64 `json:"min_residual_m"`
+	MaxResidualM float64 `json:"max_residual_m"`
+	// CurrentOffsetM is the station's entry before the estimate, if any.
+	CurrentOffsetM *float64 `json:"current_offset_m,omitempty"`
+	Written        bool     `json:"written"`
+	// StationTables is the table digest after writing.
+	StationTables string `json:"station_tables,omitempty"`
+}
+
+// EstimateDatumOffset compares a station's observations over a period with
+// predictions at its location, as cmd/jma-compare does, and optionally
+// writes the result to the datum offset table.
+func (uc *PredictionUseCase) EstimateDatumOffset(req DatumEstimateRequest) (*DatumEstimate, error) {
+	if req.Station == "" {
+		return nil, fmt.Errorf("%w: station is required", ErrInvalidDatumEstimate)
+	}
+	if !req.Start.Before(req.End) {
+		return nil, fmt.Errorf("%w: start time must be before end time", ErrInvalidDatumEstimate)
+	}
+	if req.End.Sub(req.Start) > maxDatumEstimateSpan {
+		return nil, fmt.Errorf("%w: period must be at most 366 days", ErrInvalidDatumEstimate)
+	}
+	if uc.observations == nil {
+		return nil, ErrNoObservationSource
+	}
+
+	current, known := uc.tables.datumEntry(req.Station)
+	var lat, lon float64
+	switch {
+	case req.Lat != nil && req.Lon != nil:
+		lat, lon = *req.Lat, *req.Lon
+	case known:
+		lat, lon = current.Lat, current.Lon
+	default:
+		return nil, fmt.Errorf("%w: station %s has no datum offset entry - lat and lon are required", ErrInvalidDatumEstimate, req.Station)
+	}
+	if err := (&PredictionRequest{Lat: &lat, Lon: &lon}).validateTarget(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatumEstimate, err)
+	}
+
+	observed, err := uc.observations.Between(req.Station, req.Start, req.End)
+	if err != nil {
+		return nil, fmt.Errorf("%w for station %s: %w", ErrObservationFetch, req.Station, err)
+	}
+	if len(observed) < minDatumEstimatePoints {
+		return nil, fmt.Errorf("%w: not enough observations for station %s (%d, need %d)", ErrInvalidDatumEstimate, req.Station, len(observed), minDatumEstimatePoints)
+	}
+
+	// An explicit zero offset disables the table's offset for the location.
+	zero := 0.0
+	predicted, err := uc.PredictSeries(PredictionRequest{
+		Lat:          &lat,
+		Lon:          &lon,
+		Start:        observed[0].Time,
+		End:          observed[len(observed)-1].Time,
+		Interval:     time.Hour,
+		DatumOffsetM: &zero,
+	})
+	if errors.Is(err, domain.ErrOutOfCoverage) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatumEstimate, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	residuals := domain.Residuals(observed, predicted)
+	if len(residuals) < minDatumEstimatePoints {
+		return nil, fmt.Errorf("%w: not enough observations pair with hourly predictions (%d, need %d)", ErrInvalidDatumEstimate, len(residuals), minDatumEstimatePoints)
+	}
+
+	estimate := &DatumEstimate{
+		Station:      req.Station,
+		Lat:          lat,
+		Lon:          lon,
+		Start:        req.Start.UTC().Format(time.RFC3339),
+		End:          req.End.UTC().Format(time.RFC3339),
+		PairedPoints: len(residuals),
+		MinResidualM: math.Inf(1),
+		MaxResidualM: math.Inf(-1),
+	}
+	var sum, sse float64
+	for _, r := range residuals {
+		sum += r.HeightM
+		estimate.MinResidualM = math.Min(estimate.MinResidualM, r.HeightM)
+		estimate.MaxResidualM = math.Max(estimate.MaxResidualM, r.HeightM)
+	}
+	mean := sum / float64(len(residuals))
+	for _, r := range residuals {
+		sse += (r.HeightM - mean) * (r.HeightM - mean)
+	}
+	estimate.DatumOffsetM = math.Round(mean*1e6) / 1e6
+	estimate.RMSEM = roundToDecimal(math.Sqrt(sse / float64(len(residuals))))
+	estimate.MinResidualM = roundToDecimal(estimate.MinResidualM)
+	estimate.MaxResidualM = roundToDecimal(estimate.MaxResidualM)
+	if known {
+		estimate.CurrentOffsetM = &current.OffsetM
+	}
+
+	if req.Write {
+		entry := datumOffsetEntry{Name: req.Station, Lat: lat, Lon: lon, OffsetM: estimate.DatumOffsetM}
+		if err := uc.tables.setDatumOffset(entry); err != nil {
+			return nil, err
+		}
+		estimate.Written = true
+		estimate.StationTables = uc.tables.digest()
+	}
+	return estimate, nil
+}
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// maxCachedDatumTables bounds the datum tables kept in memory.
+const maxCachedDatumTables = 1024
+
+// datumEpochStart and datumEpochYears set the synthesis of tidal datums:
+// 19 years, covering the 18.6-year nodal cycle as national tidal datum
+// epochs do.
+//
+//nolint:gochecknoglobals // Intentional: Fixed datum epoch.
+var datumEpochStart = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const datumEpochYears = 19
+
+// DatumCache persists computed datum tables by a key identifying their
+// computation.
+type DatumCache interface {
+	Get(key string) ([]byte, bool, error)
+	Put(key string, data []byte) error
+}
+
+// DatumTableResponse lists the tidal datums of a location.
+type DatumTableResponse struct {
+	Source string `json:"source"`
+	// Datum is the reference of the values, as for predictions.
+	Datum       string            `json:"datum"`
+	EpochStart  string            `json:"epoch_start"`
+	EpochEnd    string            `json:"epoch_end"`
+	Datums      []TidalDatum      `json:"datums"`
+	Meta        map[string]string `json:"meta"`
+	Fingerprint string            `json:"fingerprint"`
+	// Cached is set when the table was computed by an earlier request.
+	Cached   bool         `json:"cached"`
+	Scenario *SLRScenario `json:"scenario,omitempty"`
+	Degradation
+}
+
+// TidalDatum is one row of a datum table.
+type TidalDatum struct {
+	Name        string  `json:"name"`
+	ValueM      float64 `json:"value_m"`
+	Description string  `json:"description"`
+}
+
+// datumTables holds the datum tables computed or read by this process.
+type datumTables struct {
+	mu     sync.Mutex
+	byKey  map[string]*DatumTableResponse
+	backer DatumCache // Optional persistent store.
+}
+
+// SetDatumCache persists computed datum tables, e.g., across restarts.
+func (uc *PredictionUseCase) SetDatumCache(c DatumCache) {
+	uc.datums.mu.Lock()
+	defer uc.datums.mu.Unlock()
+	uc.datums.backer = c
+}
+
+// DatumCache returns the persistent datum table store, or nil.
+func (uc *PredictionUseCase) DatumCache() DatumCache {
+	uc.datums.mu.Lock()
+	defer uc.datums.mu.Unlock()
+	return uc.datums.backer
+}
+
+// TidalDatums returns the datum table of a location or station, derived
+// from a 19-year synthesis. The start, end and interval of req are
+// ignored. Tables are cached by the fingerprint of their computation, so
+// a change of dataset, station tables or code version recomputes them.
+// With a tidal datum other than MSL, values are relative to that datum.
+func (uc *PredictionUseCase) TidalDatums(req PredictionRequest) (*DatumTableResponse, error) {
+	if err := req.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if req.Nowcast {
+		return nil, fmt.Errorf("invalid request: nowcast does not apply to tidal datums")
+	}
+	datum, err := resolveDatum(req.Datum)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Datum = ""
+	table, err := uc.tidalDatumTable(req)
+	if err != nil || datum == domain.DatumMSL {
+		return table, err
+	}
+	level, _ := table.level(datum)
+	shifted := *table
+	shifted.Datum = datum
+	shifted.Datums = make([]TidalDatum, len(table.Datums))
+	for i, row := range table.Datums {
+		if domain.IsTidalDatum(row.Name) {
+			row.ValueM = roundToDecimal(row.ValueM - level)
+		}
+		shifted.Datums[i] = row
+	}
+	return &shifted, nil
+}
+
+// tidalDatumTable returns the datum table of a validated request,
+// relative to the model's mean sea level, from the cache when computed
+// before. Seismic events are left out: one during the epoch would shift
+// the datums by part of its displacement.
+func (uc *PredictionUseCase) tidalDatumTable(req PredictionRequest) (*DatumTableResponse, error) {
+	req.Start = datumEpochStart
+	req.End = datumEpochStart.AddDate(datumEpochYears, 0, 0)
+	req.withoutSeismic = true
+
+	prepared, err := uc.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	provenance := newComputationProvenance(uc.codeVersion, uc.tables, prepared)
+	fingerprint := provenance.Fingerprint()
+	key := "datums:" + req.Start.Format(time.DateOnly) + ":" + fingerprint
+	if table, ok := uc.datums.get(key); ok {
+		return table, nil
+	}
+
+	d, ok := domain.ComputeTidalDatums(req.Start, req.End, prepared.params)
+	if !ok {
+		return nil, fmt.Errorf("no tides found at the location")
+	}
+	meta := provenance.Meta()
+	prepared.addLandMotionMeta(meta)
+	prepared.addEnsembleMeta(meta)
+	prepared.addFallbackMeta(meta)
+	table := &DatumTableResponse{
+		Source:     prepared.source,
+		Datum:      domain.DatumMSL,
+		EpochStart: req.Start.Format(time.DateOnly),
+		EpochEnd:   req.End.Format(time.DateOnly),
+		Datums: []TidalDatum{
+			{Name: "HAT", ValueM: roundToDecimal(d.HAT), Description: "Highest astronomical tide"},
+			{Name: "MHHW", ValueM: roundToDecimal(d.MHHW), Description: "Mean higher high water"},
+			{Name: "MHW", ValueM: roundToDecimal(d.MHW), Description: "Mean high water"},
+			{Name: "MSL", ValueM: roundToDecimal(d.MSL), Description: "Mean sea level"},
+			{Name: "MLW", ValueM: roundToDecimal(d.MLW), Description: "Mean low water"},
+			{Name: "MLLW", ValueM: roundToDecimal(d.MLLW), Description: "Mean lower low water"},
+			{Name: "LAT", ValueM: roundToDecimal(d.LAT), Description: "Lowest astronomical tide"},
+			{Name: "MN", ValueM: roundToDecimal(d.MN), Description: "Mean range of tide (MHW - MLW)"},
+			{Name: "GT", ValueM: roundToDecimal(d.GT), Description: "Great diurnal range (MHHW - MLLW)"},
+		},
+		Meta:        meta,
+		Fingerprint: fingerprint,
+		Scenario:    prepared.scenario,
+		Degradation: prepared.degradation(),
+	}
+	// Tables computed without some configured data are not kept.
+	if !table.Degraded {
+		uc.datums.put(key, table)
+	}
+	return table, nil
+}
+
+// level returns the value of a tidal datum row of the table.
+func (t *DatumTableResponse) level(name string) (float64, bool) {
+	for _, row := range t.Datums {
+		if row.Name == name {
+			return row.ValueM, true
+		}
+	}
+	return 0, false
+}
+
+// resolveDatum returns the canonical name of a datum parameter: a tidal
+// datum, with MSL for none.
+func resolveDatum(datum string) (string, error) {
+	if datum == "" {
+		return domain.DatumMSL, nil
+	}
+	if !domain.IsTidalDatum(datum) {
+		return "", fmt.Errorf("unsupported datum %q (expected HAT, MHHW, MHW, MSL, MLW, MLLW or LAT)", datum)
+	}
+	return strings.ToUpper(datum), nil
+}
+
+// appliedTidalDatum is the tidal datum prepared heights are referred to.
+type appliedTidalDatum struct {
+	name   string
+	levelM float64 // Level of the datum above the model's mean sea level.
+}
+
+// applyTidalDatum refers the prepared heights to the request's tidal
+// datum, from the datum table of the same target and dataset. Datums are
+// taken from the astronomical tide of the whole constituent set: nowcasts,
+// sea level rise scenarios, land motion, seismic events and constituent
+// selections do not move them. MSL leaves heights unchanged.
+func (uc *PredictionUseCase) applyTidalDatum(req PredictionRequest, p *preparedPrediction) error {
+	datum, err := resolveDatum(req.Datum)
+	if err != nil || datum == domain.DatumMSL {
+		return err
+	}
+	base := req
+	base.Datum, base.Nowcast, base.IncludeVLM = "", false, false
+	base.SLRM, base.SLRScenario, base.Select = nil, "", nil
+	table, err := uc.tidalDatumTable(base)
+	if err != nil {
+		return fmt.Errorf("failed to derive datum %s: %w", datum, err)
+	}
+	level, _ := table.level(datum)
+	model := p.params.Model
+	if model == nil {
+		model = domain.HarmonicModel{}
+	}
+	p.params.Model = domain.DatumShiftModel{Base: model, LevelM: level}
+	p.tidalDatum = &appliedTidalDatum{name: datum, levelM: level}
+	return nil
+}
+
+// datumName returns the datum heights are referred to.
+func (p *preparedPrediction) datumName() string {
+	if p.tidalDatum == nil {
+		return domain.DatumMSL
+	}
+	return p.tidalDatum.name
+}
+
+// datumLevel returns the level of the applied tidal datum, or 0.
+func (p *preparedPrediction) datumLevel() float64 {
+	if p.tidalDatum == nil {
+		return 0
+	}
+	return p.tidalDatum.levelM
+}
+
+// get returns a cached table, flagged as cached.
+func (c *datumTables) get(key string) (*DatumTableResponse, bool) {
+	c.mu.Lock()
+	table, ok := c.byKey[key]
+	backer := c.backer
+	c.mu.Unlock()
+	if !ok && backer != nil {
+		data, found, err := backer.Get(key)
+		if err != nil {
+			log.Printf("Warning: datum cache: %v", err)
+		}
+		if found {
+			table = &DatumTableResponse{}
+			if err := json.Unmarshal(data, table); err != nil {
+				log.Printf("Warning: datum cache: invalid table %s: %v", key, err)
+				return nil, false
+			}
+			c.remember(key, table)
+			ok = true
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	cached := *table
+	cached.Cached = true
+	return &cached, true
+}
+
+// put caches a table in memory and in the persistent store.
+func (c *datumTables) put(key string, table *DatumTableResponse) {
+	c.remember(key, table)
+	c.mu.Lock()
+	backer := c.backer
+	c.mu.Unlock()
+	if backer == nil {
+		return
+	}
+	data, err := json.Marshal(table)
+	if err == nil {
+		err = backer.Put(key, data)
+	}
+	if err != nil {
+		log.Printf("Warning: datum cache: %v", err)
+	}
+}
+
+// remember keeps a table in memory, starting over when full.
+func (c *datumTables) remember(key string, table *DatumTableResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byKey == nil || len(c.byKey) >= maxCachedDatumTables {
+		c.byKey = make(map[string]*DatumTableResponse)
+	}
+	c.byKey[key] = table
+}
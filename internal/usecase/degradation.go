@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.ngs.io/tides-api/internal/adapter/circuit"
+	"go.ngs.io/tides-api/internal/adapter/retry"
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/store/bathymetry"
+	"go.ngs.io/tides-api/internal/schema"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// ErrDataUnavailable reports that configured optional data (e.g., a
+// bathymetry file) is temporarily unreadable.
+var ErrDataUnavailable = errors.New("data temporarily unavailable")
+
+// Degradation flags a response computed without some configured optional
+// data, such as MSL or seabed depth from an unreadable file.
+type Degradation struct {
+	Degraded        bool     `json:"degraded,omitempty"`
+	DegradedReasons []string `json:"degraded_reasons,omitempty"`
+}
+
+// degradation returns the degradation recorded in the location metadata
+// and while preparing the prediction.
+func (p *preparedPrediction) degradation() Degradation {
+	var reasons []string
+	if p.metadata != nil {
+		reasons = append(reasons, p.metadata.Degraded...)
+	}
+	reasons = append(reasons, p.degraded...)
+	if len(reasons) == 0 {
+		return Degradation{}
+	}
+	return Degradation{Degraded: true, DegradedReasons: reasons}
+}
+
+// StoreHealth reports the health of the optional data files; ok is false
+// when the bathymetry store does not track health.
+func (uc *PredictionUseCase) StoreHealth() (health []bathymetry.ComponentHealth, ok bool) {
+	if uc.bathymetryStore == nil {
+		return nil, false
+	}
+	reporter, ok := uc.bathymetryStore.(bathymetry.HealthReporter)
+	if !ok {
+		return nil, false
+	}
+	return reporter.Health(), true
+}
+
+// TableHealth reports the load status of the station tables and the nodal
+// coefficient file. A table is unhealthy when its file exists but could
+// not be read or is invalid, in which case predictions go without it.
+func (uc *PredictionUseCase) TableHealth() []TableHealth {
+	health := uc.tables.health()
+	set, err := domain.DefaultNodalCoeffSet()
+	entries := 0
+	if set != nil {
+		entries = len(set.Coeffs)
+	}
+	return append(health, tableHealth(schema.AstroCoeffs, domain.NodalCoeffsPath(), entries, err))
+}
+
+// DatasetCircuits returns the FES datasets whose reads are failing; ok is
+// false when the loader has no circuit breakers.
+func (uc *PredictionUseCase) DatasetCircuits() (circuits []circuit.Status, ok bool) {
+	if uc.fesStore == nil {
+		return nil, false
+	}
+	reporter, ok := (*uc.fesStore).(store.CircuitReporter)
+	if !ok {
+		return nil, false
+	}
+	return reporter.Circuits(), true
+}
+
+// FileRetryStats returns the transient file read retries per dataset.
+func (uc *PredictionUseCase) FileRetryStats() map[string]retry.Stats {
+	return retry.Default().Stats()
+}
+
+// unavailableError wraps ErrDataUnavailable with the degradation reasons.
+func unavailableError(what string, reasons []string) error {
+	return fmt.Errorf("%s: %w (%s)", what, ErrDataUnavailable, strings.Join(reasons, "; "))
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// EnsembleMember is a dataset synthesized in ensemble predictions (e.g.,
+// FES2014, FES2022 or a regional model).
+type EnsembleMember struct {
+	Name   string
+	Loader store.ConstituentLoader
+}
+
+// ensemblePrediction records the members of an ensemble prediction.
+type ensemblePrediction struct {
+	members  []domain.EnsembleMember
+	excluded []string // Members without coverage at the location.
+}
+
+// SetEnsemble configures the datasets of ensemble requests.
+func (uc *PredictionUseCase) SetEnsemble(members []EnsembleMember) {
+	uc.ensemble = members
+}
+
+// Ensemble returns the datasets of ensemble requests.
+func (uc *PredictionUseCase) Ensemble() []EnsembleMember {
+	return uc.ensemble
+}
+
+// loadEnsemble loads every member's constituents at a location. Members
+// outside their coverage are excluded; other member failures degrade the
+// response. It fails only when no member loads.
+func (uc *PredictionUseCase) loadEnsemble(lat, lon float64) (*ensemblePrediction, []string, error) {
+	if len(uc.ensemble) == 0 {
+		return nil, nil, errors.New("ensemble predictions are not configured")
+	}
+	e := &ensemblePrediction{}
+	var degraded []string
+	for _, m := range uc.ensemble {
+		constituents, err := m.Loader.LoadForLocation(lat, lon)
+		switch {
+		case errors.Is(err, domain.ErrOutOfCoverage):
+			e.excluded = append(e.excluded, m.Name)
+		case err != nil:
+			degraded = append(degraded, fmt.Sprintf("ensemble: member %s: %v", m.Name, err))
+		default:
+			e.members = append(e.members, domain.EnsembleMember{Name: m.Name, Constituents: constituents})
+		}
+	}
+	if len(e.members) == 0 {
+		if len(degraded) > 0 {
+			return nil, nil, fmt.Errorf("no ensemble member loaded for location (%.4f, %.4f): %s", lat, lon, strings.Join(degraded, "; "))
+		}
+		return nil, nil, fmt.Errorf("no ensemble member covers location (%.4f, %.4f): %w", lat, lon, domain.ErrOutOfCoverage)
+	}
+	return e, degraded, nil
+}
+
+// addEnsembleMeta records the ensemble members in response metadata.
+func (p *preparedPrediction) addEnsembleMeta(meta map[string]string) {
+	if p.ensemble == nil {
+		return
+	}
+	names := make([]string, len(p.ensemble.members))
+	for i, m := range p.ensemble.members {
+		names[i] = m.Name
+	}
+	meta["ensemble_members"] = strings.Join(names, ",")
+	if len(p.ensemble.excluded) > 0 {
+		meta["ensemble_excluded"] = strings.Join(p.ensemble.excluded, ",")
+	}
+}
+
+// ensembleDigest identifies the member constituents in the fingerprint.
+func (e *ensemblePrediction) digest() string {
+	parts := make([]string, len(e.members))
+	for i, m := range e.members {
+		parts[i] = m.Name + "=" + constituentsDigest(m.Constituents)
+	}
+	return strings.Join(parts, ",")
+}
+
+// withSpread sets the member range of a point when the prediction is an
+// ensemble.
+func (p *preparedPrediction) withSpread(point PredictionPoint, t time.Time) PredictionPoint {
+	m, ok := p.params.Model.(domain.EnsembleModel)
+	if !ok {
+		return point
+	}
+	_, lo, hi := m.Spread(t, p.params)
+	lo, hi = roundToDecimal(lo), roundToDecimal(hi)
+	point.MinM, point.MaxM = &lo, &hi
+	return point
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// feedMinYear and feedMaxYear bound the year of a feed.
+	feedMinYear = 1900
+	feedMaxYear = 2100
+
+	// feedMargin pads each month predicted for a feed, so that extrema at
+	// month boundaries are found.
+	feedMargin = time.Hour
+)
+
+// FeedRequest selects the stations and year of a static extrema feed.
+type FeedRequest struct {
+	Year int
+	// Stations are station file or override IDs (see ListStations); nil
+	// exports every station.
+	Stations []string
+	Datum    string // Datum of heights (default MSL).
+	Timezone string // Timezone of timestamps: "utc" (default) or "jst".
+}
+
+// feedStation is a station of a feed and the request that predicts it.
+type feedStation struct {
+	info StationInfo
+	req  PredictionRequest
+}
+
+// WriteFeed writes a ZIP of the predicted highs and lows of a year, for
+// publishing tide tables without querying the API:
+//
+//   - feed_info.txt: the year, generation time, code version, model and
+//     attribution of the feed;
+//   - stations.txt: one row per station with its position, source, datum
+//     and fingerprint;
+//   - extrema/<station_id>.csv: the station's highs and lows in time order.
+//
+// The year is in the timezone of the request. Station files are predicted
+// by station_id and overrides at their position; each month is predicted
+// like a predictions request.
+func (uc *PredictionUseCase) WriteFeed(w io.Writer, req FeedRequest) error {
+	if req.Year < feedMinYear || req.Year > feedMaxYear {
+		return fmt.Errorf("year must be between %d and %d", feedMinYear, feedMaxYear)
+	}
+	stations, err := uc.feedStations(req)
+	if err != nil {
+		return err
+	}
+
+	// The months of all stations are predicted concurrently.
+	loc, _ := outputZone(req.Timezone)
+	responses := make([][12]*PredictionResponse, len(stations))
+	errs := make([][12]error, len(stations))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), 12*len(stations)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				i, m := job/12, job%12
+				monthReq := stations[i].req
+				monthReq.Start, monthReq.End = feedMonth(req.Year, m, loc)
+				monthReq.Start, monthReq.End = monthReq.Start.Add(-feedMargin), monthReq.End.Add(feedMargin)
+				responses[i][m], errs[i][m] = uc.execute(monthReq, *uc.fesStore)
+			}
+		}()
+	}
+	for job := range 12 * len(stations) {
+		jobs <- job
+	}
+	close(jobs)
+	wg.Wait()
+
+	zw := zip.NewWriter(w)
+	index := [][]string{{"station_id", "station_name", "station_lat", "station_lon", "source", "datum", "timezone", "fingerprint"}}
+	var attributions []string
+	for i, s := range stations {
+		rows := [][]string{{"time", "type", "height_m"}}
+		for m, response := range responses[i] {
+			start, end := feedMonth(req.Year, m, loc)
+			if err := errs[i][m]; err != nil {
+				return fmt.Errorf("station %s, %s: %w", s.info.ID, start.Format("2006-01"), err)
+			}
+			rows = append(rows, feedExtrema(response.Extrema, start, end)...)
+		}
+		last := responses[i][11]
+		if err := writeFeedCSV(zw, "extrema/"+feedFileName(s.info.ID)+".csv", rows); err != nil {
+			return err
+		}
+
+		index = append(index, []string{
+			s.info.ID, s.info.Name, formatOptionalCoord(s.info.Lat), formatOptionalCoord(s.info.Lon),
+			last.Source, last.Datum, last.Timezone, last.Fingerprint,
+		})
+		for _, a := range strings.Split(last.Meta["attribution"], "; ") {
+			if a != "" && !slices.Contains(attributions, a) {
+				attributions = append(attributions, a)
+			}
+		}
+	}
+	if err := writeFeedCSV(zw, "stations.txt", index); err != nil {
+		return err
+	}
+
+	info := [][]string{
+		{"feed_start_date", "feed_end_date", "generated_at", "code_version", "model", "attribution"},
+		{
+			fmt.Sprintf("%04d-01-01", req.Year), fmt.Sprintf("%04d-12-31", req.Year),
+			uc.clock.Now().UTC().Format(time.RFC3339), uc.codeVersion, uc.model.Name(), strings.Join(attributions, "; "),
+		},
+	}
+	if err := writeFeedCSV(zw, "feed_info.txt", info); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write feed: %w", err)
+	}
+	return nil
+}
+
+// feedMonth returns the start and end of month m (0 for January) of year.
+func feedMonth(year, m int, loc *time.Location) (start, end time.Time) {
+	start = time.Date(year, time.January+time.Month(m), 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// feedStations resolves the stations of a feed request, in ListStations
+// order. Overrides in force for several periods are exported once.
+func (uc *PredictionUseCase) feedStations(req FeedRequest) ([]feedStation, error) {
+	all, err := uc.ListStations(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	base := PredictionRequest{Interval: time.Hour, Datum: req.Datum, Timezone: req.Timezone}
+	var stations []feedStation
+	seen := make(map[string]bool)
+	for _, info := range all {
+		if seen[info.ID] || (req.Stations != nil && !slices.Contains(req.Stations, info.ID)) {
+			continue
+		}
+		seen[info.ID] = true
+		r := base
+		if info.Source == StationKindCSV {
+			id := info.ID
+			r.StationID = &id
+		} else {
+			r.Lat, r.Lon = info.Lat, info.Lon
+		}
+		stations = append(stations, feedStation{info: info, req: r})
+	}
+	for _, id := range req.Stations {
+		if !seen[id] {
+			return nil, fmt.Errorf("unknown station %q", id)
+		}
+	}
+	if len(stations) == 0 {
+		return nil, fmt.Errorf("no stations to export")
+	}
+	return stations, nil
+}
+
+// feedExtrema returns the highs and lows in [start, end) as CSV rows in
+// time order.
+func feedExtrema(extrema ExtremaResponse, start, end time.Time) [][]string {
+	type extremum struct {
+		at    time.Time
+		kind  string
+		point PredictionPoint
+	}
+	var all []extremum
+	for kind, points := range map[string][]PredictionPoint{"high": extrema.Highs, "low": extrema.Lows} {
+		for _, p := range points {
+			at, err := time.Parse(time.RFC3339, p.Time)
+			if err != nil || at.Before(start) || !at.Before(end) {
+				continue
+			}
+			all = append(all, extremum{at: at, kind: kind, point: p})
+		}
+	}
+	slices.SortFunc(all, func(a, b extremum) int { return a.at.Compare(b.at) })
+
+	rows := make([][]string, len(all))
+	for i, e := range all {
+		rows[i] = []string{e.point.Time, e.kind, strconv.FormatFloat(e.point.HeightM, 'f', -1, 64)}
+	}
+	return rows
+}
+
+// writeFeedCSV adds a CSV file to a feed.
+func writeFeedCSV(zw *zip.Writer, name string, rows [][]string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// feedFileName makes a station ID safe as a file name in the feed.
+func feedFileName(id string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(id)
+}
+
+// formatOptionalCoord formats a coordinate, or "" when unknown.
+func formatOptionalCoord(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
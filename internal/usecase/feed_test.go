@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// m2Station is a station store of one station with a 1 m M2 tide.
+type m2Station struct{ stationFiles }
+
+func (m2Station) LoadForStation(string) ([]domain.ConstituentParam, error) {
+	speed, _ := domain.GetConstituentSpeed("M2")
+	return []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: speed}}, nil
+}
+
+func (m2Station) ListStations() ([]string, error) {
+	return []string{"tokyo"}, nil
+}
+
+func TestWriteFeed(t *testing.T) {
+	uc := NewPredictionUseCase(m2Station{}, nil, nil)
+	var buf bytes.Buffer
+	if err := uc.WriteFeed(&buf, FeedRequest{Year: 2025}); err != nil {
+		t.Fatalf("WriteFeed: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	files := make(map[string][][]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("%s: %v", f.Name, err)
+		}
+		if files[f.Name], err = csv.NewReader(r).ReadAll(); err != nil {
+			t.Fatalf("%s: %v", f.Name, err)
+		}
+		_ = r.Close()
+	}
+
+	// Two highs and two lows per lunar day of 24.84 hours.
+	rows := files["extrema/tokyo.csv"]
+	if n := len(rows) - 1; n < 1405 || n > 1415 {
+		t.Errorf("extrema = %d, want ~1411", n)
+	}
+	if first, last := rows[1][0], rows[len(rows)-1][0]; first < "2025-01-01" || last >= "2026" {
+		t.Errorf("extrema from %s to %s, want within 2025", first, last)
+	}
+	for i := 2; i < len(rows); i++ {
+		if rows[i][1] == rows[i-1][1] {
+			t.Fatalf("rows %d and %d are both %s", i-1, i, rows[i][1])
+		}
+	}
+	if s := files["stations.txt"]; len(s) != 2 || s[1][0] != "tokyo" || s[1][4] != sourceCSV {
+		t.Errorf("stations.txt = %v", s)
+	}
+	if info := files["feed_info.txt"]; len(info) != 2 || info[1][0] != "2025-01-01" || info[1][1] != "2025-12-31" {
+		t.Errorf("feed_info.txt = %v", info)
+	}
+
+	if err := uc.WriteFeed(&buf, FeedRequest{Year: 2025, Stations: []string{"osaka"}}); err == nil {
+		t.Error("unknown station: expected an error")
+	}
+}
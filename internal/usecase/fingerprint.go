@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// defaultCodeVersion is reported when the server did not set a version.
+const defaultCodeVersion = "dev"
+
+// coeffsVersioner is implemented by nodal corrections backed by a coefficient file.
+type coeffsVersioner interface {
+	CoeffsVersion() string
+}
+
+// computationProvenance lists the inputs that determine predicted values.
+// Each component is stable across runs for identical inputs.
+type computationProvenance struct {
+	codeVersion   string
+	dataset       string // Source and digest of the resolved constituent parameters.
+	nodalCoeffs   string
+	stationTables string // Digest of the datum offset and station override tables.
+	pipeline      string // Phase convention, epoch and datum corrections.
+}
+
+// newComputationProvenance describes the computation for a prepared prediction.
+func newComputationProvenance(codeVersion string, tables *stationTables, p *preparedPrediction) computationProvenance {
+	if codeVersion == "" {
+		codeVersion = defaultCodeVersion
+	}
+
+	nodal := "identity"
+	if v, ok := p.params.NodalCorrection.(coeffsVersioner); ok {
+		nodal = v.CoeffsVersion()
+	}
+
+	conv := "fes_greenwich"
+	if p.params.PhaseConvention == domain.PhaseConvVu {
+		conv = "vu"
+	}
+	pipeline := fmt.Sprintf("%s;epoch=%s;msl=%.6f;lon=%.6f",
+		conv, p.params.ReferenceTime.UTC().Format(time.RFC3339), p.params.MSL, p.params.Longitude)
+	if m := p.params.Model; m != nil && m.Name() != (domain.HarmonicModel{}).Name() {
+		// Harmonic fingerprints predate model selection and stay unchanged.
+		pipeline += ";model=" + m.Name()
+	}
+	if lm := p.landMotion; lm != nil {
+		pipeline += fmt.Sprintf(";vlm=%s:%.4f@%s", lm.source, lm.motion.RateMMPerYr, lm.motion.Epoch.UTC().Format(time.RFC3339))
+	}
+	for _, e := range p.seismic {
+		pipeline += fmt.Sprintf(";seismic=%s@%s:%.4f", e.Name, e.Time.UTC().Format(time.RFC3339), e.DisplacementM)
+	}
+	if s := p.scenario; s != nil {
+		pipeline += fmt.Sprintf(";slr=%.4f", s.RiseM)
+		if s.Name != "" {
+			pipeline += "@" + s.Name
+		}
+	}
+	if d := p.tidalDatum; d != nil {
+		pipeline += fmt.Sprintf(";datum=%s:%.4f", d.name, d.levelM)
+	}
+	if nc := p.nowcast; nc != nil {
+		pipeline += fmt.Sprintf(";nowcast=%s@%s:%.4f", nc.Station, nc.Estimate.Time.UTC().Format(time.RFC3339), nc.Estimate.ResidualM)
+	}
+
+	if sp := p.secondary; sp != nil {
+		pipeline += fmt.Sprintf(";secondary=%s:hw=%s,%g,%g:lw=%s,%g,%g", sp.Reference,
+			sp.HighWaterTimeOffset, sp.HighWaterRatio, sp.HighWaterDiffM,
+			sp.LowWaterTimeOffset, sp.LowWaterRatio, sp.LowWaterDiffM)
+	}
+
+	dataset := p.source + ":" + constituentsDigest(p.params.Constituents)
+	if p.ensemble != nil {
+		dataset = p.source + ":ensemble:" + p.ensemble.digest()
+	}
+
+	return computationProvenance{
+		codeVersion:   codeVersion,
+		dataset:       dataset,
+		nodalCoeffs:   nodal,
+		stationTables: tables.digest(),
+		pipeline:      pipeline,
+	}
+}
+
+// Fingerprint hashes all provenance components into a single identifier.
+func (c computationProvenance) Fingerprint() string {
+	h := sha256.New()
+	for _, part := range []string{c.codeVersion, c.dataset, c.nodalCoeffs, c.stationTables, c.pipeline} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Meta returns the provenance components as response metadata.
+func (c computationProvenance) Meta() map[string]string {
+	return map[string]string{
+		"code_version":   c.codeVersion,
+		"dataset":        c.dataset,
+		"nodal_coeffs":   c.nodalCoeffs,
+		"station_tables": c.stationTables,
+		"pipeline":       c.pipeline,
+	}
+}
+
+// constituentsDigest hashes constituent parameters independent of their order.
+func constituentsDigest(constituents []domain.ConstituentParam) string {
+	lines := make([]string, len(constituents))
+	for i, c := range constituents {
+		lines[i] = fmt.Sprintf("%s:%.6f:%.6f:%.7f", c.Name, c.AmplitudeM, c.PhaseDeg, c.SpeedDegPerHr)
+	}
+	sort.Strings(lines)
+	return shortDigest([]byte(strings.Join(lines, "\n")))
+}
+
+// shortDigest returns the first 12 hex characters of the SHA-256 of b.
+func shortDigest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:12]
+}
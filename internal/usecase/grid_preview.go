@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// maxGridPreviewNodes bounds the nodes of a grid preview; every node is
+// interpolated from the dataset files.
+const maxGridPreviewNodes = 10000
+
+// GridPreviewRequest selects the nodes of a constituent grid to return.
+type GridPreviewRequest struct {
+	Constituent string
+	BBox        domain.BBox
+	Stride      int // Keep every Stride-th node; 0 picks the smallest within the node limit.
+}
+
+// GridPreviewResponse holds the amplitude and phase of a constituent at
+// dataset grid nodes. Nodes without data (e.g., land) are null.
+type GridPreviewResponse struct {
+	Constituent string       `json:"constituent"`
+	Stride      int          `json:"stride"`
+	Lats        []float64    `json:"lats"` // Ascending.
+	Lons        []float64    `json:"lons"` // Ascending, in [-180, 180).
+	AmplitudeM  [][]*float64 `json:"amplitude_m"`
+	PhaseDeg    [][]*float64 `json:"phase_deg"`
+}
+
+// ConstituentGrid returns the FES grid of a constituent within a box,
+// keeping every Stride-th node of each axis (counted from the first node
+// of the dataset, so previews of overlapping boxes share nodes). It reads
+// the dataset directly, to visualize and sanity-check it.
+func (uc *PredictionUseCase) ConstituentGrid(req GridPreviewRequest) (*GridPreviewResponse, error) {
+	if err := req.BBox.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Stride < 0 {
+		return nil, errors.New("stride must be positive")
+	}
+	name, ok := domain.CanonicalConstituentName(req.Constituent)
+	if !ok {
+		return nil, fmt.Errorf("unknown constituent %s: %w", req.Constituent, domain.ErrConstituentUnavailable)
+	}
+	coverage, err := uc.ConstituentCoverage(name)
+	if err != nil {
+		return nil, err
+	}
+	if coverage.Curvilinear {
+		return nil, fmt.Errorf("%s has a curvilinear grid, which cannot be previewed by stride", name)
+	}
+
+	b := req.BBox
+	stride := req.Stride
+	if stride == 0 {
+		stride = 1
+		for len(gridNodes(coverage.Lat, b.MinLat, b.MaxLat, stride, false))*
+			len(gridNodes(coverage.Lon, b.MinLon, b.MaxLon, stride, true)) > maxGridPreviewNodes {
+			stride++
+		}
+	}
+	lats := gridNodes(coverage.Lat, b.MinLat, b.MaxLat, stride, false)
+	lons := gridNodes(coverage.Lon, b.MinLon, b.MaxLon, stride, true)
+	if len(lats) == 0 || len(lons) == 0 {
+		return nil, fmt.Errorf("no %s grid nodes in bbox: %w", name, domain.ErrOutOfCoverage)
+	}
+	if len(lats)*len(lons) > maxGridPreviewNodes {
+		return nil, fmt.Errorf("too many grid nodes (%d): at most %d allowed; increase stride or shrink bbox",
+			len(lats)*len(lons), maxGridPreviewNodes)
+	}
+
+	points := make([]domain.Position, 0, len(lats)*len(lons))
+	for _, lat := range lats {
+		for _, lon := range lons {
+			points = append(points, domain.Position{Lat: lat, Lon: lon})
+		}
+	}
+	params, errs := store.LoadConstituentAtPoints(*uc.fesStore, name, points)
+
+	response := &GridPreviewResponse{
+		Constituent: name,
+		Stride:      stride,
+		Lats:        lats,
+		Lons:        lons,
+		AmplitudeM:  make([][]*float64, len(lats)),
+		PhaseDeg:    make([][]*float64, len(lats)),
+	}
+	for i := range lats {
+		response.AmplitudeM[i] = make([]*float64, len(lons))
+		response.PhaseDeg[i] = make([]*float64, len(lons))
+		for j := range lons {
+			k := i*len(lons) + j
+			if errors.Is(errs[k], domain.ErrOutOfCoverage) {
+				continue
+			}
+			if errs[k] != nil {
+				return nil, fmt.Errorf("failed to read %s grid: %w", name, errs[k])
+			}
+			amplitude, phase := roundToDecimal(params[k].AmplitudeM), roundToDecimal(params[k].PhaseDeg)
+			response.AmplitudeM[i][j], response.PhaseDeg[i][j] = &amplitude, &phase
+		}
+	}
+	return response, nil
+}
+
+// FeatureCollection returns the nodes with data as GeoJSON Points.
+func (r *GridPreviewResponse) FeatureCollection() FeatureCollection {
+	fc := FeatureCollection{
+		Type:     "FeatureCollection",
+		BBox:     []float64{r.Lons[0], r.Lats[0], r.Lons[len(r.Lons)-1], r.Lats[len(r.Lats)-1]},
+		Features: []Feature{},
+	}
+	for i, lat := range r.Lats {
+		for j, lon := range r.Lons {
+			if r.AmplitudeM[i][j] == nil {
+				continue
+			}
+			fc.Features = append(fc.Features, Feature{
+				Type:     "Feature",
+				Geometry: Geometry{Type: "Point", Coordinates: []float64{lon, lat}},
+				Properties: map[string]any{
+					"constituent": r.Constituent,
+					"amplitude_m": *r.AmplitudeM[i][j],
+					"phase_deg":   *r.PhaseDeg[i][j],
+				},
+			})
+		}
+	}
+	return fc
+}
+
+// gridNodes returns the ascending coordinates of every stride-th node of
+// an axis within [lo, hi]. Longitudes are wrapped to [-180, 180).
+func gridNodes(axis store.GridAxis, lo, hi float64, stride int, lon bool) []float64 {
+	if axis.Size < 2 {
+		return nil
+	}
+	step := (axis.Max - axis.Min) / float64(axis.Size-1)
+	var nodes []float64
+	for k := 0; k < axis.Size; k += stride {
+		v := axis.Min + float64(k)*step
+		if lon {
+			v = math.Mod(v+180, 360)
+			if v < 0 {
+				v += 360
+			}
+			v -= 180
+		}
+		if v = roundCoord(v); v >= lo && v <= hi {
+			nodes = append(nodes, v)
+		}
+	}
+	slices.Sort(nodes)
+	// A global axis may hold both 0° and 360°.
+	return slices.Compact(nodes)
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// ErrLandMotionUnavailable reports an include_vlm request that cannot be
+// served.
+var ErrLandMotionUnavailable = errors.New("vertical land motion not available")
+
+// LandMotionSource provides vertical land motion rates.
+type LandMotionSource interface {
+	// RateAt returns the rate in mm/yr, positive for uplift, or an error
+	// wrapping domain.ErrOutOfCoverage where the source has no rate.
+	RateAt(lat, lon float64) (float64, error)
+	// Name identifies the source (e.g., file name and digest) in
+	// response metadata and fingerprints.
+	Name() string
+}
+
+// appliedLandMotion is the land motion correction of a prediction.
+type appliedLandMotion struct {
+	source string
+	motion domain.LandMotion
+}
+
+// SetLandMotion enables include_vlm requests, correcting heights for land
+// motion since epoch (the reference epoch of the tidal datums).
+func (uc *PredictionUseCase) SetLandMotion(src LandMotionSource, epoch time.Time) {
+	uc.landMotion = src
+	uc.landMotionEpoch = epoch
+}
+
+// LandMotion returns the land motion source and epoch, or nil when not
+// configured.
+func (uc *PredictionUseCase) LandMotion() (LandMotionSource, time.Time) {
+	return uc.landMotion, uc.landMotionEpoch
+}
+
+// applyLandMotion wraps the prepared model with the land motion at the
+// prediction's position. Locations the grid does not cover degrade the
+// response rather than failing it.
+func (uc *PredictionUseCase) applyLandMotion(p *preparedPrediction) error {
+	if uc.landMotion == nil {
+		return fmt.Errorf("%w: no land motion grid configured", ErrLandMotionUnavailable)
+	}
+	pos := p.params.Position
+	if pos == nil {
+		return fmt.Errorf("%w: station has no coordinates", ErrLandMotionUnavailable)
+	}
+	rate, err := uc.landMotion.RateAt(pos.Lat, pos.Lon)
+	if err != nil {
+		p.degraded = append(p.degraded, "vlm: "+err.Error())
+		return nil
+	}
+
+	base := p.params.Model
+	if base == nil {
+		base = domain.HarmonicModel{}
+	}
+	motion := domain.LandMotion{RateMMPerYr: rate, Epoch: uc.landMotionEpoch}
+	p.params.Model = domain.LandMotionModel{Base: base, Motion: motion}
+	p.landMotion = &appliedLandMotion{source: uc.landMotion.Name(), motion: motion}
+	return nil
+}
+
+// addLandMotionMeta records the applied land motion in response metadata.
+func (p *preparedPrediction) addLandMotionMeta(meta map[string]string) {
+	if p.landMotion == nil {
+		return
+	}
+	meta["vlm_rate_mm_per_yr"] = strconv.FormatFloat(p.landMotion.motion.RateMMPerYr, 'f', 2, 64)
+	meta["vlm_reference_epoch"] = p.landMotion.motion.Epoch.UTC().Format(time.DateOnly)
+	meta["vlm_source"] = p.landMotion.source
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// defaultSurgeWindow is the trailing window used to fit the residual slope.
+const defaultSurgeWindow = 3 * time.Hour
+
+// ObservationSource provides recent observed water levels for a station.
+type ObservationSource interface {
+	// Recent returns observations at or after since, in time order.
+	Recent(station string, since time.Time) ([]domain.TideLevel, error)
+}
+
+// AlertNotifier receives alert state transitions (e.g., webhooks).
+type AlertNotifier interface {
+	Notify(payload any) error
+}
+
+// MonitoredStation is a tide gauge watched for abnormal rate-of-rise.
+type MonitoredStation struct {
+	Station    string                       `json:"station"`
+	Name       string                       `json:"name"`
+	Lat        float64                      `json:"lat"`
+	Lon        float64                      `json:"lon"`
+	Thresholds *domain.RateOfRiseThresholds `json:"thresholds,omitempty"`
+}
+
+// SurgeAlert is the evaluated alert state of a monitored station.
+type SurgeAlert struct {
+	Station             string            `json:"station"`
+	Name                string            `json:"name"`
+	Level               domain.AlertLevel `json:"level"`
+	PreviousLevel       domain.AlertLevel `json:"previous_level,omitempty"`
+	ResidualSlopeMPerHr *float64          `json:"residual_slope_m_per_hr,omitempty"`
+	LatestResidualM     *float64          `json:"latest_residual_m,omitempty"`
+	ObservedAt          string            `json:"observed_at,omitempty"`
+	EvaluatedAt         string            `json:"evaluated_at"`
+	Error               string            `json:"error,omitempty"`
+}
+
+// LoadMonitoredStations reads the monitored station list from a JSON file.
+func LoadMonitoredStations(path string) ([]MonitoredStation, error) {
+	//nolint:gosec // G304: File path from env var or config path.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitored stations: %w", err)
+	}
+	var stations []MonitoredStation
+	if err := json.Unmarshal(b, &stations); err != nil {
+		return nil, fmt.Errorf("invalid monitored stations JSON: %w", err)
+	}
+	return stations, nil
+}
+
+// MonitorUseCase detects abnormal residual rate-of-rise at monitored stations
+// by combining live observations with harmonic predictions.
+type MonitorUseCase struct {
+	predictionUC *PredictionUseCase
+	observations ObservationSource
+	stations     []MonitoredStation
+	window       time.Duration
+	notifiers    []AlertNotifier
+
+	mu     sync.RWMutex
+	alerts map[string]SurgeAlert
+
+	dashboard dashboardCache
+}
+
+// NewMonitorUseCase creates a surge monitor for the given stations.
+// observations may be nil, in which case residuals and alerts are unavailable.
+func NewMonitorUseCase(predictionUC *PredictionUseCase, observations ObservationSource, stations []MonitoredStation) *MonitorUseCase {
+	return &MonitorUseCase{
+		predictionUC: predictionUC,
+		observations: observations,
+		stations:     stations,
+		window:       defaultSurgeWindow,
+		alerts:       make(map[string]SurgeAlert),
+	}
+}
+
+// AddNotifier registers a notifier for alert level transitions.
+func (m *MonitorUseCase) AddNotifier(n AlertNotifier) {
+	m.notifiers = append(m.notifiers, n)
+}
+
+// Stations returns the monitored stations.
+func (m *MonitorUseCase) Stations() []MonitoredStation {
+	return m.stations
+}
+
+// Alerts returns the latest alert state per station, evaluating first if
+// no evaluation has run yet.
+func (m *MonitorUseCase) Alerts() []SurgeAlert {
+	m.mu.RLock()
+	evaluated := len(m.alerts) > 0
+	m.mu.RUnlock()
+	if !evaluated {
+		return m.Evaluate()
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]SurgeAlert, 0, len(m.stations))
+	for _, st := range m.stations {
+		if a, ok := m.alerts[st.Station]; ok {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Evaluate computes the alert state for all stations and notifies on level changes.
+func (m *MonitorUseCase) Evaluate() []SurgeAlert {
+	now := m.predictionUC.Clock().Now().UTC()
+	out := make([]SurgeAlert, 0, len(m.stations))
+	for _, st := range m.stations {
+		alert := m.evaluateStation(st, now)
+
+		m.mu.Lock()
+		prev, hadPrev := m.alerts[st.Station]
+		m.alerts[st.Station] = alert
+		m.mu.Unlock()
+
+		if hadPrev && prev.Level != alert.Level {
+			alert.PreviousLevel = prev.Level
+			m.notify(alert)
+		} else if !hadPrev && (alert.Level == domain.AlertWatch || alert.Level == domain.AlertWarning) {
+			m.notify(alert)
+		}
+		out = append(out, alert)
+	}
+	return out
+}
+
+// Run evaluates all stations every interval until ctx is canceled.
+func (m *MonitorUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	m.Evaluate()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Evaluate()
+		}
+	}
+}
+
+func (m *MonitorUseCase) notify(alert SurgeAlert) {
+	for _, n := range m.notifiers {
+		if err := n.Notify(alert); err != nil {
+			log.Printf("Warning: surge alert notification failed for %s: %v", alert.Station, err)
+		}
+	}
+}
+
+// residualSeries returns observed minus predicted levels since the given time.
+func (m *MonitorUseCase) residualSeries(st MonitoredStation, since time.Time) ([]domain.TideLevel, error) {
+	if m.observations == nil {
+		return nil, fmt.Errorf("no observation source configured")
+	}
+	observed, err := m.observations.Recent(st.Station, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(observed) < 2 {
+		return nil, fmt.Errorf("not enough recent observations (%d)", len(observed))
+	}
+
+	lat, lon := st.Lat, st.Lon
+	predicted, err := m.predictionUC.PredictSeries(PredictionRequest{
+		Lat:      &lat,
+		Lon:      &lon,
+		Start:    observed[0].Time,
+		End:      observed[len(observed)-1].Time,
+		Interval: time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return domain.Residuals(observed, predicted), nil
+}
+
+func (m *MonitorUseCase) evaluateStation(st MonitoredStation, now time.Time) SurgeAlert {
+	alert := SurgeAlert{
+		Station:     st.Station,
+		Name:        st.Name,
+		Level:       domain.AlertUnknown,
+		EvaluatedAt: now.Format(time.RFC3339),
+	}
+
+	// Include one extra hour so the window is fully covered by hourly data.
+	residuals, err := m.residualSeries(st, now.Add(-m.window-time.Hour))
+	if err != nil {
+		alert.Error = err.Error()
+		return alert
+	}
+	if len(residuals) == 0 {
+		alert.Error = "no observations paired with predictions"
+		return alert
+	}
+
+	thresholds := domain.DefaultRateOfRiseThresholds()
+	if st.Thresholds != nil {
+		thresholds = *st.Thresholds
+	}
+
+	slope := domain.ResidualSlope(residuals, m.window)
+	alert.Level = thresholds.Classify(slope)
+	if !math.IsNaN(slope) {
+		rounded := roundToDecimal(slope)
+		alert.ResidualSlopeMPerHr = &rounded
+	}
+	latest := residuals[len(residuals)-1]
+	latestResidual := roundToDecimal(latest.HeightM)
+	alert.LatestResidualM = &latestResidual
+	alert.ObservedAt = latest.Time.Format(time.RFC3339)
+	return alert
+}
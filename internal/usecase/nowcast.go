@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// nowcastRadiusKm bounds the distance from a monitored station at which
+	// its observations correct lat/lon predictions.
+	nowcastRadiusKm = 10.0
+	// nowcastBaseline is the residual history whose mean is removed before
+	// filtering, absorbing the offset between observation and model datums.
+	nowcastBaseline = 7 * 24 * time.Hour
+)
+
+// ErrNowcastUnavailable reports a nowcast request that no monitored station
+// can serve.
+var ErrNowcastUnavailable = errors.New("nowcast not available")
+
+// Nowcast is the filtered residual at a monitored station.
+type Nowcast struct {
+	Station  string
+	Config   domain.NowcastConfig
+	Estimate domain.ResidualEstimate
+}
+
+// Nowcaster estimates the current observation residual for a prediction.
+type Nowcaster interface {
+	// Nowcast returns the estimate of the monitored station serving req,
+	// or an error wrapping ErrNowcastUnavailable when none does.
+	Nowcast(req PredictionRequest) (*Nowcast, error)
+}
+
+// SetNowcaster enables nowcast requests (typically the station monitor).
+func (uc *PredictionUseCase) SetNowcaster(n Nowcaster) {
+	uc.nowcaster = n
+}
+
+// Nowcaster returns the source of nowcasts, or nil when not configured.
+func (uc *PredictionUseCase) Nowcaster() Nowcaster {
+	return uc.nowcaster
+}
+
+// applyNowcast wraps the prepared model with the residual correction.
+// Observation failures degrade the response rather than failing it.
+func (uc *PredictionUseCase) applyNowcast(req PredictionRequest, p *preparedPrediction) error {
+	if uc.nowcaster == nil {
+		return fmt.Errorf("%w: no monitored stations with observations configured", ErrNowcastUnavailable)
+	}
+	nc, err := uc.nowcaster.Nowcast(req)
+	switch {
+	case errors.Is(err, ErrNowcastUnavailable):
+		return err
+	case err != nil:
+		p.degraded = append(p.degraded, "nowcast: "+err.Error())
+		return nil
+	}
+	if age := uc.clock.Now().Sub(nc.Estimate.Time); age >= nc.Config.Horizon {
+		p.degraded = append(p.degraded, fmt.Sprintf("nowcast: latest %s observation is %s old", nc.Station, age.Round(time.Minute)))
+	}
+
+	base := p.params.Model
+	if base == nil {
+		base = domain.HarmonicModel{}
+	}
+	p.params.Model = domain.NowcastModel{Base: base, Config: nc.Config, Estimate: nc.Estimate}
+	p.nowcast = nc
+	return nil
+}
+
+// addNowcastMeta records the applied nowcast in response metadata.
+func (p *preparedPrediction) addNowcastMeta(meta map[string]string) {
+	if p.nowcast == nil {
+		return
+	}
+	meta["nowcast_station"] = p.nowcast.Station
+	meta["nowcast_residual_m"] = fmt.Sprintf("%.3f", p.nowcast.Estimate.ResidualM)
+	meta["nowcast_observed_at"] = p.nowcast.Estimate.Time.UTC().Format(time.RFC3339)
+	meta["nowcast_horizon"] = p.nowcast.Config.Horizon.String()
+}
+
+// Nowcast filters the recent residuals of the monitored station matching
+// the request's station_id, or the nearest one within 10 km of its lat/lon.
+func (m *MonitorUseCase) Nowcast(req PredictionRequest) (*Nowcast, error) {
+	st, ok := m.nowcastStation(req)
+	if !ok {
+		return nil, fmt.Errorf("%w: no monitored station within %.0f km", ErrNowcastUnavailable, nowcastRadiusKm)
+	}
+	residuals, err := m.residualSeries(st, m.predictionUC.Clock().Now().UTC().Add(-nowcastBaseline))
+	if err != nil {
+		return nil, fmt.Errorf("station %s: %w", st.Station, err)
+	}
+
+	config := domain.DefaultNowcastConfig()
+	est, ok := config.Filter(removeMean(residuals))
+	if !ok {
+		return nil, fmt.Errorf("station %s: no observations paired with predictions", st.Station)
+	}
+	return &Nowcast{Station: st.Station, Config: config, Estimate: est}, nil
+}
+
+func (m *MonitorUseCase) nowcastStation(req PredictionRequest) (MonitoredStation, bool) {
+	if req.StationID != nil {
+		for _, st := range m.stations {
+			if st.Station == *req.StationID {
+				return st, true
+			}
+		}
+		return MonitoredStation{}, false
+	}
+	if req.Lat == nil || req.Lon == nil {
+		return MonitoredStation{}, false
+	}
+	var best MonitoredStation
+	bestKm := math.Inf(1)
+	for _, st := range m.stations {
+		if d := haversineKm(*req.Lat, *req.Lon, st.Lat, st.Lon); d < bestKm {
+			best, bestKm = st, d
+		}
+	}
+	return best, bestKm <= nowcastRadiusKm
+}
+
+// removeMean subtracts the mean residual.
+func removeMean(residuals []domain.TideLevel) []domain.TideLevel {
+	if len(residuals) == 0 {
+		return residuals
+	}
+	var sum float64
+	for _, r := range residuals {
+		sum += r.HeightM
+	}
+	mean := sum / float64(len(residuals))
+	out := make([]domain.TideLevel, len(residuals))
+	for i, r := range residuals {
+		out[i] = domain.TideLevel{Time: r.Time, HeightM: r.HeightM - mean}
+	}
+	return out
+}
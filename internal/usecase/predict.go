@@ -3,16 +3,29 @@ package usecase
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.ngs.io/tides-api/internal/adapter/store"
 	"go.ngs.io/tides-api/internal/adapter/store/bathymetry"
-	"go.ngs.io/tides-api/internal/domain"
+	"go.ngs.io/tides-api/internal/i18n"
+	"go.ngs.io/tides-api/internal/licensing"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
 const (
-	sourceCSV = "csv"
-	sourceFES = "fes"
+	sourceCSV    = "csv"
+	sourceFES    = "fes"
+	sourceTPXO   = "tpxo"
+	sourceCustom = "custom" // Constituents supplied with the request.
+
+	// maxCustomConstituents bounds request-supplied constituent sets.
+	maxCustomConstituents = 128
+
+	// Bounds on the time range and size of one request.
+	maxPredictionSpan   = 365 * 24 * time.Hour
+	maxPredictionPoints = 10000
 )
 
 // PredictionRequest encapsulates a tide prediction request.
@@ -24,6 +37,16 @@ type PredictionRequest struct {
 	// Station ID (mutually exclusive with Lat/Lon).
 	StationID *string
 
+	// Constituents supplied by the client (mutually exclusive with Lat/Lon
+	// and StationID). Used for this request only and never stored; a zero
+	// SpeedDegPerHr is resolved from the standard constituent table.
+	Constituents []domain.ConstituentParam
+
+	// Select restricts the dataset or station constituents to these
+	// canonical names (see ParseConstituentSelection); names the dataset
+	// set lacks are read from the dataset. Nil uses the whole set.
+	Select []string
+
 	// Time range.
 	Start time.Time
 	End   time.Time
@@ -33,7 +56,7 @@ type PredictionRequest struct {
 
 	// Optional parameters.
 	Datum  string // E.g., "MSL", "LAT", "MLLW" - MVP uses MSL only.
-	Source string // "csv" or "fes" - if empty, auto-detect.
+	Source string // "csv", "fes" or "tpxo" - if empty, auto-detect.
 
 	// Optional vertical datum offset in meters to adjust heights for comparison with external datums
 	// (e.g., JMA's DL/TP). Positive values raise all predicted heights by the given amount.
@@ -45,6 +68,35 @@ type PredictionRequest struct {
 
 	// Optional phase convention selector: "fes_greenwich" (default) or "vu".
 	PhaseConvention string
+
+	// Language of display labels in the response meta (default English).
+	Language string
+
+	// Nowcast corrects the hours after the latest observation at a nearby
+	// monitored station toward its observed residual.
+	Nowcast bool
+
+	// IncludeVLM adds the vertical land motion since the datum epoch to
+	// predicted heights, giving relative sea level at future dates.
+	IncludeVLM bool
+
+	// SLRM raises all heights by a sea level rise for what-if analyses;
+	// SLRScenario selects a configured scenario instead. Responses are
+	// flagged as scenarios.
+	SLRM        *float64
+	SLRScenario string
+
+	// Ensemble synthesizes every configured dataset and returns their mean
+	// with the member range per point (lat/lon only).
+	Ensemble bool
+
+	// Debug adds the normalized request and the timing of each stage to
+	// the response meta.
+	Debug bool
+
+	// withoutSeismic leaves out the seismic events, for datum epochs that
+	// describe the astronomical tide.
+	withoutSeismic bool
 }
 
 // PredictionResponse contains the tide prediction results.
@@ -55,9 +107,24 @@ type PredictionResponse struct {
 	Constituents []string          `json:"constituents"`
 	Predictions  []PredictionPoint `json:"predictions"`
 	Extrema      ExtremaResponse   `json:"extrema"`
-	MSL          *float64          `json:"msl_m,omitempty"`          // Mean Sea Level in meters.
-	SeabedDepth  *float64          `json:"seabed_depth_m,omitempty"` // Seabed depth in meters (positive value).
-	Meta         map[string]string `json:"meta"`
+	MSL          *float64          `json:"msl_m,omitempty"` // Mean Sea Level in meters.
+	// Scenario is set when heights include a sea level rise scenario:
+	// they are a what-if analysis, not a prediction.
+	Scenario    *SLRScenario      `json:"scenario,omitempty"`
+	SeabedDepth *float64          `json:"seabed_depth_m,omitempty"` // Seabed depth in meters (positive value; negative land elevation on land).
+	Land        bool              `json:"land,omitempty"`           // Location is above sea level per bathymetry.
+	Meta        map[string]string `json:"meta"`
+	// Fingerprint identifies the code version, datasets, constituents and
+	// correction pipeline that produced this response.
+	Fingerprint string `json:"fingerprint"`
+	Degradation
+	// Timing is the time spent computing the response (in meta for debug
+	// requests).
+	Timing Timing `json:"-"`
+	// ConstituentCache is "hit" or "miss" when the constituents of a
+	// lat/lon request were looked up in the constituent cache (for request
+	// logs).
+	ConstituentCache string `json:"-"`
 }
 
 // PredictionPoint represents a single tide height prediction.
@@ -65,6 +132,8 @@ type PredictionPoint struct {
 	Time    string   `json:"time"`
 	HeightM float64  `json:"height_m"`          // Tide height relative to datum.
 	DepthM  *float64 `json:"depth_m,omitempty"` // Water depth at this time (seabed_depth + msl + height).
+	MinM    *float64 `json:"min_m,omitempty"`   // Lowest ensemble member height.
+	MaxM    *float64 `json:"max_m,omitempty"`   // Highest ensemble member height.
 }
 
 // ExtremaResponse contains high and low tides.
@@ -77,7 +146,24 @@ type ExtremaResponse struct {
 type PredictionUseCase struct {
 	csvStore        *store.ConstituentLoader
 	fesStore        *store.ConstituentLoader
-	bathymetryStore bathymetry.Store // Optional bathymetry/MSL data store.
+	tpxoStore       store.ConstituentLoader // Optional TPXO9-atlas store (source=tpxo).
+	defaultSource   string                  // Source of lat/lon queries without one.
+	currentStore    store.CurrentLoader     // Optional tidal current constituents.
+	bathymetryStore bathymetry.Store        // Optional bathymetry/MSL data store.
+	codeVersion     string                  // Reported in computation fingerprints.
+	tables          *stationTables          // Datum offsets and station overrides.
+	recent          recentLocations         // Recently requested locations (snapshot warmup list).
+	model           domain.PredictionModel
+	nowcaster       Nowcaster          // Optional residual nowcasts (nowcast requests).
+	observations    ObservationHistory // Optional station observations (datum estimates).
+	landMotion      LandMotionSource   // Optional land motion rates (include_vlm requests).
+	landMotionEpoch time.Time
+	scenarios       []SLRScenario // Named sea level rise scenarios (slr_scenario requests).
+	seismicEvents   []domain.SeismicEvent
+	datums          datumTables // Computed tidal datum tables.
+	ensemble        []EnsembleMember
+	clock           domain.Clock // Current time for "now" defaults.
+	coalescer       coalescer    // Concurrent identical predictions.
 }
 
 // NewPredictionUseCase creates a new prediction use case.
@@ -86,32 +172,134 @@ func NewPredictionUseCase(csvStore, fesStore store.ConstituentLoader, bathyStore
 		csvStore:        &csvStore,
 		fesStore:        &fesStore,
 		bathymetryStore: bathyStore,
+		tables:          newStationTables(DefaultDatumOffsetsPath, DefaultStationOverridesPath),
+		defaultSource:   sourceFES,
+		model:           domain.HarmonicModel{},
+		clock:           domain.SystemClock{},
 	}
 }
 
-// Validate checks if the request is valid.
-func (r *PredictionRequest) Validate() error {
-	// Check mutually exclusive parameters.
-	hasLatLon := r.Lat != nil && r.Lon != nil
-	hasStationID := r.StationID != nil && *r.StationID != ""
+// SetTPXOStore sets the TPXO9-atlas store of source=tpxo requests.
+func (uc *PredictionUseCase) SetTPXOStore(l store.ConstituentLoader) {
+	uc.tpxoStore = l
+}
 
-	if !hasLatLon && !hasStationID {
-		return fmt.Errorf("either lat/lon or station_id must be provided")
+// TPXOStore returns the TPXO9-atlas store, or nil when not configured.
+func (uc *PredictionUseCase) TPXOStore() store.ConstituentLoader {
+	return uc.tpxoStore
+}
+
+// SetCurrentStore sets the tidal current constituents of current
+// predictions.
+func (uc *PredictionUseCase) SetCurrentStore(l store.CurrentLoader) {
+	uc.currentStore = l
+}
+
+// CurrentStore returns the tidal current store, or nil when not configured.
+func (uc *PredictionUseCase) CurrentStore() store.CurrentLoader {
+	return uc.currentStore
+}
+
+// SetDefaultSource selects the gridded dataset of lat/lon queries that do
+// not name one: "fes" (the default) or "tpxo", which needs a TPXO store.
+func (uc *PredictionUseCase) SetDefaultSource(source string) error {
+	switch source {
+	case sourceFES:
+	case sourceTPXO:
+		if uc.tpxoStore == nil {
+			return fmt.Errorf("source %s needs a TPXO store", source)
+		}
+	default:
+		return fmt.Errorf("unknown source %q (expected %s or %s)", source, sourceFES, sourceTPXO)
 	}
+	uc.defaultSource = source
+	return nil
+}
 
-	if hasLatLon && hasStationID {
-		return fmt.Errorf("lat/lon and station_id are mutually exclusive")
+// DefaultSource returns the gridded dataset of lat/lon queries that do not
+// name one.
+func (uc *PredictionUseCase) DefaultSource() string {
+	return uc.defaultSource
+}
+
+// gridSource returns the gridded dataset of a lat/lon request: its source,
+// else the default one.
+func (uc *PredictionUseCase) gridSource(req PredictionRequest) string {
+	if req.Source == "" {
+		return uc.defaultSource
 	}
+	return req.Source
+}
 
-	// Validate lat/lon ranges.
-	if hasLatLon {
-		if *r.Lat < -90 || *r.Lat > 90 {
-			return fmt.Errorf("latitude must be between -90 and 90")
-		}
-		if *r.Lon < -180 || *r.Lon > 180 {
-			return fmt.Errorf("longitude must be between -180 and 180")
+// SetClock replaces the clock telling the current time (default the wall
+// clock), e.g. to freeze time for reproducible runs.
+func (uc *PredictionUseCase) SetClock(c domain.Clock) {
+	uc.clock = c
+}
+
+// Clock returns the clock telling the current time.
+func (uc *PredictionUseCase) Clock() domain.Clock {
+	return uc.clock
+}
+
+// SetPredictionModel replaces the height model (default harmonic).
+func (uc *PredictionUseCase) SetPredictionModel(m domain.PredictionModel) {
+	uc.model = m
+}
+
+// ConstituentCacheStats returns statistics of the FES loader's cache;
+// ok is false when the loader does not cache.
+func (uc *PredictionUseCase) ConstituentCacheStats() (stats store.CacheStats, ok bool) {
+	if uc.fesStore == nil {
+		return stats, false
+	}
+	reporter, ok := (*uc.fesStore).(store.CacheStatsReporter)
+	if !ok {
+		return stats, false
+	}
+	return reporter.CacheStats(), true
+}
+
+// GridCellCacheStats returns statistics of the FES store's grid cell
+// cache; ok is false when it is disabled.
+func (uc *PredictionUseCase) GridCellCacheStats() (stats store.MemoryCacheStats, ok bool) {
+	if uc.fesStore == nil {
+		return stats, false
+	}
+	reporter, ok := (*uc.fesStore).(store.CellCacheReporter)
+	if !ok {
+		return stats, false
+	}
+	return reporter.CellCacheStats()
+}
+
+// ConstituentCoverage describes the FES grid of a constituent. Errors wrap
+// domain.ErrConstituentUnavailable when there is no grid for it.
+func (uc *PredictionUseCase) ConstituentCoverage(name string) (store.GridCoverage, error) {
+	if uc.fesStore != nil {
+		if reporter, ok := (*uc.fesStore).(store.CoverageReporter); ok {
+			return reporter.ConstituentCoverage(name)
 		}
 	}
+	return store.GridCoverage{}, fmt.Errorf("no gridded dataset for %s: %w", name, domain.ErrConstituentUnavailable)
+}
+
+// SetStationTables replaces the datum offset and station override files
+// (by default DefaultDatumOffsetsPath and DefaultStationOverridesPath).
+func (uc *PredictionUseCase) SetStationTables(datumOffsetsPath, stationOverridesPath string) {
+	uc.tables = newStationTables(datumOffsetsPath, stationOverridesPath)
+}
+
+// SetCodeVersion sets the server version recorded in computation fingerprints.
+func (uc *PredictionUseCase) SetCodeVersion(v string) {
+	uc.codeVersion = v
+}
+
+// Validate checks if the request is valid.
+func (r *PredictionRequest) Validate() error {
+	if err := r.validateTarget(); err != nil {
+		return err
+	}
 
 	// Validate time range.
 	if !r.Start.Before(r.End) {
@@ -128,53 +316,439 @@ func (r *PredictionRequest) Validate() error {
 
 	// Check that time range is reasonable.
 	duration := r.End.Sub(r.Start)
-	if duration > 365*24*time.Hour {
+	if duration > maxPredictionSpan {
 		return fmt.Errorf("time range must be at most 365 days")
 	}
 
 	// Check that number of points is reasonable.
 	numPoints := int(duration / r.Interval)
-	if numPoints > 10000 {
-		return fmt.Errorf("too many prediction points (%d) - reduce time range or increase interval", numPoints)
+	if numPoints > maxPredictionPoints {
+		return &RangeError{Points: numPoints, Interval: r.Interval, MaxRange: MaxRange(r.Interval)}
 	}
 
 	return nil
 }
 
-// Execute performs the tide prediction.
-//
-//nolint:gocyclo,nestif // Complex prediction logic with multiple conditional paths.
+// RangeError reports a time range with too many points for its interval.
+type RangeError struct {
+	Points   int
+	Interval time.Duration
+	// MaxRange is the longest range allowed at Interval.
+	MaxRange time.Duration
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("too many prediction points (%d) - at interval %s the time range can be at most %s; reduce time range or increase interval",
+		e.Points, FormatDuration(e.Interval), FormatDuration(e.MaxRange))
+}
+
+// MaxRange returns the longest time range a request may span at interval.
+func MaxRange(interval time.Duration) time.Duration {
+	return min(time.Duration(maxPredictionPoints)*interval, maxPredictionSpan)
+}
+
+// FormatDuration formats d as a Go duration without zero trailing units
+// (e.g., 166h40m rather than 166h40m0s), which time.ParseDuration accepts.
+func FormatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// validateTarget checks what the request predicts for: a location, a
+// station or supplied constituents.
+func (r *PredictionRequest) validateTarget() error {
+	// Check mutually exclusive parameters.
+	hasLatLon := r.Lat != nil && r.Lon != nil
+	hasStationID := r.StationID != nil && *r.StationID != ""
+	hasConstituents := len(r.Constituents) > 0
+
+	if !hasLatLon && !hasStationID && !hasConstituents {
+		return fmt.Errorf("either lat/lon, station_id or constituents must be provided")
+	}
+
+	if hasLatLon && hasStationID {
+		return fmt.Errorf("lat/lon and station_id are mutually exclusive")
+	}
+
+	if hasConstituents && (hasLatLon || hasStationID) {
+		return fmt.Errorf("constituents cannot be combined with lat/lon or station_id")
+	}
+	if r.Ensemble && !hasLatLon {
+		return fmt.Errorf("ensemble requires lat/lon")
+	}
+	if len(r.Select) > 0 && (hasConstituents || r.Ensemble) {
+		return fmt.Errorf("constituent selection cannot be combined with custom constituents or ensemble")
+	}
+	if r.SLRM != nil && r.SLRScenario != "" {
+		return fmt.Errorf("slr_m and slr_scenario are mutually exclusive")
+	}
+	if r.SLRM != nil {
+		if err := validateRise(*r.SLRM); err != nil {
+			return err
+		}
+	}
+	if len(r.Constituents) > maxCustomConstituents {
+		return fmt.Errorf("too many constituents (%d) - at most %d allowed", len(r.Constituents), maxCustomConstituents)
+	}
+
+	// Validate lat/lon ranges.
+	if hasLatLon {
+		if *r.Lat < -90 || *r.Lat > 90 {
+			return fmt.Errorf("latitude must be between -90 and 90")
+		}
+		if *r.Lon < -180 || *r.Lon > 180 {
+			return fmt.Errorf("longitude must be between -180 and 180")
+		}
+	}
+
+	return nil
+}
+
+// Execute performs the tide prediction. Identical concurrent requests
+// share one computation.
 func (uc *PredictionUseCase) Execute(req PredictionRequest) (*PredictionResponse, error) {
+	key, ok := coalescingKey(req)
+	if !ok {
+		return uc.execute(req, *uc.fesStore)
+	}
+	return uc.coalescer.do(key, func() (*PredictionResponse, error) {
+		return uc.execute(req, *uc.fesStore)
+	})
+}
+
+// CoalescingStats returns how many predictions were computed and how many
+// requests shared them.
+func (uc *PredictionUseCase) CoalescingStats() CoalescingStats {
+	return uc.coalescer.Stats()
+}
+
+// execute performs the tide prediction, loading lat/lon constituents from fes.
+func (uc *PredictionUseCase) execute(req PredictionRequest, fes store.ConstituentLoader) (*PredictionResponse, error) {
 	// Validate request.
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
+	prepared, err := uc.prepareWith(req, fes)
+	if err != nil {
+		return nil, err
+	}
+	params := prepared.params
+	synthesisStart := time.Now()
+
+	// Generate predictions at requested interval.
+	predictions := domain.GeneratePredictions(req.Start, req.End, req.Interval, params)
+
+	// Compute extrema on high-resolution (1m) grid for accurate times regardless of interval.
+	preciseInterval := time.Minute
+	if req.Interval < preciseInterval {
+		preciseInterval = req.Interval
+	}
+	precisePredictions := domain.GeneratePredictions(req.Start, req.End, preciseInterval, params)
+	extrema := domain.RefineExtrema(precisePredictions, domain.FindExtrema(precisePredictions))
+	prepared.timing.Synthesis = time.Since(synthesisStart)
+
+	// Choose output timezone.
+	loc, _ := outputZone(req.Timezone)
+
+	// Convert to response format.
+	predictionPoints := make([]PredictionPoint, len(predictions))
+	for i, p := range predictions {
+		predictionPoints[i] = prepared.point(p, loc)
+	}
+	highPoints := make([]PredictionPoint, len(extrema.Highs))
+	for i, h := range extrema.Highs {
+		highPoints[i] = prepared.point(h, loc)
+	}
+	lowPoints := make([]PredictionPoint, len(extrema.Lows))
+	for i, l := range extrema.Lows {
+		lowPoints[i] = prepared.point(l, loc)
+	}
+
+	return uc.newResponse(req, prepared, predictionPoints, ExtremaResponse{Highs: highPoints, Lows: lowPoints}), nil
+}
+
+// point formats a predicted level for the response, with the water depth
+// when seabed depth is known and the ensemble range when applicable.
+func (p *preparedPrediction) point(level domain.TideLevel, loc *time.Location) PredictionPoint {
+	point := PredictionPoint{
+		Time:    level.Time.In(loc).Format(time.RFC3339),
+		HeightM: roundToDecimal(level.HeightM),
+	}
+
+	// Calculate water depth if seabed depth is available.
+	// Water depth = seabed_depth + msl + tide_height (above MSL).
+	if m := p.metadata; m != nil && m.DepthM != nil && !m.Land {
+		waterDepth := *m.DepthM + p.mslAt(level.Time) + p.datumLevel() + level.HeightM
+		roundedDepth := roundToDecimal(waterDepth)
+		point.DepthM = &roundedDepth
+	}
+
+	return p.withSpread(point, level.Time)
+}
+
+// newResponse assembles a prediction response with its provenance and
+// metadata.
+//
+//nolint:nestif // Optional location metadata fields.
+func (uc *PredictionUseCase) newResponse(req PredictionRequest, prepared *preparedPrediction, predictions []PredictionPoint, extrema ExtremaResponse) *PredictionResponse {
+	source := prepared.source
+	metadata := prepared.metadata
+	_, tzLabel := outputZone(req.Timezone)
+
+	// Extract constituent names.
+	constituentNames := make([]string, len(prepared.constituents))
+	for i, c := range prepared.constituents {
+		constituentNames[i] = c.Name
+	}
+
+	// Build response.
+	response := &PredictionResponse{
+		Source:       source,
+		Datum:        prepared.datumName(),
+		Timezone:     tzLabel,
+		Constituents: constituentNames,
+		Predictions:  predictions,
+		Extrema:      extrema,
+		Meta: map[string]string{
+			"model": uc.model.Name(),
+		},
+		Scenario:         prepared.scenario,
+		Degradation:      prepared.degradation(),
+		Timing:           prepared.timing,
+		ConstituentCache: prepared.cache,
+	}
+
+	// Stamp the response with its computation provenance.
+	provenance := newComputationProvenance(uc.codeVersion, uc.tables, prepared)
+	for k, v := range provenance.Meta() {
+		response.Meta[k] = v
+	}
+	response.Fingerprint = provenance.Fingerprint()
+	prepared.addNowcastMeta(response.Meta)
+	prepared.addLandMotionMeta(response.Meta)
+	prepared.addSeismicMeta(response.Meta)
+	prepared.addEnsembleMeta(response.Meta)
+	prepared.addFallbackMeta(response.Meta)
+	if prepared.wetFallbackKm > 0 {
+		response.Meta["wet_fallback_km"] = strconv.FormatFloat(prepared.wetFallbackKm, 'f', 1, 64)
+	}
+
+	// Add self-described station metadata.
+	if sp := prepared.secondary; sp != nil {
+		response.Meta["reference_station"] = sp.Reference
+	}
+	if st := prepared.station; st != nil {
+		if st.Name != "" {
+			response.Meta["station_name"] = st.Name
+		}
+		if st.Lat != nil && st.Lon != nil {
+			response.Meta["station_lat"] = strconv.FormatFloat(*st.Lat, 'f', -1, 64)
+			response.Meta["station_lon"] = strconv.FormatFloat(*st.Lon, 'f', -1, 64)
+		}
+	}
+
+	// Add metadata if available.
+	if metadata != nil {
+		if metadata.MSL != 0.0 {
+			response.MSL = &metadata.MSL
+		}
+		if metadata.DepthM != nil {
+			response.SeabedDepth = metadata.DepthM
+		}
+		response.Land = metadata.Land
+		if metadata.Datum != "" {
+			response.Meta["datum_code"] = string(metadata.Datum)
+			response.Meta["datum_name"] = i18n.DatumLabel(req.Language, string(metadata.Datum))
+		}
+		if codes := SourceCodes(metadata); len(codes) > 0 {
+			response.Meta["metadata_source_codes"] = strings.Join(codes, ",")
+			response.Meta["metadata_source"] = i18n.SourcesLabel(req.Language, codes)
+		}
+	}
+
+	// Credit the datasets the response was computed from.
+	datasets := []string{source}
+	if prepared.fallback != nil {
+		datasets = append(datasets, prepared.fallback.source)
+	}
+	if metadata != nil {
+		datasets = append(datasets, SourceCodes(metadata)...)
+	}
+	response.Meta["attribution"] = licensing.Attribution(datasets...)
+	if m, ok := uc.model.(domain.AttributedModel); ok {
+		response.Meta["model_attribution"] = m.Attribution()
+	}
+
+	// Record applied datum offset if provided.
+	if req.DatumOffsetM != nil {
+		response.Meta["datum_offset_m"] = fmt.Sprintf("%.3f", *req.DatumOffsetM)
+	}
+	// Record when station table corrections change within the range.
+	if len(prepared.periods) > 0 {
+		changes := make([]string, len(prepared.periods))
+		for i, p := range prepared.periods {
+			changes[i] = p.Start.UTC().Format(time.RFC3339)
+		}
+		response.Meta["corrections_change_at"] = strings.Join(changes, ",")
+	}
+	// Record the level of a tidal datum above mean sea level.
+	if d := prepared.tidalDatum; d != nil {
+		response.Meta["datum_level_m"] = fmt.Sprintf("%.3f", d.levelM)
+	}
+
+	if req.Debug {
+		response.Meta["request"] = req.echo(source)
+		prepared.timing.addMeta(response.Meta)
+	}
+
+	return response
+}
+
+// SourceCodes returns the metadata source codes as strings.
+func SourceCodes(metadata *domain.LocationMetadata) []string {
+	codes := make([]string, len(metadata.Sources))
+	for i, c := range metadata.Sources {
+		codes[i] = string(c)
+	}
+	return codes
+}
+
+// outputZone returns the location and offset label for a timezone
+// preference: "jst" or UTC (default).
+func outputZone(tz string) (*time.Location, string) {
+	switch tz {
+	case "jst", "JST":
+		return time.FixedZone("JST", 9*60*60), "+09:00"
+	default:
+		return time.FixedZone("UTC", 0), "+00:00"
+	}
+}
+
+// preparedPrediction holds the resolved inputs for a model synthesis.
+type preparedPrediction struct {
+	source       string
+	constituents []domain.ConstituentParam
+	metadata     *domain.LocationMetadata
+	station      *domain.StationMetadata // Self-described station metadata (station queries only).
+	msl          float64
+	periods      []domain.ParamPeriod // Station table corrections changing during the range.
+	params       domain.PredictionParams
+	nowcast      *Nowcast // Applied residual nowcast, if requested.
+	landMotion   *appliedLandMotion
+	seismic      []domain.SeismicEvent // Applied seismic events.
+	scenario     *SLRScenario          // Applied sea level rise, if requested.
+	tidalDatum   *appliedTidalDatum
+	ensemble     *ensemblePrediction
+	fallback     *constituentFallback  // Constituents taken from FES, if any.
+	secondary    *domain.SecondaryPort // Set when predicting a secondary port.
+	degraded     []string              // Degradation reasons beyond the location metadata.
+	timing       Timing                // Synthesis is set by the caller.
+	cache        string                // Constituent cache "hit" or "miss", if looked up.
+	// Distance to the wet grid point a land location took its constituents
+	// from; 0 when interpolated from its own grid cell.
+	wetFallbackKm float64
+}
+
+// prepare loads constituents and metadata and resolves the synthesis parameters
+// for a validated request.
+func (uc *PredictionUseCase) prepare(req PredictionRequest) (*preparedPrediction, error) {
+	return uc.prepareWith(req, *uc.fesStore)
+}
+
+// prepareWith is prepare loading lat/lon constituents from fes.
+//
+//nolint:gocyclo,nestif // Source selection and correction pipeline with multiple conditional paths.
+func (uc *PredictionUseCase) prepareWith(req PredictionRequest, fes store.ConstituentLoader) (*preparedPrediction, error) {
 	// Determine source and load constituents.
 	var constituents []domain.ConstituentParam
-	var source string
+	var station, reference *domain.StationMetadata // Reference station of a secondary port.
+	var ensemble *ensemblePrediction
+	var fallback *constituentFallback
+	var degraded []string
+	var source, cache string
+	var wetFallbackKm float64
 	var err error
+	loadStart := time.Now()
 
-	if req.StationID != nil {
+	switch {
+	case len(req.Constituents) > 0:
+		// Client-supplied constituents; used as-is for this request only.
+		source = sourceCustom
+		if constituents, err = resolveCustomConstituents(req.Constituents); err != nil {
+			return nil, err
+		}
+	case req.StationID != nil:
 		// Use CSV store for station-based queries.
 		source = sourceCSV
-		if req.Source == sourceFES {
-			return nil, fmt.Errorf("FES source does not support station_id - use lat/lon instead")
+		if req.Source == sourceFES || req.Source == sourceTPXO {
+			return nil, fmt.Errorf("%s source does not support station_id - use lat/lon instead", strings.ToUpper(req.Source))
+		}
+		if station, err = uc.loadStationMetadata(*req.StationID); err != nil {
+			return nil, err
+		}
+		stationID := *req.StationID
+		if station != nil && station.Secondary != nil {
+			// Secondary ports are predicted from their reference station.
+			stationID = station.Secondary.Reference
+			if reference, err = uc.loadStationMetadata(stationID); err != nil {
+				return nil, err
+			}
 		}
-		constituents, err = (*uc.csvStore).LoadForStation(*req.StationID)
+		constituents, err = (*uc.csvStore).LoadForStation(stationID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load constituents for station %s: %w", *req.StationID, err)
+			return nil, fmt.Errorf("failed to load constituents for station %s: %w", stationID, err)
+		}
+	case req.Ensemble:
+		if req.Source == sourceCSV {
+			return nil, fmt.Errorf("CSV source does not support ensemble predictions")
 		}
-	} else {
-		// Use FES store for lat/lon queries (or CSV if explicitly requested).
+		source = sourceFES
+		uc.recent.touch(*req.Lat, *req.Lon)
+		if ensemble, degraded, err = uc.loadEnsemble(*req.Lat, *req.Lon); err != nil {
+			return nil, err
+		}
+		constituents = ensemble.members[0].Constituents
+	default:
+		// Use the FES (or TPXO) store for lat/lon queries (or CSV if explicitly requested).
 		if req.Source == sourceCSV {
 			return nil, fmt.Errorf("CSV source does not support lat/lon - use station_id instead")
 		}
 		source = sourceFES
-		constituents, err = (*uc.fesStore).LoadForLocation(*req.Lat, *req.Lon)
+		loader := fes
+		if uc.gridSource(req) == sourceTPXO {
+			if uc.tpxoStore == nil {
+				return nil, fmt.Errorf("TPXO source is not configured")
+			}
+			source, loader = sourceTPXO, uc.tpxoStore
+		}
+		uc.recent.touch(*req.Lat, *req.Lon)
+		if probe, ok := loader.(store.CacheProbe); ok {
+			cache = "miss"
+			if probe.Cached(*req.Lat, *req.Lon) {
+				cache = "hit"
+			}
+		}
+		constituents, err = loader.LoadForLocation(*req.Lat, *req.Lon)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load constituents for location (%.4f, %.4f): %w", *req.Lat, *req.Lon, err)
 		}
+		if r, ok := loader.(store.WetFallbackReporter); ok {
+			// Informational only; the constituents are already loaded.
+			if km, err := r.WetFallback(*req.Lat, *req.Lon); err == nil {
+				wetFallbackKm = km
+			}
+		}
+		if source == sourceTPXO {
+			// Constituents TPXO lacks are taken from FES one by one.
+			constituents, fallback, degraded = fillMissingConstituents(constituents, fes, sourceFES, *req.Lat, *req.Lon)
+		}
 	}
 
 	// Load bathymetry metadata if available (lat/lon queries only).
@@ -188,6 +762,7 @@ func (uc *PredictionUseCase) Execute(req PredictionRequest) (*PredictionResponse
 			fmt.Printf("Warning: failed to load bathymetry metadata: %v\n", err)
 		}
 	}
+	correctionsStart := time.Now()
 
 	// Set up prediction parameters.
 	msl := 0.0
@@ -198,15 +773,40 @@ func (uc *PredictionUseCase) Execute(req PredictionRequest) (*PredictionResponse
 	// Apply optional datum offset (e.g., to align with JMA DL/TP).
 	if req.DatumOffsetM != nil {
 		msl += *req.DatumOffsetM
-	} else if req.Lat != nil && req.Lon != nil {
-		// Auto datum offset: attempt to load nearest known offset (e.g., JMA DL/TP) and apply.
-		if off, ok := getAutoDatumOffset(*req.Lat, *req.Lon); ok {
-			msl += off
-		}
+	} else if station != nil && station.DatumOffsetM != nil {
+		// Datum offset declared in the station file.
+		msl += *station.DatumOffsetM
 	}
 
+	// Station table corrections of lat/lon queries, as in force at the
+	// start, and from each time in the range at which they change.
+	var periods []domain.ParamPeriod
 	if req.Lat != nil && req.Lon != nil {
-		constituents = applyStationOverride(*req.Lat, *req.Lon, constituents, &msl)
+		correction := tableCorrection{
+			tables:       uc.tables,
+			lat:          *req.Lat,
+			lon:          *req.Lon,
+			msl:          msl,
+			constituents: constituents,
+			autoOffset:   req.DatumOffsetM == nil,
+			// Overrides replace dataset constituents, so ensemble members keep their own.
+			override:    ensemble == nil,
+			crossesLand: uc.overrideLandCheck(),
+		}
+		msl, constituents = correction.at(req.Start)
+		for _, t := range uc.tables.changes(*req.Lat, *req.Lon, req.Start, req.End) {
+			periodMSL, periodConstituents := correction.at(t)
+			period := domain.ParamPeriod{Start: t, MSL: periodMSL}
+			if correction.override {
+				if period.Constituents, err = finishConstituents(req, periodConstituents, fes); err != nil {
+					return nil, err
+				}
+			}
+			periods = append(periods, period)
+		}
+	}
+	if constituents, err = finishConstituents(req, constituents, fes); err != nil {
+		return nil, err
 	}
 
 	// Set longitude for Greenwich phase correction (only for lat/lon queries).
@@ -226,157 +826,176 @@ func (uc *PredictionUseCase) Execute(req PredictionRequest) (*PredictionResponse
 
 	// Reference time: use FES epoch for FES source to align phases, else Unix epoch.
 	refTime := time.Unix(0, 0).UTC()
-	if source == sourceFES {
-		// FES2014 phases are commonly referenced to 2012-01-01 00:00:00 UTC.
+	if source == sourceFES || source == sourceTPXO {
+		// FES2014 phases are commonly referenced to 2012-01-01 00:00:00 UTC;
+		// TPXO phases are Greenwich lags like FES ones.
 		refTime = time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
 	}
 
+	nodal := domain.NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(refTime)
+
 	params := domain.PredictionParams{
 		Constituents:    constituents,
 		MSL:             msl,
 		Longitude:       lon,
-		NodalCorrection: domain.NewAstronomicalNodalCorrection(),
+		NodalCorrection: nodal,
 		ReferenceTime:   refTime,
 		PhaseConvention: phaseConv,
+		Model:           uc.model,
 	}
-
-	// Generate predictions at requested interval.
-	predictions := domain.GeneratePredictions(req.Start, req.End, req.Interval, params)
-
-	// Compute extrema on high-resolution (1m) grid for accurate times regardless of interval.
-	preciseInterval := time.Minute
-	if req.Interval < preciseInterval {
-		preciseInterval = req.Interval
+	if len(periods) > 0 {
+		params.Model = domain.PeriodModel{Base: uc.model, Periods: periods}
 	}
-	precisePredictions := domain.GeneratePredictions(req.Start, req.End, preciseInterval, params)
-	extrema := domain.RefineExtrema(precisePredictions, domain.FindExtrema(precisePredictions))
-
-	// Choose output timezone.
-	tz := req.Timezone
-	if tz == "" {
-		tz = "utc"
+	switch {
+	case req.Lat != nil && req.Lon != nil:
+		params.Position = &domain.Position{Lat: *req.Lat, Lon: *req.Lon}
+	case station != nil && station.Lat != nil && station.Lon != nil:
+		params.Position = &domain.Position{Lat: *station.Lat, Lon: *station.Lon}
 	}
-	var loc *time.Location
-	var tzLabel string
-	switch tz {
-	case "jst", "JST":
-		loc = time.FixedZone("JST", 9*60*60)
-		tzLabel = "+09:00"
-	default:
-		loc = time.FixedZone("UTC", 0)
-		tzLabel = "+00:00"
-	}
-
-	// Convert to response format.
-	predictionPoints := make([]PredictionPoint, len(predictions))
-	for i, p := range predictions {
-		point := PredictionPoint{
-			Time:    p.Time.In(loc).Format(time.RFC3339),
-			HeightM: roundToDecimal(p.HeightM),
-		}
 
-		// Calculate water depth if seabed depth is available.
-		// Water depth = seabed_depth + msl + tide_height.
-		if metadata != nil && metadata.DepthM != nil {
-			waterDepth := *metadata.DepthM + msl + p.HeightM
-			roundedDepth := roundToDecimal(waterDepth)
-			point.DepthM = &roundedDepth
-		}
+	prepared := &preparedPrediction{
+		source:       source,
+		constituents: constituents,
+		metadata:     metadata,
+		station:      station,
+		msl:          msl,
+		periods:      periods,
+		params:       params,
+		fallback:     fallback,
+		degraded:     degraded,
+		timing:       Timing{Load: correctionsStart.Sub(loadStart)},
+		cache:        cache,
 
-		predictionPoints[i] = point
+		wetFallbackKm: wetFallbackKm,
 	}
-
-	highPoints := make([]PredictionPoint, len(extrema.Highs))
-	for i, h := range extrema.Highs {
-		point := PredictionPoint{
-			Time:    h.Time.In(loc).Format(time.RFC3339),
-			HeightM: roundToDecimal(h.HeightM),
+	if reference != nil {
+		// Innermost, as the port's corrections replace the reference heights.
+		prepared.params.Model = domain.NewSecondaryPortModel(*station.Secondary, referenceParams(params, reference), req.Start, req.End)
+		prepared.secondary = station.Secondary
+	}
+	if req.IncludeVLM {
+		if err := uc.applyLandMotion(prepared); err != nil {
+			return nil, err
 		}
-
-		// Calculate water depth if seabed depth is available.
-		if metadata != nil && metadata.DepthM != nil {
-			waterDepth := *metadata.DepthM + msl + h.HeightM
-			roundedDepth := roundToDecimal(waterDepth)
-			point.DepthM = &roundedDepth
+	}
+	if !req.withoutSeismic {
+		uc.applySeismicEvents(req, prepared)
+	}
+	if err := uc.applyScenario(req, prepared); err != nil {
+		return nil, err
+	}
+	if req.Nowcast {
+		if err := uc.applyNowcast(req, prepared); err != nil {
+			return nil, err
 		}
+	}
+	// Inside the ensemble, so member ranges are referred to the datum too.
+	if err := uc.applyTidalDatum(req, prepared); err != nil {
+		return nil, err
+	}
+	if ensemble != nil {
+		// Outermost, so the member range includes any nowcast.
+		prepared.params.Model = domain.EnsembleModel{Base: prepared.params.Model, Members: ensemble.members}
+		prepared.ensemble = ensemble
+	}
+	prepared.timing.Corrections = time.Since(correctionsStart)
+	return prepared, nil
+}
 
-		highPoints[i] = point
+// loadStationMetadata loads the metadata of a station file, or returns nil
+// when the station store does not provide metadata.
+func (uc *PredictionUseCase) loadStationMetadata(stationID string) (*domain.StationMetadata, error) {
+	loader, ok := (*uc.csvStore).(store.StationMetadataLoader)
+	if !ok {
+		return nil, nil
+	}
+	meta, err := loader.LoadStationMetadata(stationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata for station %s: %w", stationID, err)
 	}
+	return meta, nil
+}
 
-	lowPoints := make([]PredictionPoint, len(extrema.Lows))
-	for i, l := range extrema.Lows {
-		point := PredictionPoint{
-			Time:    l.Time.In(loc).Format(time.RFC3339),
-			HeightM: roundToDecimal(l.HeightM),
+// finishConstituents applies the constituent selection of req, reading
+// constituents the set lacks from fes at lat/lon, and sorts the result.
+func finishConstituents(req PredictionRequest, constituents []domain.ConstituentParam, fes store.ConstituentLoader) ([]domain.ConstituentParam, error) {
+	if len(req.Select) > 0 {
+		var loader store.ConstituentLoader
+		var lat, lon float64
+		if req.Lat != nil && req.Lon != nil {
+			loader, lat, lon = fes, *req.Lat, *req.Lon
 		}
-
-		// Calculate water depth if seabed depth is available.
-		if metadata != nil && metadata.DepthM != nil {
-			waterDepth := *metadata.DepthM + msl + l.HeightM
-			roundedDepth := roundToDecimal(waterDepth)
-			point.DepthM = &roundedDepth
+		var err error
+		if constituents, err = selectConstituents(constituents, req.Select, loader, lat, lon); err != nil {
+			return nil, err
 		}
-
-		lowPoints[i] = point
 	}
+	return domain.SortedConstituents(constituents), nil
+}
 
-	// Extract constituent names.
-	constituentNames := make([]string, len(constituents))
-	for i, c := range constituents {
-		constituentNames[i] = c.Name
+// mslAt returns the mean sea level in force at t.
+func (p *preparedPrediction) mslAt(t time.Time) float64 {
+	if period, ok := (domain.PeriodModel{Periods: p.periods}).At(t); ok {
+		return period.MSL
 	}
+	return p.msl
+}
 
-	// Determine datum.
-	datum := req.Datum
-	if datum == "" {
-		datum = "MSL"
+// referenceParams returns the parameters predicting the reference station
+// of a secondary port: its own datum offset and position.
+func referenceParams(params domain.PredictionParams, reference *domain.StationMetadata) domain.PredictionParams {
+	params.MSL = 0
+	if reference.DatumOffsetM != nil {
+		params.MSL = *reference.DatumOffsetM
 	}
-
-	// Build response.
-	response := &PredictionResponse{
-		Source:       source,
-		Datum:        datum,
-		Timezone:     tzLabel,
-		Constituents: constituentNames,
-		Predictions:  predictionPoints,
-		Extrema: ExtremaResponse{
-			Highs: highPoints,
-			Lows:  lowPoints,
-		},
-		Meta: map[string]string{
-			"model": "harmonic_v0",
-		},
+	params.Position = nil
+	if reference.Lat != nil && reference.Lon != nil {
+		params.Position = &domain.Position{Lat: *reference.Lat, Lon: *reference.Lon}
 	}
+	return params
+}
 
-	// Add metadata if available.
-	if metadata != nil {
-		if metadata.MSL != 0.0 {
-			response.MSL = &metadata.MSL
-		}
-		if metadata.DepthM != nil {
-			response.SeabedDepth = metadata.DepthM
-		}
-		if metadata.DatumName != "" {
-			response.Meta["datum_name"] = metadata.DatumName
+// resolveCustomConstituents canonicalizes client-supplied constituents and
+// fills in standard speeds. Constituents outside the standard table need an
+// explicit speed.
+func resolveCustomConstituents(in []domain.ConstituentParam) ([]domain.ConstituentParam, error) {
+	out := make([]domain.ConstituentParam, len(in))
+	seen := make(map[string]bool, len(in))
+	for i, c := range in {
+		if canonical, ok := domain.CanonicalConstituentName(c.Name); ok {
+			c.Name = canonical
+			if c.SpeedDegPerHr == 0 {
+				c.SpeedDegPerHr, _ = domain.GetConstituentSpeed(canonical)
+			}
 		}
-		if metadata.SourceName != "" {
-			response.Meta["metadata_source"] = metadata.SourceName
+		switch {
+		case c.Name == "":
+			return nil, fmt.Errorf("invalid request: constituent %d has no name", i+1)
+		case c.SpeedDegPerHr <= 0:
+			return nil, fmt.Errorf("invalid request: unknown constituent %s (provide speed_deg_per_hr)", c.Name)
+		case c.AmplitudeM < 0:
+			return nil, fmt.Errorf("invalid request: negative amplitude for constituent %s", c.Name)
+		case seen[c.Name]:
+			return nil, fmt.Errorf("invalid request: duplicate constituent %s", c.Name)
 		}
+		seen[c.Name] = true
+		out[i] = c
 	}
+	return out, nil
+}
 
-	// Add attribution based on source.
-	if source == sourceCSV {
-		response.Meta["attribution"] = "Mock CSV (for dev). Replace with FES later."
-	} else {
-		response.Meta["attribution"] = "FES2014/2022 tidal model"
+// PredictSeries returns raw predicted levels for a request at its interval,
+// without response formatting or extrema detection.
+func (uc *PredictionUseCase) PredictSeries(req PredictionRequest) ([]domain.TideLevel, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
 	}
-
-	// Record applied datum offset if provided.
-	if req.DatumOffsetM != nil {
-		response.Meta["datum_offset_m"] = fmt.Sprintf("%.3f", *req.DatumOffsetM)
+	prepared, err := uc.prepare(req)
+	if err != nil {
+		return nil, err
 	}
-
-	return response, nil
+	return domain.GeneratePredictions(req.Start, req.End, req.Interval, prepared.params), nil
 }
 
 // GetAllConstituents returns all available constituents.
@@ -398,10 +1017,27 @@ func (uc *PredictionUseCase) GetBathymetry(lat, lon float64) (*domain.LocationMe
 	if metadata == nil {
 		return nil, fmt.Errorf("no bathymetry data available for location (%.4f, %.4f)", lat, lon)
 	}
+	if len(metadata.Sources) == 0 && len(metadata.Degraded) > 0 {
+		return nil, unavailableError("bathymetry data", metadata.Degraded)
+	}
 
 	return metadata, nil
 }
 
+// overrideLandCheck returns the check keeping station overrides from
+// applying across land, from the bathymetry store's elevations; nil
+// without them. Paths without elevations do not count as crossing land.
+func (uc *PredictionUseCase) overrideLandCheck() landCheck {
+	checker, ok := uc.bathymetryStore.(bathymetry.LandPathChecker)
+	if !ok {
+		return nil
+	}
+	return func(lat1, lon1, lat2, lon2 float64) bool {
+		crosses, ok := checker.CrossesLand(lat1, lon1, lat2, lon2)
+		return ok && crosses
+	}
+}
+
 // Helper function to round to 3 decimal places.
 func roundToDecimal(val float64) float64 {
 	multiplier := 1000.0
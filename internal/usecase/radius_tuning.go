@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// defaultRadiusTuningDays is the synthesis window of a tuning; a month
+	// covers the spring-neap cycle.
+	defaultRadiusTuningDays = 30
+	// maxRadiusTuningDays bounds the synthesis window.
+	maxRadiusTuningDays = 366
+	// defaultMinRadiusKm and defaultMaxRadiusKm bound tuned radii.
+	defaultMinRadiusKm = 5
+	defaultMaxRadiusKm = 100
+)
+
+// RadiusTuningRequest selects the override entries to tune and the bounds
+// of their radii.
+type RadiusTuningRequest struct {
+	Stations    []string // Station keys; empty tunes every entry.
+	MinRadiusKm float64  // Default 5.
+	MaxRadiusKm float64  // Default 100; checkpoints farther away are ignored.
+	Days        int      // Hourly synthesis window; default 30.
+	// Write stores the tuned radii in the station override table.
+	Write bool
+}
+
+// RadiusTuning is the outcome of a radius tuning.
+type RadiusTuning struct {
+	Start    string          `json:"start"`
+	End      string          `json:"end"`
+	Stations []StationRadius `json:"stations"`
+	Written  bool            `json:"written"`
+	// StationTables is the table digest after writing.
+	StationTables string `json:"station_tables,omitempty"`
+}
+
+// StationRadius is the tuned radius of an override entry and the
+// checkpoints it was derived from, nearest first.
+type StationRadius struct {
+	Station         string             `json:"station"`
+	CurrentRadiusKm float64            `json:"current_radius_km"`
+	TunedRadiusKm   float64            `json:"tuned_radius_km"`
+	Checkpoints     []RadiusCheckpoint `json:"checkpoints"`
+	// Reason explains a radius left unchanged.
+	Reason string `json:"reason,omitempty"`
+}
+
+// RadiusCheckpoint compares, at another override station, the tide of its
+// own override with pure FES and with the tuned entry's override applied.
+type RadiusCheckpoint struct {
+	Station       string  `json:"station"`
+	DistanceKm    float64 `json:"distance_km"`
+	FESRMSEM      float64 `json:"fes_rmse_m"`
+	OverrideRMSEM float64 `json:"override_rmse_m"`
+	Improved      bool    `json:"improved"`
+}
+
+// TuneOverrideRadii estimates, per override entry, the radius within which
+// applying its constituents improves on pure FES. The other override
+// stations within MaxRadiusKm are checkpoints: their own overrides are
+// taken as the truth, and the tidal RMSE of FES at the checkpoint, with and
+// without the tuned entry's constituents, is compared over an hourly
+// window (datum offsets are left out). The radius extends to where the
+// improvement decays to zero, interpolated between the last checkpoint
+// improved and the first one not improved. Entries without checkpoints keep
+// their radius. Optionally the tuned radii are written to the table.
+func (uc *PredictionUseCase) TuneOverrideRadii(req RadiusTuningRequest) (*RadiusTuning, error) {
+	if req.MinRadiusKm == 0 {
+		req.MinRadiusKm = defaultMinRadiusKm
+	}
+	if req.MaxRadiusKm == 0 {
+		req.MaxRadiusKm = defaultMaxRadiusKm
+	}
+	if req.Days == 0 {
+		req.Days = defaultRadiusTuningDays
+	}
+	if req.MinRadiusKm < 0 || req.MaxRadiusKm < req.MinRadiusKm {
+		return nil, errors.New("radius bounds must satisfy 0 <= min_radius_km <= max_radius_km")
+	}
+	if req.Days < 1 || req.Days > maxRadiusTuningDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxRadiusTuningDays)
+	}
+	if uc.fesStore == nil {
+		return nil, errors.New("no FES store configured")
+	}
+
+	base := uc.tables.digest()
+	_, entries := uc.tables.entries()
+	if len(entries) == 0 {
+		return nil, errors.New("station override table has no entries")
+	}
+	selected := entries
+	if len(req.Stations) > 0 {
+		selected = nil
+		for _, e := range entries {
+			if slices.Contains(req.Stations, e.key()) {
+				selected = append(selected, e)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no override entries for stations %v", req.Stations)
+		}
+	}
+
+	start := uc.clock.Now().UTC().Truncate(24 * time.Hour)
+	end := start.Add(time.Duration(req.Days) * 24 * time.Hour)
+	var times []time.Time
+	for t := start; t.Before(end); t = t.Add(time.Hour) {
+		times = append(times, t)
+	}
+
+	// FES constituents and truth of every checkpoint candidate, loaded once.
+	type checkpoint struct {
+		fes   []domain.ConstituentParam
+		truth []float64
+		err   error
+	}
+	checkpoints := make(map[string]*checkpoint, len(entries))
+	load := func(e *stationOverrideEntry) *checkpoint {
+		if cp, ok := checkpoints[e.key()]; ok {
+			return cp
+		}
+		cp := &checkpoint{}
+		checkpoints[e.key()] = cp
+		if cp.fes, cp.err = (*uc.fesStore).LoadForLocation(e.Lat, e.Lon); cp.err == nil {
+			cp.truth = tidalSeries(e.apply(cp.fes), e.Lon, times)
+		}
+		return cp
+	}
+
+	result := &RadiusTuning{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339)}
+	var tuned []stationOverrideEntry
+	for _, entry := range selected {
+		current := entry.RadiusKm
+		if current == 0 {
+			current = defaultOverrideRadiusKm
+		}
+		station := StationRadius{Station: entry.key(), CurrentRadiusKm: current, Checkpoints: []RadiusCheckpoint{}}
+		for i := range entries {
+			other := &entries[i]
+			if other.key() == entry.key() {
+				continue
+			}
+			d := haversineKm(entry.Lat, entry.Lon, other.Lat, other.Lon)
+			if d > req.MaxRadiusKm {
+				continue
+			}
+			cp := load(other)
+			if cp.err != nil {
+				continue // No FES data at the checkpoint (e.g., on land).
+			}
+			// Improvements below the 1 mm rounding do not count.
+			fesRMSE := roundToDecimal(rmse(tidalSeries(cp.fes, other.Lon, times), cp.truth))
+			overrideRMSE := roundToDecimal(rmse(tidalSeries(entry.apply(cp.fes), other.Lon, times), cp.truth))
+			station.Checkpoints = append(station.Checkpoints, RadiusCheckpoint{
+				Station:       other.key(),
+				DistanceKm:    roundToDecimal(d),
+				FESRMSEM:      fesRMSE,
+				OverrideRMSEM: overrideRMSE,
+				Improved:      overrideRMSE < fesRMSE,
+			})
+		}
+		slices.SortFunc(station.Checkpoints, func(a, b RadiusCheckpoint) int {
+			return cmp.Compare(a.DistanceKm, b.DistanceKm)
+		})
+		station.TunedRadiusKm, station.Reason = tunedRadius(station.Checkpoints, current, req.MinRadiusKm, req.MaxRadiusKm)
+		if station.TunedRadiusKm != current {
+			entry.RadiusKm = station.TunedRadiusKm
+			tuned = append(tuned, entry)
+		}
+		result.Stations = append(result.Stations, station)
+	}
+
+	if req.Write && len(tuned) > 0 {
+		if err := uc.tables.upsert(base, nil, tuned); err != nil {
+			return nil, err
+		}
+		result.Written = true
+		result.StationTables = uc.tables.digest()
+	}
+	return result, nil
+}
+
+// tunedRadius returns the radius at which the improvement of checkpoints
+// (sorted by distance) decays to zero, clamped to [minKm, maxKm] and
+// rounded to 0.1 km. Without checkpoints the current radius is kept; when
+// all improve, the radius reaches at least the farthest of them.
+func tunedRadius(checkpoints []RadiusCheckpoint, current, minKm, maxKm float64) (radiusKm float64, reason string) {
+	if len(checkpoints) == 0 {
+		return current, "no checkpoints within max_radius_km"
+	}
+	var lastDist, lastGain float64
+	radius := -1.0
+	for _, cp := range checkpoints {
+		gain := cp.FESRMSEM - cp.OverrideRMSEM
+		if !cp.Improved {
+			if lastGain > 0 {
+				// Linear decay of the gain between the two checkpoints.
+				radius = lastDist + (cp.DistanceKm-lastDist)*lastGain/(lastGain-gain)
+			} else {
+				radius = cp.DistanceKm / 2
+			}
+			break
+		}
+		lastDist, lastGain = cp.DistanceKm, gain
+	}
+	if radius < 0 {
+		radius = math.Max(current, lastDist)
+	}
+	radius = math.Min(math.Max(radius, minKm), maxKm)
+	return math.Round(radius*10) / 10, ""
+}
+
+// tidalSeries synthesizes the harmonic tide of FES-convention constituents
+// at longitude lon, without mean sea level.
+func tidalSeries(constituents []domain.ConstituentParam, lon float64, times []time.Time) []float64 {
+	refTime := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodal := domain.NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(refTime)
+	params := domain.PredictionParams{
+		Constituents:    constituents,
+		Longitude:       lon,
+		NodalCorrection: nodal,
+		ReferenceTime:   refTime,
+		PhaseConvention: domain.PhaseConvFESGreenwich,
+	}
+	heights := make([]float64, len(times))
+	for i, t := range times {
+		heights[i] = domain.CalculateTideHeight(t, params)
+	}
+	return heights
+}
+
+// rmse returns the root mean square difference of two series.
+func rmse(a, b []float64) float64 {
+	var sse float64
+	for i := range a {
+		sse += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sse / float64(len(a)))
+}
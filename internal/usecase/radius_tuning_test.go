@@ -0,0 +1,28 @@
+package usecase
+
+import "testing"
+
+func TestTunedRadius(t *testing.T) {
+	improved := func(d, gain float64) RadiusCheckpoint {
+		return RadiusCheckpoint{DistanceKm: d, FESRMSEM: 0.1, OverrideRMSEM: 0.1 - gain, Improved: gain > 0}
+	}
+	tests := []struct {
+		name        string
+		checkpoints []RadiusCheckpoint
+		want        float64
+	}{
+		{"no checkpoints keep the radius", nil, 40},
+		{"gain decays between checkpoints", []RadiusCheckpoint{improved(10, 0.03), improved(30, -0.01)}, 25},
+		{"first checkpoint worse halves its distance", []RadiusCheckpoint{improved(20, -0.02)}, 10},
+		{"all improve extends to the farthest", []RadiusCheckpoint{improved(20, 0.02), improved(60, 0.01)}, 60},
+		{"all improve keeps a larger radius", []RadiusCheckpoint{improved(20, 0.02)}, 40},
+		{"clamped to the minimum", []RadiusCheckpoint{improved(4, -0.02)}, 5},
+		{"clamped to the maximum", []RadiusCheckpoint{improved(95, 0.02)}, 80},
+	}
+	for _, tt := range tests {
+		got, _ := tunedRadius(tt.checkpoints, 40, 5, 80)
+		if got != tt.want {
+			t.Errorf("%s: radius = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// minRecalibrationSamples is the fewest hourly observations refitted;
+	// shorter records keep the station's current values.
+	minRecalibrationSamples = 30 * 24
+	// maxRecalibrationProposals bounds the staged proposals kept in memory.
+	maxRecalibrationProposals = 20
+)
+
+// Recalibration proposal states.
+const (
+	RecalibrationPending    = "pending"
+	RecalibrationApproved   = "approved"
+	RecalibrationRejected   = "rejected"
+	RecalibrationSuperseded = "superseded"
+)
+
+var (
+	// ErrRecalibrationNotFound reports an unknown proposal ID.
+	ErrRecalibrationNotFound = errors.New("recalibration proposal not found")
+	// ErrRecalibrationConflict reports a proposal that can no longer be
+	// decided, or a run while another is in progress.
+	ErrRecalibrationConflict = errors.New("recalibration conflict")
+)
+
+// RecalibrationProposal is a staged refit of the station tables, applied
+// only when approved.
+type RecalibrationProposal struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	DecidedAt string `json:"decided_at,omitempty"`
+	// Start and End bound the observations fitted.
+	Start    string                 `json:"start"`
+	End      string                 `json:"end"`
+	Stations []StationRecalibration `json:"stations"`
+	Refitted int                    `json:"refitted"`
+	Failed   int                    `json:"failed"`
+	// BaseTables is the table digest the proposal was fitted against;
+	// approval is refused once the tables have changed.
+	BaseTables string `json:"base_tables"`
+	// StationTables is the table digest after approval.
+	StationTables string `json:"station_tables,omitempty"`
+}
+
+// StationRecalibration is the refit of one station, or why it failed.
+type StationRecalibration struct {
+	Station         string  `json:"station"`
+	Lat             float64 `json:"lat"`
+	Lon             float64 `json:"lon"`
+	Samples         int     `json:"samples"`
+	RMSEM           float64 `json:"rmse_m"`
+	MaxAbsResidualM float64 `json:"max_abs_residual_m"`
+	DatumOffsetM    float64 `json:"datum_offset_m"`
+	// PreviousDatumOffsetM is the current datum offset entry, if any.
+	PreviousDatumOffsetM *float64 `json:"previous_datum_offset_m,omitempty"`
+	// Constituents are empty for stations without an override entry,
+	// whose datum offset alone is refitted.
+	Constituents []RecalibratedConstituent `json:"constituents,omitempty"`
+	Error        string                    `json:"error,omitempty"`
+
+	datum    *datumOffsetEntry
+	override *stationOverrideEntry
+}
+
+// RecalibratedConstituent is a refitted override constituent with its
+// current value.
+type RecalibratedConstituent struct {
+	Name               string  `json:"name"`
+	AmplitudeM         float64 `json:"amplitude_m"`
+	PhaseDeg           float64 `json:"phase_deg"`
+	PreviousAmplitudeM float64 `json:"previous_amplitude_m"`
+	PreviousPhaseDeg   float64 `json:"previous_phase_deg"`
+	// Kept is true when the observations are too short to resolve the
+	// constituent, which keeps its current value.
+	Kept bool `json:"kept,omitempty"`
+}
+
+// RecalibrationUseCase periodically refits station overrides and datum
+// offsets from recent observations, as cmd/jma-overrides does, and stages
+// the results for approval.
+type RecalibrationUseCase struct {
+	predictionUC *PredictionUseCase
+	history      ObservationHistory
+	window       time.Duration
+	stations     []string // Empty: every station in the tables.
+
+	running sync.Mutex
+	mu      sync.Mutex
+	staged  []*RecalibrationProposal
+	nextID  int
+}
+
+// NewRecalibrationUseCase creates a recalibration use case fitting the
+// last window of observations. stations restricts the stations refitted.
+func NewRecalibrationUseCase(predictionUC *PredictionUseCase, history ObservationHistory, window time.Duration, stations []string) *RecalibrationUseCase {
+	return &RecalibrationUseCase{
+		predictionUC: predictionUC,
+		history:      history,
+		window:       window,
+		stations:     stations,
+	}
+}
+
+// Run stages a recalibration every interval until ctx is canceled. The
+// first run waits one interval, as the tables were just loaded.
+func (r *RecalibrationUseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			proposal, err := r.Recalibrate()
+			if err != nil {
+				log.Printf("Warning: recalibration failed: %v", err)
+				continue
+			}
+			log.Printf("Staged recalibration %s: %d stations refitted, %d failed", proposal.ID, proposal.Refitted, proposal.Failed)
+		}
+	}
+}
+
+// Recalibrate refits the stations from the latest observations and stages
+// the result, superseding pending proposals.
+func (r *RecalibrationUseCase) Recalibrate() (*RecalibrationProposal, error) {
+	if !r.running.TryLock() {
+		return nil, fmt.Errorf("%w: a recalibration is already running", ErrRecalibrationConflict)
+	}
+	defer r.running.Unlock()
+
+	tables := r.predictionUC.tables
+	base := tables.digest()
+	datum, overrides := tables.entries()
+	targets := recalibrationTargets(datum, overrides, r.stations)
+	if len(targets) == 0 {
+		return nil, errors.New("no stations to recalibrate")
+	}
+
+	now := r.predictionUC.Clock().Now().UTC()
+	proposal := &RecalibrationProposal{
+		Status:     RecalibrationPending,
+		CreatedAt:  now.Format(time.RFC3339),
+		Start:      now.Add(-r.window).Format(time.RFC3339),
+		End:        now.Format(time.RFC3339),
+		Stations:   make([]StationRecalibration, 0, len(targets)),
+		BaseTables: base,
+	}
+	for _, target := range targets {
+		result := r.refit(target, now.Add(-r.window), now)
+		if result.Error != "" {
+			proposal.Failed++
+		} else {
+			proposal.Refitted++
+		}
+		proposal.Stations = append(proposal.Stations, result)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.staged {
+		if p.Status == RecalibrationPending {
+			p.Status = RecalibrationSuperseded
+		}
+	}
+	r.nextID++
+	proposal.ID = strconv.Itoa(r.nextID)
+	r.staged = append(r.staged, proposal)
+	if len(r.staged) > maxRecalibrationProposals {
+		r.staged = r.staged[len(r.staged)-maxRecalibrationProposals:]
+	}
+	return proposal.clone(), nil
+}
+
+// recalibrationTarget is a station with its current table entries.
+type recalibrationTarget struct {
+	station  string
+	datum    *datumOffsetEntry
+	override *stationOverrideEntry
+}
+
+// recalibrationTargets pairs the datum offset and override entries of each
+// station (a datum offset's name is its station code), in station order.
+func recalibrationTargets(datum []datumOffsetEntry, overrides []stationOverrideEntry, only []string) []recalibrationTarget {
+	byStation := make(map[string]*recalibrationTarget)
+	target := func(station string) *recalibrationTarget {
+		t, ok := byStation[station]
+		if !ok {
+			t = &recalibrationTarget{station: station}
+			byStation[station] = t
+		}
+		return t
+	}
+	for i := range datum {
+		target(datum[i].Name).datum = &datum[i]
+	}
+	for i := range overrides {
+		target(overrides[i].key()).override = &overrides[i]
+	}
+
+	if len(only) > 0 {
+		selected := make(map[string]*recalibrationTarget, len(only))
+		for _, s := range only {
+			if t, ok := byStation[s]; ok {
+				selected[s] = t
+			}
+		}
+		byStation = selected
+	}
+	targets := make([]recalibrationTarget, 0, len(byStation))
+	for _, t := range byStation {
+		targets = append(targets, *t)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].station < targets[j].station })
+	return targets
+}
+
+// refit fits one station's observations in [start, end]. Stations with an
+// override refit its constituents; others fit the default constituents for
+// the datum offset alone.
+func (r *RecalibrationUseCase) refit(target recalibrationTarget, start, end time.Time) StationRecalibration {
+	result := StationRecalibration{Station: target.station}
+	if target.override != nil {
+		result.Lat, result.Lon = target.override.Lat, target.override.Lon
+	} else {
+		result.Lat, result.Lon = target.datum.Lat, target.datum.Lon
+	}
+	if target.datum != nil {
+		result.PreviousDatumOffsetM = &target.datum.OffsetM
+	}
+	fail := func(err error) StationRecalibration {
+		result.Error = err.Error()
+		return result
+	}
+
+	observed, err := r.history.Between(target.station, start, end)
+	if err != nil {
+		return fail(err)
+	}
+	result.Samples = len(observed)
+	if len(observed) < minRecalibrationSamples {
+		return fail(fmt.Errorf("not enough observations (%d, need %d)", len(observed), minRecalibrationSamples))
+	}
+
+	// Constituents slower than one cycle over the observations cannot be
+	// separated from the mean and keep their current values.
+	spanDays := observed[len(observed)-1].Time.Sub(observed[0].Time).Hours() / 24
+	names := domain.FitConstituents
+	if target.override != nil {
+		names = make([]string, 0, len(target.override.Constituents))
+		for _, c := range target.override.Constituents {
+			names = append(names, c.Name)
+		}
+	}
+	fitted := make([]string, 0, len(names))
+	for _, name := range names {
+		if speed, ok := domain.GetConstituentSpeed(name); ok && domain.SpeedToCPD(speed)*spanDays >= 1 {
+			fitted = append(fitted, name)
+		}
+	}
+
+	fit, err := domain.FitHarmonics(observed, result.Lon, fitted)
+	if err != nil {
+		return fail(fmt.Errorf("fit failed: %w", err))
+	}
+	result.RMSEM = roundToDecimal(fit.RMSEM)
+	result.MaxAbsResidualM = roundToDecimal(fit.MaxAbsResidualM)
+	result.DatumOffsetM = roundFit(fit.InterceptM)
+
+	result.datum = &datumOffsetEntry{Name: target.station, Lat: result.Lat, Lon: result.Lon, OffsetM: result.DatumOffsetM}
+	if target.override == nil {
+		return result
+	}
+
+	refitted := make(map[string]domain.ConstituentParam, len(fit.Constituents))
+	for _, c := range fit.Constituents {
+		refitted[c.Name] = c
+	}
+	override := *target.override
+	override.Constituents = make([]overrideConstituent, len(target.override.Constituents))
+	override.DatumOffset = &result.DatumOffsetM
+	for i, prev := range target.override.Constituents {
+		next := prev
+		c, ok := refitted[prev.Name]
+		if ok {
+			next = overrideConstituent{Name: prev.Name, AmplitudeM: roundFit(c.AmplitudeM), PhaseDeg: roundFit(c.PhaseDeg)}
+		}
+		override.Constituents[i] = next
+		result.Constituents = append(result.Constituents, RecalibratedConstituent{
+			Name:               prev.Name,
+			AmplitudeM:         next.AmplitudeM,
+			PhaseDeg:           next.PhaseDeg,
+			PreviousAmplitudeM: prev.AmplitudeM,
+			PreviousPhaseDeg:   prev.PhaseDeg,
+			Kept:               !ok,
+		})
+	}
+	result.override = &override
+	return result
+}
+
+// Proposals returns the staged proposals, newest first.
+func (r *RecalibrationUseCase) Proposals() []RecalibrationProposal {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RecalibrationProposal, 0, len(r.staged))
+	for i := len(r.staged) - 1; i >= 0; i-- {
+		out = append(out, *r.staged[i].clone())
+	}
+	return out
+}
+
+// Proposal returns the staged proposal id.
+func (r *RecalibrationUseCase) Proposal(id string) (*RecalibrationProposal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, err := r.find(id)
+	if err != nil {
+		return nil, err
+	}
+	return p.clone(), nil
+}
+
+// Approve writes the refitted stations of a pending proposal to the datum
+// offset and override tables.
+func (r *RecalibrationUseCase) Approve(id string) (*RecalibrationProposal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, err := r.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != RecalibrationPending {
+		return nil, fmt.Errorf("%w: proposal %s is %s", ErrRecalibrationConflict, id, p.Status)
+	}
+
+	var datum []datumOffsetEntry
+	var overrides []stationOverrideEntry
+	for _, s := range p.Stations {
+		if s.datum != nil {
+			datum = append(datum, *s.datum)
+		}
+		if s.override != nil {
+			overrides = append(overrides, *s.override)
+		}
+	}
+	tables := r.predictionUC.tables
+	if err := tables.upsert(p.BaseTables, datum, overrides); err != nil {
+		if errors.Is(err, errStaleTables) {
+			return nil, fmt.Errorf("%w: %v", ErrRecalibrationConflict, err)
+		}
+		return nil, err
+	}
+	p.Status = RecalibrationApproved
+	p.DecidedAt = r.predictionUC.Clock().Now().UTC().Format(time.RFC3339)
+	p.StationTables = tables.digest()
+	return p.clone(), nil
+}
+
+// Reject discards a pending proposal.
+func (r *RecalibrationUseCase) Reject(id string) (*RecalibrationProposal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, err := r.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != RecalibrationPending {
+		return nil, fmt.Errorf("%w: proposal %s is %s", ErrRecalibrationConflict, id, p.Status)
+	}
+	p.Status = RecalibrationRejected
+	p.DecidedAt = r.predictionUC.Clock().Now().UTC().Format(time.RFC3339)
+	return p.clone(), nil
+}
+
+func (r *RecalibrationUseCase) find(id string) (*RecalibrationProposal, error) {
+	for _, p := range r.staged {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrRecalibrationNotFound, id)
+}
+
+// clone copies a proposal for callers outside the lock.
+func (p *RecalibrationProposal) clone() *RecalibrationProposal {
+	c := *p
+	c.Stations = append([]StationRecalibration(nil), p.Stations...)
+	return &c
+}
+
+// roundFit rounds fitted values to 1e-6, as cmd/jma-harmonics does.
+func roundFit(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// PredictAt returns heights at exactly the given instants (e.g., AIS fix
+// times), in the given order. The request's Start, End and Interval are
+// ignored; the response has no extrema.
+func (uc *PredictionUseCase) PredictAt(req PredictionRequest, times []time.Time) (*PredictionResponse, error) {
+	first, last, err := validateSampleTimes(times)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Start, req.End = first, last
+	if err := req.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	prepared, err := uc.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, _ := outputZone(req.Timezone)
+	synthesisStart := time.Now()
+	points := make([]PredictionPoint, len(times))
+	for i, t := range times {
+		level := domain.TideLevel{Time: t, HeightM: prepared.params.Height(t)}
+		points[i] = prepared.point(level, loc)
+	}
+	prepared.timing.Synthesis = time.Since(synthesisStart)
+	extrema := ExtremaResponse{Highs: []PredictionPoint{}, Lows: []PredictionPoint{}}
+	return uc.newResponse(req, prepared, points, extrema), nil
+}
+
+// validateSampleTimes bounds the count and span of requested instants as
+// for interval predictions, and returns the first and last of them.
+func validateSampleTimes(times []time.Time) (first, last time.Time, err error) {
+	if len(times) == 0 {
+		return first, last, errors.New("at least one time is required")
+	}
+	if len(times) > maxPredictionPoints {
+		return first, last, fmt.Errorf("too many times (%d) - at most %d allowed", len(times), maxPredictionPoints)
+	}
+	first, last = times[0], times[0]
+	for _, t := range times[1:] {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	if last.Sub(first) > maxPredictionSpan {
+		return first, last, errors.New("times must span at most 365 days")
+	}
+	return first, last, nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// maxScenarioRiseM bounds the sea level rise of a scenario.
+const maxScenarioRiseM = 10.0
+
+// ErrUnknownScenario reports a slr_scenario that is not configured.
+var ErrUnknownScenario = errors.New("unknown sea level rise scenario")
+
+// SLRScenario is a sea level rise for what-if analyses, either named in
+// the scenario table or given per request.
+type SLRScenario struct {
+	Name        string  `json:"name,omitempty"`
+	RiseM       float64 `json:"slr_m"`
+	Description string  `json:"description,omitempty"`
+}
+
+// LoadSLRScenarios reads the named scenarios from a JSON list.
+func LoadSLRScenarios(path string) ([]SLRScenario, error) {
+	//nolint:gosec // G304: File path from env var.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sea level rise scenarios: %w", err)
+	}
+	var scenarios []SLRScenario
+	if err := json.Unmarshal(b, &scenarios); err != nil {
+		return nil, fmt.Errorf("invalid sea level rise scenarios JSON: %w", err)
+	}
+	seen := make(map[string]bool, len(scenarios))
+	for i, s := range scenarios {
+		if s.Name == "" {
+			return nil, fmt.Errorf("sea level rise scenario %d has no name", i)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("duplicate sea level rise scenario %q", s.Name)
+		}
+		if err := validateRise(s.RiseM); err != nil {
+			return nil, fmt.Errorf("sea level rise scenario %q: %w", s.Name, err)
+		}
+		seen[s.Name] = true
+	}
+	return scenarios, nil
+}
+
+// SetSLRScenarios sets the scenarios selectable by slr_scenario.
+func (uc *PredictionUseCase) SetSLRScenarios(scenarios []SLRScenario) {
+	uc.scenarios = scenarios
+}
+
+// SLRScenarios returns the scenarios selectable by slr_scenario.
+func (uc *PredictionUseCase) SLRScenarios() []SLRScenario {
+	return uc.scenarios
+}
+
+// validateRise checks the sea level rise of a scenario.
+func validateRise(riseM float64) error {
+	if math.IsNaN(riseM) || math.Abs(riseM) > maxScenarioRiseM {
+		return fmt.Errorf("slr_m must be between -%g and %g", maxScenarioRiseM, maxScenarioRiseM)
+	}
+	return nil
+}
+
+// resolveScenario returns the scenario a request selects, or nil.
+func (uc *PredictionUseCase) resolveScenario(req PredictionRequest) (*SLRScenario, error) {
+	switch {
+	case req.SLRM != nil:
+		return &SLRScenario{RiseM: *req.SLRM}, nil
+	case req.SLRScenario != "":
+		for _, s := range uc.scenarios {
+			if s.Name == req.SLRScenario {
+				return &s, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %q", ErrUnknownScenario, req.SLRScenario)
+	}
+	return nil, nil
+}
+
+// applyScenario wraps the prepared model with the request's sea level rise.
+func (uc *PredictionUseCase) applyScenario(req PredictionRequest, p *preparedPrediction) error {
+	scenario, err := uc.resolveScenario(req)
+	if err != nil || scenario == nil {
+		return err
+	}
+	base := p.params.Model
+	if base == nil {
+		base = domain.HarmonicModel{}
+	}
+	p.params.Model = domain.SeaLevelRiseModel{Base: base, RiseM: scenario.RiseM}
+	p.scenario = scenario
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// maxSeismicDisplacementM bounds the displacement of a seismic event.
+const maxSeismicDisplacementM = 10.0
+
+// seismicEventEntry is an event of the seismic event registry file.
+type seismicEventEntry struct {
+	Name          string       `json:"name"`
+	Time          time.Time    `json:"time"`
+	Region        [][2]float64 `json:"region"` // [lon, lat] vertices, as in GeoJSON.
+	DisplacementM float64      `json:"displacement_m"`
+	Source        string       `json:"source"`
+}
+
+// LoadSeismicEvents reads the seismic event registry, a JSON list of
+// events with their region and vertical land displacement.
+func LoadSeismicEvents(path string) ([]domain.SeismicEvent, error) {
+	//nolint:gosec // G304: File path from env var.
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seismic events: %w", err)
+	}
+	var entries []seismicEventEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("invalid seismic events JSON: %w", err)
+	}
+	events := make([]domain.SeismicEvent, len(entries))
+	for i, e := range entries {
+		switch {
+		case e.Name == "":
+			return nil, fmt.Errorf("seismic event %d has no name", i)
+		case e.Time.IsZero():
+			return nil, fmt.Errorf("seismic event %q has no time", e.Name)
+		case e.Source == "":
+			return nil, fmt.Errorf("seismic event %q has no source", e.Name)
+		case len(e.Region) < 3:
+			return nil, fmt.Errorf("seismic event %q: region needs at least 3 vertices", e.Name)
+		case math.IsNaN(e.DisplacementM) || math.Abs(e.DisplacementM) > maxSeismicDisplacementM:
+			return nil, fmt.Errorf("seismic event %q: displacement_m must be between -%g and %g", e.Name, maxSeismicDisplacementM, maxSeismicDisplacementM)
+		}
+		region := make([]domain.Position, len(e.Region))
+		for j, v := range e.Region {
+			if v[0] < -180 || v[0] > 180 || v[1] < -90 || v[1] > 90 {
+				return nil, fmt.Errorf("seismic event %q: vertex %d is off the globe", e.Name, j)
+			}
+			region[j] = domain.Position{Lat: v[1], Lon: v[0]}
+		}
+		events[i] = domain.SeismicEvent{Name: e.Name, Time: e.Time, Region: region, DisplacementM: e.DisplacementM, Source: e.Source}
+	}
+	return events, nil
+}
+
+// SetSeismicEvents sets the seismic events applied to predictions in
+// their regions.
+func (uc *PredictionUseCase) SetSeismicEvents(events []domain.SeismicEvent) {
+	uc.seismicEvents = events
+}
+
+// SeismicEvents returns the configured seismic events.
+func (uc *PredictionUseCase) SeismicEvents() []domain.SeismicEvent {
+	return uc.seismicEvents
+}
+
+// applySeismicEvents wraps the prepared model with the events whose region
+// contains the prediction's position and which happened before the end of
+// the range.
+func (uc *PredictionUseCase) applySeismicEvents(req PredictionRequest, p *preparedPrediction) {
+	pos := p.params.Position
+	if pos == nil {
+		return
+	}
+	var events []domain.SeismicEvent
+	for _, e := range uc.seismicEvents {
+		if !e.Time.After(req.End) && e.Contains(pos.Lat, pos.Lon) {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return
+	}
+	base := p.params.Model
+	if base == nil {
+		base = domain.HarmonicModel{}
+	}
+	p.params.Model = domain.SeismicModel{Base: base, Events: events}
+	p.seismic = events
+}
+
+// addSeismicMeta records the applied seismic events and their sources in
+// response metadata.
+func (p *preparedPrediction) addSeismicMeta(meta map[string]string) {
+	if len(p.seismic) == 0 {
+		return
+	}
+	var names, sources []string
+	var displacement float64
+	for _, e := range p.seismic {
+		names = append(names, e.Name)
+		if !slices.Contains(sources, e.Source) {
+			sources = append(sources, e.Source)
+		}
+		displacement += e.DisplacementM
+	}
+	meta["seismic_events"] = strings.Join(names, ",")
+	meta["seismic_sources"] = strings.Join(sources, "; ")
+	meta["seismic_displacement_m"] = strconv.FormatFloat(displacement, 'f', 3, 64)
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+func TestSeismicEventsLeaveDatumsUnchanged(t *testing.T) {
+	speed, _ := domain.GetConstituentSpeed("M2")
+	loader := fallbackLoader{params: []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: speed}}}
+	plain := NewPredictionUseCase(nil, loader, nil)
+	shaken := NewPredictionUseCase(nil, loader, nil)
+	// An event in the middle of the 2001-2019 datum epoch.
+	shaken.SetSeismicEvents([]domain.SeismicEvent{{
+		Name:          "tohoku",
+		Time:          time.Date(2011, 3, 11, 5, 46, 0, 0, time.UTC),
+		Region:        []domain.Position{{Lat: 37, Lon: 140}, {Lat: 37, Lon: 143}, {Lat: 40, Lon: 143}, {Lat: 40, Lon: 140}},
+		DisplacementM: -0.5,
+		Source:        "test",
+	}})
+	lat, lon := 38.3, 141.5
+
+	want, err := plain.TidalDatums(PredictionRequest{Lat: &lat, Lon: &lon})
+	if err != nil {
+		t.Fatalf("TidalDatums: %v", err)
+	}
+	got, err := shaken.TidalDatums(PredictionRequest{Lat: &lat, Lon: &lon})
+	if err != nil {
+		t.Fatalf("TidalDatums with the event: %v", err)
+	}
+	for i, row := range got.Datums {
+		if row.ValueM != want.Datums[i].ValueM {
+			t.Errorf("%s = %g with the event, want %g", row.Name, row.ValueM, want.Datums[i].ValueM)
+		}
+	}
+
+	// LAT heights after the event move by its displacement alone.
+	req := PredictionRequest{
+		Lat: &lat, Lon: &lon, Datum: "LAT", Interval: time.Hour,
+		Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 1, 1, 6, 0, 0, 0, time.UTC),
+	}
+	before, err := plain.Execute(req)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	after, err := shaken.Execute(req)
+	if err != nil {
+		t.Fatalf("Execute with the event: %v", err)
+	}
+	if after.Meta["seismic_events"] != "tohoku" {
+		t.Errorf("seismic_events = %q, want tohoku", after.Meta["seismic_events"])
+	}
+	for i, p := range after.Predictions {
+		if d := math.Abs(p.HeightM - before.Predictions[i].HeightM); math.Abs(d-0.5) > 0.002 {
+			t.Errorf("%s: heights differ by %.3f m, want 0.5", p.Time, d)
+		}
+	}
+}
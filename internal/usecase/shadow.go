@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxShadowInFlight bounds concurrent shadow requests; further samples
+	// are dropped rather than queued.
+	maxShadowInFlight = 4
+	// maxShadowDivergences is the number of recent divergences kept.
+	maxShadowDivergences = 20
+)
+
+// ShadowTarget replays requests against another server instance.
+type ShadowTarget interface {
+	// Target identifies the instance (e.g., its base URL).
+	Target() string
+	// Replay sends a request and returns the response status and body.
+	Replay(method, target string, header http.Header, body []byte) (int, []byte, error)
+}
+
+// ShadowRequest is a served request and its response, to be replayed.
+type ShadowRequest struct {
+	Method string
+	Target string // Path and query.
+	Header http.Header
+	Body   []byte
+	Status int
+	// Response is the body served to the client.
+	Response []byte
+}
+
+// ShadowDivergence is a sampled request whose shadow response differed.
+type ShadowDivergence struct {
+	Time    time.Time `json:"time"`
+	Request string    `json:"request"`
+	// MaxDiffM is the largest difference of a height or depth (fields
+	// ending in _m), at Path.
+	MaxDiffM float64 `json:"max_diff_m"`
+	Path     string  `json:"path,omitempty"`
+	// Mismatch describes a structural difference: status, a missing
+	// field, a series of another length or times that differ.
+	Mismatch string `json:"mismatch,omitempty"`
+}
+
+// ShadowStats summarizes shadow evaluation since startup.
+type ShadowStats struct {
+	Target      string             `json:"target"`
+	SampleRate  float64            `json:"sample_rate"`
+	ToleranceM  float64            `json:"tolerance_m"`
+	Sampled     int64              `json:"sampled"`
+	Compared    int64              `json:"compared"`
+	Divergent   int64              `json:"divergent"`
+	Errors      int64              `json:"errors"`
+	Dropped     int64              `json:"dropped"`
+	MaxDiffM    float64            `json:"max_diff_m"`
+	Divergences []ShadowDivergence `json:"recent_divergences"`
+}
+
+// ShadowEvaluator replays a sample of requests against another instance,
+// such as a canary of a new code version, and records responses whose
+// heights differ beyond a tolerance.
+type ShadowEvaluator struct {
+	target     ShadowTarget
+	sampleRate float64
+	toleranceM float64
+	inFlight   chan struct{}
+
+	mu    sync.Mutex
+	stats ShadowStats
+}
+
+// NewShadowEvaluator creates an evaluator replaying sampleRate (0-1) of
+// requests to target.
+func NewShadowEvaluator(target ShadowTarget, sampleRate, toleranceM float64) *ShadowEvaluator {
+	return &ShadowEvaluator{
+		target:     target,
+		sampleRate: sampleRate,
+		toleranceM: toleranceM,
+		inFlight:   make(chan struct{}, maxShadowInFlight),
+		stats: ShadowStats{
+			Target:      target.Target(),
+			SampleRate:  sampleRate,
+			ToleranceM:  toleranceM,
+			Divergences: []ShadowDivergence{},
+		},
+	}
+}
+
+// Sample reports whether a request should be replayed.
+func (s *ShadowEvaluator) Sample() bool {
+	//nolint:gosec // G404: Sampling needs no cryptographic randomness.
+	return rand.Float64() < s.sampleRate
+}
+
+// Submit replays req in the background and records the comparison.
+func (s *ShadowEvaluator) Submit(req ShadowRequest) {
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		s.record(func(st *ShadowStats) { st.Sampled++; st.Dropped++ })
+		return
+	}
+	s.record(func(st *ShadowStats) { st.Sampled++ })
+	go func() {
+		defer func() { <-s.inFlight }()
+		s.Compare(req)
+	}()
+}
+
+// Compare replays req and records whether the shadow response diverges.
+func (s *ShadowEvaluator) Compare(req ShadowRequest) {
+	status, body, err := s.target.Replay(req.Method, req.Target, req.Header, req.Body)
+	if err != nil {
+		log.Printf("Warning: shadow %s %s: %v", req.Method, req.Target, err)
+		s.record(func(st *ShadowStats) { st.Errors++ })
+		return
+	}
+
+	d := ShadowDivergence{Time: time.Now().UTC(), Request: req.Method + " " + req.Target}
+	if status != req.Status {
+		d.Mismatch = fmt.Sprintf("status %d, shadow %d", req.Status, status)
+	} else {
+		var primary, shadow any
+		if err := json.Unmarshal(req.Response, &primary); err != nil {
+			s.record(func(st *ShadowStats) { st.Errors++ })
+			return
+		}
+		if err := json.Unmarshal(body, &shadow); err != nil {
+			d.Mismatch = "shadow response is not JSON"
+		} else {
+			diffJSON("", primary, shadow, &d)
+		}
+	}
+
+	divergent := d.Mismatch != "" || d.MaxDiffM > s.toleranceM
+	if divergent {
+		if d.Mismatch != "" {
+			log.Printf("Shadow divergence: %s: %s", d.Request, d.Mismatch)
+		} else {
+			log.Printf("Shadow divergence: %s: height differs by %.4f m at %s (tolerance %.4f m)", d.Request, d.MaxDiffM, d.Path, s.toleranceM)
+		}
+	}
+	s.record(func(st *ShadowStats) {
+		st.Compared++
+		st.MaxDiffM = math.Max(st.MaxDiffM, d.MaxDiffM)
+		if !divergent {
+			return
+		}
+		st.Divergent++
+		st.Divergences = append(st.Divergences, d)
+		if len(st.Divergences) > maxShadowDivergences {
+			st.Divergences = st.Divergences[len(st.Divergences)-maxShadowDivergences:]
+		}
+	})
+}
+
+// Stats returns the shadow evaluation summary.
+func (s *ShadowEvaluator) Stats() ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.stats
+	st.Divergences = append([]ShadowDivergence(nil), s.stats.Divergences...)
+	return st
+}
+
+func (s *ShadowEvaluator) record(update func(*ShadowStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(&s.stats)
+}
+
+// shadowIgnoredFields differ between instances by design.
+//
+//nolint:gochecknoglobals // Intentional: fixed comparison rule.
+var shadowIgnoredFields = map[string]bool{"meta": true, "fingerprint": true}
+
+// diffJSON compares decoded JSON values, recording the largest difference
+// of numeric fields ending in _m and the first structural mismatch.
+// Other numbers and strings are not compared, except times.
+func diffJSON(path string, a, b any, d *ShadowDivergence) {
+	mismatch := func(format string, args ...any) {
+		if d.Mismatch == "" {
+			d.Mismatch = fmt.Sprintf(format, args...)
+		}
+	}
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			mismatch("%s: type differs", path)
+			return
+		}
+		keys := make([]string, 0, len(av))
+		for k := range av {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if shadowIgnoredFields[k] {
+				continue
+			}
+			bk, ok := bv[k]
+			if !ok {
+				mismatch("%s: missing in shadow", joinPath(path, k))
+				continue
+			}
+			diffJSON(joinPath(path, k), av[k], bk, d)
+		}
+		for k := range bv {
+			if _, ok := av[k]; !ok && !shadowIgnoredFields[k] {
+				mismatch("%s: only in shadow", joinPath(path, k))
+			}
+		}
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			mismatch("%s: type differs", path)
+			return
+		}
+		if len(av) != len(bv) {
+			mismatch("%s: %d items, shadow %d", path, len(av), len(bv))
+			return
+		}
+		for i := range av {
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], d)
+		}
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			mismatch("%s: type differs", path)
+			return
+		}
+		if diff := math.Abs(av - bv); strings.HasSuffix(fieldName(path), "_m") && diff > d.MaxDiffM {
+			d.MaxDiffM, d.Path = diff, path
+		}
+	case string:
+		if bv, ok := b.(string); ok && av != bv && strings.HasSuffix(fieldName(path), "time") {
+			mismatch("%s: %s, shadow %s", path, av, bv)
+		}
+	}
+}
+
+// fieldName strips array indexes from the end of path.
+func fieldName(path string) string {
+	return strings.TrimRight(path, "[]0123456789")
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
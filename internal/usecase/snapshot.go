@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// snapshotFormatVersion is bumped when the snapshot layout changes incompatibly.
+	snapshotFormatVersion = 1
+	// maxWarmupLocations bounds the recently requested locations kept for
+	// warmup, and the locations of a warm request.
+	maxWarmupLocations = 256
+)
+
+// WarmupLocation is a recently requested location to preload on a new revision.
+type WarmupLocation struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Snapshot captures the mutable server state so a new revision can start warm
+// and identical to the old one (blue-green deploys).
+type Snapshot struct {
+	FormatVersion    int              `json:"format_version"`
+	CodeVersion      string           `json:"code_version"`
+	CreatedAt        string           `json:"created_at"`
+	DatumOffsets     json.RawMessage  `json:"datum_offsets,omitempty"`
+	StationOverrides json.RawMessage  `json:"station_overrides,omitempty"`
+	WarmupLocations  []WarmupLocation `json:"warmup_locations"`
+}
+
+// RestoreResult reports what a snapshot restore applied.
+type RestoreResult struct {
+	StationTables   string `json:"station_tables"` // Digest after restore.
+	WarmupLocations int    `json:"warmup_locations"`
+}
+
+// recentLocations tracks the most recently requested locations (LRU order).
+type recentLocations struct {
+	mu    sync.Mutex
+	order []WarmupLocation // Oldest first.
+}
+
+// touch records a request at lat/lon, rounded to ~10 m.
+func (r *recentLocations) touch(lat, lon float64) {
+	loc := WarmupLocation{Lat: math.Round(lat*1e4) / 1e4, Lon: math.Round(lon*1e4) / 1e4}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, l := range r.order {
+		if l == loc {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	r.order = append(r.order, loc)
+	if len(r.order) > maxWarmupLocations {
+		r.order = r.order[len(r.order)-maxWarmupLocations:]
+	}
+}
+
+func (r *recentLocations) list() []WarmupLocation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]WarmupLocation, len(r.order))
+	copy(out, r.order)
+	return out
+}
+
+// ExportSnapshot returns the current mutable state.
+func (uc *PredictionUseCase) ExportSnapshot() *Snapshot {
+	datumRaw, overridesRaw := uc.tables.raw()
+	return &Snapshot{
+		FormatVersion:    snapshotFormatVersion,
+		CodeVersion:      uc.codeVersion,
+		CreatedAt:        uc.clock.Now().UTC().Format(time.RFC3339),
+		DatumOffsets:     datumRaw,
+		StationOverrides: overridesRaw,
+		WarmupLocations:  uc.recent.list(),
+	}
+}
+
+// RestoreSnapshot replaces the station tables with the snapshot's and preloads
+// its warmup locations in the background.
+func (uc *PredictionUseCase) RestoreSnapshot(s *Snapshot) (*RestoreResult, error) {
+	if s.FormatVersion != snapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d (expected %d)", s.FormatVersion, snapshotFormatVersion)
+	}
+	if err := uc.tables.replace(s.DatumOffsets, s.StationOverrides); err != nil {
+		return nil, err
+	}
+	for _, l := range s.WarmupLocations {
+		uc.recent.touch(l.Lat, l.Lon)
+	}
+	go uc.Warm(s.WarmupLocations)
+
+	return &RestoreResult{
+		StationTables:   uc.tables.digest(),
+		WarmupLocations: len(s.WarmupLocations),
+	}, nil
+}
+
+// WarmResult reports what Warm preloaded.
+type WarmResult struct {
+	Locations    int `json:"locations"`
+	Constituents int `json:"constituents"` // Locations whose constituents loaded.
+	// Bathymetry counts locations whose bathymetry loaded; 0 without a
+	// bathymetry store.
+	Bathymetry int      `json:"bathymetry"`
+	Errors     []string `json:"errors,omitempty"`
+	DurationMS int64    `json:"duration_ms"`
+}
+
+// MaxWarmLocations is the most locations a warm request may list.
+const MaxWarmLocations = maxWarmupLocations
+
+// Warm loads the constituents of the default gridded dataset and the
+// bathymetry of each location, in order, so that later requests hit warm
+// caches.
+func (uc *PredictionUseCase) Warm(locations []WarmupLocation) WarmResult {
+	start := time.Now()
+	loader := *uc.fesStore
+	if uc.gridSource(PredictionRequest{}) == sourceTPXO {
+		loader = uc.tpxoStore
+	}
+	result := WarmResult{Locations: len(locations)}
+	for _, l := range locations {
+		if _, err := loader.LoadForLocation(l.Lat, l.Lon); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("(%.4f, %.4f) constituents: %v", l.Lat, l.Lon, err))
+		} else {
+			result.Constituents++
+		}
+		if uc.bathymetryStore == nil {
+			continue
+		}
+		if metadata, err := uc.bathymetryStore.GetMetadata(l.Lat, l.Lon); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("(%.4f, %.4f) bathymetry: %v", l.Lat, l.Lon, err))
+		} else if metadata != nil && len(metadata.Sources) > 0 {
+			result.Bathymetry++
+		}
+	}
+	result.DurationMS = time.Since(start).Milliseconds()
+	return result
+}
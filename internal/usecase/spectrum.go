@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// spectrumMaxCPD is the highest frequency reported; tidal energy above
+	// the eighth-diurnal band is negligible.
+	spectrumMaxCPD = 8.0
+	// maxSpectrumLines bounds the frequency grid; the step is coarsened
+	// for long series.
+	maxSpectrumLines = 4000
+)
+
+// SpectrumResponse is the amplitude spectrum of a predicted series, for
+// checking that the synthesis holds the expected constituent peaks.
+type SpectrumResponse struct {
+	Source string `json:"source"`
+	// ResolutionCPD is 1/span: constituents closer in frequency are not
+	// separated by the spectrum.
+	ResolutionCPD float64           `json:"resolution_cpd"`
+	StepCPD       float64           `json:"step_cpd"`
+	Spectrum      []SpectrumBin     `json:"spectrum"`
+	Constituents  []ConstituentPeak `json:"constituents"`
+	Observations  int               `json:"observations,omitempty"`
+	Meta          map[string]string `json:"meta"`
+	Fingerprint   string            `json:"fingerprint"`
+	Degradation
+}
+
+// SpectrumBin is the spectrum at one frequency.
+type SpectrumBin struct {
+	FrequencyCPD float64 `json:"frequency_cpd"`
+	AmplitudeM   float64 `json:"amplitude_m"`
+	// ResidualAmplitudeM is the spectrum of observed minus predicted
+	// heights, when observations are supplied.
+	ResidualAmplitudeM *float64 `json:"residual_amplitude_m,omitempty"`
+}
+
+// ConstituentPeak compares a constituent of the prediction with the
+// spectrum at its frequency.
+type ConstituentPeak struct {
+	Name               string   `json:"name"`
+	FrequencyCPD       float64  `json:"frequency_cpd"`
+	AmplitudeM         float64  `json:"amplitude_m"`          // Constituent amplitude, before nodal factors.
+	SpectrumAmplitudeM float64  `json:"spectrum_amplitude_m"` // Amplitude found in the predicted series.
+	ResidualAmplitudeM *float64 `json:"residual_amplitude_m,omitempty"`
+	// Resolved is false when another constituent is closer than
+	// ResolutionCPD, so their peaks merge.
+	Resolved bool `json:"resolved"`
+}
+
+// Spectrum predicts the request window and returns its amplitude spectrum
+// up to 8 cycles per day (or the Nyquist frequency of the interval). With
+// observations, which must lie in the window, the spectrum of the residual
+// (observed minus predicted) is added; means are removed, so observations
+// need not share the prediction datum.
+func (uc *PredictionUseCase) Spectrum(req PredictionRequest, observations []domain.TideLevel) (*SpectrumResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if len(observations) > maxPredictionPoints {
+		return nil, fmt.Errorf("invalid request: too many observations (%d) - at most %d allowed", len(observations), maxPredictionPoints)
+	}
+	for _, o := range observations {
+		if o.Time.Before(req.Start) || o.Time.After(req.End) {
+			return nil, fmt.Errorf("invalid request: observation at %s outside start/end", o.Time.Format(time.RFC3339))
+		}
+	}
+	prepared, err := uc.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+	params := prepared.params
+
+	series := domain.GeneratePredictions(req.Start, req.End, req.Interval, params)
+	var residuals []domain.TideLevel
+	if len(observations) > 0 {
+		residuals = make([]domain.TideLevel, len(observations))
+		for i, o := range observations {
+			residuals[i] = domain.TideLevel{Time: o.Time, HeightM: o.HeightM - params.Height(o.Time)}
+		}
+	}
+
+	spanDays := req.End.Sub(req.Start).Hours() / 24
+	maxCPD := math.Min(spectrumMaxCPD, 0.5*24/req.Interval.Hours())
+	step := 1 / (2 * spanDays) // Twice the resolution, to draw the peaks.
+	if maxCPD/step > maxSpectrumLines {
+		step = maxCPD / maxSpectrumLines
+	}
+
+	response := &SpectrumResponse{
+		Source:        prepared.source,
+		ResolutionCPD: roundCPD(1 / spanDays),
+		StepCPD:       roundCPD(step),
+		Constituents:  []ConstituentPeak{},
+		Observations:  len(observations),
+	}
+	lines := domain.AmplitudeSpectrum(series, step, maxCPD)
+	var residualLines []domain.SpectrumLine
+	if residuals != nil {
+		residualLines = domain.AmplitudeSpectrum(residuals, step, maxCPD)
+	}
+	response.Spectrum = make([]SpectrumBin, len(lines))
+	for i, l := range lines {
+		response.Spectrum[i] = SpectrumBin{FrequencyCPD: roundCPD(l.FrequencyCPD), AmplitudeM: roundToDecimal(l.AmplitudeM)}
+		if residualLines != nil {
+			r := roundToDecimal(residualLines[i].AmplitudeM)
+			response.Spectrum[i].ResidualAmplitudeM = &r
+		}
+	}
+
+	freqs := make([]float64, len(params.Constituents))
+	for i, c := range params.Constituents {
+		freqs[i] = domain.SpeedToCPD(c.SpeedDegPerHr)
+	}
+	for i, c := range params.Constituents {
+		if freqs[i] > maxCPD {
+			continue
+		}
+		peak := ConstituentPeak{
+			Name:               c.Name,
+			FrequencyCPD:       roundCPD(freqs[i]),
+			AmplitudeM:         roundToDecimal(c.AmplitudeM),
+			SpectrumAmplitudeM: roundToDecimal(domain.AmplitudeAt(series, freqs[i])),
+			Resolved:           true,
+		}
+		if residuals != nil {
+			r := roundToDecimal(domain.AmplitudeAt(residuals, freqs[i]))
+			peak.ResidualAmplitudeM = &r
+		}
+		for k, f := range freqs {
+			if k != i && math.Abs(f-freqs[i]) < 1/spanDays {
+				peak.Resolved = false
+			}
+		}
+		response.Constituents = append(response.Constituents, peak)
+	}
+	sort.SliceStable(response.Constituents, func(i, j int) bool {
+		return response.Constituents[i].FrequencyCPD < response.Constituents[j].FrequencyCPD
+	})
+
+	provenance := newComputationProvenance(uc.codeVersion, uc.tables, prepared)
+	response.Meta = provenance.Meta()
+	prepared.addNowcastMeta(response.Meta)
+	prepared.addLandMotionMeta(response.Meta)
+	prepared.addEnsembleMeta(response.Meta)
+	prepared.addFallbackMeta(response.Meta)
+	response.Fingerprint = provenance.Fingerprint()
+	response.Degradation = prepared.degradation()
+	return response, nil
+}
+
+// roundCPD rounds a frequency to 1e-6 cycles per day.
+func roundCPD(f float64) float64 {
+	return math.Round(f*1e6) / 1e6
+}
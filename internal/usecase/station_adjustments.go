@@ -1,59 +1,113 @@
 package usecase
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"os"
+	"slices"
 	"sync"
+	"time"
 
-	"go.ngs.io/tides-api/internal/domain"
+	"go.ngs.io/tides-api/internal/schema"
+	"go.ngs.io/tides-api/pkg/domain"
 )
 
-// Datum offsets (nearest neighbor).
+// Default station table files, relative to the working directory.
+const (
+	DefaultDatumOffsetsPath     = "data/jma_datum_offsets.json"
+	DefaultStationOverridesPath = "data/jma_station_overrides.json"
+)
 
-type datumOffsetEntry struct{
-	Name    string  `json:"name"`
-	Lat     float64 `json:"lat"`
-	Lon     float64 `json:"lon"`
-	OffsetM float64 `json:"offset_m"`
+const (
+	// datumOffsetRadiusKm bounds the nearest datum offset applied to a location.
+	datumOffsetRadiusKm = 80
+	// defaultOverrideRadiusKm applies to override entries without radius_km.
+	defaultOverrideRadiusKm = 40
+)
+
+// validity bounds the period a table entry applies to, e.g. a gauge
+// datum that changed after an earthquake shifted the land. Either bound
+// may be absent; valid_to is exclusive.
+type validity struct {
+	ValidFrom *time.Time `json:"valid_from,omitempty"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
 }
 
-//nolint:gochecknoglobals // Intentional: sync.Once pattern for lazy loading.
-var (
-	datumOnce  sync.Once
-	datumTable []datumOffsetEntry
-)
+// activeAt reports whether the entry applies at t.
+func (v validity) activeAt(t time.Time) bool {
+	return (v.ValidFrom == nil || !t.Before(*v.ValidFrom)) && (v.ValidTo == nil || t.Before(*v.ValidTo))
+}
 
-func getAutoDatumOffset(lat, lon float64) (float64, bool) {
-	datumOnce.Do(func() {
-		path := os.Getenv("DATUM_OFFSETS_PATH")
-		if path == "" {
-			path = "data/jma_datum_offsets.json"
-		}
-		//nolint:gosec // G304: File path from env var or config path.
-		if b, err := os.ReadFile(path); err == nil {
-			var entries []datumOffsetEntry
-			if err := json.Unmarshal(b, &entries); err == nil {
-				datumTable = entries
-			}
-		}
-	})
-	if len(datumTable) == 0 {
-		return 0, false
+// bounded reports whether the entry applies for a limited period only.
+func (v validity) bounded() bool {
+	return v.ValidFrom != nil || v.ValidTo != nil
+}
+
+// periodKey distinguishes entries of one station valid in different
+// periods.
+func (v validity) periodKey() string {
+	var from, to string
+	if v.ValidFrom != nil {
+		from = v.ValidFrom.UTC().Format(time.RFC3339)
 	}
-	bestDist := math.MaxFloat64
-	bestOffset := 0.0
-	for _, entry := range datumTable {
-		d := haversineKm(lat, lon, entry.Lat, entry.Lon)
-		if d < bestDist {
-			bestDist = d
-			bestOffset = entry.OffsetM
+	if v.ValidTo != nil {
+		to = v.ValidTo.UTC().Format(time.RFC3339)
+	}
+	return from + "/" + to
+}
+
+// check reports an empty period.
+func (v validity) check() error {
+	if v.ValidFrom != nil && v.ValidTo != nil && !v.ValidTo.After(*v.ValidFrom) {
+		return fmt.Errorf("valid_to %s is not after valid_from %s", v.ValidTo.Format(time.RFC3339), v.ValidFrom.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// bounds appends the bounds of the period within (start, end].
+func (v validity) bounds(times []time.Time, start, end time.Time) []time.Time {
+	for _, b := range []*time.Time{v.ValidFrom, v.ValidTo} {
+		if b != nil && b.After(start) && !b.After(end) {
+			times = append(times, *b)
 		}
 	}
-	if bestDist <= 80 {
-		return bestOffset, true
+	return times
+}
+
+// periodic is implemented by table entries, through validity.
+type periodic interface {
+	activeAt(t time.Time) bool
+	bounded() bool
+	check() error
+}
+
+// checkPeriods reports the first entry with an empty period.
+func checkPeriods[E periodic](entries []E) error {
+	for i, e := range entries {
+		if err := e.check(); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
 	}
-	return 0, false
+	return nil
+}
+
+// Datum offsets (nearest neighbor).
+
+type datumOffsetEntry struct {
+	Name    string  `json:"name"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	OffsetM float64 `json:"offset_m"`
+	validity
+}
+
+// tableKey identifies a datum offset entry by name and period.
+func (e datumOffsetEntry) tableKey() string {
+	return e.Name + "@" + e.periodKey()
 }
 
 // Station constituent overrides.
@@ -72,72 +126,499 @@ type stationOverrideEntry struct {
 	RadiusKm     float64               `json:"radius_km"`
 	DatumOffset  *float64              `json:"datum_offset_m,omitempty"`
 	Constituents []overrideConstituent `json:"constituents"`
+	Source       string                `json:"source,omitempty"`
+	validity
 }
 
-//nolint:gochecknoglobals // Intentional: sync.Once pattern for lazy loading.
-var (
-	overridesOnce  sync.Once
-	overridesTable []stationOverrideEntry
-)
+// key identifies an override entry by station code, else by name.
+func (e stationOverrideEntry) key() string {
+	if e.Station != "" {
+		return e.Station
+	}
+	return e.Name
+}
+
+// tableKey identifies an override entry by key and period.
+func (e stationOverrideEntry) tableKey() string {
+	return e.key() + "@" + e.periodKey()
+}
 
-func loadOverrides() {
-	path := os.Getenv("STATION_OVERRIDES_PATH")
-	if path == "" {
-		path = "data/jma_station_overrides.json"
+// radius returns the radius the override applies within.
+func (e *stationOverrideEntry) radius() float64 {
+	if e.RadiusKm == 0 {
+		return defaultOverrideRadiusKm
 	}
+	return e.RadiusKm
+}
+
+// stationTables holds the datum offset and station override tables.
+// Tables are loaded from their JSON files on first use, by whichever
+// request comes first, and can be replaced at runtime (e.g., snapshot
+// restore); the raw JSON is kept for export.
+type stationTables struct {
+	datumPath     string
+	overridesPath string
+	once          sync.Once
+
+	mu           sync.RWMutex
+	datum        []datumOffsetEntry
+	datumRaw     []byte
+	datumErr     error // Why the datum offset file was not loaded.
+	overrides    []stationOverrideEntry
+	overridesRaw []byte
+	overridesErr error // Why the station override file was not loaded.
+}
+
+// newStationTables creates tables backed by the given JSON files.
+func newStationTables(datumPath, overridesPath string) *stationTables {
+	return &stationTables{datumPath: datumPath, overridesPath: overridesPath}
+}
+
+func (t *stationTables) load() {
+	t.once.Do(func() {
+		datum, datumErr := readTableFile(t.datumPath)
+		overrides, overridesErr := readTableFile(t.overridesPath)
+		// Missing files leave the corresponding table empty; unreadable or
+		// invalid ones too, but are reported in health.
+		_ = t.set(datum, overrides, false)
+		if datumErr != nil || overridesErr != nil {
+			t.mu.Lock()
+			if datumErr != nil {
+				fmt.Printf("Warning: ignoring datum offsets %s: %v\n", t.datumPath, datumErr)
+				t.datumErr = datumErr
+			}
+			if overridesErr != nil {
+				fmt.Printf("Warning: ignoring station overrides %s: %v\n", t.overridesPath, overridesErr)
+				t.overridesErr = overridesErr
+			}
+			t.mu.Unlock()
+		}
+	})
+}
+
+// readTableFile reads a table file; a missing file reads as empty.
+func readTableFile(path string) ([]byte, error) {
 	//nolint:gosec // G304: File path from env var or config path.
-	if b, err := os.ReadFile(path); err == nil {
-		var entries []stationOverrideEntry
-		if err := json.Unmarshal(b, &entries); err == nil {
-			overridesTable = entries
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return raw, err
+}
+
+// set validates, parses and installs the tables. When strict, an invalid
+// table is an error; otherwise it is reported and left empty.
+func (t *stationTables) set(datumRaw, overridesRaw []byte, strict bool) error {
+	var datum []datumOffsetEntry
+	var datumErr error
+	if len(datumRaw) > 0 {
+		err := decodeTable(schema.DatumOffsets, datumRaw, &datum)
+		if err == nil {
+			err = checkPeriods(datum)
+		}
+		if err != nil {
+			datumErr = fmt.Errorf("invalid datum offsets JSON: %w", err)
+			if strict {
+				return datumErr
+			}
+			fmt.Printf("Warning: ignoring datum offsets %s: %v\n", t.datumPath, err)
+			datum, datumRaw = nil, nil
+		}
+	}
+	var overrides []stationOverrideEntry
+	var overridesErr error
+	if len(overridesRaw) > 0 {
+		err := decodeTable(schema.StationOverrides, overridesRaw, &overrides)
+		if err == nil {
+			err = checkPeriods(overrides)
+		}
+		if err != nil {
+			overridesErr = fmt.Errorf("invalid station overrides JSON: %w", err)
+			if strict {
+				return overridesErr
+			}
+			fmt.Printf("Warning: ignoring station overrides %s: %v\n", t.overridesPath, err)
+			overrides, overridesRaw = nil, nil
+		}
+	}
+	for i := range overrides {
+		canonicalizeOverride(&overrides[i])
+	}
+
+	// Store compact JSON so digests do not depend on formatting.
+	datumRaw = compactJSON(datumRaw)
+	overridesRaw = compactJSON(overridesRaw)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.datum, t.datumRaw, t.datumErr = datum, datumRaw, datumErr
+	t.overrides, t.overridesRaw, t.overridesErr = overrides, overridesRaw, overridesErr
+	return nil
+}
+
+// decodeTable validates raw against the schema of kind, then decodes it.
+func decodeTable(kind string, raw []byte, v any) error {
+	if err := schema.Validate(kind, raw); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func compactJSON(raw []byte) []byte {
+	if len(raw) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+// replace installs new tables from raw JSON, rejecting invalid input.
+func (t *stationTables) replace(datumRaw, overridesRaw []byte) error {
+	t.load()
+	return t.set(datumRaw, overridesRaw, true)
+}
+
+// raw returns the JSON of the current tables (nil when absent).
+func (t *stationTables) raw() (datumRaw, overridesRaw []byte) {
+	t.load()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.datumRaw, t.overridesRaw
+}
+
+// TableHealth is the load status of a data table.
+type TableHealth struct {
+	Table   string `json:"table"`
+	Path    string `json:"path"`
+	Healthy bool   `json:"healthy"`
+	Entries int    `json:"entries"`
+	Error   string `json:"error,omitempty"`
+}
+
+// health reports the load status of the datum offset and override tables.
+func (t *stationTables) health() []TableHealth {
+	t.load()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return []TableHealth{
+		tableHealth(schema.DatumOffsets, t.datumPath, len(t.datum), t.datumErr),
+		tableHealth(schema.StationOverrides, t.overridesPath, len(t.overrides), t.overridesErr),
+	}
+}
+
+func tableHealth(table, path string, entries int, err error) TableHealth {
+	h := TableHealth{Table: table, Path: path, Healthy: err == nil, Entries: entries}
+	if err != nil {
+		h.Error = err.Error()
+	}
+	return h
+}
+
+// digest identifies the current datum offset and override tables.
+func (t *stationTables) digest() string {
+	return tablesDigest(t.raw())
+}
+
+func tablesDigest(datumRaw, overridesRaw []byte) string {
+	datum, overrides := "none", "none"
+	if len(datumRaw) > 0 {
+		datum = shortDigest(datumRaw)
+	}
+	if len(overridesRaw) > 0 {
+		overrides = shortDigest(overridesRaw)
+	}
+	return "datum:" + datum + ";overrides:" + overrides
+}
+
+// datumEntry returns the datum offset entry named name.
+func (t *stationTables) datumEntry(name string) (datumOffsetEntry, bool) {
+	t.load()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, entry := range t.datum {
+		if entry.Name == name {
+			return entry, true
 		}
 	}
+	return datumOffsetEntry{}, false
+}
+
+// entries returns copies of the datum offset and override tables.
+func (t *stationTables) entries() ([]datumOffsetEntry, []stationOverrideEntry) {
+	t.load()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]datumOffsetEntry(nil), t.datum...), append([]stationOverrideEntry(nil), t.overrides...)
 }
 
-func getStationOverride(lat, lon float64) (*stationOverrideEntry, bool) {
-	overridesOnce.Do(loadOverrides)
-	if len(overridesTable) == 0 {
-		return nil, false
+// setDatumOffset adds or replaces the datum offset entry of the same name
+// and period and writes the table back to its file.
+func (t *stationTables) setDatumOffset(entry datumOffsetEntry) error {
+	return t.upsert("", []datumOffsetEntry{entry}, nil)
+}
+
+// errStaleTables reports tables changed since an update was prepared.
+var errStaleTables = errors.New("station tables changed since the update was prepared")
+
+// upsert adds or replaces datum offset entries by name and override entries
+// by station, each of the same period, and writes the changed tables back to their files. When base
+// is set, the update is refused unless the tables still have that digest.
+// A table whose file failed to load is not written, so that its entries
+// are not lost.
+func (t *stationTables) upsert(base string, datumEntries []datumOffsetEntry, overrideEntries []stationOverrideEntry) error {
+	t.load()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if base != "" && tablesDigest(t.datumRaw, t.overridesRaw) != base {
+		return errStaleTables
+	}
+	if len(datumEntries) > 0 && t.datumErr != nil {
+		return fmt.Errorf("datum offsets %s failed to load: %w", t.datumPath, t.datumErr)
+	}
+	if len(overrideEntries) > 0 && t.overridesErr != nil {
+		return fmt.Errorf("station overrides %s failed to load: %w", t.overridesPath, t.overridesErr)
+	}
+
+	datum, overrides := t.datum, t.overrides
+	datumRaw, overridesRaw := t.datumRaw, t.overridesRaw
+	if len(datumEntries) > 0 {
+		datum = upsertEntries(t.datum, datumEntries, datumOffsetEntry.tableKey)
+		raw, err := writeTable(schema.DatumOffsets, t.datumPath, datum)
+		if err != nil {
+			return fmt.Errorf("datum offsets: %w", err)
+		}
+		datumRaw = raw
 	}
+	if len(overrideEntries) > 0 {
+		overrides = upsertEntries(t.overrides, overrideEntries, stationOverrideEntry.tableKey)
+		raw, err := writeTable(schema.StationOverrides, t.overridesPath, overrides)
+		if err != nil {
+			return fmt.Errorf("station overrides: %w", err)
+		}
+		overridesRaw = raw
+	}
+	t.datum, t.datumRaw = datum, datumRaw
+	t.overrides, t.overridesRaw = overrides, overridesRaw
+	return nil
+}
+
+// upsertEntries returns entries with updates replacing the entries of the
+// same key in place; new keys are appended.
+func upsertEntries[E any](entries, updates []E, key func(E) string) []E {
+	index := make(map[string]int, len(updates))
+	for i, u := range updates {
+		index[key(u)] = i
+	}
+	out := make([]E, 0, len(entries)+len(updates))
+	for _, e := range entries {
+		if i, ok := index[key(e)]; ok {
+			e = updates[i]
+			delete(index, key(e))
+		}
+		out = append(out, e)
+	}
+	for _, u := range updates {
+		if _, ok := index[key(u)]; ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// writeTable validates v against the schema of kind and writes it to path,
+// returning its compact JSON.
+func writeTable(kind, path string, v any) ([]byte, error) {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode: %w", err)
+	}
+	if err := schema.Validate(kind, raw); err != nil {
+		return nil, fmt.Errorf("invalid table: %w", err)
+	}
+	//nolint:gosec // G306: Data file shared with the offline tools.
+	if err := os.WriteFile(path, append(raw, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return compactJSON(raw), nil
+}
+
+// autoDatumOffset returns the offset of the nearest datum offset entry
+// within datumOffsetRadiusKm that applies at t.
+func (t *stationTables) autoDatumOffset(lat, lon float64, at time.Time) (float64, bool) {
+	t.load()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	entry, ok := nearestWithin(t.datum, lat, lon, at, func(e *datumOffsetEntry) (float64, float64, float64) {
+		return e.Lat, e.Lon, datumOffsetRadiusKm
+	})
+	if !ok {
+		return 0, false
+	}
+	return entry.OffsetM, true
+}
+
+// nearestWithin returns the entry nearest to (lat, lon) among those within
+// their radius, given by locate with their position, that apply at t. At
+// the same distance, an entry for a limited period takes precedence over
+// an open-ended one, so that a temporary entry for a station need not end
+// its permanent one.
+func nearestWithin[E periodic](entries []E, lat, lon float64, t time.Time, locate func(*E) (lat, lon, radiusKm float64)) (*E, bool) {
 	bestDist := math.MaxFloat64
-	var best *stationOverrideEntry
-	for i := range overridesTable {
-		entry := &overridesTable[i]
-		radius := entry.RadiusKm
-		if radius == 0 {
-			radius = 40
-		}
-		d := haversineKm(lat, lon, entry.Lat, entry.Lon)
-		if d <= radius && d < bestDist {
+	var best *E
+	for i := range entries {
+		e := &entries[i]
+		if !(*e).activeAt(t) {
+			continue
+		}
+		entryLat, entryLon, radius := locate(e)
+		d := haversineKm(lat, lon, entryLat, entryLon)
+		if d <= radius && (d < bestDist || best != nil && d == bestDist && (*e).bounded() && !(*best).bounded()) {
 			bestDist = d
-			best = entry
+			best = e
 		}
 	}
-	if best == nil {
-		return nil, false
+	return best, best != nil
+}
+
+// changes returns the times in (start, end] at which the datum offset or
+// override entries near (lat, lon) start or stop applying, in order.
+func (t *stationTables) changes(lat, lon float64, start, end time.Time) []time.Time {
+	t.load()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var times []time.Time
+	for _, e := range t.datum {
+		if e.bounded() && haversineKm(lat, lon, e.Lat, e.Lon) <= datumOffsetRadiusKm {
+			times = e.bounds(times, start, end)
+		}
 	}
-	return best, true
+	for i := range t.overrides {
+		if e := &t.overrides[i]; e.bounded() && haversineKm(lat, lon, e.Lat, e.Lon) <= e.radius() {
+			times = e.bounds(times, start, end)
+		}
+	}
+	slices.SortFunc(times, time.Time.Compare)
+	return slices.CompactFunc(times, time.Time.Equal)
 }
 
-func applyStationOverride(lat, lon float64, constituents []domain.ConstituentParam, msl *float64) []domain.ConstituentParam {
-	override, ok := getStationOverride(lat, lon)
-	if !ok {
-		return constituents
+// canonicalizeOverride resolves constituent aliases, reporting unsupported names.
+func canonicalizeOverride(entry *stationOverrideEntry) {
+	kept := entry.Constituents[:0]
+	for _, c := range entry.Constituents {
+		name, ok := domain.CanonicalConstituentName(c.Name)
+		if !ok {
+			fmt.Printf("Warning: station override %s has unknown constituent %s\n", entry.Name, c.Name)
+			continue
+		}
+		c.Name = name
+		kept = append(kept, c)
 	}
+	entry.Constituents = kept
+}
 
-	adjusted := make([]domain.ConstituentParam, len(constituents))
-	copy(adjusted, constituents)
+// landCheck reports whether the path between two locations crosses land.
+type landCheck func(lat1, lon1, lat2, lon2 float64) bool
+
+// stationOverride returns the nearest override entry within its radius
+// that applies at at and whose station is not across land from the
+// location, per crossesLand (nil skips the check): an entry on the
+// opposite coast of a peninsula yields to a farther one on the same water,
+// if any. At the same distance, entries for a limited period come first.
+func (t *stationTables) stationOverride(lat, lon float64, at time.Time, crossesLand landCheck) (*stationOverrideEntry, bool) {
+	t.load()
+	t.mu.RLock()
+	overrides := t.overrides
+	t.mu.RUnlock()
 
+	type candidate struct {
+		entry      *stationOverrideEntry
+		distanceKm float64
+	}
+	var candidates []candidate
+	for i := range overrides {
+		e := &overrides[i]
+		if !e.activeAt(at) {
+			continue
+		}
+		if d := haversineKm(lat, lon, e.Lat, e.Lon); d <= e.radius() {
+			candidates = append(candidates, candidate{e, d})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if c := cmp.Compare(a.distanceKm, b.distanceKm); c != 0 {
+			return c
+		}
+		switch {
+		case a.entry.bounded() && !b.entry.bounded():
+			return -1
+		case b.entry.bounded() && !a.entry.bounded():
+			return 1
+		}
+		return 0
+	})
+	// Paths are checked nearest first, as each check reads elevations.
+	for _, c := range candidates {
+		if crossesLand == nil || !crossesLand(lat, lon, c.entry.Lat, c.entry.Lon) {
+			return c.entry, true
+		}
+	}
+	return nil, false
+}
+
+func (t *stationTables) applyStationOverride(lat, lon float64, at time.Time, constituents []domain.ConstituentParam, msl *float64, crossesLand landCheck) []domain.ConstituentParam {
+	override, ok := t.stationOverride(lat, lon, at, crossesLand)
+	if !ok {
+		return constituents
+	}
 	if override.DatumOffset != nil && msl != nil {
 		*msl += *override.DatumOffset
 	}
+	return override.apply(constituents)
+}
+
+// tableCorrection applies the station tables to the mean sea level and
+// constituents of a lat/lon prediction.
+type tableCorrection struct {
+	tables       *stationTables
+	lat, lon     float64
+	msl          float64                   // Before table datum offsets.
+	constituents []domain.ConstituentParam // Before station overrides.
+	autoOffset   bool                      // Add the nearest datum offset (none was requested).
+	override     bool                      // Apply the nearest station override.
+	crossesLand  landCheck
+}
+
+// at returns the mean sea level and constituents with the entries that
+// apply at t.
+func (c tableCorrection) at(t time.Time) (float64, []domain.ConstituentParam) {
+	msl, constituents := c.msl, c.constituents
+	if c.autoOffset {
+		// Nearest known offset (e.g., JMA DL/TP).
+		if off, ok := c.tables.autoDatumOffset(c.lat, c.lon, t); ok {
+			msl += off
+		}
+	}
+	if c.override {
+		constituents = c.tables.applyStationOverride(c.lat, c.lon, t, constituents, &msl, c.crossesLand)
+	}
+	return msl, constituents
+}
+
+// apply returns constituents with those of the entry replacing or added to
+// them.
+func (e *stationOverrideEntry) apply(constituents []domain.ConstituentParam) []domain.ConstituentParam {
+	adjusted := make([]domain.ConstituentParam, len(constituents))
+	copy(adjusted, constituents)
 
 	index := make(map[string]int, len(adjusted))
 	for i, c := range adjusted {
 		index[c.Name] = i
 	}
 
-	for _, ov := range override.Constituents {
+	for _, ov := range e.Constituents {
 		if idx, ok := index[ov.Name]; ok {
 			adjusted[idx].AmplitudeM = ov.AmplitudeM
 			adjusted[idx].PhaseDeg = wrapPhase(ov.PhaseDeg)
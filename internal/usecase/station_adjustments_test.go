@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testDatumOffsets     = `[{"name": "TK", "lat": 35.65, "lon": 139.77, "offset_m": 1.2}]`
+	testStationOverrides = `[{"name": "TK", "station": "TK", "lat": 35.65, "lon": 139.77, "radius_km": 40,
+  "constituents": [{"name": "M2", "amplitude_m": 0.5, "phase_deg": 150}]}]`
+)
+
+func writeTables(t *testing.T, datum, overrides string) *stationTables {
+	t.Helper()
+	dir := t.TempDir()
+	datumPath := filepath.Join(dir, "datum.json")
+	overridesPath := filepath.Join(dir, "overrides.json")
+	for path, content := range map[string]string{datumPath: datum, overridesPath: overrides} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return newStationTables(datumPath, overridesPath)
+}
+
+// Run with -race: the first lookups of concurrent requests load the tables
+// while others replace and update them.
+func TestStationTables_ConcurrentLoad(t *testing.T) {
+	tables := writeTables(t, testDatumOffsets, testStationOverrides)
+
+	var wg sync.WaitGroup
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch i % 4 {
+			case 0:
+				if off, ok := tables.autoDatumOffset(35.6, 139.8, time.Now()); !ok || off != 1.2 {
+					t.Errorf("autoDatumOffset = %v, %v; want 1.2", off, ok)
+				}
+			case 1:
+				if _, ok := tables.stationOverride(35.6, 139.8, time.Now(), nil); !ok {
+					t.Error("no station override near TK")
+				}
+			case 2:
+				if err := tables.replace([]byte(testDatumOffsets), []byte(testStationOverrides)); err != nil {
+					t.Errorf("replace: %v", err)
+				}
+			case 3:
+				_ = tables.digest()
+				_ = tables.health()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := tables.setDatumOffset(datumOffsetEntry{Name: "OS", Lat: 34.65, Lon: 135.43, OffsetM: 0.9}); err != nil {
+		t.Fatalf("setDatumOffset: %v", err)
+	}
+	if off, ok := tables.autoDatumOffset(34.6, 135.4, time.Now()); !ok || off != 0.9 {
+		t.Errorf("autoDatumOffset after update = %v, %v; want 0.9", off, ok)
+	}
+}
+
+func TestStationTables_InvalidFileReportedInHealth(t *testing.T) {
+	tables := writeTables(t, `[{"name": "TK",`, testStationOverrides)
+
+	if _, ok := tables.autoDatumOffset(35.6, 139.8, time.Now()); ok {
+		t.Error("offset applied from an invalid datum offset file")
+	}
+	if _, ok := tables.stationOverride(35.6, 139.8, time.Now(), nil); !ok {
+		t.Error("valid override table not loaded")
+	}
+
+	health := tables.health()
+	if len(health) != 2 {
+		t.Fatalf("health = %+v, want 2 tables", health)
+	}
+	if h := health[0]; h.Healthy || h.Error == "" || h.Entries != 0 {
+		t.Errorf("datum offsets health = %+v, want unhealthy with an error", h)
+	}
+	if h := health[1]; !h.Healthy || h.Entries != 1 {
+		t.Errorf("station overrides health = %+v, want healthy with 1 entry", h)
+	}
+
+	// Writing back would drop the entries of the unreadable file.
+	if err := tables.setDatumOffset(datumOffsetEntry{Name: "OS", Lat: 34.65, Lon: 135.43, OffsetM: 0.9}); err == nil {
+		t.Error("updated a table that failed to load")
+	}
+
+	// A valid replacement clears the error.
+	if err := tables.replace([]byte(testDatumOffsets), []byte(testStationOverrides)); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if h := tables.health()[0]; !h.Healthy || h.Entries != 1 {
+		t.Errorf("datum offsets health after replace = %+v, want healthy", h)
+	}
+}
+
+func TestStationTables_MissingFilesAreHealthy(t *testing.T) {
+	dir := t.TempDir()
+	tables := newStationTables(filepath.Join(dir, "none.json"), filepath.Join(dir, "none2.json"))
+	for _, h := range tables.health() {
+		if !h.Healthy || h.Entries != 0 {
+			t.Errorf("health = %+v, want healthy and empty", h)
+		}
+	}
+}
+
+func TestStationTables_OverrideNotAppliedAcrossLand(t *testing.T) {
+	// TK is nearer, across land; YK is farther, on the same water.
+	tables := writeTables(t, testDatumOffsets, `[
+  {"name": "TK", "lat": 35.65, "lon": 139.77, "radius_km": 40, "constituents": []},
+  {"name": "YK", "lat": 35.45, "lon": 139.65, "radius_km": 40, "constituents": []}]`)
+	acrossTK := func(_, _, lat2, lon2 float64) bool { return lat2 == 35.65 && lon2 == 139.77 }
+	acrossAll := func(_, _, _, _ float64) bool { return true }
+
+	if e, ok := tables.stationOverride(35.6, 139.8, time.Now(), nil); !ok || e.Name != "TK" {
+		t.Errorf("without land check: %+v, %v; want TK", e, ok)
+	}
+	if e, ok := tables.stationOverride(35.6, 139.8, time.Now(), acrossTK); !ok || e.Name != "YK" {
+		t.Errorf("TK across land: %+v, %v; want YK", e, ok)
+	}
+	if e, ok := tables.stationOverride(35.6, 139.8, time.Now(), acrossAll); ok {
+		t.Errorf("all across land: got %+v, want none", e)
+	}
+}
+
+func TestStationTables_Validity(t *testing.T) {
+	// After the quake, a temporary TK entry at the same gauge takes
+	// precedence over the permanent one until it expires.
+	tables := writeTables(t, `[
+  {"name": "TK", "lat": 35.65, "lon": 139.77, "offset_m": 1.2},
+  {"name": "TK", "lat": 35.65, "lon": 139.77, "offset_m": 1.5, "valid_from": "2024-01-01T07:10:00Z", "valid_to": "2024-07-01T00:00:00Z"}]`,
+		testStationOverrides)
+	quake := time.Date(2024, 1, 1, 7, 10, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		at   time.Time
+		want float64
+	}{
+		{quake.Add(-time.Minute), 1.2},
+		{quake, 1.5},
+		{time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), 1.2},
+	} {
+		if off, ok := tables.autoDatumOffset(35.6, 139.8, tc.at); !ok || off != tc.want {
+			t.Errorf("autoDatumOffset at %s = %v, %v; want %v", tc.at.Format(time.RFC3339), off, ok, tc.want)
+		}
+	}
+
+	changes := tables.changes(35.6, 139.8, quake.AddDate(0, 0, -1), quake.AddDate(1, 0, 0))
+	if len(changes) != 2 || !changes[0].Equal(quake) {
+		t.Errorf("changes = %v, want the quake and the expiry", changes)
+	}
+	if changes := tables.changes(35.6, 139.8, quake, quake.AddDate(0, 0, 1)); len(changes) != 0 {
+		t.Errorf("changes from the quake = %v, want none", changes)
+	}
+
+	// Updates replace the entry of the same name and period only.
+	if err := tables.setDatumOffset(datumOffsetEntry{Name: "TK", Lat: 35.65, Lon: 139.77, OffsetM: 1.1}); err != nil {
+		t.Fatal(err)
+	}
+	if datum, _ := tables.entries(); len(datum) != 2 || datum[0].OffsetM != 1.1 || datum[1].OffsetM != 1.5 {
+		t.Errorf("entries after update = %+v", datum)
+	}
+
+	invalid := `[{"name": "TK", "lat": 35.65, "lon": 139.77, "offset_m": 1.5, "valid_from": "2024-07-01T00:00:00Z", "valid_to": "2024-01-01T00:00:00Z"}]`
+	if err := tables.replace([]byte(invalid), nil); err == nil {
+		t.Error("replace with an empty period: expected an error")
+	}
+}
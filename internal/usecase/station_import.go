@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+	"go.ngs.io/tides-api/internal/adapter/xlsx"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// ImportLayout maps workbook columns to constituent fields.
+type ImportLayout = xlsx.Layout
+
+// DefaultImportLayout returns the layout of sheets shaped like station CSVs.
+func DefaultImportLayout() ImportLayout {
+	return xlsx.DefaultLayout()
+}
+
+// StationImportRequest is a workbook of harmonic constants to import as a
+// station. Without Commit the import is only previewed.
+type StationImportRequest struct {
+	StationID string
+	Workbook  []byte
+	Layout    ImportLayout
+	Station   domain.StationMetadata
+	Commit    bool
+}
+
+// StationImportResult is the preview of an import and whether it was saved.
+type StationImportResult struct {
+	StationID string `json:"station_id"`
+	*xlsx.Preview
+	Saved bool `json:"saved"`
+}
+
+// ImportStationWorkbook maps a workbook sheet to constituents and, when the
+// request commits and the preview is valid, saves it as a station file.
+func (uc *PredictionUseCase) ImportStationWorkbook(req StationImportRequest) (*StationImportResult, error) {
+	sheet, err := xlsx.ReadSheet(bytes.NewReader(req.Workbook), int64(len(req.Workbook)), req.Layout.Sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workbook: %w", err)
+	}
+	result := &StationImportResult{
+		StationID: req.StationID,
+		Preview:   xlsx.Import(sheet, req.Layout),
+	}
+	if !req.Commit || !result.Valid {
+		return result, nil
+	}
+
+	writer, ok := (*uc.csvStore).(store.StationWriter)
+	if !ok {
+		return nil, errors.New("station store does not support imports")
+	}
+	station := req.Station
+	if err := writer.SaveStation(req.StationID, &station, result.Constituents); err != nil {
+		return nil, fmt.Errorf("failed to save station %s: %w", req.StationID, err)
+	}
+	result.Saved = true
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.ngs.io/tides-api/internal/adapter/store"
+)
+
+// Station kinds.
+const (
+	// StationKindCSV stations are predicted by station_id.
+	StationKindCSV = "csv"
+	// StationKindOverride stations replace grid constituents of lat/lon
+	// predictions within their radius.
+	StationKindOverride = "override"
+)
+
+// StationInfo describes a station file or a station override.
+type StationInfo struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name,omitempty"`
+	Lat              *float64   `json:"lat,omitempty"`
+	Lon              *float64   `json:"lon,omitempty"`
+	RadiusKm         *float64   `json:"radius_km,omitempty"` // Overrides only.
+	Source           string     `json:"source"`              // StationKindCSV or StationKindOverride.
+	Provenance       string     `json:"provenance,omitempty"`
+	ValidFrom        *time.Time `json:"valid_from,omitempty"` // Overrides for a limited period only.
+	ValidTo          *time.Time `json:"valid_to,omitempty"`
+	ConstituentCount int        `json:"constituent_count"`
+	Reference        string     `json:"reference,omitempty"`   // Reference station of a secondary port.
+	DistanceKm       *float64   `json:"distance_km,omitempty"` // From the queried location.
+}
+
+// ListStations returns the station files, in ID order, followed by the
+// station overrides, in table order. With a location, each station with a
+// position gets its distance and the list is sorted nearest first;
+// stations without a position come last.
+func (uc *PredictionUseCase) ListStations(lat, lon *float64) ([]StationInfo, error) {
+	var stations []StationInfo
+	if lister, ok := (*uc.csvStore).(store.StationLister); ok {
+		ids, err := lister.ListStations()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list stations: %w", err)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			info, err := uc.stationFileInfo(id)
+			if err != nil {
+				return nil, err
+			}
+			stations = append(stations, info)
+		}
+	}
+	_, overrides := uc.tables.entries()
+	for _, entry := range overrides {
+		radius := entry.radius()
+		stations = append(stations, StationInfo{
+			ID:               entry.key(),
+			Name:             entry.Name,
+			Lat:              &entry.Lat,
+			Lon:              &entry.Lon,
+			RadiusKm:         &radius,
+			Source:           StationKindOverride,
+			Provenance:       entry.Source,
+			ValidFrom:        entry.ValidFrom,
+			ValidTo:          entry.ValidTo,
+			ConstituentCount: len(entry.Constituents),
+		})
+	}
+
+	if lat == nil || lon == nil {
+		return stations, nil
+	}
+	for i := range stations {
+		if s := &stations[i]; s.Lat != nil && s.Lon != nil {
+			d := haversineKm(*lat, *lon, *s.Lat, *s.Lon)
+			s.DistanceKm = &d
+		}
+	}
+	slices.SortStableFunc(stations, func(a, b StationInfo) int {
+		switch {
+		case a.DistanceKm == nil && b.DistanceKm == nil:
+			return 0
+		case a.DistanceKm == nil:
+			return 1
+		case b.DistanceKm == nil:
+			return -1
+		}
+		return cmp.Compare(*a.DistanceKm, *b.DistanceKm)
+	})
+	return stations, nil
+}
+
+// stationFileInfo describes the station file of id. Secondary ports have
+// no constituents; they name their reference station instead.
+func (uc *PredictionUseCase) stationFileInfo(id string) (StationInfo, error) {
+	info := StationInfo{ID: id, Source: StationKindCSV}
+	meta, err := uc.loadStationMetadata(id)
+	if err != nil {
+		return info, err
+	}
+	if meta != nil {
+		info.Name, info.Lat, info.Lon = meta.Name, meta.Lat, meta.Lon
+		if meta.Secondary != nil {
+			info.Reference = meta.Secondary.Reference
+			return info, nil
+		}
+	}
+	constituents, err := (*uc.csvStore).LoadForStation(id)
+	if err != nil {
+		return info, fmt.Errorf("failed to load constituents for station %s: %w", id, err)
+	}
+	info.ConstituentCount = len(constituents)
+	return info, nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"slices"
+	"testing"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// stationFiles is a station store of two stations without metadata.
+type stationFiles struct{}
+
+func (stationFiles) LoadForStation(string) ([]domain.ConstituentParam, error) {
+	return []domain.ConstituentParam{{Name: "M2"}, {Name: "S2"}}, nil
+}
+
+func (stationFiles) LoadForLocation(float64, float64) ([]domain.ConstituentParam, error) {
+	return nil, nil
+}
+
+func (stationFiles) ListStations() ([]string, error) {
+	return []string{"tokyo", "osaka"}, nil
+}
+
+func TestListStations(t *testing.T) {
+	uc := NewPredictionUseCase(stationFiles{}, nil, nil)
+	uc.tables = writeTables(t, testDatumOffsets, `[
+  {"name": "OS", "lat": 34.65, "lon": 135.43, "constituents": [{"name": "M2", "amplitude_m": 0.3, "phase_deg": 200}]},
+  {"name": "TK", "station": "TK", "lat": 35.65, "lon": 139.77, "radius_km": 20, "source": "jma-harmonics",
+   "constituents": [{"name": "M2", "amplitude_m": 0.5, "phase_deg": 150}]}]`)
+
+	stations, err := uc.ListStations(nil, nil)
+	if err != nil {
+		t.Fatalf("ListStations: %v", err)
+	}
+	var ids []string
+	for _, s := range stations {
+		ids = append(ids, s.ID)
+	}
+	if want := []string{"osaka", "tokyo", "OS", "TK"}; !slices.Equal(ids, want) {
+		t.Errorf("IDs = %v, want %v", ids, want)
+	}
+	if s := stations[0]; s.Source != StationKindCSV || s.ConstituentCount != 2 || s.DistanceKm != nil {
+		t.Errorf("station file: %+v", s)
+	}
+	if s := stations[2]; s.Source != StationKindOverride || *s.RadiusKm != defaultOverrideRadiusKm || s.ConstituentCount != 1 {
+		t.Errorf("override without radius: %+v", s)
+	}
+
+	lat, lon := 35.6, 139.8
+	stations, err = uc.ListStations(&lat, &lon)
+	if err != nil {
+		t.Fatalf("ListStations near TK: %v", err)
+	}
+	if s := stations[0]; s.ID != "TK" || s.DistanceKm == nil || *s.DistanceKm > 10 {
+		t.Errorf("nearest = %+v, want TK", s)
+	}
+	if s := stations[len(stations)-1]; s.DistanceKm != nil {
+		t.Errorf("last = %+v, want a station without position", s)
+	}
+}
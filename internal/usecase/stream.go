@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// heightStreamRefresh is how long a HeightStream predicts from the
+// constituents and corrections it loaded before loading them again, so
+// nowcast residuals and sea level trends stay current.
+const heightStreamRefresh = time.Hour
+
+// HeightStream predicts the height of one location at successive instants
+// (e.g., for a live feed) without loading its constituents each time.
+// It is not safe for concurrent use.
+type HeightStream struct {
+	uc       *PredictionUseCase
+	req      PredictionRequest
+	loc      *time.Location
+	prepared *preparedPrediction
+	loadedAt time.Time
+}
+
+// NewHeightStream validates req and loads its constituents. The request's
+// Start, End and Interval are ignored.
+func (uc *PredictionUseCase) NewHeightStream(req PredictionRequest) (*HeightStream, error) {
+	s := &HeightStream{uc: uc, req: req}
+	s.loc, _ = outputZone(req.Timezone)
+	if err := s.load(uc.Clock().Now()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// load prepares the prediction of the coming refresh period.
+func (s *HeightStream) load(now time.Time) error {
+	req := s.req
+	req.Start, req.End = now, now.Add(heightStreamRefresh)
+	if err := req.validateTarget(); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	prepared, err := s.uc.prepare(req)
+	if err != nil {
+		return err
+	}
+	s.prepared, s.loadedAt = prepared, now
+	return nil
+}
+
+// At returns the predicted height at t, loading the constituents again
+// once they are older than the refresh period.
+func (s *HeightStream) At(t time.Time) (PredictionPoint, error) {
+	if t.Sub(s.loadedAt) >= heightStreamRefresh {
+		if err := s.load(t); err != nil {
+			return PredictionPoint{}, err
+		}
+	}
+	level := domain.TideLevel{Time: t, HeightM: s.prepared.params.Height(t)}
+	return s.prepared.point(level, s.loc), nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Timing is the server-side time spent on the stages of a prediction.
+type Timing struct {
+	Load        time.Duration // Constituents, ensemble members and location metadata.
+	Corrections time.Duration // Datum offsets, station overrides and nowcast.
+	Synthesis   time.Duration // Heights and extrema.
+}
+
+// Total returns the time spent on all stages.
+func (t Timing) Total() time.Duration {
+	return t.Load + t.Corrections + t.Synthesis
+}
+
+// addMeta records the stage timings in response metadata, in milliseconds.
+func (t Timing) addMeta(meta map[string]string) {
+	ms := func(d time.Duration) string {
+		return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+	}
+	meta["timing_constituent_load_ms"] = ms(t.Load)
+	meta["timing_corrections_ms"] = ms(t.Corrections)
+	meta["timing_synthesis_ms"] = ms(t.Synthesis)
+	meta["timing_total_ms"] = ms(t.Total())
+}
+
+// echo returns the request parameters after defaults and normalization, as
+// an unescaped query string sorted by key, for debugging.
+func (r PredictionRequest) echo(source string) string {
+	v := url.Values{}
+	switch {
+	case r.StationID != nil:
+		v.Set("station_id", *r.StationID)
+	case r.Lat != nil && r.Lon != nil:
+		v.Set("lat", strconv.FormatFloat(*r.Lat, 'f', -1, 64))
+		v.Set("lon", strconv.FormatFloat(*r.Lon, 'f', -1, 64))
+	default:
+		v.Set("constituents", strconv.Itoa(len(r.Constituents)))
+	}
+	v.Set("start", r.Start.UTC().Format(time.RFC3339))
+	v.Set("end", r.End.UTC().Format(time.RFC3339))
+	if r.Interval > 0 {
+		v.Set("interval", FormatDuration(r.Interval))
+	}
+	v.Set("source", source)
+	datum := r.Datum
+	if datum == "" {
+		datum = "MSL"
+	}
+	v.Set("datum", datum)
+	if r.DatumOffsetM != nil {
+		v.Set("datum_offset_m", strconv.FormatFloat(*r.DatumOffsetM, 'f', -1, 64))
+	}
+	_, tz := outputZone(r.Timezone)
+	v.Set("timezone", tz)
+	phase := "fes_greenwich"
+	if r.PhaseConvention == "vu" || r.PhaseConvention == "VU" {
+		phase = "vu"
+	}
+	v.Set("phase_convention", phase)
+	if r.Language != "" {
+		v.Set("language", r.Language)
+	}
+	if r.IncludeVLM {
+		v.Set("include_vlm", "true")
+	}
+	if r.SLRM != nil {
+		v.Set("slr_m", strconv.FormatFloat(*r.SLRM, 'f', -1, 64))
+	}
+	if r.SLRScenario != "" {
+		v.Set("slr_scenario", r.SLRScenario)
+	}
+	if r.Nowcast {
+		v.Set("nowcast", "true")
+	}
+	if r.Ensemble {
+		v.Set("ensemble", "true")
+	}
+	params := make([]string, 0, len(v))
+	for _, k := range slices.Sorted(maps.Keys(v)) {
+		params = append(params, k+"="+v.Get(k))
+	}
+	return strings.Join(params, "&")
+}
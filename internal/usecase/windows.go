@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.ngs.io/tides-api/internal/licensing"
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+const (
+	// windowStep is the sampling step of window planning; window bounds are
+	// accurate to one step.
+	windowStep = 5 * time.Minute
+	// MaxWindowSpan bounds the planning horizon.
+	MaxWindowSpan = 14 * 24 * time.Hour
+	// defaultWindowLimit is the number of windows returned by default.
+	defaultWindowLimit = 10
+)
+
+// WindowRequest asks for time windows in which a task can be carried out.
+type WindowRequest struct {
+	// Location, horizon (Start/End), datum offset and output timezone.
+	Prediction PredictionRequest
+
+	MinDepthM    *float64      // Minimum water depth (needs bathymetry).
+	MinHeightM   *float64      // Minimum tide height relative to the datum.
+	MaxCurrentMS *float64      // Maximum current speed (needs the current store and a location).
+	DaylightOnly bool          // Only while the sun is up.
+	Duration     time.Duration // Minimum window length.
+	Limit        int           // Maximum windows returned (default 10).
+}
+
+// WindowsResponse lists feasible windows, best first.
+type WindowsResponse struct {
+	Source   string `json:"source"`
+	Datum    string `json:"datum"`
+	Timezone string `json:"timezone"`
+	// Level is "depth" when windows are constrained by water depth, else "height".
+	Level       string            `json:"level"`
+	Windows     []PlanningWindow  `json:"windows"`
+	Meta        map[string]string `json:"meta"`
+	Fingerprint string            `json:"fingerprint"`
+	Scenario    *SLRScenario      `json:"scenario,omitempty"` // Set for sea level rise scenarios.
+	Degradation
+}
+
+// PlanningWindow is one feasible window.
+type PlanningWindow struct {
+	Rank            int     `json:"rank"`
+	Start           string  `json:"start"`
+	End             string  `json:"end"`
+	DurationMinutes int     `json:"duration_minutes"`
+	MinLevelM       float64 `json:"min_level_m"` // Lowest depth or height within the window.
+	MaxLevelM       float64 `json:"max_level_m"`
+	// Highest current speed within the window, with max_current_ms.
+	MaxCurrentMS *float64 `json:"max_current_ms,omitempty"`
+}
+
+// PlanWindows returns the windows within the request horizon during which
+// all constraints hold for at least the requested duration. Windows are
+// ranked longest first, then by margin, then earliest.
+//
+//nolint:gocyclo // Constraint resolution with several optional inputs.
+func (uc *PredictionUseCase) PlanWindows(req WindowRequest) (*WindowsResponse, error) {
+	if req.MaxCurrentMS != nil {
+		if uc.currentStore == nil {
+			return nil, errors.New("invalid request: tidal currents are not configured - remove max_current_ms")
+		}
+		if *req.MaxCurrentMS < 0 {
+			return nil, errors.New("invalid request: max_current_ms must not be negative")
+		}
+	}
+	if req.MinDepthM == nil && req.MinHeightM == nil && req.MaxCurrentMS == nil && !req.DaylightOnly {
+		return nil, errors.New("invalid request: at least one of min_depth_m, min_height_m, max_current_ms or daylight_only is required")
+	}
+	if req.Duration <= 0 {
+		return nil, errors.New("invalid request: duration must be positive")
+	}
+	pr := req.Prediction
+	if pr.End.Sub(pr.Start) > MaxWindowSpan {
+		return nil, fmt.Errorf("invalid request: planning horizon must be at most %d days", int(MaxWindowSpan.Hours()/24))
+	}
+	pr.Interval = windowStep
+	if err := pr.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	prepared, err := uc.prepare(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the level being constrained.
+	level := "height"
+	var seabed float64
+	if req.MinDepthM != nil {
+		md := prepared.metadata
+		if md != nil && md.DepthM == nil && len(md.Degraded) > 0 {
+			return nil, unavailableError("min_depth_m needs seabed depth", md.Degraded)
+		}
+		if md == nil || md.DepthM == nil || md.Land {
+			return nil, errors.New("min_depth_m needs seabed depth from bathymetry at this location - use min_height_m instead")
+		}
+		level = "depth"
+		seabed = *md.DepthM
+	}
+
+	// Resolve the location for daylight and currents.
+	var lat, lon float64
+	if req.DaylightOnly || req.MaxCurrentMS != nil {
+		switch {
+		case pr.Lat != nil && pr.Lon != nil:
+			lat, lon = *pr.Lat, *pr.Lon
+		case prepared.station != nil && prepared.station.Lat != nil && prepared.station.Lon != nil:
+			lat, lon = *prepared.station.Lat, *prepared.station.Lon
+		case req.DaylightOnly:
+			return nil, errors.New("daylight_only needs a location - use lat/lon or a station file with lat/lon")
+		default:
+			return nil, errors.New("max_current_ms needs a location - use lat/lon or a station file with lat/lon")
+		}
+	}
+
+	// Current speeds at the level samples, by Unix second.
+	var speeds map[int64]float64
+	if req.MaxCurrentMS != nil {
+		params, err := uc.currentParams(lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		currents := domain.GenerateCurrents(pr.Start, pr.End, windowStep, params)
+		speeds = make(map[int64]float64, len(currents))
+		for _, v := range currents {
+			speeds[v.Time.Unix()] = v.SpeedMS
+		}
+	}
+
+	series := domain.GeneratePredictions(pr.Start, pr.End, windowStep, prepared.params)
+	levels := series
+	if level == "depth" {
+		// Water depth as reported by predictions' depth_m.
+		levels = make([]domain.TideLevel, len(series))
+		for i, s := range series {
+			levels[i] = domain.TideLevel{Time: s.Time, HeightM: seabed + prepared.mslAt(s.Time) + prepared.datumLevel() + s.HeightM}
+		}
+	}
+
+	feasible := func(l domain.TideLevel) bool {
+		height := l.HeightM
+		if level == "depth" {
+			height -= seabed + prepared.mslAt(l.Time) + prepared.datumLevel()
+			if l.HeightM < *req.MinDepthM {
+				return false
+			}
+		}
+		if req.MinHeightM != nil && height < *req.MinHeightM {
+			return false
+		}
+		if speeds != nil && speeds[l.Time.Unix()] > *req.MaxCurrentMS {
+			return false
+		}
+		return !req.DaylightOnly || domain.IsDaylight(l.Time, lat, lon)
+	}
+
+	windows := domain.FindWindows(levels, feasible, req.Duration)
+	domain.RankWindows(windows)
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultWindowLimit
+	}
+	if len(windows) > limit {
+		windows = windows[:limit]
+	}
+
+	loc, tzLabel := outputZone(pr.Timezone)
+	points := make([]PlanningWindow, len(windows))
+	for i, w := range windows {
+		points[i] = PlanningWindow{
+			Rank:            i + 1,
+			Start:           w.Start.In(loc).Format(time.RFC3339),
+			End:             w.End.In(loc).Format(time.RFC3339),
+			DurationMinutes: int(w.Duration().Minutes()),
+			MinLevelM:       roundToDecimal(w.MinLevelM),
+			MaxLevelM:       roundToDecimal(w.MaxLevelM),
+		}
+		if speeds != nil {
+			var fastest float64
+			for t := w.Start; !t.After(w.End); t = t.Add(windowStep) {
+				fastest = max(fastest, speeds[t.Unix()])
+			}
+			fastest = roundToDecimal(fastest)
+			points[i].MaxCurrentMS = &fastest
+		}
+	}
+
+	provenance := newComputationProvenance(uc.codeVersion, uc.tables, prepared)
+	meta := provenance.Meta()
+	prepared.addNowcastMeta(meta)
+	prepared.addLandMotionMeta(meta)
+	prepared.addEnsembleMeta(meta)
+	prepared.addFallbackMeta(meta)
+	if speeds != nil {
+		meta["current_attribution"] = licensing.Attribution(licensing.FESCurrents)
+	}
+	return &WindowsResponse{
+		Source:      prepared.source,
+		Datum:       prepared.datumName(),
+		Timezone:    tzLabel,
+		Level:       level,
+		Windows:     points,
+		Meta:        meta,
+		Fingerprint: provenance.Fingerprint(),
+		Scenario:    prepared.scenario,
+		Degradation: prepared.degradation(),
+	}, nil
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.ngs.io/tides-api/pkg/domain"
+)
+
+// m2Currents is a current store with a 1 m/s east-west M2 stream.
+type m2Currents struct{}
+
+func (m2Currents) LoadCurrentsForLocation(float64, float64) (east, north []domain.ConstituentParam, err error) {
+	speed, _ := domain.GetConstituentSpeed("M2")
+	return []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: speed}}, nil, nil
+}
+
+func TestPlanWindowsMaxCurrent(t *testing.T) {
+	speed, _ := domain.GetConstituentSpeed("M2")
+	uc := NewPredictionUseCase(nil, fallbackLoader{params: []domain.ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: speed}}}, nil)
+	lat, lon := 35.0, 139.0
+	start := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	limit := 0.5
+	req := WindowRequest{
+		Prediction:   PredictionRequest{Lat: &lat, Lon: &lon, Start: start, End: start.Add(24 * time.Hour)},
+		MaxCurrentMS: &limit,
+		Duration:     time.Hour,
+	}
+
+	if _, err := uc.PlanWindows(req); err == nil || !strings.Contains(err.Error(), "tidal currents are not configured") {
+		t.Fatalf("without a current store: %v", err)
+	}
+
+	uc.SetCurrentStore(m2Currents{})
+	resp, err := uc.PlanWindows(req)
+	if err != nil {
+		t.Fatalf("PlanWindows: %v", err)
+	}
+	// Slack water lasts about two hours of each half cycle, four times a day.
+	if len(resp.Windows) != 4 {
+		t.Fatalf("windows = %+v, want 4", resp.Windows)
+	}
+	for _, w := range resp.Windows {
+		if w.MaxCurrentMS == nil || *w.MaxCurrentMS > limit || w.DurationMinutes < 60 || w.DurationMinutes > 150 {
+			t.Errorf("window %+v exceeds %v m/s or has an unexpected length", w, limit)
+		}
+	}
+	if resp.Meta["current_attribution"] == "" {
+		t.Error("no current attribution")
+	}
+
+	negative := -1.0
+	req.MaxCurrentMS = &negative
+	if _, err := uc.PlanWindows(req); err == nil {
+		t.Error("negative max_current_ms accepted")
+	}
+}
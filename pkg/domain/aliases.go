@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// constituentAliases maps normalized (upper-case, ASCII) spellings used by
+// CSV/FES/TPXO/NOAA datasets to internal constituent identifiers.
+// Names whose normalized form equals an identifier's upper-case form
+// (e.g., "MSF" for "MSf") resolve without an entry here.
+//
+//nolint:gochecknoglobals // Intentional: Read-only lookup table.
+var constituentAliases = map[string]string{
+	// Greek-letter constituents (FES "La2", NOAA "LAM2", spelled-out forms).
+	"LA2":      "LAM2",
+	"LDA2":     "LAM2",
+	"LAMBDA2":  "LAM2",
+	"EPSILON2": "EPS2",
+	"RHO":      "RHO1", // NOAA.
+	"RO1":      "RHO1",
+	"SIGMA1":   "SIG1",
+	"SIGMA2":   "SIG2",
+	// Long-period spellings.
+	"MSQ":  "MSqm",
+	"MSQM": "MSqm",
+	"MTM":  "Mtm",
+	"MSF":  "MSf",
+	"MF":   "Mf",
+	"MM":   "Mm",
+	"SSA":  "Ssa",
+	"SA":   "Sa",
+}
+
+// Capital forms that look like Latin letters (e.g., Greek Mu vs M) are left alone.
+//
+//nolint:gochecknoglobals // Intentional: Greek letters to ASCII abbreviations.
+var greekLetters = strings.NewReplacer(
+	"λ", "LAM", "Λ", "LAM",
+	"ε", "EPS",
+	"ν", "NU",
+	"μ", "MU",
+	"ρ", "RHO",
+	"σ", "SIG", "Σ", "SIG",
+)
+
+// normalizeConstituentKey folds subscripts, Greek letters, separators and case
+// so that e.g. "2N₂", "λ2", "m_sf" and "MSF" compare equal.
+func normalizeConstituentKey(name string) string {
+	name = greekLetters.Replace(strings.TrimSpace(name))
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= '₀' && r <= '₉':
+			b.WriteRune('0' + (r - '₀'))
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			continue
+		default:
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// CanonicalConstituentName resolves a dataset-specific constituent name to the
+// internal identifier used by StandardConstituents. ok reports whether the
+// constituent is supported; when it is not, the normalized spelling is
+// returned so callers can report it.
+func CanonicalConstituentName(name string) (canonical string, ok bool) {
+	key := normalizeConstituentKey(name)
+	if alias, found := constituentAliases[key]; found {
+		key = alias
+	}
+	if _, found := StandardConstituents[key]; found {
+		return key, true
+	}
+	for id := range StandardConstituents {
+		if strings.ToUpper(id) == strings.ToUpper(key) {
+			return id, true
+		}
+	}
+	return key, false
+}
+
+// CanonicalizeConstituents resolves names to internal identifiers, dropping
+// duplicates. Unsupported names are returned separately (normalized) so they
+// can be reported instead of silently ignored.
+func CanonicalizeConstituents(names []string) (known, unknown []string) {
+	seen := make(map[string]bool, len(names))
+	for _, n := range names {
+		if strings.TrimSpace(n) == "" {
+			continue
+		}
+		canonical, ok := CanonicalConstituentName(n)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		if ok {
+			known = append(known, canonical)
+		} else {
+			unknown = append(unknown, canonical)
+		}
+	}
+	return known, unknown
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+// TestCanonicalConstituentName tests dataset-specific spellings.
+func TestCanonicalConstituentName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"M2", "M2", true},
+		{"m2", "M2", true},
+		{"M₂", "M2", true},
+		{"mf", "Mf", true},
+		{"MM", "Mm", true},
+		{"SSA", "Ssa", true},
+		{"ms_4", "MS4", true},
+		{" K1 ", "K1", true},
+		{"2N₂", "2N2", true},
+		{"La2", "LAM2", true},
+		{"λ2", "LAM2", true},
+		{"mks2", "MKS2", true},
+		{"RHO", "RHO1", false},
+		{"XYZ", "XYZ", false},
+	}
+	for _, tt := range tests {
+		got, ok := CanonicalConstituentName(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("CanonicalConstituentName(%q) = (%q, %v), want (%q, %v)", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestCanonicalizeConstituents tests deduplication and unknown reporting.
+func TestCanonicalizeConstituents(t *testing.T) {
+	known, unknown := CanonicalizeConstituents([]string{"M2", "m2", "K1", "EPS2", "", "Mf", "SIGMA1"})
+	if len(known) != 4 || known[0] != "M2" || known[1] != "K1" || known[2] != "EPS2" || known[3] != "Mf" {
+		t.Errorf("unexpected known: %v", known)
+	}
+	if len(unknown) != 1 || unknown[0] != "SIG1" {
+		t.Errorf("unexpected unknown: %v", unknown)
+	}
+}
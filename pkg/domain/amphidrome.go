@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Amphidrome is an amphidromic point: a zero of a constituent's amplitude
+// around which its phase turns through a full cycle.
+type Amphidrome struct {
+	Location LonLat
+	// Anticlockwise is true when the phase lag increases anticlockwise,
+	// i.e. high water travels anticlockwise around the point.
+	Anticlockwise bool
+	// CellMinAmplitudeM is the smallest amplitude at the surrounding
+	// grid nodes.
+	CellMinAmplitudeM float64
+}
+
+// FindAmphidromes locates amphidromic points on amplitude (m) and Greenwich
+// phase lag (degrees) grids of a constituent, sharing one layout. A cell
+// holds one when its corner phases wind through ±360°; the point is where
+// a linear fit of the complex tide A·e^(−iG) over the cell vanishes. Cells
+// with missing nodes are skipped, so points on land are not found.
+func FindAmphidromes(amplitude, phase Grid) []Amphidrome {
+	var found []Amphidrome
+	for i := 0; i+1 < len(phase.Lats); i++ {
+		for j := 0; j+1 < len(phase.Lons); j++ {
+			// Corners anticlockwise from the south-west.
+			corners := [4][2]int{{i, j}, {i, j + 1}, {i + 1, j + 1}, {i + 1, j}}
+			var z [4]complex128
+			winding, minAmp := 0.0, math.Inf(1)
+			valid := true
+			for k, c := range corners {
+				a, g := amplitude.Values[c[0]][c[1]], phase.Values[c[0]][c[1]]
+				if math.IsNaN(a) || math.IsNaN(g) {
+					valid = false
+					break
+				}
+				next := corners[(k+1)%4]
+				winding += wrapDeg180(phase.Values[next[0]][next[1]] - g)
+				minAmp = math.Min(minAmp, a)
+				z[k] = cmplx.Rect(a, -Deg2Rad(g))
+			}
+			if !valid || math.Abs(winding) < 180 {
+				continue
+			}
+			found = append(found, Amphidrome{
+				Location:          phase.cellZero(i, j, z),
+				Anticlockwise:     winding > 0,
+				CellMinAmplitudeM: minAmp,
+			})
+		}
+	}
+	return found
+}
+
+// cellZero returns where the plane z0 + x·zx + y·zy fitted to the corner
+// values z (anticlockwise from the south-west) vanishes, for x, y in [0, 1]
+// across cell (i, j). The point is clamped to the cell.
+func (g Grid) cellZero(i, j int, z [4]complex128) LonLat {
+	z0 := (z[0] + z[1] + z[2] + z[3]) / 4
+	zx := (z[1] + z[2] - z[0] - z[3]) / 2
+	zy := (z[2] + z[3] - z[0] - z[1]) / 2
+	// Solve real x, y (offsets from the center) with z0 + x·zx + y·zy = 0.
+	det := real(zx)*imag(zy) - imag(zx)*real(zy)
+	x, y := 0.0, 0.0
+	if det != 0 {
+		x = (-real(z0)*imag(zy) + imag(z0)*real(zy)) / det
+		y = (-real(zx)*imag(z0) + imag(zx)*real(z0)) / det
+	}
+	x = math.Max(0, math.Min(1, x+0.5))
+	y = math.Max(0, math.Min(1, y+0.5))
+	return LonLat{
+		g.Lons[j] + x*(g.Lons[j+1]-g.Lons[j]),
+		g.Lats[i] + y*(g.Lats[i+1]-g.Lats[i]),
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// tideGrids samples a complex tide z(lon, lat) = A·e^(−iG) into amplitude
+// and phase grids.
+func tideGrids(b BBox, step float64, z func(lon, lat float64) complex128) (amplitude, phase Grid) {
+	amplitude, phase = NewGrid(b, step), NewGrid(b, step)
+	fillGrid(amplitude, func(lat, lon float64) float64 { return cmplx.Abs(z(lon, lat)) })
+	fillGrid(phase, func(lat, lon float64) float64 { return NormalizePhaseDeg(-Rad2Deg(cmplx.Phase(z(lon, lat)))) })
+	return amplitude, phase
+}
+
+func TestFindAmphidromes_LocatesAndOrients(t *testing.T) {
+	b := BBox{MinLat: 33, MinLon: 138, MaxLat: 37, MaxLon: 142}
+	const lon0, lat0 = 140.3, 35.2
+	for _, anticlockwise := range []bool{true, false} {
+		amplitude, phase := tideGrids(b, 0.5, func(lon, lat float64) complex128 {
+			z := complex(lon-lon0, lat-lat0)
+			if anticlockwise {
+				// Phase lag G = arg z increases anticlockwise.
+				z = cmplx.Conj(z)
+			}
+			return z
+		})
+
+		found := FindAmphidromes(amplitude, phase)
+		if len(found) != 1 {
+			t.Fatalf("anticlockwise=%v: found %d amphidromes, want 1", anticlockwise, len(found))
+		}
+		a := found[0]
+		if math.Abs(a.Location[0]-lon0) > 1e-9 || math.Abs(a.Location[1]-lat0) > 1e-9 {
+			t.Errorf("location = %v, want [%v %v]", a.Location, lon0, lat0)
+		}
+		if a.Anticlockwise != anticlockwise {
+			t.Errorf("Anticlockwise = %v, want %v", a.Anticlockwise, anticlockwise)
+		}
+		// The nearest node is (140.5, 35).
+		if want := math.Hypot(0.2, 0.2); math.Abs(a.CellMinAmplitudeM-want) > 1e-9 {
+			t.Errorf("cell minimum amplitude = %v, want %v", a.CellMinAmplitudeM, want)
+		}
+	}
+}
+
+func TestFindAmphidromes_IgnoresProgressiveWaveAndMissingCells(t *testing.T) {
+	b := BBox{MinLat: 33, MinLon: 138, MaxLat: 37, MaxLon: 142}
+	// A wave travelling east: phase grows with longitude through the wrap.
+	amplitude, phase := tideGrids(b, 0.5, func(lon, _ float64) complex128 {
+		return cmplx.Rect(1, -Deg2Rad(100*lon))
+	})
+	if found := FindAmphidromes(amplitude, phase); len(found) != 0 {
+		t.Errorf("progressive wave: found %v", found)
+	}
+
+	amplitude, phase = tideGrids(b, 0.5, func(lon, lat float64) complex128 {
+		return complex(lon-140.3, lat-35.2)
+	})
+	phase.Values[4][4] = math.NaN() // A corner of the amphidrome's cell.
+	if found := FindAmphidromes(amplitude, phase); len(found) != 0 {
+		t.Errorf("missing node: found %v", found)
+	}
+}
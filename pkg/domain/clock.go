@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Clock tells the current time. Defaults relative to "now" (e.g., today's
+// predictions, monitor evaluations) read it, so tests and reproducible runs
+// can freeze time.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock.
+type SystemClock struct{}
+
+// Now returns the current wall-clock time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always tells the same time.
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now returns the fixed time.
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNoCommonConstituents indicates two sites share no constituent to
+// compare.
+var ErrNoCommonConstituents = errors.New("no common constituents")
+
+// Tide types by form factor (Courtier classification).
+const (
+	TideSemidiurnal      = "semidiurnal"
+	TideMixedSemidiurnal = "mixed_semidiurnal"
+	TideMixedDiurnal     = "mixed_diurnal"
+	TideDiurnal          = "diurnal"
+)
+
+// ConstituentComparison relates one constituent at two sites.
+type ConstituentComparison struct {
+	Name           string
+	AmplitudeRatio float64       // Amplitude at the second site over the first (co-range).
+	PhaseLagDeg    float64       // How far the second site lags the first, in (-180, 180] (co-tidal).
+	TimeLag        time.Duration // PhaseLagDeg as time at the constituent's speed.
+}
+
+// TideComparison relates the tides at two sites.
+type TideComparison struct {
+	// Common constituents, in the order of the first site.
+	Constituents []ConstituentComparison
+	// Reference is the largest common constituent at the first site; its
+	// time lag is the high-water lag.
+	Reference    string
+	HighWaterLag time.Duration
+	// RangeRatio is the ratio of the sums of common amplitudes, i.e. of
+	// the largest possible ranges, second site over first.
+	RangeRatio float64
+}
+
+// CompareTides compares the tides of two sites from their constituents.
+// Phases are compared through the arguments both parameter sets give at t,
+// so sites with different sources, epochs or phase conventions compare
+// correctly. Nodal corrections cancel, as they are the same at both sites.
+func CompareTides(t time.Time, from, to PredictionParams) (TideComparison, error) {
+	toByName := make(map[string]ConstituentParam, len(to.Constituents))
+	for _, c := range to.Constituents {
+		toByName[c.Name] = c
+	}
+
+	var cmp TideComparison
+	var sumFrom, sumTo, largest float64
+	for _, a := range from.Constituents {
+		b, ok := toByName[a.Name]
+		if !ok || a.AmplitudeM <= 0 || a.SpeedDegPerHr <= 0 {
+			continue
+		}
+		lag := wrapDeg180(PhaseArgumentDeg(t, from, a) - PhaseArgumentDeg(t, to, b))
+		cmp.Constituents = append(cmp.Constituents, ConstituentComparison{
+			Name:           a.Name,
+			AmplitudeRatio: b.AmplitudeM / a.AmplitudeM,
+			PhaseLagDeg:    lag,
+			TimeLag:        time.Duration(lag / a.SpeedDegPerHr * float64(time.Hour)),
+		})
+		sumFrom += a.AmplitudeM
+		sumTo += b.AmplitudeM
+		if a.AmplitudeM > largest {
+			largest = a.AmplitudeM
+			cmp.Reference = a.Name
+			cmp.HighWaterLag = cmp.Constituents[len(cmp.Constituents)-1].TimeLag
+		}
+	}
+	if len(cmp.Constituents) == 0 {
+		return TideComparison{}, ErrNoCommonConstituents
+	}
+	cmp.RangeRatio = sumTo / sumFrom
+	return cmp, nil
+}
+
+// FormFactor returns (K1 + O1) / (M2 + S2); ok is false without M2 or S2.
+func FormFactor(constituents []ConstituentParam) (f float64, ok bool) {
+	amp := make(map[string]float64, len(constituents))
+	for _, c := range constituents {
+		amp[c.Name] = c.AmplitudeM
+	}
+	semi := amp["M2"] + amp["S2"]
+	if semi <= 0 {
+		return 0, false
+	}
+	return (amp["K1"] + amp["O1"]) / semi, true
+}
+
+// TideType classifies a form factor.
+func TideType(formFactor float64) string {
+	switch {
+	case formFactor < 0.25:
+		return TideSemidiurnal
+	case formFactor < 1.5:
+		return TideMixedSemidiurnal
+	case formFactor < 3:
+		return TideMixedDiurnal
+	default:
+		return TideDiurnal
+	}
+}
+
+// wrapDeg180 wraps an angle into (-180, 180].
+func wrapDeg180(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	switch {
+	case deg > 180:
+		deg -= 360
+	case deg <= -180:
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCompareTides_LagAndRatio(t *testing.T) {
+	const m2, k1 = 28.9841042, 15.0410686
+	refTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	from := PredictionParams{
+		Constituents: []ConstituentParam{
+			{Name: "M2", AmplitudeM: 1.0, PhaseDeg: 100, SpeedDegPerHr: m2},
+			{Name: "K1", AmplitudeM: 0.2, PhaseDeg: 200, SpeedDegPerHr: k1},
+			{Name: "S2", AmplitudeM: 0.4, PhaseDeg: 120, SpeedDegPerHr: 30},
+		},
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   refTime,
+	}
+	// The second site lags by 30° in M2 and -20° in K1, uses another epoch
+	// and has no S2.
+	to := PredictionParams{
+		Constituents: []ConstituentParam{
+			{Name: "K1", AmplitudeM: 0.3, PhaseDeg: 180 + k1*24, SpeedDegPerHr: k1},
+			{Name: "M2", AmplitudeM: 1.5, PhaseDeg: 130 + m2*24, SpeedDegPerHr: m2},
+		},
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   refTime.Add(-24 * time.Hour),
+	}
+
+	cmp, err := CompareTides(refTime.Add(100*time.Hour), from, to)
+	if err != nil {
+		t.Fatalf("CompareTides: %v", err)
+	}
+	if len(cmp.Constituents) != 2 || cmp.Constituents[0].Name != "M2" || cmp.Constituents[1].Name != "K1" {
+		t.Fatalf("constituents = %+v, want M2 and K1", cmp.Constituents)
+	}
+	m2Cmp, k1Cmp := cmp.Constituents[0], cmp.Constituents[1]
+	if math.Abs(m2Cmp.PhaseLagDeg-30) > 1e-6 || math.Abs(m2Cmp.AmplitudeRatio-1.5) > 1e-9 {
+		t.Errorf("M2 = %+v, want lag 30° and ratio 1.5", m2Cmp)
+	}
+	if math.Abs(k1Cmp.PhaseLagDeg+20) > 1e-6 {
+		t.Errorf("K1 lag = %v, want -20°", k1Cmp.PhaseLagDeg)
+	}
+	wantLag := time.Duration(math.Round(30 / m2 * float64(time.Hour)))
+	if cmp.Reference != "M2" || (cmp.HighWaterLag-wantLag).Abs() > time.Second {
+		t.Errorf("high water lag = %s from %s, want %s from M2", cmp.HighWaterLag, cmp.Reference, wantLag)
+	}
+	if want := 1.8 / 1.2; math.Abs(cmp.RangeRatio-want) > 1e-9 {
+		t.Errorf("range ratio = %v, want %v", cmp.RangeRatio, want)
+	}
+
+	// With M2 alone, each predicted high water at the second site follows
+	// one at the first by the lag.
+	highs := func(p PredictionParams, name string) []TideLevel {
+		for _, c := range p.Constituents {
+			if c.Name == name {
+				p.Constituents = []ConstituentParam{c}
+			}
+		}
+		start := refTime.Add(100 * time.Hour)
+		return FindExtrema(GeneratePredictions(start, start.Add(13*time.Hour), time.Minute, p)).Highs
+	}
+	fromHW, toHW := highs(from, "M2"), highs(to, "M2")
+	if len(fromHW) == 0 || len(toHW) == 0 {
+		t.Fatalf("no M2 high waters: %v, %v", fromHW, toHW)
+	}
+	found := false
+	for _, h := range toHW {
+		if d := h.Time.Sub(fromHW[0].Time); d > 0 && d < 6*time.Hour {
+			found = true
+			if (d - wantLag).Abs() > time.Minute {
+				t.Errorf("predicted M2 high water lag = %s, want %s", d, wantLag)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no M2 high water at the second site follows %s", fromHW[0].Time)
+	}
+}
+
+func TestCompareTides_NoCommonConstituents(t *testing.T) {
+	from := PredictionParams{Constituents: []ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: 28.9841042}}}
+	to := PredictionParams{Constituents: []ConstituentParam{{Name: "K1", AmplitudeM: 1, SpeedDegPerHr: 15.0410686}}}
+	if _, err := CompareTides(time.Now(), from, to); !errors.Is(err, ErrNoCommonConstituents) {
+		t.Errorf("err = %v, want ErrNoCommonConstituents", err)
+	}
+}
+
+func TestFormFactorAndTideType(t *testing.T) {
+	f, ok := FormFactor([]ConstituentParam{
+		{Name: "M2", AmplitudeM: 0.5}, {Name: "S2", AmplitudeM: 0.25},
+		{Name: "K1", AmplitudeM: 0.3}, {Name: "O1", AmplitudeM: 0.3},
+	})
+	if !ok || math.Abs(f-0.8) > 1e-9 || TideType(f) != TideMixedSemidiurnal {
+		t.Errorf("form factor = %v (%v), type %s; want 0.8 mixed_semidiurnal", f, ok, TideType(f))
+	}
+	if _, ok := FormFactor([]ConstituentParam{{Name: "K1", AmplitudeM: 0.3}}); ok {
+		t.Error("form factor without semidiurnal constituents should not be ok")
+	}
+}
@@ -0,0 +1,215 @@
+// Package domain defines core tidal prediction domain models and algorithms.
+// It is public, with pkg/store, for use as a library.
+package domain
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Constituent represents a tidal constituent with its angular speed.
+type Constituent struct {
+	Name          string  // E.g., "M2", "S2", "K1", "O1".
+	SpeedDegPerHr float64 // Angular speed in degrees per hour.
+}
+
+// ConstituentType groups constituents by species, the number of cycles per
+// lunar day.
+type ConstituentType string
+
+// Constituent types.
+const (
+	LongPeriod  ConstituentType = "long_period" // Species 0, e.g., Mf, Sa.
+	Diurnal     ConstituentType = "diurnal"     // Species 1, e.g., K1, O1.
+	Semidiurnal ConstituentType = "semidiurnal" // Species 2, e.g., M2, S2.
+	Shallow     ConstituentType = "shallow"     // Species 3 and higher, e.g., M4, MS4.
+)
+
+// ConstituentTypes lists the constituent types by species.
+//
+//nolint:gochecknoglobals // Intentional: Read-only list of enum values.
+var ConstituentTypes = []ConstituentType{LongPeriod, Diurnal, Semidiurnal, Shallow}
+
+// Type returns the species of the constituent: its frequency rounded to
+// whole cycles per day.
+func (c Constituent) Type() ConstituentType {
+	switch species := math.Round(c.FrequencyCPD()); {
+	case species < 1:
+		return LongPeriod
+	case species < 2:
+		return Diurnal
+	case species < 3:
+		return Semidiurnal
+	default:
+		return Shallow
+	}
+}
+
+// FrequencyCPD returns the frequency in cycles per day.
+func (c Constituent) FrequencyCPD() float64 {
+	return SpeedToCPD(c.SpeedDegPerHr)
+}
+
+// PeriodHours returns the period in hours (zero for a zero speed).
+func (c Constituent) PeriodHours() float64 {
+	if c.SpeedDegPerHr == 0 {
+		return 0
+	}
+	return 360 / c.SpeedDegPerHr
+}
+
+// ConstituentParam holds the amplitude and phase for a specific location.
+type ConstituentParam struct {
+	Name          string
+	AmplitudeM    float64 // Amplitude in meters.
+	PhaseDeg      float64 // Phase in degrees.
+	SpeedDegPerHr float64 // Angular speed in degrees per hour.
+}
+
+// SortedConstituents returns a copy of params in order of speed, then
+// name, so that responses list constituents in the same order whatever
+// the order of the dataset files.
+func SortedConstituents(params []ConstituentParam) []ConstituentParam {
+	sorted := slices.Clone(params)
+	slices.SortStableFunc(sorted, func(a, b ConstituentParam) int {
+		return cmp.Or(cmp.Compare(a.SpeedDegPerHr, b.SpeedDegPerHr), strings.Compare(a.Name, b.Name))
+	})
+	return sorted
+}
+
+// StandardConstituents contains tidal constituents with their angular speeds (deg/hour).
+// Reference: https://www.pmel.noaa.gov/pubs/PDF/park2589/park2589.pdf
+//
+//nolint:gochecknoglobals // Intentional: Read-only constant map.
+var StandardConstituents = map[string]float64{
+	// Principal lunar semidiurnal.
+	"M2": 28.9841042,
+	// Principal solar semidiurnal.
+	"S2": 30.0000000,
+	// Larger lunar elliptic semidiurnal.
+	"N2": 28.4397295,
+	// Lunisolar semidiurnal.
+	"K2": 30.0821373,
+	// Lunar elliptic semidiurnal, second order.
+	"2N2": 27.8953548,
+	// Variational semidiurnal.
+	"MU2": 27.9682084,
+	// Larger lunar evectional.
+	"NU2": 28.5125831,
+	// Smaller lunar elliptic semidiurnal.
+	"L2": 29.5284789,
+	// Smaller lunar evectional.
+	"LAM2": 29.4556253,
+	// Lunar semidiurnal (evection and variation).
+	"EPS2": 27.4238337,
+	// Larger and smaller solar elliptic semidiurnal.
+	"T2": 29.9589333,
+	"R2": 30.0410667,
+
+	// Lunisolar diurnal.
+	"K1": 15.0410686,
+	// Lunar diurnal.
+	"O1": 13.9430356,
+	// Solar diurnal.
+	"P1": 14.9589314,
+	// Larger lunar elliptic diurnal.
+	"Q1": 13.3986609,
+	// Smaller lunar elliptic diurnal.
+	"J1": 15.5854433,
+	// Lunar diurnal (NOAA M1).
+	"M1": 14.4966939,
+	// Lunar diurnal, second order.
+	"OO1": 16.1391017,
+	// Solar diurnal (radiational).
+	"S1": 15.0000000,
+
+	// Shallow water constituents.
+	"M4":   57.9682084,
+	"M6":   86.9523127,
+	"MK3":  44.0251729,
+	"S4":   60.0000000,
+	"MN4":  57.4238337,
+	"MS4":  58.9841042,
+	"M3":   43.4761563,
+	"N4":   56.8794590,
+	"M8":   115.9364166,
+	"MKS2": 29.0662415,
+
+	// Long period.
+	"Mf":   1.0980331,
+	"Mm":   0.5443747,
+	"Ssa":  0.0821373,
+	"Sa":   0.0410686,
+	"MSf":  1.0158958,
+	"Mtm":  1.6424078,
+	"MSqm": 2.1139288,
+	// Lunar nodal (18.61 years).
+	"Node": 0.0022064,
+}
+
+// NodalCorrection is an interface for applying nodal corrections.
+// MVP: returns identity (1.0, 0.0).
+type NodalCorrection interface {
+	// GetFactors returns the amplitude factor (f) and phase correction (u) in degrees.
+	GetFactors(constituent string, t float64) (f float64, u float64)
+	// GetEquilibriumArgument returns the equilibrium argument V (degrees) for the constituent.
+	// V accounts for slowly varying astronomical arguments (Schureman/Foreman).
+	// Implementations may return 0 if not available.
+	GetEquilibriumArgument(constituent string, t float64) float64
+}
+
+// IdentityNodalCorrection is a dummy implementation that returns no correction.
+type IdentityNodalCorrection struct{}
+
+// GetFactors returns the nodal correction factors (no correction for identity).
+func (i *IdentityNodalCorrection) GetFactors(_ string, _ float64) (float64, float64) {
+	return 1.0, 0.0
+}
+
+// GetEquilibriumArgument returns the equilibrium argument (no correction for identity).
+func (i *IdentityNodalCorrection) GetEquilibriumArgument(_ string, _ float64) float64 {
+	return 0.0
+}
+
+// GetConstituentSpeed returns the angular speed for a given constituent name.
+func GetConstituentSpeed(name string) (float64, bool) {
+	speed, ok := StandardConstituents[name]
+	return speed, ok
+}
+
+// GetAllConstituents returns all standard constituents in order of speed,
+// then name.
+func GetAllConstituents() []Constituent {
+	constituents := make([]Constituent, 0, len(StandardConstituents))
+	for name, speed := range StandardConstituents {
+		constituents = append(constituents, Constituent{
+			Name:          name,
+			SpeedDegPerHr: speed,
+		})
+	}
+	slices.SortFunc(constituents, func(a, b Constituent) int {
+		return cmp.Or(cmp.Compare(a.SpeedDegPerHr, b.SpeedDegPerHr), strings.Compare(a.Name, b.Name))
+	})
+	return constituents
+}
+
+// Deg2Rad converts degrees to radians.
+func Deg2Rad(deg float64) float64 {
+	return deg * math.Pi / 180.0
+}
+
+// Rad2Deg converts radians to degrees.
+func Rad2Deg(rad float64) float64 {
+	return rad * 180.0 / math.Pi
+}
+
+// NormalizePhaseDeg wraps a phase lag to [0, 360).
+func NormalizePhaseDeg(deg float64) float64 {
+	deg = normalizeDeg(deg)
+	if deg >= 360 { // E.g., -1e-15 rounds up to 360.
+		deg = 0
+	}
+	return deg
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// TestConstituent_Type tests classification by species.
+func TestConstituent_Type(t *testing.T) {
+	tests := []struct {
+		name string
+		want ConstituentType
+	}{
+		{"Sa", LongPeriod},
+		{"Mf", LongPeriod},
+		{"K1", Diurnal},
+		{"O1", Diurnal},
+		{"M2", Semidiurnal},
+		{"N2", Semidiurnal},
+		{"M4", Shallow},
+		{"MS4", Shallow},
+	}
+	for _, tt := range tests {
+		speed, ok := GetConstituentSpeed(tt.name)
+		if !ok {
+			t.Fatalf("%s: not a standard constituent", tt.name)
+		}
+		if got := (Constituent{Name: tt.name, SpeedDegPerHr: speed}).Type(); got != tt.want {
+			t.Errorf("%s.Type() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestConstituent_PeriodHours tests the period of M2 and of a zero speed.
+func TestConstituent_PeriodHours(t *testing.T) {
+	m2 := Constituent{Name: "M2", SpeedDegPerHr: 28.9841042}
+	if got := m2.PeriodHours(); math.Abs(got-12.4206) > 1e-4 {
+		t.Errorf("M2 period = %v h, want 12.4206", got)
+	}
+	if got := (Constituent{Name: "Z0"}).PeriodHours(); got != 0 {
+		t.Errorf("zero-speed period = %v, want 0", got)
+	}
+}
+
+// TestSortedConstituents tests ordering by speed, then name.
+func TestSortedConstituents(t *testing.T) {
+	params := []ConstituentParam{
+		{Name: "M2", SpeedDegPerHr: 28.9841042},
+		{Name: "X2", SpeedDegPerHr: 28.9841042},
+		{Name: "K1", SpeedDegPerHr: 15.0410686},
+		{Name: "Sa", SpeedDegPerHr: 0.0410686},
+	}
+	got := SortedConstituents(params)
+	want := []string{"Sa", "K1", "M2", "X2"}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Fatalf("order = %v, want %v", got, want)
+		}
+	}
+	if params[0].Name != "M2" {
+		t.Error("input reordered")
+	}
+
+	all := GetAllConstituents()
+	for i := 1; i < len(all); i++ {
+		if all[i].SpeedDegPerHr < all[i-1].SpeedDegPerHr {
+			t.Fatalf("GetAllConstituents: %s listed after %s", all[i].Name, all[i-1].Name)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// BBox is a latitude/longitude box in degrees.
+type BBox struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Validate checks the box is ordered and on the globe.
+func (b BBox) Validate() error {
+	switch {
+	case b.MinLat < -90 || b.MaxLat > 90:
+		return fmt.Errorf("bbox latitudes must be between -90 and 90")
+	case b.MinLon < -180 || b.MaxLon > 180:
+		return fmt.Errorf("bbox longitudes must be between -180 and 180")
+	case b.MinLat >= b.MaxLat || b.MinLon >= b.MaxLon:
+		return fmt.Errorf("bbox minimums must be below maximums")
+	}
+	return nil
+}
+
+// Grid holds values on a regular latitude/longitude grid; NaN marks nodes
+// without data (e.g., land).
+type Grid struct {
+	Lats, Lons []float64   // Ascending node coordinates.
+	Values     [][]float64 // Indexed [lat][lon].
+}
+
+// NewGrid returns a NaN-filled grid over b with nodes every stepDeg,
+// including both edges.
+func NewGrid(b BBox, stepDeg float64) Grid {
+	axis := func(lo, hi float64) []float64 {
+		n := int(math.Floor((hi-lo)/stepDeg+1e-9)) + 1
+		v := make([]float64, n)
+		for i := range v {
+			v[i] = lo + float64(i)*stepDeg
+		}
+		return v
+	}
+	g := Grid{Lats: axis(b.MinLat, b.MaxLat), Lons: axis(b.MinLon, b.MaxLon)}
+	g.Values = make([][]float64, len(g.Lats))
+	for i := range g.Values {
+		g.Values[i] = make([]float64, len(g.Lons))
+		for j := range g.Values[i] {
+			g.Values[i][j] = math.NaN()
+		}
+	}
+	return g
+}
+
+// Map returns a grid of fn applied to each value.
+func (g Grid) Map(fn func(float64) float64) Grid {
+	out := Grid{Lats: g.Lats, Lons: g.Lons, Values: make([][]float64, len(g.Values))}
+	for i, row := range g.Values {
+		out.Values[i] = make([]float64, len(row))
+		for j, v := range row {
+			out.Values[i][j] = fn(v)
+		}
+	}
+	return out
+}
+
+// Range returns the smallest and largest values; ok is false when every
+// node is NaN.
+func (g Grid) Range() (lo, hi float64, ok bool) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, row := range g.Values {
+		for _, v := range row {
+			if !math.IsNaN(v) {
+				lo, hi, ok = math.Min(lo, v), math.Max(hi, v), true
+			}
+		}
+	}
+	return lo, hi, ok
+}
+
+// LonLat is a point as [longitude, latitude], the GeoJSON order.
+type LonLat [2]float64
+
+// cellEdge identifies a grid edge: the edge from node (i, j) toward
+// increasing longitude (vertical false) or latitude (vertical true).
+type cellEdge struct {
+	i, j     int
+	vertical bool
+}
+
+// Contour returns the polylines along which the grid crosses level, by
+// marching squares. Cells with a NaN corner, or for which keep returns
+// false, are skipped; keep may be nil. Lines that close on themselves
+// repeat their first point.
+func (g Grid) Contour(level float64, keep func(i, j int) bool) [][]LonLat {
+	type segment struct{ a, b cellEdge }
+	var segments []segment
+	for i := 0; i+1 < len(g.Lats); i++ {
+		for j := 0; j+1 < len(g.Lons); j++ {
+			v00, v01 := g.Values[i][j], g.Values[i][j+1]
+			v10, v11 := g.Values[i+1][j], g.Values[i+1][j+1]
+			if math.IsNaN(v00) || math.IsNaN(v01) || math.IsNaN(v10) || math.IsNaN(v11) {
+				continue
+			}
+			if keep != nil && !keep(i, j) {
+				continue
+			}
+			bottom, top := cellEdge{i, j, false}, cellEdge{i + 1, j, false}
+			left, right := cellEdge{i, j, true}, cellEdge{i, j + 1, true}
+
+			var crossed []cellEdge
+			if (v00 > level) != (v01 > level) {
+				crossed = append(crossed, bottom)
+			}
+			if (v01 > level) != (v11 > level) {
+				crossed = append(crossed, right)
+			}
+			if (v11 > level) != (v10 > level) {
+				crossed = append(crossed, top)
+			}
+			if (v10 > level) != (v00 > level) {
+				crossed = append(crossed, left)
+			}
+			switch len(crossed) {
+			case 2:
+				segments = append(segments, segment{crossed[0], crossed[1]})
+			case 4:
+				// Saddle: the cell center decides which corners connect.
+				if (v00+v01+v10+v11)/4 > level == (v00 > level) {
+					segments = append(segments, segment{bottom, right}, segment{top, left})
+				} else {
+					segments = append(segments, segment{bottom, left}, segment{top, right})
+				}
+			}
+		}
+	}
+
+	// Join segments sharing edges into polylines.
+	byEdge := make(map[cellEdge][]int, 2*len(segments))
+	for k, s := range segments {
+		byEdge[s.a] = append(byEdge[s.a], k)
+		byEdge[s.b] = append(byEdge[s.b], k)
+	}
+	used := make([]bool, len(segments))
+	walk := func(start cellEdge) []LonLat {
+		line := []LonLat{g.crossing(start, level)}
+		at := start
+		for {
+			next := -1
+			for _, k := range byEdge[at] {
+				if !used[k] {
+					next = k
+					break
+				}
+			}
+			if next < 0 {
+				return line
+			}
+			used[next] = true
+			if segments[next].a == at {
+				at = segments[next].b
+			} else {
+				at = segments[next].a
+			}
+			line = append(line, g.crossing(at, level))
+		}
+	}
+	var lines [][]LonLat
+	// Open lines start at an edge used once; what remains are loops.
+	for k, s := range segments {
+		if used[k] {
+			continue
+		}
+		for _, e := range []cellEdge{s.a, s.b} {
+			if len(byEdge[e]) == 1 {
+				lines = append(lines, walk(e))
+				break
+			}
+		}
+	}
+	for k, s := range segments {
+		if !used[k] {
+			lines = append(lines, walk(s.a))
+		}
+	}
+	return lines
+}
+
+// crossing interpolates where level crosses edge e.
+func (g Grid) crossing(e cellEdge, level float64) LonLat {
+	i2, j2 := e.i, e.j+1
+	if e.vertical {
+		i2, j2 = e.i+1, e.j
+	}
+	a, b := g.Values[e.i][e.j], g.Values[i2][j2]
+	t := 0.5
+	if a != b {
+		t = (level - a) / (b - a)
+	}
+	return LonLat{
+		g.Lons[e.j] + t*(g.Lons[j2]-g.Lons[e.j]),
+		g.Lats[e.i] + t*(g.Lats[i2]-g.Lats[e.i]),
+	}
+}
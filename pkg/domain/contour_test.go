@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// fillGrid sets every node of g to fn(lat, lon).
+func fillGrid(g Grid, fn func(lat, lon float64) float64) {
+	for i, lat := range g.Lats {
+		for j, lon := range g.Lons {
+			g.Values[i][j] = fn(lat, lon)
+		}
+	}
+}
+
+func TestNewGrid_IncludesEdges(t *testing.T) {
+	g := NewGrid(BBox{MinLat: 30, MinLon: 130, MaxLat: 31, MaxLon: 132}, 0.5)
+	if len(g.Lats) != 3 || len(g.Lons) != 5 || g.Lons[4] != 132 {
+		t.Fatalf("axes = %v x %v", g.Lats, g.Lons)
+	}
+	if _, _, ok := g.Range(); ok {
+		t.Error("new grid: want no values")
+	}
+}
+
+func TestContour_OpenLine(t *testing.T) {
+	g := NewGrid(BBox{MinLat: 0, MinLon: 0, MaxLat: 4, MaxLon: 4}, 1)
+	fillGrid(g, func(_, lon float64) float64 { return lon })
+
+	lines := g.Contour(1.5, nil)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1", len(lines))
+	}
+	line := lines[0]
+	if len(line) != 5 {
+		t.Fatalf("got %d points, want one per latitude", len(line))
+	}
+	for _, p := range line {
+		if math.Abs(p[0]-1.5) > 1e-12 {
+			t.Errorf("point %v off lon 1.5", p)
+		}
+	}
+	if first, last := line[0][1], line[len(line)-1][1]; math.Abs(first-last) != 4 {
+		t.Errorf("line spans lat %v to %v, want the full grid", first, last)
+	}
+}
+
+func TestContour_ClosedLoop(t *testing.T) {
+	g := NewGrid(BBox{MinLat: -3, MinLon: -3, MaxLat: 3, MaxLon: 3}, 0.5)
+	fillGrid(g, func(lat, lon float64) float64 { return math.Hypot(lat, lon) })
+
+	lines := g.Contour(2, nil)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1", len(lines))
+	}
+	line := lines[0]
+	if line[0] != line[len(line)-1] {
+		t.Errorf("loop not closed: %v ... %v", line[0], line[len(line)-1])
+	}
+	for _, p := range line {
+		if r := math.Hypot(p[0], p[1]); math.Abs(r-2) > 0.1 {
+			t.Errorf("point %v at radius %v, want about 2", p, r)
+		}
+	}
+}
+
+func TestContour_SkipsMissingAndUnkeptCells(t *testing.T) {
+	g := NewGrid(BBox{MinLat: 0, MinLon: 0, MaxLat: 4, MaxLon: 4}, 1)
+	fillGrid(g, func(_, lon float64) float64 { return lon })
+	g.Values[2][1] = math.NaN() // Breaks the line at the middle row.
+
+	if lines := g.Contour(1.5, nil); len(lines) != 2 {
+		t.Errorf("NaN node: got %d lines, want 2", len(lines))
+	}
+	if lines := g.Contour(1.5, func(int, int) bool { return false }); len(lines) != 0 {
+		t.Errorf("no kept cells: got %d lines, want 0", len(lines))
+	}
+}
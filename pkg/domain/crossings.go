@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	// crossingScanStep is the sampling step used to bracket crossings. It is
+	// short relative to the quarter period of the fastest common
+	// constituents, so distinct crossings fall in distinct steps.
+	crossingScanStep = 10 * time.Minute
+	// crossingTolerance is the time resolution of refined crossings.
+	crossingTolerance = time.Second
+)
+
+// Crossing directions.
+const (
+	CrossingRising  = "rising"
+	CrossingFalling = "falling"
+)
+
+// Crossing is a time at which the predicted height passes a target height.
+type Crossing struct {
+	Time      time.Time
+	Direction string // CrossingRising or CrossingFalling.
+}
+
+// FindCrossings returns every time in [start, end] at which the predicted
+// height crosses targetM, in chronological order. Crossings are bracketed by
+// sampling the model heights and refined by bisection on the model itself, so
+// their accuracy does not depend on a prediction interval. Heights that only
+// touch the target between two samples are not reported.
+func FindCrossings(start, end time.Time, targetM float64, params PredictionParams) []Crossing {
+	crossings := make([]Crossing, 0)
+	if !start.Before(end) {
+		return crossings
+	}
+	f := func(t time.Time) float64 { return params.Height(t) - targetM }
+
+	t0, f0 := start, f(start)
+	for t0.Before(end) {
+		t1 := t0.Add(crossingScanStep)
+		if t1.After(end) {
+			t1 = end
+		}
+		f1 := f(t1)
+		if (f0 < 0) != (f1 < 0) {
+			direction := CrossingRising
+			if f1 < f0 {
+				direction = CrossingFalling
+			}
+			crossings = append(crossings, Crossing{Time: bisectCrossing(f, t0, t1, f0), Direction: direction})
+		}
+		t0, f0 = t1, f1
+	}
+	return crossings
+}
+
+// bisectCrossing narrows [lo, hi], where f changes sign, to crossingTolerance.
+func bisectCrossing(f func(time.Time) float64, lo, hi time.Time, fLo float64) time.Time {
+	for hi.Sub(lo) > crossingTolerance {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		fMid := f(mid)
+		if (fMid < 0) == (fLo < 0) {
+			lo, fLo = mid, fMid
+		} else {
+			hi = mid
+		}
+	}
+	return lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFindCrossings_SingleConstituent(t *testing.T) {
+	refTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := PredictionParams{
+		Constituents:    []ConstituentParam{{Name: "M2", AmplitudeM: 1.0, SpeedDegPerHr: 28.9841042}},
+		MSL:             0.2,
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   refTime,
+	}
+
+	// h(t) = cos(ωt) + 0.2 crosses 0.7 where cos(ωt) = 0.5, i.e. at ωt = 60° (falling)
+	// and ωt = 300° (rising) in each period.
+	period := 360 / 28.9841042
+	at := func(deg float64) time.Time {
+		return refTime.Add(time.Duration(deg / 28.9841042 * float64(time.Hour)))
+	}
+	crossings := FindCrossings(refTime, refTime.Add(time.Duration(1.5*period*float64(time.Hour))), 0.7, params)
+
+	want := []Crossing{
+		{Time: at(60), Direction: CrossingFalling},
+		{Time: at(300), Direction: CrossingRising},
+		{Time: at(420), Direction: CrossingFalling},
+	}
+	if len(crossings) != len(want) {
+		t.Fatalf("expected %d crossings, got %+v", len(want), crossings)
+	}
+	for i, w := range want {
+		got := crossings[i]
+		if got.Direction != w.Direction || math.Abs(got.Time.Sub(w.Time).Seconds()) > 2 {
+			t.Errorf("crossing %d: got %s %s, want %s %s", i, got.Time, got.Direction, w.Time, w.Direction)
+		}
+	}
+
+	// A target above the highest tide is never crossed.
+	if got := FindCrossings(refTime, refTime.Add(48*time.Hour), 1.5, params); len(got) != 0 {
+		t.Errorf("expected no crossings above high water, got %+v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Current event types.
+const (
+	CurrentSlack    = "slack"
+	CurrentMaxFlood = "max_flood"
+	CurrentMaxEbb   = "max_ebb"
+)
+
+// CurrentParams holds the harmonic parameters of the eastward and northward
+// velocity components of a tidal current (amplitudes in m/s).
+type CurrentParams struct {
+	East, North PredictionParams
+}
+
+// CurrentVelocity is a predicted tidal current. DirectionDeg is the
+// direction the current flows toward, clockwise from true north.
+type CurrentVelocity struct {
+	Time         time.Time
+	EastMS       float64
+	NorthMS      float64
+	SpeedMS      float64
+	DirectionDeg float64
+}
+
+// CurrentEvent is a slack water or a maximum flood or ebb current.
+type CurrentEvent struct {
+	Time     time.Time
+	Type     string // CurrentSlack, CurrentMaxFlood or CurrentMaxEbb.
+	Velocity CurrentVelocity
+}
+
+// At returns the current at time t.
+func (p CurrentParams) At(t time.Time) CurrentVelocity {
+	u := CalculateTideHeight(t, p.East)
+	v := CalculateTideHeight(t, p.North)
+	return CurrentVelocity{
+		Time:         t,
+		EastMS:       u,
+		NorthMS:      v,
+		SpeedMS:      math.Hypot(u, v),
+		DirectionDeg: NormalizePhaseDeg(Rad2Deg(math.Atan2(u, v))),
+	}
+}
+
+// GenerateCurrents predicts currents from start to end (inclusive) at the
+// given interval.
+func GenerateCurrents(start, end time.Time, interval time.Duration, p CurrentParams) []CurrentVelocity {
+	var currents []CurrentVelocity
+	for t := start; !t.After(end); t = t.Add(interval) {
+		currents = append(currents, p.At(t))
+	}
+	return currents
+}
+
+// PrincipalAxisDeg returns the direction of the axis of greatest variance
+// of the currents, clockwise from north in [0, 180). For a reversing
+// current it is the flood-ebb axis, up to its orientation.
+func PrincipalAxisDeg(currents []CurrentVelocity) float64 {
+	if len(currents) == 0 {
+		return 0
+	}
+	var meanU, meanV float64
+	for _, c := range currents {
+		meanU += c.EastMS
+		meanV += c.NorthMS
+	}
+	meanU /= float64(len(currents))
+	meanV /= float64(len(currents))
+	var suu, svv, suv float64
+	for _, c := range currents {
+		du, dv := c.EastMS-meanU, c.NorthMS-meanV
+		suu += du * du
+		svv += dv * dv
+		suv += du * dv
+	}
+	// Angle from north of the major axis of the covariance ellipse.
+	theta := 0.5 * Rad2Deg(math.Atan2(2*suv, svv-suu))
+	return math.Mod(theta+180, 180)
+}
+
+// OrientFlood turns a principal axis (see PrincipalAxisDeg) into the flood
+// direction: the orientation along which the current flows while the tide
+// rises. rising holds the rate of change of the tide at the time of each
+// current.
+func OrientFlood(axisDeg float64, currents []CurrentVelocity, rising []float64) float64 {
+	var corr float64
+	for i, c := range currents {
+		corr += AlongAxis(c, axisDeg) * rising[i]
+	}
+	if corr < 0 {
+		return NormalizePhaseDeg(axisDeg + 180)
+	}
+	return axisDeg
+}
+
+// AlongAxis returns the component of a current along the direction axisDeg.
+func AlongAxis(c CurrentVelocity, axisDeg float64) float64 {
+	a := Deg2Rad(axisDeg)
+	return c.EastMS*math.Sin(a) + c.NorthMS*math.Cos(a)
+}
+
+// FindCurrentEvents returns the slack waters and the maximum flood and ebb
+// currents in [start, end], in chronological order. Flood is the velocity
+// component toward floodDeg and ebb the opposite. Slack water is when that
+// component reverses, solved by bisection on the model; a rotary current
+// may keep some cross-axis speed then. Maxima are the extrema of the
+// component, refined by parabolic interpolation.
+func FindCurrentEvents(start, end time.Time, p CurrentParams, floodDeg float64) []CurrentEvent {
+	events := make([]CurrentEvent, 0)
+	if !start.Before(end) {
+		return events
+	}
+	along := func(t time.Time) float64 { return AlongAxis(p.At(t), floodDeg) }
+
+	var samples []TideLevel
+	for t := start; !t.After(end); t = t.Add(crossingScanStep) {
+		samples = append(samples, TideLevel{Time: t, HeightM: along(t)})
+	}
+	for i := 1; i < len(samples); i++ {
+		prev, cur := samples[i-1], samples[i]
+		if (prev.HeightM < 0) != (cur.HeightM < 0) {
+			t := bisectCrossing(along, prev.Time, cur.Time, prev.HeightM)
+			events = append(events, CurrentEvent{Time: t, Type: CurrentSlack, Velocity: p.At(t)})
+		}
+		if i+1 == len(samples) {
+			continue
+		}
+		next := samples[i+1]
+		var kind string
+		switch {
+		case cur.HeightM > 0 && cur.HeightM > prev.HeightM && cur.HeightM >= next.HeightM:
+			kind = CurrentMaxFlood
+		case cur.HeightM < 0 && cur.HeightM < prev.HeightM && cur.HeightM <= next.HeightM:
+			kind = CurrentMaxEbb
+		default:
+			continue
+		}
+		t, _ := RefineExtremum(prev, cur, next)
+		t = t.Truncate(time.Second)
+		events = append(events, CurrentEvent{Time: t, Type: kind, Velocity: p.At(t)})
+	}
+	return events
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// reversingCurrent returns an M2 current of 1 m/s along the 45° axis,
+// flowing northeast at the reference time, and an M2 tide rising then.
+func reversingCurrent(refTime time.Time) (CurrentParams, PredictionParams) {
+	component := func(amplitude, phase float64) PredictionParams {
+		return PredictionParams{
+			Constituents:    []ConstituentParam{{Name: "M2", AmplitudeM: amplitude, PhaseDeg: phase, SpeedDegPerHr: 28.9841042}},
+			NodalCorrection: &IdentityNodalCorrection{},
+			ReferenceTime:   refTime,
+		}
+	}
+	return CurrentParams{East: component(math.Sqrt2/2, 0), North: component(math.Sqrt2/2, 0)}, component(1, 90)
+}
+
+func TestCurrentParamsAt(t *testing.T) {
+	refTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current, _ := reversingCurrent(refTime)
+
+	c := current.At(refTime)
+	if math.Abs(c.SpeedMS-1) > 1e-9 || math.Abs(c.DirectionDeg-45) > 1e-9 {
+		t.Errorf("at reference: %.4f m/s toward %.2f°, want 1 m/s toward 45°", c.SpeedMS, c.DirectionDeg)
+	}
+	halfPeriod := 180 / 28.9841042 * float64(time.Hour)
+	if c := current.At(refTime.Add(time.Duration(halfPeriod))); math.Abs(c.DirectionDeg-225) > 1e-6 {
+		t.Errorf("half a period later: toward %.2f°, want 225°", c.DirectionDeg)
+	}
+}
+
+func TestPrincipalAxisAndFlood(t *testing.T) {
+	refTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current, tide := reversingCurrent(refTime)
+
+	series := GenerateCurrents(refTime, refTime.Add(25*time.Hour), 10*time.Minute, current)
+	axis := PrincipalAxisDeg(series)
+	if math.Abs(axis-45) > 1e-6 {
+		t.Fatalf("principal axis = %.4f°, want 45°", axis)
+	}
+
+	rising := make([]float64, len(series))
+	for i, c := range series {
+		rising[i] = CalculateTideHeight(c.Time.Add(time.Minute), tide) - CalculateTideHeight(c.Time, tide)
+	}
+	if got := OrientFlood(axis, series, rising); math.Abs(got-45) > 1e-6 {
+		t.Errorf("flood = %.2f°, want 45°", got)
+	}
+	for i := range rising {
+		rising[i] = -rising[i]
+	}
+	if got := OrientFlood(axis, series, rising); math.Abs(got-225) > 1e-6 {
+		t.Errorf("flood with the tide reversed = %.2f°, want 225°", got)
+	}
+}
+
+func TestFindCurrentEvents(t *testing.T) {
+	refTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current, _ := reversingCurrent(refTime)
+	at := func(deg float64) time.Time {
+		return refTime.Add(time.Duration(deg / 28.9841042 * float64(time.Hour)))
+	}
+
+	events := FindCurrentEvents(at(30), at(400), current, 45)
+	want := []struct {
+		deg   float64
+		kind  string
+		speed float64
+	}{
+		{90, CurrentSlack, 0},
+		{180, CurrentMaxEbb, 1},
+		{270, CurrentSlack, 0},
+		{360, CurrentMaxFlood, 1},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.Type != w.kind || math.Abs(got.Time.Sub(at(w.deg)).Seconds()) > 60 {
+			t.Errorf("event %d: got %s %s, want %s %s", i, got.Time, got.Type, at(w.deg), w.kind)
+		}
+		if math.Abs(got.Velocity.SpeedMS-w.speed) > 0.01 {
+			t.Errorf("event %d: speed %.4f m/s, want %.2f", i, got.Velocity.SpeedMS, w.speed)
+		}
+	}
+}
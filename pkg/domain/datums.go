@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// TidalDay is the mean lunar day, over which the higher high and lower
+	// low waters are taken.
+	TidalDay = 24*time.Hour + 50*time.Minute + 28*time.Second
+
+	// datumStep is the synthesis step of tidal datums; extrema are refined
+	// by parabolic interpolation.
+	datumStep = 15 * time.Minute
+)
+
+// TidalDatums are the tidal datums of a location, in meters relative to
+// the prediction datum, and the tidal ranges.
+type TidalDatums struct {
+	HAT  float64 // Highest astronomical tide.
+	MHHW float64 // Mean higher high water.
+	MHW  float64 // Mean high water.
+	MSL  float64 // Mean sea level.
+	MLW  float64 // Mean low water.
+	MLLW float64 // Mean lower low water.
+	LAT  float64 // Lowest astronomical tide.
+	MN   float64 // Mean range of tide: MHW - MLW.
+	GT   float64 // Great diurnal range: MHHW - MLLW.
+}
+
+// datumAccumulator sums heights and extrema over tidal days.
+type datumAccumulator struct {
+	heights, highs, lows, higherHighs, lowerLows runningMean
+	hat, lat                                     float64
+
+	dayEnd          time.Time
+	dayHigh, dayLow float64
+	hasHigh, hasLow bool
+}
+
+type runningMean struct {
+	sum float64
+	n   int
+}
+
+func (m *runningMean) add(v float64) { m.sum += v; m.n++ }
+
+func (m runningMean) value() float64 { return m.sum / float64(m.n) }
+
+// ComputeTidalDatums synthesizes heights over [start, end) and derives
+// the tidal datums: means of all sampled heights (MSL), of all high and
+// low waters, and of the higher high and lower low water of each complete
+// tidal day, and the extreme heights. Over a 19-year epoch, which covers
+// the 18.6-year nodal cycle, HAT and LAT are the astronomical extremes.
+// ok is false when the span holds no complete tidal day.
+func ComputeTidalDatums(start, end time.Time, params PredictionParams) (d TidalDatums, ok bool) {
+	acc := datumAccumulator{hat: math.Inf(-1), lat: math.Inf(1), dayEnd: start.Add(TidalDay)}
+	before := TideLevel{Time: start, HeightM: params.Height(start)}
+	peak := TideLevel{Time: start.Add(datumStep), HeightM: params.Height(start.Add(datumStep))}
+	acc.heights.add(before.HeightM)
+	for t := start.Add(2 * datumStep); t.Before(end); t = t.Add(datumStep) {
+		after := TideLevel{Time: t, HeightM: params.Height(t)}
+		acc.heights.add(peak.HeightM)
+		switch {
+		case peak.HeightM > before.HeightM && peak.HeightM > after.HeightM:
+			at, h := RefineExtremum(before, peak, after)
+			acc.extremum(at, h, true)
+		case peak.HeightM < before.HeightM && peak.HeightM < after.HeightM:
+			at, h := RefineExtremum(before, peak, after)
+			acc.extremum(at, h, false)
+		}
+		before, peak = peak, after
+	}
+	if acc.higherHighs.n == 0 || acc.lowerLows.n == 0 {
+		return TidalDatums{}, false
+	}
+
+	d = TidalDatums{
+		HAT:  acc.hat,
+		MHHW: acc.higherHighs.value(),
+		MHW:  acc.highs.value(),
+		MSL:  acc.heights.value(),
+		MLW:  acc.lows.value(),
+		MLLW: acc.lowerLows.value(),
+		LAT:  acc.lat,
+	}
+	d.MN = d.MHW - d.MLW
+	d.GT = d.MHHW - d.MLLW
+	return d, true
+}
+
+// extremum records a high or low water at t, closing the tidal days that
+// ended before it.
+func (a *datumAccumulator) extremum(t time.Time, h float64, high bool) {
+	for !t.Before(a.dayEnd) {
+		if a.hasHigh {
+			a.higherHighs.add(a.dayHigh)
+		}
+		if a.hasLow {
+			a.lowerLows.add(a.dayLow)
+		}
+		a.hasHigh, a.hasLow = false, false
+		a.dayEnd = a.dayEnd.Add(TidalDay)
+	}
+	if high {
+		a.highs.add(h)
+		a.hat = math.Max(a.hat, h)
+		if !a.hasHigh || h > a.dayHigh {
+			a.dayHigh, a.hasHigh = h, true
+		}
+		return
+	}
+	a.lows.add(h)
+	a.lat = math.Min(a.lat, h)
+	if !a.hasLow || h < a.dayLow {
+		a.dayLow, a.hasLow = h, true
+	}
+}
+
+// Tidal datum names, as selected by the datum parameter of predictions.
+const (
+	DatumHAT  = "HAT"
+	DatumMHHW = "MHHW"
+	DatumMHW  = "MHW"
+	DatumMSL  = "MSL"
+	DatumMLW  = "MLW"
+	DatumMLLW = "MLLW"
+	DatumLAT  = "LAT"
+)
+
+// IsTidalDatum reports whether name is one of the tidal datum names
+// (case-insensitive).
+func IsTidalDatum(name string) bool {
+	switch strings.ToUpper(name) {
+	case DatumHAT, DatumMHHW, DatumMHW, DatumMSL, DatumMLW, DatumMLLW, DatumLAT:
+		return true
+	}
+	return false
+}
+
+// DatumShiftModel refers a base model's heights to a tidal datum by
+// subtracting the datum's level.
+type DatumShiftModel struct {
+	Base   PredictionModel
+	LevelM float64
+}
+
+// Name returns the base model name; the datum is reported separately.
+func (m DatumShiftModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the base height at t above the datum.
+func (m DatumShiftModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	return m.Base.HeightAt(t, params) - m.LevelM
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func datumParams(constituents ...ConstituentParam) PredictionParams {
+	return PredictionParams{
+		Constituents:    constituents,
+		MSL:             0.5,
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// TestComputeTidalDatums_Semidiurnal tests that a pure M2 tide has equal
+// mean and mean higher high waters, at MSL ± amplitude.
+func TestComputeTidalDatums_Semidiurnal(t *testing.T) {
+	params := datumParams(ConstituentParam{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: StandardConstituents["M2"]})
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok := ComputeTidalDatums(start, start.AddDate(0, 0, 60), params)
+	if !ok {
+		t.Fatal("expected datums")
+	}
+	for name, c := range map[string]struct{ got, want float64 }{
+		"HAT": {d.HAT, 1.5}, "MHHW": {d.MHHW, 1.5}, "MHW": {d.MHW, 1.5}, "MSL": {d.MSL, 0.5},
+		"MLW": {d.MLW, -0.5}, "MLLW": {d.MLLW, -0.5}, "LAT": {d.LAT, -0.5}, "MN": {d.MN, 2}, "GT": {d.GT, 2},
+	} {
+		if math.Abs(c.got-c.want) > 1e-3 {
+			t.Errorf("%s = %.4f, want %.4f", name, c.got, c.want)
+		}
+	}
+}
+
+// TestComputeTidalDatums_DiurnalInequality tests that a diurnal
+// constituent separates higher high from mean high water.
+func TestComputeTidalDatums_DiurnalInequality(t *testing.T) {
+	params := datumParams(
+		ConstituentParam{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: StandardConstituents["M2"]},
+		ConstituentParam{Name: "K1", AmplitudeM: 0.3, SpeedDegPerHr: StandardConstituents["K1"]},
+	)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, ok := ComputeTidalDatums(start, start.AddDate(1, 0, 0), params)
+	if !ok {
+		t.Fatal("expected datums")
+	}
+	if !(d.HAT > d.MHHW && d.MHHW > d.MHW && d.MHW > d.MSL && d.MSL > d.MLW && d.MLW > d.MLLW && d.MLLW > d.LAT) {
+		t.Errorf("datums out of order: %+v", d)
+	}
+	if math.Abs(d.HAT-1.8) > 0.01 || math.Abs(d.LAT+0.8) > 0.01 {
+		t.Errorf("HAT, LAT = %.3f, %.3f, want about 1.8, -0.8", d.HAT, d.LAT)
+	}
+	if math.Abs(d.GT-(d.MHHW-d.MLLW)) > 1e-12 || d.GT <= d.MN {
+		t.Errorf("GT = %.3f, MN = %.3f; want GT = MHHW - MLLW > MN", d.GT, d.MN)
+	}
+
+	if _, ok := ComputeTidalDatums(start, start.Add(12*time.Hour), params); ok {
+		t.Error("expected no datums without a complete tidal day")
+	}
+}
+
+func TestIsTidalDatumAndShift(t *testing.T) {
+	for _, name := range []string{"HAT", "mhhw", "MHW", "MSL", "MLW", "MLLW", "lat"} {
+		if !IsTidalDatum(name) {
+			t.Errorf("IsTidalDatum(%q) = false", name)
+		}
+	}
+	for _, name := range []string{"TP", "MN", "GT", ""} {
+		if IsTidalDatum(name) {
+			t.Errorf("IsTidalDatum(%q) = true", name)
+		}
+	}
+
+	params := datumParams(ConstituentParam{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: StandardConstituents["M2"]})
+	model := DatumShiftModel{Base: HarmonicModel{}, LevelM: -1.5}
+	if got, want := model.HeightAt(params.ReferenceTime, params), CalculateTideHeight(params.ReferenceTime, params)+1.5; math.Abs(got-want) > 1e-12 {
+		t.Errorf("shifted height = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// EnsembleMember is one dataset's constituents at a location.
+type EnsembleMember struct {
+	Name         string
+	Constituents []ConstituentParam
+}
+
+// EnsembleModel synthesizes every member with the base model and returns
+// their mean. The member spread is a cheap uncertainty proxy.
+type EnsembleModel struct {
+	Base    PredictionModel
+	Members []EnsembleMember
+}
+
+// Name returns the base model name; members are reported separately.
+func (m EnsembleModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the mean member height at t.
+func (m EnsembleModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	mean, _, _ := m.Spread(t, params)
+	return mean
+}
+
+// Spread returns the mean, minimum and maximum member heights at t.
+func (m EnsembleModel) Spread(t time.Time, params PredictionParams) (mean, lo, hi float64) {
+	if len(m.Members) == 0 {
+		h := m.Base.HeightAt(t, params)
+		return h, h, h
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	var sum float64
+	for _, member := range m.Members {
+		params.Constituents = member.Constituents
+		h := m.Base.HeightAt(t, params)
+		sum += h
+		lo = math.Min(lo, h)
+		hi = math.Max(hi, h)
+	}
+	return sum / float64(len(m.Members)), lo, hi
+}
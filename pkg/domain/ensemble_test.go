@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestEnsembleModel_MeanAndSpread tests the member mean and range.
+func TestEnsembleModel_MeanAndSpread(t *testing.T) {
+	member := func(name string, amplitude float64) EnsembleMember {
+		return EnsembleMember{Name: name, Constituents: []ConstituentParam{
+			{Name: "M2", AmplitudeM: amplitude, PhaseDeg: 0, SpeedDegPerHr: 28.9841042},
+		}}
+	}
+	model := EnsembleModel{
+		Base:    HarmonicModel{},
+		Members: []EnsembleMember{member("a", 1.0), member("b", 1.2), member("c", 0.8)},
+	}
+	params := PredictionParams{
+		MSL:             0.1,
+		NodalCorrection: NewAstronomicalNodalCorrection(),
+		ReferenceTime:   time.Unix(0, 0).UTC(),
+		Model:           model,
+	}
+
+	at := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	heights := make([]float64, len(model.Members))
+	for i, m := range model.Members {
+		p := params
+		p.Constituents = m.Constituents
+		heights[i] = CalculateTideHeight(at, p)
+	}
+
+	mean, lo, hi := model.Spread(at, params)
+	wantMean := (heights[0] + heights[1] + heights[2]) / 3
+	if math.Abs(mean-wantMean) > 1e-12 {
+		t.Errorf("expected mean %g, got %g", wantMean, mean)
+	}
+	if lo != math.Min(heights[0], math.Min(heights[1], heights[2])) || hi != math.Max(heights[0], math.Max(heights[1], heights[2])) {
+		t.Errorf("unexpected range [%g, %g] for %v", lo, hi, heights)
+	}
+	if got := params.Height(at); got != mean {
+		t.Errorf("expected model height %g, got %g", mean, got)
+	}
+}
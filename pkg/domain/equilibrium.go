@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// doodsonArgs holds Doodson multipliers of the mean longitudes (τ, s, h, p, N', p1)
+// and a constant phase offset in degrees.
+type doodsonArgs struct {
+	tau, s, h, p, nPrime, p1 float64
+	offset                   float64
+}
+
+// doodsonNumbers defines the equilibrium arguments V of the standard
+// constituents from the astronomical longitudes. Phase offsets follow
+// Schureman (1958), whose nodal u the built-in corrections use: with
+// T = τ - h + s, e.g., K1 is T + h - 90° and O1 is T - 2s + h + 90°.
+// Reference: Doodson (1921); Schureman (1958) Table 2; Cartwright & Tayler (1971).
+//
+//nolint:gochecknoglobals // Intentional: Read-only constant map.
+var doodsonNumbers = map[string]doodsonArgs{
+	// Semidiurnal.
+	"M2":   {tau: 2},
+	"S2":   {tau: 2, s: 2, h: -2},
+	"N2":   {tau: 2, s: -1, p: 1},
+	"K2":   {tau: 2, s: 2},
+	"2N2":  {tau: 2, s: -2, p: 2},
+	"MU2":  {tau: 2, s: -2, h: 2},
+	"NU2":  {tau: 2, s: -1, h: 2, p: -1},
+	"L2":   {tau: 2, s: 1, p: -1, offset: 180},
+	"LAM2": {tau: 2, s: 1, h: -2, p: 1, offset: 180},
+	"EPS2": {tau: 2, s: -3, h: 2, p: 1},
+	"T2":   {tau: 2, s: 2, h: -3, p1: 1},
+	"R2":   {tau: 2, s: 2, h: -1, p1: -1, offset: 180},
+
+	// Diurnal.
+	"K1":  {tau: 1, s: 1, offset: -90},
+	"O1":  {tau: 1, s: -1, offset: 90},
+	"P1":  {tau: 1, s: 1, h: -2, offset: 90},
+	"Q1":  {tau: 1, s: -2, p: 1, offset: 90},
+	"J1":  {tau: 1, s: 2, p: -1, offset: -90},
+	"M1":  {tau: 1, p: 1, offset: -90},
+	"OO1": {tau: 1, s: 3, offset: -90},
+	"S1":  {tau: 1, s: 1, h: -1},
+
+	// Shallow water (sums of the above).
+	"M4":   {tau: 4},
+	"M6":   {tau: 6},
+	"MK3":  {tau: 3, s: 1, offset: -90},
+	"S4":   {tau: 4, s: 4, h: -4},
+	"MN4":  {tau: 4, s: -1, p: 1},
+	"MS4":  {tau: 4, s: 2, h: -2},
+	"M3":   {tau: 3},
+	"N4":   {tau: 4, s: -2, p: 2},
+	"M8":   {tau: 8},
+	"MKS2": {tau: 2, h: 2},
+
+	// Long period.
+	"Mf":   {s: 2},
+	"Mm":   {s: 1, p: -1},
+	"Ssa":  {h: 2},
+	"Sa":   {h: 1},
+	"MSf":  {s: 2, h: -2},
+	"Mtm":  {s: 3, p: -1},
+	"MSqm": {s: 4, h: -2},
+	"Node": {nPrime: 1},
+}
+
+// meanLongitudes returns s, h, p, N and p1 in degrees at t.
+// Polynomials from Meeus (1998), with T in Julian centuries from J2000.0.
+func meanLongitudes(t time.Time) (s, h, p, n, p1 float64) {
+	T := (float64(t.Unix())/86400.0 - 10957.5) / 36525.0
+	s = 218.3164477 + 481267.88123421*T - 0.0015786*T*T
+	h = 280.46646 + 36000.76983*T + 0.0003032*T*T
+	p = 83.35324 + 4069.01363*T - 0.0103238*T*T
+	n = 125.04452 - 1934.136261*T + 0.0020708*T*T
+	p1 = 282.94 + 1.7192*T
+	return s, h, p, n, p1
+}
+
+// EquilibriumArgument returns the astronomical equilibrium argument V (degrees,
+// in [0, 360)) of the constituent at t, for constituents with known Doodson numbers.
+func EquilibriumArgument(constituent string, t time.Time) (float64, bool) {
+	d, ok := doodsonNumbers[constituent]
+	if !ok {
+		return 0, false
+	}
+	s, h, p, n, p1 := meanLongitudes(t)
+	// τ = 15°/hr·UT + h - s (mean lunar time).
+	hours := float64(t.Unix()%86400) / 3600.0
+	tau := 15.0*hours + 180.0 + h - s
+	// Doodson's fifth argument is N' = -N.
+	v := d.tau*tau + d.s*s + d.h*h + d.p*p + d.nPrime*(-n) + d.p1*p1 + d.offset
+	v = math.Mod(v, 360.0)
+	if v < 0 {
+		v += 360.0
+	}
+	return v, true
+}
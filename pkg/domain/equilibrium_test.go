@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestEquilibriumArgument_AdvancesAtSpeed tests that V advances at the constituent speed.
+func TestEquilibriumArgument_AdvancesAtSpeed(t *testing.T) {
+	start := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	const hours = 24 * 10
+
+	for name := range doodsonNumbers {
+		speed, ok := GetConstituentSpeed(name)
+		if !ok {
+			t.Fatalf("%s: missing speed", name)
+		}
+		v0, ok := EquilibriumArgument(name, start)
+		if !ok {
+			t.Fatalf("%s: missing equilibrium argument", name)
+		}
+		v1, _ := EquilibriumArgument(name, start.Add(hours*time.Hour))
+
+		got := math.Mod(v1-v0+720.0, 360.0)
+		want := math.Mod(speed*hours, 360.0)
+		diff := math.Abs(got - want)
+		if diff > 180 {
+			diff = 360 - diff
+		}
+		if diff > 0.01 {
+			t.Errorf("%s: V advanced %.4f°, want %.4f°", name, got, want)
+		}
+	}
+}
+
+// TestLongPeriodNodalFactors tests nodal factors of the added long-period constituents.
+func TestLongPeriodNodalFactors(t *testing.T) {
+	nc := &AstronomicalNodalCorrection{}
+	for _, name := range []string{"MSf", "Mtm", "MSqm", "Node"} {
+		f, u := nc.GetFactors(name, 0)
+		if f <= 0 || math.IsNaN(f) || math.IsNaN(u) {
+			t.Errorf("%s: invalid factors f=%.4f u=%.4f", name, f, u)
+		}
+	}
+
+	// Mtm and MSqm share the Mf nodal modulation.
+	fMf, uMf := nc.GetFactors("Mtm", 0)
+	fMSqm, uMSqm := nc.GetFactors("MSqm", 0)
+	if fMf != fMSqm || uMf != uMSqm {
+		t.Errorf("expected Mtm and MSqm to share factors, got (%.4f, %.4f) vs (%.4f, %.4f)", fMf, uMf, fMSqm, uMSqm)
+	}
+}
+
+func TestFES2014NodalFactors(t *testing.T) {
+	nc := &AstronomicalNodalCorrection{}
+	const hours = 24 * 6000 // mid-nodal cycle, far from f = 1 and u = 0
+	fM2, uM2 := nc.GetFactors("M2", hours)
+	for _, name := range []string{"2N2", "MU2", "NU2", "LAM2"} {
+		if f, u := nc.GetFactors(name, hours); f != fM2 || u != uM2 {
+			t.Errorf("%s: got (%.4f, %.4f), want the M2 factors (%.4f, %.4f)", name, f, u, fM2, uM2)
+		}
+	}
+	if f, u := nc.GetFactors("N4", hours); math.Abs(f-fM2*fM2) > 1e-9 || math.Abs(u-2*uM2) > 1e-9 {
+		t.Errorf("N4: got (%.4f, %.4f), want M2 squared (%.4f, %.4f)", f, u, fM2*fM2, 2*uM2)
+	}
+	for _, name := range []string{"T2", "R2", "S1"} {
+		if f, u := nc.GetFactors(name, hours); f != 1 || u != 0 {
+			t.Errorf("%s: got (%.4f, %.4f), want no nodal modulation", name, f, u)
+		}
+	}
+	for _, name := range []string{"L2", "M1", "J1", "OO1", "M3", "M8", "MKS2"} {
+		if f, u := nc.GetFactors(name, hours); f <= 0 || math.IsNaN(f) || math.IsNaN(u) {
+			t.Errorf("%s: invalid factors f=%.4f u=%.4f", name, f, u)
+		}
+	}
+}
+
+// TestEquilibriumArgument_Schureman tests V against relations of
+// Schureman's arguments: S2 is 2T, zero at 00:00 and 12:00 UT, and
+// compound constituents are the sums of their components.
+func TestEquilibriumArgument_Schureman(t *testing.T) {
+	at := time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)
+	v := func(name string, t time.Time) float64 {
+		x, _ := EquilibriumArgument(name, t)
+		return x
+	}
+	angleDiff := func(a, b float64) float64 {
+		d := math.Mod(math.Abs(a-b), 360)
+		return math.Min(d, 360-d)
+	}
+
+	for _, tt := range []time.Time{at, at.Add(12 * time.Hour)} {
+		if s2 := v("S2", tt); angleDiff(s2, 0) > 1e-6 {
+			t.Errorf("V(S2) at %s = %.6f°, want 0", tt.Format("15:04"), s2)
+		}
+	}
+	relations := []struct {
+		name      string
+		got, want float64
+	}{
+		{"K1 + O1 = M2", v("K1", at) + v("O1", at), v("M2", at)},
+		{"M2 + K1 = MK3", v("M2", at) + v("K1", at), v("MK3", at)},
+		{"M2 + S2 = MS4", v("M2", at) + v("S2", at), v("MS4", at)},
+		{"M2 + N2 = MN4", v("M2", at) + v("N2", at), v("MN4", at)},
+		{"3 M2 = M6", 3 * v("M2", at), v("M6", at)},
+		{"K1 - P1 = Ssa - 180", v("K1", at) - v("P1", at), v("Ssa", at) - 180},
+	}
+	for _, r := range relations {
+		if d := angleDiff(r.got, r.want); d > 1e-6 {
+			t.Errorf("%s: off by %.6f°", r.name, d)
+		}
+	}
+}
+
+// TestGetEquilibriumArgument_PrefersAstronomical tests that standard
+// constituents do not take the placeholder V0 of a coefficient file.
+func TestGetEquilibriumArgument_PrefersAstronomical(t *testing.T) {
+	ref := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	nc := &AstronomicalNodalCorrection{coeffs: &NodalCoeffSet{ByName: map[string]NodalCoeff{
+		"M2":  {Name: "M2"},
+		"XYZ": {Name: "XYZ", V0: 42},
+	}}}
+	nc.SetReferenceTime(ref)
+
+	want, _ := EquilibriumArgument("M2", ref)
+	if got := nc.GetEquilibriumArgument("M2", 0); got != want {
+		t.Errorf("V(M2) = %.4f°, want %.4f°", got, want)
+	}
+	if got := nc.GetEquilibriumArgument("XYZ", 0); got != 42 {
+		t.Errorf("V(XYZ) = %.4f°, want the file's 42°", got)
+	}
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+// ErrOutOfCoverage indicates a location where the tidal model has no data
+// (e.g., on land or outside the model domain).
+var ErrOutOfCoverage = errors.New("location outside model coverage")
+
+// ErrConstituentUnavailable indicates a constituent the dataset has no
+// grid for.
+var ErrConstituentUnavailable = errors.New("constituent not in dataset")
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// ArchivedLevel is an archived water level observation. Synthetic levels were
+// not observed but filled from a harmonic prediction to close a gap.
+type ArchivedLevel struct {
+	Time      time.Time
+	HeightM   float64
+	Synthetic bool
+}
+
+// Gap is a run of consecutive missing samples in a regularly spaced series.
+type Gap struct {
+	Start   time.Time `json:"start"`   // First missing sample.
+	End     time.Time `json:"end"`     // Last missing sample.
+	Missing int       `json:"missing"` // Number of missing samples.
+}
+
+// FindGaps returns the runs of missing samples in [start, end] for a series
+// expected every step. Expected sample times are aligned to step (e.g., the top
+// of the hour). levels need not be sorted.
+func FindGaps(levels []TideLevel, start, end time.Time, step time.Duration) []Gap {
+	gaps := make([]Gap, 0)
+	if step <= 0 || end.Before(start) {
+		return gaps
+	}
+
+	present := make(map[int64]bool, len(levels))
+	for _, l := range levels {
+		present[l.Time.Unix()] = true
+	}
+
+	first := start.Truncate(step)
+	if first.Before(start) {
+		first = first.Add(step)
+	}
+
+	var current *Gap
+	for t := first; !t.After(end); t = t.Add(step) {
+		if present[t.Unix()] {
+			if current != nil {
+				gaps = append(gaps, *current)
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			current = &Gap{Start: t}
+		}
+		current.End = t
+		current.Missing++
+	}
+	if current != nil {
+		gaps = append(gaps, *current)
+	}
+	return gaps
+}
+
+// MissingTimes expands gaps into the individual missing sample times.
+func MissingTimes(gaps []Gap, step time.Duration) []time.Time {
+	out := make([]time.Time, 0)
+	for _, g := range gaps {
+		for t := g.Start; !t.After(g.End); t = t.Add(step) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
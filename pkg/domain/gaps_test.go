@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFindGaps tests detection of interior, leading and trailing gaps.
+func TestFindGaps(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	levels := []TideLevel{
+		{Time: start.Add(1 * time.Hour)},
+		{Time: start.Add(2 * time.Hour)},
+		{Time: start.Add(5 * time.Hour)},
+	}
+
+	gaps := FindGaps(levels, start, start.Add(6*time.Hour), time.Hour)
+	want := []Gap{
+		{Start: start, End: start, Missing: 1},
+		{Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour), Missing: 2},
+		{Start: start.Add(6 * time.Hour), End: start.Add(6 * time.Hour), Missing: 1},
+	}
+	if len(gaps) != len(want) {
+		t.Fatalf("expected %d gaps, got %d: %+v", len(want), len(gaps), gaps)
+	}
+	for i := range want {
+		if !gaps[i].Start.Equal(want[i].Start) || !gaps[i].End.Equal(want[i].End) || gaps[i].Missing != want[i].Missing {
+			t.Errorf("gap %d: expected %+v, got %+v", i, want[i], gaps[i])
+		}
+	}
+
+	if n := len(MissingTimes(gaps, time.Hour)); n != 4 {
+		t.Errorf("expected 4 missing times, got %d", n)
+	}
+}
+
+// TestFindGaps_AlignsToStep tests that an unaligned start skips to the next step.
+func TestFindGaps_AlignsToStep(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC)
+	gaps := FindGaps(nil, start, start.Add(2*time.Hour), time.Hour)
+	if len(gaps) != 1 || gaps[0].Missing != 2 {
+		t.Fatalf("expected one gap of 2 samples, got %+v", gaps)
+	}
+	if gaps[0].Start.Minute() != 0 {
+		t.Errorf("expected gap to start on the hour, got %s", gaps[0].Start)
+	}
+}
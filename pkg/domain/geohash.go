@@ -0,0 +1,74 @@
+package domain
+
+import "strings"
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash returns the geohash of a location at the given precision
+// (number of characters). Precision 4 cells are roughly 39 x 20 km,
+// precision 6 cells roughly 1.2 x 0.6 km.
+func EncodeGeohash(lat, lon float64, precision int) string {
+	if precision <= 0 {
+		return ""
+	}
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	var sb strings.Builder
+	sb.Grow(precision)
+	bit, ch := 0, 0
+	even := true // Longitude bits come first.
+	for sb.Len() < precision {
+		r, v := &latRange, lat
+		if even {
+			r, v = &lonRange, lon
+		}
+		mid := (r[0] + r[1]) / 2
+		ch <<= 1
+		if v >= mid {
+			ch |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+
+		bit++
+		if bit == 5 {
+			sb.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// DecodeGeohash returns the center of a geohash cell. ok is false for
+// empty hashes or characters outside the geohash alphabet.
+func DecodeGeohash(hash string) (lat, lon float64, ok bool) {
+	if hash == "" {
+		return 0, 0, false
+	}
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		ch := strings.IndexByte(geohashAlphabet, hash[i])
+		if ch < 0 {
+			return 0, 0, false
+		}
+		for bit := 4; bit >= 0; bit-- {
+			r := &latRange
+			if even {
+				r = &lonRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if ch&(1<<bit) != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	return (latRange[0] + latRange[1]) / 2, (lonRange[0] + lonRange[1]) / 2, true
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+// TestEncodeGeohash tests encoding against published reference hashes.
+func TestEncodeGeohash(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{42.605, -5.603, 5, "ezs42"},
+		{-33.8688, 151.2093, 5, "r3gx2"},
+		{0, 0, 1, "s"},
+		{35.6544, 139.7447, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := EncodeGeohash(tt.lat, tt.lon, tt.precision); got != tt.want {
+			t.Errorf("EncodeGeohash(%v, %v, %d) = %q, want %q", tt.lat, tt.lon, tt.precision, got, tt.want)
+		}
+	}
+}
+
+// TestDecodeGeohash tests that decoding returns the cell center.
+func TestDecodeGeohash(t *testing.T) {
+	lat, lon, ok := DecodeGeohash("ezs42")
+	if !ok {
+		t.Fatal("expected ezs42 to decode")
+	}
+	if math.Abs(lat-42.605) > 0.03 || math.Abs(lon-(-5.603)) > 0.03 {
+		t.Errorf("expected center near (42.605, -5.603), got (%v, %v)", lat, lon)
+	}
+	if got := EncodeGeohash(lat, lon, 5); got != "ezs42" {
+		t.Errorf("expected center to re-encode to ezs42, got %q", got)
+	}
+
+	for _, bad := range []string{"", "ezs4a"} {
+		if _, _, ok := DecodeGeohash(bad); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// FitConstituents is the default constituent list of harmonic fits to
+// station observations.
+//
+//nolint:gochecknoglobals // Intentional: shared default of jma-harmonics and scheduled recalibration.
+var FitConstituents = []string{"M2", "S2", "N2", "K2", "K1", "O1", "P1", "Q1", "M4", "MS4", "MN4", "M6", "S4", "Mf", "Mm", "Ssa", "Sa"}
+
+// fitReferenceTime is the epoch of the fitted phases, as used by the JMA
+// station overrides.
+//
+//nolint:gochecknoglobals // Intentional: fixed epoch of station override phases.
+var fitReferenceTime = time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// HarmonicFit is a least-squares fit of constituents to observed heights.
+type HarmonicFit struct {
+	// InterceptM is the fitted mean level, in the datum of the observations.
+	InterceptM float64
+	// Constituents hold phases in the convention of the station overrides,
+	// relative to the station's longitude.
+	Constituents    []ConstituentParam
+	Samples         int
+	RMSEM           float64
+	MaxAbsResidualM float64
+}
+
+// FitHarmonics fits a mean level and the named constituents, with
+// astronomical nodal factors, to samples at a station at longitude lon.
+func FitHarmonics(samples []TideLevel, lon float64, names []string) (*HarmonicFit, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples to fit")
+	}
+	speeds := make([]float64, len(names))
+	for i, name := range names {
+		speed, ok := GetConstituentSpeed(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown constituent: %s", name)
+		}
+		speeds[i] = speed
+	}
+
+	nodal := NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(fitReferenceTime)
+	paramCount := 1 + len(names)*2
+
+	normal := make([][]float64, paramCount)
+	for i := range normal {
+		normal[i] = make([]float64, paramCount)
+	}
+	rhs := make([]float64, paramCount)
+
+	design := func(s TideLevel) []float64 {
+		deltaHours := s.Time.Sub(fitReferenceTime).Hours()
+		features := make([]float64, paramCount)
+		features[0] = 1
+		idx := 1
+		for i, name := range names {
+			f, u := nodal.GetFactors(name, deltaHours)
+			thetaRad := Deg2Rad(speeds[i]*deltaHours + lon + u)
+			features[idx] = f * math.Cos(thetaRad)
+			features[idx+1] = f * math.Sin(thetaRad)
+			idx += 2
+		}
+		return features
+	}
+
+	for _, s := range samples {
+		features := design(s)
+		for i := 0; i < paramCount; i++ {
+			rhs[i] += features[i] * s.HeightM
+			for j := 0; j <= i; j++ {
+				normal[i][j] += features[i] * features[j]
+			}
+		}
+	}
+	for i := 0; i < paramCount; i++ {
+		for j := 0; j < i; j++ {
+			normal[j][i] = normal[i][j]
+		}
+	}
+
+	coeffs, err := solveSPD(normal, rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	fit := &HarmonicFit{InterceptM: coeffs[0], Samples: len(samples)}
+	var sumSq float64
+	for _, s := range samples {
+		var fitted float64
+		for i, f := range design(s) {
+			fitted += coeffs[i] * f
+		}
+		r := s.HeightM - fitted
+		sumSq += r * r
+		fit.MaxAbsResidualM = math.Max(fit.MaxAbsResidualM, math.Abs(r))
+	}
+	fit.RMSEM = math.Sqrt(sumSq / float64(len(samples)))
+
+	fit.Constituents = make([]ConstituentParam, len(names))
+	for i, name := range names {
+		c, s := coeffs[1+2*i], coeffs[2+2*i]
+		fit.Constituents[i] = ConstituentParam{
+			Name:          name,
+			AmplitudeM:    math.Hypot(c, s),
+			PhaseDeg:      math.Mod(Rad2Deg(math.Atan2(s, c))+360, 360),
+			SpeedDegPerHr: speeds[i],
+		}
+	}
+	return fit, nil
+}
+
+// OverridePhase converts a Greenwich phase lag G (degrees), as published
+// with the V+u convention (e.g., by NOAA CO-OPS or in IHO constituent
+// lists), into the phase φ of a station override at longitude lon, such
+// that f A cos(ωΔt + lon + u − φ), with Δt from the override epoch, equals
+// f A cos(V + u − G): φ = G + lon − V(epoch). ok is false for constituents
+// without a built-in equilibrium argument.
+func OverridePhase(name string, greenwichDeg, lon float64) (phaseDeg float64, ok bool) {
+	v, ok := EquilibriumArgument(name, fitReferenceTime)
+	if !ok {
+		return 0, false
+	}
+	return math.Mod(math.Mod(greenwichDeg+lon-v, 360)+360, 360), true
+}
+
+// solveSPD solves a linear system Ax = b where A is a symmetric positive-definite matrix,
+// using Cholesky decomposition. The input matrix 'mat' must be square, symmetric, and positive-definite.
+// Returns the solution vector x, or an error if the matrix is not positive-definite.
+func solveSPD(mat [][]float64, rhs []float64) ([]float64, error) {
+	n := len(rhs)
+	L := make([][]float64, n)
+	for i := range L {
+		L[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := mat[i][j]
+			for k := 0; k < j; k++ {
+				sum -= L[i][k] * L[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, fmt.Errorf("matrix not positive definite")
+				}
+				L[i][j] = math.Sqrt(sum)
+			} else {
+				L[i][j] = sum / L[j][j]
+			}
+		}
+	}
+
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := rhs[i]
+		for k := 0; k < i; k++ {
+			sum -= L[i][k] * y[k]
+		}
+		y[i] = sum / L[i][i]
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < n; k++ {
+			sum -= L[k][i] * x[k]
+		}
+		x[i] = sum / L[i][i]
+	}
+	return x, nil
+}
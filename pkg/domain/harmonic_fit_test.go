@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFitHarmonics_RecoversConstituents(t *testing.T) {
+	const lon = 139.8
+	want := []ConstituentParam{
+		{Name: "M2", AmplitudeM: 0.5, PhaseDeg: 150},
+		{Name: "K1", AmplitudeM: 0.2, PhaseDeg: 300},
+	}
+	nodal := NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(fitReferenceTime)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var samples []TideLevel
+	for h := 0; h < 60*24; h++ {
+		tm := start.Add(time.Duration(h) * time.Hour)
+		dh := tm.Sub(fitReferenceTime).Hours()
+		height := 1.2
+		for _, c := range want {
+			speed, _ := GetConstituentSpeed(c.Name)
+			f, u := nodal.GetFactors(c.Name, dh)
+			height += f * c.AmplitudeM * math.Cos(Deg2Rad(speed*dh+lon+u-c.PhaseDeg))
+		}
+		samples = append(samples, TideLevel{Time: tm, HeightM: height})
+	}
+
+	fit, err := FitHarmonics(samples, lon, []string{"M2", "K1"})
+	if err != nil {
+		t.Fatalf("FitHarmonics: %v", err)
+	}
+	if math.Abs(fit.InterceptM-1.2) > 1e-6 {
+		t.Errorf("intercept = %v, want 1.2", fit.InterceptM)
+	}
+	if fit.Samples != len(samples) || fit.RMSEM > 1e-6 {
+		t.Errorf("samples = %d, rmse = %v; want %d, ~0", fit.Samples, fit.RMSEM, len(samples))
+	}
+	for i, c := range fit.Constituents {
+		if c.Name != want[i].Name || math.Abs(c.AmplitudeM-want[i].AmplitudeM) > 1e-6 || math.Abs(c.PhaseDeg-want[i].PhaseDeg) > 1e-4 {
+			t.Errorf("constituent %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+}
+
+func TestFitHarmonics_Errors(t *testing.T) {
+	if _, err := FitHarmonics(nil, 0, []string{"M2"}); err == nil {
+		t.Error("expected error for no samples")
+	}
+	samples := []TideLevel{{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), HeightM: 1}}
+	if _, err := FitHarmonics(samples, 0, []string{"XX9"}); err == nil {
+		t.Error("expected error for unknown constituent")
+	}
+	// One sample cannot determine a constituent.
+	if _, err := FitHarmonics(samples, 0, []string{"M2"}); err == nil {
+		t.Error("expected error for a singular fit")
+	}
+}
+
+// TestOverridePhase tests that an override with the converted phase
+// predicts the heights of the Greenwich phase lag under the V+u convention.
+func TestOverridePhase(t *testing.T) {
+	const lon = -122.4
+	const greenwich = 200.0
+	nodal := NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(fitReferenceTime)
+	for _, name := range []string{"M2", "K1", "O1", "Mf"} {
+		phase, ok := OverridePhase(name, greenwich, lon)
+		if !ok {
+			t.Fatalf("%s: no equilibrium argument", name)
+		}
+		speed, _ := GetConstituentSpeed(name)
+		override := PredictionParams{
+			Constituents:    []ConstituentParam{{Name: name, AmplitudeM: 1, PhaseDeg: phase, SpeedDegPerHr: speed}},
+			Longitude:       lon,
+			NodalCorrection: nodal,
+			ReferenceTime:   fitReferenceTime,
+			PhaseConvention: PhaseConvFESGreenwich,
+		}
+		published := override
+		published.Constituents = []ConstituentParam{{Name: name, AmplitudeM: 1, PhaseDeg: greenwich, SpeedDegPerHr: speed}}
+		published.PhaseConvention = PhaseConvVu
+		for _, at := range []time.Time{fitReferenceTime, time.Date(2025, 6, 1, 7, 30, 0, 0, time.UTC)} {
+			if got, want := CalculateTideHeight(at, override), CalculateTideHeight(at, published); math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s at %s: override height %.6f, want %.6f", name, at.Format(time.RFC3339), got, want)
+			}
+		}
+	}
+	if _, ok := OverridePhase("XYZ", greenwich, lon); ok {
+		t.Error("OverridePhase(XYZ) ok, want false")
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// julianYear is the year of land motion rates.
+const julianYear = 365.25 * 24 * time.Hour
+
+// LandMotion is the vertical land motion at a location, e.g., glacial
+// isostatic adjustment (GIA) or subsidence from GNSS and InSAR.
+type LandMotion struct {
+	RateMMPerYr float64   // Vertical rate, positive for uplift.
+	Epoch       time.Time // Reference epoch of the tidal datum.
+}
+
+// OffsetM returns the change of relative sea level at t caused by land
+// motion since the epoch: positive (higher water) where land subsides.
+// Times before the epoch are not corrected.
+func (m LandMotion) OffsetM(t time.Time) float64 {
+	dt := t.Sub(m.Epoch)
+	if dt <= 0 {
+		return 0
+	}
+	return -m.RateMMPerYr / 1000 * float64(dt) / float64(julianYear)
+}
+
+// LandMotionModel predicts relative sea level: a base model's heights
+// plus the land motion since the datum epoch.
+type LandMotionModel struct {
+	Base   PredictionModel
+	Motion LandMotion
+}
+
+// Name returns the base model name; land motion is reported separately.
+func (m LandMotionModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the base height at t plus the land motion offset.
+func (m LandMotionModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	return m.Base.HeightAt(t, params) + m.Motion.OffsetM(t)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestLandMotionModel_SubsidenceRaisesFutureHeights tests that subsiding
+// land raises relative sea level after the epoch only.
+func TestLandMotionModel_SubsidenceRaisesFutureHeights(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := PredictionParams{MSL: 1}
+	m := LandMotionModel{Base: HarmonicModel{}, Motion: LandMotion{RateMMPerYr: -4, Epoch: epoch}}
+
+	if m.Name() != (HarmonicModel{}).Name() {
+		t.Errorf("Name() = %q, want the base model name", m.Name())
+	}
+	for _, tc := range []struct {
+		t    time.Time
+		want float64
+	}{
+		{epoch.AddDate(-5, 0, 0), 1},
+		{epoch, 1},
+		{epoch.Add(50 * julianYear), 1.2},
+	} {
+		if got := m.HeightAt(tc.t, params); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("HeightAt(%s) = %v, want %v", tc.t.Format(time.DateOnly), got, tc.want)
+		}
+	}
+
+	uplift := LandMotion{RateMMPerYr: 10, Epoch: epoch}
+	if got := uplift.OffsetM(epoch.Add(10 * julianYear)); math.Abs(got+0.1) > 1e-9 {
+		t.Errorf("uplift offset = %v, want -0.1", got)
+	}
+}
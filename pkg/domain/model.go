@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PredictionModel computes tide heights from resolved prediction parameters.
+// The harmonic sum is the default; alternative models (e.g., the response
+// method or a residual correction learned from observations) implement this
+// interface and are registered under a key selectable by configuration.
+type PredictionModel interface {
+	// Name identifies the model and its version in response metadata.
+	Name() string
+	// HeightAt returns the height in meters at t.
+	HeightAt(t time.Time, params PredictionParams) float64
+}
+
+// AttributedModel is implemented by models that credit their training data
+// in response metadata.
+type AttributedModel interface {
+	Attribution() string
+}
+
+// HarmonicModelKey selects the harmonic sum.
+const HarmonicModelKey = "harmonic"
+
+// HarmonicModel is the harmonic sum of CalculateTideHeight.
+type HarmonicModel struct{}
+
+// Name returns "harmonic_v0".
+func (HarmonicModel) Name() string { return "harmonic_v0" }
+
+// HeightAt returns the harmonic sum at t.
+func (HarmonicModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	return CalculateTideHeight(t, params)
+}
+
+//nolint:gochecknoglobals // Intentional: registry of selectable models.
+var (
+	predictionModelsMu sync.RWMutex
+	predictionModels   = map[string]PredictionModel{HarmonicModelKey: HarmonicModel{}}
+)
+
+// RegisterPredictionModel makes a model selectable under key, replacing any
+// model registered under the same key.
+func RegisterPredictionModel(key string, m PredictionModel) {
+	predictionModelsMu.Lock()
+	defer predictionModelsMu.Unlock()
+	predictionModels[strings.ToLower(key)] = m
+}
+
+// LookupPredictionModel returns the model registered under key.
+func LookupPredictionModel(key string) (PredictionModel, error) {
+	predictionModelsMu.RLock()
+	defer predictionModelsMu.RUnlock()
+	if m, ok := predictionModels[strings.ToLower(key)]; ok {
+		return m, nil
+	}
+	keys := make([]string, 0, len(predictionModels))
+	for k := range predictionModels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return nil, fmt.Errorf("unknown prediction model %q (available: %s)", key, strings.Join(keys, ", "))
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// offsetModel adds a constant to the harmonic sum.
+type offsetModel struct{ offsetM float64 }
+
+func (offsetModel) Name() string { return "offset_test" }
+
+func (m offsetModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	return CalculateTideHeight(t, params) + m.offsetM
+}
+
+func TestPredictionModelSelection(t *testing.T) {
+	params := PredictionParams{
+		Constituents: []ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: 28.9841042}},
+		MSL:          0.5,
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	harmonic := GeneratePredictions(start, start.Add(time.Hour), 30*time.Minute, params)
+
+	RegisterPredictionModel("Offset", offsetModel{offsetM: 0.25})
+	m, err := LookupPredictionModel("offset")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	params.Model = m
+	corrected := GeneratePredictions(start, start.Add(time.Hour), 30*time.Minute, params)
+	for i := range harmonic {
+		if got := corrected[i].HeightM - harmonic[i].HeightM; got < 0.2499 || got > 0.2501 {
+			t.Errorf("point %d: model offset %v, want 0.25", i, got)
+		}
+	}
+
+	if m, err := LookupPredictionModel(HarmonicModelKey); err != nil || m.Name() != "harmonic_v0" {
+		t.Errorf("expected the built-in harmonic model, got %v, %v", m, err)
+	}
+	if _, err := LookupPredictionModel("response"); err == nil {
+		t.Error("expected error for an unregistered model")
+	}
+}
@@ -1,26 +1,52 @@
 package domain
 
-import "math"
+import (
+	"math"
+	"time"
+)
 
 // AstronomicalNodalCorrection implements nodal corrections based on astronomical arguments.
 // Based on Schureman (1958) and Foreman (1977).
 type AstronomicalNodalCorrection struct {
-	coeffs *NodalCoeffSet
+	coeffs    *NodalCoeffSet
+	reference *time.Time // Epoch at which equilibrium arguments are evaluated.
 }
 
 // NewAstronomicalNodalCorrection creates a nodal correction calculator.
 func NewAstronomicalNodalCorrection() *AstronomicalNodalCorrection {
 	nc := &AstronomicalNodalCorrection{}
-	if set, err := LoadNodalCoeffSetFromEnv(); err == nil {
+	if set, err := DefaultNodalCoeffSet(); err == nil {
 		nc.coeffs = set
 	}
 	return nc
 }
 
-// GetFactors returns the nodal correction amplitude factor (f) and phase correction (u) in degrees.
+// CoeffsVersion identifies the nodal coefficients in use: the coefficient
+// file version and digest, or "builtin" when no file was loaded.
+func (n *AstronomicalNodalCorrection) CoeffsVersion() string {
+	if n.coeffs == nil {
+		return "builtin"
+	}
+	digest := n.coeffs.Digest
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return "astro_coeffs:" + n.coeffs.Version + ":" + digest
+}
+
+// SetReferenceTime sets the prediction reference epoch, enabling astronomical
+// equilibrium arguments for constituents with built-in Doodson numbers.
+// The times passed to GetFactors are then hours since this epoch rather
+// than since the Unix epoch.
+func (n *AstronomicalNodalCorrection) SetReferenceTime(t time.Time) {
+	n.reference = &t
+}
+
+// GetFactors returns the nodal correction amplitude factor (f) and phase correction (u) in degrees
+// at t hours since the reference epoch (the Unix epoch when none is set).
 func (n *AstronomicalNodalCorrection) GetFactors(constituent string, t float64) (f, u float64) {
 	// Calculate astronomical arguments at time t.
-	args := n.calculateAstronomicalArguments(t)
+	args := n.calculateAstronomicalArguments(n.unixHours(t))
 
 	// Use external coefficients if available (Fourier series in N).
 	//nolint:nestif // Nodal correction logic with fallback handling.
@@ -44,19 +70,7 @@ func (n *AstronomicalNodalCorrection) GetFactors(constituent string, t float64)
 
 	// Use built-in nonlinear coefficients (pyTMD-derived) if available.
 	if coeff, ok := builtInNonlinearCoeffs[constituent]; ok {
-		Nrad := Deg2Rad(args.N)
-		// term1 = sum a_k sin(kN), term2 = b0 + sum b_k cos(kN)
-		term1 := 0.0
-		for k, a := range coeff.term1Sin {
-			term1 += a * math.Sin(float64(k)*Nrad)
-		}
-		term2 := coeff.term2Const
-		for k, b := range coeff.term2Cos {
-			term2 += b * math.Cos(float64(k)*Nrad)
-		}
-		f = math.Sqrt(term1*term1 + term2*term2)
-		u = Rad2Deg(math.Atan2(term1, term2))
-		return f, u
+		return coeff.eval(args.N)
 	}
 
 	// Get nodal corrections for each constituent.
@@ -77,16 +91,52 @@ func (n *AstronomicalNodalCorrection) GetFactors(constituent string, t float64)
 		return n.getP1Factors(args)
 	case "Q1":
 		return n.getQ1Factors(args)
+	case "2N2", "MU2", "NU2", "LAM2", "EPS2":
+		// Lunar semidiurnals modulated like M2.
+		return n.GetFactors("M2", t)
+	case "T2", "R2", "S1":
+		// Solar constituents have no nodal correction.
+		return 1.0, 0.0
+	case "L2":
+		return n.getL2Factors(args)
+	case "M1":
+		return n.getM1Factors(args)
+	case "M3":
+		return n.overtideFactors(1.5, t)
+	case "N4":
+		// Overtide of N2, modulated like M2.
+		return n.overtideFactors(2, t)
+	case "M8":
+		return n.overtideFactors(4, t)
+	case "MKS2":
+		// MKS2 = M2 + K2 - S2.
+		fM2, uM2 := n.GetFactors("M2", t)
+		fK2, uK2 := n.GetFactors("K2", t)
+		return fM2 * fK2, uM2 + uK2
+	case "Mtm", "MSqm":
+		return n.getMfFactors(args)
+	case "MSf":
+		return n.getMSfFactors(args)
+	case "Node":
+		// Equilibrium node tide: the 18.6-year modulation is the constituent itself.
+		return 1.0, 0.0
 	default:
 		// For unknown constituents, return identity (no correction).
 		return 1.0, 0.0
 	}
 }
 
-// GetEquilibriumArgument returns an approximate equilibrium argument V (degrees)
-// for the given constituent at time t (hours since Unix epoch).
-// Placeholder returns 0 until the full astronomical series is integrated.
+// GetEquilibriumArgument returns the equilibrium argument V (degrees) of
+// the given constituent at the reference epoch; predictions advance it at
+// the constituent speed, so t (hours since the epoch) is not used.
+// Standard constituents use V from the astronomical longitudes when the
+// reference epoch is set; others use the coefficient-file V0, else 0.
 func (n *AstronomicalNodalCorrection) GetEquilibriumArgument(constituent string, _ float64) float64 {
+	if n.reference != nil {
+		if v, ok := EquilibriumArgument(constituent, *n.reference); ok {
+			return v
+		}
+	}
 	if n.coeffs != nil {
 		if c, ok := n.coeffs.ByName[constituent]; ok {
 			return c.V0
@@ -102,6 +152,17 @@ type nonlinearCoeff struct {
 	term2Cos   map[int]float64 // b_k for cos(kN)
 }
 
+// eval returns f and u (degrees) at lunar node longitude N (degrees).
+// term1 = sum a_k sin(kN), term2 = b0 + sum b_k cos(kN).
+func (c nonlinearCoeff) eval(nDeg float64) (f, u float64) {
+	nRad := Deg2Rad(nDeg)
+	term1 := sumSeries(c.term1Sin, func(k float64) float64 { return math.Sin(k * nRad) })
+	term2 := c.term2Const + sumSeries(c.term2Cos, func(k float64) float64 { return math.Cos(k * nRad) })
+	f = math.Sqrt(term1*term1 + term2*term2)
+	u = Rad2Deg(math.Atan2(term1, term2))
+	return f, u
+}
+
 // Shared coefficients for constituents with identical sin/cos terms.
 //
 //nolint:gochecknoglobals // Intentional: Read-only constant maps for nodal corrections.
@@ -134,6 +195,10 @@ var builtInNonlinearCoeffs = map[string]nonlinearCoeff{
 	"P1": {term1Sin: p1SinCosCoeffs, term2Const: 1.0, term2Cos: p1SinCosCoeffs},
 	// Q1: Lunar elliptical diurnal
 	"Q1": {term1Sin: q1SinCosCoeffs, term2Const: 1.0, term2Cos: q1SinCosCoeffs},
+	// J1: Smaller lunar elliptic diurnal
+	"J1": {term1Sin: map[int]float64{1: -0.227}, term2Const: 1.0, term2Cos: map[int]float64{1: 0.169}},
+	// OO1: Lunar diurnal, second order
+	"OO1": {term1Sin: map[int]float64{1: -0.640, 2: -0.134}, term2Const: 1.0, term2Cos: map[int]float64{1: 0.640, 2: 0.134}},
 }
 
 // AstronomicalArguments holds the fundamental astronomical arguments.
@@ -146,7 +211,16 @@ type AstronomicalArguments struct {
 	xi float64 // Nutation factor.
 }
 
-// calculateAstronomicalArguments computes astronomical arguments at time t (hours since epoch).
+// unixHours converts t, in hours since the reference epoch, to hours since
+// the Unix epoch.
+func (n *AstronomicalNodalCorrection) unixHours(t float64) float64 {
+	if n.reference == nil {
+		return t
+	}
+	return float64(n.reference.Unix())/3600 + t
+}
+
+// calculateAstronomicalArguments computes astronomical arguments at time t (hours since the Unix epoch).
 // Based on Schureman (1958) formulas.
 func (n *AstronomicalNodalCorrection) calculateAstronomicalArguments(t float64) AstronomicalArguments {
 	// Convert hours to days since epoch (J2000.0 = 2000-01-01 12:00:00 UTC).
@@ -288,3 +362,45 @@ func (n *AstronomicalNodalCorrection) getQ1Factors(args AstronomicalArguments) (
 
 	return f, u
 }
+
+// overtideFactors returns nodal factors for an overtide of M2 whose speed
+// is order times that of M2: f and u scale with the order.
+func (n *AstronomicalNodalCorrection) overtideFactors(order, t float64) (f, u float64) {
+	fM2, uM2 := n.GetFactors("M2", t)
+	return math.Pow(fM2, order), order * uM2
+}
+
+// getL2Factors returns nodal factors for L2 (smaller lunar elliptic
+// semidiurnal), which also depend on the lunar perigee (pyTMD).
+func (n *AstronomicalNodalCorrection) getL2Factors(args AstronomicalArguments) (f, u float64) {
+	nRad, pRad := Deg2Rad(args.N), Deg2Rad(args.p)
+	term1 := -0.25*math.Sin(2*pRad) - 0.11*math.Sin(2*pRad-nRad) - 0.04*math.Sin(nRad)
+	term2 := 1.0 - 0.25*math.Cos(2*pRad) - 0.11*math.Cos(2*pRad-nRad) - 0.04*math.Cos(nRad)
+	return math.Sqrt(term1*term1 + term2*term2), Rad2Deg(math.Atan2(term1, term2))
+}
+
+// getM1Factors returns nodal factors for M1 (smaller lunar elliptic
+// diurnal), which also depend on the lunar perigee (pyTMD).
+func (n *AstronomicalNodalCorrection) getM1Factors(args AstronomicalArguments) (f, u float64) {
+	nRad, pRad := Deg2Rad(args.N), Deg2Rad(args.p)
+	term1 := -0.2294*math.Sin(nRad) - 0.3594*math.Sin(2*pRad) - 0.0664*math.Sin(2*pRad-nRad)
+	term2 := 1.0 + 0.1722*math.Cos(nRad) + 0.3594*math.Cos(2*pRad) + 0.0664*math.Cos(2*pRad-nRad)
+	return math.Sqrt(term1*term1 + term2*term2), Rad2Deg(math.Atan2(term1, term2))
+}
+
+// getMfFactors returns nodal factors shared by Mf-like long-period lunar
+// constituents (Mtm, MSqm).
+func (n *AstronomicalNodalCorrection) getMfFactors(args AstronomicalArguments) (f, u float64) {
+	// Schureman Table 14 (formula 227).
+	nRad := Deg2Rad(args.N)
+	f = 1.043 + 0.414*math.Cos(nRad)
+	u = -23.7*math.Sin(nRad) + 2.7*math.Sin(2*nRad) - 0.4*math.Sin(3*nRad) // Degrees.
+	return f, u
+}
+
+// getMSfFactors returns nodal factors for MSf (lunisolar synodic fortnightly).
+// MSf = S2 - M2, so it takes f of M2 and the negated u of M2.
+func (n *AstronomicalNodalCorrection) getMSfFactors(args AstronomicalArguments) (f, u float64) {
+	fM2, uM2 := builtInNonlinearCoeffs["M2"].eval(args.N)
+	return fM2, -uM2
+}
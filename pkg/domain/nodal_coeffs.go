@@ -1,15 +1,21 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strconv"
+	"sync"
 )
 
 // NodalCoeff holds Fourier series coefficients in N (degrees) for f and u,
-// and an optional constant V0 (degrees) for the equilibrium argument.
+// and an optional constant V0 (degrees) for the equilibrium argument of
+// constituents without built-in Doodson numbers.
 // f(N) = F0 + sum_k FCos[k]*cos(kN) + sum_k FSin[k]*sin(kN).
 // u(N) = U0 + sum_k UCos[k]*cos(kN) + sum_k USin[k]*sin(kN).
 type NodalCoeff struct {
@@ -29,14 +35,8 @@ type NodalCoeff struct {
 // EvalF evaluates the nodal amplitude factor f at a given lunar node angle N (degrees).
 func (c *NodalCoeff) EvalF(ndeg float64) float64 {
 	f := c.F0
-	for k, a := range c.FCos {
-		ki, _ := strconv.Atoi(k)
-		f += a * mathCos(Deg2Rad(float64(ki)*ndeg))
-	}
-	for k, b := range c.FSin {
-		ki, _ := strconv.Atoi(k)
-		f += b * mathSin(Deg2Rad(float64(ki)*ndeg))
-	}
+	f += sumSeries(c.FCos, func(k float64) float64 { return mathCos(Deg2Rad(k * ndeg)) })
+	f += sumSeries(c.FSin, func(k float64) float64 { return mathSin(Deg2Rad(k * ndeg)) })
 	if f == 0 {
 		f = 1
 	}
@@ -46,14 +46,8 @@ func (c *NodalCoeff) EvalF(ndeg float64) float64 {
 // EvalU evaluates the nodal phase correction u at a given lunar node angle N (degrees).
 func (c *NodalCoeff) EvalU(ndeg float64) float64 {
 	u := c.U0
-	for k, a := range c.UCos {
-		ki, _ := strconv.Atoi(k)
-		u += a * mathCos(Deg2Rad(float64(ki)*ndeg))
-	}
-	for k, b := range c.USin {
-		ki, _ := strconv.Atoi(k)
-		u += b * mathSin(Deg2Rad(float64(ki)*ndeg))
-	}
+	u += sumSeries(c.UCos, func(k float64) float64 { return mathCos(Deg2Rad(k * ndeg)) })
+	u += sumSeries(c.USin, func(k float64) float64 { return mathSin(Deg2Rad(k * ndeg)) })
 	return u
 }
 
@@ -63,21 +57,42 @@ func (c *NodalCoeff) EvalNonlinear(ndeg float64) (float64, float64, bool) {
 		return 0, 0, false
 	}
 	nrad := Deg2Rad(ndeg)
-	term1 := 0.0
-	for k, coeff := range c.Nonlinear.Term1Sin {
-		ki, _ := strconv.Atoi(k)
-		term1 += coeff * mathSin(float64(ki)*nrad)
-	}
-	term2 := c.Nonlinear.Term2Const
-	for k, coeff := range c.Nonlinear.Term2Cos {
-		ki, _ := strconv.Atoi(k)
-		term2 += coeff * mathCos(float64(ki)*nrad)
-	}
+	term1 := sumSeries(c.Nonlinear.Term1Sin, func(k float64) float64 { return mathSin(k * nrad) })
+	term2 := c.Nonlinear.Term2Const +
+		sumSeries(c.Nonlinear.Term2Cos, func(k float64) float64 { return mathCos(k * nrad) })
 	f := math.Sqrt(term1*term1 + term2*term2)
 	u := Rad2Deg(math.Atan2(term1, term2))
 	return f, u, true
 }
 
+// sumSeries returns the sum of coeff*fn(k) over the terms of a series keyed
+// by harmonic number k. Terms are summed in ascending k so results are
+// reproducible bit for bit, whatever the map iteration order.
+func sumSeries[K string | int](terms map[K]float64, fn func(k float64) float64) float64 {
+	type term struct{ k, coeff float64 }
+	sorted := make([]term, 0, len(terms))
+	for k, coeff := range terms {
+		sorted = append(sorted, term{harmonicNumber(k), coeff})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].k < sorted[j].k })
+	sum := 0.0
+	for _, t := range sorted {
+		sum += t.coeff * fn(t.k)
+	}
+	return sum
+}
+
+func harmonicNumber[K string | int](k K) float64 {
+	switch k := any(k).(type) {
+	case int:
+		return float64(k)
+	case string:
+		n, _ := strconv.Atoi(k)
+		return float64(n)
+	}
+	return 0
+}
+
 // NonlinearSpec specifies nonlinear nodal correction coefficients.
 type NonlinearSpec struct {
 	Term1Sin   map[string]float64 `json:"term1_sin,omitempty"`
@@ -87,8 +102,10 @@ type NonlinearSpec struct {
 
 // NodalCoeffSet contains a set of nodal coefficients for multiple constituents.
 type NodalCoeffSet struct {
-	Coeffs []NodalCoeff          `json:"coeffs"`
-	ByName map[string]NodalCoeff `json:"-"`
+	Version string                `json:"version"`
+	Coeffs  []NodalCoeff          `json:"coeffs"`
+	ByName  map[string]NodalCoeff `json:"-"`
+	Digest  string                `json:"-"` // SHA-256 of the source file (hex).
 }
 
 // LoadNodalCoeffSet loads nodal coefficients from a JSON file.
@@ -102,6 +119,8 @@ func LoadNodalCoeffSet(path string) (*NodalCoeffSet, error) {
 	if err := json.Unmarshal(b, &set); err != nil {
 		return nil, fmt.Errorf("invalid nodal coeff json: %w", err)
 	}
+	sum := sha256.Sum256(b)
+	set.Digest = hex.EncodeToString(sum[:])
 	set.ByName = make(map[string]NodalCoeff)
 	for _, c := range set.Coeffs {
 		set.ByName[c.Name] = c
@@ -109,19 +128,43 @@ func LoadNodalCoeffSet(path string) (*NodalCoeffSet, error) {
 	return &set, nil
 }
 
+// NodalCoeffsPath returns the nodal coefficient file: ASTRO_COEFFS_PATH,
+// else data/astro_coeffs.json.
+func NodalCoeffsPath() string {
+	if path := os.Getenv("ASTRO_COEFFS_PATH"); path != "" {
+		return path
+	}
+	return "data/astro_coeffs.json"
+}
+
 // LoadNodalCoeffSetFromEnv loads nodal coefficients from the path specified in ASTRO_COEFFS_PATH env var.
 func LoadNodalCoeffSetFromEnv() (*NodalCoeffSet, error) {
-	path := os.Getenv("ASTRO_COEFFS_PATH")
-	if path == "" {
-		// Try default path
-		path = "data/astro_coeffs.json"
-	}
+	path := NodalCoeffsPath()
 	if _, err := os.Stat(path); err != nil {
 		return nil, err
 	}
 	return LoadNodalCoeffSet(path)
 }
 
+// defaultNodalCoeffs loads the nodal coefficient file once, on first use:
+// every prediction creates a nodal correction.
+//
+//nolint:gochecknoglobals // Intentional: The file is read once per process.
+var defaultNodalCoeffs = sync.OnceValues(func() (*NodalCoeffSet, error) {
+	set, err := LoadNodalCoeffSetFromEnv()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return set, err
+})
+
+// DefaultNodalCoeffSet returns the coefficients of NodalCoeffsPath, loaded
+// once. Both are nil when the file does not exist; on an error, nodal
+// corrections use the built-in coefficients.
+func DefaultNodalCoeffSet() (*NodalCoeffSet, error) {
+	return defaultNodalCoeffs()
+}
+
 // Local wrappers to avoid importing math here repeatedly.
 func mathCos(x float64) float64 { return math.Cos(x) }
 func mathSin(x float64) float64 { return math.Sin(x) }
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestNodalFactors_PublishedValues checks f and u, at times given relative
+// to a 2012 reference epoch as predictions pass them, against Schureman
+// (1958) Table 14 at the lunar node longitudes N = 0°, 90° and 180°.
+func TestNodalFactors_PublishedValues(t *testing.T) {
+	reference := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		date  time.Time
+		name  string
+		f, u  float64
+		fTol  float64
+		uTol  float64
+		label string
+	}{
+		{time.Date(2006, 6, 19, 0, 0, 0, 0, time.UTC), "M2", 0.963, 0, 0.002, 0.2, "N=0°"},
+		{time.Date(2006, 6, 19, 0, 0, 0, 0, time.UTC), "K1", 1.113, 0, 0.002, 0.2, "N=0°"},
+		{time.Date(2006, 6, 19, 0, 0, 0, 0, time.UTC), "O1", 1.183, 0, 0.002, 0.2, "N=0°"},
+		{time.Date(2001, 10, 30, 0, 0, 0, 0, time.UTC), "M2", 1.000, -2.1, 0.002, 0.2, "N=90°"},
+		{time.Date(2001, 10, 30, 0, 0, 0, 0, time.UTC), "K1", 1.016, -8.9, 0.002, 0.2, "N=90°"},
+		{time.Date(2001, 10, 30, 0, 0, 0, 0, time.UTC), "O1", 1.025, 10.7, 0.002, 0.2, "N=90°"},
+		{time.Date(1997, 2, 28, 0, 0, 0, 0, time.UTC), "M2", 1.037, 0, 0.002, 0.2, "N=180°"},
+		{time.Date(1997, 2, 28, 0, 0, 0, 0, time.UTC), "K1", 0.882, 0, 0.002, 0.2, "N=180°"},
+		{time.Date(1997, 2, 28, 0, 0, 0, 0, time.UTC), "O1", 0.806, 0, 0.002, 0.2, "N=180°"},
+	}
+	nodal := NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(reference)
+	for _, tt := range tests {
+		f, u := nodal.GetFactors(tt.name, tt.date.Sub(reference).Hours())
+		if math.Abs(f-tt.f) > tt.fTol || math.Abs(u-tt.u) > tt.uTol {
+			t.Errorf("%s %s (%s): f = %.4f, u = %.2f°; want %.3f, %.1f°", tt.name, tt.date.Format("2006-01-02"), tt.label, f, u, tt.f, tt.u)
+		}
+	}
+}
+
+// TestNodalFactors_ReferenceEpoch checks that times are taken relative to
+// the reference epoch, or to the Unix epoch when none is set.
+func TestNodalFactors_ReferenceEpoch(t *testing.T) {
+	at := time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)
+	reference := time.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	unix := NewAstronomicalNodalCorrection()
+	nodal := NewAstronomicalNodalCorrection()
+	nodal.SetReferenceTime(reference)
+	for _, name := range []string{"M2", "K1", "O1", "MKS2"} {
+		fUnix, uUnix := unix.GetFactors(name, float64(at.Unix())/3600)
+		f, u := nodal.GetFactors(name, at.Sub(reference).Hours())
+		if math.Abs(f-fUnix) > 1e-9 || math.Abs(u-uUnix) > 1e-9 {
+			t.Errorf("%s: f, u = %v, %v relative to the reference, %v, %v from the Unix epoch", name, f, u, fUnix, uUnix)
+		}
+	}
+}
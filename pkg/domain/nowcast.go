@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// NowcastConfig tunes the short-horizon correction of predictions toward
+// recently observed residuals (e.g., during a storm surge).
+//
+// The residual is modeled as a first-order autoregressive process decaying
+// toward zero with TimeConstant; a scalar Kalman filter smooths the observed
+// residuals and the resulting estimate is added to predictions up to Horizon
+// after the latest observation, tapered to zero at the horizon.
+type NowcastConfig struct {
+	TimeConstant      time.Duration // E-folding time of the residual.
+	Horizon           time.Duration // Corrections end this long after the latest observation.
+	ProcessNoiseM2    float64       // Residual variance added per hour (m²/h).
+	ObservationNoiseM float64       // Observation error standard deviation (m).
+}
+
+// DefaultNowcastConfig returns a 12-hour nowcast with a 6-hour residual
+// memory and 2 cm observation noise.
+func DefaultNowcastConfig() NowcastConfig {
+	return NowcastConfig{
+		TimeConstant:      6 * time.Hour,
+		Horizon:           12 * time.Hour,
+		ProcessNoiseM2:    0.0025,
+		ObservationNoiseM: 0.02,
+	}
+}
+
+// ResidualEstimate is the filtered residual at the latest observation.
+type ResidualEstimate struct {
+	Time      time.Time
+	ResidualM float64
+	StdDevM   float64
+}
+
+// Filter runs the Kalman filter over residuals in time order. ok is false
+// when there are no residuals.
+func (c NowcastConfig) Filter(residuals []TideLevel) (est ResidualEstimate, ok bool) {
+	if len(residuals) == 0 {
+		return est, false
+	}
+	r := c.ObservationNoiseM * c.ObservationNoiseM
+	x, p := residuals[0].HeightM, r
+	last := residuals[0].Time
+	for _, obs := range residuals[1:] {
+		dt := obs.Time.Sub(last).Hours()
+		if dt <= 0 {
+			continue
+		}
+		phi := c.decay(obs.Time.Sub(last))
+		x *= phi
+		p = phi*phi*p + c.ProcessNoiseM2*dt
+		k := p / (p + r)
+		x += k * (obs.HeightM - x)
+		p *= 1 - k
+		last = obs.Time
+	}
+	return ResidualEstimate{Time: last, ResidualM: x, StdDevM: math.Sqrt(p)}, true
+}
+
+// Correction returns the residual correction to add to the prediction at t.
+// Times before the estimate or beyond the horizon are not corrected.
+func (c NowcastConfig) Correction(est ResidualEstimate, t time.Time) float64 {
+	dt := t.Sub(est.Time)
+	if dt < 0 || dt >= c.Horizon {
+		return 0
+	}
+	taper := 1 - float64(dt)/float64(c.Horizon)
+	return est.ResidualM * c.decay(dt) * taper
+}
+
+func (c NowcastConfig) decay(dt time.Duration) float64 {
+	if c.TimeConstant <= 0 {
+		return 1
+	}
+	return math.Exp(-dt.Hours() / c.TimeConstant.Hours())
+}
+
+// NowcastModel adds a residual nowcast to a base model.
+type NowcastModel struct {
+	Base     PredictionModel
+	Config   NowcastConfig
+	Estimate ResidualEstimate
+}
+
+// Name returns the base model name; the nowcast is reported separately.
+func (m NowcastModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the base height at t plus the residual correction.
+func (m NowcastModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	return m.Base.HeightAt(t, params) + m.Config.Correction(m.Estimate, t)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestNowcastFilter_TracksSurge tests that the filter follows a persistent
+// residual while smoothing observation noise.
+func TestNowcastFilter_TracksSurge(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	residuals := make([]TideLevel, 0, 12)
+	for i := range 12 {
+		noise := 0.02 * float64(i%2*2-1)
+		residuals = append(residuals, TideLevel{Time: start.Add(time.Duration(i) * time.Hour), HeightM: 0.5 + noise})
+	}
+
+	est, ok := DefaultNowcastConfig().Filter(residuals)
+	if !ok {
+		t.Fatal("expected an estimate")
+	}
+	if !est.Time.Equal(residuals[11].Time) {
+		t.Errorf("expected estimate at latest observation, got %s", est.Time)
+	}
+	if math.Abs(est.ResidualM-0.5) > 0.05 {
+		t.Errorf("expected residual near 0.5 m, got %.3f", est.ResidualM)
+	}
+	if est.StdDevM <= 0 || est.StdDevM > 0.02 {
+		t.Errorf("expected standard deviation below observation noise, got %.4f", est.StdDevM)
+	}
+
+	if _, ok := DefaultNowcastConfig().Filter(nil); ok {
+		t.Error("expected no estimate without residuals")
+	}
+}
+
+// TestNowcastCorrection_DecaysToHorizon tests the correction window.
+func TestNowcastCorrection_DecaysToHorizon(t *testing.T) {
+	config := DefaultNowcastConfig()
+	est := ResidualEstimate{Time: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), ResidualM: 0.4}
+
+	if c := config.Correction(est, est.Time.Add(-time.Hour)); c != 0 {
+		t.Errorf("expected no correction before the observation, got %g", c)
+	}
+	if c := config.Correction(est, est.Time); c != 0.4 {
+		t.Errorf("expected full correction at the observation, got %g", c)
+	}
+	want := 0.4 * math.Exp(-1) * 0.5
+	if c := config.Correction(est, est.Time.Add(6*time.Hour)); math.Abs(c-want) > 1e-12 {
+		t.Errorf("expected %g after 6h, got %g", want, c)
+	}
+	if c := config.Correction(est, est.Time.Add(config.Horizon)); c != 0 {
+		t.Errorf("expected no correction at the horizon, got %g", c)
+	}
+
+	model := NowcastModel{Base: HarmonicModel{}, Config: config, Estimate: est}
+	params := PredictionParams{
+		Constituents:    []ConstituentParam{{Name: "M2", AmplitudeM: 1, SpeedDegPerHr: 28.9841042}},
+		NodalCorrection: NewAstronomicalNodalCorrection(),
+		ReferenceTime:   time.Unix(0, 0).UTC(),
+		Model:           model,
+	}
+	at := est.Time.Add(time.Hour)
+	if got, want := params.Height(at), CalculateTideHeight(at, params)+config.Correction(est, at); got != want {
+		t.Errorf("expected corrected height %g, got %g", want, got)
+	}
+	if model.Name() != "harmonic_v0" {
+		t.Errorf("expected base model name, got %q", model.Name())
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ParamPeriod is the mean sea level and constituents in force from Start
+// until the next period starts.
+type ParamPeriod struct {
+	Start        time.Time
+	MSL          float64
+	Constituents []ConstituentParam // Nil keeps the constituents of the params.
+}
+
+// PeriodModel predicts with parameters that change at given times, e.g.
+// a gauge datum offset that applies only after an earthquake shifted the
+// land. Before the first period starts, the params passed in apply.
+type PeriodModel struct {
+	Base    PredictionModel
+	Periods []ParamPeriod // Ascending by Start.
+}
+
+// Name returns the base model name.
+func (m PeriodModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the base height at t with the parameters of the period
+// in force.
+func (m PeriodModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	if p, ok := m.At(t); ok {
+		params.MSL = p.MSL
+		if p.Constituents != nil {
+			params.Constituents = p.Constituents
+		}
+	}
+	return m.Base.HeightAt(t, params)
+}
+
+// At returns the period in force at t, if one has started.
+func (m PeriodModel) At(t time.Time) (ParamPeriod, bool) {
+	i := sort.Search(len(m.Periods), func(i int) bool { return m.Periods[i].Start.After(t) })
+	if i == 0 {
+		return ParamPeriod{}, false
+	}
+	return m.Periods[i-1], true
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestPeriodModel_SwitchesParams tests that each period's MSL and
+// constituents apply from its start, and the params before the first.
+func TestPeriodModel_SwitchesParams(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	quake := start.AddDate(0, 0, 1)
+	params := PredictionParams{MSL: 1}
+	m := PeriodModel{Base: HarmonicModel{}, Periods: []ParamPeriod{
+		{Start: quake, MSL: 1.3},
+		{Start: quake.AddDate(0, 0, 1), MSL: 1, Constituents: []ConstituentParam{{Name: "Z0", AmplitudeM: 0.2, PhaseDeg: 0}}},
+	}}
+
+	for _, tc := range []struct {
+		t    time.Time
+		want float64
+	}{
+		{start, 1},
+		{quake.Add(-time.Second), 1},
+		{quake, 1.3},
+		{quake.AddDate(0, 0, 1), 1.2},
+	} {
+		if got := m.HeightAt(tc.t, params); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("HeightAt(%s) = %v, want %v", tc.t.Format(time.RFC3339), got, tc.want)
+		}
+	}
+}
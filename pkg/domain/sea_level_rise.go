@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// SeaLevelRiseModel shifts a base model's heights by a sea level rise
+// scenario, for what-if analyses rather than predictions.
+type SeaLevelRiseModel struct {
+	Base  PredictionModel
+	RiseM float64
+}
+
+// Name returns the base model name; the scenario is reported separately.
+func (m SeaLevelRiseModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the base height at t raised by the scenario.
+func (m SeaLevelRiseModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	return m.Base.HeightAt(t, params) + m.RiseM
+}
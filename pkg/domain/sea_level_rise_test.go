@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestSeaLevelRiseModel_ShiftsHeights tests that a scenario raises every
+// height by the same amount, keeping the tidal signal.
+func TestSeaLevelRiseModel_ShiftsHeights(t *testing.T) {
+	params := PredictionParams{
+		Constituents:    []ConstituentParam{{Name: "M2", AmplitudeM: 1, PhaseDeg: 0, SpeedDegPerHr: 28.9841042}},
+		NodalCorrection: &IdentityNodalCorrection{},
+	}
+	m := SeaLevelRiseModel{Base: HarmonicModel{}, RiseM: 0.3}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 12 {
+		at := start.Add(time.Duration(i) * time.Hour)
+		if got, want := m.HeightAt(at, params), (HarmonicModel{}).HeightAt(at, params)+0.3; math.Abs(got-want) > 1e-12 {
+			t.Errorf("HeightAt(%s) = %v, want %v", at.Format(time.RFC3339), got, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// SecondaryPort defines a port by differences from the high and low waters
+// of a reference station, the classic form of published data for many
+// small harbors. Corrected heights are ratio × reference height + diff.
+type SecondaryPort struct {
+	Reference string // Station ID of the reference station.
+
+	HighWaterTimeOffset time.Duration
+	LowWaterTimeOffset  time.Duration
+	HighWaterRatio      float64
+	LowWaterRatio       float64
+	HighWaterDiffM      float64
+	LowWaterDiffM       float64
+}
+
+// secondaryPortPad extends the reference extrema beyond the prediction
+// span so every predicted time lies between two corrected turning points.
+const secondaryPortPad = 26 * time.Hour
+
+// Correct applies the time and height differences to reference extrema.
+func (s SecondaryPort) Correct(ref Extrema) Extrema {
+	correct := func(levels []TideLevel, offset time.Duration, ratio, diff float64) []TideLevel {
+		out := make([]TideLevel, len(levels))
+		for i, l := range levels {
+			out[i] = TideLevel{Time: l.Time.Add(offset), HeightM: ratio*l.HeightM + diff}
+		}
+		return out
+	}
+	return Extrema{
+		Highs: correct(ref.Highs, s.HighWaterTimeOffset, s.HighWaterRatio, s.HighWaterDiffM),
+		Lows:  correct(ref.Lows, s.LowWaterTimeOffset, s.LowWaterRatio, s.LowWaterDiffM),
+	}
+}
+
+// SecondaryPortModel predicts a secondary port by cosine interpolation
+// between its corrected high and low waters.
+type SecondaryPortModel struct {
+	Base PredictionModel // Model of the reference station.
+	Port SecondaryPort
+	// Turning holds the corrected high and low waters in time order.
+	Turning []TideLevel
+}
+
+// NewSecondaryPortModel corrects the extrema of the reference station,
+// predicted with ref, around [start, end].
+func NewSecondaryPortModel(port SecondaryPort, ref PredictionParams, start, end time.Time) SecondaryPortModel {
+	pad := secondaryPortPad + max(port.HighWaterTimeOffset.Abs(), port.LowWaterTimeOffset.Abs())
+	series := GeneratePredictions(start.Add(-pad), end.Add(pad), time.Minute, ref)
+	corrected := port.Correct(RefineExtrema(series, FindExtrema(series)))
+
+	turning := append(append([]TideLevel{}, corrected.Highs...), corrected.Lows...)
+	sort.Slice(turning, func(i, j int) bool { return turning[i].Time.Before(turning[j].Time) })
+
+	base := ref.Model
+	if base == nil {
+		base = HarmonicModel{}
+	}
+	return SecondaryPortModel{Base: base, Port: port, Turning: turning}
+}
+
+// Name returns the reference model name; the port is reported separately.
+func (m SecondaryPortModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the interpolated height at t plus params.MSL. Outside
+// the corrected turning points the nearest one is held.
+func (m SecondaryPortModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	n := len(m.Turning)
+	if n == 0 {
+		return params.MSL
+	}
+	i := sort.Search(n, func(i int) bool { return m.Turning[i].Time.After(t) })
+	switch i {
+	case 0:
+		return m.Turning[0].HeightM + params.MSL
+	case n:
+		return m.Turning[n-1].HeightM + params.MSL
+	}
+	a, b := m.Turning[i-1], m.Turning[i]
+	frac := t.Sub(a.Time).Seconds() / b.Time.Sub(a.Time).Seconds()
+	h := (a.HeightM+b.HeightM)/2 + (a.HeightM-b.HeightM)/2*math.Cos(math.Pi*frac)
+	return h + params.MSL
+}
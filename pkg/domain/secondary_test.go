@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSecondaryPortModel_CorrectsExtrema(t *testing.T) {
+	refTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ref := PredictionParams{
+		Constituents:    []ConstituentParam{{Name: "M2", AmplitudeM: 1.0, SpeedDegPerHr: 28.9841042}},
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   refTime,
+	}
+	port := SecondaryPort{
+		Reference:           "ref",
+		HighWaterTimeOffset: 30 * time.Minute,
+		LowWaterTimeOffset:  45 * time.Minute,
+		HighWaterRatio:      0.8,
+		LowWaterRatio:       0.9,
+		HighWaterDiffM:      0.1,
+		LowWaterDiffM:       -0.05,
+	}
+	start, end := refTime.Add(24*time.Hour), refTime.Add(48*time.Hour)
+	model := NewSecondaryPortModel(port, ref, start, end)
+	params := PredictionParams{MSL: 0.5, Model: model}
+
+	// Reference high and low waters of cos(ωt) are +1 at ωt = 0 and -1 at
+	// ωt = 180°; the port's follow by the offsets with corrected heights.
+	period := time.Duration(math.Round(360 / 28.9841042 * float64(time.Hour)))
+	hw := refTime.Add(2 * period).Add(port.HighWaterTimeOffset)
+	lw := refTime.Add(2*period + period/2).Add(port.LowWaterTimeOffset)
+	if got, want := params.Height(hw), 0.8*1+0.1+0.5; math.Abs(got-want) > 1e-6 {
+		t.Errorf("height at corrected high water = %v, want %v", got, want)
+	}
+	if got, want := params.Height(lw), 0.9*-1-0.05+0.5; math.Abs(got-want) > 1e-6 {
+		t.Errorf("height at corrected low water = %v, want %v", got, want)
+	}
+
+	// Between them the curve falls monotonically through the mean.
+	mid := hw.Add(lw.Sub(hw) / 2)
+	if got, want := params.Height(mid), (0.9+(-0.95))/2+0.5; math.Abs(got-want) > 1e-6 {
+		t.Errorf("height midway = %v, want %v", got, want)
+	}
+	series := GeneratePredictions(start, end, time.Minute, params)
+	extrema := RefineExtrema(series, FindExtrema(series))
+	if len(extrema.Highs) < 1 || len(extrema.Lows) < 1 {
+		t.Fatalf("no extrema in interpolated series: %+v", extrema)
+	}
+	if d := extrema.Highs[0].Time.Sub(hw).Abs(); d > time.Minute {
+		t.Errorf("first high water at %s, want %s", extrema.Highs[0].Time, hw)
+	}
+}
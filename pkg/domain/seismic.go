@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// SeismicEvent is a sudden vertical land displacement within a region,
+// e.g. the coastal subsidence of an earthquake, as surveyed after it.
+type SeismicEvent struct {
+	Name          string
+	Time          time.Time
+	Region        []Position // Polygon ring, closed implicitly.
+	DisplacementM float64    // Vertical displacement, positive for uplift.
+	Source        string     // Survey the displacement is taken from.
+}
+
+// Contains reports whether the event's region contains (lat, lon).
+func (e SeismicEvent) Contains(lat, lon float64) bool {
+	// Ray casting along the latitude; regions do not cross the antimeridian.
+	inside := false
+	n := len(e.Region)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := e.Region[i], e.Region[j]
+		if (a.Lat > lat) != (b.Lat > lat) && lon < (b.Lon-a.Lon)*(lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// OffsetM returns the change of relative sea level at t caused by the
+// event: from the event on, positive (higher water) where land subsided.
+func (e SeismicEvent) OffsetM(t time.Time) float64 {
+	if t.Before(e.Time) {
+		return 0
+	}
+	return -e.DisplacementM
+}
+
+// SeismicModel predicts relative sea level: a base model's heights plus
+// the offsets of the seismic events at the location.
+type SeismicModel struct {
+	Base   PredictionModel
+	Events []SeismicEvent
+}
+
+// Name returns the base model name; events are reported separately.
+func (m SeismicModel) Name() string { return m.Base.Name() }
+
+// HeightAt returns the base height at t plus the offsets of the events
+// before t.
+func (m SeismicModel) HeightAt(t time.Time, params PredictionParams) float64 {
+	h := m.Base.HeightAt(t, params)
+	for _, e := range m.Events {
+		h += e.OffsetM(t)
+	}
+	return h
+}
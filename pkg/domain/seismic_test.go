@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestSeismicModel_SubsidenceAfterEvent tests that subsided land raises
+// heights from the event on, inside its region only.
+func TestSeismicModel_SubsidenceAfterEvent(t *testing.T) {
+	event := SeismicEvent{
+		Name:          "test",
+		Time:          time.Date(2011, 3, 11, 5, 46, 0, 0, time.UTC),
+		Region:        []Position{{Lat: 38, Lon: 141}, {Lat: 38, Lon: 142}, {Lat: 39, Lon: 142}, {Lat: 39, Lon: 141}},
+		DisplacementM: -0.8,
+	}
+	if !event.Contains(38.3, 141.5) || event.Contains(37.9, 141.5) || event.Contains(38.3, 142.1) {
+		t.Error("Contains: wrong side of the region")
+	}
+
+	m := SeismicModel{Base: HarmonicModel{}, Events: []SeismicEvent{event}}
+	params := PredictionParams{MSL: 1}
+	for _, tc := range []struct {
+		t    time.Time
+		want float64
+	}{
+		{event.Time.Add(-time.Minute), 1},
+		{event.Time, 1.8},
+		{event.Time.AddDate(10, 0, 0), 1.8},
+	} {
+		if got := m.HeightAt(tc.t, params); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("HeightAt(%s) = %v, want %v", tc.t.Format(time.RFC3339), got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// SpectrumLine is the amplitude of a series at one frequency.
+type SpectrumLine struct {
+	FrequencyCPD float64 // Cycles per day.
+	AmplitudeM   float64
+}
+
+// SpeedToCPD converts an angular speed in degrees per hour to cycles per day.
+func SpeedToCPD(speedDegPerHr float64) float64 {
+	return speedDegPerHr * 24 / 360
+}
+
+// AmplitudeAt returns the amplitude of the sinusoid of frequency freqCPD in
+// series, 2/N·|Σ (h − mean)·e^(−iωt)|. Samples need not be evenly spaced;
+// for a series spanning many periods of freqCPD this is the constituent's
+// amplitude, less leakage from constituents closer than 1/span.
+func AmplitudeAt(series []TideLevel, freqCPD float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	return amplitudeAt(series, seriesMean(series), freqCPD)
+}
+
+func amplitudeAt(series []TideLevel, mean, freqCPD float64) float64 {
+	omega := 2 * math.Pi * freqCPD / 86400 // Radians per second.
+	t0 := series[0].Time
+	var sum complex128
+	for _, l := range series {
+		sum += complex(l.HeightM-mean, 0) * cmplx.Rect(1, -omega*l.Time.Sub(t0).Seconds())
+	}
+	amp := 2 * cmplx.Abs(sum) / float64(len(series))
+	if freqCPD == 0 {
+		amp /= 2
+	}
+	return amp
+}
+
+// AmplitudeSpectrum evaluates AmplitudeAt from 0 to maxCPD every stepCPD.
+func AmplitudeSpectrum(series []TideLevel, stepCPD, maxCPD float64) []SpectrumLine {
+	if len(series) == 0 || stepCPD <= 0 {
+		return nil
+	}
+	mean := seriesMean(series)
+	n := int(math.Floor(maxCPD/stepCPD+1e-9)) + 1
+	lines := make([]SpectrumLine, n)
+	for k := range lines {
+		f := float64(k) * stepCPD
+		lines[k] = SpectrumLine{FrequencyCPD: f, AmplitudeM: amplitudeAt(series, mean, f)}
+	}
+	return lines
+}
+
+func seriesMean(series []TideLevel) float64 {
+	mean := 0.0
+	for _, l := range series {
+		mean += l.HeightM
+	}
+	return mean / float64(len(series))
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAmplitudeAt_RecoversConstituents(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := PredictionParams{
+		Constituents: []ConstituentParam{
+			{Name: "M2", AmplitudeM: 1.0, PhaseDeg: 40, SpeedDegPerHr: 28.9841042},
+			{Name: "K1", AmplitudeM: 0.3, PhaseDeg: 200, SpeedDegPerHr: 15.0410686},
+		},
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   start,
+		MSL:             1.5,
+	}
+	series := GeneratePredictions(start, start.Add(60*24*time.Hour), 30*time.Minute, params)
+
+	for _, c := range params.Constituents {
+		if got := AmplitudeAt(series, SpeedToCPD(c.SpeedDegPerHr)); math.Abs(got-c.AmplitudeM) > 0.01 {
+			t.Errorf("%s: amplitude = %v, want %v", c.Name, got, c.AmplitudeM)
+		}
+	}
+	// The mean is removed and no other frequency is present.
+	if got := AmplitudeAt(series, 0); got > 0.01 {
+		t.Errorf("mean: amplitude = %v, want 0", got)
+	}
+	if got := AmplitudeAt(series, 3.0); got > 0.01 {
+		t.Errorf("3 cpd: amplitude = %v, want 0", got)
+	}
+}
+
+func TestAmplitudeSpectrum_PeaksAtConstituent(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := PredictionParams{
+		Constituents:    []ConstituentParam{{Name: "M2", AmplitudeM: 0.5, SpeedDegPerHr: 28.9841042}},
+		NodalCorrection: &IdentityNodalCorrection{},
+		ReferenceTime:   start,
+	}
+	series := GeneratePredictions(start, start.Add(30*24*time.Hour), time.Hour, params)
+
+	lines := AmplitudeSpectrum(series, 0.01, 12)
+	if len(lines) != 1201 || lines[1200].FrequencyCPD != 12 {
+		t.Fatalf("got %d lines up to %v cpd, want 1201 up to 12", len(lines), lines[len(lines)-1].FrequencyCPD)
+	}
+	peak := lines[0]
+	for _, l := range lines {
+		if l.AmplitudeM > peak.AmplitudeM {
+			peak = l
+		}
+	}
+	if math.Abs(peak.FrequencyCPD-1.93) > 0.011 {
+		t.Errorf("peak at %v cpd, want M2 at 1.932", peak.FrequencyCPD)
+	}
+}
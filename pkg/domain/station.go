@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StationMetadata describes a station from its constituent file.
+// Optional fields are nil or empty when the file does not provide them.
+type StationMetadata struct {
+	ID           string
+	Name         string
+	Lat          *float64
+	Lon          *float64
+	DatumOffsetM *float64 // Offset added to predicted heights (e.g., MSL above chart datum).
+	Units        string   // Amplitude units in the file; amplitudes are converted to meters on load.
+	// Secondary is set for secondary ports, which have no constituents of
+	// their own.
+	Secondary *SecondaryPort
+}
+
+// amplitudeUnitScale converts amplitude units to meters.
+//
+//nolint:gochecknoglobals // Intentional: fixed unit table.
+var amplitudeUnitScale = map[string]float64{
+	"m":  1,
+	"cm": 0.01,
+	"mm": 0.001,
+	"ft": 0.3048,
+}
+
+// AmplitudeUnitScale returns the factor converting amplitudes in units
+// (m, cm, mm or ft) to meters.
+func AmplitudeUnitScale(units string) (float64, error) {
+	scale, ok := amplitudeUnitScale[strings.ToLower(strings.TrimSpace(units))]
+	if !ok {
+		return 0, fmt.Errorf("unsupported units %q (want m, cm, mm or ft)", units)
+	}
+	return scale, nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// DaylightElevationDeg is the solar elevation at sunrise and sunset,
+// allowing for refraction and the solar disc radius.
+const DaylightElevationDeg = -0.833
+
+// SolarElevationDeg returns the sun's elevation above the horizon in degrees
+// at a location, using the low-precision solar ephemeris of the Astronomical
+// Almanac (about 0.01° in declination, ample for daylight tests).
+func SolarElevationDeg(t time.Time, lat, lon float64) float64 {
+	// Days since J2000.0 (2000-01-01 12:00 UTC).
+	n := float64(t.UTC().Sub(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC))) / float64(24*time.Hour)
+
+	meanLon := normalizeDeg(280.460 + 0.9856474*n)
+	meanAnomaly := Deg2Rad(normalizeDeg(357.528 + 0.9856003*n))
+	eclipticLon := Deg2Rad(meanLon + 1.915*math.Sin(meanAnomaly) + 0.020*math.Sin(2*meanAnomaly))
+	obliquity := Deg2Rad(23.439 - 0.0000004*n)
+
+	rightAscension := math.Atan2(math.Cos(obliquity)*math.Sin(eclipticLon), math.Cos(eclipticLon))
+	declination := math.Asin(math.Sin(obliquity) * math.Sin(eclipticLon))
+
+	gmstDeg := normalizeDeg(280.46061837 + 360.98564736629*n)
+	hourAngle := Deg2Rad(gmstDeg+lon) - rightAscension
+
+	latRad := Deg2Rad(lat)
+	sinElevation := math.Sin(latRad)*math.Sin(declination) + math.Cos(latRad)*math.Cos(declination)*math.Cos(hourAngle)
+	return math.Asin(sinElevation) * 180 / math.Pi
+}
+
+// IsDaylight reports whether the sun is above the horizon at a location.
+func IsDaylight(t time.Time, lat, lon float64) bool {
+	return SolarElevationDeg(t, lat, lon) > DaylightElevationDeg
+}
+
+// normalizeDeg wraps an angle to [0, 360).
+func normalizeDeg(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// AlertLevel is the severity of a surge alert.
+type AlertLevel string
+
+const (
+	// AlertNormal indicates the residual is not rising abnormally.
+	AlertNormal AlertLevel = "normal"
+	// AlertWatch indicates the residual rate-of-rise exceeds the watch threshold.
+	AlertWatch AlertLevel = "watch"
+	// AlertWarning indicates the residual rate-of-rise exceeds the warning threshold.
+	AlertWarning AlertLevel = "warning"
+	// AlertUnknown indicates there is not enough data to evaluate.
+	AlertUnknown AlertLevel = "unknown"
+)
+
+// RateOfRiseThresholds configures surge detection on the residual
+// (observed minus predicted) slope, in meters per hour.
+type RateOfRiseThresholds struct {
+	WatchMPerHr   float64 `json:"watch_m_per_hr"`
+	WarningMPerHr float64 `json:"warning_m_per_hr"`
+}
+
+// DefaultRateOfRiseThresholds returns conservative thresholds for port safety monitoring.
+func DefaultRateOfRiseThresholds() RateOfRiseThresholds {
+	return RateOfRiseThresholds{
+		WatchMPerHr:   0.15,
+		WarningMPerHr: 0.30,
+	}
+}
+
+// Classify returns the alert level for a residual slope (m/hr).
+func (t RateOfRiseThresholds) Classify(slopeMPerHr float64) AlertLevel {
+	switch {
+	case math.IsNaN(slopeMPerHr):
+		return AlertUnknown
+	case t.WarningMPerHr > 0 && slopeMPerHr >= t.WarningMPerHr:
+		return AlertWarning
+	case t.WatchMPerHr > 0 && slopeMPerHr >= t.WatchMPerHr:
+		return AlertWatch
+	default:
+		return AlertNormal
+	}
+}
+
+// Residuals pairs observations with predictions at identical timestamps and
+// returns observed minus predicted levels. Observations without a matching
+// prediction are skipped.
+func Residuals(observed, predicted []TideLevel) []TideLevel {
+	byTime := make(map[int64]float64, len(predicted))
+	for _, p := range predicted {
+		byTime[p.Time.Unix()] = p.HeightM
+	}
+	residuals := make([]TideLevel, 0, len(observed))
+	for _, o := range observed {
+		if h, ok := byTime[o.Time.Unix()]; ok {
+			residuals = append(residuals, TideLevel{Time: o.Time, HeightM: o.HeightM - h})
+		}
+	}
+	return residuals
+}
+
+// ResidualSlope fits a least-squares line to the residuals within window
+// ending at the latest residual and returns its slope in meters per hour.
+// Returns NaN when fewer than two residuals fall within the window.
+func ResidualSlope(residuals []TideLevel, window time.Duration) float64 {
+	if len(residuals) < 2 {
+		return math.NaN()
+	}
+	latest := residuals[0].Time
+	for _, r := range residuals {
+		if r.Time.After(latest) {
+			latest = r.Time
+		}
+	}
+	cutoff := latest.Add(-window)
+
+	var n, sumX, sumY, sumXX, sumXY float64
+	for _, r := range residuals {
+		if r.Time.Before(cutoff) {
+			continue
+		}
+		x := r.Time.Sub(latest).Hours()
+		n++
+		sumX += x
+		sumY += r.HeightM
+		sumXX += x * x
+		sumXY += x * r.HeightM
+	}
+	if n < 2 {
+		return math.NaN()
+	}
+	denom := n*sumXX - sumX*sumX
+	if math.Abs(denom) < 1e-12 {
+		return math.NaN()
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestResidualSlope_LinearSurge tests slope recovery from a linear residual trend.
+func TestResidualSlope_LinearSurge(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	observed := make([]TideLevel, 0, 6)
+	predicted := make([]TideLevel, 0, 6)
+	for i := 0; i < 6; i++ {
+		ts := start.Add(time.Duration(i) * time.Hour)
+		tide := math.Sin(float64(i))
+		predicted = append(predicted, TideLevel{Time: ts, HeightM: tide})
+		// Observed = tide + datum offset + 0.2 m/hr surge.
+		observed = append(observed, TideLevel{Time: ts, HeightM: tide + 1.5 + 0.2*float64(i)})
+	}
+
+	residuals := Residuals(observed, predicted)
+	if len(residuals) != 6 {
+		t.Fatalf("expected 6 residuals, got %d", len(residuals))
+	}
+
+	slope := ResidualSlope(residuals, 3*time.Hour)
+	if math.Abs(slope-0.2) > 1e-9 {
+		t.Errorf("expected slope 0.2 m/hr, got %.10f", slope)
+	}
+
+	if level := DefaultRateOfRiseThresholds().Classify(slope); level != AlertWatch {
+		t.Errorf("expected %s, got %s", AlertWatch, level)
+	}
+}
+
+// TestResidualSlope_InsufficientData tests that too few points yield NaN and unknown level.
+func TestResidualSlope_InsufficientData(t *testing.T) {
+	residuals := []TideLevel{{Time: time.Now(), HeightM: 0.1}}
+	slope := ResidualSlope(residuals, time.Hour)
+	if !math.IsNaN(slope) {
+		t.Fatalf("expected NaN slope, got %v", slope)
+	}
+	if level := DefaultRateOfRiseThresholds().Classify(slope); level != AlertUnknown {
+		t.Errorf("expected %s, got %s", AlertUnknown, level)
+	}
+}
+
+// TestRateOfRiseThresholds_Classify tests level boundaries.
+func TestRateOfRiseThresholds_Classify(t *testing.T) {
+	th := RateOfRiseThresholds{WatchMPerHr: 0.1, WarningMPerHr: 0.3}
+	tests := []struct {
+		slope    float64
+		expected AlertLevel
+	}{
+		{-0.5, AlertNormal},
+		{0.05, AlertNormal},
+		{0.1, AlertWatch},
+		{0.29, AlertWatch},
+		{0.3, AlertWarning},
+	}
+	for _, tt := range tests {
+		if got := th.Classify(tt.slope); got != tt.expected {
+			t.Errorf("Classify(%.2f): expected %s, got %s", tt.slope, tt.expected, got)
+		}
+	}
+}
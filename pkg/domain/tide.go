@@ -14,12 +14,36 @@ type TideLevel struct {
 
 // LocationMetadata holds additional metadata about a location.
 type LocationMetadata struct {
-	MSL        float64  // Mean Sea Level in meters (relative to reference datum).
-	DepthM     *float64 // Seabed depth in meters (optional, positive value indicates depth below MSL).
-	DatumName  string   // Name of the reference datum (e.g., "EGM2008", "WGS84").
-	SourceName string   // Data source name (e.g., "GEBCO 2024", "DTU21 MSS").
+	MSL     float64      // Mean Sea Level in meters (relative to reference datum).
+	DepthM  *float64     // Seabed depth in meters (optional, positive value indicates depth below MSL; negative on land).
+	Land    bool         // Location is above sea level (DepthM is minus the land elevation).
+	Datum   DatumCode    // Reference datum of MSL.
+	Sources []SourceCode // Datasets the metadata was derived from.
+	// Degraded lists why configured datasets did not contribute (e.g., an
+	// unreadable file); empty when all configured datasets were used.
+	Degraded []string
 }
 
+// DatumCode identifies a vertical reference datum. Codes are stable API
+// values; display labels live in the i18n catalog.
+type DatumCode string
+
+// Datum codes.
+const (
+	DatumEGM2008               DatumCode = "egm2008"
+	DatumEGM2008GeoidCorrected DatumCode = "egm2008_geoid_corrected"
+)
+
+// SourceCode identifies a metadata dataset. Codes are stable API values;
+// display labels live in the i18n catalog.
+type SourceCode string
+
+// Source codes.
+const (
+	SourceGEBCO2025 SourceCode = "gebco_2025"
+	SourceDTU21MSS  SourceCode = "dtu21_mss"
+)
+
 // Extrema represents high and low tide events.
 type Extrema struct {
 	Highs []TideLevel
@@ -28,26 +52,42 @@ type Extrema struct {
 
 // PredictionParams holds all parameters needed for tide prediction.
 type PredictionParams struct {
-    Constituents    []ConstituentParam
-    MSL             float64         // Mean Sea Level offset in meters.
-    Longitude       float64         // Longitude in degrees (for Greenwich phase correction).
-    NodalCorrection NodalCorrection // Interface for nodal corrections.
-    ReferenceTime   time.Time       // Reference time for phase (usually Unix epoch or local epoch).
-    PhaseConvention PhaseConvention // Phase handling convention.
+	Constituents    []ConstituentParam
+	MSL             float64         // Mean Sea Level offset in meters.
+	Longitude       float64         // Longitude in degrees (for Greenwich phase correction).
+	NodalCorrection NodalCorrection // Interface for nodal corrections.
+	ReferenceTime   time.Time       // Reference time for phase (usually Unix epoch or local epoch).
+	PhaseConvention PhaseConvention // Phase handling convention.
+	Model           PredictionModel // Height model; nil uses the harmonic sum.
+	Position        *Position       // Prediction location when known (model features).
+}
+
+// Position is a geographic location in degrees.
+type Position struct {
+	Lat float64
+	Lon float64
+}
+
+// Height returns the model height at t.
+func (p PredictionParams) Height(t time.Time) float64 {
+	if p.Model != nil {
+		return p.Model.HeightAt(t, p)
+	}
+	return CalculateTideHeight(t, p)
 }
 
 // PhaseConvention selects the phase formula to use.
-// - PhaseConvFESGreenwich: use Greenwich phase lag with longitude correction (typical for FES)
-//   h(t) = f A cos(ωΔt - φ + λ + u) + MSL
-// - PhaseConvVu: use equilibrium argument V + nodal correction u
-//   h(t) = f A cos(ωΔt + (V + u) - φ) + MSL
+//   - PhaseConvFESGreenwich: use Greenwich phase lag with longitude correction (typical for FES)
+//     h(t) = f A cos(ωΔt - φ + λ + u) + MSL
+//   - PhaseConvVu: use equilibrium argument V + nodal correction u
+//     h(t) = f A cos(ωΔt + (V + u) - φ) + MSL
 type PhaseConvention int
 
 const (
 	// PhaseConvFESGreenwich uses Greenwich phase lag with longitude correction.
-    PhaseConvFESGreenwich PhaseConvention = iota
+	PhaseConvFESGreenwich PhaseConvention = iota
 	// PhaseConvVu uses equilibrium argument V + nodal correction u.
-    PhaseConvVu
+	PhaseConvVu
 )
 
 // CalculateTideHeight computes the tide height at a specific time using harmonic analysis
@@ -59,50 +99,63 @@ const (
 //   - φ_k is phase in degrees
 //   - Δt is hours since reference time
 func CalculateTideHeight(t time.Time, params PredictionParams) float64 {
-    if params.NodalCorrection == nil {
-        params.NodalCorrection = &IdentityNodalCorrection{}
-    }
-
-    deltaHours := t.Sub(params.ReferenceTime).Hours()
-    height := params.MSL
-
-    for _, c := range params.Constituents {
-        // Get nodal corrections.
-        f, u := params.NodalCorrection.GetFactors(c.Name, deltaHours)
-
-        // Calculate phase angle in degrees based on convention.
-        var phaseAngleDeg float64
-        switch params.PhaseConvention {
-        case PhaseConvFESGreenwich:
-            // FES Greenwich phase lag φ with geographic longitude correction.
-            // h(t) = f A cos(ωΔt - φ + λ + u)
-            phaseAngleDeg = c.SpeedDegPerHr*deltaHours - c.PhaseDeg + params.Longitude + u
-        case PhaseConvVu:
-            // Use equilibrium argument V + u (if provided by nodal correction). Avoid longitude.
-            v := params.NodalCorrection.GetEquilibriumArgument(c.Name, deltaHours)
-            phaseAngleDeg = c.SpeedDegPerHr*deltaHours + v + u - c.PhaseDeg
-        default:
-            // Use equilibrium argument V + u (if provided by nodal correction). Avoid longitude.
-            v := params.NodalCorrection.GetEquilibriumArgument(c.Name, deltaHours)
-            phaseAngleDeg = c.SpeedDegPerHr*deltaHours + v + u - c.PhaseDeg
-        }
-
-        // Convert to radians and calculate contribution.
-        phaseAngleRad := Deg2Rad(phaseAngleDeg)
-        contribution := f * c.AmplitudeM * math.Cos(phaseAngleRad)
-
-        height += contribution
-    }
-
-    return height
+	if params.NodalCorrection == nil {
+		params.NodalCorrection = &IdentityNodalCorrection{}
+	}
+
+	deltaHours := t.Sub(params.ReferenceTime).Hours()
+	height := params.MSL
+
+	for _, c := range params.Constituents {
+		f, argDeg := constituentTerm(deltaHours, params, c)
+		height += f * c.AmplitudeM * math.Cos(Deg2Rad(argDeg))
+	}
+
+	return height
+}
+
+// PhaseArgumentDeg returns the argument of constituent c at t, in degrees,
+// such that its contribution is f A cos(argument). Constituents peak when
+// the argument is a multiple of 360°.
+func PhaseArgumentDeg(t time.Time, params PredictionParams, c ConstituentParam) float64 {
+	if params.NodalCorrection == nil {
+		params.NodalCorrection = &IdentityNodalCorrection{}
+	}
+	_, argDeg := constituentTerm(t.Sub(params.ReferenceTime).Hours(), params, c)
+	return argDeg
+}
+
+// constituentTerm returns the nodal amplitude factor and argument in
+// degrees of c at deltaHours after the reference time.
+func constituentTerm(deltaHours float64, params PredictionParams, c ConstituentParam) (f, argDeg float64) {
+	// Get nodal corrections.
+	f, u := params.NodalCorrection.GetFactors(c.Name, deltaHours)
+
+	// Calculate phase angle in degrees based on convention.
+	switch params.PhaseConvention {
+	case PhaseConvFESGreenwich:
+		// FES Greenwich phase lag φ with geographic longitude correction.
+		// h(t) = f A cos(ωΔt - φ + λ + u)
+		argDeg = c.SpeedDegPerHr*deltaHours - c.PhaseDeg + params.Longitude + u
+	case PhaseConvVu:
+		// Use equilibrium argument V + u (if provided by nodal correction). Avoid longitude.
+		v := params.NodalCorrection.GetEquilibriumArgument(c.Name, deltaHours)
+		argDeg = c.SpeedDegPerHr*deltaHours + v + u - c.PhaseDeg
+	default:
+		// Use equilibrium argument V + u (if provided by nodal correction). Avoid longitude.
+		v := params.NodalCorrection.GetEquilibriumArgument(c.Name, deltaHours)
+		argDeg = c.SpeedDegPerHr*deltaHours + v + u - c.PhaseDeg
+	}
+	return f, argDeg
 }
 
-// GeneratePredictions creates a time series of tide predictions.
+// GeneratePredictions creates a time series of tide predictions with the
+// params' model.
 func GeneratePredictions(start, end time.Time, interval time.Duration, params PredictionParams) []TideLevel {
 	predictions := make([]TideLevel, 0)
 
 	for t := start; !t.After(end); t = t.Add(interval) {
-		height := CalculateTideHeight(t, params)
+		height := params.Height(t)
 		predictions = append(predictions, TideLevel{
 			Time:    t,
 			HeightM: height,
@@ -205,3 +205,23 @@ func TestDeg2Rad(t *testing.T) {
 		}
 	}
 }
+
+// TestNormalizePhaseDeg tests wrapping phases to [0, 360).
+func TestNormalizePhaseDeg(t *testing.T) {
+	tests := []struct {
+		deg      float64
+		expected float64
+	}{
+		{145, 145},
+		{360, 0},
+		{-30, 330},
+		{725, 5},
+		{-1e-15, 0},
+	}
+
+	for _, tt := range tests {
+		if result := NormalizePhaseDeg(tt.deg); math.Abs(result-tt.expected) > 1e-9 {
+			t.Errorf("NormalizePhaseDeg(%g): expected %g, got %g", tt.deg, tt.expected, result)
+		}
+	}
+}
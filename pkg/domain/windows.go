@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Window is a contiguous run of samples that satisfy a planning constraint.
+type Window struct {
+	Start     time.Time
+	End       time.Time
+	MinLevelM float64 // Lowest level within the window.
+	MaxLevelM float64 // Highest level within the window.
+}
+
+// Duration returns the length of the window.
+func (w Window) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// FindWindows returns the runs of consecutive levels for which feasible
+// holds and that last at least minDuration. levels must be sorted and
+// evenly spaced; a window spans from its first to its last feasible sample.
+func FindWindows(levels []TideLevel, feasible func(TideLevel) bool, minDuration time.Duration) []Window {
+	windows := make([]Window, 0)
+	var current *Window
+	flush := func() {
+		if current != nil && current.Duration() >= minDuration {
+			windows = append(windows, *current)
+		}
+		current = nil
+	}
+
+	for _, l := range levels {
+		if !feasible(l) {
+			flush()
+			continue
+		}
+		if current == nil {
+			current = &Window{Start: l.Time, MinLevelM: l.HeightM, MaxLevelM: l.HeightM}
+		}
+		current.End = l.Time
+		current.MinLevelM = min(current.MinLevelM, l.HeightM)
+		current.MaxLevelM = max(current.MaxLevelM, l.HeightM)
+	}
+	flush()
+	return windows
+}
+
+// RankWindows orders windows best first: longest, then the highest lowest
+// level (most margin), then earliest.
+func RankWindows(windows []Window) {
+	sort.SliceStable(windows, func(i, j int) bool {
+		a, b := windows[i], windows[j]
+		if a.Duration() != b.Duration() {
+			return a.Duration() > b.Duration()
+		}
+		if a.MinLevelM != b.MinLevelM {
+			return a.MinLevelM > b.MinLevelM
+		}
+		return a.Start.Before(b.Start)
+	})
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSolarElevationDeg(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     time.Time
+		lat, lon float64
+		want     float64
+	}{
+		// Equinox noon on the equator at Greenwich: sun nearly overhead.
+		{"equinox noon", time.Date(2025, 3, 20, 12, 7, 0, 0, time.UTC), 0, 0, 89.9},
+		// Solstice solar noon in Tokyo: 90 - 35.68 + 23.44.
+		{"tokyo solstice noon", time.Date(2025, 6, 21, 2, 43, 0, 0, time.UTC), 35.68, 139.77, 77.76},
+		// Solstice midnight in Tokyo: 35.68 + 23.44 - 90 below the horizon.
+		{"tokyo solstice midnight", time.Date(2025, 6, 21, 14, 43, 0, 0, time.UTC), 35.68, 139.77, -30.88},
+	}
+	for _, tt := range tests {
+		if got := SolarElevationDeg(tt.time, tt.lat, tt.lon); got < tt.want-0.5 || got > tt.want+0.5 {
+			t.Errorf("%s: elevation = %.2f, want %.2f", tt.name, got, tt.want)
+		}
+	}
+	if IsDaylight(time.Date(2025, 6, 21, 14, 43, 0, 0, time.UTC), 35.68, 139.77) {
+		t.Error("expected night in Tokyo at 23:43 JST")
+	}
+}
+
+func TestFindWindows(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	heights := []float64{0.1, 0.6, 0.8, 0.7, 0.2, 0.9, 0.3, 0.5, 0.6, 0.9, 0.5}
+	levels := make([]TideLevel, len(heights))
+	for i, h := range heights {
+		levels[i] = TideLevel{Time: start.Add(time.Duration(i) * time.Hour), HeightM: h}
+	}
+
+	windows := FindWindows(levels, func(l TideLevel) bool { return l.HeightM >= 0.5 }, 2*time.Hour)
+	if len(windows) != 2 {
+		t.Fatalf("expected 2 windows, got %+v", windows)
+	}
+	if !windows[0].Start.Equal(start.Add(time.Hour)) || windows[0].Duration() != 2*time.Hour || windows[0].MinLevelM != 0.6 || windows[0].MaxLevelM != 0.8 {
+		t.Errorf("unexpected first window: %+v", windows[0])
+	}
+	if !windows[1].Start.Equal(start.Add(7*time.Hour)) || windows[1].Duration() != 3*time.Hour {
+		t.Errorf("unexpected second window: %+v", windows[1])
+	}
+
+	RankWindows(windows)
+	if windows[0].Duration() != 3*time.Hour {
+		t.Errorf("expected the longest window first, got %+v", windows)
+	}
+}
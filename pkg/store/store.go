@@ -0,0 +1,23 @@
+// Package store defines the interfaces tidal constituent datasets
+// implement, so library consumers can plug in their own.
+package store
+
+import "go.ngs.io/tides-api/pkg/domain"
+
+// ConstituentLoader is the interface for loading tidal constituent parameters.
+type ConstituentLoader interface {
+	// LoadForStation loads parameters for a named station (e.g., "tokyo").
+	LoadForStation(stationID string) ([]domain.ConstituentParam, error)
+
+	// LoadForLocation loads parameters for a lat/lon location (using interpolation for FES).
+	LoadForLocation(lat, lon float64) ([]domain.ConstituentParam, error)
+}
+
+// CurrentLoader loads tidal current constituents: the harmonic parameters
+// of the eastward and northward velocity components, amplitudes in m/s.
+type CurrentLoader interface {
+	// LoadCurrentsForLocation returns both components at a location, with
+	// constituents in the same order; errors wrap domain.ErrOutOfCoverage
+	// outside the dataset.
+	LoadCurrentsForLocation(lat, lon float64) (east, north []domain.ConstituentParam, err error)
+}